@@ -79,6 +79,11 @@ func AccountAtRound(account models.Account, round uint64, db idb.IndexerDb) (acc
 	}
 
 	acct = account
+	// The resource counts are not rewound.
+	acct.TotalAppsOptedIn = nil
+	acct.TotalAssetsOptedIn = nil
+	acct.TotalCreatedApps = nil
+	acct.TotalCreatedAssets = nil
 	var addr basics.Address
 	addr, err = basics.UnmarshalChecksumAddress(account.Address)
 	if err != nil {
@@ -19,11 +19,13 @@ func TestBasic(t *testing.T) {
 	var a basics.Address
 	a[0] = 'a'
 
+	assets := uint64(1)
 	account := models.Account{
 		Address:                     a.String(),
 		Amount:                      100,
 		AmountWithoutPendingRewards: 100,
 		Round:                       8,
+		TotalAssetsOptedIn:          &assets,
 	}
 
 	txnBytes := protocol.Encode(&transactions.SignedTxnWithAD{
@@ -55,6 +57,8 @@ func TestBasic(t *testing.T) {
 	assert.NoError(t, err)
 
 	assert.Equal(t, uint64(98), account.Amount)
+	// The resource counts are not rewound.
+	assert.Nil(t, account.TotalAssetsOptedIn)
 }
 
 // Test that when idb.Transactions() returns stale data the first time, we return an error.
@@ -0,0 +1,246 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/algorand/go-algorand/data/basics"
+	"github.com/algorand/go-algorand/data/transactions"
+	"github.com/algorand/go-algorand/protocol"
+	"github.com/labstack/echo/v4"
+
+	"github.com/algorand/indexer/api/generated/v2"
+	"github.com/algorand/indexer/idb"
+)
+
+// Account activity limits, the limit is the number of transactions scanned, a
+// transaction can produce more than one activity item.
+const maxActivityLimit = 1000
+const defaultActivityLimit = 100
+
+// Activity item types.
+const (
+	activityPayment         = "payment"
+	activityAssetTransfer   = "asset-transfer"
+	activityAssetConfig     = "asset-config"
+	activityAssetFreeze     = "asset-freeze"
+	activityApplication     = "app-call"
+	activityKeyRegistration = "key-registration"
+	activityReward          = "reward"
+)
+
+var activityTypes = map[protocol.TxType]string{
+	protocol.PaymentTx:         activityPayment,
+	protocol.AssetTransferTx:   activityAssetTransfer,
+	protocol.AssetConfigTx:     activityAssetConfig,
+	protocol.AssetFreezeTx:     activityAssetFreeze,
+	protocol.ApplicationCallTx: activityApplication,
+	protocol.KeyRegistrationTx: activityKeyRegistration,
+}
+
+// Activity item directions, relative to the requested account.
+const (
+	activityIn   = "in"
+	activityOut  = "out"
+	activitySelf = "self"
+)
+
+// LookupAccountActivity returns payments, asset transfers, application calls and
+// rewards of an account as a single feed, newest first.
+// (GET /v2/accounts/{account-id}/activity)
+func (si *ServerImplementation) LookupAccountActivity(ctx echo.Context, accountID string, params generated.LookupAccountActivityParams) error {
+	addr, err := basics.UnmarshalChecksumAddress(accountID)
+	if err != nil {
+		return badRequest(ctx, errUnableToParseAddress)
+	}
+
+	searchParams := accountActivityParams(accountID, params)
+	searchParams.Next, err = si.decodeNext(ctx, searchParams.Next)
+	if err != nil {
+		return badRequest(ctx, err.Error())
+	}
+	filter, err := transactionParamsToTransactionFilter(searchParams)
+	if err != nil {
+		return badRequest(ctx, err.Error())
+	}
+
+	activity := make([]generated.ActivityItem, 0)
+	txchan, round := si.db.Transactions(ctx.Request().Context(), filter)
+	next := ""
+	for txrow := range txchan {
+		items, err := txnRowToActivity(addr, txrow)
+		if err != nil {
+			return indexerError(ctx, fmt.Sprintf("%s: %v", errTransactionSearch, err))
+		}
+		activity = append(activity, items...)
+		next = txrow.Next()
+	}
+
+	response := generated.AccountActivityResponse{
+		CurrentRound: round,
+		NextToken:    si.encodeNext(ctx, next),
+		Activity:     activity,
+	}
+	return ctx.JSON(http.StatusOK, response)
+}
+
+// accountActivityParams converts the parameters of the activity endpoint to
+// transaction search parameters.
+func accountActivityParams(accountID string, params generated.LookupAccountActivityParams) generated.SearchForTransactionsParams {
+	limit := uint64(defaultActivityLimit)
+	if params.Limit != nil {
+		limit = min(*params.Limit, maxActivityLimit)
+	}
+	return generated.SearchForTransactionsParams{
+		Address:    strPtr(accountID),
+		Limit:      &limit,
+		Next:       params.Next,
+		MinRound:   params.MinRound,
+		MaxRound:   params.MaxRound,
+		BeforeTime: params.BeforeTime,
+		AfterTime:  params.AfterTime,
+	}
+}
+
+// direction returns how a transfer between `from` and `to` relates to `addr`.
+func direction(addr, from, to basics.Address) string {
+	switch {
+	case from == addr && to == addr:
+		return activitySelf
+	case from == addr:
+		return activityOut
+	default:
+		return activityIn
+	}
+}
+
+// txnRowToActivity converts a transaction of the account `addr` to the activity
+// items it produced for that account.
+func txnRowToActivity(addr basics.Address, row idb.TxnRow) ([]generated.ActivityItem, error) {
+	if row.Error != nil {
+		return nil, row.Error
+	}
+
+	var stxn transactions.SignedTxnWithAD
+	err := protocol.Decode(row.TxnBytes, &stxn)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %s", errUnableToDecodeTransaction, err.Error())
+	}
+	txn := &stxn.Txn
+
+	base := generated.ActivityItem{
+		Type:      activityTypes[txn.Type],
+		Txid:      txn.ID().String(),
+		Round:     row.Round,
+		Intra:     uint64(row.Intra),
+		RoundTime: uint64(row.RoundTime.Unix()),
+	}
+	if txn.Sender == addr {
+		base.Fee = uint64PtrOrNil(txn.Fee.Raw)
+	}
+
+	items := make([]generated.ActivityItem, 0, 2)
+	add := func(item generated.ActivityItem) {
+		items = append(items, item)
+		// Only charge the fee once.
+		base.Fee = nil
+	}
+
+	// transfer adds the item of a transfer from `from` to `to` if it concerns `addr`.
+	transfer := func(item generated.ActivityItem, from, to basics.Address, amount uint64) {
+		if from != addr && to != addr {
+			return
+		}
+		item.Direction = strPtr(direction(addr, from, to))
+		item.Amount = amount
+		if from == addr {
+			item.Counterparty = addrPtr(to)
+		} else {
+			item.Counterparty = addrPtr(from)
+		}
+		add(item)
+	}
+
+	switch txn.Type {
+	case protocol.PaymentTx:
+		item := base
+		transfer(item, txn.Sender, txn.Receiver, txn.Amount.Raw)
+		if !txn.CloseRemainderTo.IsZero() {
+			item.Fee = base.Fee
+			transfer(item, txn.Sender, txn.CloseRemainderTo, stxn.ClosingAmount.Raw)
+		}
+	case protocol.AssetTransferTx:
+		item := base
+		item.AssetId = uint64PtrOrNil(uint64(txn.XferAsset))
+		from := txn.Sender
+		if !txn.AssetSender.IsZero() {
+			// Clawback, the assets leave the clawed back account.
+			from = txn.AssetSender
+		}
+		transfer(item, from, txn.AssetReceiver, txn.AssetAmount)
+		if !txn.AssetCloseTo.IsZero() {
+			item.Fee = base.Fee
+			transfer(item, from, txn.AssetCloseTo, row.Extra.AssetCloseAmount)
+		}
+	case protocol.ApplicationCallTx:
+		item := base
+		appID := uint64(txn.ApplicationID)
+		if appID == 0 {
+			appID = row.AssetID
+		}
+		item.ApplicationId = uint64PtrOrNil(appID)
+		if txn.Sender == addr {
+			item.Direction = strPtr(activityOut)
+		} else {
+			item.Direction = strPtr(activityIn)
+		}
+		add(item)
+	case protocol.AssetConfigTx:
+		item := base
+		assetID := uint64(txn.ConfigAsset)
+		if assetID == 0 {
+			assetID = row.AssetID
+		}
+		item.AssetId = uint64PtrOrNil(assetID)
+		add(item)
+	case protocol.AssetFreezeTx:
+		item := base
+		item.AssetId = uint64PtrOrNil(uint64(txn.FreezeAsset))
+		if txn.Sender != addr {
+			item.Direction = strPtr(activityIn)
+		}
+		add(item)
+	case protocol.KeyRegistrationTx:
+		add(base)
+	}
+
+	// Rewards paid out to the account while applying the transaction.
+	var rewards uint64
+	if txn.Sender == addr {
+		rewards += stxn.SenderRewards.Raw
+	}
+	if txn.Type == protocol.PaymentTx {
+		if txn.Receiver == addr {
+			rewards += stxn.ReceiverRewards.Raw
+		}
+		if txn.CloseRemainderTo == addr {
+			rewards += stxn.CloseRewards.Raw
+		}
+	}
+	if rewards > 0 {
+		item := base
+		item.Type = activityReward
+		item.Direction = strPtr(activityIn)
+		item.Amount = rewards
+		add(item)
+	}
+
+	// Always report fees paid by the account, e.g. for a clawback.
+	if len(items) == 0 && base.Fee != nil {
+		item := base
+		item.Direction = strPtr(activityOut)
+		items = append(items, item)
+	}
+
+	return items, nil
+}
@@ -0,0 +1,100 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/algorand/go-algorand/data/basics"
+	"github.com/algorand/go-algorand/data/transactions"
+	"github.com/algorand/go-algorand/protocol"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/algorand/indexer/idb"
+	"github.com/algorand/indexer/util/test"
+)
+
+func activityRow(stxn transactions.SignedTxnWithAD) idb.TxnRow {
+	return idb.TxnRow{
+		Round:    10,
+		Intra:    2,
+		TxnBytes: protocol.Encode(&stxn),
+	}
+}
+
+func TestActivityPayment(t *testing.T) {
+	stxn := test.MakePaymentTxn(
+		1000, 10, 0, 5, 7, 0, test.AccountA, test.AccountB, basics.Address{},
+		basics.Address{})
+
+	// Sender pays the amount and the fee, and earned rewards.
+	items, err := txnRowToActivity(test.AccountA, activityRow(stxn))
+	require.NoError(t, err)
+	require.Len(t, items, 2)
+	assert.Equal(t, activityPayment, items[0].Type)
+	assert.Equal(t, activityOut, *items[0].Direction)
+	assert.Equal(t, uint64(10), items[0].Amount)
+	assert.Equal(t, uint64(1000), *items[0].Fee)
+	assert.Equal(t, test.AccountB.String(), *items[0].Counterparty)
+	assert.Equal(t, activityReward, items[1].Type)
+	assert.Equal(t, uint64(5), items[1].Amount)
+	assert.Nil(t, items[1].Fee)
+
+	// Receiver gets the amount and its own rewards.
+	items, err = txnRowToActivity(test.AccountB, activityRow(stxn))
+	require.NoError(t, err)
+	require.Len(t, items, 2)
+	assert.Equal(t, activityIn, *items[0].Direction)
+	assert.Nil(t, items[0].Fee)
+	assert.Equal(t, test.AccountA.String(), *items[0].Counterparty)
+	assert.Equal(t, uint64(7), items[1].Amount)
+}
+
+func TestActivityPaymentClose(t *testing.T) {
+	stxn := test.MakePaymentTxn(
+		1000, 10, 300, 0, 0, 0, test.AccountA, test.AccountB, test.AccountC,
+		basics.Address{})
+
+	items, err := txnRowToActivity(test.AccountA, activityRow(stxn))
+	require.NoError(t, err)
+	require.Len(t, items, 2)
+	assert.Equal(t, uint64(1000), *items[0].Fee)
+	assert.Equal(t, uint64(300), items[1].Amount)
+	assert.Nil(t, items[1].Fee)
+	assert.Equal(t, test.AccountC.String(), *items[1].Counterparty)
+
+	items, err = txnRowToActivity(test.AccountC, activityRow(stxn))
+	require.NoError(t, err)
+	require.Len(t, items, 1)
+	assert.Equal(t, activityIn, *items[0].Direction)
+	assert.Equal(t, uint64(300), items[0].Amount)
+}
+
+func TestActivityAssetTransfer(t *testing.T) {
+	stxn := test.MakeAssetTransferTxn(5, 20, test.AccountA, test.AccountB, basics.Address{})
+
+	items, err := txnRowToActivity(test.AccountB, activityRow(stxn))
+	require.NoError(t, err)
+	require.Len(t, items, 1)
+	assert.Equal(t, activityAssetTransfer, items[0].Type)
+	assert.Equal(t, activityIn, *items[0].Direction)
+	assert.Equal(t, uint64(5), *items[0].AssetId)
+	assert.Equal(t, uint64(20), items[0].Amount)
+
+	optin := test.MakeAssetOptInTxn(5, test.AccountA)
+	items, err = txnRowToActivity(test.AccountA, activityRow(optin))
+	require.NoError(t, err)
+	require.Len(t, items, 1)
+	assert.Equal(t, activitySelf, *items[0].Direction)
+}
+
+func TestActivityApplicationCreate(t *testing.T) {
+	stxn := test.MakeCreateAppTxn(test.AccountA)
+	row := activityRow(stxn)
+	row.AssetID = 77
+
+	items, err := txnRowToActivity(test.AccountA, row)
+	require.NoError(t, err)
+	require.Len(t, items, 1)
+	assert.Equal(t, activityApplication, items[0].Type)
+	assert.Equal(t, uint64(77), *items[0].ApplicationId)
+}
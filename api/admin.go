@@ -0,0 +1,129 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/algorand/indexer/api/middlewares"
+)
+
+// AdminTokenHeader is the header used to provide an admin token.
+const AdminTokenHeader = "X-Indexer-Admin-Token"
+
+// registerAdminHandlers adds the operator endpoints under /admin. They are not part
+// of the public API specification and always require an admin token.
+func registerAdminHandlers(e *echo.Echo, si *ServerImplementation, adminTokens *middlewares.Tokens) {
+	g := e.Group("/admin", middlewares.MakeTokenAuth(AdminTokenHeader, adminTokens))
+	g.GET("/db-settings", si.getDatabaseSettings)
+	g.GET("/config", si.getConfig)
+	g.GET("/importer", si.getImporterStatus)
+	g.POST("/importer/pause", si.pauseImporter)
+	g.POST("/importer/resume", si.resumeImporter)
+	g.GET("/migrations", si.getMigrationStatus)
+	g.POST("/migrations", si.runPendingMigrations)
+	g.POST("/caches/flush", si.flushCaches)
+	g.GET("/webhooks", si.listWebhooks)
+	g.POST("/webhooks", si.addWebhook)
+	g.DELETE("/webhooks/:id", si.deleteWebhook)
+}
+
+// ImporterControl lets the admin API pause and resume the block importer.
+type ImporterControl interface {
+	Pause()
+	Resume()
+	Paused() bool
+}
+
+// ImporterStatusResponse is the response of the admin importer endpoints.
+type ImporterStatusResponse struct {
+	Paused bool `json:"paused"`
+}
+
+// getDatabaseSettings returns the database server settings discovered by the
+// indexer and its connection pool size.
+// (GET /admin/db-settings)
+func (si *ServerImplementation) getDatabaseSettings(ctx echo.Context) error {
+	settings, err := si.db.GetServerSettings(ctx.Request().Context())
+	if err != nil {
+		return indexerError(ctx, errFailedLoadServerSettings)
+	}
+	return ctx.JSON(http.StatusOK, settings)
+}
+
+// getConfig returns the configuration the daemon was started with, secrets are
+// redacted.
+// (GET /admin/config)
+func (si *ServerImplementation) getConfig(ctx echo.Context) error {
+	config := si.config
+	if config == nil {
+		config = make(map[string]string)
+	}
+	return ctx.JSON(http.StatusOK, config)
+}
+
+// getImporterStatus returns whether the block importer is paused.
+// (GET /admin/importer)
+func (si *ServerImplementation) getImporterStatus(ctx echo.Context) error {
+	if si.importer == nil {
+		return notFound(ctx, errNoImporter)
+	}
+	return ctx.JSON(http.StatusOK, ImporterStatusResponse{Paused: si.importer.Paused()})
+}
+
+// pauseImporter stops importing new blocks after the current one.
+// (POST /admin/importer/pause)
+func (si *ServerImplementation) pauseImporter(ctx echo.Context) error {
+	if si.importer == nil {
+		return notFound(ctx, errNoImporter)
+	}
+	si.importer.Pause()
+	return ctx.JSON(http.StatusOK, ImporterStatusResponse{Paused: si.importer.Paused()})
+}
+
+// resumeImporter resumes importing new blocks.
+// (POST /admin/importer/resume)
+func (si *ServerImplementation) resumeImporter(ctx echo.Context) error {
+	if si.importer == nil {
+		return notFound(ctx, errNoImporter)
+	}
+	si.importer.Resume()
+	return ctx.JSON(http.StatusOK, ImporterStatusResponse{Paused: si.importer.Paused()})
+}
+
+// getMigrationStatus returns the health status, which includes the state and
+// progress of the migrations.
+// (GET /admin/migrations)
+func (si *ServerImplementation) getMigrationStatus(ctx echo.Context) error {
+	health, err := si.db.Health()
+	if err != nil {
+		return indexerError(ctx, fmt.Sprintf("problem fetching health: %v", err))
+	}
+	return ctx.JSON(http.StatusOK, health)
+}
+
+// runPendingMigrations starts the pending non-blocking migrations and returns the
+// resulting health status.
+// (POST /admin/migrations)
+func (si *ServerImplementation) runPendingMigrations(ctx echo.Context) error {
+	err := si.db.RunPendingMigrations()
+	if err != nil {
+		return indexerError(ctx, fmt.Sprintf("%s: %v", errRunningMigrations, err))
+	}
+	health, err := si.db.Health()
+	if err != nil {
+		return indexerError(ctx, fmt.Sprintf("problem fetching health: %v", err))
+	}
+	return ctx.JSON(http.StatusOK, health)
+}
+
+// flushCaches drops the database caches.
+// (POST /admin/caches/flush)
+func (si *ServerImplementation) flushCaches(ctx echo.Context) error {
+	err := si.db.FlushCaches(ctx.Request().Context())
+	if err != nil {
+		return indexerError(ctx, fmt.Sprintf("%s: %v", errFlushingCaches, err))
+	}
+	return ctx.NoContent(http.StatusOK)
+}
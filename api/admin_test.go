@@ -0,0 +1,212 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/algorand/indexer/api/middlewares"
+	"github.com/algorand/indexer/idb"
+	"github.com/algorand/indexer/idb/mocks"
+)
+
+func TestAdminDatabaseSettings(t *testing.T) {
+	settings := idb.ServerSettings{
+		MaxConnections:     100,
+		WorkMem:            "4MB",
+		SharedBuffers:      "128MB",
+		PoolMaxConnections: 8,
+	}
+	mockIndexer := &mocks.IndexerDb{}
+	mockIndexer.On("GetServerSettings", mock.Anything).Return(settings, nil).Once()
+	mockIndexer.On("GetServerSettings", mock.Anything).Return(idb.ServerSettings{}, errors.New("boom"))
+
+	e := echo.New()
+	registerAdminHandlers(e, &ServerImplementation{db: mockIndexer}, middlewares.MakeTokens([]string{"admin"}))
+
+	request := func(token string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/admin/db-settings", nil)
+		req.Header.Set(AdminTokenHeader, token)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := request("wrong")
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+
+	rec = request("admin")
+	require.Equal(t, http.StatusOK, rec.Code)
+	var response idb.ServerSettings
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	assert.Equal(t, settings, response)
+
+	rec = request("admin")
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	assert.Contains(t, rec.Body.String(), errFailedLoadServerSettings)
+}
+
+type fakeImporter struct {
+	paused bool
+}
+
+func (f *fakeImporter) Pause() {
+	f.paused = true
+}
+
+func (f *fakeImporter) Resume() {
+	f.paused = false
+}
+
+func (f *fakeImporter) Paused() bool {
+	return f.paused
+}
+
+func TestAdminImporterControl(t *testing.T) {
+	e := echo.New()
+	importer := &fakeImporter{}
+	registerAdminHandlers(e, &ServerImplementation{importer: importer}, middlewares.MakeTokens([]string{"admin"}))
+
+	request := func(method, path string) ImporterStatusResponse {
+		req := httptest.NewRequest(method, path, nil)
+		req.Header.Set(AdminTokenHeader, "admin")
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		require.Equal(t, http.StatusOK, rec.Code)
+
+		var response ImporterStatusResponse
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+		return response
+	}
+
+	assert.False(t, request(http.MethodGet, "/admin/importer").Paused)
+	assert.True(t, request(http.MethodPost, "/admin/importer/pause").Paused)
+	assert.True(t, importer.paused)
+	assert.False(t, request(http.MethodPost, "/admin/importer/resume").Paused)
+	assert.False(t, importer.paused)
+}
+
+func TestAdminNoImporter(t *testing.T) {
+	e := echo.New()
+	registerAdminHandlers(e, &ServerImplementation{}, middlewares.MakeTokens([]string{"admin"}))
+
+	req := httptest.NewRequest(http.MethodPost, "/admin/importer/pause", nil)
+	req.Header.Set(AdminTokenHeader, "admin")
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestAdminRunPendingMigrations(t *testing.T) {
+	mockIndexer := &mocks.IndexerDb{}
+	mockIndexer.On("RunPendingMigrations").Return(errors.New("blocking")).Once()
+	mockIndexer.On("RunPendingMigrations").Return(nil)
+	mockIndexer.On("Health").Return(idb.Health{IsMigrating: true}, nil)
+
+	e := echo.New()
+	registerAdminHandlers(e, &ServerImplementation{db: mockIndexer}, middlewares.MakeTokens([]string{"admin"}))
+
+	request := func() *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/admin/migrations", nil)
+		req.Header.Set(AdminTokenHeader, "admin")
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := request()
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	assert.Contains(t, rec.Body.String(), errRunningMigrations)
+
+	rec = request()
+	require.Equal(t, http.StatusOK, rec.Code)
+	var health idb.Health
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &health))
+	assert.True(t, health.IsMigrating)
+}
+
+func TestAdminGetMigrationStatus(t *testing.T) {
+	data := map[string]interface{}{
+		"migration-progress": map[string]interface{}{"processed": 1, "total": 4},
+	}
+	mockIndexer := &mocks.IndexerDb{}
+	mockIndexer.On("Health").Return(idb.Health{Data: &data, IsMigrating: true}, nil)
+
+	e := echo.New()
+	registerAdminHandlers(e, &ServerImplementation{db: mockIndexer}, middlewares.MakeTokens([]string{"admin"}))
+
+	req := httptest.NewRequest(http.MethodGet, "/admin/migrations", nil)
+	req.Header.Set(AdminTokenHeader, "admin")
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var health idb.Health
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &health))
+	assert.True(t, health.IsMigrating)
+	require.NotNil(t, health.Data)
+	assert.Contains(t, *health.Data, "migration-progress")
+}
+
+func TestAdminWebhooks(t *testing.T) {
+	mockIndexer := &mocks.IndexerDb{}
+	mockIndexer.On("Webhooks", mock.Anything).Return(
+		[]idb.Webhook{{ID: "a", URL: "http://localhost/a", Secret: "secret"}}, nil)
+	mockIndexer.On("AddWebhook", mock.Anything, mock.Anything).Return(nil).Once()
+	mockIndexer.On("AddWebhook", mock.Anything, mock.Anything).Return(
+		fmt.Errorf("AddWebhook() err: %w", idb.ErrorWebhookExists))
+	mockIndexer.On("DeleteWebhook", mock.Anything, "a").Return(true, nil)
+	mockIndexer.On("DeleteWebhook", mock.Anything, "b").Return(false, nil)
+
+	e := echo.New()
+	registerAdminHandlers(e, &ServerImplementation{db: mockIndexer}, middlewares.MakeTokens([]string{"admin"}))
+
+	request := func(method, path, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set(AdminTokenHeader, "admin")
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec
+	}
+
+	// The secrets are not listed.
+	rec := request(http.MethodGet, "/admin/webhooks", "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	var hooks []idb.Webhook
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &hooks))
+	require.Len(t, hooks, 1)
+	assert.Equal(t, "a", hooks[0].ID)
+	assert.Empty(t, hooks[0].Secret)
+
+	rec = request(http.MethodPost, "/admin/webhooks", `{"url": "ftp://localhost"}`)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Contains(t, rec.Body.String(), errInvalidWebhook)
+
+	rec = request(http.MethodPost, "/admin/webhooks", `{"url": "http://localhost/b", "filter": {"types": ["pay"]}}`)
+	require.Equal(t, http.StatusCreated, rec.Code)
+	var hook idb.Webhook
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &hook))
+	assert.NotEmpty(t, hook.ID)
+	assert.NotEmpty(t, hook.Secret)
+	assert.Equal(t, []string{"pay"}, hook.Filter.Types)
+
+	rec = request(http.MethodPost, "/admin/webhooks", `{"id": "a", "url": "http://localhost/a"}`)
+	assert.Equal(t, http.StatusConflict, rec.Code)
+	assert.Contains(t, rec.Body.String(), errWebhookExists)
+
+	rec = request(http.MethodDelete, "/admin/webhooks/a", "")
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+
+	rec = request(http.MethodDelete, "/admin/webhooks/b", "")
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
@@ -432,6 +432,10 @@ func txnRowToTransaction(row idb.TxnRow) (generated.Transaction, error) {
 		LocalStateDelta:          localStateDelta,
 	}
 
+	if row.Truncated {
+		txn.Truncated = boolPtr(true)
+	}
+
 	if stxn.Txn.Type == protocol.AssetConfigTx {
 		if txn.AssetConfigTransaction != nil && txn.AssetConfigTransaction.AssetId != nil && *txn.AssetConfigTransaction.AssetId == 0 {
 			txn.CreatedAssetIndex = uint64Ptr(row.AssetID)
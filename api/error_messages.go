@@ -29,6 +29,21 @@ const (
 	errTransactionSearch         = "error while searching for transaction"
 	errSpecialAccounts           = "indexer doesn't support fee sink and rewards pool accounts, please refer to algod for relevant information"
 	errFailedLoadSpecialAccounts = "failed to retrieve special accounts"
+	errFailedLoadServerSettings  = "failed to retrieve database server settings"
+	errExplainingQuery           = "error while explaining query"
+	errNoImporter                = "no block importer is configured"
+	errRunningMigrations         = "error while starting migrations"
+	errFlushingCaches            = "error while flushing caches"
+	errWaitingForRound           = "error while waiting for round"
+	errLookingUpEarliestRound    = "error while looking up the earliest available round"
+	errRoundPruned               = "the transactions of this round have been pruned"
+	errLoadingWebhooks           = "error while loading webhooks"
+	errInvalidWebhook            = "invalid webhook"
+	errWebhookExists             = "a webhook with this id exists"
+	errNoWebhookFound            = "no webhook found for id"
+	errUnknownSearchKind         = "unknown search, use notes, assets or app-keys"
+	errSearchDisabled            = "free-text search is not enabled, see --enable-search"
+	errSearching                 = "error while searching"
 )
 
 var errUnknownAddressRole string
@@ -0,0 +1,92 @@
+package api
+
+//go:generate go run ../cmd/texttosource/main.go api examplesJSON examples.json examples_json.go
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/labstack/echo/v4"
+
+	"github.com/algorand/indexer/api/generated/v2"
+)
+
+// Example is a request and the response of the API to it, captured by
+// cmd/openapi-examples against a fixture database.
+type Example struct {
+	Request  string          `json:"request"`
+	Response json.RawMessage `json:"response"`
+}
+
+// loadExamples returns the captured examples by operation id.
+func loadExamples() (map[string]Example, error) {
+	var examples map[string]Example
+	err := json.Unmarshal([]byte(examplesJSON), &examples)
+	if err != nil {
+		return nil, fmt.Errorf("loadExamples() err: %w", err)
+	}
+	return examples, nil
+}
+
+// specWithExamples returns the OpenAPI document of the API with the examples
+// added to the successful responses. Examples of operations which are not in the
+// document are an error, the examples must then be captured again.
+func specWithExamples(examples map[string]Example) (*openapi3.Swagger, error) {
+	swagger, err := generated.GetSwagger()
+	if err != nil {
+		return nil, fmt.Errorf("specWithExamples() err: %w", err)
+	}
+
+	used := 0
+	for _, pathItem := range swagger.Paths {
+		for _, op := range pathItem.Operations() {
+			example, ok := examples[op.OperationID]
+			if !ok {
+				continue
+			}
+			used++
+			response, ok := op.Responses["200"]
+			if !ok || response.Value == nil {
+				return nil, fmt.Errorf("specWithExamples() no 200 response for %s", op.OperationID)
+			}
+			mediaType := response.Value.Content.Get(echo.MIMEApplicationJSON)
+			if mediaType == nil {
+				return nil, fmt.Errorf("specWithExamples() no json response for %s", op.OperationID)
+			}
+			mediaType.Examples = map[string]*openapi3.ExampleRef{
+				"fixture": {
+					Value: &openapi3.Example{
+						Summary: "GET " + example.Request,
+						Value:   example.Response,
+					},
+				},
+			}
+		}
+	}
+	if used != len(examples) {
+		return nil, fmt.Errorf("specWithExamples() examples of unknown operations")
+	}
+	return swagger, nil
+}
+
+// makeSpecHandler returns the handler of the OpenAPI document. The document is
+// built once, at startup.
+func makeSpecHandler() (echo.HandlerFunc, error) {
+	examples, err := loadExamples()
+	if err != nil {
+		return nil, err
+	}
+	swagger, err := specWithExamples(examples)
+	if err != nil {
+		return nil, err
+	}
+	spec, err := json.Marshal(swagger)
+	if err != nil {
+		return nil, fmt.Errorf("makeSpecHandler() err: %w", err)
+	}
+	return func(ctx echo.Context) error {
+		return ctx.JSONBlob(http.StatusOK, spec)
+	}, nil
+}
@@ -0,0 +1,59 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// The captured examples must belong to operations of the current document.
+func TestCapturedExamples(t *testing.T) {
+	examples, err := loadExamples()
+	require.NoError(t, err)
+	_, err = specWithExamples(examples)
+	require.NoError(t, err)
+}
+
+func TestSpecWithExamples(t *testing.T) {
+	examples := map[string]Example{
+		"lookupBlock": {
+			Request:  "/v2/blocks/1",
+			Response: json.RawMessage(`{"round":1}`),
+		},
+	}
+	swagger, err := specWithExamples(examples)
+	require.NoError(t, err)
+
+	op := swagger.Paths["/v2/blocks/{round-number}"].Get
+	require.NotNil(t, op)
+	mediaType := op.Responses["200"].Value.Content.Get(echo.MIMEApplicationJSON)
+	require.NotNil(t, mediaType)
+	require.Contains(t, mediaType.Examples, "fixture")
+	example := mediaType.Examples["fixture"].Value
+	assert.Equal(t, "GET /v2/blocks/1", example.Summary)
+	assert.Equal(t, examples["lookupBlock"].Response, example.Value)
+
+	examples["noSuchOperation"] = examples["lookupBlock"]
+	_, err = specWithExamples(examples)
+	assert.Error(t, err)
+}
+
+func TestSpecHandler(t *testing.T) {
+	handler, err := makeSpecHandler()
+	require.NoError(t, err)
+
+	e := echo.New()
+	rec := httptest.NewRecorder()
+	c := e.NewContext(httptest.NewRequest(http.MethodGet, "/openapi.json", nil), rec)
+	require.NoError(t, handler(c))
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var spec map[string]interface{}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &spec))
+	assert.Contains(t, spec, "paths")
+}
@@ -0,0 +1,40 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/algorand/indexer/api/generated/v2"
+)
+
+// explainServer answers transaction searches with the query plan instead of the
+// transactions. Embedding ServerImplementation lets it reuse the generated
+// parameter parsing of SearchForTransactions.
+type explainServer struct {
+	*ServerImplementation
+}
+
+// SearchForTransactions returns how the database would run the search.
+// (GET /v2/debug/explain)
+func (es explainServer) SearchForTransactions(ctx echo.Context, params generated.SearchForTransactionsParams) error {
+	filter, err := transactionParamsToTransactionFilter(params)
+	if err != nil {
+		return badRequest(ctx, err.Error())
+	}
+
+	plan, err := es.db.ExplainTransactions(ctx.Request().Context(), filter)
+	if err != nil {
+		return indexerError(ctx, fmt.Sprintf("%s: %v", errExplainingQuery, err))
+	}
+
+	return ctx.JSON(http.StatusOK, plan)
+}
+
+// registerExplainHandler adds /v2/debug/explain, which accepts the parameters of
+// /v2/transactions.
+func registerExplainHandler(e *echo.Echo, si *ServerImplementation, middleware ...echo.MiddlewareFunc) {
+	wrapper := generated.ServerInterfaceWrapper{Handler: explainServer{si}}
+	e.GET("/v2/debug/explain", wrapper.SearchForTransactions, middleware...)
+}
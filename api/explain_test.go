@@ -0,0 +1,48 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/algorand/indexer/idb"
+	"github.com/algorand/indexer/idb/mocks"
+)
+
+func TestExplainUsesSearchParameters(t *testing.T) {
+	plan := idb.QueryPlan{
+		Tables:        []string{"txn"},
+		Indexes:       []string{"txn_asset"},
+		EstimatedCost: 12.5,
+	}
+	mockIndexer := &mocks.IndexerDb{}
+	mockIndexer.
+		On("ExplainTransactions", mock.Anything, mock.MatchedBy(func(tf idb.TransactionFilter) bool {
+			return tf.AssetID == 7 && tf.Limit == 5
+		})).
+		Return(plan, nil)
+
+	e := echo.New()
+	registerExplainHandler(e, &ServerImplementation{db: mockIndexer})
+
+	req := httptest.NewRequest(http.MethodGet, "/v2/debug/explain?asset-id=7&limit=5", nil)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var response idb.QueryPlan
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	assert.Equal(t, plan, response)
+
+	// Parameters are validated like /v2/transactions.
+	req = httptest.NewRequest(http.MethodGet, "/v2/debug/explain?not-a-param=1", nil)
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
@@ -71,149 +71,174 @@ func RegisterHandlers(router interface {
 // Base64 encoded, gzipped, json marshaled Swagger object
 var swaggerSpec = []string{
 
-	"H4sIAAAAAAAC/+x9f2/cOLLgVyH6HjDJXsvOZHYXNwEWD9lkgw02mQ1izyxwcQ7Llqq7OZZIDUnZ7sn5",
-	"uz+wipQoiVJ3204yA+xfiVtkscgqFov1i58WuapqJUFas3j2aVFzzSuwoPEvnueqkTYThfurAJNrUVuh",
-	"5OJZ+MaM1UJuFsuFcL/W3G4Xy4XkFXRtXP/lQsMvjdBQLJ5Z3cByYfItVNwBtrvatfaQbm+XC14UGowZ",
-	"j/pPWe6YkHnZFMCs5tLw3H0y7FrYLbNbYZjvzIRkSgJTa2a3vcZsLaAszElA+pcG9C7C2g8+jeJycZPx",
-	"cqM0l0W2VrridvFs8dz3u9372Y+QaVXCeI4vVLUSEsKMoJ1QSxxmFStgjY223DKHnZtnaGgVM8B1vmVr",
-	"pfdMk5CI5wqyqRbPPiwMyAI0Ui4HcYX/XWuAXyGzXG/ALj4uU7RbW9CZFVViaq895TSYprSGYVuc40Zc",
-	"gWSu1wl72xjLVsC4ZO9fvWDffffd94yW0ULhGW5yVt3o8ZxaKhTcQvh8CFHfv3qB45/5CR7aitd1KXLu",
-	"5p3cPs+77+z1y6nJ9IEkGFJICxvQtPDGQHqvPndfZoYJHfcN0Nht5thmmrB+xxuWK7kWm0ZD4bixMUB7",
-	"09QgCyE37BJ2kyRsh/l8O3AFa6XhQC6lxg/KpvH4X5VP80ZrkPku22jguHW2XI6X5L1fCrNVTVmwLb/C",
-	"efMKzwDfl7m+ROcrXjZuiUSu1fNyowzjfgULWPOmtCwMzBpZOpnloHk+ZMKwWqsrUUCxdGL8eivyLcu5",
-	"IRDYjl2LsnTL3xgoppY5Pbs9bN52cnjdaT1wQr/dxejmtWcl4AY3QpaXykBm1Z6zKhw/XBYsPl26g8sc",
-	"d3Kx8y0wHNx9oFMb1046hi7LHbNI14JxwzgL59SSiTXbqYZdI3FKcYn9/WzcqlXMLRoSp3eoOs1kavlG",
-	"i5FYvJVSJXCJi+e1lIyX5Yy8LEsmLFTGKzVONOIARStKl6yAEnCS3XGAvxqr1Q4nb8C1U7WFIlON9Uyx",
-	"VaUDaJZIEQJLn6PDp1Q5L43lFiYVongmeyZdikrY8XTf8htRNRWTTbUC7QgeZKtVTINttJwanCDuYdSK",
-	"32RaNbI4QOWwTOlYpJsacrEWULAWyhQu3TD78BHyOHw6RShCJwCZRKcdZQ86Em4SRHGby31hNd9ARJMT",
-	"9qOXLfjVqkuQrQhiqx1+qjVcCdWYttMEjjj0vLIvlYWs1rAWN2Mkz/xyuP1NbbwArPzpmytpuZBQONmI",
-	"SCsLJCsmcYoGPFbFWHEDf/7j1PnafdVwCbukyBwyAE2nvdNs3RfqOz+LdoQ9W/JAPlyrIf/N8t5BfIeN",
-	"Mtr0iTPUffUiIX1/7PU/4AYZj23EJqOfRywlNufu2FmLEo+knx0nhWVojBPB/YUIh5QRG8lto+HZhfyD",
-	"+4tl7MxyWXBduF8q+ultU1pxJjbup5J+eqM2Ij8Tm4nFbHFNXsOwW0X/OHjpa5e9aaebGiJ8To1Qc9fw",
-	"EnYa3Bg8X+M/N2tcdb7Wvy7oQjM1curO8Uapy6aOVzLv3cFXO/b65RR3Icg5qYE7zNRKGkArwXM6LN/7",
-	"39xPTjCARLkXnXenPxuF+lwHu9aqBm0FxDYP99//0rBePFv8r9PORnJK3cypH7BToe2UwCc259ZvdNrg",
-	"fuuDdgKsqhtLaltqD7VM/6HFbThmRxa1+hlySwvUR+MRVLXdPXYIe9zNw60W/h/1mCPWzaPMtea7z7yO",
-	"dARmeJSNIf/o1C0n/2q+ERInvmTXW5Cs4pdOHHCp7BY0c7QAY8NhSAoknY+tscafqF6pPFmkdkyCpube",
-	"RO2o9hB07drupWjU9IvuhodaLvOw63XEXuiv3H/2A+6HeCXvuyfcNeivvOQyh4eg8sqDOpjCb4UUiMTf",
-	"6Sr2HzIHMrdL+RAkfogN7ODs3bDY6Mse+TjkQyySeahVOkLAhfX6D8+3tLw3x/+1VPnlnWg5RyqEumfk",
-	"vwMv7fbFFj7D+BHsPVicd5eIB+Doz8qJ0X1n3/yjWe1RdPpgj2SeaBjzW1+9384+7i354eKvR9OhEDyc",
-	"xuY4It+Ge3N8MU74BL3/XkiyXrk7ObeMexcXGX8u5IV8CWshhfv+7EIW3PLTFTciN6eNAe2Vq5ONYs+Y",
-	"B/mSW34hF8vh2THl00cvhsemblalyNkl7FJUIPfKGMLFxQdebtTFxUdmleVlZGeOnC7ePthdoscsRwNk",
-	"jjNUYzPvrMw0XHNdJFA3rXUSIZP3Z27UJfOwyYjqnaEefnob8Lo2GVrpMzTTp6df16Wbfqw9k2mfOZIx",
-	"Y5UOJlJhAjZI3x+U9WZHfs2Iv1hjwLB/V7z+IKT9yLKL5smT74A9r+s3DuaZw+Pf3mTo9tOuJrfJkbee",
-	"DlhKScCJIz0zuLGaZzXfgElO3wKvkfpbYKap0KNUlgy79bwdtVYbzSs0eZtuAmE9pglAeBx2lkUzxMmd",
-	"Ua/gok9PAT8hCbEN20Lpje33oFd09bgzufZcX2aCAi4uPqC/P1Cm9Q9uuJAmnApGbKTbBN6VugKWOy0A",
-	"ihP2es1Qqi173X1Aj5eYregQhryf7NzNEU3nLOcSvaJ1gV5CIRmXu6EZ0oC1wej7Hi5hdx5Z3o8MO/BO",
-	"Nr7nSCwaB649FjsKs2tuWKXQIJ2DtOXO++0SrJlGphHSkgsiJ99o5vh3Smjgroncs27jxCLEwxgyYuSt",
-	"5HXNNqVaeUnTsuizlkdDn2mh8s4hYB5AoCTvGmEZZvZezXViIWgjTizBHSbq4N1rG85O784stxbaoE8Y",
-	"uD8jeLxF7sB53mE9RuVfW0CtTGkmlR2wlAlbOsX0rUdruai5tiIX9WHWSYL+rtfHAdl3tCcPc7Uentmj",
-	"IzV5hFDjbMVN+vgG98VxYGMomMHNMQi6MBJpyziDE4aBkH6rrkqMb2hjr4jGXGPgRZg2xSJNoZbeF6Bl",
-	"p1MFNPorEitvW25CDAaGqgQRcZCaM8G8524BkIHdvom4N9ZbhRu3hCs+tf7TzsDXsnCyA0w/HqV19YVj",
-	"Zbj9l60DmmJMg0sw+AGD82+xPMqRt1w4Ha9Jk0NJ1PHc7trQxKlxYBSP2jcmIpDD45/rdSkksIyJdrYW",
-	"Z0vxQyoXFETT7UQ/BrgrwB+Y4zYH4GAIKTaO0K6VKgkw+0HFe1NujkFSgkBpwgNsFCvR33CATaYN9vWX",
-	"i72XgLHs6DbRsvOLExnHN7fW/fZuKMaS97NeK0ZNVv6+ER1XKRZ1oil3F3xpGowhsypX5cnoYmagBJT0",
-	"WU+yZu4SltTpANnwLHSLLm3skVg7FetxJMo1bISxoP2FHTFsQwu6yImdBYcZtxa0G+j/PfrvZx+eZ/+X",
-	"Z78+yb7/36cfP/3x9vEfRj8+vf3LX/5//6fvbv/y+L//K3V/vFIWMjzusiteprzWFxcfXKNXBlXxV3gy",
-	"JsVPb6kYBfmJCUMGDnsJu6wQZZOmth/3Hy/dsD+0t1fTrC5hh4cM8HzLVtzmWzyFesO7NjNDl3zvhN/Q",
-	"hN/wB5vvYbzkmrqBtVJ2MMbvhKsG8mRuMyUYMMUcY6pNLumMeMGb50soLZ8PPkebghOYlp/M2WxGm6kI",
-	"sOfUrwiLaclLkJJz6buhp2chZAE3GOYobBTTaUYzOlRdRlsiSdNoGHc78xA+u1oczy5WjT2UtG7sP95j",
-	"emPwh05vQrzwuhbFzcA4RQRLiw+k3jG3Pro+jhgMN44Htoe5IkPUOFzMKg3BmEa7JVJHKPBZxnMbb6Mu",
-	"9PYwwoQD3EcCq6ZVogbDfDYGhHGMsJ97ihfZWqsKd974FhQxp5jQ73ss2B05g1F9KtOYX5zwxBD7vfZ4",
-	"4OU/YPeTa4tUdb0paFrIQ7dMd93BnkxIqx6ANPezLKY430Pcw/nv2s2W5HrMeSHrTs9RcOQG4HWt1RUv",
-	"M29/nRIUWl15QYHNg7n2C5/paVqd/+35m3cefbT0AddkkZ+dFbarfzezcoeb0hP7NCRpuGtZMIsNDxFv",
-	"fxWmZ7O93oIPt48uLe649sxFu7yzx0cSwdtw10G5O9Ii610HNMUZFwLUrQehM/2QA6HvNOBXXJTB5hKw",
-	"TUsmmlzntjlaOMUA7u18iHxI2YOKm9HuTu+OPZIoHmEmDaCiVBLDlA/3by9LeENCAw4yaMV3jm/I8zUW",
-	"SbKpMrfpMlOKPG2VkyvjWEKSQ8k1Zth44q7lIDqBnobViAiWa2YOiP4ZIBmNkVzMEMM0tXYr5T3ejRS/",
-	"NMBEAdK6Txr34mB7ut0YEtHurEcnzM6UsPYFNWkc8Bgd2idW3WtyLZS7aNJOOR4P6qnm59PS7j5KtAM1",
-	"pT4jEvMadOwbHKH7sjVWBS5qnZpc9twoR4QYxCOOtIyZ8AC/+byoaKTwLtY7UGd/nnXQ1n0CXlpcTB61",
-	"z6ePWQf/iAO2O08RsfgkpZxAXhqVANPIay5tyCz0q+V7GyDLout1rbSxmIqaDJo56roRZyze65JhsrVW",
-	"v0LayLZ2fHA9Hj4amHqngR98WRhIholLQ0uZaUbZx4xtzud9UWovmfdGaqgdtHb1rsxA4P2YXJMCZuqK",
-	"En1k/UCciUMMZU3k7sUbXXBRcEnC5QUWLug5QNMiKo7QOiX4nYjyOI8NAfx6xfPL9E3B4fS8C3LoOVOs",
-	"YqFzm9fbp9cJi+Il2rbCII/XoCth+0det1HvqvX/3sRRLipeptX/Alf/vKdQFmIjKEW5MRCl6HpArFZC",
-	"WuKiQpi65DsKI+mW5vWaPVlG8s1ToxBXwohVCdjiW2qx4gYVs87WE7q46YG0W4PNnx7QfNvIQkNhtz73",
-	"2yjW3szQVNJ6L1dgrwEke4Ltvv2ePUK/rRFX8Nitole3F8++/R7TmumPJ6kDzRczmBO/BcrfIP7TfIyO",
-	"a4LhVAUPNS2PqRzNtKSf2U3U9ZC9hC394bB/L1Vc8g2ko6GqPThRX6Qmun0G6yILKp+AiiUTNj0+WO7k",
-	"U7blZpvWhQgNlquqErZyG8gqZlTl+KnLeqVBAziqxUCyvsUrfEQnec3ShrAv6+KjBNLUrDGU4QdeQX9Z",
-	"l4wbZhqHc5fd7gXiCfNZ0gVTstxFJkBcGzcWqipOsUZD7ZrVWkiL1oHGrrP/w/It1zx34u9kCt1s9ec/",
-	"jlH+K6aSM5C5cuPL4xD/4uuuwYC+Si+9nmD7oHT5vuyRVDKrnEQpHnsp39+VychsZXmZDgoNEn0YEzwP",
-	"+lDNy0HJJtmt6bEbjyT1vRhPzgC8Jyu28zmKH4+e2RfnzEan2YM3jkI/vn/jtYxKaegbuVchTrunr2iw",
-	"WsAVxqemieRg3pMWujyICvfB/uv6ybsbQKuWhb2cughQetJ4OdzP8bSnzAlKXV4C1EJuTleuD6nqBHWo",
-	"pG9AghFm+gDdbB3nuM/uyIusPwiaraBUcmO+PKcHxCccsRtAmfT65T6sR4BDsZcMm04vjGvnhngXisMQ",
-	"aNf+a5xIbWDj3sS3977tdByiO8Yokv2FjzunMJm+y5Lme83RJwCyILUOxd+WCzkRnAhQTARaAY54prQV",
-	"FKwB8BXCpqyowFhe1eljFo3ktBNxVztE2y7uNmIgV7IwzAiZA4Name2+dLmJNI8biYOVwtCRExcAzZWm",
-	"kiCoU1g1SGU6NNB6Nmmrj2OmlbJTiKLyEWfbKWUZb+wWpG3DGwHrkA1nQqHYeOOgA4VEFnvrZHwopsLL",
-	"crdkwn5DcDCACs/jCvRlCcxqAHa9VQZYCfwKuqJzCO0bw85vRGGwpFwJNyJXG83rrciZ0gXoE/bKFwTC",
-	"WxB18uM9OWE+CcWHZ57fSJxeoYCuSPE8aZohnrb128QzXtIBOvwZK7UZKK/AnLDza0VImC5xzzglpNdj",
-	"1VgKYC/Eeg24T3E6eHnCft2HCCcsn4dF/Fqwfk5fYbfdyAz144lLpCVLxY18QY2Yj/ruO8MGW6OiG2tg",
-	"qBKKDeglmVRx2UUFXaKm092Utp3BZg0UDO0km5BWq6LJgdIDz3r8GKElRii1FcWi/BvkoVC9sMMzGFuC",
-	"THUXclRwn5CaJVV/hkg7uALNVgAyAvSIhE6El7FcYz4HYNYRTRWKx2nh3NQbzQs4zIeLQvBH6tGmtQUI",
-	"V+o4AD+59kO1qaeb9E789CkdBSS7UyaW5SlZNql6vZ/KEnhFRRk1lBS+jfX8sO1ypFitATIjZNr6uQZA",
-	"2c7zHGrHznG9ZgAnqEiJRVGBeWXhbHUUllZcAQWWzygDWc7LvCkpgHLmpL/Oean7LqMS1lY5BovLeHYm",
-	"QeHGWmEAJ5XSo/G0E4BRD7ejHJvufAu6PYXKdW5z6EGcwzhVIyvhCtJ3GuCUsfF3dc0qLnctLdwQHRpL",
-	"2i+4VVrMSVdBJzpR+0d/sYvQp83kuW4eSUeKicUtYjrXoIUqRM6E/Bn8bm7FUuAYKmCppBWywbqfGjq8",
-	"6ZxgmHwyTDAZc4CeSqF1H/rR1xKue9QuIn2uH6tsLL8EQjukyfij8VCaajCiaCZMmZrnfcyOY0a/ed9z",
-	"C6e6Ja15IL4cSKh2k89tuiEvD9hmQK3xKk3KqZ7wPURY8TYxgnlBnQjf9Ln5oeXE3UdZFSxOITe1hX0F",
-	"2vQDAyMbINzsge1a9OBTxQKtyL5w/ChZCNkxk+PtSBx3PBeUL0ouw/7gY0YSKzhRzqFFwFwLm2+ziVwI",
-	"15ZaOBzeD29a4yFJhcBdCOs15PYQHDConirBTmJBnx0WL4EXmAXV5UdQZsQQlUc/KOZAm0ivkUagFtqp",
-	"NQjl8RFlzloO2cf8P6kDef9K4f/QRXrANgiKjKd92uxJbTzzdMl1nO3A4Kq0hUajPVIrw8u0hycMWkDJ",
-	"d3NDYoP+oK1iG5xcdOZwd4a5AwVuIG8m4nWjof0+mxvcNRlOuN2e410RF88cUvJvWisdl2YZOL0lA9eC",
-	"hfKXdKtR+D1Ue2iz1/sEdN+iVI5uzAqM4RtIl+eNeTE0TLHg3654OZFv8h5qDcZpuoyz8789f+OdkFNZ",
-	"J/lkkhS3PgPScjaZnny7xJtaWrZRTB9+94XZkwbYqTg+CuNzn0e97xYdMVXGJ1rQEBY6RugfIfSd1Vx4",
-	"D3uXcjNeWZ+GNU6MOyR8viPwcBI+uQmBpGYSF3caczTb4mcq+9Dy9RHsW6yyNig3VQR5ucAt0y/cM753",
-	"Dyw9wmSV2GiUlmmo09smMiPuke493AeDdiMEeKnFHdUYTKywEVVdklvX6wjuRI97saNyv7pIu88fuPnQ",
-	"MWGfPaoL7uxSfPhgrrvisj9Lej5w65/yharqEqYFeU0OeXoMgs5qzMDnRSH8WRaMOyrPG91Z/YahWT/x",
-	"UlCRboNZ+FKp2v3rzkTp/oNpVKqx9H/g2v2HasL0/0dcFaXsO1ALpIuQC1/dRTU2BLgvnJJQ0BXF902l",
-	"9N8xFfMgc/X4kEiIstnQ+t7hjJQpycjepQu4XYlfNvglzkpghAiGh5jwl2EFWNCV05a36ppVTb7FQHy+",
-	"gRCXjzEvaKodDNSDHsL3+vkl3t1pap4TIAqJKrnegGY+Son5EqNtqFPFxeCpgGEgAl6eeerg3JctMH7g",
-	"AtWcKGcgkZQQ0LiE3Smd4vj7HQTHdOrBBGKYgPAZUbpXHkOcCrOHXy97ChAVeOplD7XoP6Ai5PDze+1I",
-	"RWic5HPo9HAeuB0aA+N5Hu7eitc2ISq6uR2qxY8Xd1r5tqtDlO90pRbXHbV/WpBQPSlxb/tSujvN08Pw",
-	"4yap3i8DOnxBCYWSwYJ1/omjXFWVkmieKsuBb1AWDKOlDL55JBnIKyhVDcnWuEgHhC8bsZFQ2BtJcRFn",
-	"+Of5jUy1jY9fbB1NL1X2MXrD7m71UAf1vSiMnN6XuyvELtC7gxieNrw7xFcUjdpCRFBr0PeBee5hHFBq",
-	"byM1ZTBSOLYIwUmoOBGFB09mhoClUIIvhF23flz4peGl91NL9AqfY+hxfgmSquu1L/tZxUCaRnu3sMMV",
-	"4TlUPBgVH7qma3LXOnvZXO0qjSbz1hrvg9EwjJ66OnWgcMRR87W7XHshN9lMdlGO6UW+YUgfRTvXbBk1",
-	"B9wxoa6gODD3PPaKYQpd6D+TY0QlALuHJNPJZdFDS3JcqYE9ev3yMcMyLFMFMaIXWPdPO67JdxhGFOE4",
-	"wmWYTHgMFmuAKVfkIHqDrWHisNlXTWh91RUSwlZD8/FeLA8MR/s7N1gZyDf3bvPfaAxaD0n/DtAYVJz8",
-	"fHS1meVio1WTDlnaUEL+IJgSlXVUhCiQxmz5n759evr0T39mhdiAsSfsX5gxRFrQuE5Zn5pMdPXPegUV",
-	"GSLWZtySOuOjJaIxt56go6gY4aMmEMyXp3Cyikc0O3xmdtxLWs1JyGVqvU4mKv8Tf+/MKDrIPg3j1T1A",
-	"+tGLVnc8ff9Bz2HdLhd7ymeVV23lrLtt8BKmykKWNwk2/e5p1nHqCXvjejOQa6XdLbNqrDtr8bHKYOeL",
-	"uYcyW2xXIheTWuSvoBVeoiVT7s48PGtEtNgYicFz1IONDydyOLQZyW3M96Mz1BqWhORjuqMl3uNupBWk",
-	"Zrhl/ClaxdoJeIf0v7aiTHBBrdx3E+OxZFIxKv4et6S4uS5Di3D2UdE9Rvqy2ymuylCkbUSOEzBm4k1U",
-	"Eae7oedbLrtq1v1yOhTkRI6uqELggCePebmrL2OH10epJqIrpC/05nRkTCNqDS1fdrlrvqtA2jsKhXfU",
-	"mwI36LHZeSVUTyihofe+srFTj0g62O5jm8baavtoUiNBFM1xOaF6dw8H+xLZnfpEzOVOqXWDwX9RvGQw",
-	"qflbRWuavYQd08FMEFekJM39Doo+nRjpd7rPRQWdaky6ROoUFgedFv65++TViiK/SZp9MzOdFsw8V5gJ",
-	"rqC+8zzRUuEItj1r+/QfihwbWHY19P3Yvaq4/cBNvGaesJdtQC2a4Cm0rIuyJZPG0FBPaaltlrDQ8evO",
-	"ZIpEW/7FxYea3PqJjesb0DHv2owPfN+E5+tNW1s/YTsIzW7WoLt2qft7aLnWv3YNx6aD0Gz8LENP8iwf",
-	"4g3O9B7yZM5wgESQ1qJ/d1lS0bBe0Um/I2Ke69hnj6FrtnKjj0VB4350WPX0lEOS7iP7J6Xedz+84GV5",
-	"fiNppESEQfdsZco1RcVQfZZBKzWdaPXeqWDM8Ds2NqTzPHcaSdFFMUZ4fmPYsFqSf5x7VC+pdzAfKTUT",
-	"T2m0/Mf1ZnLeaMcYa00iZ1xvmopsv59/fntmMFloUhQ+wWlcLdFrQrT1Gw0FU9qnNoi1z1uZqtRyYPU6",
-	"eoIEn/rtNK4usHKC05dOV4fa1xFQMstbx6k7u/CdesUuyOF4sThhrykMWgMvSIhqYSFVR603f8zJvIay",
-	"RLMxcXTWUjcqtXjidlGvTp1BztaAL40MHbC/48p8vDbNBMWmpJIPtuoR6StQ6IUbyUNqiZRzKZX9HdHp",
-	"yMp8g7eWojCBum5L9JUgw5NfpAsj2AnTndIgNnLufZQ1DweBGZIreRz0pZRPv4oJb0anRKsi302IokGe",
-	"gNEzCLzIlCx3Kekap9oNxGu7FrOPpLTJd6YLLTF+llGdl8OmGMTMu2iGyNh4w3z3sPO7QyHFe1dPHADo",
-	"SY19fXvxMzNP5FLmTx/0Ps0scn7NamZUdKR0Eyf5pCEL52eQWLKgeiRNF45zIZ+zX0Erf4FsQbkN0ZlM",
-	"fVK6zxc9SXRqiweZUbfhkEcWZ6LJz2iHkwXeLi4+3PCRloE43UO/uFutvr00fjVRHCemcfCg+Go496x6",
-	"RSPOLOzUE4AXFx/WvCgGdULiEB0SMm2dC1ptXyUImYVfTxTkmaXmepaaM/B7SQXX4QY483hLuDFS+sZ1",
-	"WHHqkQpbnA7B6+qojYc+ZPO3PuWDWCPcgu/LHGHUGfaYqd/IK7yTPW9L83rkVIvfCfMixPtfw+862FbK",
-	"dZBmwWUTnIqD13PouWRW8fpBq0PuFR4RxtOuaJh0RHepOv5gDvCiKgQIoPN4D9/oud+zXwF6moL4dZig",
-	"weMSJd0LgBoqzC7qrpgJ4vjSZq1a2NWcI+c++uLjEGITjRCvNWOvHWReXvOdCbbTjrGmwYVVpVomCbtd",
-	"nH5IBt/02ugcnUjvIRe1wEcN+1Kw5fFpi+PEo5JkuXRCh/KixFVrtPAxxLwrFth3FAU/kS97xqMDeumX",
-	"mZd9awEBDtZh1+ZFgB1m1JI0Os8OeKYpUUSyXdI9Ms978maFnTcdHivjqBcJORpmWrrJ4ZswE34S6Ro5",
-	"or3l+rJ3BnLTf9CNguV7UHsqRhTifoc3nrx34V33DA+G7La2/p9Ak7PvPZeFqtirRhIXPPrp/avH/qHn",
-	"wGQhId8xn8fkN/z803r8/FPiESS3JA/18NNl8ZUefipHDz/dfaaHP/kUeGvqwacQHE7+pI0wVidMxF++",
-	"gtWcmAm+wXk5490Yxwoa340kjR/pbooU6VETD2TbtmbR4Ii8lzrSey6SW3r73fi6k51a0g/J6yrAyjay",
-	"LrK47w3Z68ObeJrDayQ4CBauS7w9aPzrlUEKR+8U0/M8VLm2jNSEdSMLM1jC7rWIGefhrJbglYTQZtYP",
-	"OXV8HnpmnsVexj4m6MXzwfXtK5nDB2GwmijVDcWXSumRzGEpoG4p/fP8iaTVUm1EbshWcay7803oe7tc",
-	"VE1pxR3hvA19yf+aPjEFehjPLJcF1wWD4umf/vTt9910f2PiarxIybgTPy1vjuNW5H2Nr53dAUIskPJk",
-	"o8Yia9IrpTedkb71Qi2x/nEXFXWcMwkRSc83mmyIbljtGI9YXTkFt7Si+2npfttys+1EZ/+5ci458/Jq",
-	"GM2FeRRf50GgaFNk94oqGGyPKcHRbZLfwt6IxSPxw6Ei8W0kScYlnv0UyUDp+CUkl+Fa1yU43a6TgeN9",
-	"k+tdbdVpIA0d+WHMMzF+9iKGl151bIA1K5XTRChX3CmTncaFV+kOqztUyxutz1mMV6qU3laDcRilQ1G2",
-	"+uLiY1rZpBTmtHaZ7nR7JG3PBmvaX3Fat0kNt74kJL7sXt7DA18epfGa32Ig8Bq1sVxJy3PUG6mI8uK5",
-	"Ny0tfM3exdba2jw7Pb2+vj4JdqeTXFWnG0wayKxq8u1pAEQv98Sptb6Lr3bnpHC5syI37Pm716gzCVsC",
-	"vQEON2jfajlr8fTkCWVkg+S1WDxbfHfy5ORbWrEtMsEplS2girE4D8ciqBi9LjDz8hLiwgdYIxtLG2D3",
-	"p0+ehGXwt4bIrXP6syH+PszTFA+Di9xfiEfoh3gc1egfs8iP8lKqa8mw/AjSzjRVxfUOE/9so6VhT588",
-	"YWLtyzWgB85yd2p/WFDC2uKj63d69fQ0iq8Z/HL6Kbi2RXG75/PpoCBoaBs5YdO/nn7qu8jigYKDs/f3",
-	"6adgV7qd+XTqM4rnuk/gTMWTTj9ROCPdvqKh0p16ytMne+OxQ3OOdqy6ePbh02CvwA2v6hJwmyxuP7Yk",
-	"aneZJ9Xtsv2lVOqyqeNfDHCdbxe3H2//JwAA//9YPuu9IKoAAA==",
+	"H4sIAAAAAAAC/+09a4/bRpJ/hdAtEHtPmnHszeJiYLGYtWPEiJ0YnnECXJzDcsiWxAxFKmxyHsn5v189",
+	"+kl2U5RmPE5w+WSP2I/q6uqq6nr1b7Os3mzrSlStnD39bbZNm3QjWtHQX2mW1V3VLooc/8qFzJpi2xZ1",
+	"NXuqvyWybYpqNZvPCvx1m7Zr+H8Fg9g22H8+a8QvXdEIGKptOjGfyWwtNikO3N5ssbUa6cOH+SzN80ZI",
+	"OZz1u6q8SYoqK7tcJG2TVjLN8JNMrop2nbTrQiaqMzRLYGFJvYSfvcbJshBlLo800L90orlxoFaTx0Gc",
+	"z64XabmqYch8saybTdrCxxPV78POz2qGRVOXYrjGZ/XmvADA1YqEWZDZnKStk1wsqdE6bROEDtepG8Jn",
+	"KdImWycw+45lMhDuWkXVbWZPf5xJUeWioZ3LRHFJ/102QvwqFm3arEQ7+2ke2rslQLhoi01gaS/VzsHE",
+	"XdnCVi1pNbDGFUxQJdjrKHndyTY5h3VXydsXz5InT558mTAaW5Ergouuys7ursnsQp62Qn+esqkAAM1/",
+	"qhY4tVW63ZZFluK6g8fnxH5PXj6PLcYfJECQRdWKFewMIV5KET6rJ/hlZBrdcdcEXbteINnEN1adeJlk",
+	"dbUsVh2cd6TGTgo+m3ILRAUoSi7ETXQLzTQf7wSeC/hVTKRSbnynZOrO/0nplBm0kDAVzBjHRVu3abnw",
+	"W89x6foPzWeBeZzDmYa/NPpqYB1JWpbAv1ZCzpEvY8Nl0QD+8LcYknqwBajhvAbmlVZEnVnXNKLKbhar",
+	"RqTEBNbwZbCgtwoqua67Mk/W6SXtYLohaab6JtiXKfYyLTvc7CJr6hNAKDAtRQvAgVMYKtETJ11VIvfF",
+	"0dSJSmCAbVNfFrnIaeFX6wK4cpZKHoLaAaMH5AAhwSnJo7gIrm7HgTWdEK6D8EEL+v0iw65rBybENZHx",
+	"IitrCeeq3iF1tSCFw5O4ctKKYLmfDE7OYIE0OX5g/YNwV+HRLEGpaWlfYTr4PdESF9C0TG7qLrmizSmL",
+	"C+qvVoNY2ySINNocTz1AHSuGvgEydpwspW8BPylHOD9sW9GKjVTqGTJ5miA3QmEOCCsFLdIKNvoVWFt9",
+	"Q4uH1cAv9RZaLequVUSxrkscUM5pR3hY/uyI0bLO0lK2gMWoaueuZMeiy2JTtMPlvk6vi023CbA5QHoj",
+	"2q6pYpPziDsIdZNeA6V1VT5BeWqTunGFEwjXrADayhMzSgwWO80ueIpqP3isSueAoweJgmNm2QFOJa4D",
+	"m4KHC7+QNHH25Ch5p3gLfW3rCxDemgUl5zf0aduIy6LupOkUgZGmHr+2VDUIbRhvWVwPgTxV6MDzzW0U",
+	"A9woPQJUpjYFfpJrCYnDMa+IwuRMuK+ydA589+9/i2kK9msjQFcLssw+AfByzO1sjV+47/gqzAw7juRE",
+	"OoQ19OhvlPYm0R01WvChD8hQ/KpYQvgm7PWfcBd255bFasE/D0iqWJ2h2FkWJYmkn5GSNBo6iSzYR4QW",
+	"UjBklQKvEk/fV3/Fv5IF6IRAAGmT4y8b/uk1DFTAJPhTyT+9qldFBj9FkGlgDV4oqduG/8HxwhfI9tos",
+	"NzSF/hyaYZtiQ6CmRuAcabakf66XhPV02fw646tZbObQ7elVXV90WxeTmWdNAD7y8nmMumjIMa5BJ0xu",
+	"QRAKsnecsLA8gbEvi/bmrfqGn5BBiIr4nyP3jn+WNel1dg5gcVvRtIVQVhweihCKEhr/8xdgGgDGfxxb",
+	"+88xDyCP9dwvoTFyAAVy2jSAXqNWtjG5wKcBpAHzA+YDikOIBvncZtu1rN31yZ05/II49XDkd6hN4PEG",
+	"Pl9UtPo5zAJMfZNeILWnwBDXIH7wfAm8XTCvZ/2I2b+xqiiBoXSmo1mIIOw5/dGisY8AS0r1+c8ia3lT",
+	"fcgfiM22vXmIC1QbfCcbSyPt3k5u9nF3boAtnvRukCXvDltyj2Ng8Pb/9ATAD4Fr/xCqr/a2ASybesP3",
+	"xrRNUddIUHEvYJwMtDay8rIyDcs18FqLAf82tEbkSQ06Nt4wCCdVbc0TaBi+TIsyPS/FdPqVtyZgS6F3",
+	"QcO27U7qdZre68m/K3TJu8XXHufex9yf0o/Og4vJ254JvNP/Ky3TKhN3scvnaqjJO/y6qAoC4mu2K/y5",
+	"zXqbDSrvYovv4gDjODsPLDW6X/WGprwLJMm7wtIeDE7j60+aN3t5a4r/V1lnFwft5dhW0ag7Zv5apGW7",
+	"frYWH2F+Z+wdUJySsfsOiFmRVNjKB6pctkaCyOus2wgyKp8jQdAHIVlHpMu4Pgm9TexTfY8Y9Ox77v6Z",
+	"tQfcAQo+6jl0TBe7dt9Z1Q41zx/2cOTJ3zv2/rycfYrLmUde0wWdR787Dv4IPe/NDX5Ii/ZF3RClfXyC",
+	"Rr5YoterTQpAQdNqi/c8KVpELVBd37EtNA3qtuhoxJ+vAHSKb4Dd6tqPe937oO2iruEzEL2iIs2Kir0T",
+	"aHOF45uqYAw27r+v3lfP0R1b4Pen7yuk3WMgXqDZ406KRt03jlZ18jRRQz6HNu/Rd9xTp2LRZ+SlVtBs",
+	"u3PYQIxjCR1Ndp8PR3j//kf0tbx//xNHVDin0XGqK/+PtaENzwNPsMCjBvu0UGE1i0ZcpU0eAF0a7xON",
+	"zN79sVnniRqbnWQqbEeNHz6jQNNyQV7YBblhw8sHwsfluxdKdt0SuwFWUzfaBYZsgaGh/f0W/WFEu+lV",
+	"wvSFYQIy+fcm3f4IgPyULN53jx49EQnc4V/hmKcIx7+VSwiZLADtaQcTDQF2sJDeTAtnbgxcu0kXxEiD",
+	"y29FuqXdR29Ct6GIAeC81M3zZgNJroD5M0+2C9D4iG8AwzFNvXNWSIs75V46mCy8BPpEW0htkrUolTP1",
+	"Fvvl3MYP3q4dN/qR8DVYFUWm6Z0x8R+rtKikVhXQa4aHQIXKoMsWFWNQFZKXy4S42tzrriSuYpGGdRSS",
+	"o1uSM1wjuUaTDNgyRr1sc4oCAfJPq5u+mwnW12qn3lt0mp45ntU9A+RUEEW6Q0/KOxzO6Ep2h0FSyGRT",
+	"k8Mxg9WBUsBDBkgzDEwHn9nFnHHsywLpN8Y06NQ44Td4cFwWosboE6ITjQLNk1VZnytOY0j0qaFR3SfO",
+	"VN4gAPIOGErw+q3RMHL2AAMBRPBBjKDggIXieLc6hqPLO5jkWMeEfYS7pjok7hE5gPJUQNIQlB/WglR1",
+	"QAGqTz5JSX2kQ0RvIhbmGE0POnOxnWaw59HfeH1wkF2iPSjM4a+ezB6I1KAI4cYL1PqDBCjwC1JgJzlY",
+	"DdeoGZ2eia9QtAJ1X1BHFdR7jJMyUcK8xxgH56CKo2ZjoIXPhWgqq1NpMHyMuMrbOpU6xo5CETWLmKTm",
+	"jKjhrErjuXGo19VbC5y3FJdpDP/xYI+XAFqGar4fb2hCObRY6R//uQkw4mwIHfKh4zx0cAeAs0+gBoAK",
+	"O9yFt6OuSMfD07XihXNjTSgKtM+ks0EIx3fLZYkRlQtAml5tS6vl+NA6KzhI0p5ENYfAK8BfE6Q2HGDy",
+	"CCEydsDewmHmgRPgoG9cIt0HyEoUxE1SPTaxFedvMXLBJ62OIyKLKhL8Zm4RnkwMsFAah1hBDYR/JI6w",
+	"U4HRe/1IykSFUuLC7X0fadscX7qFbVPUAXSEVeDmzosgETV9GSzR9lqAFyl6R2CPayb7oV6NddeQRZSF",
+	"qfi8G6j6JnWTQqUuwjsvrEM5Zxn+3MboMcsZWhlMpMibvsgN2hK8Vgk3OVd3Y0e1CrFTJL0MDTqV7Cie",
+	"va0z4BEDI4KEg01aycLTAhZoMAjePwSxzFPdzTEwJA+KJV4HHjpqRyNWhQQg1V4RhGZ3bBTnDeVNbDEl",
+	"pcGJ/ufBP5/+eLL473Tx66PFl/95/NNvf/vw8K+DHx9/+Mc//tf/6cmHfzz8519CXOoSg1BJNVtcpmUo",
+	"gg6Wh41eSLo2viAtLigqPVQlnHBQRI4BTYuBo3lRduHdVvN+8xyn/dYcBtmdQz9SiEQKU5+j74A0Jm96",
+	"bDMydZnuXPArXvCr9M7WO42WsClO3NR125vjD0JVPX4ydpgCBBgijuGuRVE6wl7ISvJclG06ntJHshOF",
+	"O9wzx+yLg8OU67HHrgoOFHHOyyOF1+LEdwZSbEUCszU3JDcqw/d0/GOyFHzf6S0qYvE82WiuuUFVL8Hb",
+	"GGZ4NPgTORe6qlBR2yzC6Ua3BP4bNXJNzqzko4aWClBoyphPZ1fipMKDB9zcZDjyTAA958aGpyAUigZp",
+	"LXBkv2PlUGs4S32tXUYcPznsdhZmeV/XV0rDVrsF1w3WUGvfultUc7TwI+BwupbBeWCnhzO8ECgjC5M/",
+	"YUas8aJH+HIT/NBm4CxJGbMk2sP8AcKIg/82gbMGFwW1M/3cbmXFHtGfRo0PwxFHBonkj57Br+ZKzM4V",
+	"XDEQDFxKJdw24YBt62wdUe+CQeiOK0tFmw9vC8Fr45m6J7pEMdeJ8dv0Bp3oc5WjZ4mb/2YS138xfc9R",
+	"z13gcZqjFFmwyLCngJW2CVEXWjHkHbY6noPZuQ6fp6GCrMwPiIxzAriqiWtyQiKzccoc9PnYVCsV+TBZ",
+	"MXQZTmrMcB/dGuWuzrVIqVHCJin18RbLGw4/dXkRTQkmKPLrnk+INyx8Qmj39jG2stV2QIJEWmqwHcTl",
+	"+H+GWTjosZLupdm1AnA+aeWubSg8bUbjtI0xlzdOsCQ+ro64P81HI0ARMxgEaZFDCuiWPmD2DnEWEbNa",
+	"OhDpRJm9WVWtiyG9IJuizOWd8QIiLb8RN99jW9pV7K1NFlOPjBiYKrTovdXW3M6hF6J8NeIOyn9jDluQ",
+	"6qkoAjtVPP/8ngcAPjY17NFCuT1jjAIaKUZBzbWX9J6vJ+G9Ovvq5NUbBT452ETasCN8dFXUbvuHWRUK",
+	"t7qJnFOd+47WUO2N6gsR5fYspOcqhcOjspgd+wuKa0VcfMqtG9zhCMp1uuxpfFMdocpjz0sc8dyLrXHc",
+	"W48L++19X72JZGKdm6ENcyZenI2W2Js5uQPc2ufvhG4s7pTdDE53+HTs4ETuDCPZ1RvO0Jf6GmKNoGTs",
+	"Ib8JEegmvUG64YCTIUuCfgs8dAsJAISdYdW5RJKoOI4DGyfUOHJzxRGRoYfH6gpnLGwmJxhae0A6cwSR",
+	"qaPpY7g7r1U0H9zJf+lAquaw3fipobPYO550ZVcX44P16IC3l+uA3KMmTRPuo0OrehW3WpwZ5RBNGpXj",
+	"4aRq19R6zN7dRonGoWLqMwExrkG7ITkDcJ8bu7sxr+hYImt32jeyz51xqo0KdQt1+BSrYJvU2g+tmbo7",
+	"uwtxaW1deasiNqOYqD2Ji1kcfw8Ba+UpAeZKUo7YTUtZB4bpqqu0anXBFoUt1ZtMO8oKc1WjCQgr/ITt",
+	"WPtcN1z33q0uGXIBDX8VYX/BEungaji9MzH3Dg8++bLQ4wyRS0PRc2seQIymlM5tQTKXzFsDFbMEOXXo",
+	"NO272xVlMLErivMx8eNfI0KMeI0TZUU3Oh0ZAI1owGdkFPMshGEW5UaQH/P4lkUpmIeGgPTqPM0uwjcF",
+	"hOnExhZ6MQxAL7qzcYL7+3WUOGGKpq2KDwcYODg/eFAP1fr/aOwoKzYwRRD5OWHf96rnxargyk9Y4NBW",
+	"PlIDJdu6wEBJpKK8kNsyveHoTYsa2JBHc4e/qd3Ii8tCFpgMgS0+5xbGWWJsPboLLg+WuZbU/PGE5mtA",
+	"KRw/6MKIBbSamxmZSkzQ0LlorwQs4BG1+/zL5AGFS8niUjxELCp1e/b08y+pWhT/8Sgk0FSNuDH2mxP/",
+	"1ew/TMcUL8ZjoKqgRg3zY7ZZxzn9yGnirlPOErVUwmH3WdqkVboS4SDkzQ6YuC/tJnmwe3ipcq5KR4ol",
+	"SMLw/KJNkT8t1qlch3UhBgPD+GAd6CCganb1BunJFhPiSfVwXOKOeb2BS3+k2LRtEjaE3W+0AtflCa2a",
+	"Igi/TbXvRqMV3R+J7BBmWzRMMUQ4b1x8KmfvlzUBEm5wLlJVULEmQ+0y2QIgLVkHuna5+K8kWwP/y1rf",
+	"3emDuzgHqTkA+V9UoSsRVVbj/NV+gN873oGkRXMZRn0TIXutdKm+yYOqrhYb5Cj5Q8Xl/VMZDaIL52Jo",
+	"jt5PxRkfeqrmhaMsouTWeeSWOpz6VoRXjQx4S1I069mLHvde2b1TZteEySPtcIfevX2ltIwN1ln0jNzn",
+	"Oj3K01caAUOLS0oLCW8SjnnLvWjKSbtwG+g/bciPvQEYtUyf5dBFgBPlh+jAn91lx8wJdX1xIcQWIDk+",
+	"xz6sqvOofSV9JSoh4V4SFaCrNVIOfkaR51h/aGjAclljiOq9U7oGPOKIhc8I98vnu6AeDKxraC6oaRwx",
+	"2A6neKNrbvLQ2P5TSCSTT7CzBMNb1TYePoJijBPInql0Lw798F2WvF40/2EWS5WzWkfsb50WkVgTKUQe",
+	"iRkVNONpDbSp4mrEJ4gAxWAN2aabbVjMkpGcTyKdagTUdAnGxsj1pCTw4VTXFU1WFpJFjvtCRFY3XGmR",
+	"dApMQ/EyiKfmN40mlfswLjAAMwYoKR9u5QMM1sQcRTTb6qwCkegwJ3clnAFFNw4WKMyyktfI43WNSqwq",
+	"jRngn0kViFSr0LKNaC7QOQW3FiBNLEkNt6VLYWt502jQ7ey6yLleeymuiwydNFsg5aRucgEayAtVZ5Vu",
+	"QdxJzfeIosmFzYo4u65oeXkt+IrkrpOXqdNYjN/GXbEKLuv/TAWwpSgvMU3h7KpmIKQtSiBRCfF6nHct",
+	"543lxXIp6JzScujyRP3sBwcmqkpOtdHNsGpNn+C0XVcLFU8Ypi22VFxXz7hRosL4fWdY72hsVGkGRVCl",
+	"yFcYB2ZKQeB5tUUzUHcDnmMNNkvBOUjI2TCgq867THBW/qlHjw5YxQAkU6jZCXYjGtJF4S2c2tiieSpe",
+	"yEnBfWSqS3grpL0TWOjiHK0ZdqAHzHQcuIAtUQ2Fc0HJvrxUuHGEmXO3hWORi2k+XGKC77iHySbXI2A0",
+	"8j4DfI/t+2qTp5t4Ej8spZ3cCpQyLi8P8bKo6vU2lpz3gmvdUxiq4rtK8M4HitVSAB6LKmz9hI/E2+Fy",
+	"KLZIzu6DPoIelmAlluNQUbpq2Yo7DMwGKIDyuUaUAQxszLqSo4tHJP0VtGt8l1Epli1VUnFfR7AmQQyS",
+	"LM47DpNcahwsGmSATg88UUimN6oF3550QXA8HGORrWrQEkYI32lAbJDgwTDhDWaY673AKSwYcz4vdFQM",
+	"5KyrkBOdd/udutg54PNhUlQ3DiRuRQS5ubvPQB9FnYPYKaqfhTrNhi1piuF3AWrY5Kqj5xTgOBi4WU4k",
+	"lPPZz+scUkATq1yBH/xEkkpcebudO/qcn3YBJ+pCMNg6O1WJxql7ClKoyLuIKROuij5k+xGjOrxvYYHH",
+	"jdlaeUd02eNQ5pCPHbo+LffIprdbQyxF+ZTHfKcwq9TkeCWKUQfCN1UNHN0ycveBj9ripEtCmLEBtdIP",
+	"DHRsgFhzanRsbOGNz9WjAEiyL+w/y0KH7MjofDfMji3NaeWLc7qpv1AxIwEMRkprGQAkKGPZehFJ68K2",
+	"3AJheNu/aQ2nZBWCTqEA/S5rp8BA+UH8wEYUCv6MUDwXaU7JxzbVi5O8+qA8+LZOcGjp6DUV0K1oXLWG",
+	"Rnm4RwUmQyG7iP/7eiLtA5D4P3KRTjgGWpFRex82e3IbRTw2pz1N4CfCinm/wTkjQMZpGfbw6ElzgPtm",
+	"bEpq4E9qFFvt5GKZg9EkJFDEtci6eA6Inlqds7HJsUl/weZ4Dk+F+yZBfye/apq6cauK9ZzeVSKwRaJf",
+	"FeBbTU3fdZElUzTG30D8FiyZCCqhTFci/OqJS4u6YYgEvwJ2EkmdewsIEuipRbxgxKlyQsYS6LJovmfa",
+	"qsIDsMqx/B28qYV5G8f00Xf13lXQABuL4+MwPvw86H1YdESspKKDUB0WOgToGx36jilbysNusweHmFUZ",
+	"pcMc3ynh83aD+4tQeZo0SGglbpnRIUUna/rM1ZYMXe9Bvvn5wgTlht6Wmc/oyPiFBSP5VdbSA9eqTbFq",
+	"iFuGR40fG8eMuIO7e7D3JrUz6PFCyB1Uuw5gWBabbcluXaUjoER3eyV7pbHaSLuPH7h51zFhHz2qSxzs",
+	"Urz7YK5DYdld8GE8cOu76hkwENijKCPfskOe39hjWU2Fb2CqQskybdypM9h4a/Xrh2Z9j+neFPgtqfhN",
+	"VYMwhn9RJlb4H0qjApTw/+GOjP/hUmz+/5iqnEo5ONSM9qWoZqqoGgykA9xnqCTkfEVRfUOVdA7MKp9k",
+	"rh4KiQArGw2t94Qz7UzJRnabLoCnkr6s6IublZAwIBQeIvVfGP3cYpRMhQE2V8mmQ6NiC7S2Ejoun2Je",
+	"yFTbm8gbXYfv+fklyt0pt2nGA3FIVInPFjeJilJKVLF7E+q0SYveC2z9QARdFHf/bIHhu4Gk5jg5A4Gk",
+	"BA0GiM9jluL0+wGMI556EAGMEhA+Iki3ymNwU2F20OuFpwBxXUUve8iAf4eKEMKnztqeitAwyWfq8mgd",
+	"dBwwFnGwzunuLRe3AVZh1zZVix8iN658t+dTlO9wpjt2J+2fEaKLFgbubfelu/M61Rhq3uCu+yXZ+w/T",
+	"ElOSVCdWvRyL7gv0jdT0o+8bxGhOjJaS9JQs3AarS1HWWxFsTUiaEL6MrjCRt9cVx0Wc0p9n11WorSt+",
+	"qbWzvFC1Zaeex2G16XtlNd3aBYeOaAO97YgcEHqbEV9wNKoZUddbuM2YZ2qMCRVuV1XDGYwcjl3o4CRS",
+	"nHiHfeowAUu68q0OuzZ+XCB20MPYT12RV/iMQo+zC3S/oDfGPP2OvoNKdo1yCyOsNB6CoobxSpRI2+TQ",
+	"8raLsZKRDZnMjTVeBaNRGD13RXUgx82px0uUYHssXDaSXZRRepFqqNNHyc61q84IkXGzAa1/Wu656xWj",
+	"FDrdfyTHiOvb2aI64eQy5/3aalipIXnw8vnDpFj2PzppfFpBL+SEZbvV7aZBxBGOA1j6yYT7QBGsf8Ou",
+	"yF70BjqiImPsKIy2vLQ10ahV33y8E8qJ4WhfYzgaqHequXKb/05j0DwgYwVvvOTnvQtnQX/AdDhkacUJ",
+	"+b1gSlLWSRHiQBq5Tr/4/PHx4y/+jpkgQrZHmLmApduFyjrplQf1dzMpbNlRvzoSAWYyblmdUdESzpxr",
+	"taGDqJhCRU3QMPe/w7vLFwV7YRkgZnKLmqo7Ras+GTNKo3lfI4bYncD9+KHgA6XvN/zKMObNj1cCLC9N",
+	"EcDDDngpYtWYy+sAmT55vLCUepS8wt7wEebDW+ama1HWimtK4mE7n0s9nNnS2sr0lNRS/Sqami7RGF+R",
+	"iWHZLQfZFImRZqQHSxVOhDCYjGQT8/3glLSGOQP5kO9ogaJeIC4LVjMQjd87WNwig0egf1gXZYAKtjV+",
+	"ly4ccwwO4od4vJJkJE1shhbDrKKiPUK63+PkVmXIwzYipASKmXjlVMSxN/RsnVb2EQm/nA4HObGjyyl2",
+	"2qPJfd7L9Xls//pY1ZHoikrVrEQdmdKIjKHlftGtSqIdyBTecG8O3KD64s24EtpElFDde1e1djQAtHV4",
+	"bPxo0liNtk8mNWZEzhrnEdXbuKj1yxRWfWLiQim17Cj4z4mX1CY1daswplmsO9poM4FbXJc19wMU/Z2F",
+	"+IxqzLpESAoXk6QF33DCVyuO/GZu9tnIcsww41QhI1TBfcdpwuzCHmR7avpQBHNXodoSkGanoo1fNdq6",
+	"ZgsrQsqZJFxMGtC77LBeoFZMMAM3GWpvREsDdkdhyfUgodJxz7TXi7hFCD74jnever4faUr34qPkuYkA",
+	"Jp8Bx8LZsGC2wfQ9C5xHa9KaQY4pWw2Cz7ZTcj5gJBDHIQQ4jWrAegm2GWooqkmaLVfmDZ6AsUM3uwag",
+	"bbuQwUG3XDa/2oZDW4duNny+yWOV1jUCy5tpFQv9QgAw/oMA4b8w3YyLuQ5dIuFDr7Z5QRMEospm/mVr",
+	"zlXOvIK/6gi7h8SSzw7L3GipSRU8Q94IR7p6itWUKgGOwZZrBdgfnqVleXZd8UyBkAj7un3Il8aFqFVa",
+	"hGHzKAuUO01bXxSLcS3/GBUjpXam9jSIz2TSL+/EwZjDAk+hZzmnsvnAk1uG/oDnRNdNhpehmldkcC5X",
+	"/ILofaxvxwqilTGLXGVkDcs7KtVNlyFGp26jcjGKpUq0iZWWmVhuj58qe1WvAF1GRbSRoBFKn+PlQmyV",
+	"pKgxWUF7elHYUhXlOnnPHtL3syMM3Ec1GyDOmYk2gMVQ4Tdv/ZREeiVAO0mNd39hdtepDXmEp8grrCeJ",
+	"shtBL5IFyv3+UUsJplvZRXYsxpVUdJi3SZ9gh57hTGoks0kwZUWPUvxR9mnPUoK9NxmduIbt1tQULDFB",
+	"zbzVWVRcZDBiawQtAwTb2Dtqy1QLAtnfrqA48LmUyhdzN14OpITR6Q9jouRB4MH4uaQ0X2BKR4i7urmB",
+	"PfZqcDH6mJrJFpQ2FkaqVTqFaaYtUbOZN84KibDpSvzmbtd3QOXHW5d77A3gcY1dfb2An0CBSFcW9ofe",
+	"pZk53rpRzYyrpJS4cOZPjVh4ZfxZiacCKp2NH3pfnSRo/1I3XjMUHghr41VZ9CrB9SjQyVQ7koNu/Sn3",
+	"rCbFix/RDqMV6eAYXKcDLYNguoV+cVhxwZ17/CJSzcfdY+3yUeV7blmmi2ccQWzsqWD07MDHXmETN6aI",
+	"mYwpzMHYVmWNiFjSq0gFodHdXI7u5sj4XhbElb4Bjjzypm+MnG9ypTHOPUI39XjMoC38Npx6yuE3TvBJ",
+	"pKFvwbclDj3rCHmMFJxMN3QnOzG1hBVwtX37I1EsRDmM9e+NNgaVS83NtI9Je0F7r+ydsFzbpNs7LWe5",
+	"k3k4EMd95yLqOf+2/36ZHs8pm0ADWBd9/y2/2z0PqkcP7yB97WeUpG5NFftScCM2lA5lr5iBzVG12Ixa",
+	"aIvkcTQCBQ94z9w5M7i4xmRo1LnKq/RGamOvJaz4cBqrXHwlYGh08yXZQh3GTZOR1+stLGVb0OPHPhcc",
+	"fd9mxNKaKlMrMh1O5MK0XmW0UEHPqa1u6Hu2tGNL1WlLHQE9V2hOy/47NTiwNmdjm2d6bL0is6WOPNvj",
+	"/ROH+RmU7uB5yvU4yuyU6XBfHse9mMnxNHHuVvXf44o4dipshJv2Om0uPBmYSv/hV47u90b1VAzH6HvA",
+	"+3rKHfLGPoFGMcbGOfG9aNg7+RaOIezpi65iKnjw/dsXDzHxpCtbTWS6ggASn4Lkd/z03nL49F7gATpE",
+	"yV09uneRf6JH98rBo3uHr3T6c3uatmKP7elodnaAOU8meRzq/ktujbEZ7cwc5zPKjbEvo1HdmNOomQ5T",
+	"pFiPsvHrTtY67qcustQTkbdSR7xnpbE+CcppqQplWrXEjyG0JWsrEwroWNx3xhj640XeElEaCU1ClfYC",
+	"bxRL9cq15sJWh1DvCXGp3dJRE5Yd1mfyUWiftxjxdo5qCUpJ0G1GHacx8TlVZp66blEfEvLiqWwA85p2",
+	"/wUbKn/KhU7pRXP1glqvdpFFJZqCijz0sESJ1lnJtop9/bOvdF/MLgRpVBw4zmvdlx3GYYlZkIfxtAVy",
+	"wIoPIn/8xReff2mX+ztjV0MkBQNl1LKUOQ62PfM1PrO6CUxMbyVwsSHLinqlmpU10hsv1JwKNtswrv2c",
+	"SQRIeL3OYnU4Bj6w4JB6jQou0IP9id7nxPhCyzqdottUDB2UbOZX/fAzSvz4NC8YOYdicaswiN7xiDEO",
+	"e0h+D2fDZY9MD1NZ4muHkwxrUqslsoES6UVnwxGut6VA3c7ywOG5yZqbbVsf661hka/nBCCGb2c744Wx",
+	"Tg2oyGaNmggnt6MyaTUuukpbqA4o7zfAz6kLV6j23xpmQojCoShrjMQIK5uccx3WLsOdPuy5t6c9nPoY",
+	"Z7xFNdztBQNxv2d5Bw3cP0hDnH+gyOUlaWNYfgqQTzdjqvo8O1GmpZkqMjxbt+1WPj0+vrq6OtJ2pyMg",
+	"wuMVZTmAWtdl62M9ED815OYCqy6qPB9y4fIGBJhMTt68JJ2paLHCwewlpkGQfctQ1uzx0SNOIRdVui3g",
+	"hydHj44+Z4ytiQiOuc4Cl7ildSCJkGL0MqdU0QvhVmqgot5Ui4G6P370SKNB3Roct87xz5Lpe5qnyZ2G",
+	"kOwj4gH5IR46jwoMSeRddVHVV1VC9VJo72S32aTNDWUqYmiaTABkdGbwuskD16YotX+ccYbd7Cfsd3z5",
+	"+NiJr+n9cvybdm0X+Ycdn4/1C7wOjn2oX9X1BVbKd59w9t7hfqovXHLefy973nsWryw5HdAEEkoS+9Wq",
+	"FPyIN1nRymJTtIFijF75TT8/AWMBKCiOsqzhXlGROmwARkZ7RGnxLvmUtDQVJazfINfvR/FjvE9/7OPD",
+	"3FG0G77AX5FkZ7qW/8wieOZKQZBXwn03c3CYd6dCs8lHckAuksyRhuCXTjQ3FgRC4iwwm8OvQ2+ncJ0u",
+	"zHq3sx0l76RwimHWF5RTwtcJHTmvazmaThHAcIjZXlhQNcvN2lN6I9wWj7MPQGwKVa4tNrlpsCdmwiBw",
+	"pa0+DOn1Dhh0g1vC4MzOVYMx5PgI1Bi2DMMRePviWfLkyZMv1SMYeHNkLMdA4yH1U9cWOCPSMOLavIQ9",
+	"IRwFICAATo0IndRqJ/rN3t/VymnET77wn24pwIZFqTRrn5gvwR1eQuOQNqnruo0mfpqSvOy+4A0jcxTO",
+	"2LVxF+91uyDWEpCcUgWNAWMqKhUYQRalTXpBhqOK02dUXJJmTjrPF/mVMaorDqdY/ATDjhUMPgJ+Cipf",
+	"Eb3gg5XkLHaGktwXzL3S4rqtEx0V/hVG8WJXXA1ARx55f0MH5fD5MPLpWNUmGesegZnLMB7/xokRLMuc",
+	"qaRIG9Axf4ONJAjCagjGPSxa3DpuH0rKwowdOe89dwM/UGVQ+j9pH+/evpLzJJB65IUPkm8Am5M3Th6p",
+	"as5UycBJby5YsU2aTocuLhagz56XYsGAzr2DoCua53WmYn03BaqDbNb4Cr0JoD2rjudIyht0S2CuFHpK",
+	"hjoMtz1joTqquPxA1tZa4e8orLngJozqLLbOEFeSUDRFEbx4W5OBekJDhn6K1b1EQkxYcWZju/TB6/Hp",
+	"X37PCtXdsm8FWfiJO6IKxJohpBi59E129nLus/ce19OzH8rkeBstk+PYmeOrtGhR/qm8WhK7ijPEz/4P",
+	"KdZLY+cOO9D6CoByq5FhEEiLyqPP1VWDL1ZcbalFFOkGqkAw3Tm04NDNeYIUbiVX5jEIuJdVtU7xxbLy",
+	"ZqJWc4PMe2lDefVXlOff8LWkB/PwQCOGXtQNCdMThGLXuX6r1449GfDI4baVjHccoI9F0jsUiLPYHlH+",
+	"bgybNhZZYZ35CGEDNTaqLbdH1dq7Fe5hkejZ7H9rr5XspSiC5lLvs2+iEdcpMk2yzpCqqKY0xh1lIUCu",
+	"p3kWA+P8oo7lh58+/B9bpTZSaccAAA==",
 }
 
 // GetSwagger returns the Swagger specification corresponding to the generated code
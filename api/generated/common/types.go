@@ -83,6 +83,18 @@ type Account struct {
 	// *  Online  - indicates that the associated account used as part of the delegation pool.
 	// *   NotParticipating - indicates that the associated account is neither a delegator nor a delegate.
 	Status string `json:"status"`
+
+	// The number of applications which this account opted in to, i.e. of its application local states. Not returned for accounts at a past round.
+	TotalAppsOptedIn *uint64 `json:"total-apps-opted-in,omitempty"`
+
+	// The number of assets which this account opted in to, i.e. of its asset holdings. Not returned for accounts at a past round.
+	TotalAssetsOptedIn *uint64 `json:"total-assets-opted-in,omitempty"`
+
+	// The number of applications which this account created. Not returned for accounts at a past round.
+	TotalCreatedApps *uint64 `json:"total-created-apps,omitempty"`
+
+	// The number of assets which this account created. Not returned for accounts at a past round.
+	TotalCreatedAssets *uint64 `json:"total-created-assets,omitempty"`
 }
 
 // AccountParticipation defines model for AccountParticipation.
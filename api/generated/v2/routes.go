@@ -24,6 +24,9 @@ type ServerInterface interface {
 	// (GET /v2/accounts/{account-id})
 	LookupAccountByID(ctx echo.Context, accountId string, params LookupAccountByIDParams) error
 
+	// (GET /v2/accounts/{account-id}/activity)
+	LookupAccountActivity(ctx echo.Context, accountId string, params LookupAccountActivityParams) error
+
 	// (GET /v2/accounts/{account-id}/transactions)
 	LookupAccountTransactions(ctx echo.Context, accountId string, params LookupAccountTransactionsParams) error
 
@@ -48,6 +51,12 @@ type ServerInterface interface {
 	// (GET /v2/blocks/{round-number})
 	LookupBlock(ctx echo.Context, roundNumber uint64) error
 
+	// (GET /v2/search/{kind})
+	SearchText(ctx echo.Context, kind string, params SearchTextParams) error
+
+	// (GET /v2/status/wait-for-round-after/{round})
+	WaitForRoundAfter(ctx echo.Context, round uint64) error
+
 	// (GET /v2/transactions)
 	SearchForTransactions(ctx echo.Context, params SearchForTransactionsParams) error
 
@@ -74,6 +83,7 @@ func (w *ServerInterfaceWrapper) SearchForAccounts(ctx echo.Context) error {
 		"auth-addr":             true,
 		"round":                 true,
 		"application-id":        true,
+		"count-estimate":        true,
 	}
 
 	// Check for unknown query parameters.
@@ -177,6 +187,16 @@ func (w *ServerInterfaceWrapper) SearchForAccounts(ctx echo.Context) error {
 		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter application-id: %s", err))
 	}
 
+	// ------------- Optional query parameter "count-estimate" -------------
+	if paramValue := ctx.QueryParam("count-estimate"); paramValue != "" {
+
+	}
+
+	err = runtime.BindQueryParameter("form", true, false, "count-estimate", ctx.QueryParams(), &params.CountEstimate)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter count-estimate: %s", err))
+	}
+
 	// Invoke the callback with all the unmarshalled arguments
 	err = w.Handler.SearchForAccounts(ctx, params)
 	return err
@@ -234,6 +254,102 @@ func (w *ServerInterfaceWrapper) LookupAccountByID(ctx echo.Context) error {
 	return err
 }
 
+// LookupAccountActivity converts echo context to params.
+func (w *ServerInterfaceWrapper) LookupAccountActivity(ctx echo.Context) error {
+
+	validQueryParams := map[string]bool{
+		"pretty":      true,
+		"limit":       true,
+		"next":        true,
+		"min-round":   true,
+		"max-round":   true,
+		"before-time": true,
+		"after-time":  true,
+	}
+
+	// Check for unknown query parameters.
+	for name, _ := range ctx.QueryParams() {
+		if _, ok := validQueryParams[name]; !ok {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Unknown parameter detected: %s", name))
+		}
+	}
+
+	var err error
+	// ------------- Path parameter "account-id" -------------
+	var accountId string
+
+	err = runtime.BindStyledParameter("simple", false, "account-id", ctx.Param("account-id"), &accountId)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter account-id: %s", err))
+	}
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params LookupAccountActivityParams
+	// ------------- Optional query parameter "limit" -------------
+	if paramValue := ctx.QueryParam("limit"); paramValue != "" {
+
+	}
+
+	err = runtime.BindQueryParameter("form", true, false, "limit", ctx.QueryParams(), &params.Limit)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter limit: %s", err))
+	}
+
+	// ------------- Optional query parameter "next" -------------
+	if paramValue := ctx.QueryParam("next"); paramValue != "" {
+
+	}
+
+	err = runtime.BindQueryParameter("form", true, false, "next", ctx.QueryParams(), &params.Next)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter next: %s", err))
+	}
+
+	// ------------- Optional query parameter "min-round" -------------
+	if paramValue := ctx.QueryParam("min-round"); paramValue != "" {
+
+	}
+
+	err = runtime.BindQueryParameter("form", true, false, "min-round", ctx.QueryParams(), &params.MinRound)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter min-round: %s", err))
+	}
+
+	// ------------- Optional query parameter "max-round" -------------
+	if paramValue := ctx.QueryParam("max-round"); paramValue != "" {
+
+	}
+
+	err = runtime.BindQueryParameter("form", true, false, "max-round", ctx.QueryParams(), &params.MaxRound)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter max-round: %s", err))
+	}
+
+	// ------------- Optional query parameter "before-time" -------------
+	if paramValue := ctx.QueryParam("before-time"); paramValue != "" {
+
+	}
+
+	err = runtime.BindQueryParameter("form", true, false, "before-time", ctx.QueryParams(), &params.BeforeTime)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter before-time: %s", err))
+	}
+
+	// ------------- Optional query parameter "after-time" -------------
+	if paramValue := ctx.QueryParam("after-time"); paramValue != "" {
+
+	}
+
+	err = runtime.BindQueryParameter("form", true, false, "after-time", ctx.QueryParams(), &params.AfterTime)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter after-time: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.LookupAccountActivity(ctx, accountId, params)
+	return err
+}
+
 // LookupAccountTransactions converts echo context to params.
 func (w *ServerInterfaceWrapper) LookupAccountTransactions(ctx echo.Context) error {
 
@@ -1019,6 +1135,88 @@ func (w *ServerInterfaceWrapper) LookupBlock(ctx echo.Context) error {
 	return err
 }
 
+// SearchText converts echo context to params.
+func (w *ServerInterfaceWrapper) SearchText(ctx echo.Context) error {
+
+	validQueryParams := map[string]bool{
+		"pretty": true,
+		"q":      true,
+		"limit":  true,
+	}
+
+	// Check for unknown query parameters.
+	for name, _ := range ctx.QueryParams() {
+		if _, ok := validQueryParams[name]; !ok {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Unknown parameter detected: %s", name))
+		}
+	}
+
+	var err error
+	// ------------- Path parameter "kind" -------------
+	var kind string
+
+	err = runtime.BindStyledParameter("simple", false, "kind", ctx.Param("kind"), &kind)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter kind: %s", err))
+	}
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params SearchTextParams
+	// ------------- Required query parameter "q" -------------
+	if paramValue := ctx.QueryParam("q"); paramValue != "" {
+
+	} else {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Query argument q is required, but not found"))
+	}
+
+	err = runtime.BindQueryParameter("form", true, true, "q", ctx.QueryParams(), &params.Q)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter q: %s", err))
+	}
+
+	// ------------- Optional query parameter "limit" -------------
+	if paramValue := ctx.QueryParam("limit"); paramValue != "" {
+
+	}
+
+	err = runtime.BindQueryParameter("form", true, false, "limit", ctx.QueryParams(), &params.Limit)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter limit: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.SearchText(ctx, kind, params)
+	return err
+}
+
+// WaitForRoundAfter converts echo context to params.
+func (w *ServerInterfaceWrapper) WaitForRoundAfter(ctx echo.Context) error {
+
+	validQueryParams := map[string]bool{
+		"pretty": true,
+	}
+
+	// Check for unknown query parameters.
+	for name, _ := range ctx.QueryParams() {
+		if _, ok := validQueryParams[name]; !ok {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Unknown parameter detected: %s", name))
+		}
+	}
+
+	var err error
+	// ------------- Path parameter "round" -------------
+	var round uint64
+
+	err = runtime.BindStyledParameter("simple", false, "round", ctx.Param("round"), &round)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter round: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.WaitForRoundAfter(ctx, round)
+	return err
+}
+
 // SearchForTransactions converts echo context to params.
 func (w *ServerInterfaceWrapper) SearchForTransactions(ctx echo.Context) error {
 
@@ -1043,6 +1241,7 @@ func (w *ServerInterfaceWrapper) SearchForTransactions(ctx echo.Context) error {
 		"exclude-close-to":      true,
 		"rekey-to":              true,
 		"application-id":        true,
+		"count-estimate":        true,
 	}
 
 	// Check for unknown query parameters.
@@ -1246,6 +1445,16 @@ func (w *ServerInterfaceWrapper) SearchForTransactions(ctx echo.Context) error {
 		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter application-id: %s", err))
 	}
 
+	// ------------- Optional query parameter "count-estimate" -------------
+	if paramValue := ctx.QueryParam("count-estimate"); paramValue != "" {
+
+	}
+
+	err = runtime.BindQueryParameter("form", true, false, "count-estimate", ctx.QueryParams(), &params.CountEstimate)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter count-estimate: %s", err))
+	}
+
 	// Invoke the callback with all the unmarshalled arguments
 	err = w.Handler.SearchForTransactions(ctx, params)
 	return err
@@ -1298,6 +1507,7 @@ func RegisterHandlers(router interface {
 
 	router.GET("/v2/accounts", wrapper.SearchForAccounts, m...)
 	router.GET("/v2/accounts/:account-id", wrapper.LookupAccountByID, m...)
+	router.GET("/v2/accounts/:account-id/activity", wrapper.LookupAccountActivity, m...)
 	router.GET("/v2/accounts/:account-id/transactions", wrapper.LookupAccountTransactions, m...)
 	router.GET("/v2/applications", wrapper.SearchForApplications, m...)
 	router.GET("/v2/applications/:application-id", wrapper.LookupApplicationByID, m...)
@@ -1306,6 +1516,8 @@ func RegisterHandlers(router interface {
 	router.GET("/v2/assets/:asset-id/balances", wrapper.LookupAssetBalances, m...)
 	router.GET("/v2/assets/:asset-id/transactions", wrapper.LookupAssetTransactions, m...)
 	router.GET("/v2/blocks/:round-number", wrapper.LookupBlock, m...)
+	router.GET("/v2/search/:kind", wrapper.SearchText, m...)
+	router.GET("/v2/status/wait-for-round-after/:round", wrapper.WaitForRoundAfter, m...)
 	router.GET("/v2/transactions", wrapper.SearchForTransactions, m...)
 	router.GET("/v2/transactions/:txid", wrapper.LookupTransaction, m...)
 
@@ -1314,173 +1526,194 @@ func RegisterHandlers(router interface {
 // Base64 encoded, gzipped, json marshaled Swagger object
 var swaggerSpec = []string{
 
-	"H4sIAAAAAAAC/+x9/W/cNrbov0LMu8Am+0Z22mwXrwEWF2mywQabdoPY7QIv7sPlSJwZ1hKpkpTtaZ7/",
-	"9wueQ1KURGlm7LGT3s5PiUf8OCTPF88XP81yWdVSMGH07MWnWU0VrZhhCv6ieS4bYTJe2L8KpnPFa8Ol",
-	"mL3w34g2iovVbD7j9teamvVsPhO0Ym0b238+U+zXhitWzF4Y1bD5TOdrVlE7sNnUtrUb6fZ2PqNFoZjW",
-	"w1n/JcoN4SIvm4IRo6jQNLefNLnmZk3MmmviOhMuiBSMyCUx605jsuSsLPSJB/rXhqlNBLWbfBzE+ewm",
-	"o+VKKiqKbClVRc3sxeyl63e79bObIVOyZMM1vpLVggvmV8TCgsLhECNJwZbQaE0NsdDZdfqGRhLNqMrX",
-	"ZCnVlmUiEPFamWiq2YuPM81EwRScXM74Ffx3qRj7jWWGqhUzs5/nqbNbGqYyw6vE0t66k1NMN6XRBNrC",
-	"Glf8iglie52Q7xttyIIRKsiHN6/I8+fPvyW4jYYVDuFGV9XOHq8pnEJBDfOfdznUD29ewfxnboG7tqJ1",
-	"XfKc2nUnyedl+528fT22mO4gCYTkwrAVU7jxWrM0rb60Xyam8R23TdCYdWbRZvxgHcVrkkux5KtGscJi",
-	"Y6MZ0qaumSi4WJFLthk9wjDNw1Hggi2lYjtiKTY+KJrG839WPM0bpZjIN9lKMQqks6ZiuCUf3FbotWzK",
-	"gqzpFaybViADXF9i++I5X9GysVvEcyVfliupCXU7WLAlbUpD/MSkEaXlWXY0h4eEa1IrecULVswtG79e",
-	"83xNcqpxCGhHrnlZ2u1vNCvGtjm9ui1oHjpZuO60H7CgL3cz2nVt2Ql2A4SQ5aXULDNyi6zy4oeKgsTS",
-	"pRVcej/JRc7XjMDk9gNKbdg7YRG6LDfEwLkWhGpCiZdTc8KXZCMbcg2HU/JL6O9WY3etInbT4HA6QtVq",
-	"JmPbN9iMxOYtpCwZFbB5TkvJaFlO8MuyJNywSjulxrJGmKAIrHROClYyWGQrDuBXbZTcwOI1s+1kbViR",
-	"ycY4pFjL0g6o53AiOCx+joRPKXNaakMNG1WI4pVsWXTJK26Gy/2e3vCqqYhoqgVT9sA9bzWSKGYaJcYm",
-	"xxG3IGpFbzIlG1HsoHIYIlXM0nXNcr7krCBhlDFY2mm2wcPFfvC0ilAEjh9kFJwwyxZwBLtJHIolLvuF",
-	"1HTFojM5IT863gJfjbxkIrAgstjAp1qxKy4bHTqNwAhTTyv7QhqW1Yot+c0QyDO3HZa+sY1jgJWTvrkU",
-	"hnLBCssbAWhpGPKKUZiiCfdVMRZUs7/+ZUy+tl8Vu2SbJMvsIwAuJ9xp1vYL9p1eRZhhC0nuiIdL2ce/",
-	"SdzbCe+gUYZEn5Ch9qtjCen7Y6f/DjfIeG7NVxn+PEApvjq3YmfJSxBJv1hM8tvQaMuCuxvhhZTmK0FN",
-	"o9iLC/Fn+xfJyJmhoqCqsL9U+NP3TWn4GV/Zn0r86Z1c8fyMr0Y2M8CavIZBtwr/seOlr13mJiw3NYX/",
-	"nJqhprbhJdsoZueg+RL+uVnCrtOl+m2GF5qxmVN3jndSXjZ1vJN55w6+2JC3r8ewC4ac4hpAYbqWQjOw",
-	"ErxEYfnB/WZ/soyBCeB7kbw7/UVL0OfasWsla6YMZ7HNw/73PxRbzl7M/tdpayM5xW761E3YqtBmjOEj",
-	"mlPjCB0J3JE+U5aBVXVjUG1L0VBA+o8Btv6c7bHIxS8sN7hBXTCesKo2m6cWYAe7Ptxuwf9Bj9lj3xzI",
-	"VCm6eeB9RBGYgSgbjvyjVbcs/6vpigtY+Jxcr5kgFb207IAKadZMEXsWTBsvDFGBRPkYjDVOojql8mSW",
-	"opjEmep7H2p7aoc417bt1hONmj4qNRxqu/Rh92sPWuju3JEegB7inbwvTdhr0He0pCJnhzjlhRtq5xP+",
-	"ngsOQPwDr2LHY/bHHLbyEEd8CAK242wlWGj0uCIfpjzEJulD7dIeDM7v1xHnw1neG+O/K2V+eaeznDoq",
-	"GHXLzP9gtDTrV2v2APNHY2+B4ry9RBwAox8UE6P7zrb1R6vaouh0h90TeaJp9Je+e18OHXe2fHf21znT",
-	"PhPc/Yz1fod86+/N8cU44RN0/nsu0Hpl7+TUEOpcXGj8uRAX4jVbcsHt9xcXoqCGni6o5rk+bTRTTrk6",
-	"WUnygrghX1NDL8Rs3pcdYz598GI4aOpmUfKcXLJN6hTQvTIc4eLiIy1X8uLiZ2KkoWVkZ46cLs4+2F6i",
-	"hyiHE2QWM2RjMueszBS7pqpIgK6DdRJGRu/P1Kxz4sZGI6pzhrrx02RA61pnYKXPwEyfXn5dl3b5sfaM",
-	"pn1ij4xoI5U3kXLtoYHz/UEaZ3ak1wTxizSaafJfFa0/cmF+JtlF8+zZc0Ze1vU7O+aZheO/nMnQ0tOm",
-	"RrfJnreedrCUkgALh/PM2I1RNKvpiunk8g2jNZz+mhHdVOBRKksC3TrejlrJlaIVmLx1uwC/H+MHgHDs",
-	"JsuiFcLizrCXd9GnlwCf4AihDVmz0hnb73Fe0dXjzse15foyERRwcfER/P3+ZIJ/cEW50F4qaL4Slgic",
-	"K3XBSG61AFackLdLAlxt3unuAnocxwysg2v0fpJzu0YwnZOcCvCK1gV4CbkgVGz6ZkjNjPFG3w/skm3O",
-	"I8v7nmEHzslGt4jEorHDBbHYnjC5pppUEgzSOROm3Di/XQI108A0XBh0QeToG80s/o4xDaCayD1rCSdm",
-	"IW6MPiJG3kpa12RVyoXjNAFFXwQc9X3Gmcp7C4A+AENJ3jX8NkzQXk1VYiOQEEe24A4LtePdiwwnl3dn",
-	"lFtypcEnzKiTETQmkTtgnnNYD0H595qBViYVEdL0UEp7kk4hffBozWc1VYbnvN7NOomjv+/0sYNsE+1J",
-	"YS6XfZk9EKlJEYKNswXVafHN7BeLgY3GYAa7Rs/o/EyoLcMKTggEQjpSXZQQ3xBir/CMqYLAC79sjEUa",
-	"Ay1NF0yJVqfyYHR3JFbe1lT7GAwIVfEsYic1ZwR5z+0GAAJbuomwN9ZbuZ23ZFd0bP/HnYFvRWF5B9Pd",
-	"eJTg6vNipU/+8+CAxhhT7xL0fkDv/JvN93LkzWdWx2vSxyEF6HiWula4cGzsEcWB9icdHZCF41/LZckF",
-	"IxnhYbUGVovxQzLnGETTUqKbg9krwJ+JxTY7wM4jpNA4AruWssSByQ8ypk2x2gdIwThwE+rHBrYS/c12",
-	"sMmEYF93udh6CRjyjpaI5q1fHI9xeHML7rf3fTaWvJ91WhFssnD3jUhcpVDUsqbcXvCFbiCGzMhclieD",
-	"i5lmJQNOn3U4a2YvYUmdjgEanvlu0aWNPOFLq2I9jVi5YiuuDVPuwg4QhtCCNnJiY5iFjBrDlJ3o/z35",
-	"zxcfX2b/l2a/Pcu+/d+nP3/6y+3TPw9+/Pr2b3/7/92fnt/+7el//kfq/nglDctA3GVXtEx5rS8uPtpG",
-	"bzSo4m9AMibZT2erCAb58RFDBkx7yTZZwcsmfdpu3n++ttP+EG6vullcsg0IGUbzNVlQk69BCnWmt20m",
-	"pi7p1gW/wwW/owdb7264ZJvaiZWUpjfH7wSrevxkipgSCJhCjuGpjW7pBHuBm+drVho6HXwONgXLMA09",
-	"mbLZDIip8GNPqV8RFOOcF0dKrqXrhh5fBRcFu4EwR26imE49WNGu6jLYEpGbRtPY25kb4cHV4nh1sWrs",
-	"Rknrxu7jPZY3HH7X5Y2wF1rXvLjpGafwwNLsA05vn1sfXh8HCAaE4wbbglyRIWoYLmakYt6YhtQSqSMY",
-	"+CzitQ3JqA293e1gvAB3kcCyCUpUb5oHQ0A2jBF2a0/hIlkqWQHlDW9BEXLyEf2+g4KtyOnN6lKZhvhi",
-	"mSeE2G+1xzNa/pNtfrJt4VRtbwya5mJXkmmvO9CTcGHkAY7mfpbFFOa7Ebdg/vtAbEmsh5wXtO50HAV7",
-	"EgCtayWvaJk5++sYo1DyyjEKaO7NtY8s09Nndf73l+/eO/DB0seoQov85KqgXf27WZUVblKN0KlP0rDX",
-	"Mm8W6wsRZ3/lumOzvV4zF24fXVqsuHbIhVTe2uMjjuBsuEuv3O1pkXWuA1zihAuB1cGD0Jp+0IHQdRrQ",
-	"K8pLb3Px0KY5Ey6uddvszZziAe7tfIh8SNlB2c2AutPUsYUTxTNMpAFUmEqiiXTh/uGyBDckMOAAglZ0",
-	"Y/EGPV9DliSaKrNEl+mS52mrnFhoixICHUq2MYHGI3ctO6Jl6OmxGh6NZZvpHaJ/ekBGcyQ308cwje3d",
-	"QjqPdyP4rw0jvGDC2E8KaLFHnpYafSLanfXohNkZE9YeUZOGCffRoV1i1b0WF0a5iyZtlePhpO7U3HrC",
-	"2d1HibZDjanPAMS0Bh37Bgfgvg7GKo9FwalJRceNskeIQTzjQMuYCA9wxOdYRSO4c7He4XS251l7bd0l",
-	"4KXZxaiofTkuZu34ewjYVp4CYLEkxZxAWmqZGKYR11QYn1nodsv11gwti7bXtVTaQCpqMmhmr+tGnLF4",
-	"r0uGzpZK/sbSRralxYPr4fTRxNg7PfjOl4UeZxi5NISTGUeUbcgYcj7vC1K4ZN4bqL52EOzqbZkBj/vx",
-	"cY0ymLErSvSRdANxRoQY8JrI3Qs3Ou+ioAKZyysoXNBxgKZZVByhdYrjtyzKwTw0BNDrBc0v0zcFC9PL",
-	"Nsih40wxkvjOIa+3e14nJIqXCG25Bhyvmaq46Yq8llDvqvX/3thRzitaptX/Anb/vKNQFnzFMUW50SxK",
-	"0XUDkVpyYRCLCq7rkm4wjKTdmrdL8mwe8Td3GgW/4povSgYtvsIWC6pBMWttPb6LXR4TZq2h+dc7NF83",
-	"olCsMGuX+60lCTczMJUE7+WCmWvGBHkG7b76ljwBv63mV+yp3UWnbs9efPUtpDXjH89SAs0VM5hivwXw",
-	"X8/+03gMjmscw6oKbtQ0P8ZyNOOcfoKasOsutAQtnXDYTksVFXTF0tFQ1RaYsC+cJrh9evsiCiyfAIol",
-	"4SY9PzPU8qdsTfU6rQshGCSXVcVNZQnISKJlZfGpzXrFSf1wWIsBeX2Ay38EJ3lN0oawx3XxYQJpatUQ",
-	"yvADrVh3W+eEaqIbC3Ob3e4Y4glxWdIFkaLcRCZA2Bs7F6gqVrEGQ+2S1IoLA9aBxiyz/0PyNVU0t+zv",
-	"ZAzcbPHXvwxB/g5SyQkTubTzi/0Af/R9V0wzdZXeejWC9l7pcn3JEyFFVlmOUjx1XL5LlcnIbGlomQ4K",
-	"9Ry9HxM8PfSumpcdJRtFt6aDbjTi1PdCPDEx4D1RMaxnL3zce2WPjpmNSqMHbewJ/fjhndMyKqlY18i9",
-	"8HHaHX1FMaM4u4L41PQh2THveRaq3OkU7gP95/WTtzeAoJZ5Wk5dBDA9abgd9ud42WPmBCkvLxmruVid",
-	"LmwfVNVx1L6SvmKCaa7HBehqbTHHfrYiL7L+wNBkwUopVvrxMd0DPuKIXTHgSW9fb4N6MLAv9pJB0/GN",
-	"se3sFO99cRgc2rb/HBIpBDZuTXz74NqOxyFaMYaR7K9c3DmGyXRdlrjeawo+ASYKVOuA/a0pFyPBiYwV",
-	"I4FWDGY8k8pwDNZg7DOETRleMW1oVafFLBjJkRKBqi2goYu9jWiWS1FoornIGWG11Ott6XIjaR43AiYr",
-	"uUaRExcAzaXCkiCgUxjZS2XaNdB6MmmrC2OmpDRjgILyEWfbSWkIbcyaCRPCGxnUIeuvBEOx4caBAgVZ",
-	"Fvne8nhfTIWW5WZOuPkTjgMBVCCPK6YuS0aMYoxcr6VmpGT0irVF52C0P2lyfsMLDSXlSnbDc7lStF7z",
-	"nEhVMHVC3riCQHALwk5uvmcnxCWhuPDM8xsByyskwytSvE5cpo+nDX6beMVzFKD9n6FSm2blFdMn5Pxa",
-	"IhC6TdzTVgnp9Fg0BgPYC75cMqBTWA5cnqBf+yGCCcrnQRG/MKxb02egthuRgX48cok0aKm4Ea+wEXFR",
-	"311nWI80KryxeoQqWbFiao4mVdh2XrE2UdPqblKZ1mCzZBgMbTkbF0bJoskZpgeedfAxAosPQAoVxaL8",
-	"G8AhX72whdMbWzxPtRdyUHCfoZolZHeFcHbsiimyYExEAz1BphPBpQ1VkM/BIOsIl8qKp2nm3NQrRQu2",
-	"mw8XmOCP2COktfkRruR+A/xk2/fVpo5u0pH4aSkdBSRbKRPz8hQvG1W9PoxlCbzBooyKlRi+DfX8oO18",
-	"oFgtGcs0F2nr55Ix4O00z1lt0Tmu18yYZVSoxAKrgLwyL1vtCQvDrxgGlk8oA1lOy7wpMYByQtJf57RU",
-	"XZdRyZZGWgSLy3i2JkFu51pAACeW0sP5lGWAUQ9LURZNN64F3p585TpLHKoX5zBM1chKdsXSdxpGMWPj",
-	"H/KaVFRswlnYKVow5kgvQCoBctRVwImOp/2ju9hF4CMxOaybBtIexcjmFvE510xxWfCccPELc9Qc2JLH",
-	"GCxgKYXhooG6n4q1cKOcIJB80k8wGWKAGkuhtR+60deCXXdOu4j0uW6ssjb0kiHYPk3GicZdz1QxzYtm",
-	"xJSpaN6FbD9kdMT7gRp2qsLR6gPhZY9DBSKfIro+LvfQpndaw10a5VMd5rsLs6IhMYI4Rp0I33S5+b7l",
-	"yN1HGuktTj43NYx9xZTuBgZGNkB2s2Vs26IzPlYsUBLtC/vPkvmQHT063wbZcYtzXvnC5DLoz1zMSGIH",
-	"R8o5BAD0NTf5OhvJhbBtsYWF4UP/pjWcElUIoEK2XLLc7AIDBNVjJdhRKPCzheI1owVkQbX5EZgZ0Qfl",
-	"yQ+S2KF1pNcIzUELbdUaGOXpHmXOAoZsQ/6f5I64fyXhf+Ai3YEMvCLjzj5t9sQ2Dnna5DpKNkzDroRC",
-	"oxGN1FLTMu3h8ZMWrKSbqSmhQXfSoNh6JxfKHGplmBUo7IblzUi8bjS1o7OpyW2T/oIDeQ6pIi6e2T/J",
-	"vyslVVyapef0FoTZFsSXv8RbjYTvvtpDyF7vHqD9FqVytHNWTGu6YunyvDEu+oYpFPz7FS1H8k0+sFox",
-	"bTVdQsn531++c07IsayTfDRJihqXAWkoGU1Pvp3DTS3N2zCmD767wuxJA+xYHB+G8dnPg953i44YK+MT",
-	"bagPCx0C9E8f+k5qyp2HvU25Ge6sS8MaJsbtEj7fHnB/ES65CQZJrSQu7jTEaLKGz1j2IeD1HuhbLLIQ",
-	"lJsqgjyfAcl0C/cM7909Sw/XWcVXCrhletRxsonMiFu4ewf23qTtDH681OYOagwmdljzqi7Rret0BCvR",
-	"415kr9yvNtLu4QM3Dx0T9uBRXezOLsXDB3PdFZbtWdLTgVv/Eq9kVZdsnJHX6JDHxyBQVkMGPi0K7mSZ",
-	"N+7IPG9Ua/Xrh2b9REuORbo1ZOELKWv7r5WJwv4H0qhkY/D/jCr7H6wJ0/0fYlWUsm+HmsG5cDFz1V1k",
-	"Y3yA+8wqCQVeUVzfVEr/HVMxdzJXD4VEgpVNhtZ3hDOcTIlG9jZdwFIlfFnBlzgrgSAgEB6i/V+aFMww",
-	"VVlteS2vSdXkawjEpyvm4/Ih5gVMtb2JOqP78L1ufolzd+qa5jgQhkSVVK2YIi5KibgSoyHUqaK891RA",
-	"PxABLs80JTi3ZQsMH7gANSfKGUgkJXgwLtnmFKU4/H4HxjGeejACGCQgPCBI98pjiFNhtuDrZUcBwgJP",
-	"neyhAP4BFSELn6O1PRWhYZLPrsuDdQA5NJoN17m7eyve2wSraNe2qxY/3Nxx5dssdlG+05VabHfQ/nFD",
-	"fPWkxL3tsXR3XKcbw82bPPVuGdD+C0rAlDQUrHNPHOWyqqQA81RZ9nyDoiAQLaXhzSNBmLhipaxZsjVs",
-	"0g7hy5qvBCvMjcC4iDP48/xGpNrG4hdaR8tLlX2M3rC7Wz3UXn0vDCPH9+XuOmIb6N2O6J82vPuIbzAa",
-	"NYwIQy2Zus+Y526MHUrtrYTCDEYMx+Y+OAkUJzzh3pOZPmDJl+DzYdfBj8t+bWjp/NQCvMLnEHqcXzKB",
-	"1fXCy35GEiZ0o5xb2MIK41lQ3DAyFrq6bXLXOnvZVO0qBSbzYI13wWgQRo9drTpQ2MOR07W7bHsuVtlE",
-	"dlEO6UWuoU8fBTvXZBk1O7hFQlWxYsfc89grBil0vv9EjhGWAGwfkkwnl0UPLYlhpQby5O3rpwTKsIwV",
-	"xIheYN2+7Lgm324QYYTjAJZ+MuE+UCwZG3NF9qI3yJKNCJtt1YSWV20hIWjVNx9vhXLHcLR/UA2VgVxz",
-	"5zb/QmPQOkC6d4CGQ8XJz3tXm5nPVko26ZClFSbk94IpQVkHRQgDafSafvPV16dff/NXUvAV0+aE/Bsy",
-	"hlALGtYp654m4W39s05BRQKAhYxbVGdctEQ059od6CAqhruoCRjm8U84WcUjWh08MzvsJYyiyOQyuVwm",
-	"E5X/Bb+3ZhTleZ9iw93dgfvhi1Z3lL7/xOewbuezLeWzyqtQOetuBF6ysbKQ5U0CTZ9/nbWYekLe2d6E",
-	"iaVU9pZZNcbKWnis0tv5YuzBzBbTlsiFpBbxG1MSLtGCSHtn7ssaHm02RGLQHPRg7cKJLAwhIznEfD85",
-	"A61hjkA+xTta4j3uRhiOaobdxp+iXawtg7dA/3vNywQW1NJ+1zEccyIkweLvcUuMm2sztBBmFxXdQaTH",
-	"Jae4KkORthFZTICYiXdRRZz2hp6vqWirWXfL6WCQEzq6ogqBPZzc5+WuLo/tXx+FHImuEK7Qm9WRIY0o",
-	"GFoed7truqmYMHdkCu+xNwZu4GOz00qoGlFCfe9tZWPHHpG0Y9uPIY01aPtgUkNGFK1xPqJ6tw8HuxLZ",
-	"rfqEyGWl1LKB4L8oXtKb1NytIphmL9mGKG8miCtSouZ+B0UfJUb6ne5zXrFWNUZdIiWF+U7Swj13n7xa",
-	"YeQ3crM/TSwnDDONFXoEK7DvNE6EU9gDbc9Cn+5DkUMDy6ZmXT92pypuN3ATrpkn5HUIqAUTPIaWtVG2",
-	"aNLoG+oxLTVkCXMVv+6Mpkiw5V9cfKzRrZ8gXNcAxbxtMxT4rgnNl6tQWz9hO/DNbpZMte1S93ffcql+",
-	"axsOTQe+2fBZhg7nmR/iDc40DbljzmCCRJDWrHt3mWPRsE7RSUcRMc616LPF0DVZudHFooBxPxJWHT1l",
-	"l6T7yP6JqfftD69oWZ7fCJwpEWHQPluZck1hMVSXZRC4pmWtzjvljRmOYmNDOs1zq5EUbRRjBOefNOlX",
-	"S3KPcw/qJXUE855cM/GURsA/qlaj6wY7xlBr4jmhatVUaPt9+PVtWcFooUleuASnYbVEpwkh6TeKFUQq",
-	"l9rAly5vZaxSy47V6/AJEnjqt9W42sDKEUyfW12d1a6OgBRZHhynVnbBO/WSXKDD8WJ2Qt5iGLRitEAm",
-	"qrhhqTpqnfVDTuY1K0swGyNGZ+F0o1KLJ5aKOnXqNGC2YvDSSN8B+zuuzEdr3Yyc2BhXcsFWnUP6DCf0",
-	"ys7kRgqHlFMhpPkdndOelfl6by1FYQJ1HUr0lUz4J79QF4ZhR0x3UjG+ElPvoyypFwS6f1xJcdDlUi79",
-	"Kj54PZASQUW+GxMFgzwOhs8g0CKTotykuGucatdjr2EvJh9JCcl3ug0t0W6VUZ2X3Zbo2cz7aIWA2HDD",
-	"fH/Y9d2hkOK9qyf2BuhwjW19O/EzE0/kYuZPd+htmlnk/JrUzLDoSGkXjvxJsczLT8+xRIH1SJo2HOdC",
-	"vCS/MSXdBTIMZQmiNZm6pHSXL3qS6BSKB+lBt/6UexZnwsVPaIejBd4uLj7e0IGWATDdQ7+4W62+rWf8",
-	"ZqQ4TnzG3oPiquHcs+oVzjixsWNPAF5cfFzSoujVCYlDdJDJhDoXuNuuShAgC70eKcgzeZrLydOcGL+T",
-	"VHDtb4ATj7f4GyOmb1z7HcceqbDF8RC8to7acOpdiD/4lHdCDX8Lvi9y+Fkn0GOifiOt4E72MpTmdcDJ",
-	"AN8JcSzE+V/978rbVsql52beZeOdir3Xc/C5ZFLR+qDVIbcyjwjicVc0G3VEt6k6TjD78aIqBDBA6/Hu",
-	"v9Fzv2e//OjpE4Sv/QQNGpcoaV8AVKyC7KL2ipk4HFfaLKiFbc05dO6DLz4OIdbRDPFeE/LWjkzLa7rR",
-	"3nbaItb4cH5XsZZJwm4Xpx+iwTe9NyoHJ9IHlvOaw6OGXS4YcHzc4jjyqCRaLi3TwbwofhWMFi6GmLbF",
-	"AruOIu8ncmXPaCSg526badm1FuDA3jps27zyY/sVhSON5NkOzzQlikiGLd3C85wnb5LZOdPhvjwOeyGT",
-	"w2nGuZvovwkz4icRtpE9tO+puuzIQKq7D7phsHxn1I6KEYW43+GNJ+ddeN8+wwMhu8HW/xNT6Oz7QEUh",
-	"K/KmEYgFT3768Oape+jZI5lPyLfI5yD5gp9/Wg6ff0o8gmS35FAPP10Wn+nhp3Lw8NPdV7r7k08et8Ye",
-	"fPLB4ehPWnFtVMJE/PgVrKbYjPcNTvMZ58bYl9G4bshp3Ex3U6RQjxp5INuEmkU9EXkvdaTzXCQ1+Pa7",
-	"dnUnW7WkG5LXVoAVIbIusrhvDdnrjjfyNIfTSGASKFyXeHtQu9crPReO3inG53mwcm0ZqQnLRhS6t4Xt",
-	"axETzsNJLcEpCb7NpB9yTHzuKjPPYi9jFxLw4rng+vBKZv9BGKgminVD4aVSfCSzXwqo3Ur3PH8iabWU",
-	"K55rtFXs6+585/vezmdVUxp+x3G+933R/5qWmBw8jGeGioKqgrDi62+++erbdrlfGLsablIy7sQty5nj",
-	"qOF5V+MLq9uBifmjPFnJIcsa9UqpVWukD16oOdQ/bqOi9nMmASDp9UaL9dENiw2hEapLq+CWhrc/ze1v",
-	"a6rXLevsPldOBSWOX/WjuSCP4vM8CBQRRXavqIIeeYwxjpZIvgTaiNkj4sOuLPH7iJMMSzy7JaKB0uKL",
-	"Ty6Dva5LZnW7lgcO6SZXm9rIU380KPL9nGd8+OxFPF5616EB1KyUVhPBXHGrTLYaF1ylW6juUC1vsD9n",
-	"MVypUnprxbSFKB2KslYXFz+nlU1MYU5rl+lOt3ue7VlvT7s7jvs2quHWlwjE49LyFhx4fJCGe34LgcBL",
-	"0MZyKQzNQW/EIsqzl860NHM1e2drY2r94vT0+vr6xNudTnJZna4gaSAzssnXp34gfLknTq11XVy1O8uF",
-	"y43huSYv378FnYmbkuEb4OwG7FsBs2ZfnzzDjGwmaM1nL2bPT56dfIU7tgYkOMWyBbMXn27ns9Orr0/j",
-	"oJJV8iEmRlW+xouAa3sC2cUMbzdvi9DojVQv/XDOQYDvmb74OPbojCVZ+/evDVObma+EHhtMWrfVkDy2",
-	"543ihV5j9KJpFEaOJmYsecXNntO1RY3oikWznZAfNYsqB8pLCMBHZdGHGfvCd6HTCGB2iBRcLcIOUx5x",
-	"zU5RhdA2KryFeQUpJ+AcEFHM5EmnKpczSbpnDFwJg3xDGlFa7cCb2cE7psPSoGAbZvfn1O2Ay3XxAZva",
-	"aT2JhfpJMgdhZiHc80RcbWu42YAoiB709xcfh6HzUI4h9o/P2yewnEF6TkKBg54lde782/6Z1OHro+g9",
-	"H1uwi37NaFmmlhn5VPY74dI9fPKFHq+d4l5n6yPbIrele+0E1qvdS/dWQxgDpk1KHKesrfFq05/HwPcc",
-	"yXuL27crsFgdlLCtmYIhRQ4Gaw2Y6W1cyFV9wELBNV2UUM4dL7Adb/co8oUKm3ucQFz2YZx19/38EzP8",
-	"DE8wQOUcEEBfP3vmpawzSkWjnf6iUX1qBxyPj9wnOSCl5vn6ZZMJjqH0LPoV8FzBTmQna8y47/XGZCAV",
-	"hiP/qF00V01XXLiIBTD1VPQSLDoC00RcwJCnTp/PakVNsHY74eQwZgeLSyu9uxvwc1Ir6kL+BAIHnqIK",
-	"Re3F+ONMg14w+/m2p22cfvKxYry4HVU93kl52dTBhhVXzB9oINjWneh3G0DPSQ0kWMY8tQMyW0UpwuUA",
-	"5CzeKKMatpdE3pX2D0ir/zMl4YMwjD3YxAOyhTQpHowSS6CPLZR42i9mvwtZ9r0bE3QZl5bfRp9HXb6X",
-	"Tm5nWfIbh1veXZ3LXnkgAeUqfS3FJBTg9oLB9lZ/0IA5pv2Er5+SE/ssjHjSA6SSpLaNr843td2HEoI7",
-	"f7G75fGnad0ygf/6ZKFgj4BEHs1XJAvWcftLhT+BxeWMr+xPJf4Etl60dKXWrvlqfPEaulX4jx1vp0U6",
-	"OowW0jVzLzYuST19Fmnl7IsUYn5KaohUUc3oduqKuyrNY9OHBgcBAQvs9mGgN1tg8A321bsfxFjSX1m0",
-	"JnyCxPDK3hsdo6GCfHjzijx//vxb96Ke1RgQXcYWjENiImQMXGAYBTXh8y7s58ObVwDAWTAg7tRq66EG",
-	"jDrUymHEL2/hf2DT0B/SZvI5Lzm4aqfaO10YM8On1ZOQP/6IN4E/yHV/+FrV/V+XGin87uVcZ8KDXV6i",
-	"e+pOjou4/bjvottq2n9xaFPYH9WU/Ye85h3YjNGjht1sn936hEf7Zy9H7QFtoNEkp5+6PGK7LbRbPTVp",
-	"a2mbpO2gKR2gz6m26gFH0+OhaHZPSn08E+QDGR5Dsu5WqQ0tp4INcKgtovooSP9A9tI3YPBDe5/P9PbS",
-	"AO/2Ie+pDUNOXr3CU88Hnd2OPrpa2rMWHGC+RnAzNp/9tt98BzFEHZiRBnaym9pjmx8VnqDweA76QKoO",
-	"DH/6ySPGdvXG5bZud/TahrurN3H+3VGxeVDFRrsqqTtR4SP6U2HKeyH6fPaXZ3/Za2sm30DpPJl2e3u7",
-	"XWmKCOnUvROy1UsLFUb7NcGu1xLwLH6xaJLQ/GRHVeuoan1Gf+DRffE/3X1xMOF9WKkWc9ud9MzB+3ZH",
-	"ldO/P9PKkoc0MMSycp+opk7hvLikzKQmegxsOgY2HQObjoFNx8CmYwjSMQTpGIJ0DEFq38cR5aaNAhqU",
-	"JY6ri1hAo5obMct3BfnHUD2UGXykZK5XslpwwVot2K+gTc8y0j0T3H2NwDeE4n7e1bVlXZmS5Yh89cXu",
-	"Q4mU+czX9afK6rm7yNvOajyAUCAmmj+ulbvX2qCOG5hpiA/9QlwWdp/LckOMewWNWmXQr2RO+JJsZEOu",
-	"gVhKfgn94Y0gjCersIp1NysOSuA1oz4W1z0LVf+2WX4e3pp8jJc7xss9cLwcvDKjTz/huzR48dzqiwlv",
-	"8aVuvd/Zj9tuuogGOF069jQG6HHtO1Pnh4u7417vZGyIohym06dCrMPRwnC0MBwtDEcLw9HCcEydOtot",
-	"jnaLo93iaLc42i2OdovdIlYe19bweyscdLRmfHnWjPnsmwPe6CeD7fpRq53yv5+str89bpXYi1E5eIsk",
-	"ZTo57zyluj141V03ds/M/R0Rx90etN4H7b6sEM9HxOrWLgUvaagrj2LdMqXshlZ1yaBC6cyijusfCpzm",
-	"sqqA8sMvbuToF0dBtz/f/ncAAAD//0XYbNub+wAA",
+	"H4sIAAAAAAAC/+19aY/bRrboXyH0LjD2PLHbsScXLwYGFx07xhjjZAy7kwAvzsNliyWJaYpUuPSSXP/3",
+	"d5ZaySqSUqvbzkSf7BZrOVV1tjpb/T5blJttWYiiqWfPf59tkyrZiEZU9FeyWJRt0cRZin+lol5U2bbJ",
+	"ymL2XH2L6qbKitVsPsvw123SrOH/BQxi2mD/+awSv7ZZJWCopmrFfFYv1mKT4MDN7RZby5E+fpzPkjSt",
+	"RF33Z/1Xkd9GWbHI21RETZUUdbLAT3V0nTXrqFlndSQ7Q7MIFhaVS/jZaRwtM5Gn9YkC+tdWVLcW1HLy",
+	"MIjz2U2c5KsShkzjZVltkgY+nsl+H0c/yxniqsxFf40vys1FBoDLFQm9IH04UVNGqVhSo3XSRAgdrlM1",
+	"hM+1SKrFOoLZR5bJQNhrFUW7mT3/aVaLIhUVndxCZFf032UlxG8ibpJqJZrZz3Pf2S0BwrjJNp6lvZYn",
+	"BxO3eQNHtaTVwBpXMEERYa+T6Nu2bqILWHcRvXv1Inr27NlXEW9jI1KJcMFVmdntNelTSJNGqM9TDhUA",
+	"oPnfywVObZVst3m2SHDdXvI5M9+j1y9Di3EH8SBkVjRiBSdDG1/Xwk+rZ/hlYBrVcWyCtlnHiDbhg5UU",
+	"X0eLslhmqxboHbGxrQXTZr0FpIItii7FbfAI9TT3R4EXAn4VE7GUGx8UTe35PymeMoMWNUwFM4b3oimb",
+	"JI/d1nNcuvpD8VlgHhdA0/CX2r4SWEeU5Dnwr5Wo58iXseEyq2D/8LfQJnVg82DDRQnMKykIOxdtVYli",
+	"cRuvKpEQE1jDl96C3kmo6nXZ5mm0Tq7oBJMNSTPZN8K+jLFXSd7iYWeLqjyDDQWmJXEBOHACQ0Vq4qgt",
+	"cuS+OJqkqAgG2FblVZaKlBZ+vc6AKy+SmoegdsDoYXMAkYBK0uBeeFc3QrC6E8K1137Qgj7fzTDrGtkJ",
+	"cUNoHC/ysga6KkekrhKkQDyRLSeNCK53k8HROSyQJscPrH/Q3hVImjkoNQ2dK0wHv0dK4sI2LaPbso2u",
+	"6XDy7JL6y9Xgrm0i3DQ6HEc9QB0rtH29zRihLKlvAT/JBzg/HFvWiE0t1TNk8jRBqoXCHDYsF7RII9jo",
+	"V2Bt5S0tHlYDv5RbaBWXbSORYl3mOGA9pxPhYfmzJUbzcpHkdQO7GFTt7JWMLDrPNlnTX+63yU22aTce",
+	"NgebXommrYrQ5DziCKJukhvAtLZIJyhPTVRWtnAC4brIALfSSI8SgsVMMwZPVuwGj1HpLHDUIEFw9Cwj",
+	"4BTixnMoSFz4haSJdSYn0feSt9DXprwE4a1YUHRxS5+2lbjKyrbWnQIw0tTD15aiBKEN4y2zmz6Q7+V2",
+	"IH1zG8kAN1KPAJWpSYCfpEpC4nDMK4IwWRPuqixdAN/9z7+FNAXztRKgq3lZZhcBeDn6drbGL9x3eBV6",
+	"hhGSnIiHsIYO/g3i3iS8o0YxE71HhuJXyRL8N2Gn/4S7sD13na1i/rmHUtnqHMXOMstJJP2CmKS2oa2R",
+	"BbsboYQUDFkkwKvE8w/FX/GvKAadEBAgqVL8ZcM/fQsDZTAJ/pTzT2/KVbaAnwKbqWH1Xiip24b/wfH8",
+	"F8jmRi/XN4X67Jthm2BDwKZK4BzJYkn/3Cxp15Nl9duMr2ahmX23pzdledlu7Z1cONYE4COvX4awi4Yc",
+	"4hpEYfUWBKEge8cZC8szGPsqa27fyW/4CRmEKIj/WXLv9Je6JL3OzAEsbiuqJhPSisND0YaihMb//Acw",
+	"DQDjf50a+88pD1CfqrlfQ2PkABLkpKpge7Va2YTkAlMDSAPmB8wHJIcQFfK5zbZtWLvrojtz+Jg4dX/k",
+	"71GbQPIGPp8VtPo5zAJMfZNcIrYnwBDXIH6QvgTeLpjXs37E7F9bVaTAkDrTycyHEIZOfzLb2N0Ag0rl",
+	"xS9i0fChupA/Epttc/sYFygP+CAHSyONHyc3u9+T6+0WT3qYzaoPt1v1DmSg9+1PSgHwg+fa34fqm51t",
+	"AMuq3PC9MWkS1DUiVNwzGGcBWhtZeVmZhuVqeI3FgH/rWyPSqAQdG28YtCdFacwTaBi+SrI8ucjFdPyt",
+	"74zABkMPgcOm7Sj2Wk0flPIPtV31YfdrB7p3d+4o/Yge7J28K03gnf7rJE+KhTjEKV/IoSaf8LdZkREQ",
+	"/2C7wvGY1THrrTzEER+CgHGcUYKlRg+r3tCUh9ik+lC7tAODU/t1xHl9lnfG+K/zcnG511kOHRWNOjLz",
+	"P0SSN+sXa3EP81tjj0DxnozdB0BmiVJ+Kx+ocos1IkRaLtqNIKPyBSIEfRA164h0GVeU0DnELtZ3kEHN",
+	"vuPpnxt7wAG24F7p0DJdjJ2+taoRNc8ddv/Nqz/33Ttezj7F5cxBr+mCzsHfEcIfwOeducGPSda8KivC",
+	"tPtHaOSLOXq9miiDLagaZfGeR1mDWwtY13VsC4WDqi06GvHnawCd4hvgtNrmfq97H5Vd1DZ8eqJXZKRZ",
+	"VrB3Am2uQL6JDMZg4/6H4kPxEt2xGX5//qFA3D0F5AWcPW1rUcn7xsmqjJ5HcsiX0OYD+o476lQo+oy8",
+	"1BKabXsBB4hxLD7SZPd5f4QPH35CX8uHDz9zRIVFjZZTXfp/jA2tTw88QYykBucUy7CauBLXSZV6QK+1",
+	"94lGZu/+0KzzSI7NTjIZtiPH99Mo4HQdkxc2Jjesf/mA+Lh8+0LJrltiN8Bqykq5wJAtMDR0vt+hP4xw",
+	"N7mOGL8wTKCO/nuTbH8CQH6O4g/tkyfPRAR3+Dc45nuE47+lSwiZLADtaAcTDQFmMJ/eTAtnbgxcu0pi",
+	"YqTe5Tci2dLpozeh3VDEAHBe6uZ4swElV8D8mSebBaj9CB8AwzFNvbNWSIt7z71UMJl/CfSJjpDaRGuR",
+	"S2fqHc7Luo3vfVwjN/qB8DVYFUWmqZPR8R+rJCtqpSqg1wyJQIbKoMsWFWNQFaLXy4i42tzpLiWuZJGa",
+	"dWQ1R7dE57hGco1GC2DLGPWyTSkKBNA/KW67biZYX6Oceu/QaXpueVZ3DJCTQRTJiJ6Utjic1pXMCYOk",
+	"qKNNSQ7HBawOlAIe0oOafmBa+Mwu5gXHvsSIvyGmQVRjhd8g4dgsRI7RRUQrGgWaR6u8vJCcRqPoc42j",
+	"qk+YqbxFAOoDMBTv9VttwwDtwQ54NoIJMbAFeywUx7sTGQ4ub2+UYx0TzhHumpJIbBLZA/NkQFIflB/X",
+	"glR12AJUn1yUqhVJ+5BeRyzMMZoedOZsO81gz6O/dfrgIGOi3SvM4a+OzO6JVK8I4cYxav1eBBT4BTGw",
+	"rTlYDdeoGJ2aia9QtAJ5X5CkCuo9xknpKGE+Y4yDs7aKo2ZDoPnpQlSF0akUGO6O2MrbOqlVjB2FIioW",
+	"MUnNGVDDWZVGurGw19ZbM5w3F1dJaP/DwR6vAbQFqvluvKEO5VBipUv+cx1gxNkQKuRDxXmo4A4AZ5dA",
+	"DQAVTrj1H0dZkI6H1LXihXNjhSgStL/U1gEhHP9aLnOMqIxh09RqG1otx4eWi4yDJA0lyjkEXgH+GiG2",
+	"4QCTR/ChsQX2FoiZB46Ag761kXQXIAuRETdJ1NjEVqy/xcAFn7Q6jojMikDwm75FODLRw0JpHGIFJSD+",
+	"iTjBThlG73UjKSMZSokLN/d9xG1NvnQL2yaoA6gIK8/NnRdBImr6Mlii7bQAJ1L0QGAPaya7bb0c69CQ",
+	"BZSFqft5GKi6JnWdQiUvwqMX1r6cMwx/bmL0mOX0rQw6UuRtV+R6bQlOq4ibXMi7saVa+dgpot4CDTpF",
+	"3VI8e1MugEf0jAg1EDZpJbGjBcRoMPDePwSxzPeqm2VgiB5lS7wOPLbUjkqsshqAlGdFEOrTMVGct5Q3",
+	"scWUlAon+n+P/uv5T2fx/03i357EX/3v059//9vHx3/t/fj049///j/uT88+/v3xf/2Hj0tdYRAqqWbx",
+	"VZL7IuhgedjoVU3XxlekxXlFpbNVESccZAEyoGkxcDTN8tZ/2nLef77Eab/TxFC3F9CPFCKRwNQX6Dsg",
+	"jcmZHtsMTJ0nowt+wwt+kxxsvdNwCZvixFVZNp05/iBY1eEnQ8TkQUAfcvRPLbilA+yFrCQvRd4kwyl9",
+	"JDtRuMM9c8i+2COmVI09dFWwoAhzXh7JvxYrvtOTYisimK26JblRaL6n4h+jpeD7TmdRAYvn2UZxzQ2q",
+	"ehHexjDDo8KfyLnQFpmM2mYRTje6JfDfoJFrcmYlkxpaKkChyUM+nbHESbkPDnBzneHIMwH0nBvrn4K2",
+	"UFSIax6S/Rcrh0rDWapr7TLg+EnhtBd+lveP8lpq2PK04LrBGmrpWnezYo4WfgQcqGvpnQdOuj/DK4Ey",
+	"MtP5E3rEEi96tF92gh/aDKwlSWNWjfYwdwD/xsF/Kw+twUVBnkw3t1tasQf0p0HjQ3/EgUEC+aPn8Ku+",
+	"ErNzBVcMCAOX0hpum0Bg23KxDqh33iB0y5Ulo837twXvtfFc3hNtpJirxPhtcotO9LnM0TPIzX8ziqu/",
+	"GL/nqOfGSE5zlCIxiwxDBay0TYi6UIohn7DR8aydnavweRrKy8rcgMgwJ4CrmrghJyQyG6vMQZePTbVS",
+	"kQ+TFUOb4STaDHfv1ih7dbZFSo7iN0nJj3dYXn/4qcsLaEowQZbedHxCfGB+CqHT28XYylbbHgoSasnB",
+	"RpDL8v/0s3DQY1Xbl2bbCsD5pIW9tr7wNBmN0w5GX944wZL4uCRxd5p7Q0ARMhh4cZFDCuiW3mP2FnJm",
+	"AbNa0hPphJmdWWWtiz6+IJuizOXReAGR5P8Utz9gWzpV7K1MFlNJRvRMFUr03ulo7ubQ82G+HHEE899q",
+	"YvNiPRVFYKeK45/fkQDgY1XCGcXS7RliFNBIMgpqrrykD3w98Z/V+Tdnb95K8MnBJpKKHeGDq6J22z/M",
+	"qlC4lVWATlXuO1pDlTeqK0Sk2zOrHVcpEI/MYrbsLyiuJXIxlRs3uMURpOt02dH4pjpCpceelzjguRdb",
+	"7bg3Hhf227u+eh3JxDo3Q+vnTLw4Ey2xM3OyB7izz98K3YgPym561O2njhFOZM8wkF294Qz9Wl1DjBGU",
+	"jD3kNyEE3SS3iDcccNJnSdAvRqKLawDA7wwrLmpEiYLjOLBxRI0DN1ccERm6f6w2s8bCZvUEQ2sHSGsO",
+	"72aqaPrQ3l2UMpoP7uS/tiBVUzhu/FQRLXbIk67s8mK8tx7t8fZyHZAH1KRpwl10aFmv4k6L06Pso0mj",
+	"ctyfVJ6aXI8+u7so0ThUSH0mIIY1aDskpwfuS2131+YVFUtk7E67RvbZM061UaFuIYlPsgq2Sa3d0Jqp",
+	"pzNeiEtp69JbFbAZhUTtWVjM4vg7CFgjTwkwW5JyxG6S16VnmLa4TopGFWyRuyV7k2lHWmGuSzQBYYUf",
+	"vx1rl+uG7d670yWjjqHhb8LvL1giHlz3p7cm5t7+wSdfFjqcIXBpyDpuzT2QUZfSuStI+pJ5Z6BCliCr",
+	"Dp3Cffu4ggwmdEWxPkZu/GtAiBGvsaKs6EanIgOgEQ34goxijoXQz6LsCPJTHt+wKAlz3xCQXF8ki0v/",
+	"TQFhOjOxhU4MA+CL6qyd4O55nURWmKJuK+PDAQYOzvcS6r5a/x+NHS2yDUzh3fyUdt/1qqfZKuPKT1jg",
+	"0FQ+kgNF2zLDQEnEojSrt3lyy9GbZmvgQJ7MLf4mTyPNrrI6w2QIbPEFt9DOEm3rUV1webDMdU3Nn05o",
+	"voYtBfKDLryxsK36ZkamEh00dCGaawELeELtvvgqekThUnV2JR7jLkp1e/b8i6+oWhT/8cQn0GSNuCH2",
+	"mxL/Vezfj8cUL8ZjoKogR/XzY7ZZhzn9ADVx1ym0RC2lcBinpU1SJCvhD0LejMDEfek0yYPd2Zci5ap0",
+	"pFiCJPTPL5oE+VO8Tuq1XxdiMDCMD9aBDgKqZlduEJ9MMSGeVA3HJe6Y12u41EeKTdtGfkPYw0YrcF0e",
+	"36opgvC7RPlu1Lai+yOqW4TZFA2TDBHojYtPpez9MiZA2huci1QVVKzJULuMtgBIQ9aBtlnG/ydarIH/",
+	"LRrX3emCG1+A1OyB/DVV6IpEsShx/mI3wB983wGlRXXl3/oqgPZK6ZJ9o0dFWcQb5CjpY8nlXaoMBtH5",
+	"czEUR++m4gwPPVXzwlHiILq1DrolFqe+E+IVAwPeERX1enbCx51X9uCY2VZ+9EhaPKHv372RWsYG6yw6",
+	"Ru4LlR7l6CuVgKHFFaWF+A8Jx7zjWVT5pFO4C/SfNuTH3AC0WqZo2XcR4ET5/nbgz/ayQ+aEsry8FGIL",
+	"kJxeYB9W1XnUrpK+EoWo4V4SFKCrNWIOfkaRZ1l/aGjY5bzEENUHx3QFeMARC58R7tcvx6DuDaxqaMbU",
+	"NLwx2A6neKtqbvLQ2P5TSCSdTzBaguGdbBsOH0ExxglkL2S6F4d+uC5LXi+a/zCLpUhZrSP2t06yQKxJ",
+	"LUQaiBkVNOP7EnBTxtWITxABisEadZNstn4xS0ZypkSiagRUd/HGxtTrSUng/aluCposz2oWOfYLEYuy",
+	"4kqLpFNgGoqTQTw1v2kwqdyFMcYAzBCgpHzYlQ8wWBNzFNFsq7IKRKTCnOyVcAYU3ThYoDDLir5FHq9q",
+	"VGJVacwA/0stA5FKGVq2EdUlOqfg1gKoiSWp4bZ0JUwtbxoNup3fZCnXa8/FTbZAJ80WUDkqq1SABvJK",
+	"1lmlWxB3kvM9oWhyYbIizm8KWl5aCr4i2evkZao0Fu23sVcsg8u6P1MB7FrkV5imcH5dMhC1KUpQoxLi",
+	"9LhoG84bS7PlUhCd0nLo8kT9zAcLJqpKTrXR9bByTZ+A2m6KWMYT+nGLLRU3xQtuFMkwftcZ1iGNjSzN",
+	"IBEqF+kK48B0KQikV1M0A3U34DnGYLMUnIOEnA0Dusq0XQjOyn/v4KMFVtYDSRdqtoLdCIdUUXgDpzK2",
+	"KJ6KF3JScJ/o6hLOCunsBBa6uEBrhhnoETMdCy5gS1RD4UJQsi8vFW4cfubcboEsUjHNh0tM8HvuobPJ",
+	"1QgYjbzLAD9g+67a5OgmjsT3S2krtwKljM3LfbwsqHq9CyXnveJa9xSGKvmuFLzznmK1FLCPWeG3fsJH",
+	"4u1wORRbRGf7QR9BD0uwEstxqChdlWzFEwZmAxhA+VwDygAGNi7anKOLByT9NbSrXJdRLpYNVVKxX0cw",
+	"JkEMkswuWg6TXKo9iCtkgFYPpChE01vZgm9PqiA4EsdQZKscNIcR/HcaEBskeDBMeIMZ5uoscAoDxpzp",
+	"hUhFQ866CjnR+bS/lxc7C3wmJol1w0DiUQQ2N7XPGfAjK1MQO1nxi5DUrNmSwhh+F6CEQy5aek4ByEHD",
+	"zXIiopzPbl5nHwOqUOUK/OAmkhTi2jnt1NLn3LQLoKhLwWCr7FQpGqeeKUihLG0Dpky4KrqQ7YaMknjf",
+	"wQJPK3209YHwssOhNJEPEV0Xlzto0zmt/i4F+ZTDfKcwq0TneEWSUXvCN2UNHNUycPeBj8ripEpC6LFh",
+	"a2s3MNCyAWLNqcGxsYUzPlePAiDJvrD7LLEK2amD890yOzY4p5Qvzumm/kLGjHh2MFBaSwNQgzK2WMeB",
+	"tC5syy0Qhnfdm1Z/SlYhiAoF6HeLZgoMlB/ED2wEoeDPCMVLkaSUfGxSvTjJqwvKo+/KCIeuLb2mALwV",
+	"la3W0CiPd6jApDFkDPl/KCfiPgCJ/yMX6QQyUIqMPHu/2ZPbSOQxOe1JBD/Rruj3GywaATROcr+HR02a",
+	"Aty3Q1NSA3dSrdgqJxfLHIwmIYEibsSiDeeAqKklnQ1Njk26C9bk2acK+02C7kl+U1VlZVcV6zi9i0hg",
+	"i0i9KsC3mpK+qyJLumiMe4D4zVsyEVTCOlkJ/6snNi6qhj4U/AbYSSB17h1skEBPLe4LRpxKJ2QogW4R",
+	"zPdMGll4AFY5lL+DNzU/b+OYPvou37vyGmBDcXwcxoefe733i44IlVS0NlSFhfYB+qcKfceULelhN9mD",
+	"/Z2VGaX9HN8p4fPmgLuLkHmaNIhvJXaZ0T5GR2v6zNWWNF7vgL7pRayDcn1vy8xnRDJuYcFAfpWx9MC1",
+	"apOtKuKW/lHDZGOZEUe4uwN7Z1IzgxrPt7m9ateeHa6zzTZnt67UEVCi272indJYTaTd/QduHjom7N6j",
+	"usTeLsXDB3PtC8t4wYfhwK1/FS+AgcAZBRn5lh3y/MYey2oqfANTZVKWKeNOuYCDN1a/bmjWD5juTYHf",
+	"NRW/KUoQxvAvysQC/0NpVLAl/H+4I+N/uBSb+z/GKqtSDg41o3PJipksqgYDqQD3GSoJKV9RZF9fJZ09",
+	"s8onmav7QsLDygZD6x3hTCeTs5HdpAsgVdKXFX2xsxIiBoTCQ2r1F0Y/NxglU2CAzXW0adGo2ACurYSK",
+	"y6eYFzLVdiZyRlfhe25+iXR31ttkwQNxSFSOzxZXkYxSimSxex3qtEmyzgts3UAEVRR392yB/ruBpOZY",
+	"OQOepAQFBojPU5bi9PsejCOcehAAjBIQ7hGkO+Ux2KkwI/h66ShAXFfRyR7S4B9QEUL4JK3tqAj1k3ym",
+	"Lo/WQeSAsYi9dU53b9l762EVZm1Ttfj+5oaV7+ZiivLtz3TH7qT984aoooWee9tD6e68TjmGnNd76m5J",
+	"9u7DtMSUaqoTK1+ORfcF+kZK+tH1DWI0J0ZL1fSULNwGiyuRl1vhbU2bNCF8GV1hIm1uCo6LeE9/nt8U",
+	"vra2+KXW1vJ81Zateh771abvlNW0axfsO6IJ9DYjckDoXUZ8xdGoekRVb+EuY57LMSZUuF0VFWcwcjh2",
+	"poKTSHHiE3axQwcsqcq3Kuxa+3EB2UEPYz91QV7hcwo9Xlyi+wW9Mfrpd/QdFHVbSbcwwkrjIShyGKdE",
+	"SW2a7FveNh4qGVmRyVxb42UwGoXRc1dUB1I8nHK4RAm2x8JlA9lFC0ovkg1V+ijZucbqjBAaVxvQ+qfl",
+	"ntteMUqhU/0Hcoy4vp0pquNPLrPery36lRqiR69fPo6yZfejlcanFPSsnrBsu7rdNIg4wrEHSzeZcBco",
+	"vPVv2BXZid5AR1RgjJHCaMsrUxONWnXNx6NQTgxH+weGo4F6J5tLt/lnGoPmABkqeOMkP+9cOAv6w077",
+	"Q5ZWnJDfCaYkZZ0UIQ6kqdfJl188PX365X9iJoiomxPMXMDS7UJmnXTKg7qnGWWm7KhbHYkA0xm3rM7I",
+	"aAlrzrU80F5UTCajJmiYhz/h8fJF3l5YBoiZXFxSdadg1SdtRqkU76tEf3cncD9+KHhP6ftPfmUY8+aH",
+	"KwHmV7oI4H4EnotQNeb8xoOmz57GBlNPojfYGz7CfHjL3LQNylpxQ0k8bOezsYczWxpTmZ6SWorfRFXS",
+	"JRrjKxaiX3bL2myKxEgWpAfXMpwIYdAZyTrm+9F70hrmDORjvqN5inqBuMxYzcBt/MHaxS0yeAT6x3WW",
+	"e7BgW+L32oZjjsFB/BCPU5KMpInJ0GKYZVS0g0gPS052VYbUbyNCTKCYiTdWRRxzQ1+sk8I8IuGW0+Eg",
+	"J3Z0WcVOOzi5y3u5Lo/tXh+LMhBdUcialagjUxqRNrQ87HbLkmh7MoW33JsDN6i+eDWshFYBJVT1HqvW",
+	"jgaApvSPjR91GqvW9smkxozIWuM8oHprF7V6mcKoT4xcKKWWLQX/WfGSyqQmbxXaNIt1RytlJrCL67Lm",
+	"voeiP1qIT6vGrEv4pHA2SVrwDcd/teLIb+ZmfxlYjh5mGCvqAFZw32Gc0KewA9q+130ogrktUG3xSLP3",
+	"oglfNZqyZAsrQsqZJFxMGrZ32WK9QKWYYAZu1NfeCJd67I7CksteQqXlnmlu4rBFCD64jnener4baUr3",
+	"4pPopY4AJp8Bx8KZsGC2wXQ9C5xHq9OaQY5JWw2Cz7ZTcj5gJBDHIXg4jWzAegm26WsoskmyWK70Gzwe",
+	"Y4dqdgNAm3Y+g4Nquax+Mw37tg7VrP98k8MqjWsEljdTKhb6hQBg/AcBwn9huhkXc+27RPxEL485pgk8",
+	"UWUz97I15ypnTsFfScI2kRj0GbHMDZaalMEz5I2wpKujWE2pEmAZbLlWgPnhRZLn5zcFz+QJiTCv2/t8",
+	"aVyIWqZFaDaPskC605T1RbIY2/KPUTF1rZypHQ3iL3XULe/EwZj9Ak++ZzmnsnnPk1sa/4DnBNdNhpe+",
+	"mpctgC5X/ILoQ6xvZAXByphZKjOy+uUdpeqmyhCjU7eSuRjZUibahErLTCy3x0+VvSlXsF1aRTSRoAFM",
+	"n+PlQmylpCgxWUF5elHYUhXlMvrAHtIPsxMM3Ec1GyBOmYlWsIu+wm/O+imJ9FqAdpJo736sT9eqDXmC",
+	"VOQU1qsJsytBL5J5yv3+UUsJJtu6DZxYiCvJ6DDnkD7BCb3AmeRI+pBgyoIepfijnNOOpQQ7bzJacQ3b",
+	"ra4pmGOCmn6rMyu4yGDA1ghaBgi2oXfUlokSBHX3uLziwOVSMl/MPvi6JyW0Tr8fEyUPAg/GzyUlaYwp",
+	"HT7uaucGdtir3ovBx9R0tmBtYmFquUqrMM20JSo289ZaISE2XYnfHnZ9e1R+vHO5x84ADtcY6+sE/HgK",
+	"RNqysDv0mGZmeesGNTOukpLjwpk/VSJ2yvizEk8FVFoTP/ShOIvQ/iVvvHooJAhj45VZ9DLB9cTTSVc7",
+	"qnvdulPuWE2KFz+gHQYr0gEZ3CQ9LYNguoN+sV9xwdEzfhWo5mOfsXL5yPI9dyzTxTMObGzoqWD07MDH",
+	"TmETO6aImYwuzMG7LcsaEbIk14EKQoOnuRw8zYHxnSyIa3UDHHjkTd0YOd/kWu049/Dd1MMxg6bwW3/q",
+	"KcSvneCTUEPdgu+KHGrWAfQYKDiZbOhOdqZrCUvgSvP2RyRZiHQYq98rZQzKl4qbKR+T8oJ2Xtk7Y7m2",
+	"SbYHLWc5yjwsiMO+cxH0nH/Xfb9MjWeVTaABjIu++5bf3Z4HVaP7T5C+djNKEruminkpuBIbSocyV0zP",
+	"4chabFotNEXyOBqBggecZ+6sGey9xmRo1Lny6+S2VsZeg1jh4dSucvEVj6HRzpdkC7V/b6oFeb3ewVK2",
+	"GT1+7HLBwfdtBiytiTS1ItPhRC5M65VGCxn0nJjqhq5nSzm2ZJ22xBLQc7nNSd59pwYHVuZsbPNCja1W",
+	"pI/Ukmc7vH9iMT+9pSM8T7oeB5mdNB3uyuO4FzM5nibM3Yrue1wBx06BjfDQvk2qS0cGJrX78CtH9zuj",
+	"OiqGZfTd43096Q55a55Aoxhj7Zz4QVTsnXwHZAhn+qotGAse/fDu1WNMPGnzRiGZqiCAyCch+Yyf3lv2",
+	"n97zPECHW3KoR/cu00/06F7ee3Rv/5VOf25P4VbosT0Vzc4OMOvJJIdDPXzJrSE2o5yZw3xGujF2ZTSy",
+	"G3MaOdN+ihTrUSZ+3cpax/NURZY6IvJO6ojzrDTWJ0E5XctCmUYtcWMITcnaQocCWhb30RhDd7zAWyJS",
+	"I6FJqNKe543iWr5yrbiw0SHke0Jcaje31IRli/WZ3C00z1sMeDsHtQSpJKg2g47TkPicKjPf225RFxLy",
+	"4slsAP2advcFGyp/yoVO6UVz+YJap3aR2Uo0BWWp72GJHK2zNdsqdvXPvlF9MbsQpFG25zjfqr7sMPZL",
+	"zIw8jO8bQAes+CDSp19++cVXZrmfGbvqb5I3UEYuS5rj4NgXrsanVzeBiamjBC7WZ1lBr1S1MkZ67YWa",
+	"U8FmE8a1mzOJAPGv11qsCsfABxYsVC9RwQV8MD/R+5wYX2hYp1V0m4qhg5LN/KobfkaJH5/mBSOLKOI7",
+	"hUF0yCPEOAyRfA60YbNHxoepLPFbi5P0a1LLJbKBEvFFZcPRXm9zgbqd4YF9ullUt9umPFVHwyJfzQlA",
+	"9N/Otsbz7zo1oCKbJWoinNyOyqTRuOgqbaDao7xfb3/e23D5av+tYSaEyB+KssZIDL+yyTnXfu3S3+nj",
+	"jmf7vrOn7o7zvgU13O0lA/GwtDyCAw8PUn/PP1Lk8pK0MSw/BZtPN2Oq+jw7k6almSwyPFs3zbZ+fnp6",
+	"fX19ouxOJ4CEpyvKcgC1rl2sT9VA/NSQnQssu8jyfMiF81sQYHV09vY16UxZgxUOZq8xDYLsWxqzZk9P",
+	"nnAKuSiSbQY/PDt5cvIF79iakOCU6yzAf6Hd6dXTUzuoZOV9OUokFdzilsZURISGmEX61OtUN3pVVmdq",
+	"OOkg4AdYn/8UeiWHHoeFv39tRYWxRHJXLYOJcVv1yWM80ZUv9DWHW2Ks2klgxjyDq/6O05kqTJjTbGY7",
+	"ib6vhVXqsLykjAFWFlVctKrUpzsFAMMhfHAZhO3naPKapaJKoW1oGGcL84pyZMg5UFhBnidOGTFpkpTv",
+	"LsiaCwu44hY5agfKzE7esVovjaL/uBwBhf8ZI6mOMK2l1uNZqJoklhDGCOGOJyKLcdPNhkSBjIkla468",
+	"+EgMnev6EbZ/fG7e7JIG6XmkKzJ0LKlz6d9W77r2n0tl73lowTJcNwZgfcu0fCq7nXAuX2r5TI8Xp7jT",
+	"2arINsttKZ9nofVSWUg8cJB0IWBMFmWYskbj1YY/h8BXHEl5i81jG1xdj2ruAnOlITENA6ihJsxUNi7m",
+	"qipgIc1qrBtDtdHoAut4u4PIp0uC7nACdp2KMOvu+vn3OWMqAs8VcGOgxww2F18lLyL1h64l2+P0VBIR",
+	"6Z+CaObKUcYv1eNvQQR1ZhumyJ/psQuqUUSS8+mTJ0o9kNY0axtOf6lZ7zMDhgM7d0nD8OmnqlLcYCqp",
+	"LvLLDhHeODJw4WRtE3Ya3zQxibP+yN/XMgwN9jgrZKgF2ag2ySWZogpOyJGRToqtqMxhlJHaTC+lqkT1",
+	"8JsfHRTpQ/XNzviiXT1oCqAHlpCLZzXqYNIupKPeNbwGu/i3PuamKr5d1y7WqJxZ78lOqAWYGBXLPeyf",
+	"vaqrux+PKLrjMeu5CVovfprVpLzNfv7YUQlPf1cBfVn6MagfvinLS8xklIZG+x2GnprIbSX2fn1LPGRQ",
+	"TdTmS8WSiXBRm7UYjgZyZm8UXGXFTmrTVAZ9QIb676mu3Atz3IEl3iML9JPiwSgxJ/oYocRTvP1fZc3t",
+	"GEmyF5bbytcN5TDPlUcHkcKJl8EfnHe385zrjehMpZrsisUqp8rMKcc60u3JU+3dqe/vJkBjsDEprlTG",
+	"idRVtLdrgJEe+hwktznImdqIz4iLHK+gPl6K78VVVnVqw1M3mawHHZpcN9iTt7ogcCnfLgzJzQgMqsEd",
+	"YbBm52dJMKcRrmYy9ARI4N2rF9GzZ8++kq/sIb/nXQ6BxkNycqQNnL6RYEqn+jzlggMQEADvtaY1qdXo",
+	"9uuzP9TKacRPvvBDCzvD2ifeBLjDa2j8J74OeArqSsHwkIK5+3bRFH25GxsyoDDbLwmNibyjGOpUD8JZ",
+	"ltmNRHQV7LcoO9UgC6pOrkpne6GgoCEabGfjEbt/Q6xFf/3dO7HKYbUnPUAirm/bstU5Jk8vs5xSY37B",
+	"3VL405qgFi1AVaq19uZQGjT8FcU6tgB/2fBP5K+CSfCnnH8iTzn7CX1rR29vcPE1ddvwPzjepEVaSrLO",
+	"V7P1U0BOrknkPwu/aeuzvF3+yZWw+3E1HVW7f3vV7uhY+5N7nD6l9ZFXnSl/CCnmXAhoWD3R5YIe0ET3",
+	"5/E5dBT8uz8mGnjnR8k5Z8KDXV4sA/KksA+7fTjyw201HP3xYI7Ef/NAgD/lNe/AJpcONUwzu7jlqI9W",
+	"l06G/z06J61JTn93ecS4k9Itlu+1tZgmfgelTwfocqpRPeDoEzwUze5IqQ/nG7wnw6MudTIqtanlUKgm",
+	"DzUiqo+C9E9kL31FBj+296k6OUoa8N1eZ42bJC7v1YubHXp2HD242mQjDj0fPoAbmg+/7TbfQQxRB2ak",
+	"mp1MU3uw+VHh0QqP4qD3pOrQ8KDkSMQYV29kZZDxCCxsOF29sasXHBWbe1VsalkUfxIVPmCgE015J0Sf",
+	"z/725G87bc3gk3fOC7kfP34cV5osQjqVz8LVU0Ko8m5F1et1SXhmP1A5SGhqsqOqdVS1PqE/8Oi++Hd3",
+	"XxxMeB9WqtncdpKe2XvO+KhyqucGjSy5TwODLSt3iWpyyg7bBfkGNdFjYNMxsOkY2HQMbDoGNh1DkI4h",
+	"SMcQpGMIknkOEfNNdRRQ71EHuzYbAmpVLLNZvnzOKITqukjzA6XCvyg3F6CbGC1YrcAkt4Myl2J9IeG+",
+	"5aQaUmlk5eoaWRfw1jwgX9VTQbrA3HymXkXCR66aSfLWWY0CkMrrWfPbLw3stDZKryMzTaRCvxiXC9zn",
+	"HLCjkY/eUmaeWskcC7jflm10TcSSZ5fUn56E5HiyDb8B4tYUoALCbdDHIrvHumbymOXn/q3Jx3i5Y7zc",
+	"PcfL0aOCcCXmZwj54jnqi9FPL/tuvV/jx7GbLqMBT+ePPbUBelj7ztD58eL23GvmfKe/AzYO+LvwyYa4",
+	"QfyTnNIj8/A6W88teY2bBj+g35b/T0z6+3dv6nnkeTXVefmIyhpjc9Iranotid65qq6E9TJ7xjW5oqpV",
+	"AjqORYF1HWIGdO5Qs1ZJyoV8pmyToV+DC9p8g4WQs4XqeIH0uMGKyvjMK9ab6GMXtz1nc8Igcv1IkkdJ",
+	"mhM/fuEhDOKVEqS016rOec03ciw0V0+8leMrMBExZqmV67KrLngd3v3r55yqfViRISHzvH8Ku0RYgbum",
+	"ESmELt1qo6au4CCbVbMfyrPNz36cXidZg/qifBKcrlySzYZp/0fopOpSc+3v7uVPVgSnmqaAWmXVoBrP",
+	"RQzwUOU70ejdanQDVX3pnOiTpZ9qzhMk0VJcA0bCCYIKBupVUarXyUH1MhM1ihtI4SQbyQcJOlc0B+Y+",
+	"QeMOvSorEgVnCMU0oQFrx54M+MmA8PiUXoERLeg8dEb09HhoN80zanLXmY/QbuBtPY3Ktvlk0XuTTOlW",
+	"DN9wcrCO5Dvaz4/286P9/Gg/P9rPj4nBR6v80Sp/tMofrfJHq/zRKj8tHvNhLenHosJHJ8OxEPB+hYD/",
+	"UA6V+ezLAzoVBuP9u+ZF5/2m3/FKNp46o2qadh6T9Xlv7P2ckj8j74TTLdV/IEZgbddOaDgd7T6vLJMH",
+	"xGpjPKSnUKsrhWLuOzPiJkH3CT0xQ+UoZf/fjQjabIjy9S9yZOsXSUEff/74/wER7VEyLhwBAA==",
 }
 
 // GetSwagger returns the Swagger specification corresponding to the generated code
@@ -83,6 +83,18 @@ type Account struct {
 	// *  Online  - indicates that the associated account used as part of the delegation pool.
 	// *   NotParticipating - indicates that the associated account is neither a delegator nor a delegate.
 	Status string `json:"status"`
+
+	// The number of applications which this account opted in to, i.e. of its application local states. Not returned for accounts at a past round.
+	TotalAppsOptedIn *uint64 `json:"total-apps-opted-in,omitempty"`
+
+	// The number of assets which this account opted in to, i.e. of its asset holdings. Not returned for accounts at a past round.
+	TotalAssetsOptedIn *uint64 `json:"total-assets-opted-in,omitempty"`
+
+	// The number of applications which this account created. Not returned for accounts at a past round.
+	TotalCreatedApps *uint64 `json:"total-created-apps,omitempty"`
+
+	// The number of assets which this account created. Not returned for accounts at a past round.
+	TotalCreatedAssets *uint64 `json:"total-created-assets,omitempty"`
 }
 
 // AccountParticipation defines model for AccountParticipation.
@@ -112,6 +124,43 @@ type AccountStateDelta struct {
 	Delta StateDelta `json:"delta"`
 }
 
+// ActivityItem defines model for ActivityItem.
+type ActivityItem struct {
+
+	// Amount in micro algos, or in base units for asset transfers.
+	Amount uint64 `json:"amount"`
+
+	// Application which was called.
+	ApplicationId *uint64 `json:"application-id,omitempty"`
+
+	// Asset of an asset transfer, configuration or freeze.
+	AssetId *uint64 `json:"asset-id,omitempty"`
+
+	// Other account of a transfer.
+	Counterparty *string `json:"counterparty,omitempty"`
+
+	// How the activity relates to the account, in, out or self.
+	Direction *string `json:"direction,omitempty"`
+
+	// Fee paid by the account, only set on the first item of a transaction sent by the account.
+	Fee *uint64 `json:"fee,omitempty"`
+
+	// Offset of the transaction in the round.
+	Intra uint64 `json:"intra"`
+
+	// Round of the transaction.
+	Round uint64 `json:"round"`
+
+	// Time of the round in seconds since epoch.
+	RoundTime uint64 `json:"round-time"`
+
+	// Transaction ID.
+	Txid string `json:"txid"`
+
+	// Type of the activity, one of payment, asset-transfer, asset-config, asset-freeze, app-call, key-registration or reward.
+	Type string `json:"type"`
+}
+
 // Application defines model for Application.
 type Application struct {
 
@@ -590,6 +639,9 @@ type Transaction struct {
 	// Validation signature associated with some data. Only one of the signatures should be provided.
 	Signature TransactionSignature `json:"signature"`
 
+	// Set when the transaction was too large to be returned in full, in that case global-state-delta and local-state-delta are omitted.
+	Truncated *bool `json:"truncated,omitempty"`
+
 	// \[type\] Indicates what type of transaction this is. Different types have different fields.
 	//
 	// Valid types, and where their fields are stored:
@@ -866,6 +918,17 @@ type TxType string
 // Txid defines model for txid.
 type Txid string
 
+// AccountActivityResponse defines model for AccountActivityResponse.
+type AccountActivityResponse struct {
+	Activity []ActivityItem `json:"activity"`
+
+	// Round at which the results were computed.
+	CurrentRound uint64 `json:"current-round"`
+
+	// Used for pagination, when making another request provide this token with the next parameter.
+	NextToken *string `json:"next-token,omitempty"`
+}
+
 // AccountResponse defines model for AccountResponse.
 type AccountResponse struct {
 
@@ -888,6 +951,9 @@ type AccountsResponse struct {
 
 	// Used for pagination, when making another request provide this token with the next parameter.
 	NextToken *string `json:"next-token,omitempty"`
+
+	// Estimate of the number of results over all pages, from the database statistics. Only returned with the first page, and omitted when no estimate is available.
+	TotalCountEstimate *uint64 `json:"total-count-estimate,omitempty"`
 }
 
 // ApplicationResponse defines model for ApplicationResponse.
@@ -949,6 +1015,13 @@ type BlockResponse Block
 // HealthCheckResponse defines model for HealthCheckResponse.
 type HealthCheckResponse HealthCheck
 
+// SearchResponse defines model for SearchResponse.
+type SearchResponse struct {
+
+	// The matching documents, best matches first.
+	Results []map[string]interface{} `json:"results"`
+}
+
 // TransactionResponse defines model for TransactionResponse.
 type TransactionResponse struct {
 
@@ -972,6 +1045,16 @@ type TransactionsResponse struct {
 	// Used for pagination, when making another request provide this token with the next parameter.
 	NextToken    *string       `json:"next-token,omitempty"`
 	Transactions []Transaction `json:"transactions"`
+
+	// Estimate of the number of results over all pages, from the database statistics. Only returned with the first page, and omitted when no estimate is available.
+	TotalCountEstimate *uint64 `json:"total-count-estimate,omitempty"`
+}
+
+// WaitForRoundResponse defines model for WaitForRoundResponse.
+type WaitForRoundResponse struct {
+
+	// The latest imported round, it is not greater than the requested round if the wait timed out.
+	CurrentRound uint64 `json:"current-round"`
 }
 
 // SearchForAccountsParams defines parameters for SearchForAccounts.
@@ -1003,6 +1086,9 @@ type SearchForAccountsParams struct {
 
 	// Application ID
 	ApplicationId *uint64 `json:"application-id,omitempty"`
+
+	// Include total-count-estimate, an estimate of the number of results over all pages, in the first page.
+	CountEstimate *bool `json:"count-estimate,omitempty"`
 }
 
 // LookupAccountByIDParams defines parameters for LookupAccountByID.
@@ -1015,6 +1101,28 @@ type LookupAccountByIDParams struct {
 	IncludeAll *bool `json:"include-all,omitempty"`
 }
 
+// LookupAccountActivityParams defines parameters for LookupAccountActivity.
+type LookupAccountActivityParams struct {
+
+	// Maximum number of results to return.
+	Limit *uint64 `json:"limit,omitempty"`
+
+	// The next page of results. Use the next token provided by the previous results.
+	Next *string `json:"next,omitempty"`
+
+	// Include results at or after the specified min-round.
+	MinRound *uint64 `json:"min-round,omitempty"`
+
+	// Include results at or before the specified max-round.
+	MaxRound *uint64 `json:"max-round,omitempty"`
+
+	// Include results before the given time. Must be an RFC 3339 formatted string.
+	BeforeTime *time.Time `json:"before-time,omitempty"`
+
+	// Include results after the given time. Must be an RFC 3339 formatted string.
+	AfterTime *time.Time `json:"after-time,omitempty"`
+}
+
 // LookupAccountTransactionsParams defines parameters for LookupAccountTransactions.
 type LookupAccountTransactionsParams struct {
 
@@ -1198,6 +1306,16 @@ type LookupAssetTransactionsParams struct {
 	RekeyTo *bool `json:"rekey-to,omitempty"`
 }
 
+// SearchTextParams defines parameters for SearchText.
+type SearchTextParams struct {
+
+	// Simple query string of the search.
+	Q string `json:"q"`
+
+	// Maximum number of results to return.
+	Limit *uint64 `json:"limit,omitempty"`
+}
+
 // SearchForTransactionsParams defines parameters for SearchForTransactions.
 type SearchForTransactionsParams struct {
 
@@ -1258,4 +1376,7 @@ type SearchForTransactionsParams struct {
 
 	// Application ID
 	ApplicationId *uint64 `json:"application-id,omitempty"`
+
+	// Include total-count-estimate, an estimate of the number of results over all pages, in the first page.
+	CountEstimate *bool `json:"count-estimate,omitempty"`
 }
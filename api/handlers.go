@@ -28,6 +28,26 @@ type ServerImplementation struct {
 	db idb.IndexerDb
 
 	fetcher error
+
+	// readyMaxLag is the number of rounds the import may lag algod before the
+	// readiness check fails, 0 disables the check.
+	readyMaxLag uint64
+
+	// importer is nil when the daemon does not import blocks.
+	importer ImporterControl
+
+	// config is returned by the admin API.
+	config map[string]string
+
+	// roundWaiter is nil when the server does not import blocks, rounds are
+	// then polled from the database.
+	roundWaiter RoundWaiter
+
+	// paginationKey signs the next tokens.
+	paginationKey []byte
+
+	// searcher is nil when free-text search is not configured.
+	searcher Searcher
 }
 
 /////////////////////
@@ -55,7 +75,8 @@ const defaultBalancesLimit = 1000
 ////////////////////////////
 
 // MakeHealthCheck returns health check information about indexer and the IndexerDb being used.
-// Returns 200 if healthy.
+// Returns 200 if healthy. It is meant as a liveness check, see MakeReadinessCheck
+// for deciding whether the indexer should receive traffic.
 // (GET /health)
 func (si *ServerImplementation) MakeHealthCheck(ctx echo.Context) error {
 	var errors []string
@@ -109,6 +130,12 @@ func (si *ServerImplementation) LookupAccountByID(ctx echo.Context, accountID st
 		IncludeDeleted:       boolOrDefault(params.IncludeAll),
 	}
 
+	if params.Round != nil {
+		if pruned, err := si.checkPruned(ctx, *params.Round, false); pruned {
+			return err
+		}
+	}
+
 	accounts, round, err := si.fetchAccounts(ctx.Request().Context(), options, params.Round)
 	if err != nil {
 		return indexerError(ctx, fmt.Sprintf("%s: %v", errFailedSearchingAccount, err))
@@ -159,23 +186,33 @@ func (si *ServerImplementation) SearchForAccounts(ctx echo.Context, params gener
 		options.AssetLT = params.CurrencyLessThan
 	}
 
-	if params.Next != nil {
-		addr, err := basics.UnmarshalChecksumAddress(*params.Next)
+	next, err := si.decodeNext(ctx, params.Next)
+	if err != nil {
+		return badRequest(ctx, err.Error())
+	}
+	if next != nil {
+		addr, err := basics.UnmarshalChecksumAddress(*next)
 		if err != nil {
-			ctx.JSON(http.StatusBadRequest, errUnableToParseNext)
+			return badRequest(ctx, errUnableToParseNext)
 		}
 		options.GreaterThanAddress = addr[:]
 	}
 
+	if params.Round != nil {
+		if pruned, err := si.checkPruned(ctx, *params.Round, false); pruned {
+			return err
+		}
+	}
+
 	accounts, round, err := si.fetchAccounts(ctx.Request().Context(), options, params.Round)
 
 	if err != nil {
 		return indexerError(ctx, fmt.Sprintf("%s: %v", errFailedSearchingAccount, err))
 	}
 
-	var next *string
+	next = nil
 	if len(accounts) > 0 {
-		next = strPtr(accounts[len(accounts)-1].Address)
+		next = si.encodeNext(ctx, accounts[len(accounts)-1].Address)
 	}
 
 	response := generated.AccountsResponse{
@@ -183,6 +220,13 @@ func (si *ServerImplementation) SearchForAccounts(ctx echo.Context, params gener
 		NextToken:    next,
 		Accounts:     accounts,
 	}
+	// The estimate is optional, the search does not fail without it.
+	if params.Next == nil && boolOrDefault(params.CountEstimate) {
+		estimate, err := si.db.EstimateAccountsCount(ctx.Request().Context(), options)
+		if err == nil {
+			response.TotalCountEstimate = uint64Ptr(estimate)
+		}
+	}
 
 	return ctx.JSON(http.StatusOK, response)
 }
@@ -225,6 +269,12 @@ func (si *ServerImplementation) LookupAccountTransactions(ctx echo.Context, acco
 // SearchForApplications returns applications for the provided parameters.
 // (GET /v2/applications)
 func (si *ServerImplementation) SearchForApplications(ctx echo.Context, params generated.SearchForApplicationsParams) error {
+	var err error
+	params.Next, err = si.decodeNext(ctx, params.Next)
+	if err != nil {
+		return badRequest(ctx, err.Error())
+	}
+
 	results, round := si.db.Applications(ctx.Request().Context(), &params)
 	apps := make([]generated.Application, 0)
 	for result := range results {
@@ -236,7 +286,7 @@ func (si *ServerImplementation) SearchForApplications(ctx echo.Context, params g
 
 	var next *string
 	if len(apps) > 0 {
-		next = strPtr(strconv.FormatUint(apps[len(apps)-1].Id, 10))
+		next = si.encodeNext(ctx, strconv.FormatUint(apps[len(apps)-1].Id, 10))
 	}
 
 	out := generated.ApplicationsResponse{
@@ -312,10 +362,14 @@ func (si *ServerImplementation) LookupAssetBalances(ctx echo.Context, assetID ui
 		Limit:          min(uintOrDefaultValue(params.Limit, defaultBalancesLimit), maxBalancesLimit),
 	}
 
-	if params.Next != nil {
-		addr, err := basics.UnmarshalChecksumAddress(*params.Next)
+	next, err := si.decodeNext(ctx, params.Next)
+	if err != nil {
+		return badRequest(ctx, err.Error())
+	}
+	if next != nil {
+		addr, err := basics.UnmarshalChecksumAddress(*next)
 		if err != nil {
-			ctx.JSON(http.StatusBadRequest, errUnableToParseNext)
+			return badRequest(ctx, errUnableToParseNext)
 		}
 		query.PrevAddress = addr[:]
 	}
@@ -325,9 +379,9 @@ func (si *ServerImplementation) LookupAssetBalances(ctx echo.Context, assetID ui
 		indexerError(ctx, err.Error())
 	}
 
-	var next *string
+	next = nil
 	if len(balances) > 0 {
-		next = strPtr(balances[len(balances)-1].Address)
+		next = si.encodeNext(ctx, balances[len(balances)-1].Address)
 	}
 
 	return ctx.JSON(http.StatusOK, generated.AssetBalancesResponse{
@@ -368,6 +422,12 @@ func (si *ServerImplementation) LookupAssetTransactions(ctx echo.Context, assetI
 // SearchForAssets returns assets matching the provided parameters
 // (GET /v2/assets)
 func (si *ServerImplementation) SearchForAssets(ctx echo.Context, params generated.SearchForAssetsParams) error {
+	var err error
+	params.Next, err = si.decodeNext(ctx, params.Next)
+	if err != nil {
+		return badRequest(ctx, err.Error())
+	}
+
 	options, err := assetParamsToAssetQuery(params)
 	if err != nil {
 		return badRequest(ctx, err.Error())
@@ -380,7 +440,7 @@ func (si *ServerImplementation) SearchForAssets(ctx echo.Context, params generat
 
 	var next *string
 	if len(assets) > 0 {
-		next = strPtr(strconv.FormatUint(assets[len(assets)-1].Index, 10))
+		next = si.encodeNext(ctx, strconv.FormatUint(assets[len(assets)-1].Index, 10))
 	}
 
 	return ctx.JSON(http.StatusOK, generated.AssetsResponse{
@@ -393,6 +453,10 @@ func (si *ServerImplementation) SearchForAssets(ctx echo.Context, params generat
 // LookupBlock returns the block for a given round number
 // (GET /v2/blocks/{round-number})
 func (si *ServerImplementation) LookupBlock(ctx echo.Context, roundNumber uint64) error {
+	if pruned, err := si.checkPruned(ctx, roundNumber, false); pruned {
+		return err
+	}
+
 	blk, err := si.fetchBlock(ctx.Request().Context(), roundNumber)
 	if err != nil {
 		return indexerError(ctx, err.Error())
@@ -435,11 +499,29 @@ func (si *ServerImplementation) LookupTransaction(ctx echo.Context, txid string)
 // SearchForTransactions returns transactions matching the provided parameters
 // (GET /v2/transactions)
 func (si *ServerImplementation) SearchForTransactions(ctx echo.Context, params generated.SearchForTransactionsParams) error {
+	var err error
+	params.Next, err = si.decodeNext(ctx, params.Next)
+	if err != nil {
+		return badRequest(ctx, err.Error())
+	}
+
 	filter, err := transactionParamsToTransactionFilter(params)
 	if err != nil {
 		return badRequest(ctx, err.Error())
 	}
 
+	// Only reject requests for which no transactions are left, results of a
+	// range which starts before the earliest round begin at the earliest round.
+	if filter.Round != nil || filter.MaxRound != 0 {
+		lastRound := filter.MaxRound
+		if filter.Round != nil {
+			lastRound = *filter.Round
+		}
+		if pruned, err := si.checkPruned(ctx, lastRound, len(filter.Address) > 0); pruned {
+			return err
+		}
+	}
+
 	// Fetch the transactions
 	txns, next, round, err := si.fetchTransactions(ctx.Request().Context(), filter)
 	if err != nil {
@@ -448,9 +530,16 @@ func (si *ServerImplementation) SearchForTransactions(ctx echo.Context, params g
 
 	response := generated.TransactionsResponse{
 		CurrentRound: round,
-		NextToken:    strPtr(next),
+		NextToken:    si.encodeNext(ctx, next),
 		Transactions: txns,
 	}
+	// The estimate is optional, the search does not fail without it.
+	if len(filter.NextToken) == 0 && boolOrDefault(params.CountEstimate) {
+		estimate, err := si.db.EstimateTransactionsCount(ctx.Request().Context(), filter)
+		if err == nil {
+			response.TotalCountEstimate = uint64Ptr(estimate)
+		}
+	}
 
 	return ctx.JSON(http.StatusOK, response)
 }
@@ -480,6 +569,39 @@ func notFound(ctx echo.Context, err string) error {
 	})
 }
 
+// return a 409
+func conflict(ctx echo.Context, err string) error {
+	return ctx.JSON(http.StatusConflict, generated.ErrorResponse{
+		Message: err,
+	})
+}
+
+// return a 410
+func gone(ctx echo.Context, err string) error {
+	return ctx.JSON(http.StatusGone, generated.ErrorResponse{
+		Message: err,
+	})
+}
+
+// checkPruned returns true and writes an error response if the transactions of
+// `round`, or only their participation rows when searching `byAddress`, have
+// been deleted by the retention job or can't be checked.
+func (si *ServerImplementation) checkPruned(ctx echo.Context, round uint64, byAddress bool) (bool, error) {
+	retention, err := si.db.GetRetention(ctx.Request().Context())
+	if err != nil {
+		return true, indexerError(ctx, fmt.Sprintf("%s: %v", errLookingUpEarliestRound, err))
+	}
+	earliest := retention.EarliestRound
+	if byAddress {
+		earliest = retention.EarliestParticipationRound
+	}
+	if round < earliest {
+		return true, gone(ctx, fmt.Sprintf(
+			"%s: %d, the earliest available round is %d", errRoundPruned, round, earliest))
+	}
+	return false, nil
+}
+
 ///////////////////////
 // IndexerDb helpers //
 ///////////////////////
@@ -579,8 +701,7 @@ func (si *ServerImplementation) fetchAssetBalances(ctx context.Context, options
 // fetchBlock looks up a block and converts it into a generated.Block object
 // the method also loads the transactions into the returned block object.
 func (si *ServerImplementation) fetchBlock(ctx context.Context, round uint64) (generated.Block, error) {
-	blockHeader, transactions, err :=
-		si.db.GetBlock(ctx, round, idb.GetBlockOptions{Transactions: true})
+	blockHeader, transactions, err := si.db.GetBlockWithTransactions(ctx, round)
 
 	if err != nil {
 		return generated.Block{}, fmt.Errorf("%s '%d': %v", errLookingUpBlock, round, err)
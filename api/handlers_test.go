@@ -6,12 +6,16 @@ import (
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
 	"strings"
 	"testing"
 	"time"
 
+	"github.com/labstack/echo/v4"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 
 	"github.com/algorand/go-algorand-sdk/encoding/msgpack"
 	"github.com/algorand/indexer/api/generated/v2"
@@ -501,3 +505,82 @@ func TestFetchAccountsRewindRoundTooLarge(t *testing.T) {
 	assert.Error(t, err)
 	assert.True(t, strings.HasPrefix(err.Error(), errRewindingAccount), err.Error())
 }
+
+func TestSearchForTransactionsPruned(t *testing.T) {
+	mockIndexer := &mocks.IndexerDb{}
+	retention := idb.Retention{EarliestRound: 100, EarliestParticipationRound: 200}
+	mockIndexer.On("GetRetention", mock.Anything).Return(retention, nil)
+	si := ServerImplementation{db: mockIndexer}
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	err := si.SearchForTransactions(c, generated.SearchForTransactionsParams{MaxRound: uint64Ptr(99)})
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusGone, rec.Code)
+	assert.Contains(t, rec.Body.String(), errRoundPruned)
+
+	rec = httptest.NewRecorder()
+	c = e.NewContext(req, rec)
+	err = si.LookupBlock(c, 99)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusGone, rec.Code)
+
+	// Transactions are still searched when the range ends after the earliest round.
+	ch := make(chan idb.TxnRow)
+	close(ch)
+	var outCh <-chan idb.TxnRow = ch
+	mockIndexer.On("Transactions", mock.Anything, mock.Anything).Return(outCh, uint64(150))
+
+	rec = httptest.NewRecorder()
+	c = e.NewContext(req, rec)
+	err = si.SearchForTransactions(
+		c, generated.SearchForTransactionsParams{MinRound: uint64Ptr(50), MaxRound: uint64Ptr(120)})
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	// Searches by address need the participation rows.
+	rec = httptest.NewRecorder()
+	c = e.NewContext(req, rec)
+	err = si.SearchForTransactions(c, generated.SearchForTransactionsParams{
+		Address:  strPtr("AAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAY5HFKQ"),
+		MaxRound: uint64Ptr(120),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusGone, rec.Code)
+}
+
+func TestSearchForTransactionsCountEstimate(t *testing.T) {
+	mockIndexer := &mocks.IndexerDb{}
+	ch := make(chan idb.TxnRow)
+	close(ch)
+	var outCh <-chan idb.TxnRow = ch
+	mockIndexer.On("Transactions", mock.Anything, mock.Anything).Return(outCh, uint64(150))
+	si := ServerImplementation{db: mockIndexer}
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+
+	// The database is not asked for an estimate without count-estimate.
+	rec := httptest.NewRecorder()
+	err := si.SearchForTransactions(e.NewContext(req, rec), generated.SearchForTransactionsParams{})
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.NotContains(t, rec.Body.String(), "total-count-estimate")
+	mockIndexer.AssertNotCalled(t, "EstimateTransactionsCount", mock.Anything, mock.Anything)
+
+	mockIndexer.On("EstimateTransactionsCount", mock.Anything, mock.Anything).
+		Return(uint64(42), nil)
+	rec = httptest.NewRecorder()
+	err = si.SearchForTransactions(
+		e.NewContext(req, rec), generated.SearchForTransactionsParams{CountEstimate: boolPtr(true)})
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	var response generated.TransactionsResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	require.NotNil(t, response.TotalCountEstimate)
+	assert.Equal(t, uint64(42), *response.TotalCountEstimate)
+}
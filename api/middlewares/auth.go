@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"net/http"
 	"strings"
+	"sync/atomic"
 
 	"github.com/labstack/echo/v4"
 )
@@ -16,19 +17,65 @@ type authMiddleware struct {
 	header string
 
 	// Tokens is the set of tokens which can be set to allow access.
-	tokens [][]byte
+	tokens *Tokens
+
+	// open allows every request while there are no tokens.
+	open bool
+}
+
+// Tokens is a set of access tokens which can be replaced while the server runs,
+// e.g. when the configuration is reloaded.
+type Tokens struct {
+	tokens atomic.Value // [][]byte
+}
+
+// MakeTokens creates a set of `tokens`.
+func MakeTokens(tokens []string) *Tokens {
+	t := &Tokens{}
+	t.Set(tokens)
+	return t
+}
+
+// Set replaces the tokens, the empty ones are ignored.
+func (t *Tokens) Set(tokens []string) {
+	apiTokenBytes := make([][]byte, 0, len(tokens))
+	for _, token := range tokens {
+		if token != "" {
+			apiTokenBytes = append(apiTokenBytes, []byte(token))
+		}
+	}
+	t.tokens.Store(apiTokenBytes)
+}
+
+// Get returns the current tokens.
+func (t *Tokens) Get() [][]byte {
+	tokens, _ := t.tokens.Load().([][]byte)
+	return tokens
 }
 
 // MakeAuth constructs the auth middleware function
 func MakeAuth(header string, tokens []string) echo.MiddlewareFunc {
-	apiTokenBytes := make([][]byte, 0)
-	for _, token := range tokens {
-		apiTokenBytes = append(apiTokenBytes, []byte(token))
+	return MakeTokenAuth(header, MakeTokens(tokens))
+}
+
+// MakeTokenAuth constructs the auth middleware function of a set of tokens which
+// may change, requests are refused while it is empty.
+func MakeTokenAuth(header string, tokens *Tokens) echo.MiddlewareFunc {
+	auth := authMiddleware{
+		header: header,
+		tokens: tokens,
 	}
 
+	return auth.handler
+}
+
+// MakeOptionalAuth is MakeTokenAuth, except that every request is allowed while
+// the set of tokens is empty.
+func MakeOptionalAuth(header string, tokens *Tokens) echo.MiddlewareFunc {
 	auth := authMiddleware{
 		header: header,
-		tokens: apiTokenBytes,
+		tokens: tokens,
+		open:   true,
 	}
 
 	return auth.handler
@@ -43,6 +90,11 @@ func (auth *authMiddleware) handler(next echo.HandlerFunc) echo.HandlerFunc {
 			return next(ctx)
 		}
 
+		tokens := auth.tokens.Get()
+		if auth.open && len(tokens) == 0 {
+			return next(ctx)
+		}
+
 		// Grab the apiToken from the HTTP header, or as a bearer token
 		providedToken := []byte(ctx.Request().Header.Get(auth.header))
 		if len(providedToken) == 0 {
@@ -69,7 +121,7 @@ func (auth *authMiddleware) handler(next echo.HandlerFunc) echo.HandlerFunc {
 		}
 
 		// Check the tokens in constant time
-		for _, tokenBytes := range auth.tokens {
+		for _, tokenBytes := range tokens {
 			if subtle.ConstantTimeCompare(providedToken, tokenBytes) == 1 {
 				// Token was correct, keep serving request
 				return next(ctx)
@@ -0,0 +1,47 @@
+package middlewares
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+// authorize runs the auth middleware `auth` on a request with `token`, and
+// returns whether the request was allowed.
+func authorize(auth echo.MiddlewareFunc, token string) bool {
+	req := httptest.NewRequest(http.MethodGet, "/v2/accounts", nil)
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	return auth(success)(e.NewContext(req, httptest.NewRecorder())) == errSuccess
+}
+
+func TestTokenAuthReplaceTokens(t *testing.T) {
+	tokens := MakeTokens([]string{"old"})
+	auth := MakeTokenAuth("X-Indexer-Admin-Token", tokens)
+	assert.True(t, authorize(auth, "old"))
+	assert.False(t, authorize(auth, "new"))
+
+	tokens.Set([]string{"new"})
+	assert.False(t, authorize(auth, "old"))
+	assert.True(t, authorize(auth, "new"))
+
+	// Without tokens every request is refused.
+	tokens.Set([]string{""})
+	assert.False(t, authorize(auth, ""))
+	assert.False(t, authorize(auth, "new"))
+}
+
+func TestOptionalAuth(t *testing.T) {
+	tokens := MakeTokens(nil)
+	auth := MakeOptionalAuth("X-Indexer-API-Token", tokens)
+	assert.True(t, authorize(auth, ""))
+
+	tokens.Set([]string{"token"})
+	assert.False(t, authorize(auth, ""))
+	assert.False(t, authorize(auth, "other"))
+	assert.True(t, authorize(auth, "token"))
+}
@@ -0,0 +1,56 @@
+package middlewares
+
+import (
+	"net/http"
+	"regexp"
+	"sync/atomic"
+
+	"github.com/labstack/echo/v4"
+)
+
+// pathParamRegexp matches the parameters of an OpenAPI path, e.g. {account-id}.
+var pathParamRegexp = regexp.MustCompile(`{([^}]+)}`)
+
+// DisabledEndpoints is a set of routes which are not served. It can be replaced
+// while the server runs, e.g. when the configuration is reloaded.
+type DisabledEndpoints struct {
+	paths atomic.Value // map[string]bool
+}
+
+// MakeDisabledEndpoints creates a set of disabled routes, see Set.
+func MakeDisabledEndpoints(paths []string) *DisabledEndpoints {
+	d := &DisabledEndpoints{}
+	d.Set(paths)
+	return d
+}
+
+// Set replaces the disabled routes. A route is given by its path in the OpenAPI
+// document, e.g. /v2/accounts/{account-id}, or in the echo router, e.g.
+// /v2/accounts/:account-id.
+func (d *DisabledEndpoints) Set(paths []string) {
+	set := make(map[string]bool, len(paths))
+	for _, path := range paths {
+		if path != "" {
+			set[pathParamRegexp.ReplaceAllString(path, ":$1")] = true
+		}
+	}
+	d.paths.Store(set)
+}
+
+func (d *DisabledEndpoints) get() map[string]bool {
+	paths, _ := d.paths.Load().(map[string]bool)
+	return paths
+}
+
+// MakeDisabledEndpointsMiddleware constructs the middleware which responds 404
+// to the requests of the routes in `disabled`.
+func MakeDisabledEndpointsMiddleware(disabled *DisabledEndpoints) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(ctx echo.Context) error {
+			if disabled.get()[ctx.Path()] {
+				return echo.NewHTTPError(http.StatusNotFound, "This endpoint is disabled")
+			}
+			return next(ctx)
+		}
+	}
+}
@@ -0,0 +1,35 @@
+package middlewares
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// requestPath sends a request of the route `path` through `handler`.
+func requestPath(handler echo.HandlerFunc, path string) error {
+	ctx := e.NewContext(nil, nil)
+	ctx.SetPath(path)
+	return handler(ctx)
+}
+
+func TestDisabledEndpoints(t *testing.T) {
+	disabled := MakeDisabledEndpoints([]string{"/v2/transactions", "/v2/accounts/{account-id}/transactions"})
+	handler := MakeDisabledEndpointsMiddleware(disabled)(success)
+
+	err := requestPath(handler, "/v2/transactions")
+	require.Error(t, err)
+	httpErr, ok := err.(*echo.HTTPError)
+	require.True(t, ok)
+	assert.Equal(t, http.StatusNotFound, httpErr.Code)
+
+	// The parameters of the OpenAPI path match the ones of the route.
+	assert.NotEqual(t, errSuccess, requestPath(handler, "/v2/accounts/:account-id/transactions"))
+	assert.Equal(t, errSuccess, requestPath(handler, "/v2/accounts/:account-id"))
+
+	disabled.Set(nil)
+	assert.Equal(t, errSuccess, requestPath(handler, "/v2/transactions"))
+}
@@ -1,11 +1,12 @@
 package middlewares
 
 import (
-	"strconv"
 	"time"
 
 	"github.com/labstack/echo/v4"
 	log "github.com/sirupsen/logrus"
+
+	"github.com/algorand/indexer/idb"
 )
 
 type loggerMiddleware struct {
@@ -30,24 +31,26 @@ func (logger *loggerMiddleware) handler(next echo.HandlerFunc) echo.HandlerFunc
 		res := ctx.Response()
 		req := ctx.Request()
 
+		// The database logs the endpoint with the slow queries of the request.
+		ctx.SetRequest(req.WithContext(
+			idb.ContextWithEndpoint(req.Context(), req.Method+" "+ctx.Path())))
+
 		// Propogate the error if the next middleware has a problem
 		if err = next(ctx); err != nil {
 			ctx.Error(err)
 		}
 
-		logger.log.Infof("%s %s %s [%v] \"%s %s %s\" %d %s \"%s\" %s",
-			req.RemoteAddr,
-			"-",
-			"-",
-			start,
-			req.Method,
-			req.RequestURI,
-			req.Proto, // string "HTTP/1.1"
-			res.Status,
-			strconv.FormatInt(res.Size, 10), // bytes_out
-			req.UserAgent(),
-			time.Since(start),
-		)
+		logger.log.WithFields(log.Fields{
+			"remote":     req.RemoteAddr,
+			"method":     req.Method,
+			"uri":        req.RequestURI,
+			"proto":      req.Proto,
+			"status":     res.Status,
+			"bytes":      res.Size,
+			"user_agent": req.UserAgent(),
+			"duration":   time.Since(start).Seconds(),
+			"request_id": idb.RequestIDFromContext(req.Context()),
+		}).Info("request")
 
 		return
 	}
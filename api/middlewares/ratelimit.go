@@ -0,0 +1,110 @@
+package middlewares
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+// rateLimitSweepInterval is how often the buckets of idle clients are removed.
+const rateLimitSweepInterval = time.Minute
+
+// bucket is the token bucket of one client.
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// RateLimit limits the API requests of each client, identified by its address,
+// with a token bucket which fills at a rate of requests per second up to a burst.
+// The limit can be changed while the server runs, e.g. when the configuration
+// is reloaded.
+type RateLimit struct {
+	mu      sync.Mutex
+	rate    float64
+	burst   float64
+	clients map[string]*bucket
+	swept   time.Time
+
+	now func() time.Time
+}
+
+// MakeRateLimit creates a limit of `rate` requests per second per client, every
+// request is allowed while `rate` is not positive.
+func MakeRateLimit(rate float64, burst int) *RateLimit {
+	l := &RateLimit{now: time.Now}
+	l.Set(rate, burst)
+	return l
+}
+
+// Set replaces the limit, the clients start with a full bucket.
+func (l *RateLimit) Set(rate float64, burst int) {
+	if burst < 1 {
+		burst = 1
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.rate = rate
+	l.burst = float64(burst)
+	l.clients = make(map[string]*bucket)
+}
+
+// take takes a token of `client`. It returns 0 when the request is allowed,
+// otherwise how long the client has to wait for the next token.
+func (l *RateLimit) take(client string) time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.rate <= 0 {
+		return 0
+	}
+	now := l.now()
+	if now.Sub(l.swept) >= rateLimitSweepInterval {
+		l.sweep(now)
+	}
+
+	b, ok := l.clients[client]
+	if !ok {
+		b = &bucket{tokens: l.burst, last: now}
+		l.clients[client] = b
+	}
+	b.tokens = math.Min(l.burst, b.tokens+now.Sub(b.last).Seconds()*l.rate)
+	b.last = now
+	if b.tokens < 1 {
+		return time.Duration((1 - b.tokens) / l.rate * float64(time.Second))
+	}
+	b.tokens--
+	return 0
+}
+
+// sweep removes the buckets which are full again, their clients start with a
+// full bucket anyway.
+func (l *RateLimit) sweep(now time.Time) {
+	for client, b := range l.clients {
+		if b.tokens+now.Sub(b.last).Seconds()*l.rate >= l.burst {
+			delete(l.clients, client)
+		}
+	}
+	l.swept = now
+}
+
+// MakeRateLimitMiddleware constructs the middleware which responds 429 to the
+// requests over `limit`, with a Retry-After header.
+func MakeRateLimitMiddleware(limit *RateLimit) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(ctx echo.Context) error {
+			wait := limit.take(ctx.RealIP())
+			if wait > 0 {
+				seconds := int(math.Ceil(wait.Seconds()))
+				ctx.Response().Header().Set("Retry-After", strconv.Itoa(seconds))
+				return echo.NewHTTPError(http.StatusTooManyRequests, "Rate limit exceeded")
+			}
+			return next(ctx)
+		}
+	}
+}
@@ -0,0 +1,87 @@
+package middlewares
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// request sends a request from `ip` through `handler` and returns the response and the error.
+func request(handler echo.HandlerFunc, ip string) (*httptest.ResponseRecorder, error) {
+	req := httptest.NewRequest(http.MethodGet, "/v2/accounts", nil)
+	req.RemoteAddr = ip + ":1234"
+	rec := httptest.NewRecorder()
+	return rec, handler(e.NewContext(req, rec))
+}
+
+func TestRateLimit(t *testing.T) {
+	now := time.Unix(1000, 0)
+	limit := MakeRateLimit(2, 3)
+	limit.now = func() time.Time { return now }
+	handler := MakeRateLimitMiddleware(limit)(success)
+
+	// The burst is allowed at once.
+	for i := 0; i < 3; i++ {
+		_, err := request(handler, "10.0.0.1")
+		assert.Equal(t, errSuccess, err)
+	}
+	rec, err := request(handler, "10.0.0.1")
+	require.Error(t, err)
+	httpErr, ok := err.(*echo.HTTPError)
+	require.True(t, ok)
+	assert.Equal(t, http.StatusTooManyRequests, httpErr.Code)
+	assert.Equal(t, "1", rec.Header().Get("Retry-After"))
+
+	// Other clients have a bucket of their own.
+	_, err = request(handler, "10.0.0.2")
+	assert.Equal(t, errSuccess, err)
+
+	// The bucket fills at the rate.
+	now = now.Add(500 * time.Millisecond)
+	_, err = request(handler, "10.0.0.1")
+	assert.Equal(t, errSuccess, err)
+	_, err = request(handler, "10.0.0.1")
+	assert.Error(t, err)
+}
+
+func TestRateLimitSet(t *testing.T) {
+	now := time.Unix(1000, 0)
+	limit := MakeRateLimit(0, 1)
+	limit.now = func() time.Time { return now }
+	handler := MakeRateLimitMiddleware(limit)(success)
+
+	// There is no limit while the rate is 0.
+	for i := 0; i < 10; i++ {
+		_, err := request(handler, "10.0.0.1")
+		assert.Equal(t, errSuccess, err)
+	}
+
+	limit.Set(1, 1)
+	_, err := request(handler, "10.0.0.1")
+	assert.Equal(t, errSuccess, err)
+	_, err = request(handler, "10.0.0.1")
+	assert.Error(t, err)
+
+	limit.Set(0, 1)
+	_, err = request(handler, "10.0.0.1")
+	assert.Equal(t, errSuccess, err)
+}
+
+func TestRateLimitSweep(t *testing.T) {
+	now := time.Unix(1000, 0)
+	limit := MakeRateLimit(1, 1)
+	limit.now = func() time.Time { return now }
+
+	assert.Equal(t, time.Duration(0), limit.take("10.0.0.1"))
+	assert.Len(t, limit.clients, 1)
+
+	// The full buckets are removed.
+	now = now.Add(rateLimitSweepInterval)
+	assert.Equal(t, time.Duration(0), limit.take("10.0.0.2"))
+	assert.Len(t, limit.clients, 1)
+}
@@ -0,0 +1,20 @@
+package middlewares
+
+import (
+	"github.com/labstack/echo/v4"
+
+	"github.com/algorand/indexer/util/reporting"
+)
+
+// MakeReporting initializes an echo.MiddlewareFunc which reports the panics of
+// the handlers. The server still recovers from them, they are not handled here.
+func MakeReporting() echo.MiddlewareFunc {
+	return reportingHandler
+}
+
+func reportingHandler(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(ctx echo.Context) error {
+		defer reporting.ReportPanic()
+		return next(ctx)
+	}
+}
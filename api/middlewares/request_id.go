@@ -0,0 +1,57 @@
+package middlewares
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/algorand/indexer/idb"
+)
+
+// maxRequestIDLength is the maximum length of an accepted X-Request-ID.
+const maxRequestIDLength = 64
+
+// MakeRequestID initializes an echo.MiddlewareFunc which identifies every
+// request by its X-Request-ID header, or a new random ID when the header is
+// missing or invalid. The ID is returned in the X-Request-ID response header
+// and carried by the request's context to the logs and the database.
+func MakeRequestID() echo.MiddlewareFunc {
+	return requestIDHandler
+}
+
+func requestIDHandler(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(ctx echo.Context) error {
+		req := ctx.Request()
+		id := req.Header.Get(echo.HeaderXRequestID)
+		if !validRequestID(id) {
+			id = newRequestID()
+		}
+		ctx.Response().Header().Set(echo.HeaderXRequestID, id)
+		ctx.SetRequest(req.WithContext(idb.ContextWithRequestID(req.Context(), id)))
+		return next(ctx)
+	}
+}
+
+// validRequestID returns true if `id` is short and only has letters, digits and
+// the punctuation of common ID formats, so that it can be written to the logs
+// and the database as is.
+func validRequestID(id string) bool {
+	if len(id) == 0 || len(id) > maxRequestIDLength {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		c := id[i]
+		if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' ||
+			c == '-' || c == '_' || c == '.' || c == ':') {
+			return false
+		}
+	}
+	return true
+}
+
+func newRequestID() string {
+	var id [16]byte
+	rand.Read(id[:])
+	return hex.EncodeToString(id[:])
+}
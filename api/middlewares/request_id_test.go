@@ -0,0 +1,44 @@
+package middlewares
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/algorand/indexer/idb"
+)
+
+// requestID runs a request with the X-Request-ID `header` and returns the ID of
+// the request's context and the response header.
+func requestID(t *testing.T, header string) (string, string) {
+	req := httptest.NewRequest(http.MethodGet, "/health", nil)
+	if header != "" {
+		req.Header.Set(echo.HeaderXRequestID, header)
+	}
+	rec := httptest.NewRecorder()
+	var id string
+	handler := MakeRequestID()(func(ctx echo.Context) error {
+		id = idb.RequestIDFromContext(ctx.Request().Context())
+		return nil
+	})
+	require.NoError(t, handler(e.NewContext(req, rec)))
+	return id, rec.Header().Get(echo.HeaderXRequestID)
+}
+
+func TestRequestIDAccepted(t *testing.T) {
+	id, header := requestID(t, "4bf92f35-77b3-4da6-a3ce-929d0e0e4736")
+	assert.Equal(t, "4bf92f35-77b3-4da6-a3ce-929d0e0e4736", id)
+	assert.Equal(t, id, header)
+}
+
+func TestRequestIDGenerated(t *testing.T) {
+	for _, invalid := range []string{"", "id with spaces", "'; DROP TABLE txn; --", string(make([]byte, 65))} {
+		id, header := requestID(t, invalid)
+		assert.Len(t, id, 32, invalid)
+		assert.Equal(t, id, header)
+	}
+}
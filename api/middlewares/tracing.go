@@ -0,0 +1,45 @@
+package middlewares
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/algorand/indexer/util/tracing"
+)
+
+// MakeTracing initializes a tracing echo.MiddlewareFunc. It records a span of
+// every request, which continues the trace of the request's traceparent header,
+// and the database queries of the request are recorded as its children.
+func MakeTracing() echo.MiddlewareFunc {
+	return tracingHandler
+}
+
+func tracingHandler(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(ctx echo.Context) (err error) {
+		req := ctx.Request()
+		reqCtx := tracing.ContextWithTraceparent(req.Context(), req.Header.Get("traceparent"))
+		reqCtx, span := tracing.Start(reqCtx, req.Method+" "+ctx.Path(), trace.SpanKindServer,
+			attribute.String("http.method", req.Method),
+			attribute.String("http.route", ctx.Path()),
+			attribute.String("http.target", req.RequestURI))
+		defer span.End()
+		ctx.SetRequest(req.WithContext(reqCtx))
+		if !span.IsRecording() {
+			return next(ctx)
+		}
+
+		// The error is written here, like in the logger, to record the status.
+		if err = next(ctx); err != nil {
+			ctx.Error(err)
+		}
+		status := ctx.Response().Status
+		span.SetAttributes(attribute.Int64("http.status_code", int64(status)))
+		if status >= http.StatusInternalServerError {
+			tracing.SetError(span, err)
+		}
+		return
+	}
+}
@@ -0,0 +1,91 @@
+package api
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"net/url"
+
+	"github.com/labstack/echo/v4"
+)
+
+// Next tokens are signed so that clients cannot forge a cursor, or reuse the
+// cursor of one query with another query. A token is the base64 encoding of:
+//
+//	version (1 byte) | HMAC-SHA256(key, shape | 0 | cursor) truncated to 16 bytes | cursor
+//
+// where the shape is the path and the query parameters of the request which
+// created it, except `next` and `limit`.
+const nextTokenVersion = 1
+const nextTokenMacLen = 16
+
+// Query parameters which may change between pages of the same query.
+var nextTokenIgnoredParams = map[string]struct{}{
+	"next":  {},
+	"limit": {},
+}
+
+var errNextTokenMismatch = errors.New("next token was not issued for this query")
+
+// queryShape returns the parts of the request which must be the same for every
+// page of a query.
+func queryShape(ctx echo.Context) string {
+	params := make(url.Values)
+	for k, v := range ctx.QueryParams() {
+		if _, ok := nextTokenIgnoredParams[k]; !ok {
+			params[k] = v
+		}
+	}
+	// Encode() sorts by key.
+	return ctx.Request().URL.Path + "?" + params.Encode()
+}
+
+func nextTokenMac(key []byte, shape string, cursor string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(shape))
+	mac.Write([]byte{0})
+	mac.Write([]byte(cursor))
+	return mac.Sum(nil)[:nextTokenMacLen]
+}
+
+// encodeNextToken signs the cursor of the last result for the query of `ctx`.
+// It returns nil when there is no cursor.
+func encodeNextToken(key []byte, ctx echo.Context, cursor string) *string {
+	if cursor == "" {
+		return nil
+	}
+	b := make([]byte, 0, 1+nextTokenMacLen+len(cursor))
+	b = append(b, nextTokenVersion)
+	b = append(b, nextTokenMac(key, queryShape(ctx), cursor)...)
+	b = append(b, cursor...)
+	return strPtr(base64.RawURLEncoding.EncodeToString(b))
+}
+
+// decodeNextToken returns the cursor of a token created by encodeNextToken() for
+// the same query. A nil token returns nil.
+func decodeNextToken(key []byte, ctx echo.Context, token *string) (*string, error) {
+	if token == nil {
+		return nil, nil
+	}
+	b, err := base64.RawURLEncoding.DecodeString(*token)
+	if err != nil || len(b) < 1+nextTokenMacLen || b[0] != nextTokenVersion {
+		return nil, errors.New(errUnableToParseNext)
+	}
+	mac := b[1 : 1+nextTokenMacLen]
+	cursor := string(b[1+nextTokenMacLen:])
+	if !hmac.Equal(mac, nextTokenMac(key, queryShape(ctx), cursor)) {
+		return nil, errNextTokenMismatch
+	}
+	return &cursor, nil
+}
+
+// encodeNext is encodeNextToken() with the key of the server.
+func (si *ServerImplementation) encodeNext(ctx echo.Context, cursor string) *string {
+	return encodeNextToken(si.paginationKey, ctx, cursor)
+}
+
+// decodeNext is decodeNextToken() with the key of the server.
+func (si *ServerImplementation) decodeNext(ctx echo.Context, token *string) (*string, error) {
+	return decodeNextToken(si.paginationKey, ctx, token)
+}
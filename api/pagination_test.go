@@ -0,0 +1,69 @@
+package api
+
+import (
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func makeContext(target string) echo.Context {
+	e := echo.New()
+	return e.NewContext(httptest.NewRequest(http.MethodGet, target, nil), httptest.NewRecorder())
+}
+
+func TestNextTokenRoundTrip(t *testing.T) {
+	key := []byte("key")
+	token := encodeNextToken(key, makeContext("/v2/transactions?tx-type=pay&limit=5"), "cursor")
+	require.NotNil(t, token)
+
+	// The limit may change between pages, the order of parameters does not matter.
+	ctx := makeContext("/v2/transactions?limit=10&next=" + *token + "&tx-type=pay")
+	cursor, err := decodeNextToken(key, ctx, token)
+	require.NoError(t, err)
+	assert.Equal(t, "cursor", *cursor)
+
+	cursor, err = decodeNextToken(key, ctx, nil)
+	require.NoError(t, err)
+	assert.Nil(t, cursor)
+
+	assert.Nil(t, encodeNextToken(key, ctx, ""))
+}
+
+func TestNextTokenRejected(t *testing.T) {
+	key := []byte("key")
+	token := encodeNextToken(key, makeContext("/v2/transactions?tx-type=pay"), "cursor")
+	require.NotNil(t, token)
+
+	// Same signature, different cursor.
+	b, err := base64.RawURLEncoding.DecodeString(*token)
+	require.NoError(t, err)
+	b[len(b)-1] = 'x'
+	forged := base64.RawURLEncoding.EncodeToString(b)
+
+	testcases := []struct {
+		name   string
+		key    []byte
+		target string
+		token  string
+		err    string
+	}{
+		{"different parameter", key, "/v2/transactions?tx-type=axfer", *token, errNextTokenMismatch.Error()},
+		{"extra parameter", key, "/v2/transactions?tx-type=pay&round=5", *token, errNextTokenMismatch.Error()},
+		{"different path", key, "/v2/assets/1/transactions?tx-type=pay", *token, errNextTokenMismatch.Error()},
+		{"different key", []byte("other"), "/v2/transactions?tx-type=pay", *token, errNextTokenMismatch.Error()},
+		{"raw cursor", key, "/v2/transactions?tx-type=pay", "cursor", errUnableToParseNext},
+		{"forged cursor", key, "/v2/transactions?tx-type=pay", forged, errNextTokenMismatch.Error()},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := decodeNextToken(tc.key, makeContext(tc.target), &tc.token)
+			require.Error(t, err)
+			assert.Equal(t, tc.err, err.Error())
+		})
+	}
+}
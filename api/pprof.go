@@ -0,0 +1,29 @@
+package api
+
+import (
+	"net/http"
+	"net/http/pprof"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/algorand/indexer/api/middlewares"
+)
+
+// registerPprofHandlers adds the net/http/pprof handlers under /debug/pprof. They
+// are also available under /urlAuth/{token}/debug/pprof for tools like
+// `go tool pprof` which cannot set headers.
+func registerPprofHandlers(e *echo.Echo, adminTokens *middlewares.Tokens) {
+	auth := middlewares.MakeTokenAuth(AdminTokenHeader, adminTokens)
+
+	for _, prefix := range []string{"/debug/pprof", "/urlAuth/:token/debug/pprof"} {
+		g := e.Group(prefix, auth)
+		g.GET("/cmdline", echo.WrapHandler(http.HandlerFunc(pprof.Cmdline)))
+		g.GET("/profile", echo.WrapHandler(http.HandlerFunc(pprof.Profile)))
+		g.GET("/symbol", echo.WrapHandler(http.HandlerFunc(pprof.Symbol)))
+		g.POST("/symbol", echo.WrapHandler(http.HandlerFunc(pprof.Symbol)))
+		g.GET("/trace", echo.WrapHandler(http.HandlerFunc(pprof.Trace)))
+		// pprof.Index serves the index page and the named profiles (heap, goroutine, ...).
+		g.GET("/", echo.WrapHandler(http.HandlerFunc(pprof.Index)))
+		g.GET("/*", echo.WrapHandler(http.HandlerFunc(pprof.Index)))
+	}
+}
@@ -0,0 +1,43 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/algorand/indexer/api/middlewares"
+)
+
+func TestPprofRequiresAdminToken(t *testing.T) {
+	e := echo.New()
+	registerPprofHandlers(e, middlewares.MakeTokens([]string{"admin"}))
+
+	tests := []struct {
+		name   string
+		path   string
+		header string
+		code   int
+	}{
+		{"no token", "/debug/pprof/", "", http.StatusUnauthorized},
+		{"wrong token", "/debug/pprof/", "nope", http.StatusUnauthorized},
+		{"header token", "/debug/pprof/", "admin", http.StatusOK},
+		{"named profile", "/debug/pprof/goroutine", "admin", http.StatusOK},
+		{"url token", "/urlAuth/admin/debug/pprof/cmdline", "", http.StatusOK},
+		{"wrong url token", "/urlAuth/nope/debug/pprof/cmdline", "", http.StatusUnauthorized},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, test.path, nil)
+			if test.header != "" {
+				req.Header.Set(AdminTokenHeader, test.header)
+			}
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+			assert.Equal(t, test.code, rec.Code)
+		})
+	}
+}
@@ -0,0 +1,94 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+// latestRounder is implemented by block fetchers which know the latest round of
+// the network.
+type latestRounder interface {
+	LatestRound() (uint64, bool)
+}
+
+// ReadinessResponse is the response of the readiness check.
+type ReadinessResponse struct {
+	Ready bool `json:"ready"`
+
+	DBAvailable     bool   `json:"db-available"`
+	IsMigrating     bool   `json:"is-migrating"`
+	MigrationStatus string `json:"migration-status,omitempty"`
+
+	// Round is the latest imported round.
+	Round uint64 `json:"round"`
+
+	// ChainRound is the latest round reported by algod, it is omitted if the
+	// indexer is not following algod or has not heard from it yet.
+	ChainRound *uint64 `json:"chain-round,omitempty"`
+	Lag        *uint64 `json:"lag,omitempty"`
+
+	Errors []string `json:"errors,omitempty"`
+}
+
+// MakeReadinessCheck reports whether the indexer should receive traffic. Unlike
+// /health, which only shows that the server is alive, it returns 503 while the
+// database is unreachable, blocking migrations are running, or the import lags
+// the network by more than the configured number of rounds.
+// (GET /ready)
+func (si *ServerImplementation) MakeReadinessCheck(ctx echo.Context) error {
+	var response ReadinessResponse
+
+	health, err := si.db.Health()
+	if err != nil {
+		response.Errors = append(response.Errors, fmt.Sprintf("database unreachable: %v", err))
+		return ctx.JSON(http.StatusServiceUnavailable, response)
+	}
+
+	response.DBAvailable = health.DBAvailable
+	response.IsMigrating = health.IsMigrating
+	response.Round = health.Round
+	if health.Data != nil {
+		if status, ok := (*health.Data)["migration-status"].(string); ok {
+			response.MigrationStatus = status
+		}
+	}
+	if health.Error != "" {
+		response.Errors = append(response.Errors, fmt.Sprintf("database error: %s", health.Error))
+	}
+
+	ready := health.DBAvailable
+	if !health.DBAvailable {
+		response.Errors = append(response.Errors, "waiting for blocking migrations")
+	}
+
+	if si.fetcher != nil {
+		if si.fetcher.Error() != "" {
+			response.Errors = append(
+				response.Errors, fmt.Sprintf("fetcher error: %s", si.fetcher.Error()))
+		}
+		if lr, ok := si.fetcher.(latestRounder); ok {
+			if chainRound, ok := lr.LatestRound(); ok {
+				var lag uint64
+				if chainRound > health.Round {
+					lag = chainRound - health.Round
+				}
+				response.ChainRound = &chainRound
+				response.Lag = &lag
+				if si.readyMaxLag > 0 && lag > si.readyMaxLag {
+					ready = false
+					response.Errors = append(response.Errors, fmt.Sprintf(
+						"import is %d rounds behind, more than the allowed %d",
+						lag, si.readyMaxLag))
+				}
+			}
+		}
+	}
+
+	response.Ready = ready
+	if !ready {
+		return ctx.JSON(http.StatusServiceUnavailable, response)
+	}
+	return ctx.JSON(http.StatusOK, response)
+}
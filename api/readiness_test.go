@@ -0,0 +1,95 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/algorand/indexer/idb"
+	"github.com/algorand/indexer/idb/mocks"
+)
+
+type fakeFetcher struct {
+	round uint64
+	known bool
+}
+
+func (f fakeFetcher) Error() string {
+	return ""
+}
+
+func (f fakeFetcher) LatestRound() (uint64, bool) {
+	return f.round, f.known
+}
+
+func TestReadinessCheck(t *testing.T) {
+	tests := []struct {
+		name      string
+		health    idb.Health
+		healthErr error
+		fetcher   error
+		code      int
+	}{
+		{
+			name:    "ready",
+			health:  idb.Health{DBAvailable: true, Round: 95},
+			fetcher: fakeFetcher{round: 100, known: true},
+			code:    http.StatusOK,
+		},
+		{
+			name:   "no algod",
+			health: idb.Health{DBAvailable: true, Round: 95},
+			code:   http.StatusOK,
+		},
+		{
+			name:    "chain round unknown",
+			health:  idb.Health{DBAvailable: true, Round: 5},
+			fetcher: fakeFetcher{},
+			code:    http.StatusOK,
+		},
+		{
+			name:    "lagging",
+			health:  idb.Health{DBAvailable: true, Round: 5},
+			fetcher: fakeFetcher{round: 100, known: true},
+			code:    http.StatusServiceUnavailable,
+		},
+		{
+			name:   "blocking migration",
+			health: idb.Health{DBAvailable: false, IsMigrating: true},
+			code:   http.StatusServiceUnavailable,
+		},
+		{
+			name:      "database down",
+			healthErr: errors.New("connection refused"),
+			code:      http.StatusServiceUnavailable,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockIndexer := &mocks.IndexerDb{}
+			mockIndexer.On("Health").Return(test.health, test.healthErr)
+			si := ServerImplementation{
+				db:          mockIndexer,
+				fetcher:     test.fetcher,
+				readyMaxLag: 10,
+			}
+
+			e := echo.New()
+			rec := httptest.NewRecorder()
+			c := e.NewContext(httptest.NewRequest(http.MethodGet, "/ready", nil), rec)
+			require.NoError(t, si.MakeReadinessCheck(c))
+			assert.Equal(t, test.code, rec.Code)
+
+			var response ReadinessResponse
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+			assert.Equal(t, test.code == http.StatusOK, response.Ready)
+		})
+	}
+}
@@ -0,0 +1,64 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/algorand/indexer/api/generated/v2"
+)
+
+// Free-text search limits.
+const maxSearchLimit = 1000
+const defaultSearchLimit = 100
+
+// Kinds of documents which can be searched.
+var searchKinds = map[string]bool{
+	"notes":    true,
+	"assets":   true,
+	"app-keys": true,
+}
+
+// Searcher runs free-text searches, the daemon uses the Elasticsearch mirror.
+type Searcher interface {
+	// Search returns the documents of `kind` which match `query`, best matches
+	// first.
+	Search(ctx context.Context, kind string, query string, limit uint64) ([]json.RawMessage, error)
+}
+
+// SearchText searches the transaction notes, the asset names and URLs or the
+// application global state keys for the text of the `q` parameter.
+// (GET /v2/search/{kind})
+func (si *ServerImplementation) SearchText(ctx echo.Context, kind string, params generated.SearchTextParams) error {
+	if si.searcher == nil {
+		return notFound(ctx, errSearchDisabled)
+	}
+	if !searchKinds[kind] {
+		return notFound(ctx, fmt.Sprintf("%s: %s", errUnknownSearchKind, kind))
+	}
+	limit := uintOrDefaultValue(params.Limit, defaultSearchLimit)
+	if limit > maxSearchLimit {
+		limit = maxSearchLimit
+	}
+
+	docs, err := si.searcher.Search(ctx.Request().Context(), kind, params.Q, limit)
+	if err != nil {
+		return indexerError(ctx, fmt.Sprintf("%s: %v", errSearching, err))
+	}
+	results := make([]map[string]interface{}, 0, len(docs))
+	for _, doc := range docs {
+		// Numbers are kept as they are, e.g. uint64 asset IDs.
+		decoder := json.NewDecoder(bytes.NewReader(doc))
+		decoder.UseNumber()
+		var result map[string]interface{}
+		if err := decoder.Decode(&result); err != nil {
+			return indexerError(ctx, fmt.Sprintf("%s: %v", errSearching, err))
+		}
+		results = append(results, result)
+	}
+	return ctx.JSON(http.StatusOK, generated.SearchResponse{Results: results})
+}
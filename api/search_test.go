@@ -0,0 +1,79 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/algorand/indexer/api/generated/v2"
+)
+
+type fakeSearcher struct {
+	kind  string
+	query string
+	limit uint64
+	err   error
+}
+
+func (f *fakeSearcher) Search(ctx context.Context, kind string, query string, limit uint64) ([]json.RawMessage, error) {
+	f.kind = kind
+	f.query = query
+	f.limit = limit
+	if f.err != nil {
+		return nil, f.err
+	}
+	return []json.RawMessage{json.RawMessage(`{"txid": "A"}`)}, nil
+}
+
+func search(t *testing.T, si *ServerImplementation, path string) *httptest.ResponseRecorder {
+	e := echo.New()
+	generated.RegisterHandlers(e, si)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+	return rec
+}
+
+func TestSearchText(t *testing.T) {
+	searcher := &fakeSearcher{}
+	si := &ServerImplementation{searcher: searcher}
+
+	rec := search(t, si, "/v2/search/notes?q=hello+world")
+	require.Equal(t, http.StatusOK, rec.Code)
+	var response generated.SearchResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	require.Len(t, response.Results, 1)
+	assert.Equal(t, "A", response.Results[0]["txid"])
+	assert.Equal(t, "notes", searcher.kind)
+	assert.Equal(t, "hello world", searcher.query)
+	assert.Equal(t, uint64(defaultSearchLimit), searcher.limit)
+
+	rec = search(t, si, "/v2/search/assets?q=usd&limit=5000")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, uint64(maxSearchLimit), searcher.limit)
+
+	rec = search(t, si, "/v2/search/blocks?q=usd")
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+
+	rec = search(t, si, "/v2/search/notes")
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	searcher.err = errors.New("unavailable")
+	rec = search(t, si, "/v2/search/notes?q=hello")
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	assert.Contains(t, rec.Body.String(), errSearching)
+}
+
+func TestSearchTextDisabled(t *testing.T) {
+	si := &ServerImplementation{}
+
+	rec := search(t, si, "/v2/search/notes?q=hello")
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	assert.Contains(t, rec.Body.String(), errSearchDisabled)
+}
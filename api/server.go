@@ -2,6 +2,7 @@ package api
 
 import (
 	"context"
+	"crypto/rand"
 	"net"
 	"net/http"
 	"time"
@@ -30,8 +31,69 @@ type ExtraOptions struct {
 
 	// MetricsEndpointVerbose generates separate histograms based on query parameters on the /metrics endpoint.
 	MetricsEndpointVerbose bool
+
+	// AdminTokens are the access tokens which can access the admin endpoints. The
+	// admin endpoints are not served when there are none.
+	AdminTokens []string
+
+	// EnablePprof turns on the /debug/pprof endpoints, they require an admin token.
+	EnablePprof bool
+
+	// Middleware is installed on every route after the built in logging and CORS
+	// middleware, in the given order. It allows applications embedding the indexer
+	// to add things like authentication, tracing or header rewriting.
+	Middleware []echo.MiddlewareFunc
+
+	// ReadyMaxLag is the number of rounds the import may lag algod before /ready
+	// returns 503, 0 disables the lag check.
+	ReadyMaxLag uint64
+
+	// Importer allows the admin API to pause and resume the block importer.
+	Importer ImporterControl
+
+	// Config is the configuration returned by the admin API, it must not contain
+	// secrets.
+	Config map[string]string
+
+	// RoundWaiter is used to wait for new rounds without polling the database.
+	RoundWaiter RoundWaiter
+
+	// PaginationKey signs the next tokens. When it is empty the secret which is
+	// kept in the database is used, so tokens keep working across restarts and
+	// on every server which uses the same database.
+	PaginationKey []byte
+
+	// EnableExperimentalV3 serves the /v3 API, which may change between releases.
+	EnableExperimentalV3 bool
+
+	// DrainTimeout is how long requests in flight may run after the context of
+	// Serve is canceled, new connections are refused in the meantime. Defaults to
+	// defaultDrainTimeout.
+	DrainTimeout time.Duration
+
+	// Searcher serves the free-text search endpoint, /v2/search/{kind} returns
+	// 404 when it is nil.
+	Searcher Searcher
+
+	// TokenSet replaces Tokens when it is set, so that the tokens can be changed
+	// while the server runs. Every request is allowed while it is empty.
+	TokenSet *middlewares.Tokens
+
+	// AdminTokenSet replaces AdminTokens when it is set. The admin endpoints are
+	// served, and refuse every request while it is empty.
+	AdminTokenSet *middlewares.Tokens
+
+	// RateLimit limits the API requests of each client, there is no limit when
+	// it is nil. The admin and health endpoints are not limited.
+	RateLimit *middlewares.RateLimit
+
+	// DisabledEndpoints are routes which respond 404, e.g. expensive searches on
+	// a public server.
+	DisabledEndpoints *middlewares.DisabledEndpoints
 }
 
+const defaultDrainTimeout = time.Second
+
 // Serve starts an http server for the indexer API. This call blocks.
 func Serve(ctx context.Context, serveAddr string, db idb.IndexerDb, fetcherError error, log *log.Logger, options ExtraOptions) {
 	e := echo.New()
@@ -49,31 +111,107 @@ func Serve(ctx context.Context, serveAddr string, db idb.IndexerDb, fetcherError
 		p.Use(e)
 	}
 
+	e.Use(middlewares.MakeRequestID())
 	e.Use(middlewares.MakeLogger(log))
+	e.Use(middlewares.MakeReporting())
+	e.Use(middlewares.MakeTracing())
 	e.Use(middleware.CORS())
+	e.Use(options.Middleware...)
 
 	middleware := make([]echo.MiddlewareFunc, 0)
 
+	if options.DisabledEndpoints != nil {
+		middleware = append(middleware, middlewares.MakeDisabledEndpointsMiddleware(options.DisabledEndpoints))
+	}
+	if options.RateLimit != nil {
+		middleware = append(middleware, middlewares.MakeRateLimitMiddleware(options.RateLimit))
+	}
 	middleware = append(middleware, middlewares.MakeMigrationMiddleware(db))
 
-	if len(options.Tokens) > 0 {
-		middleware = append(middleware, middlewares.MakeAuth("X-Indexer-API-Token", options.Tokens))
+	tokens := options.TokenSet
+	if tokens == nil && len(options.Tokens) > 0 {
+		tokens = middlewares.MakeTokens(options.Tokens)
+	}
+	if tokens != nil {
+		middleware = append(middleware, middlewares.MakeOptionalAuth("X-Indexer-API-Token", tokens))
+	}
+	adminTokens := options.AdminTokenSet
+	if adminTokens == nil && len(options.AdminTokens) > 0 {
+		adminTokens = middlewares.MakeTokens(options.AdminTokens)
+	}
+
+	paginationKey := options.PaginationKey
+	if len(paginationKey) == 0 {
+		var err error
+		paginationKey, err = db.PaginationSecret(ctx)
+		if err != nil {
+			log.WithError(err).Warn("failed to get the pagination secret, next tokens expire on restart")
+		}
+	}
+	if len(paginationKey) == 0 {
+		paginationKey = make([]byte, 32)
+		if _, err := rand.Read(paginationKey); err != nil {
+			log.Fatalf("failed to generate pagination key: %v", err)
+		}
 	}
 
 	api := ServerImplementation{
 		EnableAddressSearchRoundRewind: options.DeveloperMode,
 		db:                             db,
 		fetcher:                        fetcherError,
+		readyMaxLag:                    options.ReadyMaxLag,
+		importer:                       options.Importer,
+		config:                         options.Config,
+		roundWaiter:                    options.RoundWaiter,
+		paginationKey:                  paginationKey,
+		searcher:                       options.Searcher,
 	}
 
 	generated.RegisterHandlers(e, &api, middleware...)
 	common.RegisterHandlers(e, &api)
+	e.GET("/ready", api.MakeReadinessCheck)
+	e.GET("/version", api.GetVersion)
+	if specHandler, err := makeSpecHandler(); err == nil {
+		e.GET("/openapi.json", specHandler)
+	} else {
+		log.WithError(err).Warn("the OpenAPI document is not served")
+	}
+
+	if options.EnableExperimentalV3 {
+		registerV3Handlers(e, &api, middleware...)
+	}
+
+	if adminTokens != nil {
+		registerAdminHandlers(e, &api, adminTokens)
+	}
+
+	// The query explainer is available to everyone in developer mode, otherwise
+	// only to admins.
+	if options.DeveloperMode {
+		registerExplainHandler(e, &api, middleware...)
+	} else if adminTokens != nil {
+		registerExplainHandler(
+			e, &api, middlewares.MakeMigrationMiddleware(db),
+			middlewares.MakeTokenAuth(AdminTokenHeader, adminTokens))
+	}
+
+	if options.EnablePprof {
+		if adminTokens != nil {
+			registerPprofHandlers(e, adminTokens)
+		} else {
+			log.Warn("pprof endpoints are disabled because no admin token is configured")
+		}
+	}
 
 	if ctx == nil {
 		ctx = context.Background()
 	}
+	// Requests use a context which outlives ctx so that the requests in flight
+	// when ctx is canceled can finish.
+	requestCtx, cancelRequests := context.WithCancel(context.Background())
+	defer cancelRequests()
 	getctx := func(l net.Listener) context.Context {
-		return ctx
+		return requestCtx
 	}
 	s := &http.Server{
 		Addr:           serveAddr,
@@ -84,14 +222,26 @@ func Serve(ctx context.Context, serveAddr string, db idb.IndexerDb, fetcherError
 	}
 
 	go func() {
-		log.Fatal(e.StartServer(s))
+		err := e.StartServer(s)
+		if err != nil && err != http.ErrServerClosed {
+			log.Fatal(err)
+		}
 	}()
 
 	<-ctx.Done()
-	// Allow one second for graceful shutdown.
-	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+
+	drainTimeout := options.DrainTimeout
+	if drainTimeout == 0 {
+		drainTimeout = defaultDrainTimeout
+	}
+	log.Infof("stopped accepting connections, draining requests for up to %s", drainTimeout)
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), drainTimeout)
 	defer cancel()
-	if err := e.Shutdown(ctx); err != nil {
-		log.Fatal(err)
+	err := s.Shutdown(shutdownCtx)
+	// Cancel the requests which are still running, the database stops their
+	// queries and closes their result channels.
+	cancelRequests()
+	if err != nil {
+		log.WithError(err).Warn("requests were still running after the drain timeout")
 	}
 }
@@ -0,0 +1,167 @@
+package api
+
+import (
+	"context"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/algorand/indexer/idb"
+	"github.com/algorand/indexer/idb/mocks"
+)
+
+// serveForTest starts Serve with a handler for /slow and returns once it accepts
+// connections. Serve is stopped with the returned cancel function, the returned
+// channel is closed when it returns.
+func serveForTest(t *testing.T, slow echo.HandlerFunc, drainTimeout time.Duration) (string, context.CancelFunc, <-chan struct{}) {
+	slowMiddleware := func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if c.Request().URL.Path == "/slow" {
+				return slow(c)
+			}
+			return next(c)
+		}
+	}
+	options := ExtraOptions{
+		Middleware:    []echo.MiddlewareFunc{slowMiddleware},
+		DrainTimeout:  drainTimeout,
+		PaginationKey: []byte("test"),
+	}
+	return startForTest(t, &mocks.IndexerDb{}, options)
+}
+
+// startForTest starts Serve and returns once it accepts connections, like
+// serveForTest.
+func startForTest(t *testing.T, db idb.IndexerDb, options ExtraOptions) (string, context.CancelFunc, <-chan struct{}) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	addr := listener.Addr().String()
+	listener.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		Serve(ctx, addr, db, nil, logrus.New(), options)
+		close(done)
+	}()
+
+	for i := 0; ; i++ {
+		conn, err := net.Dial("tcp", addr)
+		if err == nil {
+			conn.Close()
+			break
+		}
+		require.Less(t, i, 100, "server did not start")
+		time.Sleep(10 * time.Millisecond)
+	}
+	return addr, cancel, done
+}
+
+func TestServeDrainsRequestsInFlight(t *testing.T) {
+	started := make(chan struct{})
+	slow := func(c echo.Context) error {
+		close(started)
+		time.Sleep(200 * time.Millisecond)
+		return c.String(http.StatusOK, "done")
+	}
+	addr, cancel, done := serveForTest(t, slow, 5*time.Second)
+
+	type result struct {
+		body string
+		err  error
+	}
+	results := make(chan result, 1)
+	go func() {
+		resp, err := http.Get("http://" + addr + "/slow")
+		if err != nil {
+			results <- result{err: err}
+			return
+		}
+		defer resp.Body.Close()
+		body, err := ioutil.ReadAll(resp.Body)
+		results <- result{body: string(body), err: err}
+	}()
+
+	<-started
+	cancel()
+
+	res := <-results
+	require.NoError(t, res.err)
+	assert.Equal(t, "done", res.body)
+	<-done
+
+	// New connections are refused once the server stopped.
+	_, err := net.Dial("tcp", addr)
+	assert.Error(t, err)
+}
+
+func TestServeCancelsRequestsAfterDrainTimeout(t *testing.T) {
+	started := make(chan struct{})
+	canceled := make(chan struct{})
+	slow := func(c echo.Context) error {
+		close(started)
+		<-c.Request().Context().Done()
+		close(canceled)
+		return c.Request().Context().Err()
+	}
+	addr, cancel, done := serveForTest(t, slow, 100*time.Millisecond)
+
+	go http.Get("http://" + addr + "/slow")
+	<-started
+	cancel()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Serve did not return after the drain timeout")
+	}
+	select {
+	case <-canceled:
+	case <-time.After(5 * time.Second):
+		t.Fatal("the request was not canceled")
+	}
+}
+
+func TestServeMiddleware(t *testing.T) {
+	var mu sync.Mutex
+	var calls []string
+	record := func(name string) echo.MiddlewareFunc {
+		return func(next echo.HandlerFunc) echo.HandlerFunc {
+			return func(c echo.Context) error {
+				mu.Lock()
+				calls = append(calls, name+" "+c.Path())
+				mu.Unlock()
+				return next(c)
+			}
+		}
+	}
+	db := &mocks.IndexerDb{}
+	db.On("Health").Return(idb.Health{DBAvailable: true}, nil)
+	options := ExtraOptions{
+		Middleware:    []echo.MiddlewareFunc{record("first"), record("second")},
+		PaginationKey: []byte("test"),
+	}
+	addr, cancel, done := startForTest(t, db, options)
+	defer func() {
+		cancel()
+		<-done
+	}()
+
+	// The middleware runs in order on the generated routes, after routing.
+	resp, err := http.Get("http://" + addr + "/v2/accounts/invalid")
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, []string{"first /v2/accounts/:account-id", "second /v2/accounts/:account-id"}, calls)
+}
@@ -0,0 +1,83 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/algorand/indexer/api/generated/v2"
+)
+
+// The /v3 API is experimental, it is only served with
+// ExtraOptions.EnableExperimentalV3 and may change between releases. It is where
+// breaking improvements are made while /v2 stays stable:
+//   - next-token is only returned when there may be more results, clients stop
+//     paging when it is missing instead of making a request for an empty page.
+//   - byte fields are only returned base64 encoded, the printable copies of
+//     asset names, unit names and urls are omitted.
+
+// TransactionsResponseV3 is the response of the /v3 transaction search.
+type TransactionsResponseV3 struct {
+	CurrentRound uint64                  `json:"current-round"`
+	NextToken    *string                 `json:"next-token,omitempty"`
+	Transactions []generated.Transaction `json:"transactions"`
+}
+
+// v3Server implements the /v3 endpoints which take the same parameters as their
+// /v2 version. Embedding ServerImplementation lets it reuse the generated
+// parameter parsing.
+type v3Server struct {
+	*ServerImplementation
+}
+
+// SearchForTransactions returns transactions matching the provided parameters.
+// (GET /v3/transactions)
+func (vs v3Server) SearchForTransactions(ctx echo.Context, params generated.SearchForTransactionsParams) error {
+	var err error
+	params.Next, err = vs.decodeNext(ctx, params.Next)
+	if err != nil {
+		return badRequest(ctx, err.Error())
+	}
+
+	filter, err := transactionParamsToTransactionFilter(params)
+	if err != nil {
+		return badRequest(ctx, err.Error())
+	}
+
+	txns, next, round, err := vs.fetchTransactions(ctx.Request().Context(), filter)
+	if err != nil {
+		return indexerError(ctx, fmt.Sprintf("%s: %v", errTransactionSearch, err))
+	}
+
+	response := TransactionsResponseV3{
+		CurrentRound: round,
+		Transactions: txns,
+	}
+	// A short page is the last one.
+	if uint64(len(txns)) == filter.Limit {
+		response.NextToken = vs.encodeNext(ctx, next)
+	}
+	for i := range response.Transactions {
+		transactionToV3(&response.Transactions[i])
+	}
+
+	return ctx.JSON(http.StatusOK, response)
+}
+
+// transactionToV3 removes the fields of a /v2 transaction which are not in /v3.
+func transactionToV3(txn *generated.Transaction) {
+	if txn.AssetConfigTransaction != nil && txn.AssetConfigTransaction.Params != nil {
+		params := txn.AssetConfigTransaction.Params
+		params.Name = nil
+		params.UnitName = nil
+		params.Url = nil
+	}
+}
+
+// registerV3Handlers adds the experimental /v3 route group.
+func registerV3Handlers(e *echo.Echo, si *ServerImplementation, middleware ...echo.MiddlewareFunc) {
+	wrapper := generated.ServerInterfaceWrapper{Handler: v3Server{si}}
+	v3 := e.Group("/v3", middleware...)
+	v3.GET("/transactions", wrapper.SearchForTransactions)
+}
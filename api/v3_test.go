@@ -0,0 +1,62 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/algorand/go-algorand/protocol"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/algorand/indexer/idb"
+	"github.com/algorand/indexer/idb/mocks"
+	"github.com/algorand/indexer/util/test"
+)
+
+func searchV3(t *testing.T, rows int, target string) TransactionsResponseV3 {
+	stxn := test.MakeConfigAssetTxn(
+		0, 100, 0, false, "UNIT", "name", "https://example.com", test.AccountA)
+	ch := make(chan idb.TxnRow, rows)
+	for i := 0; i < rows; i++ {
+		ch <- idb.TxnRow{Round: 1, Intra: i, TxnBytes: protocol.Encode(&stxn)}
+	}
+	close(ch)
+	var outCh <-chan idb.TxnRow = ch
+
+	mockIndexer := &mocks.IndexerDb{}
+	mockIndexer.On("Transactions", mock.Anything, mock.Anything).Return(outCh, uint64(1))
+
+	e := echo.New()
+	registerV3Handlers(e, &ServerImplementation{db: mockIndexer})
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, target, nil))
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	var response TransactionsResponseV3
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	require.Len(t, response.Transactions, rows)
+	return response
+}
+
+func TestV3NextTokenOnlyForFullPages(t *testing.T) {
+	response := searchV3(t, 2, "/v3/transactions?limit=2")
+	assert.NotNil(t, response.NextToken)
+
+	response = searchV3(t, 1, "/v3/transactions?limit=2")
+	assert.Nil(t, response.NextToken)
+}
+
+func TestV3OmitsPrintableCopies(t *testing.T) {
+	response := searchV3(t, 1, "/v3/transactions")
+	params := response.Transactions[0].AssetConfigTransaction.Params
+	require.NotNil(t, params)
+	assert.Nil(t, params.Name)
+	assert.Nil(t, params.UnitName)
+	assert.Nil(t, params.Url)
+	require.NotNil(t, params.NameB64)
+	assert.Equal(t, []byte("name"), *params.NameB64)
+}
@@ -0,0 +1,16 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/algorand/indexer/version"
+)
+
+// GetVersion returns the version, git commit and go version of the binary, the
+// labels of the build info metric.
+// (GET /version)
+func (si *ServerImplementation) GetVersion(ctx echo.Context) error {
+	return ctx.JSON(http.StatusOK, version.Info())
+}
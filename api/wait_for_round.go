@@ -0,0 +1,75 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/algorand/indexer/api/generated/v2"
+)
+
+// waitForRoundTimeout is how long a wait for round request is held open. It must
+// stay below the server's write timeout.
+const waitForRoundTimeout = 8 * time.Second
+
+// roundPollInterval is how often the database is checked for new rounds when the
+// server does not import blocks itself.
+const roundPollInterval = time.Second
+
+// RoundWaiter is notified by the importer when rounds are imported.
+type RoundWaiter interface {
+	// WaitForRoundAfter blocks until a round greater than `round` has been
+	// imported or the context is done, and returns the latest imported round.
+	WaitForRoundAfter(ctx context.Context, round uint64) uint64
+}
+
+// WaitForRoundAfter waits until a round after the given one has been imported, or
+// until a timeout elapses, and returns the latest imported round.
+// (GET /v2/status/wait-for-round-after/{round})
+func (si *ServerImplementation) WaitForRoundAfter(ctx echo.Context, round uint64) error {
+	waitCtx, cancel := context.WithTimeout(ctx.Request().Context(), waitForRoundTimeout)
+	defer cancel()
+
+	var latest uint64
+	var err error
+	if si.roundWaiter != nil {
+		latest = si.roundWaiter.WaitForRoundAfter(waitCtx, round)
+	} else {
+		latest, err = si.pollForRoundAfter(waitCtx, round)
+		if err != nil {
+			return indexerError(ctx, fmt.Sprintf("%s: %v", errWaitingForRound, err))
+		}
+	}
+
+	return ctx.JSON(http.StatusOK, generated.WaitForRoundResponse{CurrentRound: latest})
+}
+
+// pollForRoundAfter is used by read only servers which are not notified of new
+// rounds.
+func (si *ServerImplementation) pollForRoundAfter(ctx context.Context, round uint64) (uint64, error) {
+	ticker := time.NewTicker(roundPollInterval)
+	defer ticker.Stop()
+
+	for {
+		next, err := si.db.GetNextRoundToAccount()
+		if err != nil {
+			return 0, err
+		}
+		var latest uint64
+		if next > 0 {
+			latest = next - 1
+		}
+		if latest > round {
+			return latest, nil
+		}
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return latest, nil
+		}
+	}
+}
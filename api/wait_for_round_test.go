@@ -0,0 +1,58 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/algorand/indexer/api/generated/v2"
+	"github.com/algorand/indexer/idb/mocks"
+)
+
+type fakeRoundWaiter struct {
+	round uint64
+}
+
+func (f fakeRoundWaiter) WaitForRoundAfter(ctx context.Context, round uint64) uint64 {
+	return f.round
+}
+
+func waitForRound(t *testing.T, si *ServerImplementation, path string) *httptest.ResponseRecorder {
+	e := echo.New()
+	generated.RegisterHandlers(e, si)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+	return rec
+}
+
+func TestWaitForRoundAfterPublished(t *testing.T) {
+	si := &ServerImplementation{roundWaiter: fakeRoundWaiter{round: 6}}
+
+	rec := waitForRound(t, si, "/v2/status/wait-for-round-after/5")
+	require.Equal(t, http.StatusOK, rec.Code)
+	var response generated.WaitForRoundResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	assert.Equal(t, uint64(6), response.CurrentRound)
+
+	rec = waitForRound(t, si, "/v2/status/wait-for-round-after/five")
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestWaitForRoundAfterPolling(t *testing.T) {
+	mockIndexer := &mocks.IndexerDb{}
+	mockIndexer.On("GetNextRoundToAccount").Return(uint64(5), nil).Once()
+	mockIndexer.On("GetNextRoundToAccount").Return(uint64(8), nil)
+	si := &ServerImplementation{db: mockIndexer}
+
+	rec := waitForRound(t, si, "/v2/status/wait-for-round-after/5")
+	require.Equal(t, http.StatusOK, rec.Code)
+	var response generated.WaitForRoundResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	assert.Equal(t, uint64(7), response.CurrentRound)
+}
@@ -0,0 +1,102 @@
+package api
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/algorand/indexer/idb"
+)
+
+// randomHex returns `n` random bytes in hex.
+func randomHex(n int) (string, error) {
+	b := make([]byte, n)
+	_, err := rand.Read(b)
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// checkWebhook returns why `hook` can't be registered, or nil.
+func checkWebhook(hook idb.Webhook) error {
+	u, err := url.Parse(hook.URL)
+	if err != nil {
+		return err
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("url must be http or https")
+	}
+	_, err = idb.MakeTxnRules(idb.TxnRulesConfig{Rules: []idb.TxnRule{hook.Filter}})
+	return err
+}
+
+// listWebhooks returns the registered webhooks, without their secrets.
+// (GET /admin/webhooks)
+func (si *ServerImplementation) listWebhooks(ctx echo.Context) error {
+	hooks, err := si.db.Webhooks(ctx.Request().Context())
+	if err != nil {
+		return indexerError(ctx, fmt.Sprintf("%s: %v", errLoadingWebhooks, err))
+	}
+	for i := range hooks {
+		hooks[i].Secret = ""
+	}
+	if hooks == nil {
+		hooks = []idb.Webhook{}
+	}
+	return ctx.JSON(http.StatusOK, hooks)
+}
+
+// addWebhook registers the webhook in the body. A missing id or secret is
+// generated. The response has the secret, it is not returned again.
+// (POST /admin/webhooks)
+func (si *ServerImplementation) addWebhook(ctx echo.Context) error {
+	var hook idb.Webhook
+	err := ctx.Bind(&hook)
+	if err != nil {
+		return badRequest(ctx, fmt.Sprintf("%s: %v", errInvalidWebhook, err))
+	}
+	err = checkWebhook(hook)
+	if err != nil {
+		return badRequest(ctx, fmt.Sprintf("%s: %v", errInvalidWebhook, err))
+	}
+	if hook.ID == "" {
+		hook.ID, err = randomHex(8)
+		if err != nil {
+			return indexerError(ctx, err.Error())
+		}
+	}
+	if hook.Secret == "" {
+		hook.Secret, err = randomHex(32)
+		if err != nil {
+			return indexerError(ctx, err.Error())
+		}
+	}
+
+	err = si.db.AddWebhook(ctx.Request().Context(), hook)
+	if errors.Is(err, idb.ErrorWebhookExists) {
+		return conflict(ctx, errWebhookExists)
+	}
+	if err != nil {
+		return indexerError(ctx, err.Error())
+	}
+	return ctx.JSON(http.StatusCreated, hook)
+}
+
+// deleteWebhook removes a webhook.
+// (DELETE /admin/webhooks/{id})
+func (si *ServerImplementation) deleteWebhook(ctx echo.Context) error {
+	deleted, err := si.db.DeleteWebhook(ctx.Request().Context(), ctx.Param("id"))
+	if err != nil {
+		return indexerError(ctx, err.Error())
+	}
+	if !deleted {
+		return notFound(ctx, errNoWebhookFound)
+	}
+	return ctx.NoContent(http.StatusNoContent)
+}
@@ -0,0 +1,160 @@
+package blockstream
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/algorand/go-algorand/data/basics"
+	"github.com/algorand/go-algorand/data/bookkeeping"
+	"github.com/algorand/go-algorand/ledger/ledgercore"
+	"github.com/algorand/go-algorand/protocol"
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	"github.com/algorand/indexer/api/middlewares"
+	"github.com/algorand/indexer/blockstream/generated"
+	"github.com/algorand/indexer/util/test"
+)
+
+func addRound(b *Buffer, round uint64) {
+	block := bookkeeping.Block{BlockHeader: bookkeeping.BlockHeader{Round: basics.Round(round)}}
+	delta := ledgercore.StateDelta{
+		Creatables: map[basics.CreatableIndex]ledgercore.ModifiedCreatable{
+			basics.CreatableIndex(round): {Ctype: basics.AssetCreatable, Created: true, Creator: test.AccountA},
+		},
+		ModifiedAssetHoldings: map[ledgercore.AccountAsset]bool{
+			{Address: test.AccountB, Asset: basics.AssetIndex(round)}: false,
+		},
+	}
+	b.AfterBlock(&block, delta)
+}
+
+func TestMakeDelta(t *testing.T) {
+	delta := ledgercore.StateDelta{
+		Creatables: map[basics.CreatableIndex]ledgercore.ModifiedCreatable{
+			9: {Ctype: basics.AppCreatable, Created: false, Creator: test.AccountA},
+			3: {Ctype: basics.AssetCreatable, Created: true, Creator: test.AccountB},
+		},
+		ModifiedAppLocalStates: map[ledgercore.AccountApp]bool{
+			{Address: test.AccountA, App: 9}: true,
+		},
+	}
+
+	var decoded Delta
+	require.NoError(t, protocol.DecodeReflect(protocol.EncodeReflect(MakeDelta(delta)), &decoded))
+	assert.Equal(t, []Creatable{
+		{Index: 3, Type: basics.AssetCreatable, Created: true, Creator: test.AccountB},
+		{Index: 9, Type: basics.AppCreatable, Created: false, Creator: test.AccountA},
+	}, decoded.Creatables)
+	assert.Equal(t, []AppLocalState{{Address: test.AccountA, App: 9, Created: true}}, decoded.AppLocalStates)
+}
+
+func TestBuffer(t *testing.T) {
+	b := MakeBuffer(2)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err := b.Next(ctx, 0)
+	assert.Equal(t, context.DeadlineExceeded, err)
+
+	addRound(b, 1)
+	addRound(b, 2)
+	addRound(b, 3)
+	_, err = b.Next(context.Background(), 1)
+	assert.Equal(t, errRoundNotBuffered, err)
+	messages, err := b.Next(context.Background(), 2)
+	require.NoError(t, err)
+	require.Len(t, messages, 2)
+	assert.Equal(t, uint64(2), messages[0].Round)
+
+	var block bookkeeping.Block
+	require.NoError(t, protocol.Decode(messages[1].Block, &block))
+	assert.Equal(t, basics.Round(3), block.Round())
+	var delta Delta
+	require.NoError(t, protocol.DecodeReflect(messages[1].Delta, &delta))
+	assert.Equal(t, []AssetHolding{{Address: test.AccountB, Asset: 3}}, delta.AssetHoldings)
+
+	// A waiting stream gets the next round.
+	done := make(chan []Message)
+	go func() {
+		messages, _ := b.Next(context.Background(), 4)
+		done <- messages
+	}()
+	addRound(b, 4)
+	messages = <-done
+	require.Len(t, messages, 1)
+	assert.Equal(t, uint64(4), messages[0].Round)
+
+	// A gap starts the buffer again.
+	addRound(b, 8)
+	_, err = b.Next(context.Background(), 4)
+	assert.Equal(t, errRoundNotBuffered, err)
+}
+
+// streamBlocks returns the rounds of a StreamBlocks stream from `round`, and
+// the status which ended it.
+func streamBlocks(t *testing.T, client generated.BlockStreamClient, round uint64, md ...string) ([]uint64, codes.Code) {
+	ctx := metadata.AppendToOutgoingContext(context.Background(), md...)
+	stream, err := client.StreamBlocks(ctx, &generated.StreamBlocksRequest{StartRound: round})
+	require.NoError(t, err)
+	var rounds []uint64
+	for {
+		block, err := stream.Recv()
+		if err != nil {
+			return rounds, status.Code(err)
+		}
+		rounds = append(rounds, block.Round)
+	}
+}
+
+func TestServer(t *testing.T) {
+	b := MakeBuffer(10)
+	addRound(b, 5)
+	addRound(b, 6)
+	tokens := middlewares.MakeTokens([]string{"old"})
+	s := MakeServer(b, tokens, log.New())
+
+	listener := bufconn.Listen(1 << 20)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		done <- serve(ctx, listener, s, log.New())
+	}()
+	defer func() {
+		cancel()
+		assert.NoError(t, <-done)
+	}()
+	conn, err := grpc.Dial("bufconn",
+		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) {
+			return listener.Dial()
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	defer conn.Close()
+	client := generated.NewBlockStreamClient(conn)
+
+	_, code := streamBlocks(t, client, 5)
+	assert.Equal(t, codes.Unauthenticated, code)
+
+	// The tokens are replaced when the configuration is reloaded.
+	tokens.Set([]string{"token"})
+	_, code = streamBlocks(t, client, 4, TokenHeader, "old")
+	assert.Equal(t, codes.Unauthenticated, code)
+
+	_, code = streamBlocks(t, client, 4, TokenHeader, "token")
+	assert.Equal(t, codes.OutOfRange, code)
+
+	// A stopped server streams the buffered rounds and ends the stream.
+	s.Stop()
+	rounds, code := streamBlocks(t, client, 5, "Authorization", "Bearer token")
+	assert.Equal(t, codes.Unavailable, code)
+	assert.Equal(t, []uint64{5, 6}, rounds)
+}
@@ -0,0 +1,102 @@
+package blockstream
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/algorand/go-algorand/data/bookkeeping"
+	"github.com/algorand/go-algorand/ledger/ledgercore"
+	"github.com/algorand/go-algorand/protocol"
+)
+
+// errRoundNotBuffered is returned for a round which is no longer, or was never,
+// in the buffer.
+var errRoundNotBuffered = errors.New("round is not buffered")
+
+// Message is a streamed block.
+type Message struct {
+	Round uint64
+
+	// Block is the msgpack encoding of the block.
+	Block []byte
+
+	// Delta is the msgpack encoding of the Delta of the block.
+	Delta []byte
+}
+
+// Buffer is an idb.BlockHook which keeps the latest imported blocks and their
+// deltas for the streams. The delta of a block is computed once, when it is
+// imported, so a stream can only start at a buffered round.
+type Buffer struct {
+	size int
+
+	mu sync.Mutex
+	// messages are of consecutive rounds, oldest first.
+	messages []Message
+	// changed is closed when a message is added.
+	changed chan struct{}
+}
+
+// MakeBuffer creates a Buffer which keeps the latest `size` blocks.
+func MakeBuffer(size int) *Buffer {
+	return &Buffer{
+		size:    size,
+		changed: make(chan struct{}),
+	}
+}
+
+// BeforeBlock is part of idb.BlockHook.
+func (b *Buffer) BeforeBlock(block *bookkeeping.Block, delta ledgercore.StateDelta) error {
+	return nil
+}
+
+// AfterBlock is part of idb.BlockHook.
+func (b *Buffer) AfterBlock(block *bookkeeping.Block, delta ledgercore.StateDelta) {
+	message := Message{
+		Round: uint64(block.Round()),
+		Block: protocol.Encode(block),
+		Delta: protocol.EncodeReflect(MakeDelta(delta)),
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	// The buffer starts again after a gap, e.g. after the import was reset.
+	if len(b.messages) > 0 && b.messages[len(b.messages)-1].Round+1 != message.Round {
+		b.messages = nil
+	}
+	b.messages = append(b.messages, message)
+	if len(b.messages) > b.size {
+		b.messages = append([]Message(nil), b.messages[len(b.messages)-b.size:]...)
+	}
+	close(b.changed)
+	b.changed = make(chan struct{})
+}
+
+// Next returns the buffered messages from `round` on, waiting until `round` is
+// imported. It returns errRoundNotBuffered if `round` is older than the buffer.
+func (b *Buffer) Next(ctx context.Context, round uint64) ([]Message, error) {
+	for {
+		b.mu.Lock()
+		messages := b.messages
+		changed := b.changed
+		b.mu.Unlock()
+
+		if len(messages) > 0 {
+			first := messages[0].Round
+			if round < first {
+				return nil, errRoundNotBuffered
+			}
+			if round-first < uint64(len(messages)) {
+				return messages[round-first:], nil
+			}
+		}
+
+		select {
+		case <-changed:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
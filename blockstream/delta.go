@@ -0,0 +1,92 @@
+package blockstream
+
+import (
+	"bytes"
+	"sort"
+
+	"github.com/algorand/go-algorand/data/basics"
+	"github.com/algorand/go-algorand/ledger/ledgercore"
+)
+
+// Delta is the state delta of a block as computed by the evaluator, in a form
+// which can be encoded. It is streamed as msgpack.
+type Delta struct {
+	// Accounts are the new states of the accounts which changed.
+	Accounts []basics.BalanceRecord `codec:"accts"`
+
+	// Creatables are the assets and applications created or deleted.
+	Creatables []Creatable `codec:"creatables"`
+
+	// AssetHoldings are the asset holdings opted in or closed out.
+	AssetHoldings []AssetHolding `codec:"asset-holdings"`
+
+	// AppLocalStates are the application local states opted in or cleared.
+	AppLocalStates []AppLocalState `codec:"app-local-states"`
+}
+
+// Creatable is an asset or application which was created or deleted.
+type Creatable struct {
+	Index   basics.CreatableIndex `codec:"index"`
+	Type    basics.CreatableType  `codec:"type"`
+	Created bool                  `codec:"created"`
+	Creator basics.Address        `codec:"creator"`
+}
+
+// AssetHolding is an asset holding which was opted in or closed out.
+type AssetHolding struct {
+	Address basics.Address    `codec:"address"`
+	Asset   basics.AssetIndex `codec:"asset"`
+	Created bool              `codec:"created"`
+}
+
+// AppLocalState is an application local state which was opted in or cleared.
+type AppLocalState struct {
+	Address basics.Address  `codec:"address"`
+	App     basics.AppIndex `codec:"app"`
+	Created bool            `codec:"created"`
+}
+
+// MakeDelta converts `delta`. The maps of the state delta are sorted, so that a
+// delta is always encoded the same way.
+func MakeDelta(delta ledgercore.StateDelta) Delta {
+	var d Delta
+	for i := 0; i < delta.Accts.Len(); i++ {
+		address, data := delta.Accts.GetByIdx(i)
+		d.Accounts = append(d.Accounts, basics.BalanceRecord{Addr: address, AccountData: data})
+	}
+
+	for index, creatable := range delta.Creatables {
+		d.Creatables = append(d.Creatables, Creatable{
+			Index:   index,
+			Type:    creatable.Ctype,
+			Created: creatable.Created,
+			Creator: creatable.Creator,
+		})
+	}
+	sort.Slice(d.Creatables, func(i, j int) bool {
+		return d.Creatables[i].Index < d.Creatables[j].Index
+	})
+
+	for aa, created := range delta.ModifiedAssetHoldings {
+		d.AssetHoldings = append(d.AssetHoldings, AssetHolding{Address: aa.Address, Asset: aa.Asset, Created: created})
+	}
+	sort.Slice(d.AssetHoldings, func(i, j int) bool {
+		a, b := d.AssetHoldings[i], d.AssetHoldings[j]
+		if c := bytes.Compare(a.Address[:], b.Address[:]); c != 0 {
+			return c < 0
+		}
+		return a.Asset < b.Asset
+	})
+
+	for aa, created := range delta.ModifiedAppLocalStates {
+		d.AppLocalStates = append(d.AppLocalStates, AppLocalState{Address: aa.Address, App: aa.App, Created: created})
+	}
+	sort.Slice(d.AppLocalStates, func(i, j int) bool {
+		a, b := d.AppLocalStates[i], d.AppLocalStates[j]
+		if c := bytes.Compare(a.Address[:], b.Address[:]); c != 0 {
+			return c < 0
+		}
+		return a.App < b.App
+	})
+	return d
+}
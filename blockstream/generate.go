@@ -0,0 +1,3 @@
+package blockstream
+
+//go:generate protoc --go_out=generated --go_opt=paths=source_relative --go-grpc_out=generated --go-grpc_opt=paths=source_relative blockstream.proto
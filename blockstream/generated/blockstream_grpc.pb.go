@@ -0,0 +1,140 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.2.0
+// - protoc             (unknown)
+// source: blockstream.proto
+
+package generated
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+// BlockStreamClient is the client API for BlockStream service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type BlockStreamClient interface {
+	// StreamBlocks streams the imported blocks from start_round on, in order,
+	// and waits for the next ones. It fails with OUT_OF_RANGE when start_round is
+	// older than the blocks the daemon keeps, and with UNAVAILABLE when the
+	// daemon shuts down, after which the client reconnects at the next round.
+	StreamBlocks(ctx context.Context, in *StreamBlocksRequest, opts ...grpc.CallOption) (BlockStream_StreamBlocksClient, error)
+}
+
+type blockStreamClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewBlockStreamClient(cc grpc.ClientConnInterface) BlockStreamClient {
+	return &blockStreamClient{cc}
+}
+
+func (c *blockStreamClient) StreamBlocks(ctx context.Context, in *StreamBlocksRequest, opts ...grpc.CallOption) (BlockStream_StreamBlocksClient, error) {
+	stream, err := c.cc.NewStream(ctx, &BlockStream_ServiceDesc.Streams[0], "/algorand.indexer.blockstream.v1.BlockStream/StreamBlocks", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &blockStreamStreamBlocksClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type BlockStream_StreamBlocksClient interface {
+	Recv() (*Block, error)
+	grpc.ClientStream
+}
+
+type blockStreamStreamBlocksClient struct {
+	grpc.ClientStream
+}
+
+func (x *blockStreamStreamBlocksClient) Recv() (*Block, error) {
+	m := new(Block)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// BlockStreamServer is the server API for BlockStream service.
+// All implementations must embed UnimplementedBlockStreamServer
+// for forward compatibility
+type BlockStreamServer interface {
+	// StreamBlocks streams the imported blocks from start_round on, in order,
+	// and waits for the next ones. It fails with OUT_OF_RANGE when start_round is
+	// older than the blocks the daemon keeps, and with UNAVAILABLE when the
+	// daemon shuts down, after which the client reconnects at the next round.
+	StreamBlocks(*StreamBlocksRequest, BlockStream_StreamBlocksServer) error
+	mustEmbedUnimplementedBlockStreamServer()
+}
+
+// UnimplementedBlockStreamServer must be embedded to have forward compatible implementations.
+type UnimplementedBlockStreamServer struct {
+}
+
+func (UnimplementedBlockStreamServer) StreamBlocks(*StreamBlocksRequest, BlockStream_StreamBlocksServer) error {
+	return status.Errorf(codes.Unimplemented, "method StreamBlocks not implemented")
+}
+func (UnimplementedBlockStreamServer) mustEmbedUnimplementedBlockStreamServer() {}
+
+// UnsafeBlockStreamServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to BlockStreamServer will
+// result in compilation errors.
+type UnsafeBlockStreamServer interface {
+	mustEmbedUnimplementedBlockStreamServer()
+}
+
+func RegisterBlockStreamServer(s grpc.ServiceRegistrar, srv BlockStreamServer) {
+	s.RegisterService(&BlockStream_ServiceDesc, srv)
+}
+
+func _BlockStream_StreamBlocks_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(StreamBlocksRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(BlockStreamServer).StreamBlocks(m, &blockStreamStreamBlocksServer{stream})
+}
+
+type BlockStream_StreamBlocksServer interface {
+	Send(*Block) error
+	grpc.ServerStream
+}
+
+type blockStreamStreamBlocksServer struct {
+	grpc.ServerStream
+}
+
+func (x *blockStreamStreamBlocksServer) Send(m *Block) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+// BlockStream_ServiceDesc is the grpc.ServiceDesc for BlockStream service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var BlockStream_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "algorand.indexer.blockstream.v1.BlockStream",
+	HandlerType: (*BlockStreamServer)(nil),
+	Methods:     []grpc.MethodDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "StreamBlocks",
+			Handler:       _BlockStream_StreamBlocks_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "blockstream.proto",
+}
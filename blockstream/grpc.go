@@ -0,0 +1,162 @@
+package blockstream
+
+import (
+	"context"
+	"crypto/subtle"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/algorand/indexer/blockstream/generated"
+)
+
+// TokenHeader is the metadata key of the API token.
+const TokenHeader = "X-Indexer-API-Token"
+
+// maxRequestSize is the size limit of a request message.
+const maxRequestSize = 1 << 10
+
+// TokenSet is the set of API tokens, it may change while the streams are served.
+type TokenSet interface {
+	Get() [][]byte
+}
+
+// Server implements the BlockStream gRPC service of blockstream.proto.
+type Server struct {
+	generated.UnimplementedBlockStreamServer
+
+	buffer *Buffer
+	tokens TokenSet
+	log    *log.Logger
+
+	// stopped is closed to end the streams.
+	stopped  chan struct{}
+	stopOnce sync.Once
+}
+
+// MakeServer creates a Server for the streams of `buffer`. While `tokens` is
+// not empty, one of them must be sent in the X-Indexer-API-Token metadata or as
+// a bearer token.
+func MakeServer(buffer *Buffer, tokens TokenSet, l *log.Logger) *Server {
+	return &Server{buffer: buffer, tokens: tokens, log: l, stopped: make(chan struct{})}
+}
+
+func (s *Server) authorized(ctx context.Context) bool {
+	tokens := s.tokens.Get()
+	if len(tokens) == 0 {
+		return true
+	}
+	md, _ := metadata.FromIncomingContext(ctx)
+	var provided []byte
+	if values := md.Get(TokenHeader); len(values) > 0 {
+		provided = []byte(values[0])
+	} else if values := md.Get("Authorization"); len(values) > 0 {
+		authorization := strings.SplitN(values[0], " ", 2)
+		if len(authorization) == 2 && strings.EqualFold("Bearer", authorization[0]) {
+			provided = []byte(authorization[1])
+		}
+	}
+	for _, token := range tokens {
+		if subtle.ConstantTimeCompare(provided, token) == 1 {
+			return true
+		}
+	}
+	return false
+}
+
+// StreamBlocks is part of generated.BlockStreamServer.
+func (s *Server) StreamBlocks(req *generated.StreamBlocksRequest, stream generated.BlockStream_StreamBlocksServer) error {
+	if !s.authorized(stream.Context()) {
+		return status.Error(codes.Unauthenticated, "invalid API token")
+	}
+
+	ctx, cancel := context.WithCancel(stream.Context())
+	defer cancel()
+	go func() {
+		select {
+		case <-s.stopped:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+	round := req.StartRound
+	for {
+		messages, err := s.buffer.Next(ctx, round)
+		if err == errRoundNotBuffered {
+			return status.Errorf(codes.OutOfRange, "round %d is no longer buffered", round)
+		}
+		if err != nil {
+			// The client is gone, or the server is shutting down and the client
+			// should reconnect.
+			return status.Error(codes.Unavailable, "the stream ended")
+		}
+		for _, message := range messages {
+			err = stream.Send(&generated.Block{
+				Round: message.Round,
+				Block: message.Block,
+				Delta: message.Delta,
+			})
+			if err != nil {
+				s.log.WithError(err).Debug("block stream client went away")
+				return err
+			}
+		}
+		round = messages[len(messages)-1].Round + 1
+	}
+}
+
+// Stop ends the streams with UNAVAILABLE, so that the clients reconnect.
+func (s *Server) Stop() {
+	s.stopOnce.Do(func() {
+		close(s.stopped)
+	})
+}
+
+// serve serves `server` on `listener` until `ctx` is done, and then stops the
+// streams.
+func serve(ctx context.Context, listener net.Listener, server *Server, l *log.Logger) error {
+	gs := grpc.NewServer(grpc.MaxRecvMsgSize(maxRequestSize))
+	generated.RegisterBlockStreamServer(gs, server)
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- gs.Serve(listener)
+	}()
+	select {
+	case err := <-errCh:
+		return fmt.Errorf("Serve() err: %w", err)
+	case <-ctx.Done():
+	}
+
+	server.Stop()
+	stopped := make(chan struct{})
+	go func() {
+		gs.GracefulStop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-time.After(5 * time.Second):
+		l.Warn("block streams were still open after the shutdown timeout")
+		gs.Stop()
+	}
+	return nil
+}
+
+// Serve serves `server` on `addr` without TLS until `ctx` is done, and then
+// stops the streams.
+func Serve(ctx context.Context, addr string, server *Server, l *log.Logger) error {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return fmt.Errorf("Serve() err: %w", err)
+	}
+	return serve(ctx, listener, server, l)
+}
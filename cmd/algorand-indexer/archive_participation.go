@@ -0,0 +1,94 @@
+package main
+
+import (
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/algorand/indexer/config"
+	"github.com/algorand/indexer/idb"
+)
+
+var archiveParticipationCmd = &cobra.Command{
+	Use:   "archive-participation",
+	Short: "archive and delete old participation rows",
+	Long:  "write the txn_participation rows of the rounds before --before-round to a CSV file, gzipped if it ends in .gz, and delete them. The rows make transactions searchable by address and dominate the size of the database, the transactions themselves are kept. Searching by address for the archived rounds then fails. Running the same command again after an interruption resumes where it stopped.",
+	Run: func(cmd *cobra.Command, args []string) {
+		config.BindFlags(cmd)
+		err := configureLogger()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "failed to configure logger: %v", err)
+			os.Exit(1)
+		}
+		if archiveBeforeRound == 0 {
+			fmt.Fprintf(os.Stderr, "--before-round is required\n")
+			os.Exit(1)
+		}
+
+		var out io.Writer = os.Stdout
+		var closers []io.Closer
+		if archiveOutput != "-" {
+			// Append, an interrupted run may have archived some batches already.
+			f, err := os.OpenFile(archiveOutput, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
+			maybeFail(err, "failed to open output, %v", err)
+			closers = append(closers, f)
+			out = f
+		}
+		if strings.HasSuffix(archiveOutput, ".gz") {
+			// Each run appends a gzip member, which gunzip reads as one file.
+			gz := gzip.NewWriter(out)
+			closers = append(closers, gz)
+			out = gz
+		}
+		// Flushes the batches archived so far, also before exiting on an error.
+		closeOutput := func() {
+			for i := len(closers) - 1; i >= 0; i-- {
+				err := closers[i].Close()
+				maybeFail(err, "failed to close output, %v", err)
+			}
+			closers = nil
+		}
+
+		db, availableCh := indexerDbFromFlags(idb.IndexerDbOptions{})
+		<-availableCh
+
+		retention, err := db.GetRetention(context.Background())
+		maybeFail(err, "failed to get retention, %v", err)
+		start := retention.EarliestParticipationRound
+
+		earliest := start
+		for earliest < archiveBeforeRound {
+			next, err := db.PruneParticipation(
+				context.Background(), archiveBeforeRound, archiveBatchRounds, out)
+			if err != nil {
+				closeOutput()
+				maybeFail(err, "archive failed, %v", err)
+			}
+			if next == earliest {
+				// The rounds after it have not been imported.
+				break
+			}
+			earliest = next
+			logger.Infof("archived participation rows before round %d", earliest)
+		}
+		closeOutput()
+		fmt.Fprintf(os.Stderr, "archived participation rows of rounds %d-%d\n", start, earliest)
+	},
+}
+
+var (
+	archiveBeforeRound uint64
+	archiveOutput      string
+	archiveBatchRounds uint64
+)
+
+func init() {
+	archiveParticipationCmd.Flags().Uint64VarP(&archiveBeforeRound, "before-round", "", 0, "archive the rows of the rounds before this one")
+	archiveParticipationCmd.Flags().StringVarP(&archiveOutput, "output", "o", "-", "file the rows are appended to, gzipped if it ends in .gz, - for standard out")
+	archiveParticipationCmd.Flags().Uint64VarP(&archiveBatchRounds, "batch-rounds", "", 1000, "number of rounds archived and deleted per database transaction")
+}
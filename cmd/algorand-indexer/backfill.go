@@ -0,0 +1,89 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/algorand/indexer/config"
+	"github.com/algorand/indexer/idb"
+)
+
+var backfillCmd = &cobra.Command{
+	Use:   "backfill feature",
+	Short: "recompute derived data from stored transactions",
+	Long:  "recompute the derived data of a feature (e.g. sig-type, participation) for a range of rounds from the stored transactions, in resumable chunks, instead of rebuilding the whole database. Running the same command again after an interruption resumes where it stopped.",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		config.BindFlags(cmd)
+		err := configureLogger()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "failed to configure logger: %v", err)
+			os.Exit(1)
+		}
+
+		minRound, maxRound, err := parseRoundRange(backfillRounds)
+		maybeFail(err, "invalid --rounds, %v", err)
+
+		db, availableCh := indexerDbFromFlags(idb.IndexerDbOptions{})
+		<-availableCh
+
+		if backfillRounds == "" {
+			next, err := db.GetNextRoundToAccount()
+			maybeFail(err, "failed to get next round, %v", err)
+			if next == 0 {
+				fmt.Println("nothing to backfill")
+				return
+			}
+			maxRound = next - 1
+		}
+
+		opts := idb.BackfillOptions{
+			Feature:   args[0],
+			MinRound:  minRound,
+			MaxRound:  maxRound,
+			ChunkSize: backfillChunkSize,
+		}
+		err = db.Backfill(context.Background(), opts)
+		maybeFail(err, "backfill failed, %v", err)
+		fmt.Printf("backfilled %s for rounds %d-%d\n", opts.Feature, minRound, maxRound)
+	},
+}
+
+var (
+	backfillRounds    string
+	backfillChunkSize uint64
+)
+
+func init() {
+	backfillCmd.Flags().StringVarP(&backfillRounds, "rounds", "", "", "inclusive range of rounds to process, e.g. 1000-2000 (defaults to all imported rounds)")
+	backfillCmd.Flags().Uint64VarP(&backfillChunkSize, "chunk-size", "", 1000, "number of rounds processed per database transaction")
+}
+
+// parseRoundRange parses an inclusive round range of the form "a-b". An empty
+// string returns [0, 0].
+func parseRoundRange(s string) (uint64, uint64, error) {
+	if s == "" {
+		return 0, 0, nil
+	}
+	parts := strings.SplitN(s, "-", 2)
+	if len(parts) != 2 {
+		return 0, 0, fmt.Errorf("expected a range like 100-200, got \"%s\"", s)
+	}
+	min, err := strconv.ParseUint(strings.TrimSpace(parts[0]), 10, 64)
+	if err != nil {
+		return 0, 0, fmt.Errorf("bad start round: %w", err)
+	}
+	max, err := strconv.ParseUint(strings.TrimSpace(parts[1]), 10, 64)
+	if err != nil {
+		return 0, 0, fmt.Errorf("bad end round: %w", err)
+	}
+	if min > max {
+		return 0, 0, fmt.Errorf("start round %d is greater than end round %d", min, max)
+	}
+	return min, max, nil
+}
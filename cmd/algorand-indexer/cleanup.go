@@ -0,0 +1,51 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/algorand/indexer/config"
+	"github.com/algorand/indexer/idb"
+)
+
+var cleanupCmd = &cobra.Command{
+	Use:   "cleanup",
+	Short: "remove orphaned rows",
+	Long:  "remove rows left behind by interrupted migrations or repairs, for example participation rows for deleted transactions. Use --dry-run to only report what would be removed.",
+	Run: func(cmd *cobra.Command, args []string) {
+		config.BindFlags(cmd)
+		err := configureLogger()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "failed to configure logger: %v", err)
+			os.Exit(1)
+		}
+
+		db, availableCh := indexerDbFromFlags(idb.IndexerDbOptions{ReadOnly: cleanupDryRun})
+		<-availableCh
+
+		results, err := db.DeleteOrphanedRows(context.Background(), cleanupDryRun)
+		maybeFail(err, "cleanup failed, %v", err)
+
+		action := "deleted"
+		if cleanupDryRun {
+			action = "found"
+		}
+		var total int64
+		for _, result := range results {
+			fmt.Printf("%s: %s %d %s\n", result.Table, action, result.Count, result.Description)
+			total += result.Count
+		}
+		fmt.Printf("%s %d orphaned rows in total\n", action, total)
+	},
+}
+
+var (
+	cleanupDryRun bool
+)
+
+func init() {
+	cleanupCmd.Flags().BoolVarP(&cleanupDryRun, "dry-run", "", false, "only report orphaned rows, do not delete them")
+}
@@ -9,16 +9,24 @@ import (
 	"syscall"
 	"time"
 
+	"github.com/algorand/go-algorand/protocol"
 	"github.com/algorand/go-algorand/rpcs"
+	"github.com/prometheus/client_golang/prometheus"
+	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 	"github.com/spf13/viper"
 
 	"github.com/algorand/indexer/api"
+	"github.com/algorand/indexer/api/middlewares"
+	"github.com/algorand/indexer/blockstream"
 	"github.com/algorand/indexer/config"
 	"github.com/algorand/indexer/fetcher"
 	"github.com/algorand/indexer/idb"
 	"github.com/algorand/indexer/importer"
 	"github.com/algorand/indexer/util/metrics"
+	"github.com/algorand/indexer/util/reporting"
+	"github.com/algorand/indexer/util/tracing"
 )
 
 var (
@@ -31,6 +39,84 @@ var (
 	allowMigration   bool
 	metricsMode      string
 	tokenString      string
+	adminTokenString string
+	enablePprof      bool
+	maxConn          uint32
+	minConn          uint32
+	maxConnLifetime  time.Duration
+	healthCheck      time.Duration
+	statementTimeout time.Duration
+	slowQuery        time.Duration
+	replicaAddr      string
+	replicaMaxLag    uint64
+	pgbouncerCompat  bool
+	cockroachCompat  bool
+	retainRounds     uint64
+	retainPartRounds uint64
+	maintInterval    time.Duration
+	maintThreshold   uint64
+	maintVacuum      bool
+	readyMaxLag      uint64
+	paginationKey    string
+	enableV3         bool
+	drainTimeout     time.Duration
+	bulkImportBlocks int
+	bulkImportMB     uint64
+	validateInterval uint64
+	validateAccounts int
+	stopAtRound      uint64
+	unknownProtocol  string
+	kafkaRESTURL     string
+	kafkaTopicPrefix string
+	kafkaMsgpack     bool
+	kafkaStartRound  uint64
+	enableWebhooks   bool
+	esURL            string
+	esIndexPrefix    string
+	enableSearch     bool
+	chainMetricsRW   string
+	chainMetricsPush string
+	chainMetricsJob  string
+	blockStreamAddr  string
+	eventsURL        string
+	eventsBlockTopic string
+	eventsTxnTopic   string
+	blockStreamSize  int
+	archiveURLs      []string
+	// The credentials of S3 and GCS archives, shared with reprocess.
+	archiveOptions   fetcher.ObjectArchiveOptions
+	compressTxns     bool
+	migrationWorkers int
+	migrationDiskGB  uint64
+	accountCacheSize int
+	lookupBatchSize  int
+	lookupPipeline   bool
+	txnRulesPath     string
+	scopeAddresses   []string
+	fetchRetries     int
+	fetchBackoffBase time.Duration
+	fetchBackoffMax  time.Duration
+	fetchJitter      float64
+	blockCacheDir    string
+	blockCacheRounds uint64
+	algodRateLimit   float64
+	algodRateBurst   int
+	apiRateLimit     float64
+	apiRateBurst     int
+	disabledPaths    []string
+	algodHeaders     []string
+	algodCertFile    string
+	algodKeyFile     string
+	algodCAFile      string
+	tipMode          string
+	waitTimeout      time.Duration
+	pollInterval     time.Duration
+	otlpEndpoint     string
+	otlpHeaders      []string
+	traceSampleRatio float64
+	metricsSink      string
+	statsdAddress    string
+	statsdInterval   time.Duration
 )
 
 var daemonCmd = &cobra.Command{
@@ -40,6 +126,7 @@ var daemonCmd = &cobra.Command{
 	//Args:
 	Run: func(cmd *cobra.Command, args []string) {
 		var err error
+		cmdline := commandLineFlags(cmd)
 		config.BindFlags(cmd)
 		err = configureLogger()
 		if err != nil {
@@ -60,56 +147,346 @@ var daemonCmd = &cobra.Command{
 				<-cancelCh
 				logger.Println("Stopping Indexer.")
 				cf()
+				// A second signal skips draining the API requests.
+				<-cancelCh
+				logger.Println("Exiting without draining requests.")
+				os.Exit(1)
 			}()
 		}
 
+		if otlpEndpoint != "" {
+			opts, err := makeTracingOptions()
+			maybeFail(err, "tracing options, %v", err)
+			tracer, err := tracing.MakeTracer(opts, logger)
+			maybeFail(err, "tracing setup, %v", err)
+			tracing.SetTracer(tracer)
+			defer tracer.Close()
+		}
+
+		algodOpts, err := makeAlgodOptions()
+		maybeFail(err, "algod options, %v", err)
 		var bot fetcher.Fetcher
 		if noAlgod {
 			logger.Info("algod block following disabled")
 		} else if algodAddr != "" && algodToken != "" {
-			bot, err = fetcher.ForNetAndToken(algodAddr, algodToken, logger)
+			bot, err = fetcher.ForNetAndToken(algodAddr, algodToken, algodOpts, logger)
 			maybeFail(err, "fetcher setup, %v", err)
 		} else if algodDataDir != "" {
-			bot, err = fetcher.ForDataDir(algodDataDir, logger)
+			bot, err = fetcher.ForDataDir(algodDataDir, algodOpts, logger)
 			maybeFail(err, "fetcher setup, %v", err)
 		} else {
 			// no algod was found
 			noAlgod = true
 		}
-		opts := idb.IndexerDbOptions{}
+		if bot != nil {
+			bot.SetRetryPolicy(fetcher.RetryPolicy{
+				Retries:     fetchRetries,
+				BackoffBase: fetchBackoffBase,
+				BackoffMax:  fetchBackoffMax,
+				Jitter:      fetchJitter,
+			})
+			bot.SetRateLimit(algodRateLimit, algodRateBurst)
+			mode, err := fetcher.ParseTipMode(tipMode)
+			maybeFail(err, "--tip-mode, %v", err)
+			bot.SetFollowOptions(fetcher.FollowOptions{
+				Mode:         mode,
+				WaitTimeout:  waitTimeout,
+				PollInterval: pollInterval,
+			})
+			if blockCacheDir != "" {
+				diskCache, err := fetcher.MakeDiskCache(blockCacheDir, blockCacheRounds)
+				maybeFail(err, "block cache setup, %v", err)
+				bot.SetDiskCache(diskCache)
+			}
+			for _, url := range archiveURLs {
+				archive, err := fetcher.MakeArchive(url, archiveOptions)
+				maybeFail(err, "archive setup, %v", err)
+				bot.AddArchive(archive)
+			}
+		}
+		if unknownProtocol != unknownProtocolFail && unknownProtocol != unknownProtocolPause {
+			fmt.Fprintf(os.Stderr, "--unknown-protocol must be %s or %s\n", unknownProtocolFail, unknownProtocolPause)
+			os.Exit(1)
+		}
+		if unknownProtocol == unknownProtocolPause && blockCacheDir == "" {
+			fmt.Fprintf(os.Stderr, "--unknown-protocol %s requires --block-cache-dir to keep the block\n", unknownProtocolPause)
+			os.Exit(1)
+		}
+		var kafkaSink *importer.KafkaSink
+		if kafkaRESTURL != "" {
+			kafkaSink, err = importer.MakeKafkaSink(importer.KafkaOptions{
+				RESTURL:     kafkaRESTURL,
+				TopicPrefix: kafkaTopicPrefix,
+				Msgpack:     kafkaMsgpack,
+				Timeout:     time.Minute,
+			})
+			maybeFail(err, "kafka setup, %v", err)
+		}
+		var eventSink *importer.EventSink
+		if eventsURL != "" {
+			eventSink, err = importer.MakeEventSink(importer.EventOptions{
+				URL:              eventsURL,
+				BlockTopic:       eventsBlockTopic,
+				TransactionTopic: eventsTxnTopic,
+				Timeout:          10 * time.Second,
+			})
+			maybeFail(err, "events setup, %v", err)
+		}
+		if enableSearch && esURL == "" {
+			fmt.Fprintf(os.Stderr, "--enable-search requires --elasticsearch-url\n")
+			os.Exit(1)
+		}
+		var esSink *importer.ElasticsearchSink
+		if esURL != "" {
+			esSink, err = importer.MakeElasticsearchSink(importer.ElasticsearchOptions{
+				URL:         esURL,
+				IndexPrefix: esIndexPrefix,
+				Timeout:     time.Minute,
+			})
+			maybeFail(err, "elasticsearch setup, %v", err)
+		}
+		if enablePprof && adminTokenString == "" {
+			fmt.Fprintf(os.Stderr, "--enable-pprof requires --admin-token\n")
+			os.Exit(1)
+		}
+
+		opts := idb.IndexerDbOptions{
+			MaxConn:            maxConn,
+			MinConn:            minConn,
+			MaxConnLifetime:    maxConnLifetime,
+			HealthCheckPeriod:  healthCheck,
+			StatementTimeout:   statementTimeout,
+			SlowQueryThreshold: slowQuery,
+			ReadConnection:     replicaAddr,
+			ReplicaMaxLag:      replicaMaxLag,
+			PgBouncerCompat:    pgbouncerCompat,
+			CockroachCompat:    cockroachCompat,
+			CompressTxnBytes:   compressTxns,
+			MigrationWorkers:   migrationWorkers,
+			MigrationDiskSpace: migrationDiskGB << 30,
+			AccountCacheSize:   accountCacheSize,
+			LookupBatchSize:    lookupBatchSize,
+			LookupPipeline:     lookupPipeline,
+		}
+		if txnRulesPath != "" {
+			opts.TxnRules, err = idb.LoadTxnRules(txnRulesPath)
+			maybeFail(err, "transaction rules, %v", err)
+		}
+		if len(scopeAddresses) > 0 {
+			opts.AddressScope, err = idb.ParseAddressScope(scopeAddresses)
+			maybeFail(err, "address scope, %v", err)
+		}
 		if noAlgod && !allowMigration {
 			opts.ReadOnly = true
 		}
 		db, availableCh := indexerDbFromFlags(opts)
+		var chainMetricsSink *importer.ChainMetricsSink
+		if chainMetricsRW != "" || chainMetricsPush != "" {
+			chainMetricsSink, err = importer.MakeChainMetricsSink(db, importer.ChainMetricsOptions{
+				RemoteWriteURL: chainMetricsRW,
+				PushgatewayURL: chainMetricsPush,
+				Job:            chainMetricsJob,
+				Timeout:        time.Minute,
+			})
+			maybeFail(err, "chain metrics setup, %v", err)
+		}
+		switch metricsSink {
+		case metrics.SinkPrometheus:
+		case metrics.SinkStatsd, metrics.SinkDogStatsd:
+			sink, err := metrics.MakeStatsdSink(metrics.StatsdOptions{
+				Address:   statsdAddress,
+				DogStatsd: metricsSink == metrics.SinkDogStatsd,
+				Interval:  statsdInterval,
+			}, prometheus.DefaultGatherer, logger)
+			maybeFail(err, "metrics sink setup, %v", err)
+			go sink.Run(ctx)
+		default:
+			fmt.Fprintf(os.Stderr, "unknown --metrics-sink %q, use %s, %s or %s\n",
+				metricsSink, metrics.SinkPrometheus, metrics.SinkStatsd, metrics.SinkDogStatsd)
+			os.Exit(1)
+		}
+		// The block stream shares the API tokens, which are replaced on reload.
+		options := makeOptions()
+		if blockStreamAddr != "" {
+			if bot == nil {
+				fmt.Fprintf(os.Stderr, "--block-stream-address requires an algod to import from\n")
+				os.Exit(1)
+			}
+			if blockStreamSize < 1 {
+				fmt.Fprintf(os.Stderr, "--block-stream-rounds must be at least 1\n")
+				os.Exit(1)
+			}
+			buffer := blockstream.MakeBuffer(blockStreamSize)
+			db.AddBlockHook(buffer)
+			server := blockstream.MakeServer(buffer, options.TokenSet, logger)
+			logger.Infof("streaming blocks on %s", blockStreamAddr)
+			go func() {
+				err := blockstream.Serve(ctx, blockStreamAddr, server, logger)
+				maybeFail(err, "block stream, %v", err)
+			}()
+		}
+		var pauser *importer.Pauser
+		var publisher *importer.RoundPublisher
 		if bot != nil {
+			pauser = importer.MakePauser()
+			publisher = importer.MakeRoundPublisher(0)
+			err = metrics.RegisterImportLag(bot.LatestRound, publisher.Round)
+			maybeFail(err, "metrics setup, %v", err)
 			go func() {
+				defer reporting.ReportPanic()
 				// Wait until the database is available.
 				<-availableCh
 
 				// Initial import if needed.
 				importer.InitialImport(db, genesisJSONPath, bot.Algod(), logger)
 
+				// Refuse to import the blocks of another network.
+				err := importer.CheckGenesisHash(ctx, db, bot.Algod())
+				maybeFail(err, "genesis check failed, %v", err)
+
 				logger.Info("Initializing block import handler.")
 
 				nextRound, err := db.GetNextRoundToAccount()
 				maybeFail(err, "failed to get next round, %v", err)
+				if stopAtRound != 0 && nextRound > stopAtRound {
+					logger.WithField("round", stopAtRound).Info("Stop round was already imported, exiting.")
+					cf()
+					return
+				}
 				bot.SetNextRound(nextRound)
+				if nextRound > 0 {
+					publisher.Publish(nextRound - 1)
+				}
 
-				bih := blockImporterHandler{imp: importer.NewImporter(db)}
+				bih := blockImporterHandler{
+					imp:         importer.NewImporter(db),
+					pauser:      pauser,
+					ctx:         ctx,
+					latestRound: bot.LatestRound,
+					bulkBlocks:  bulkImportBlocks,
+					bulkBytes:   bulkImportMB << 20,
+					stop:        cf,
+				}
+				bih.imp.SetStopRound(stopAtRound)
+				bih.imp.AddPublishHook(publisher.Publish)
+				if validateInterval != 0 {
+					opts := importer.ValidationOptions{
+						Interval: validateInterval,
+						Accounts: validateAccounts,
+					}
+					validator := importer.MakeValidator(db, bot.Algod(), opts, logger)
+					bih.imp.RegisterBlockHook(validator)
+					go validator.Run(ctx)
+				}
 				bot.AddBlockHandler(&bih)
 				bot.SetContext(ctx)
 
+				if kafkaSink != nil {
+					opts := importer.ExportOptions{Name: "kafka", StartRound: kafkaStartRound}
+					go importer.RunExport(ctx, db, publisher, kafkaSink, opts, logger)
+				}
+				if enableWebhooks {
+					// The webhooks are posted the new rounds, not the history.
+					opts := importer.ExportOptions{Name: "webhooks"}
+					exported, err := db.GetExportRound(ctx, opts.Name)
+					maybeFail(err, "failed to get the webhooks round, %v", err)
+					if exported == 0 {
+						opts.StartRound = nextRound
+					}
+					sink := importer.MakeWebhookSink(db, logger)
+					go importer.RunExport(ctx, db, publisher, sink, opts, logger)
+				}
+				if esSink != nil {
+					opts := importer.ExportOptions{Name: "elasticsearch"}
+					go importer.RunExport(ctx, db, publisher, esSink, opts, logger)
+				}
+				if eventSink != nil {
+					// The events are of the new rounds, like the webhooks.
+					opts := importer.ExportOptions{Name: "events"}
+					exported, err := db.GetExportRound(ctx, opts.Name)
+					maybeFail(err, "failed to get the events round, %v", err)
+					if exported == 0 {
+						opts.StartRound = nextRound
+					}
+					go importer.RunExport(ctx, db, publisher, eventSink, opts, logger)
+				}
+				if chainMetricsSink != nil {
+					// Prometheus rejects samples which are much older than its
+					// latest ones, the statistics start at the new rounds.
+					opts := importer.ExportOptions{Name: "chain-metrics"}
+					exported, err := db.GetExportRound(ctx, opts.Name)
+					maybeFail(err, "failed to get the chain metrics round, %v", err)
+					if exported == 0 {
+						opts.StartRound = nextRound
+					}
+					go importer.RunExport(ctx, db, publisher, chainMetricsSink, opts, logger)
+				}
+
+				if retainRounds != 0 || retainPartRounds != 0 {
+					logger.Infof(
+						"Keeping the transactions of the latest %d rounds, searchable by address for the latest %d rounds (0 is all).",
+						retainRounds, retainPartRounds)
+					opts := importer.RetentionOptions{
+						KeepRounds:              retainRounds,
+						KeepParticipationRounds: retainPartRounds,
+					}
+					go importer.RunRetention(ctx, db, opts, logger)
+				}
+
+				if maintInterval != 0 || maintThreshold != 0 {
+					opts := idb.MaintenanceOptions{
+						Interval:        maintInterval,
+						ChangeThreshold: maintThreshold,
+						Vacuum:          maintVacuum,
+					}
+					go func() {
+						err := db.RunMaintenance(ctx, opts)
+						if err != nil {
+							logger.WithError(err).Error("table maintenance failed")
+						}
+					}()
+				}
+
 				logger.Info("Starting block importer.")
 				bot.Run()
 				cf()
 			}()
+		} else if pgbouncerCompat || cockroachCompat {
+			// PgBouncer and CockroachDB do not deliver notifications, the API polls.
+			logger.Info("No block importer configured.")
 		} else {
 			logger.Info("No block importer configured.")
+			// Follow the rounds imported by another indexer instead of polling.
+			publisher = importer.MakeRoundPublisher(0)
+			go func() {
+				<-availableCh
+				followRounds(ctx, db, publisher)
+			}()
 		}
 
 		fmt.Printf("serving on %s\n", daemonServerAddr)
 		logger.Infof("serving on %s", daemonServerAddr)
-		api.Serve(ctx, daemonServerAddr, db, bot, logger, makeOptions())
+		if bot != nil {
+			options.Importer = pauser
+		}
+		if publisher != nil {
+			options.RoundWaiter = publisher
+		}
+		if enableSearch {
+			options.Searcher = esSink
+		}
+		options.Config = redactedConfig(cmd)
+		r := &reloader{
+			cmd:         cmd,
+			cmdline:     cmdline,
+			bot:         bot,
+			tokens:      options.TokenSet,
+			adminTokens: options.AdminTokenSet,
+			rateLimit:   options.RateLimit,
+			disabled:    options.DisabledEndpoints,
+		}
+		go r.run(ctx)
+		api.Serve(ctx, daemonServerAddr, db, bot, logger, options)
 	},
 }
 
@@ -124,6 +501,83 @@ func init() {
 	daemonCmd.Flags().BoolVarP(&developerMode, "dev-mode", "", false, "allow performance intensive operations like searching for accounts at a particular round")
 	daemonCmd.Flags().BoolVarP(&allowMigration, "allow-migration", "", false, "allow migrations to happen even when no algod connected")
 	daemonCmd.Flags().StringVarP(&metricsMode, "metrics-mode", "", "OFF", "configure the /metrics endpoint to [ON, OFF, VERBOSE]")
+	daemonCmd.Flags().StringVarP(&adminTokenString, "admin-token", "", "", "an optional admin token, required to access the admin endpoints in a bearer format, or in a 'X-Indexer-Admin-Token' header")
+	daemonCmd.Flags().Uint32VarP(&maxConn, "max-conn", "", 0, "maximum number of connections in the database connection pool, startup fails if the database server does not allow this many (defaults to the pgx default or pool_max_conns in the connection string)")
+	daemonCmd.Flags().Uint32VarP(&minConn, "min-conn", "", 0, "number of connections the database connection pool keeps open when idle (defaults to pool_min_conns in the connection string)")
+	daemonCmd.Flags().DurationVarP(&maxConnLifetime, "max-conn-lifetime", "", 0, "time after which a database connection is closed and replaced (defaults to the pgx default of 1h or pool_max_conn_lifetime in the connection string)")
+	daemonCmd.Flags().DurationVarP(&healthCheck, "health-check-period", "", 0, "how often idle database connections are checked (defaults to the pgx default of 1m or pool_health_check_period in the connection string)")
+	daemonCmd.Flags().DurationVarP(&statementTimeout, "statement-timeout", "", 0, "abort database statements which run longer, including those of the import and of migrations (defaults to no timeout)")
+	daemonCmd.Flags().DurationVarP(&slowQuery, "slow-query-threshold", "", time.Second, "log the database statements which run longer, with their SQL, parameters and API endpoint, 0 disables it")
+	daemonCmd.Flags().StringVarP(&replicaAddr, "postgres-replica", "", "", "connection string of a read-only replica of the database, the API queries it while the import writes to --postgres")
+	daemonCmd.Flags().Uint64VarP(&replicaMaxLag, "replica-max-lag", "", 10, "number of rounds the replica may be behind the primary before API queries fall back to the primary")
+	daemonCmd.Flags().BoolVarP(&pgbouncerCompat, "pgbouncer-compat", "", false, "avoid prepared statements and LISTEN, which PgBouncer does not support in transaction pooling mode")
+	daemonCmd.Flags().BoolVarP(&cockroachCompat, "cockroach-compat", "", false, "run on CockroachDB: create the tables for it and avoid LISTEN/NOTIFY and the postgres statistics, which it does not support")
+	daemonCmd.Flags().Uint64VarP(&retainRounds, "retain-rounds", "", 0, "delete the transactions of older rounds in the background, accounts and block headers are kept (defaults to 0, keep all transactions)")
+	daemonCmd.Flags().Uint64VarP(&retainPartRounds, "retain-participation-rounds", "", 0, "delete the rows which make transactions of older rounds searchable by address in the background, see the archive-participation command to archive them first (defaults to 0, keep all)")
+	daemonCmd.Flags().DurationVarP(&maintInterval, "maintenance-interval", "", 0, "analyze the tables changed by the import when they were not analyzed for this long, e.g. 6h (defaults to 0, off)")
+	daemonCmd.Flags().Uint64VarP(&maintThreshold, "maintenance-change-threshold", "", 0, "analyze the tables changed by the import when this many rows changed since they were last analyzed (defaults to 0, off)")
+	daemonCmd.Flags().BoolVarP(&maintVacuum, "maintenance-vacuum", "", false, "vacuum the tables as well when they are analyzed")
+	daemonCmd.Flags().Uint64VarP(&readyMaxLag, "ready-max-lag", "", 20, "number of rounds the import may lag algod before /ready reports the indexer as not ready, 0 disables the check")
+	daemonCmd.Flags().BoolVarP(&enablePprof, "enable-pprof", "", false, "serve the /debug/pprof profiling endpoints, requires --admin-token")
+	daemonCmd.Flags().BoolVarP(&enableV3, "enable-experimental-v3", "", false, "serve the experimental /v3 API, its responses may change between releases")
+	daemonCmd.Flags().DurationVarP(&drainTimeout, "drain-timeout", "", 10*time.Second, "on shutdown, time given to API requests in flight to finish before they are canceled")
+	daemonCmd.Flags().IntVarP(&bulkImportBlocks, "bulk-import-blocks", "", 0, "while the import is more than this many rounds behind algod, blocks are imported in batches of this size, which is much faster, e.g. 100 (defaults to 0, disabled)")
+	daemonCmd.Flags().Uint64VarP(&bulkImportMB, "bulk-import-mb", "", 0, "a batch of blocks is also imported as soon as the blocks add up to this many MB, which bounds the memory and the size of the database transaction when blocks are large (defaults to 0, no limit)")
+	daemonCmd.Flags().Uint64VarP(&validateInterval, "validate-interval", "", 0, "every this many rounds, compare a sample of the accounts modified by the round with algod and report differences in the log and metrics (defaults to 0, disabled)")
+	daemonCmd.Flags().IntVarP(&validateAccounts, "validate-accounts", "", 10, "maximum number of accounts compared with algod per validated round")
+	daemonCmd.Flags().Uint64VarP(&stopAtRound, "stop-at-round", "", 0, "import up to this round and exit, e.g. to build a dataset of a fixed range of rounds (defaults to 0, follow the network)")
+	daemonCmd.Flags().IntVarP(&fetchRetries, "fetch-retries", "", 3, "number of times fetching a block from algod is retried before the indexer reconnects to algod")
+	daemonCmd.Flags().DurationVarP(&fetchBackoffBase, "fetch-backoff", "", time.Second, "wait after the first failure to fetch a block, it doubles with every consecutive failure")
+	daemonCmd.Flags().DurationVarP(&fetchBackoffMax, "fetch-backoff-max", "", time.Minute, "maximum wait after a failure to fetch a block")
+	daemonCmd.Flags().Float64VarP(&fetchJitter, "fetch-backoff-jitter", "", 0.2, "fraction by which the waits after failures to fetch a block are randomized, so that several indexers don't retry at once")
+	daemonCmd.Flags().StringSliceVarP(&algodHeaders, "algod-header", "", nil, "header added to the requests to algod, e.g. \"X-API-Key: secret\" for a proxy in front of algod (can be repeated)")
+	daemonCmd.Flags().StringVarP(&algodCertFile, "algod-cert", "", "", "PEM file of a client certificate which is presented to algod, requires --algod-key")
+	daemonCmd.Flags().StringVarP(&algodKeyFile, "algod-key", "", "", "PEM file of the key of --algod-cert")
+	daemonCmd.Flags().StringVarP(&algodCAFile, "algod-ca", "", "", "PEM bundle of the certificate authorities which are trusted to sign the certificate of algod (defaults to the system's)")
+	daemonCmd.Flags().StringVarP(&unknownProtocol, "unknown-protocol", "", unknownProtocolFail, "what to do with a block of a consensus protocol which this version does not know: fail exits, pause keeps the block in --block-cache-dir and pauses the import until the indexer is upgraded while the API keeps serving")
+	daemonCmd.Flags().StringVarP(&kafkaRESTURL, "kafka-rest-url", "", "", "URL of a Kafka REST proxy to which the imported block headers and transactions are published (defaults to none)")
+	daemonCmd.Flags().StringVarP(&kafkaTopicPrefix, "kafka-topic-prefix", "", "algorand", "prefix of the Kafka topics, the block headers are published to <prefix>.blocks and the transactions to <prefix>.transactions")
+	daemonCmd.Flags().BoolVarP(&kafkaMsgpack, "kafka-msgpack", "", false, "publish msgpack encoded values instead of JSON")
+	daemonCmd.Flags().Uint64VarP(&kafkaStartRound, "kafka-start-round", "", 0, "first round which is published to Kafka when nothing was published yet")
+	daemonCmd.Flags().BoolVarP(&enableWebhooks, "enable-webhooks", "", false, "post the imported transactions which match the filters of the webhooks registered with the admin API to their URLs")
+	daemonCmd.Flags().StringVarP(&esURL, "elasticsearch-url", "", "", "URL of an Elasticsearch or OpenSearch cluster to which the notes, asset names and URLs and application global state keys are mirrored (defaults to none)")
+	daemonCmd.Flags().StringVarP(&esIndexPrefix, "elasticsearch-index-prefix", "", "algorand", "prefix of the Elasticsearch indices, <prefix>-notes, <prefix>-assets and <prefix>-app-keys")
+	daemonCmd.Flags().BoolVarP(&enableSearch, "enable-search", "", false, "serve free-text searches of the Elasticsearch indices on /v2/search/{kind}")
+	daemonCmd.Flags().StringVarP(&chainMetricsRW, "chain-metrics-remote-write-url", "", "", "Prometheus remote write URL to which the statistics of every imported round are written (defaults to none)")
+	daemonCmd.Flags().StringVarP(&chainMetricsPush, "chain-metrics-pushgateway-url", "", "", "Prometheus pushgateway URL to which the totals of the imported rounds are pushed (defaults to none)")
+	daemonCmd.Flags().StringVarP(&chainMetricsJob, "chain-metrics-job", "", "algorand-indexer", "job label of the chain metrics")
+	daemonCmd.Flags().StringVarP(&eventsURL, "events-url", "", "", "NATS server, nats://host:port, or MQTT broker, mqtt://host:port, to which events of the imported blocks and transactions are published (defaults to none)")
+	daemonCmd.Flags().StringVarP(&eventsBlockTopic, "events-block-topic", "", "algorand.blocks", "topic of the block events, empty for none")
+	daemonCmd.Flags().StringVarP(&eventsTxnTopic, "events-transaction-topic", "", "algorand.transactions.{type}", "topic of the transaction events, {type} is replaced by the transaction type, empty for none")
+	daemonCmd.Flags().StringVarP(&blockStreamAddr, "block-stream-address", "", "", "host:port to serve the gRPC stream of the imported blocks and their state deltas on (defaults to none)")
+	daemonCmd.Flags().IntVarP(&blockStreamSize, "block-stream-rounds", "", 1000, "number of the latest imported rounds which the block stream can start at")
+	daemonCmd.Flags().StringVarP(&tipMode, "tip-mode", "", "auto", "how new blocks are awaited at the tip: wait asks algod to answer when it has the next block, poll asks algod for its status every --poll-interval, auto waits and falls back to polling when algod does not support waiting")
+	daemonCmd.Flags().DurationVarP(&waitTimeout, "wait-timeout", "", 0, "time after which a wait for the next block is ended and sent again (defaults to 0, algod's own timeout)")
+	daemonCmd.Flags().DurationVarP(&pollInterval, "poll-interval", "", time.Second, "time between status requests when polling for the next block")
+	daemonCmd.Flags().Float64VarP(&algodRateLimit, "algod-rate-limit", "", 0, "maximum number of requests per second which the indexer sends to algod, e.g. to not starve other users of a shared algod during a catchup (defaults to 0, no limit)")
+	daemonCmd.Flags().IntVarP(&algodRateBurst, "algod-rate-burst", "", 10, "number of requests to algod which may be sent at once when the indexer did not use the --algod-rate-limit for a while, e.g. after waiting for a new block")
+	daemonCmd.Flags().Float64VarP(&apiRateLimit, "api-rate-limit", "", 0, "maximum number of API requests per second of each client address, further requests get a 429 response (defaults to 0, no limit)")
+	daemonCmd.Flags().IntVarP(&apiRateBurst, "api-rate-burst", "", 20, "number of API requests which a client may send at once when it did not use the --api-rate-limit for a while")
+	daemonCmd.Flags().StringSliceVarP(&disabledPaths, "disabled-endpoint", "", nil, "path of an API endpoint which responds 404, as in the OpenAPI specification, e.g. /v2/transactions (can be repeated)")
+	daemonCmd.Flags().StringVarP(&blockCacheDir, "block-cache-dir", "", "", "directory in which the fetched blocks are kept, so that they are not downloaded again when the import restarts (defaults to none)")
+	daemonCmd.Flags().Uint64VarP(&blockCacheRounds, "block-cache-rounds", "", 10000, "number of recent rounds whose blocks are kept in --block-cache-dir")
+	daemonCmd.Flags().StringSliceVarP(&archiveURLs, "archive", "", nil, "URL of a block archive, e.g. an archival relay, used instead of algod for rounds far behind algod, {round} and {round36} are replaced by the round in base 10 and 36, or an s3://bucket/prefix or gs://bucket/prefix of block files listed by a catalog.json (can be repeated)")
+	addArchiveCredentialFlags(daemonCmd)
+	daemonCmd.Flags().IntVarP(&migrationWorkers, "migration-workers", "", 0, "number of parts of a table which data migrations rewrite in parallel, each on a database connection of its own (defaults to 4)")
+	daemonCmd.Flags().Uint64VarP(&migrationDiskGB, "migration-free-disk-gb", "", 0, "free disk space of the database in GB, migrations which would need more space to rewrite their tables don't start (defaults to 0, not checked)")
+	daemonCmd.Flags().IntVarP(&accountCacheSize, "account-cache-size", "", 50000, "number of accounts and asset and application creators which the import keeps in memory across rounds, 0 disables the cache")
+	daemonCmd.Flags().IntVarP(&lookupBatchSize, "lookup-batch-size", "", 1000, "maximum number of accounts which the import reads from the database in one batch, 0 for no limit")
+	daemonCmd.Flags().BoolVarP(&lookupPipeline, "lookup-pipeline", "", false, "read the assets and applications of the accounts in the same batch as the accounts, one round trip instead of two per batch, which helps when the database has a high latency")
+	daemonCmd.Flags().StringVarP(&txnRulesPath, "txn-rules", "", "", "JSON file of rules which select the transactions which are stored by type, sender, receiver and asset or application, e.g. for an indexer of a single application (defaults to none, all transactions are stored)")
+	daemonCmd.Flags().StringSliceVarP(&scopeAddresses, "scope-address", "", nil, "only store the transactions which involve this address or the assets and applications it created, e.g. an exchange's wallet (can be repeated, defaults to all transactions)")
+	daemonCmd.Flags().BoolVarP(&compressTxns, "compress-transactions", "", false, "compress large transactions, e.g. with application programs or notes, with zstd before writing them to the database")
+	daemonCmd.Flags().StringVarP(&paginationKey, "pagination-key", "", "", "secret used to sign next tokens, must be the same on every indexer behind a load balancer (defaults to a random secret which is kept in the database)")
+	daemonCmd.Flags().StringVarP(&otlpEndpoint, "otlp-endpoint", "", "", "OTLP over HTTP endpoint of an OpenTelemetry collector, e.g. http://localhost:4318, to which spans of the API requests, database queries, block fetches and block writes are exported (defaults to none)")
+	daemonCmd.Flags().StringSliceVarP(&otlpHeaders, "otlp-header", "", nil, "header added to the requests to --otlp-endpoint, e.g. \"X-API-Key: secret\" for a tracing vendor (can be repeated)")
+	daemonCmd.Flags().StringVarP(&metricsSink, "metrics-sink", "", metrics.SinkPrometheus, "also push the metrics to a StatsD server [prometheus, statsd, dogstatsd], dogstatsd sends the labels as tags, e.g. to the Datadog agent")
+	daemonCmd.Flags().StringVarP(&statsdAddress, "statsd-address", "", "127.0.0.1:8125", "host:port of the StatsD server to which --metrics-sink pushes over UDP")
+	daemonCmd.Flags().DurationVarP(&statsdInterval, "statsd-interval", "", 10*time.Second, "time between two pushes to the StatsD server")
+	daemonCmd.Flags().Float64VarP(&traceSampleRatio, "trace-sample-ratio", "", 1, "fraction of the traces which are exported, traces continued from a caller's traceparent header follow the caller's decision")
 
 	viper.RegisterAlias("algod", "algod-data-dir")
 	viper.RegisterAlias("algod-net", "algod-address")
@@ -134,9 +588,20 @@ func init() {
 // makeOptions converts CLI options to server options
 func makeOptions() (options api.ExtraOptions) {
 	options.DeveloperMode = developerMode
-	if tokenString != "" {
-		options.Tokens = append(options.Tokens, tokenString)
+	// The tokens are replaced when the configuration is reloaded.
+	options.TokenSet = middlewares.MakeTokens([]string{tokenString})
+	if adminTokenString != "" {
+		options.AdminTokenSet = middlewares.MakeTokens([]string{adminTokenString})
 	}
+	// The rate limit and the disabled endpoints are replaced when the
+	// configuration is reloaded as well.
+	options.RateLimit = middlewares.MakeRateLimit(apiRateLimit, apiRateBurst)
+	options.DisabledEndpoints = middlewares.MakeDisabledEndpoints(disabledPaths)
+	options.EnablePprof = enablePprof
+	options.ReadyMaxLag = readyMaxLag
+	options.PaginationKey = []byte(paginationKey)
+	options.EnableExperimentalV3 = enableV3
+	options.DrainTimeout = drainTimeout
 	switch strings.ToUpper(metricsMode) {
 	case "OFF":
 		options.MetricsEndpoint = false
@@ -152,14 +617,259 @@ func makeOptions() (options api.ExtraOptions) {
 	return
 }
 
+// publicFlags are the flags whose values are shown by the admin API. Their
+// values are paths, sizes, durations and switches. The values of the other
+// flags are redacted when they are set, so that a new flag which holds a secret,
+// e.g. a URL with credentials, is not shown unless it is added here.
+var publicFlags = map[string]bool{
+	"account-cache-size":           true,
+	"algod":                        true,
+	"algod-ca":                     true,
+	"algod-cert":                   true,
+	"algod-rate-burst":             true,
+	"algod-rate-limit":             true,
+	"allow-migration":              true,
+	"api-rate-burst":               true,
+	"api-rate-limit":               true,
+	"archive-region":               true,
+	"block-cache-dir":              true,
+	"block-cache-rounds":           true,
+	"block-stream-address":         true,
+	"block-stream-rounds":          true,
+	"bulk-import-blocks":           true,
+	"bulk-import-mb":               true,
+	"chain-metrics-job":            true,
+	"cockroach-compat":             true,
+	"compress-transactions":        true,
+	"cpuprofile":                   true,
+	"dev-mode":                     true,
+	"disabled-endpoint":            true,
+	"drain-timeout":                true,
+	"dummydb":                      true,
+	"elasticsearch-index-prefix":   true,
+	"enable-experimental-v3":       true,
+	"enable-pprof":                 true,
+	"enable-search":                true,
+	"enable-webhooks":              true,
+	"events-block-topic":           true,
+	"events-transaction-topic":     true,
+	"fetch-backoff":                true,
+	"fetch-backoff-jitter":         true,
+	"fetch-backoff-max":            true,
+	"fetch-retries":                true,
+	"genesis":                      true,
+	"health-check-period":          true,
+	"help":                         true,
+	"kafka-msgpack":                true,
+	"kafka-start-round":            true,
+	"kafka-topic-prefix":           true,
+	"log-format":                   true,
+	"logfile":                      true,
+	"logfile-max-files":            true,
+	"logfile-max-size-mb":          true,
+	"logfile-rotate-interval":      true,
+	"loglevel":                     true,
+	"lookup-batch-size":            true,
+	"lookup-pipeline":              true,
+	"maintenance-change-threshold": true,
+	"maintenance-interval":         true,
+	"maintenance-vacuum":           true,
+	"max-conn":                     true,
+	"max-conn-lifetime":            true,
+	"metrics-mode":                 true,
+	"metrics-sink":                 true,
+	"migration-free-disk-gb":       true,
+	"migration-workers":            true,
+	"min-conn":                     true,
+	"no-algod":                     true,
+	"pgbouncer-compat":             true,
+	"pidfile":                      true,
+	"poll-interval":                true,
+	"postgres-schema":              true,
+	"ready-max-lag":                true,
+	"replica-max-lag":              true,
+	"retain-participation-rounds":  true,
+	"retain-rounds":                true,
+	"scope-address":                true,
+	"sentry-environment":           true,
+	"server":                       true,
+	"slow-query-threshold":         true,
+	"sql-migrations-dir":           true,
+	"statement-timeout":            true,
+	"statsd-address":               true,
+	"statsd-interval":              true,
+	"stop-at-round":                true,
+	"tip-mode":                     true,
+	"trace-sample-ratio":           true,
+	"txn-rules":                    true,
+	"unknown-protocol":             true,
+	"validate-accounts":            true,
+	"validate-interval":            true,
+	"version":                      true,
+	"wait-timeout":                 true,
+}
+
+// redactedConfig returns the value of every flag for the admin API. The values
+// of the flags which are not in publicFlags are replaced unless they are the
+// default.
+func redactedConfig(cmd *cobra.Command) map[string]string {
+	config := make(map[string]string)
+	cmd.Flags().VisitAll(func(f *pflag.Flag) {
+		value := f.Value.String()
+		if !publicFlags[f.Name] && value != f.DefValue {
+			value = "<redacted>"
+		}
+		config[f.Name] = value
+	})
+	return config
+}
+
+// addArchiveCredentialFlags adds the flags of the credentials of S3 and GCS
+// archives to `cmd`.
+func addArchiveCredentialFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVarP(&archiveOptions.AccessKeyID, "archive-access-key-id", "", "", "access key id of the s3:// and gs:// archives, an AWS access key or the HMAC key of a GCS service account (defaults to none, the buckets must be public)")
+	cmd.Flags().StringVarP(&archiveOptions.SecretAccessKey, "archive-secret-access-key", "", "", "secret of --archive-access-key-id")
+	cmd.Flags().StringVarP(&archiveOptions.SessionToken, "archive-session-token", "", "", "session token of temporary AWS credentials")
+	cmd.Flags().StringVarP(&archiveOptions.Region, "archive-region", "", "", "region of the S3 buckets of the archives (defaults to us-east-1)")
+}
+
+// makeAlgodOptions returns the options of the connection to algod set by the
+// --algod-* flags.
+func makeAlgodOptions() (fetcher.AlgodOptions, error) {
+	opts := fetcher.AlgodOptions{
+		Headers:  make(map[string]string, len(algodHeaders)),
+		CertFile: algodCertFile,
+		KeyFile:  algodKeyFile,
+		CAFile:   algodCAFile,
+	}
+	for _, header := range algodHeaders {
+		parts := strings.SplitN(header, ":", 2)
+		if len(parts) != 2 || strings.TrimSpace(parts[0]) == "" {
+			return fetcher.AlgodOptions{}, fmt.Errorf("--algod-header %q is not \"Name: value\"", header)
+		}
+		opts.Headers[strings.TrimSpace(parts[0])] = strings.TrimSpace(parts[1])
+	}
+	if (algodCertFile == "") != (algodKeyFile == "") {
+		return fetcher.AlgodOptions{}, fmt.Errorf("--algod-cert and --algod-key must be set together")
+	}
+	return opts, nil
+}
+
+// makeTracingOptions returns the options of the tracer set by the --otlp-* and
+// --trace-* flags.
+func makeTracingOptions() (tracing.Options, error) {
+	opts := tracing.Options{
+		Endpoint:    otlpEndpoint,
+		Headers:     make(map[string]string, len(otlpHeaders)),
+		ServiceName: "algorand-indexer",
+		SampleRatio: traceSampleRatio,
+	}
+	for _, header := range otlpHeaders {
+		parts := strings.SplitN(header, ":", 2)
+		if len(parts) != 2 || strings.TrimSpace(parts[0]) == "" {
+			return tracing.Options{}, fmt.Errorf("--otlp-header %q is not \"Name: value\"", header)
+		}
+		opts.Headers[strings.TrimSpace(parts[0])] = strings.TrimSpace(parts[1])
+	}
+	return opts, nil
+}
+
+// Values of --unknown-protocol.
+const (
+	unknownProtocolFail  = "fail"
+	unknownProtocolPause = "pause"
+)
+
+var unknownProtocolRound = metrics.DefaultRegistry.NewGaugeVec(
+	"import_unknown_protocol_round",
+	"Round of a consensus protocol unknown to this version at which the import is paused.")
+
 type blockImporterHandler struct {
-	imp importer.Importer
+	imp    importer.Importer
+	pauser *importer.Pauser
+	ctx    context.Context
+
+	// latestRound returns the round of algod. While the import is more than
+	// bulkBlocks rounds behind it, blocks are buffered and imported bulkBlocks at
+	// a time, or as soon as their fetched size reaches bulkBytes unless it is 0.
+	latestRound   func() (uint64, bool)
+	bulkBlocks    int
+	bulkBytes     uint64
+	buffered      []*rpcs.EncodedBlockCert
+	bufferedBytes uint64
+
+	// stop shuts down the daemon once the stop round of the importer is
+	// imported.
+	stop func()
+}
+
+// atStopRound returns true if `block` is the last block which is imported.
+func (bih *blockImporterHandler) atStopRound(block *rpcs.EncodedBlockCert) bool {
+	return stopAtRound != 0 && uint64(block.Block.Round()) >= stopAtRound
+}
+
+// bufferFull returns true if the buffered blocks should be imported although
+// the import is still far behind.
+func (bih *blockImporterHandler) bufferFull() bool {
+	return len(bih.buffered) >= bih.bulkBlocks ||
+		(bih.bulkBytes > 0 && bih.bufferedBytes >= bih.bulkBytes)
+}
+
+// farBehind returns true if the import of `round` is far enough behind algod for
+// batching.
+func (bih *blockImporterHandler) farBehind(round uint64) bool {
+	if bih.bulkBlocks <= 1 || bih.latestRound == nil {
+		return false
+	}
+	latest, ok := bih.latestRound()
+	return ok && round+uint64(bih.bulkBlocks) < latest
 }
 
+// HandleBlock is part of fetcher.BlockHandler.
 func (bih *blockImporterHandler) HandleBlock(block *rpcs.EncodedBlockCert) {
+	bih.HandleSizedBlock(block, len(protocol.Encode(block)))
+}
+
+// HandleSizedBlock is part of fetcher.SizedBlockHandler. `size` is the length of
+// the fetched block, it counts towards bulkBytes.
+func (bih *blockImporterHandler) HandleSizedBlock(block *rpcs.EncodedBlockCert, size int) {
+	// Blocks while an operator paused the import.
+	if err := bih.pauser.Wait(bih.ctx); err != nil {
+		return
+	}
+	// The fetcher may deliver a few more blocks before it stops.
+	if bih.imp.StopRoundReached() {
+		return
+	}
+	defer bih.checkStopRound()
+
+	if unknownProtocol == unknownProtocolPause && !importer.ProtocolSupported(&block.Block) {
+		if len(bih.buffered) > 0 {
+			bih.importBuffered()
+		}
+		bih.pauseUnknownProtocol(block)
+		return
+	}
+
+	// Buffered blocks are only lost on shutdown, they are fetched again at
+	// startup since none of them was committed.
+	farBehind := bih.farBehind(uint64(block.Block.Round()))
+	if farBehind || len(bih.buffered) > 0 {
+		bih.buffered = append(bih.buffered, block)
+		bih.bufferedBytes += uint64(size)
+		if farBehind && !bih.bufferFull() && !bih.atStopRound(block) {
+			return
+		}
+		bih.importBuffered()
+		return
+	}
+
 	start := time.Now()
 	err := bih.imp.ImportBlock(block)
-	maybeFail(err, "adding block %d to database failed", block.Block.Round())
+	if err != nil {
+		logger.WithError(err).WithField("round", uint64(block.Block.Round())).Error("adding block to database failed")
+		exit(1)
+	}
 	dt := time.Since(start)
 
 	// Ignore round 0 (which is empty).
@@ -169,5 +879,88 @@ func (bih *blockImporterHandler) HandleBlock(block *rpcs.EncodedBlockCert) {
 		metrics.ImportedRoundGauge.Set(float64(block.Block.Round()))
 	}
 
-	logger.Infof("round r=%d (%d txn) imported in %s", block.Block.Round(), len(block.Block.Payset), dt.String())
+	logger.WithFields(log.Fields{
+		"round":    uint64(block.Block.Round()),
+		"txns":     len(block.Block.Payset),
+		"duration": dt.Seconds(),
+	}).Info("round imported")
+}
+
+// pauseUnknownProtocol pauses the import at `block`, whose consensus protocol
+// this version does not know, until the daemon stops. The fetcher already wrote
+// the block to the block cache, the upgraded indexer continues from there.
+// Resuming the import from the admin API pauses it again.
+func (bih *blockImporterHandler) pauseUnknownProtocol(block *rpcs.EncodedBlockCert) {
+	unknownProtocolRound.WithLabelValues().Set(float64(block.Block.Round()))
+	for bih.ctx.Err() == nil {
+		logger.WithFields(log.Fields{
+			"round":    uint64(block.Block.Round()),
+			"protocol": block.Block.CurrentProtocol,
+		}).Error("the round uses an unknown consensus protocol, the import is paused until the indexer is upgraded")
+		bih.pauser.Pause()
+		bih.pauser.Wait(bih.ctx)
+	}
+}
+
+// checkStopRound shuts down the daemon if the stop round was imported.
+func (bih *blockImporterHandler) checkStopRound() {
+	if bih.imp.StopRoundReached() {
+		logger.WithField("round", stopAtRound).Info("Stop round imported, exiting.")
+		bih.stop()
+	}
+}
+
+// importBuffered imports the buffered blocks in one database transaction.
+func (bih *blockImporterHandler) importBuffered() {
+	blocks := bih.buffered
+	bih.buffered = nil
+	bih.bufferedBytes = 0
+	first := blocks[0].Block.Round()
+	last := blocks[len(blocks)-1].Block.Round()
+
+	start := time.Now()
+	err := bih.imp.ImportBlocks(blocks)
+	if err != nil {
+		logger.WithError(err).WithFields(log.Fields{
+			"round":      uint64(first),
+			"last_round": uint64(last),
+		}).Error("adding blocks to database failed")
+		exit(1)
+	}
+	dt := time.Since(start)
+
+	txns := 0
+	for _, block := range blocks {
+		txns += len(block.Block.Payset)
+		if block.Block.Round() > 0 {
+			metrics.BlockImportTimeSeconds.Observe(dt.Seconds() / float64(len(blocks)))
+			metrics.ImportedTxnsPerBlock.Observe(float64(len(block.Block.Payset)))
+		}
+	}
+	metrics.ImportedRoundGauge.Set(float64(last))
+
+	logger.WithFields(log.Fields{
+		"round":      uint64(first),
+		"last_round": uint64(last),
+		"txns":       txns,
+		"duration":   dt.Seconds(),
+	}).Info("rounds imported")
+}
+
+// followRounds publishes the rounds which the database notifies about until the
+// context is done, reconnecting when the connection fails.
+func followRounds(ctx context.Context, db idb.IndexerDb, publisher *importer.RoundPublisher) {
+	for {
+		err := db.ListenRounds(ctx, publisher.Publish)
+		if ctx.Err() != nil {
+			return
+		}
+		logger.WithError(err).Warn("listening for imported rounds failed, reconnecting")
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(time.Second):
+		}
+	}
 }
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/algorand/indexer/config"
+	"github.com/algorand/indexer/idb"
+)
+
+var dumpCmd = &cobra.Command{
+	Use:   "dump",
+	Short: "dump the current accounts, assets or asset balances to CSV",
+	Long:  "write all the current rows of a table, read in one consistent snapshot of the latest imported round while the import continues, to a CSV file with a header, e.g. for audits and offline reconciliation. Closed accounts, destroyed assets and closed out balances are not written.",
+}
+
+// makeDumpCmd returns the dump subcommand of `table`.
+func makeDumpCmd(table string, description string) *cobra.Command {
+	return &cobra.Command{
+		Use:   table,
+		Short: "dump " + description + " to CSV",
+		Long:  "write " + description + " to --output as CSV, gzipped if it ends in .gz.",
+		Run: func(cmd *cobra.Command, args []string) {
+			config.BindFlags(cmd)
+			err := configureLogger()
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "failed to configure logger: %v", err)
+				os.Exit(1)
+			}
+			if dumpFile == "" {
+				fmt.Fprintf(os.Stderr, "--output is required\n")
+				os.Exit(1)
+			}
+
+			f, err := os.Create(dumpFile)
+			maybeFail(err, "failed to create output, %v", err)
+			fail := func(err error, errfmt string) {
+				if err != nil {
+					f.Close()
+					os.Remove(dumpFile)
+					maybeFail(err, errfmt, err)
+				}
+			}
+			var out io.Writer = f
+			var gz *gzip.Writer
+			if strings.HasSuffix(dumpFile, ".gz") {
+				gz = gzip.NewWriter(f)
+				out = gz
+			}
+
+			db, availableCh := indexerDbFromFlags(idb.IndexerDbOptions{ReadOnly: true})
+			<-availableCh
+
+			round, err := db.DumpCSV(context.Background(), table, out)
+			fail(err, "dump failed, %v")
+			if gz != nil {
+				fail(gz.Close(), "failed to close output, %v")
+			}
+			fail(f.Close(), "failed to close output, %v")
+			fmt.Printf("dumped the %s of round %d\n", table, round)
+		},
+	}
+}
+
+var dumpFile string
+
+func init() {
+	for _, cmd := range []*cobra.Command{
+		makeDumpCmd(idb.DumpAccounts, "the current accounts"),
+		makeDumpCmd(idb.DumpAssets, "the current assets"),
+		makeDumpCmd(idb.DumpBalances, "the current asset balances of the accounts"),
+	} {
+		cmd.Flags().StringVarP(&dumpFile, "output", "o", "", "file the CSV is written to, gzipped if it ends in .gz")
+		dumpCmd.AddCommand(cmd)
+	}
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/algorand/indexer/config"
+	"github.com/algorand/indexer/idb"
+	"github.com/algorand/indexer/warehouse"
+)
+
+var exportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "export the history for a data warehouse",
+	Long:  "write the transactions of a round range, and the current accounts and assets, to partitioned newline-delimited JSON, Avro, Parquet or Arrow files in --dir which BigQuery and Snowflake, Spark and DuckDB, or pandas and R, can load. The manifest.json of the directory lists the written files, and the next export continues after the last exported round unless --from-round is set.",
+	Run: func(cmd *cobra.Command, args []string) {
+		config.BindFlags(cmd)
+		err := configureLogger()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "failed to configure logger: %v", err)
+			os.Exit(1)
+		}
+		if exportDir == "" {
+			fmt.Fprintf(os.Stderr, "--dir is required\n")
+			os.Exit(1)
+		}
+
+		opts := warehouse.Options{
+			Dir:             exportDir,
+			Format:          exportFormat,
+			Tables:          exportTables,
+			PartitionRounds: exportPartitionRounds,
+		}
+		if cmd.Flags().Changed("from-round") {
+			opts.FromRound = &exportFromRound
+		}
+		if cmd.Flags().Changed("to-round") {
+			opts.ToRound = &exportToRound
+		}
+
+		db, availableCh := indexerDbFromFlags(idb.IndexerDbOptions{ReadOnly: true})
+		<-availableCh
+
+		manifest, err := warehouse.Export(context.Background(), db, opts, logger)
+		maybeFail(err, "export failed, %v", err)
+		fmt.Printf("exported to %s, the next round to export is %d\n", exportDir, manifest.NextRound)
+	},
+}
+
+var (
+	exportDir             string
+	exportFormat          string
+	exportTables          []string
+	exportFromRound       uint64
+	exportToRound         uint64
+	exportPartitionRounds uint64
+)
+
+func init() {
+	exportCmd.Flags().StringVarP(&exportDir, "dir", "d", "", "directory the files and the manifest are written to")
+	exportCmd.Flags().StringVarP(&exportFormat, "format", "", warehouse.FormatNDJSON, "format of the files, ndjson, avro, parquet or arrow")
+	exportCmd.Flags().StringSliceVarP(&exportTables, "tables", "", []string{warehouse.TransactionsTable, warehouse.AccountsTable, warehouse.AssetsTable}, "tables to export")
+	exportCmd.Flags().Uint64VarP(&exportFromRound, "from-round", "", 0, "first round of the transactions, by default the round after the previous export")
+	exportCmd.Flags().Uint64VarP(&exportToRound, "to-round", "", 0, "last round of the transactions, by default the latest imported round")
+	exportCmd.Flags().Uint64VarP(&exportPartitionRounds, "partition-rounds", "", warehouse.DefaultPartitionRounds, "rounds of a transactions partition")
+}
@@ -30,6 +30,7 @@ var importCmd = &cobra.Command{
 			genesisJSONPath,
 			blockFileLimit,
 			logger)
+		helper.StopAtRound = importStopRound
 
 		helper.Import(db, args)
 	},
@@ -38,9 +39,11 @@ var importCmd = &cobra.Command{
 var (
 	genesisJSONPath string
 	blockFileLimit  int
+	importStopRound uint64
 )
 
 func init() {
 	importCmd.Flags().StringVarP(&genesisJSONPath, "genesis", "g", "", "path to genesis.json")
 	importCmd.Flags().IntVarP(&blockFileLimit, "block-file-limit", "", 0, "number of block files to process (for debugging)")
+	importCmd.Flags().Uint64VarP(&importStopRound, "stop-at-round", "", 0, "last round to import, the later blocks of the files are skipped (defaults to 0, no limit)")
 }
@@ -6,6 +6,7 @@ import (
 	"os"
 	"runtime/pprof"
 	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 	//"github.com/spf13/cobra/doc" // TODO: enable cobra doc generation
@@ -16,7 +17,9 @@ import (
 	"github.com/algorand/indexer/idb"
 	"github.com/algorand/indexer/idb/dummy"
 	_ "github.com/algorand/indexer/idb/postgres"
+	"github.com/algorand/indexer/util/logging"
 	"github.com/algorand/indexer/util/metrics"
+	"github.com/algorand/indexer/util/reporting"
 	"github.com/algorand/indexer/version"
 )
 
@@ -25,7 +28,13 @@ func maybeFail(err error, errfmt string, params ...interface{}) {
 		return
 	}
 	logger.WithError(err).Errorf(errfmt, params...)
-	os.Exit(1)
+	exit(1)
+}
+
+// exit sends the queued error reports and exits.
+func exit(code int) {
+	reporting.Close(5 * time.Second)
+	os.Exit(code)
 }
 
 var rootCmd = &cobra.Command{
@@ -79,14 +88,24 @@ var (
 	doVersion      bool
 	cpuProfile     string
 	pidFilePath    string
+	postgresSchema string
+	sqlMigrations  string
 	profFile       io.WriteCloser
 	logLevel       string
 	logFile        string
+	logFormat      string
+	logMaxSizeMB   int64
+	logRotateEvery time.Duration
+	logMaxFiles    int
+	sentryDSN      string
+	sentryEnv      string
 	logger         *log.Logger
 )
 
 func indexerDbFromFlags(opts idb.IndexerDbOptions) (idb.IndexerDb, chan struct{}) {
 	if postgresAddr != "" {
+		opts.Schema = postgresSchema
+		opts.SQLMigrationsDir = sqlMigrations
 		db, ch, err := idb.IndexerDbByName("postgres", postgresAddr, opts, logger)
 		maybeFail(err, "could not init db, %v", err)
 		return db, ch
@@ -101,19 +120,35 @@ func indexerDbFromFlags(opts idb.IndexerDbOptions) (idb.IndexerDb, chan struct{}
 
 func init() {
 	logger = log.New()
-	logger.SetFormatter(&log.JSONFormatter{
-		DisableHTMLEscape: true,
-	})
+	logging.Configure(logger, logging.FormatJSON)
 	logger.SetOutput(os.Stdout)
 	logger.SetLevel(log.InfoLevel)
 
 	rootCmd.AddCommand(importCmd)
 	importCmd.Hidden = true
 	rootCmd.AddCommand(daemonCmd)
+	rootCmd.AddCommand(cleanupCmd)
+	rootCmd.AddCommand(verifyArchiveCmd)
+	rootCmd.AddCommand(backfillCmd)
+	rootCmd.AddCommand(archiveParticipationCmd)
+	rootCmd.AddCommand(snapshotCmd)
+	rootCmd.AddCommand(rederiveCmd)
+	rootCmd.AddCommand(reprocessCmd)
+	rootCmd.AddCommand(exportCmd)
+	rootCmd.AddCommand(dumpCmd)
+	rootCmd.AddCommand(migrationsCmd)
 
 	rootCmd.PersistentFlags().StringVarP(&logLevel, "loglevel", "l", "info", "verbosity of logs: [error, warn, info, debug, trace]")
 	rootCmd.PersistentFlags().StringVarP(&logFile, "logfile", "f", "", "file to write logs to, if unset logs are written to standard out")
+	rootCmd.PersistentFlags().Int64VarP(&logMaxSizeMB, "logfile-max-size-mb", "", 0, "rotate the --logfile when it reaches this many MB (defaults to 0, no limit)")
+	rootCmd.PersistentFlags().DurationVarP(&logRotateEvery, "logfile-rotate-interval", "", 0, "rotate the --logfile after this long, e.g. 24h (defaults to 0, never)")
+	rootCmd.PersistentFlags().IntVarP(&logMaxFiles, "logfile-max-files", "", 0, "number of rotated log files which are kept, the oldest ones are deleted (defaults to 0, keep all)")
+	rootCmd.PersistentFlags().StringVarP(&logFormat, "log-format", "", logging.FormatJSON, "format of the logs: json, one object per line with the fields round, txns, duration and module, or text for reading in a terminal")
+	rootCmd.PersistentFlags().StringVarP(&sentryDSN, "sentry-dsn", "", "", "report panics and the errors which are logged, e.g. failed imports and migrations, to this Sentry compatible DSN (defaults to no reporting)")
+	rootCmd.PersistentFlags().StringVarP(&sentryEnv, "sentry-environment", "", "", "environment of the error reports, e.g. mainnet")
 	rootCmd.PersistentFlags().StringVarP(&postgresAddr, "postgres", "P", "", "connection string for postgres database")
+	rootCmd.PersistentFlags().StringVarP(&postgresSchema, "postgres-schema", "", "", "postgres schema of the indexer tables, created if it does not exist, so that several indexers can share a database (defaults to the search_path of the connection, usually public)")
+	rootCmd.PersistentFlags().StringVarP(&sqlMigrations, "sql-migrations-dir", "", "", "directory of SQL scripts named like 001_name.sql, which run once each as migrations after the built-in ones, e.g. to create site specific indexes")
 	rootCmd.PersistentFlags().BoolVarP(&dummyIndexerDb, "dummydb", "n", false, "use dummy indexer db")
 	rootCmd.PersistentFlags().StringVarP(&cpuProfile, "cpuprofile", "", "", "file to record cpu profile to")
 	rootCmd.PersistentFlags().StringVarP(&pidFilePath, "pidfile", "", "", "file to write daemon's process id to")
@@ -148,6 +183,10 @@ func init() {
 }
 
 func configureLogger() error {
+	err := logging.Configure(logger, logFormat)
+	if err != nil {
+		return err
+	}
 	if logLevel != "" {
 		level, err := log.ParseLevel(logLevel)
 		if err != nil {
@@ -159,19 +198,42 @@ func configureLogger() error {
 	if logFile == "-" {
 		logger.SetOutput(os.Stdout)
 	} else if logFile != "" {
-		f, err := os.OpenFile(logFile, os.O_APPEND|os.O_WRONLY|os.O_CREATE, 0755)
+		// The rotated files are named <logfile>.<time of rotation>.
+		f, err := logging.OpenRotatingFile(logFile, logging.RotateOptions{
+			MaxSize:  logMaxSizeMB << 20,
+			Interval: logRotateEvery,
+			MaxFiles: logMaxFiles,
+		})
 		if err != nil {
 			return err
 		}
 		logger.SetOutput(f)
 	}
 
+	// The errors are reported by logging them.
+	if sentryDSN != "" {
+		hostname, _ := os.Hostname()
+		reporter, err := reporting.MakeReporter(reporting.Options{
+			DSN:         sentryDSN,
+			Environment: sentryEnv,
+			Release:     version.Version(),
+			ServerName:  hostname,
+		}, logger)
+		if err != nil {
+			return err
+		}
+		reporting.SetReporter(reporter)
+		logger.AddHook(reporter)
+	}
+
 	return nil
 }
 
 func main() {
+	defer reporting.ReportPanic()
 	if err := rootCmd.Execute(); err != nil {
 		logger.WithError(err).Error("an error occurred running indexer")
-		os.Exit(1)
+		exit(1)
 	}
+	reporting.Close(5 * time.Second)
 }
@@ -0,0 +1,124 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/algorand/indexer/config"
+	"github.com/algorand/indexer/idb"
+)
+
+var migrationsCmd = &cobra.Command{
+	Use:   "migrations",
+	Short: "manage the database migrations",
+	Long:  "manage the migrations of the database schema and data, which the daemon runs when it starts.",
+}
+
+var migrationsListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "list the migrations",
+	Long:  "list the migrations of this release in the order they run, whether they were applied to the database, are blocking, i.e. the database is unavailable while they run, and can be rolled back.",
+	Run: func(cmd *cobra.Command, args []string) {
+		infos := loadMigrations(cmd)
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "ID\tSTATE\tBLOCKING\tREVERSIBLE\tDESCRIPTION")
+		for _, info := range infos {
+			state := "pending"
+			if info.Applied {
+				state = "applied"
+			}
+			fmt.Fprintf(
+				w, "%d\t%s\t%t\t%t\t%s\n",
+				info.ID, state, info.Blocking, info.Reversible, info.Description)
+		}
+		w.Flush()
+	},
+}
+
+var migrationsStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "check for pending migrations",
+	Long:  "print the number of applied migrations and the pending ones. Exits with 1 if a blocking migration is pending, i.e. the database will be unavailable while the daemon migrates it, so deployment pipelines can plan for the downtime.",
+	Run: func(cmd *cobra.Command, args []string) {
+		infos := loadMigrations(cmd)
+
+		var pending []idb.MigrationInfo
+		blocking := false
+		for _, info := range infos {
+			if !info.Applied {
+				pending = append(pending, info)
+				blocking = blocking || info.Blocking
+			}
+		}
+		fmt.Printf("%d of %d migrations applied\n", len(infos)-len(pending), len(infos))
+		for _, info := range pending {
+			if info.Blocking {
+				fmt.Printf("pending blocking migration %d: %s\n", info.ID, info.Description)
+			} else {
+				fmt.Printf("pending migration %d: %s\n", info.ID, info.Description)
+			}
+		}
+		if blocking {
+			os.Exit(1)
+		}
+	},
+}
+
+// loadMigrations connects to the database without running the migrations and
+// returns them.
+func loadMigrations(cmd *cobra.Command) []idb.MigrationInfo {
+	config.BindFlags(cmd)
+	err := configureLogger()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to configure logger: %v", err)
+		os.Exit(1)
+	}
+
+	db, availableCh := indexerDbFromFlags(idb.IndexerDbOptions{SkipMigrations: true})
+	<-availableCh
+
+	infos, err := db.Migrations(context.Background())
+	maybeFail(err, "failed to read the migrations, %v", err)
+	return infos
+}
+
+var migrationsRollbackCmd = &cobra.Command{
+	Use:   "rollback",
+	Short: "roll back the latest migrations",
+	Long:  "undo the latest --count completed migrations, newest first, so that the database can be used by an older release. Run it with the release which ran the migrations, after stopping the daemons. Nothing is rolled back if one of the migrations can't be undone.",
+	Run: func(cmd *cobra.Command, args []string) {
+		config.BindFlags(cmd)
+		err := configureLogger()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "failed to configure logger: %v", err)
+			os.Exit(1)
+		}
+		if migrationsRollbackCount < 1 {
+			fmt.Fprintf(os.Stderr, "--count must be at least 1\n")
+			os.Exit(1)
+		}
+
+		db, availableCh := indexerDbFromFlags(idb.IndexerDbOptions{SkipMigrations: true})
+		<-availableCh
+
+		rolledBack, err := db.RollbackMigrations(context.Background(), migrationsRollbackCount)
+		for _, description := range rolledBack {
+			fmt.Printf("rolled back: %s\n", description)
+		}
+		maybeFail(err, "rollback failed, %v", err)
+	},
+}
+
+var migrationsRollbackCount int
+
+func init() {
+	migrationsRollbackCmd.Flags().IntVarP(&migrationsRollbackCount, "count", "n", 1, "number of migrations to roll back")
+	migrationsCmd.AddCommand(migrationsListCmd)
+	migrationsCmd.AddCommand(migrationsStatusCmd)
+	migrationsCmd.AddCommand(migrationsRollbackCmd)
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"github.com/algorand/go-algorand/data/bookkeeping"
+	"github.com/algorand/go-algorand/protocol"
+	"github.com/spf13/cobra"
+
+	"github.com/algorand/indexer/config"
+	"github.com/algorand/indexer/idb"
+)
+
+var rederiveCmd = &cobra.Command{
+	Use:   "rederive",
+	Short: "rebuild the account state from the stored blocks",
+	Long:  "rebuild the account, asset and app tables by evaluating the stored blocks from the genesis on, without fetching them from algod, and report how they differ from the stored tables. With --repair the rebuilt tables replace the stored ones. Stop the daemon first, the tables are rebuilt in one database transaction which conflicts with the import.",
+	Run: func(cmd *cobra.Command, args []string) {
+		config.BindFlags(cmd)
+		err := configureLogger()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "failed to configure logger: %v", err)
+			os.Exit(1)
+		}
+		if rederiveGenesisPath == "" {
+			fmt.Fprintf(os.Stderr, "--genesis is required\n")
+			os.Exit(1)
+		}
+
+		genesisJSON, err := ioutil.ReadFile(rederiveGenesisPath)
+		maybeFail(err, "failed to read genesis, %v", err)
+		var genesis bookkeeping.Genesis
+		err = protocol.DecodeJSON(genesisJSON, &genesis)
+		maybeFail(err, "failed to decode genesis, %v", err)
+
+		db, availableCh := indexerDbFromFlags(idb.IndexerDbOptions{})
+		<-availableCh
+
+		results, err := db.Rederive(context.Background(), genesis, rederiveRepair)
+		maybeFail(err, "rederive failed, %v", err)
+
+		var differences int64
+		for _, result := range results {
+			fmt.Printf(
+				"%s: %d rows, %d stored rows missing, %d extra rows\n",
+				result.Table, result.Rows, result.Missing, result.Extra)
+			differences += result.Missing + result.Extra
+		}
+		switch {
+		case differences == 0:
+			fmt.Println("the stored state matches the blocks")
+		case rederiveRepair:
+			fmt.Println("the stored state was replaced")
+		default:
+			fmt.Println("the stored state differs from the blocks, run again with --repair to replace it")
+			os.Exit(1)
+		}
+	},
+}
+
+var (
+	rederiveGenesisPath string
+	rederiveRepair      bool
+)
+
+func init() {
+	rederiveCmd.Flags().StringVarP(&rederiveGenesisPath, "genesis", "g", "", "path to the genesis.json of the network")
+	rederiveCmd.Flags().BoolVarP(&rederiveRepair, "repair", "", false, "replace the stored tables with the rebuilt ones")
+}
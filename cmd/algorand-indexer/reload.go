@@ -0,0 +1,154 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+
+	"github.com/algorand/indexer/api/middlewares"
+	"github.com/algorand/indexer/fetcher"
+)
+
+// reloadableFlags are the daemon flags which are read again from the
+// configuration file on SIGHUP.
+var reloadableFlags = []string{
+	"loglevel", "token", "admin-token", "algod-rate-limit", "algod-rate-burst",
+	"api-rate-limit", "api-rate-burst", "disabled-endpoint",
+}
+
+// reloader applies the reloadable flags to a running daemon. The import and the
+// API requests in flight are not interrupted.
+type reloader struct {
+	cmd *cobra.Command
+	// cmdline are the flags set on the command line, they keep their value.
+	cmdline map[string]bool
+
+	bot         fetcher.Fetcher
+	tokens      *middlewares.Tokens
+	adminTokens *middlewares.Tokens
+	rateLimit   *middlewares.RateLimit
+	disabled    *middlewares.DisabledEndpoints
+}
+
+// commandLineFlags returns the flags of `cmd` set on the command line, it must
+// be called before config.BindFlags.
+func commandLineFlags(cmd *cobra.Command) map[string]bool {
+	cmdline := make(map[string]bool)
+	cmd.Flags().Visit(func(f *pflag.Flag) {
+		cmdline[f.Name] = true
+	})
+	return cmdline
+}
+
+// run reloads the configuration on every SIGHUP until `ctx` is done.
+func (r *reloader) run(ctx context.Context) {
+	hupCh := make(chan os.Signal, 1)
+	signal.Notify(hupCh, syscall.SIGHUP)
+	defer signal.Stop(hupCh)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-hupCh:
+			err := r.reload()
+			if err != nil {
+				logger.WithError(err).Error("reloading the configuration failed, keeping the current one")
+				continue
+			}
+			logger.Info("configuration reloaded")
+		}
+	}
+}
+
+// reload reads the configuration file again and applies the reloadable flags.
+// Nothing is applied when a value is invalid.
+func (r *reloader) reload() error {
+	err := viper.ReadInConfig()
+	if _, ok := err.(viper.ConfigFileNotFoundError); err != nil && !ok {
+		return fmt.Errorf("reload() err: %w", err)
+	}
+
+	values := make(map[string]string, len(reloadableFlags))
+	slices := make(map[string][]string)
+	for _, name := range reloadableFlags {
+		f := r.cmd.Flags().Lookup(name)
+		if f == nil || r.cmdline[name] {
+			continue
+		}
+		// The default of a slice flag is shown as "[]", it can't be set again.
+		if _, ok := f.Value.(pflag.SliceValue); ok {
+			slices[name] = viper.GetStringSlice(name)
+			continue
+		}
+		value := f.DefValue
+		if viper.IsSet(name) {
+			value = fmt.Sprintf("%v", viper.Get(name))
+		}
+		values[name] = value
+	}
+	if value, ok := values["loglevel"]; ok {
+		if _, err := log.ParseLevel(value); err != nil {
+			return fmt.Errorf("reload() loglevel err: %w", err)
+		}
+	}
+	for _, name := range []string{"algod-rate-limit", "api-rate-limit"} {
+		if value, ok := values[name]; ok {
+			if _, err := strconv.ParseFloat(value, 64); err != nil {
+				return fmt.Errorf("reload() %s err: %w", name, err)
+			}
+		}
+	}
+	for _, name := range []string{"algod-rate-burst", "api-rate-burst"} {
+		if value, ok := values[name]; ok {
+			if _, err := strconv.Atoi(value); err != nil {
+				return fmt.Errorf("reload() %s err: %w", name, err)
+			}
+		}
+	}
+
+	for name, value := range values {
+		if err := r.cmd.Flags().Set(name, value); err != nil {
+			return fmt.Errorf("reload() %s err: %w", name, err)
+		}
+	}
+	for name, value := range slices {
+		f := r.cmd.Flags().Lookup(name)
+		if err := f.Value.(pflag.SliceValue).Replace(value); err != nil {
+			return fmt.Errorf("reload() %s err: %w", name, err)
+		}
+	}
+	r.apply()
+	return nil
+}
+
+// apply applies the values of the reloadable flags.
+func (r *reloader) apply() {
+	if level, err := log.ParseLevel(logLevel); err == nil {
+		logger.SetLevel(level)
+	}
+	if r.tokens != nil {
+		r.tokens.Set([]string{tokenString})
+	}
+	if r.adminTokens != nil {
+		r.adminTokens.Set([]string{adminTokenString})
+	} else if adminTokenString != "" {
+		logger.Warn("the admin endpoints are only served when --admin-token is set at startup, restart the daemon to enable them")
+	}
+	if r.bot != nil {
+		r.bot.SetRateLimit(algodRateLimit, algodRateBurst)
+	}
+	if r.rateLimit != nil {
+		r.rateLimit.Set(apiRateLimit, apiRateBurst)
+	}
+	if r.disabled != nil {
+		r.disabled.Set(disabledPaths)
+	}
+}
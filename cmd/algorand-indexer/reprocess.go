@@ -0,0 +1,145 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"github.com/algorand/go-algorand/data/bookkeeping"
+	"github.com/algorand/go-algorand/protocol"
+	"github.com/algorand/go-algorand/rpcs"
+	"github.com/spf13/cobra"
+
+	"github.com/algorand/indexer/config"
+	"github.com/algorand/indexer/fetcher"
+	"github.com/algorand/indexer/idb"
+)
+
+var reprocessCmd = &cobra.Command{
+	Use:   "reprocess",
+	Short: "re-import a range of rounds",
+	Long:  "replace the stored blocks and transactions of the rounds from --from-round to --to-round with the blocks of --block-cache-dir or --archive, and rebuild the account state from the genesis on as rederive --repair does. Use it to repair the rows of a few rounds which were corrupted. Every imported round is evaluated again in one database transaction, so it refuses databases of more than --max-replay-rounds rounds. Stop the daemon first, the transaction conflicts with the import.",
+	Run: func(cmd *cobra.Command, args []string) {
+		config.BindFlags(cmd)
+		err := configureLogger()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "failed to configure logger: %v", err)
+			os.Exit(1)
+		}
+		if reprocessGenesisPath == "" {
+			fmt.Fprintf(os.Stderr, "--genesis is required\n")
+			os.Exit(1)
+		}
+		if reprocessToRound < reprocessFromRound {
+			fmt.Fprintf(os.Stderr, "--to-round must not be before --from-round\n")
+			os.Exit(1)
+		}
+		if reprocessCacheDir == "" && len(reprocessArchiveURLs) == 0 {
+			fmt.Fprintf(os.Stderr, "--block-cache-dir or --archive is required\n")
+			os.Exit(1)
+		}
+
+		genesisJSON, err := ioutil.ReadFile(reprocessGenesisPath)
+		maybeFail(err, "failed to read genesis, %v", err)
+		var genesis bookkeeping.Genesis
+		err = protocol.DecodeJSON(genesisJSON, &genesis)
+		maybeFail(err, "failed to decode genesis, %v", err)
+
+		var archives []fetcher.Archive
+		for _, url := range reprocessArchiveURLs {
+			archive, err := fetcher.MakeArchive(url, archiveOptions)
+			maybeFail(err, "archive setup, %v", err)
+			archives = append(archives, archive)
+		}
+		var cache *fetcher.DiskCache
+		if reprocessCacheDir != "" {
+			cache, err = fetcher.MakeDiskCache(reprocessCacheDir, 1)
+			maybeFail(err, "block cache setup, %v", err)
+		}
+
+		db, availableCh := indexerDbFromFlags(idb.IndexerDbOptions{})
+		<-availableCh
+
+		nextRound, err := db.GetNextRoundToAccount()
+		maybeFail(err, "failed to get the next round, %v", err)
+		if reprocessMaxReplayRounds > 0 && nextRound > reprocessMaxReplayRounds {
+			fmt.Fprintf(
+				os.Stderr,
+				"reprocess replays the %d imported rounds from the genesis in one database transaction, more than --max-replay-rounds %d\n",
+				nextRound, reprocessMaxReplayRounds)
+			os.Exit(1)
+		}
+
+		ctx := context.Background()
+		var blocks []*bookkeeping.Block
+		for round := reprocessFromRound; round <= reprocessToRound; round++ {
+			block, err := reprocessBlock(ctx, round, cache, archives)
+			maybeFail(err, "failed to get block %d, %v", round, err)
+			blocks = append(blocks, block)
+		}
+
+		results, err := db.Reprocess(ctx, genesis, blocks)
+		maybeFail(err, "reprocess failed, %v", err)
+
+		for _, result := range results {
+			fmt.Printf(
+				"%s: %d rows, %d stored rows replaced, %d rows added\n",
+				result.Table, result.Rows, result.Missing, result.Extra)
+		}
+		fmt.Printf("reprocessed rounds %d to %d\n", reprocessFromRound, reprocessToRound)
+	},
+}
+
+// reprocessBlock returns the block of `round` from the cache, or from the first
+// archive which has it.
+func reprocessBlock(ctx context.Context, round uint64, cache *fetcher.DiskCache, archives []fetcher.Archive) (*bookkeeping.Block, error) {
+	var blockbytes []byte
+	if cache != nil {
+		blockbytes, _ = cache.Get(round)
+	}
+	for _, archive := range archives {
+		if blockbytes != nil {
+			break
+		}
+		var err error
+		blockbytes, err = archive.BlockRaw(ctx, round)
+		if err != nil {
+			logger.WithError(err).Warnf("failed to get block %d from an archive", round)
+			blockbytes = nil
+		}
+	}
+	if blockbytes == nil {
+		return nil, fmt.Errorf("no cache or archive has the block")
+	}
+
+	var cert rpcs.EncodedBlockCert
+	err := protocol.Decode(blockbytes, &cert)
+	if err != nil {
+		return nil, fmt.Errorf("decode err: %w", err)
+	}
+	if uint64(cert.Block.Round()) != round {
+		return nil, fmt.Errorf("got the block of round %d", cert.Block.Round())
+	}
+	return &cert.Block, nil
+}
+
+var (
+	reprocessGenesisPath string
+	reprocessFromRound   uint64
+	reprocessToRound     uint64
+	reprocessCacheDir    string
+	reprocessArchiveURLs []string
+
+	reprocessMaxReplayRounds uint64
+)
+
+func init() {
+	reprocessCmd.Flags().StringVarP(&reprocessGenesisPath, "genesis", "g", "", "path to the genesis.json of the network")
+	reprocessCmd.Flags().Uint64VarP(&reprocessFromRound, "from-round", "", 0, "first round to re-import")
+	reprocessCmd.Flags().Uint64VarP(&reprocessToRound, "to-round", "", 0, "last round to re-import")
+	reprocessCmd.Flags().StringVarP(&reprocessCacheDir, "block-cache-dir", "", "", "block cache directory of the daemon to read the blocks from")
+	reprocessCmd.Flags().StringSliceVarP(&reprocessArchiveURLs, "archive", "", nil, "URL of a block archive to read the blocks from, as for the daemon, when they are not in --block-cache-dir (can be repeated)")
+	addArchiveCredentialFlags(reprocessCmd)
+	reprocessCmd.Flags().Uint64VarP(&reprocessMaxReplayRounds, "max-replay-rounds", "", 100000, "refuse to reprocess databases with more imported rounds than this, they are all evaluated again in one database transaction (0 for no limit)")
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/algorand/indexer/config"
+	"github.com/algorand/indexer/idb"
+)
+
+var snapshotCmd = &cobra.Command{
+	Use:   "snapshot",
+	Short: "export or import a snapshot of the database",
+	Long:  "export all the tables of the indexer at one imported round to a file, and import it into an empty database. A new deployment can import a snapshot and continue from its round instead of importing from round 0. Snapshots can only be imported by the indexer version which exported them.",
+}
+
+var snapshotExportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "export a snapshot of the database",
+	Long:  "write all the tables, consistent as of the latest imported round, to --output, gzipped if it ends in .gz. The import may continue meanwhile.",
+	Run: func(cmd *cobra.Command, args []string) {
+		config.BindFlags(cmd)
+		err := configureLogger()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "failed to configure logger: %v", err)
+			os.Exit(1)
+		}
+		if snapshotFile == "" {
+			fmt.Fprintf(os.Stderr, "--output is required\n")
+			os.Exit(1)
+		}
+
+		f, err := os.Create(snapshotFile)
+		maybeFail(err, "failed to create output, %v", err)
+		fail := func(err error, errfmt string) {
+			if err != nil {
+				f.Close()
+				os.Remove(snapshotFile)
+				maybeFail(err, errfmt, err)
+			}
+		}
+		var out io.Writer = f
+		var gz *gzip.Writer
+		if strings.HasSuffix(snapshotFile, ".gz") {
+			gz = gzip.NewWriter(f)
+			out = gz
+		}
+
+		db, availableCh := indexerDbFromFlags(idb.IndexerDbOptions{ReadOnly: true})
+		<-availableCh
+
+		nextRound, err := db.ExportSnapshot(context.Background(), out)
+		fail(err, "export failed, %v")
+		if gz != nil {
+			fail(gz.Close(), "failed to close output, %v")
+		}
+		fail(f.Close(), "failed to close output, %v")
+		fmt.Printf("exported the snapshot, the next round to import is %d\n", nextRound)
+	},
+}
+
+var snapshotImportCmd = &cobra.Command{
+	Use:   "import",
+	Short: "import a snapshot into an empty database",
+	Long:  "load a snapshot written by the export command from --input, gunzipped if it ends in .gz, into an empty database. The daemon then continues importing from the round of the snapshot.",
+	Run: func(cmd *cobra.Command, args []string) {
+		config.BindFlags(cmd)
+		err := configureLogger()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "failed to configure logger: %v", err)
+			os.Exit(1)
+		}
+		if snapshotFile == "" {
+			fmt.Fprintf(os.Stderr, "--input is required\n")
+			os.Exit(1)
+		}
+
+		f, err := os.Open(snapshotFile)
+		maybeFail(err, "failed to open input, %v", err)
+		defer f.Close()
+		var in io.Reader = f
+		if strings.HasSuffix(snapshotFile, ".gz") {
+			gz, err := gzip.NewReader(f)
+			maybeFail(err, "failed to open input, %v", err)
+			in = gz
+		}
+
+		db, availableCh := indexerDbFromFlags(idb.IndexerDbOptions{})
+		<-availableCh
+
+		nextRound, err := db.ImportSnapshot(context.Background(), in)
+		maybeFail(err, "import failed, %v", err)
+		fmt.Printf("imported the snapshot, the next round to import is %d\n", nextRound)
+	},
+}
+
+var snapshotFile string
+
+func init() {
+	snapshotExportCmd.Flags().StringVarP(&snapshotFile, "output", "o", "", "file the snapshot is written to, gzipped if it ends in .gz")
+	snapshotImportCmd.Flags().StringVarP(&snapshotFile, "input", "i", "", "file the snapshot is read from, gunzipped if it ends in .gz")
+	snapshotCmd.AddCommand(snapshotExportCmd)
+	snapshotCmd.AddCommand(snapshotImportCmd)
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/algorand/indexer/util/archive"
+)
+
+var verifyArchiveCmd = &cobra.Command{
+	Use:   "verify-archive path",
+	Short: "verify the checksums of an exported archive",
+	Long:  "verify the checksums of an exported snapshot or block archive against its manifest before attempting a restore. path may be a single archive file or an archive directory. With --write-manifest a manifest is created for an existing archive instead.",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		path := args[0]
+
+		if verifyArchiveWriteManifest {
+			m, err := archive.BuildManifest(path, verifyArchiveChunkSize)
+			maybeFail(err, "%s: could not build manifest, %v", path, err)
+			err = archive.WriteManifest(path, m)
+			maybeFail(err, "%s: could not write manifest, %v", path, err)
+			fmt.Printf("wrote manifest for %d files\n", len(m.Files))
+			return
+		}
+
+		problems, err := archive.Verify(path)
+		maybeFail(err, "%s: could not verify archive, %v", path, err)
+
+		if len(problems) > 0 {
+			for _, problem := range problems {
+				fmt.Fprintln(os.Stderr, problem.String())
+			}
+			fmt.Fprintf(os.Stderr, "%s: archive is corrupt, %d problems found\n", path, len(problems))
+			os.Exit(1)
+		}
+		fmt.Printf("%s: archive is intact\n", path)
+	},
+}
+
+var (
+	verifyArchiveWriteManifest bool
+	verifyArchiveChunkSize     int64
+)
+
+func init() {
+	verifyArchiveCmd.Flags().BoolVarP(&verifyArchiveWriteManifest, "write-manifest", "", false, "create a manifest for the archive instead of verifying it")
+	verifyArchiveCmd.Flags().Int64VarP(&verifyArchiveChunkSize, "chunk-size", "", archive.DefaultChunkSize, "number of bytes covered by each checksum when writing a manifest")
+}
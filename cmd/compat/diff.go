@@ -0,0 +1,111 @@
+package main
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+// Kinds of differences between an upstream and a local response.
+const (
+	// diffMissing is a field returned upstream but not locally.
+	diffMissing = "missing"
+	// diffExtra is a field only returned locally. Clients ignore unknown fields,
+	// so it does not break compatibility.
+	diffExtra = "extra"
+	// diffType is a value of a different JSON type.
+	diffType = "type"
+	// diffValue is a different value.
+	diffValue = "value"
+	// diffLength is an array of a different length.
+	diffLength = "length"
+)
+
+// Diff is a difference between the upstream and the local response at a path
+// like "transactions[0].id".
+type Diff struct {
+	Path     string
+	Kind     string
+	Upstream interface{}
+	Local    interface{}
+}
+
+// Breaking returns true if the difference may break a client of the upstream
+// indexer.
+func (d Diff) Breaking() bool {
+	return d.Kind != diffExtra
+}
+
+func (d Diff) String() string {
+	switch d.Kind {
+	case diffMissing:
+		return fmt.Sprintf("%s: missing, upstream %v", d.Path, d.Upstream)
+	case diffExtra:
+		return fmt.Sprintf("%s: extra, local %v", d.Path, d.Local)
+	default:
+		return fmt.Sprintf("%s: %s differs, upstream %v, local %v", d.Path, d.Kind, d.Upstream, d.Local)
+	}
+}
+
+// diffJSON compares decoded JSON values. Only the presence and type of the
+// fields named in `ignoreValues` are compared, e.g. next tokens which are
+// encoded differently.
+func diffJSON(path string, upstream, local interface{}, ignoreValues map[string]bool) []Diff {
+	if reflect.TypeOf(upstream) != reflect.TypeOf(local) {
+		return []Diff{{Path: path, Kind: diffType, Upstream: upstream, Local: local}}
+	}
+
+	switch u := upstream.(type) {
+	case map[string]interface{}:
+		l := local.(map[string]interface{})
+		var diffs []Diff
+		for _, key := range sortedKeys(u, l) {
+			keyPath := key
+			if path != "" {
+				keyPath = path + "." + key
+			}
+			uv, uok := u[key]
+			lv, lok := l[key]
+			switch {
+			case !lok:
+				diffs = append(diffs, Diff{Path: keyPath, Kind: diffMissing, Upstream: uv})
+			case !uok:
+				diffs = append(diffs, Diff{Path: keyPath, Kind: diffExtra, Local: lv})
+			case ignoreValues[key] && reflect.TypeOf(uv) == reflect.TypeOf(lv):
+			default:
+				diffs = append(diffs, diffJSON(keyPath, uv, lv, ignoreValues)...)
+			}
+		}
+		return diffs
+	case []interface{}:
+		l := local.([]interface{})
+		if len(u) != len(l) {
+			return []Diff{{Path: path, Kind: diffLength, Upstream: len(u), Local: len(l)}}
+		}
+		var diffs []Diff
+		for i := range u {
+			diffs = append(diffs, diffJSON(fmt.Sprintf("%s[%d]", path, i), u[i], l[i], ignoreValues)...)
+		}
+		return diffs
+	default:
+		if upstream != local {
+			return []Diff{{Path: path, Kind: diffValue, Upstream: upstream, Local: local}}
+		}
+		return nil
+	}
+}
+
+// sortedKeys returns the keys of both objects in order.
+func sortedKeys(a, b map[string]interface{}) []string {
+	keys := make([]string, 0, len(a)+len(b))
+	for key := range a {
+		keys = append(keys, key)
+	}
+	for key := range b {
+		if _, ok := a[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func decode(t *testing.T, s string) interface{} {
+	var v interface{}
+	require.NoError(t, json.Unmarshal([]byte(s), &v))
+	return v
+}
+
+func TestDiffJSON(t *testing.T) {
+	upstream := decode(t, `{"current-round": 2, "next-token": "abc",
+		"transactions": [{"id": "A", "fee": 1000}, {"id": "B", "note": "x"}]}`)
+	local := decode(t, `{"current-round": 2, "next-token": "def",
+		"transactions": [{"id": "A", "fee": "1000", "truncated": true}, {"id": "B"}]}`)
+
+	diffs := diffJSON("", upstream, local, map[string]bool{"next-token": true})
+	expected := []Diff{
+		{Path: "transactions[0].fee", Kind: diffType, Upstream: 1000.0, Local: "1000"},
+		{Path: "transactions[0].truncated", Kind: diffExtra, Local: true},
+		{Path: "transactions[1].note", Kind: diffMissing, Upstream: "x"},
+	}
+	assert.Equal(t, expected, diffs)
+	assert.True(t, diffs[0].Breaking())
+	assert.False(t, diffs[1].Breaking())
+}
+
+func TestDiffJSONValueAndLength(t *testing.T) {
+	upstream := decode(t, `{"round": 1, "accounts": [1, 2], "next-token": "a"}`)
+	local := decode(t, `{"round": 2, "accounts": [1], "next-token": 3}`)
+
+	diffs := diffJSON("", upstream, local, map[string]bool{"next-token": true})
+	expected := []Diff{
+		{Path: "accounts", Kind: diffLength, Upstream: 2, Local: 1},
+		{Path: "next-token", Kind: diffType, Upstream: "a", Local: 3.0},
+		{Path: "round", Kind: diffValue, Upstream: 1.0, Local: 2.0},
+	}
+	assert.Equal(t, expected, diffs)
+}
+
+func TestDiffJSONEqual(t *testing.T) {
+	s := `{"a": [{"b": null, "c": true}], "d": "e"}`
+	assert.Empty(t, diffJSON("", decode(t, s), decode(t, s), nil))
+}
@@ -0,0 +1,224 @@
+// Check that the API stays compatible with the upstream indexer.
+//
+// The corpus is a directory of requests and the responses of the upstream
+// indexer to them, recorded against the fixture database of util/test. The
+// fixture is imported into an empty database, the API of this indexer is started
+// against it and every response is compared to the recorded one. Fields which
+// are missing or differ are reported and make the check fail, new fields are
+// only reported with -v.
+//
+// To record the corpus, import the fixture with -seed, start the upstream
+// indexer against the database and run with -record <upstream url>.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/algorand/indexer/api"
+	"github.com/algorand/indexer/idb"
+	_ "github.com/algorand/indexer/idb/postgres"
+	"github.com/algorand/indexer/util"
+	"github.com/algorand/indexer/util/test"
+)
+
+var maybeFail = util.MaybeFail
+
+// recording is a request and the response of the upstream indexer to it.
+type recording struct {
+	Request  string          `json:"request"`
+	Status   int             `json:"status"`
+	Response json.RawMessage `json:"response"`
+}
+
+func main() {
+	var pgdb, listen, corpus, record, ignore string
+	var seed, verbose bool
+	flag.StringVar(&pgdb, "pg", "", "postgres connect string of the fixture database, it is imported when the database is empty")
+	flag.StringVar(&listen, "listen", "127.0.0.1:8982", "host:port to serve the API on while comparing")
+	flag.StringVar(&corpus, "corpus", "test/compat", "directory of the recorded responses")
+	flag.BoolVar(&seed, "seed", false, "only import the fixture")
+	flag.StringVar(&record, "record", "", "URL of an upstream indexer to record the corpus from")
+	flag.StringVar(&ignore, "ignore-values", "next-token", "comma separated fields whose values are not compared")
+	flag.BoolVar(&verbose, "v", false, "also report fields which are only returned by this indexer")
+	flag.Parse()
+
+	db, availableCh, err := idb.IndexerDbByName("postgres", pgdb, idb.IndexerDbOptions{}, nil)
+	maybeFail(err, "open postgres")
+	<-availableCh
+
+	_, err = db.GetNextRoundToAccount()
+	if err == idb.ErrorNotInitialized {
+		err = test.ImportFixture(db)
+		maybeFail(err, "import fixture")
+	} else {
+		maybeFail(err, "get next round")
+	}
+	if seed {
+		return
+	}
+
+	if record != "" {
+		requests, err := test.FixtureRequests(db)
+		maybeFail(err, "fixture requests")
+		err = recordCorpus(strings.TrimSuffix(record, "/"), requests, corpus)
+		maybeFail(err, "record corpus")
+		return
+	}
+
+	recordings, err := loadCorpus(corpus)
+	maybeFail(err, "load corpus")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	logger := log.New()
+	logger.SetLevel(log.WarnLevel)
+	go api.Serve(ctx, listen, db, nil, logger, api.ExtraOptions{})
+
+	base := "http://" + listen
+	err = waitForServer(base)
+	maybeFail(err, "start server")
+
+	ignoreValues := make(map[string]bool)
+	for _, field := range strings.Split(ignore, ",") {
+		ignoreValues[strings.TrimSpace(field)] = true
+	}
+
+	breaking := 0
+	for _, name := range sortedNames(recordings) {
+		diffs, err := compare(base, recordings[name], ignoreValues)
+		maybeFail(err, "%s", name)
+		for _, diff := range diffs {
+			if diff.Breaking() {
+				breaking++
+			} else if !verbose {
+				continue
+			}
+			fmt.Printf("%s %s\n", name, diff)
+		}
+	}
+	fmt.Printf("compared %d responses, %d breaking differences\n", len(recordings), breaking)
+	if breaking > 0 {
+		os.Exit(1)
+	}
+}
+
+// compare requests a recording from the server at `base` and returns the
+// differences to the recorded response.
+func compare(base string, rec recording, ignoreValues map[string]bool) ([]Diff, error) {
+	status, body, err := get(base + rec.Request)
+	if err != nil {
+		return nil, err
+	}
+	if status != rec.Status {
+		return []Diff{{Path: "status", Kind: diffValue, Upstream: rec.Status, Local: status}}, nil
+	}
+
+	var upstream, local interface{}
+	err = json.Unmarshal(rec.Response, &upstream)
+	if err != nil {
+		return nil, fmt.Errorf("decode recorded response: %w", err)
+	}
+	err = json.Unmarshal(body, &local)
+	if err != nil {
+		return nil, fmt.Errorf("decode response: %w", err)
+	}
+	return diffJSON("", upstream, local, ignoreValues), nil
+}
+
+// recordCorpus writes the response of the upstream indexer at `base` to each
+// request to <operation id>.json in `dir`.
+func recordCorpus(base string, requests []test.FixtureRequest, dir string) error {
+	err := os.MkdirAll(dir, 0755)
+	if err != nil {
+		return err
+	}
+	for _, req := range requests {
+		status, body, err := get(base + req.Path)
+		if err != nil {
+			return fmt.Errorf("%s: %w", req.Path, err)
+		}
+		data, err := json.MarshalIndent(
+			recording{Request: req.Path, Status: status, Response: body}, "", "  ")
+		if err != nil {
+			return err
+		}
+		err = ioutil.WriteFile(
+			filepath.Join(dir, req.OperationID+".json"), append(data, '\n'), 0644)
+		if err != nil {
+			return err
+		}
+	}
+	fmt.Printf("recorded %d responses to %s\n", len(requests), dir)
+	return nil
+}
+
+// loadCorpus returns the recordings in `dir` by file name.
+func loadCorpus(dir string) (map[string]recording, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no recordings in %s", dir)
+	}
+	recordings := make(map[string]recording, len(files))
+	for _, file := range files {
+		data, err := ioutil.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		var rec recording
+		err = json.Unmarshal(data, &rec)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", file, err)
+		}
+		recordings[strings.TrimSuffix(filepath.Base(file), ".json")] = rec
+	}
+	return recordings, nil
+}
+
+func sortedNames(recordings map[string]recording) []string {
+	names := make([]string, 0, len(recordings))
+	for name := range recordings {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func waitForServer(base string) error {
+	var err error
+	for i := 0; i < 50; i++ {
+		_, _, err = get(base + "/health")
+		if err == nil {
+			return nil
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	return err
+}
+
+func get(url string) (int, []byte, error) {
+	resp, err := http.Get(url)
+	if err != nil {
+		return 0, nil, err
+	}
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return 0, nil, err
+	}
+	return resp.StatusCode, body, nil
+}
@@ -0,0 +1,103 @@
+// Capture examples for the OpenAPI document from a fixture database.
+//
+// The fixture is imported into an empty database, then the API is started
+// against it and the response to one request per endpoint is written to
+// api/examples.json. Run `go generate` in api/ afterwards to embed them.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/algorand/indexer/api"
+	"github.com/algorand/indexer/idb"
+	_ "github.com/algorand/indexer/idb/postgres"
+	"github.com/algorand/indexer/util"
+	"github.com/algorand/indexer/util/test"
+)
+
+var maybeFail = util.MaybeFail
+
+func main() {
+	var pgdb, listen, out string
+	flag.StringVar(&pgdb, "pg", "", "postgres connect string of an empty database, e.g. \"dbname=examples sslmode=disable\"")
+	flag.StringVar(&listen, "listen", "127.0.0.1:8981", "host:port to serve the API on while capturing")
+	flag.StringVar(&out, "out", "api/examples.json", "output file")
+	flag.Parse()
+
+	db, availableCh, err := idb.IndexerDbByName("postgres", pgdb, idb.IndexerDbOptions{}, nil)
+	maybeFail(err, "open postgres")
+	<-availableCh
+
+	next, err := db.GetNextRoundToAccount()
+	if err != idb.ErrorNotInitialized {
+		maybeFail(fmt.Errorf("next round %d, err %v", next, err), "the database is not empty")
+	}
+
+	err = test.ImportFixture(db)
+	maybeFail(err, "import fixture")
+	requests, err := test.FixtureRequests(db)
+	maybeFail(err, "fixture requests")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	logger := log.New()
+	logger.SetLevel(log.WarnLevel)
+	options := api.ExtraOptions{
+		// Next tokens must be the same on every run.
+		PaginationKey: []byte("examples"),
+	}
+	go api.Serve(ctx, listen, db, nil, logger, options)
+
+	base := "http://" + listen
+	err = waitForServer(base)
+	maybeFail(err, "start server")
+
+	examples := make(map[string]api.Example)
+	for _, req := range requests {
+		body, err := get(base + req.Path)
+		maybeFail(err, "%s", req.Path)
+		examples[req.OperationID] = api.Example{Request: req.Path, Response: body}
+	}
+
+	data, err := json.MarshalIndent(examples, "", "  ")
+	maybeFail(err, "encode examples")
+	err = ioutil.WriteFile(out, append(data, '\n'), 0644)
+	maybeFail(err, "write %s", out)
+	fmt.Printf("wrote %d examples to %s\n", len(examples), out)
+}
+
+func waitForServer(base string) error {
+	var err error
+	for i := 0; i < 50; i++ {
+		_, err = get(base + "/health")
+		if err == nil {
+			return nil
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	return err
+}
+
+func get(url string) ([]byte, error) {
+	resp, err := http.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("status %d: %s", resp.StatusCode, body)
+	}
+	return body, nil
+}
@@ -0,0 +1,89 @@
+package fetcher
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"sort"
+
+	"github.com/algorand/go-algorand-sdk/client/v2/common"
+)
+
+// archiveTransport is used by the archives. It is a copy of the default
+// transport made before AlgodOptions change it, so that the client certificate
+// and the certificate authorities of algod are not used for the archives.
+var archiveTransport = http.DefaultTransport.(*http.Transport).Clone()
+
+// AlgodOptions configures the connection to algod, e.g. when algod is behind a
+// proxy which authenticates its clients.
+type AlgodOptions struct {
+	// Headers are added to every request to algod, e.g. the API key of a proxy.
+	Headers map[string]string
+
+	// CertFile and KeyFile are the PEM files of a client certificate which is
+	// presented to algod, empty for none.
+	CertFile string
+	KeyFile  string
+
+	// CAFile is a PEM bundle of the certificate authorities which are trusted
+	// to sign the certificate of algod instead of the system's, empty for the
+	// system's.
+	CAFile string
+}
+
+// headers returns the headers in the form of the go-algorand-sdk, sorted by
+// name.
+func (opts AlgodOptions) headers() []*common.Header {
+	res := make([]*common.Header, 0, len(opts.Headers))
+	for key, value := range opts.Headers {
+		res = append(res, &common.Header{Key: key, Value: value})
+	}
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].Key < res[j].Key
+	})
+	return res
+}
+
+// tlsConfig returns the TLS configuration of the options, nil if they don't
+// change the default.
+func (opts AlgodOptions) tlsConfig() (*tls.Config, error) {
+	if opts.CertFile == "" && opts.KeyFile == "" && opts.CAFile == "" {
+		return nil, nil
+	}
+
+	config := &tls.Config{}
+	if opts.CertFile != "" || opts.KeyFile != "" {
+		cert, err := tls.LoadX509KeyPair(opts.CertFile, opts.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("tlsConfig() client certificate err: %w", err)
+		}
+		config.Certificates = []tls.Certificate{cert}
+	}
+	if opts.CAFile != "" {
+		pem, err := ioutil.ReadFile(opts.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("tlsConfig() err: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("tlsConfig() %s contains no certificates", opts.CAFile)
+		}
+		config.RootCAs = pool
+	}
+	return config, nil
+}
+
+// configureTransport applies the TLS options to the default HTTP transport,
+// which the algod client of go-algorand-sdk sends its requests with.
+func (opts AlgodOptions) configureTransport() error {
+	config, err := opts.tlsConfig()
+	if err != nil {
+		return fmt.Errorf("configureTransport() err: %w", err)
+	}
+	if config != nil {
+		http.DefaultTransport.(*http.Transport).TLSClientConfig = config
+	}
+	return nil
+}
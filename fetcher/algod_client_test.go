@@ -0,0 +1,58 @@
+package fetcher
+
+import (
+	"encoding/pem"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/algorand/go-algorand-sdk/client/v2/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAlgodOptionsHeaders(t *testing.T) {
+	opts := AlgodOptions{Headers: map[string]string{"X-Key": "key", "Authorization": "Bearer x"}}
+	expected := []*common.Header{
+		{Key: "Authorization", Value: "Bearer x"},
+		{Key: "X-Key", Value: "key"},
+	}
+	assert.Equal(t, expected, opts.headers())
+}
+
+func TestAlgodOptionsTLSConfig(t *testing.T) {
+	config, err := AlgodOptions{}.tlsConfig()
+	require.NoError(t, err)
+	assert.Nil(t, config)
+
+	_, err = AlgodOptions{CertFile: "missing.pem", KeyFile: "missing.key"}.tlsConfig()
+	assert.Error(t, err)
+
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	f, err := ioutil.TempFile("", "ca*.pem")
+	require.NoError(t, err)
+	defer os.Remove(f.Name())
+	err = pem.Encode(f, &pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+
+	config, err = AlgodOptions{CAFile: f.Name()}.tlsConfig()
+	require.NoError(t, err)
+
+	// The server's certificate is trusted with the bundle.
+	client := &http.Client{Transport: &http.Transport{TLSClientConfig: config}}
+	resp, err := client.Get(server.URL)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	// A file without certificates is an error.
+	_, err = AlgodOptions{CAFile: os.DevNull}.tlsConfig()
+	assert.Error(t, err)
+}
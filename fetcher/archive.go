@@ -0,0 +1,133 @@
+package fetcher
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/algorand/go-algorand/rpcs"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// Rounds closer than this to the latest round of algod are always fetched from
+	// algod, archives may not have them yet.
+	archiveTipLag = 1000
+
+	// How long an archive is not used after it failed to serve a block.
+	archiveRetryInterval = time.Minute
+)
+
+// Archive serves historical blocks, for example an archival relay or a block
+// archive CDN. Catching up from an archive reduces the load on algod.
+type Archive interface {
+	// BlockRaw returns the msgpack encoded block certificate of `round`.
+	BlockRaw(ctx context.Context, round uint64) ([]byte, error)
+
+	// String describes the archive in logs.
+	String() string
+}
+
+type httpArchive struct {
+	template string
+	client   *http.Client
+}
+
+// MakeHTTPArchive returns an archive which downloads blocks from the URL
+// `template`, in which "{round}" is replaced by the round in decimal and
+// "{round36}" in base 36. The URL of a block on an archival relay is for example
+// "https://relay:4160/v1/mainnet-v1.0/block/{round36}".
+func MakeHTTPArchive(template string) (Archive, error) {
+	if !strings.Contains(template, "{round}") && !strings.Contains(template, "{round36}") {
+		return nil, fmt.Errorf(
+			"MakeHTTPArchive() %s contains neither {round} nor {round36}", template)
+	}
+	archive := &httpArchive{
+		template: template,
+		client:   &http.Client{Timeout: 30 * time.Second, Transport: archiveTransport},
+	}
+	return archive, nil
+}
+
+func (a *httpArchive) url(round uint64) string {
+	replacer := strings.NewReplacer(
+		"{round}", strconv.FormatUint(round, 10),
+		"{round36}", strconv.FormatUint(round, 36))
+	return replacer.Replace(a.template)
+}
+
+// BlockRaw is part of the Archive interface.
+func (a *httpArchive) BlockRaw(ctx context.Context, round uint64) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, a.url(round), nil)
+	if err != nil {
+		return nil, fmt.Errorf("BlockRaw() err: %w", err)
+	}
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("BlockRaw() err: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("BlockRaw() round %d status %s", round, resp.Status)
+	}
+	blockbytes, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("BlockRaw() read body err: %w", err)
+	}
+	return blockbytes, nil
+}
+
+// String is part of the Archive interface.
+func (a *httpArchive) String() string {
+	return a.template
+}
+
+// archiveState is an archive and when it may be used again after a failure.
+type archiveState struct {
+	archive     Archive
+	failedUntil time.Time
+}
+
+// getArchivedBlock returns the block of `round` from the first archive which
+// serves it and its encoded length, or nil when the round is close to the tip of
+// algod or no archive has it. Archives which fail are skipped for
+// archiveRetryInterval.
+func (bot *fetcherImpl) getArchivedBlock(round uint64) (*rpcs.EncodedBlockCert, int) {
+	latest, ok := bot.LatestRound()
+	if len(bot.archives) == 0 || !ok || round+archiveTipLag > latest {
+		return nil, 0
+	}
+
+	for _, state := range bot.archives {
+		if time.Now().Before(state.failedUntil) {
+			continue
+		}
+		fetch := startFetch(archiveSourceName, round)
+		blockbytes, err := state.archive.BlockRaw(context.Background(), round)
+		fetch.end(err)
+		if err == nil {
+			var block *rpcs.EncodedBlockCert
+			block, err = bot.decodeBlock(blockbytes)
+			if err == nil {
+				fetchedBlocks.WithLabelValues(archiveSourceName).Inc()
+				bot.cacheBlock(round, blockbytes)
+				return block, len(blockbytes)
+			}
+		}
+		bot.log.WithError(err).WithFields(log.Fields{
+			"archive": state.archive.String(),
+			"round":   round,
+		}).Warn("archive failed to serve the block, falling back to algod")
+		state.failedUntil = time.Now().Add(archiveRetryInterval)
+	}
+	return nil, 0
+}
+
+// AddArchive is part of the Fetcher interface
+func (bot *fetcherImpl) AddArchive(archive Archive) {
+	bot.archives = append(bot.archives, &archiveState{archive: archive})
+}
@@ -0,0 +1,38 @@
+package fetcher
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMakeHTTPArchiveNoRound(t *testing.T) {
+	_, err := MakeHTTPArchive("https://archive.example.com/blocks")
+	assert.Error(t, err)
+}
+
+func TestHTTPArchiveBlockRaw(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/testnet/block/rs":
+			w.Write([]byte("block 1000"))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	archive, err := MakeHTTPArchive(server.URL + "/v1/testnet/block/{round36}")
+	require.NoError(t, err)
+
+	blockbytes, err := archive.BlockRaw(context.Background(), 1000)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("block 1000"), blockbytes)
+
+	_, err = archive.BlockRaw(context.Background(), 1001)
+	assert.Error(t, err)
+}
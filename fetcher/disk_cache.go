@@ -0,0 +1,143 @@
+package fetcher
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/algorand/go-algorand/rpcs"
+)
+
+const diskCacheSuffix = ".block"
+
+// DiskCache keeps the raw blocks of the most recently fetched rounds in a
+// directory, so that they are not downloaded again when the import restarts,
+// e.g. after a crash.
+type DiskCache struct {
+	dir string
+	// size is the number of rounds kept.
+	size uint64
+}
+
+// MakeDiskCache creates a cache of the last `size` rounds in `dir`, which is
+// created if it does not exist. Blocks of older rounds which were left in the
+// directory are deleted when the next block is added.
+func MakeDiskCache(dir string, size uint64) (*DiskCache, error) {
+	if size == 0 {
+		return nil, fmt.Errorf("MakeDiskCache() size must be positive")
+	}
+	err := os.MkdirAll(dir, 0755)
+	if err != nil {
+		return nil, fmt.Errorf("MakeDiskCache() err: %w", err)
+	}
+	return &DiskCache{dir: dir, size: size}, nil
+}
+
+func (c *DiskCache) path(round uint64) string {
+	return filepath.Join(c.dir, strconv.FormatUint(round, 10)+diskCacheSuffix)
+}
+
+// Get returns the raw block of `round`, or false if it is not cached.
+func (c *DiskCache) Get(round uint64) ([]byte, bool) {
+	blockbytes, err := ioutil.ReadFile(c.path(round))
+	if err != nil {
+		return nil, false
+	}
+	return blockbytes, true
+}
+
+// Remove deletes the block of `round`, e.g. because it is corrupt.
+func (c *DiskCache) Remove(round uint64) {
+	os.Remove(c.path(round))
+}
+
+// Put adds the raw block of `round` and deletes the blocks which are more than
+// `size` rounds older.
+func (c *DiskCache) Put(round uint64, blockbytes []byte) error {
+	// Write to a temporary file first so that a crash does not leave a
+	// truncated block.
+	f, err := ioutil.TempFile(c.dir, "tmp")
+	if err != nil {
+		return fmt.Errorf("Put() err: %w", err)
+	}
+	_, err = f.Write(blockbytes)
+	if err == nil {
+		err = f.Close()
+	} else {
+		f.Close()
+	}
+	if err == nil {
+		err = os.Rename(f.Name(), c.path(round))
+	}
+	if err != nil {
+		os.Remove(f.Name())
+		return fmt.Errorf("Put() err: %w", err)
+	}
+
+	if round >= c.size {
+		c.prune(round - c.size)
+	}
+	return nil
+}
+
+// prune deletes the blocks of `round` and earlier rounds. Usually only the
+// block of `round` is left, the directory is scanned every `size` rounds.
+func (c *DiskCache) prune(round uint64) {
+	c.Remove(round)
+	if round%c.size != 0 {
+		return
+	}
+
+	infos, err := ioutil.ReadDir(c.dir)
+	if err != nil {
+		return
+	}
+	for _, info := range infos {
+		name := info.Name()
+		if !strings.HasSuffix(name, diskCacheSuffix) {
+			continue
+		}
+		r, err := strconv.ParseUint(strings.TrimSuffix(name, diskCacheSuffix), 10, 64)
+		if err == nil && r < round {
+			os.Remove(filepath.Join(c.dir, name))
+		}
+	}
+}
+
+// getCachedBlock returns the block of `round` from the disk cache and its
+// encoded length, or nil if it is not cached. A block which does not decode is
+// removed.
+func (bot *fetcherImpl) getCachedBlock(round uint64) (*rpcs.EncodedBlockCert, int) {
+	if bot.diskCache == nil {
+		return nil, 0
+	}
+	fetch := startFetch(cacheSourceName, round)
+	blockbytes, ok := bot.diskCache.Get(round)
+	fetch.end(nil)
+	if !ok {
+		return nil, 0
+	}
+	block, err := bot.decodeBlock(blockbytes)
+	if err != nil {
+		bot.log.WithError(err).WithField("round", round).Warn("removing cached block")
+		bot.diskCache.Remove(round)
+		return nil, 0
+	}
+	fetchedBlocks.WithLabelValues(cacheSourceName).Inc()
+	return block, len(blockbytes)
+}
+
+// cacheBlock adds a fetched block to the disk cache. Failures are logged, the
+// import does not depend on the cache.
+func (bot *fetcherImpl) cacheBlock(round uint64, blockbytes []byte) {
+	if bot.diskCache == nil {
+		return
+	}
+	err := bot.diskCache.Put(round, blockbytes)
+	if err != nil {
+		bot.log.WithError(err).WithField("round", round).Warn("caching block failed")
+	}
+}
@@ -0,0 +1,61 @@
+package fetcher
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiskCache(t *testing.T) {
+	dir, err := ioutil.TempDir("", "blocks")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	cache, err := MakeDiskCache(dir, 4)
+	require.NoError(t, err)
+
+	_, ok := cache.Get(1)
+	assert.False(t, ok)
+
+	for round := uint64(1); round <= 9; round++ {
+		require.NoError(t, cache.Put(round, []byte{byte(round)}))
+	}
+
+	for round := uint64(1); round <= 5; round++ {
+		_, ok = cache.Get(round)
+		assert.False(t, ok, round)
+	}
+	for round := uint64(6); round <= 9; round++ {
+		blockbytes, ok := cache.Get(round)
+		require.True(t, ok, round)
+		assert.Equal(t, []byte{byte(round)}, blockbytes)
+	}
+
+	cache.Remove(9)
+	_, ok = cache.Get(9)
+	assert.False(t, ok)
+
+	files, err := ioutil.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Len(t, files, 3)
+}
+
+func TestDiskCachePrunesOldBlocks(t *testing.T) {
+	dir, err := ioutil.TempDir("", "blocks")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	cache, err := MakeDiskCache(dir, 4)
+	require.NoError(t, err)
+	require.NoError(t, cache.Put(1, []byte{1}))
+
+	// The import restarted at a later round.
+	for round := uint64(100); round <= 104; round++ {
+		require.NoError(t, cache.Put(round, []byte{byte(round)}))
+	}
+	_, ok := cache.Get(1)
+	assert.False(t, ok)
+}
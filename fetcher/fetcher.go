@@ -8,6 +8,7 @@ import (
 	"path/filepath"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/algorand/go-algorand-sdk/client/v2/algod"
@@ -25,11 +26,29 @@ type Fetcher interface {
 	Run()
 
 	AddBlockHandler(handler BlockHandler)
+	// AddArchive adds an archive which serves old blocks during catchup instead
+	// of algod. Archives are tried in the order they are added.
+	AddArchive(archive Archive)
 	SetContext(ctx context.Context)
 	SetNextRound(nextRound uint64)
+	// SetRetryPolicy configures how failures to fetch a block are retried.
+	SetRetryPolicy(policy RetryPolicy)
+	// SetRateLimit limits the requests to algod to `requestsPerSecond`, with
+	// bursts of up to `burst` requests. 0 requests per second is no limit. It
+	// may be called while Run fetches blocks.
+	SetRateLimit(requestsPerSecond float64, burst int)
+	// SetFollowOptions configures how the fetcher waits for new blocks.
+	SetFollowOptions(opts FollowOptions)
+	// SetDiskCache makes the fetcher keep the fetched blocks in `cache` and
+	// read them from it before fetching them.
+	SetDiskCache(cache *DiskCache)
 
 	// Error returns any error fetcher is currently experiencing.
 	Error() string
+
+	// LatestRound returns the latest round reported by algod. The second return
+	// value is false until algod has been asked.
+	LatestRound() (uint64, bool)
 }
 
 // BlockHandler is the handler fetcher uses to process a block.
@@ -37,12 +56,24 @@ type BlockHandler interface {
 	HandleBlock(block *rpcs.EncodedBlockCert)
 }
 
+// SizedBlockHandler is a BlockHandler which also receives the length of the
+// encoded block as it was fetched, e.g. to bound the memory of buffered blocks
+// without encoding them again. The fetcher calls HandleSizedBlock() instead of
+// HandleBlock().
+type SizedBlockHandler interface {
+	BlockHandler
+	HandleSizedBlock(block *rpcs.EncodedBlockCert, size int)
+}
+
 type fetcherImpl struct {
 	algorandData string
 	aclient      *algod.Client
 	algodLastmod time.Time // newest mod time of algod.net algod.token
+	algodOptions AlgodOptions
 
 	blockHandlers []BlockHandler
+	archives      []*archiveState
+	diskCache     *DiskCache
 
 	nextRound uint64
 
@@ -50,11 +81,25 @@ type fetcherImpl struct {
 	done bool
 
 	failingSince time.Time
+	// failures counts the consecutive times Run() had to reconnect to algod.
+	failures int
+
+	retryPolicy RetryPolicy
+	limiter     atomic.Value // *rateLimiter, replaced by SetRateLimit while Run fetches
+
+	followOptions FollowOptions
+	// polling is set when TipModeAuto fell back to polling.
+	polling bool
+	// quickReturns counts the consecutive long polls which returned early
+	// without the block.
+	quickReturns int
 
 	log *log.Logger
 
 	err   error // protected by `errmu`
 	errmu sync.Mutex
+
+	latestRound uint64 // accessed atomically
 }
 
 func (bot *fetcherImpl) Error() string {
@@ -67,6 +112,17 @@ func (bot *fetcherImpl) Error() string {
 	return ""
 }
 
+// LatestRound is part of the Fetcher interface
+func (bot *fetcherImpl) LatestRound() (uint64, bool) {
+	round := atomic.LoadUint64(&bot.latestRound)
+	return round, round > 0
+}
+
+func (bot *fetcherImpl) setLatestRound(round uint64) {
+	atomic.StoreUint64(&bot.latestRound, round)
+	algodRound.WithLabelValues().Set(float64(round))
+}
+
 // Algod is part of the Fetcher interface
 func (bot *fetcherImpl) Algod() *algod.Client {
 	return bot.aclient
@@ -88,6 +144,47 @@ func (bot *fetcherImpl) isDone() bool {
 	}
 }
 
+// throttle waits until the rate limit allows the next request to algod.
+func (bot *fetcherImpl) throttle() {
+	limiter, _ := bot.limiter.Load().(*rateLimiter)
+	if wait := limiter.reserve(); wait > 0 {
+		bot.sleep(wait)
+	}
+}
+
+// sleep waits for `d` or until the context is done.
+func (bot *fetcherImpl) sleep(d time.Duration) {
+	if bot.ctx == nil {
+		time.Sleep(d)
+		return
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+	case <-bot.ctx.Done():
+	}
+}
+
+// blockNotYetAvailable returns true if algod reports that its latest round is
+// before the next round, i.e. fetching the block failed because it does not
+// exist yet rather than because of a transient error.
+func (bot *fetcherImpl) blockNotYetAvailable(aclient *algod.Client) bool {
+	bot.throttle()
+	status, err := aclient.Status().Do(context.Background())
+	if err != nil {
+		return false
+	}
+	bot.setLatestRound(status.LastRound)
+	return status.LastRound < bot.nextRound
+}
+
+// succeeded resets the failure state after a block was handled.
+func (bot *fetcherImpl) succeeded() {
+	bot.failingSince = time.Time{}
+	bot.failures = 0
+}
+
 func (bot *fetcherImpl) setError(err error) {
 	bot.errmu.Lock()
 	bot.err = err
@@ -99,26 +196,61 @@ func (bot *fetcherImpl) catchupLoop() {
 	var err error
 	var blockbytes []byte
 	aclient := bot.Algod()
+	bot.throttle()
+	status, err := aclient.Status().Do(context.Background())
+	if err == nil {
+		bot.setLatestRound(status.LastRound)
+	} else {
+		bot.log.WithError(err).Warn("catchup could not get algod status")
+	}
+	retries := 0
 	for {
 		if bot.isDone() {
 			return
 		}
 
-		blockbytes, err = aclient.BlockRaw(bot.nextRound).Do(context.Background())
-		if err != nil {
-			bot.setError(err)
-			bot.log.WithError(err).Errorf("catchup block %d", bot.nextRound)
-			return
+		block, size := bot.getCachedBlock(bot.nextRound)
+		if block == nil {
+			block, size = bot.getArchivedBlock(bot.nextRound)
 		}
+		if block == nil {
+			bot.throttle()
+			fetch := startFetch(algodSourceName, bot.nextRound)
+			blockbytes, err = aclient.BlockRaw(bot.nextRound).Do(context.Background())
+			fetch.end(err)
+			if err != nil {
+				if bot.blockNotYetAvailable(aclient) {
+					// Caught up, followLoop() waits for the next block.
+					return
+				}
+				retries++
+				if retries > bot.retryPolicy.Retries {
+					bot.setError(err)
+					bot.log.WithError(err).WithField("round", bot.nextRound).Error("catchup block failed")
+					return
+				}
+				bot.log.WithError(err).WithFields(log.Fields{
+					"round":   bot.nextRound,
+					"retries": retries,
+				}).Warn("catchup block failed, retrying")
+				bot.sleep(bot.retryPolicy.Backoff(retries))
+				continue
+			}
+			retries = 0
+			fetchedBlocks.WithLabelValues(algodSourceName).Inc()
 
-		err = bot.handleBlockBytes(blockbytes)
-		if err != nil {
-			bot.setError(err)
-			bot.log.WithError(err).Errorf("err handling catchup block %d", bot.nextRound)
-			return
+			block, err = bot.decodeBlock(blockbytes)
+			if err != nil {
+				bot.setError(err)
+				bot.log.WithError(err).WithField("round", bot.nextRound).Error("handling catchup block failed")
+				return
+			}
+			bot.cacheBlock(bot.nextRound, blockbytes)
+			size = len(blockbytes)
 		}
+		bot.handleBlock(block, size)
 		bot.nextRound++
-		bot.failingSince = time.Time{}
+		bot.succeeded()
 	}
 }
 
@@ -126,37 +258,52 @@ func (bot *fetcherImpl) catchupLoop() {
 func (bot *fetcherImpl) followLoop() {
 	var err error
 	var blockbytes []byte
+	var available bool
 	aclient := bot.Algod()
+	retries := 0
 	for {
-		for retries := 0; retries < 3; retries++ {
-			if bot.isDone() {
-				return
-			}
-			_, err = aclient.StatusAfterBlock(bot.nextRound).Do(context.Background())
-			if err != nil {
-				bot.log.WithError(err).Errorf("r=%d error getting status %d", retries, bot.nextRound)
+		if bot.isDone() {
+			return
+		}
+		bot.throttle()
+		available, err = bot.waitForBlock(aclient)
+		if err == nil {
+			if !available {
+				// Not a failure, wait again.
 				continue
 			}
+			bot.throttle()
+			fetch := startFetch(algodSourceName, bot.nextRound)
 			blockbytes, err = aclient.BlockRaw(bot.nextRound).Do(context.Background())
-			if err == nil {
-				break
-			}
-			bot.log.WithError(err).Errorf("r=%d err getting block %d", retries, bot.nextRound)
+			fetch.end(err)
 		}
 		if err != nil {
-			bot.setError(err)
-			return
+			retries++
+			if retries > bot.retryPolicy.Retries {
+				bot.setError(err)
+				bot.log.WithError(err).WithField("round", bot.nextRound).Error("getting block failed")
+				return
+			}
+			bot.log.WithError(err).WithFields(log.Fields{
+				"round":   bot.nextRound,
+				"retries": retries,
+			}).Warn("getting block failed, retrying")
+			bot.sleep(bot.retryPolicy.Backoff(retries))
+			continue
 		}
+		retries = 0
+		fetchedBlocks.WithLabelValues(algodSourceName).Inc()
+
 		err = bot.handleBlockBytes(blockbytes)
 		if err != nil {
 			bot.setError(err)
-			bot.log.WithError(err).Errorf("err handling follow block %d", bot.nextRound)
+			bot.log.WithError(err).WithField("round", bot.nextRound).Error("handling follow block failed")
 			break
 		}
 		// If we successfully handle the block, clear out any transient error which may have occurred.
 		bot.setError(nil)
 		bot.nextRound++
-		bot.failingSince = time.Time{}
+		bot.succeeded()
 	}
 }
 
@@ -175,11 +322,13 @@ func (bot *fetcherImpl) Run() {
 		if bot.failingSince.IsZero() {
 			bot.failingSince = time.Now()
 		} else {
-			now := time.Now()
-			dt := now.Sub(bot.failingSince)
-			bot.log.Warnf("failing to fetch from algod for %s, (since %s, now %s)", dt.String(), bot.failingSince.String(), now.String())
+			bot.log.WithFields(log.Fields{
+				"since":    bot.failingSince,
+				"duration": time.Since(bot.failingSince).Seconds(),
+			}).Warn("failing to fetch from algod")
 		}
-		time.Sleep(5 * time.Second)
+		bot.failures++
+		bot.sleep(bot.retryPolicy.Backoff(bot.failures))
 		err := bot.reclient()
 		if err != nil {
 			bot.setError(err)
@@ -195,27 +344,67 @@ func (bot *fetcherImpl) SetContext(ctx context.Context) {
 	bot.ctx = ctx
 }
 
+// SetRetryPolicy is part of the Fetcher interface
+func (bot *fetcherImpl) SetRetryPolicy(policy RetryPolicy) {
+	bot.retryPolicy = policy
+}
+
+// SetRateLimit is part of the Fetcher interface
+func (bot *fetcherImpl) SetRateLimit(requestsPerSecond float64, burst int) {
+	bot.limiter.Store(makeRateLimiter(requestsPerSecond, burst))
+}
+
+// SetFollowOptions is part of the Fetcher interface
+func (bot *fetcherImpl) SetFollowOptions(opts FollowOptions) {
+	bot.followOptions = opts
+	bot.polling = false
+	bot.quickReturns = 0
+}
+
+// SetDiskCache is part of the Fetcher interface
+func (bot *fetcherImpl) SetDiskCache(cache *DiskCache) {
+	bot.diskCache = cache
+}
+
 // SetNextRound is part of the Fetcher interface
 func (bot *fetcherImpl) SetNextRound(nextRound uint64) {
 	bot.nextRound = nextRound
 }
 
 func (bot *fetcherImpl) handleBlockBytes(blockbytes []byte) error {
+	block, err := bot.decodeBlock(blockbytes)
+	if err != nil {
+		return err
+	}
+	bot.cacheBlock(bot.nextRound, blockbytes)
+	bot.handleBlock(block, len(blockbytes))
+	return nil
+}
+
+// decodeBlock decodes a block and checks that it is the next round.
+func (bot *fetcherImpl) decodeBlock(blockbytes []byte) (*rpcs.EncodedBlockCert, error) {
 	var block rpcs.EncodedBlockCert
 	err := protocol.Decode(blockbytes, &block)
 	if err != nil {
-		return fmt.Errorf("unable to decode block: %v", err)
+		return nil, fmt.Errorf("unable to decode block: %v", err)
 	}
 
 	if block.Block.Round() != basics.Round(bot.nextRound) {
-		return fmt.Errorf("expected round %d but got %d", bot.nextRound, block.Block.Round())
+		return nil, fmt.Errorf("expected round %d but got %d", bot.nextRound, block.Block.Round())
 	}
 
+	return &block, nil
+}
+
+// handleBlock passes `block`, whose encoded length is `size`, to the handlers.
+func (bot *fetcherImpl) handleBlock(block *rpcs.EncodedBlockCert, size int) {
 	for _, handler := range bot.blockHandlers {
-		handler.HandleBlock(&block)
+		if sized, ok := handler.(SizedBlockHandler); ok {
+			sized.HandleSizedBlock(block, size)
+		} else {
+			handler.HandleBlock(block)
+		}
 	}
-
-	return nil
 }
 
 // AddBlockHandler is part of the Fetcher interface
@@ -235,8 +424,18 @@ func (bot *fetcherImpl) AddBlockHandler(handler BlockHandler) {
 }
 
 // ForDataDir initializes Fetcher to read data from the data directory.
-func ForDataDir(path string, log *log.Logger) (bot Fetcher, err error) {
-	boti := &fetcherImpl{algorandData: path, log: log}
+func ForDataDir(path string, opts AlgodOptions, log *log.Logger) (bot Fetcher, err error) {
+	err = opts.configureTransport()
+	if err != nil {
+		return
+	}
+	boti := &fetcherImpl{
+		algorandData:  path,
+		algodOptions:  opts,
+		log:           log,
+		retryPolicy:   DefaultRetryPolicy(),
+		followOptions: DefaultFollowOptions(),
+	}
 	err = boti.reclient()
 	if err == nil {
 		bot = boti
@@ -245,16 +444,25 @@ func ForDataDir(path string, log *log.Logger) (bot Fetcher, err error) {
 }
 
 // ForNetAndToken initializes Fetch to read data from an algod REST endpoint.
-func ForNetAndToken(netaddr, token string, log *log.Logger) (bot Fetcher, err error) {
+func ForNetAndToken(netaddr, token string, opts AlgodOptions, log *log.Logger) (bot Fetcher, err error) {
+	err = opts.configureTransport()
+	if err != nil {
+		return
+	}
 	var client *algod.Client
 	if !strings.HasPrefix(netaddr, "http") {
 		netaddr = "http://" + netaddr
 	}
-	client, err = algod.MakeClient(netaddr, token)
+	client, err = algod.MakeClientWithHeaders(netaddr, token, opts.headers())
 	if err != nil {
 		return
 	}
-	bot = &fetcherImpl{aclient: client, log: log}
+	bot = &fetcherImpl{
+		aclient:       client,
+		log:           log,
+		retryPolicy:   DefaultRetryPolicy(),
+		followOptions: DefaultFollowOptions(),
+	}
 	return
 }
 
@@ -266,7 +474,7 @@ func (bot *fetcherImpl) reclient() (err error) {
 	// algod.token files and make a new API client object.
 	var nclient *algod.Client
 	var lastmod time.Time
-	nclient, lastmod, err = algodClientForDataDir(bot.algorandData)
+	nclient, lastmod, err = algodClientForDataDir(bot.algorandData, bot.algodOptions)
 	if err == nil {
 		bot.aclient = nclient
 		bot.algodLastmod = lastmod
@@ -298,7 +506,7 @@ func algodStat(netpath, tokenpath string) (lastmod time.Time, err error) {
 	return
 }
 
-func algodClientForDataDir(datadir string) (client *algod.Client, lastmod time.Time, err error) {
+func algodClientForDataDir(datadir string, opts AlgodOptions) (client *algod.Client, lastmod time.Time, err error) {
 	// TODO: move this to go-algorand-sdk
 	netpath, tokenpath := algodPaths(datadir)
 	var netaddrbytes []byte
@@ -316,7 +524,8 @@ func algodClientForDataDir(datadir string) (client *algod.Client, lastmod time.T
 		err = fmt.Errorf("%s: %v", tokenpath, err)
 		return
 	}
-	client, err = algod.MakeClient(netaddr, strings.TrimSpace(string(token)))
+	client, err = algod.MakeClientWithHeaders(
+		netaddr, strings.TrimSpace(string(token)), opts.headers())
 	if err == nil {
 		lastmod, err = algodStat(netpath, tokenpath)
 	}
@@ -0,0 +1,46 @@
+package fetcher
+
+import (
+	"testing"
+
+	"github.com/algorand/go-algorand/protocol"
+	"github.com/algorand/go-algorand/rpcs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/algorand/indexer/util/test"
+)
+
+type blockHandler struct {
+	blocks int
+}
+
+func (h *blockHandler) HandleBlock(block *rpcs.EncodedBlockCert) {
+	h.blocks++
+}
+
+type sizedBlockHandler struct {
+	blockHandler
+	sizes []int
+}
+
+func (h *sizedBlockHandler) HandleSizedBlock(block *rpcs.EncodedBlockCert, size int) {
+	h.sizes = append(h.sizes, size)
+}
+
+func TestHandleBlockBytesSize(t *testing.T) {
+	blockbytes := protocol.Encode(&rpcs.EncodedBlockCert{Block: test.MakeGenesisBlock()})
+
+	plain := &blockHandler{}
+	sized := &sizedBlockHandler{}
+	bot := &fetcherImpl{}
+	bot.AddBlockHandler(plain)
+	bot.AddBlockHandler(sized)
+
+	err := bot.handleBlockBytes(blockbytes)
+	require.NoError(t, err)
+
+	assert.Equal(t, 1, plain.blocks)
+	assert.Equal(t, []int{len(blockbytes)}, sized.sizes)
+	assert.Equal(t, 0, sized.blocks)
+}
@@ -0,0 +1,133 @@
+package fetcher
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/algorand/go-algorand-sdk/client/v2/algod"
+)
+
+// TipMode is how the fetcher waits for new blocks at the tip of the chain.
+type TipMode string
+
+const (
+	// TipModeAuto long polls algod and falls back to polling when algod, or a
+	// proxy in front of it, does not support long polls.
+	TipModeAuto TipMode = "auto"
+	// TipModeWait only long polls algod.
+	TipModeWait TipMode = "wait"
+	// TipModePoll asks algod for its status every poll interval.
+	TipModePoll TipMode = "poll"
+)
+
+// quickReturnsBeforePolling is the number of consecutive long polls which
+// return early without the block before TipModeAuto falls back to polling.
+const quickReturnsBeforePolling = 3
+
+// ParseTipMode returns the TipMode named `mode`.
+func ParseTipMode(mode string) (TipMode, error) {
+	switch TipMode(mode) {
+	case TipModeAuto, TipModeWait, TipModePoll:
+		return TipMode(mode), nil
+	default:
+		return "", fmt.Errorf("ParseTipMode() unknown mode %q, expected auto, wait or poll", mode)
+	}
+}
+
+// FollowOptions configures how the fetcher waits for new blocks.
+type FollowOptions struct {
+	Mode TipMode
+
+	// WaitTimeout ends a long poll which algod did not answer by then, 0 for
+	// algod's own timeout.
+	WaitTimeout time.Duration
+
+	// PollInterval is the time between two status requests when polling. Long
+	// polls which return earlier without the block wait for it too, so that a
+	// misbehaving endpoint does not cause a hot loop.
+	PollInterval time.Duration
+}
+
+// DefaultFollowOptions returns the options used unless SetFollowOptions() is
+// called.
+func DefaultFollowOptions() FollowOptions {
+	return FollowOptions{
+		Mode:         TipModeAuto,
+		PollInterval: time.Second,
+	}
+}
+
+// waitForBlock waits for algod to have the next round. It returns false when
+// algod does not have it yet, the caller then waits again.
+func (bot *fetcherImpl) waitForBlock(aclient *algod.Client) (bool, error) {
+	if bot.polling || bot.followOptions.Mode == TipModePoll {
+		return bot.pollForBlock(aclient)
+	}
+
+	ctx := context.Background()
+	if bot.followOptions.WaitTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, bot.followOptions.WaitTimeout)
+		defer cancel()
+	}
+
+	start := time.Now()
+	status, err := aclient.StatusAfterBlock(bot.nextRound).Do(ctx)
+	if err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			// The long poll timed out, which is not a failure.
+			return false, nil
+		}
+		if bot.followOptions.Mode == TipModeAuto && bot.statusAvailable(aclient) {
+			// algod answers status requests, so it is the long poll which is
+			// not supported.
+			bot.log.WithError(err).Warn("waiting for a block failed, polling the status of algod instead")
+			bot.polling = true
+			return false, nil
+		}
+		return false, err
+	}
+	bot.setLatestRound(status.LastRound)
+	if status.LastRound >= bot.nextRound {
+		bot.quickReturns = 0
+		return true, nil
+	}
+
+	// algod stopped waiting before it had the block.
+	if time.Since(start) < bot.followOptions.PollInterval {
+		bot.quickReturns++
+		if bot.followOptions.Mode == TipModeAuto && bot.quickReturns >= quickReturnsBeforePolling {
+			bot.log.Warn("algod does not wait for blocks, polling its status instead")
+			bot.polling = true
+		}
+		bot.sleep(bot.followOptions.PollInterval - time.Since(start))
+	}
+	return false, nil
+}
+
+// pollForBlock asks algod for its status and waits for the poll interval if it
+// does not have the next round yet.
+func (bot *fetcherImpl) pollForBlock(aclient *algod.Client) (bool, error) {
+	status, err := aclient.Status().Do(context.Background())
+	if err != nil {
+		return false, err
+	}
+	bot.setLatestRound(status.LastRound)
+	if status.LastRound >= bot.nextRound {
+		return true, nil
+	}
+	bot.sleep(bot.followOptions.PollInterval)
+	return false, nil
+}
+
+// statusAvailable returns true if algod answers status requests.
+func (bot *fetcherImpl) statusAvailable(aclient *algod.Client) bool {
+	bot.throttle()
+	status, err := aclient.Status().Do(context.Background())
+	if err != nil {
+		return false
+	}
+	bot.setLatestRound(status.LastRound)
+	return true
+}
@@ -0,0 +1,93 @@
+package fetcher
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/algorand/go-algorand-sdk/client/v2/algod"
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseTipMode(t *testing.T) {
+	for _, mode := range []string{"auto", "wait", "poll"} {
+		parsed, err := ParseTipMode(mode)
+		require.NoError(t, err)
+		assert.Equal(t, TipMode(mode), parsed)
+	}
+	_, err := ParseTipMode("push")
+	assert.Error(t, err)
+}
+
+// makeAlgod serves the status of an algod at round `lastRound`, without the
+// wait-for-block endpoint.
+func makeAlgod(t *testing.T, lastRound *uint64) (*algod.Client, func()) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasPrefix(r.URL.Path, "/v2/status/wait-for-block-after/") {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if r.URL.Path == "/v2/status" {
+			fmt.Fprintf(w, `{"last-round": %d}`, *lastRound)
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	client, err := algod.MakeClient(server.URL, "token")
+	require.NoError(t, err)
+	return client, server.Close
+}
+
+func TestWaitForBlockFallsBackToPolling(t *testing.T) {
+	lastRound := uint64(9)
+	client, shutdown := makeAlgod(t, &lastRound)
+	defer shutdown()
+
+	bot := &fetcherImpl{
+		aclient:   client,
+		log:       log.New(),
+		nextRound: 10,
+		followOptions: FollowOptions{
+			Mode:         TipModeAuto,
+			PollInterval: time.Millisecond,
+		},
+	}
+
+	available, err := bot.waitForBlock(client)
+	require.NoError(t, err)
+	assert.False(t, available)
+	assert.True(t, bot.polling)
+
+	available, err = bot.waitForBlock(client)
+	require.NoError(t, err)
+	assert.False(t, available)
+
+	lastRound = 10
+	available, err = bot.waitForBlock(client)
+	require.NoError(t, err)
+	assert.True(t, available)
+	latest, _ := bot.LatestRound()
+	assert.Equal(t, uint64(10), latest)
+}
+
+func TestWaitForBlockWaitModeFails(t *testing.T) {
+	lastRound := uint64(9)
+	client, shutdown := makeAlgod(t, &lastRound)
+	defer shutdown()
+
+	bot := &fetcherImpl{
+		aclient:       client,
+		log:           log.New(),
+		nextRound:     10,
+		followOptions: FollowOptions{Mode: TipModeWait, PollInterval: time.Millisecond},
+	}
+
+	_, err := bot.waitForBlock(client)
+	assert.Error(t, err)
+	assert.False(t, bot.polling)
+}
@@ -0,0 +1,53 @@
+package fetcher
+
+import (
+	"context"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/algorand/indexer/util/metrics"
+	"github.com/algorand/indexer/util/tracing"
+)
+
+// Sources of the fetched blocks in the metrics.
+const (
+	algodSourceName   = "algod"
+	archiveSourceName = "archive"
+	cacheSourceName   = "cache"
+)
+
+// fetchedBlocks counts the blocks fetched from algod and from archives.
+var fetchedBlocks = metrics.DefaultRegistry.NewCounterVec(
+	"fetched_blocks_total", "Blocks fetched from each source.", "source")
+
+var fetchDuration = metrics.DefaultRegistry.NewHistogramVec(
+	"block_fetch_duration_seconds",
+	"Time to fetch a block, whether it succeeded or not, by source.",
+	nil, "source")
+
+var algodRound = metrics.DefaultRegistry.NewGaugeVec(
+	"algod_round", "The latest round reported by algod, the tip of the chain.")
+
+// fetchObservation records a fetch of a block in the metrics and as a span.
+type fetchObservation struct {
+	source string
+	start  time.Time
+	span   trace.Span
+}
+
+// startFetch starts observing a fetch of the block of `round` from `source`.
+func startFetch(source string, round uint64) fetchObservation {
+	_, span := tracing.Start(context.Background(), "fetch block", trace.SpanKindClient,
+		attribute.Int64("indexer.round", int64(round)),
+		attribute.String("indexer.source", source))
+	return fetchObservation{source: source, start: time.Now(), span: span}
+}
+
+// end records the fetch, whether it succeeded or not.
+func (f fetchObservation) end(err error) {
+	fetchDuration.WithLabelValues(f.source).Observe(time.Since(f.start).Seconds())
+	tracing.SetError(f.span, err)
+	f.span.End()
+}
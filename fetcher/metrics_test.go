@@ -0,0 +1,62 @@
+package fetcher
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/algorand/go-algorand/protocol"
+	"github.com/algorand/go-algorand/rpcs"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	dto "github.com/prometheus/client_model/go"
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/algorand/indexer/util/test"
+)
+
+// fetchCount returns the number of fetches from `source` observed by the
+// duration histogram.
+func fetchCount(t *testing.T, source string) uint64 {
+	var m dto.Metric
+	err := fetchDuration.WithLabelValues(source).(prometheus.Metric).Write(&m)
+	require.NoError(t, err)
+	return m.GetHistogram().GetSampleCount()
+}
+
+func TestFetchMetricsCache(t *testing.T) {
+	dir, err := ioutil.TempDir("", "blocks")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	cache, err := MakeDiskCache(dir, 4)
+	require.NoError(t, err)
+	bot := &fetcherImpl{diskCache: cache, log: log.New()}
+
+	fetches := fetchCount(t, cacheSourceName)
+	fetched := testutil.ToFloat64(fetchedBlocks.WithLabelValues(cacheSourceName))
+
+	// Misses are timed but not counted as fetched blocks.
+	block, _ := bot.getCachedBlock(0)
+	assert.Nil(t, block)
+	assert.Equal(t, fetches+1, fetchCount(t, cacheSourceName))
+	assert.Equal(t, fetched, testutil.ToFloat64(fetchedBlocks.WithLabelValues(cacheSourceName)))
+
+	blockbytes := protocol.Encode(&rpcs.EncodedBlockCert{Block: test.MakeGenesisBlock()})
+	require.NoError(t, cache.Put(0, blockbytes))
+	block, _ = bot.getCachedBlock(0)
+	assert.NotNil(t, block)
+	assert.Equal(t, fetches+2, fetchCount(t, cacheSourceName))
+	assert.Equal(t, fetched+1, testutil.ToFloat64(fetchedBlocks.WithLabelValues(cacheSourceName)))
+}
+
+func TestSetLatestRoundMetric(t *testing.T) {
+	bot := &fetcherImpl{}
+	bot.setLatestRound(7)
+
+	round, ok := bot.LatestRound()
+	assert.True(t, ok)
+	assert.Equal(t, uint64(7), round)
+	assert.Equal(t, 7.0, testutil.ToFloat64(algodRound.WithLabelValues()))
+}
@@ -0,0 +1,296 @@
+package fetcher
+
+import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// The catalog of an object storage archive is read again after this long,
+	// archives grow while the chain does.
+	catalogRefreshInterval = 10 * time.Minute
+
+	// catalogName is the name of the catalog under the prefix of the archive.
+	catalogName = "catalog.json"
+
+	// maxArchivedBlockSize bounds the length of a block in an archive file, a
+	// larger length means that the file is corrupt.
+	maxArchivedBlockSize = 64 << 20
+)
+
+// archiveFile is an entry of the catalog of an object storage archive, a file
+// with the blocks of rounds First to Last.
+type archiveFile struct {
+	First uint64 `json:"first"`
+	Last  uint64 `json:"last"`
+	// Path of the file relative to the prefix of the archive. Files ending in
+	// .gz are gzipped.
+	Path string `json:"path"`
+}
+
+type archiveCatalog struct {
+	Files []archiveFile `json:"files"`
+}
+
+// objectArchive serves blocks from files in an object storage bucket, listed by
+// a catalog.json under its prefix. A file holds the blocks of consecutive
+// rounds, each the big endian uint32 length of the msgpack encoded block
+// certificate followed by the certificate.
+type objectArchive struct {
+	name string
+	// base is the https URL of the prefix, ending in a slash.
+	base   string
+	client *http.Client
+	// signer signs the requests, nil for a public bucket.
+	signer *sigV4Signer
+
+	mu sync.Mutex
+	// files of the catalog sorted by round.
+	files       []archiveFile
+	catalogRead time.Time
+	// The blocks of the file read last, a catchup reads consecutive rounds.
+	file   archiveFile
+	blocks [][]byte
+}
+
+// ObjectArchiveOptions are the credentials of S3 and GCS archives. The requests
+// are signed with AWS signature version 4, which GCS accepts with the HMAC key of
+// a service account. Without an access key the bucket must be readable without
+// credentials.
+type ObjectArchiveOptions struct {
+	AccessKeyID     string
+	SecretAccessKey string
+	// SessionToken of temporary AWS credentials, empty for none.
+	SessionToken string
+	// Region of the S3 bucket, defaults to us-east-1. GCS buckets don't need it.
+	Region string
+}
+
+// MakeObjectArchive returns an archive which reads block files from an S3 or GCS
+// bucket, given as "s3://bucket/prefix" or "gs://bucket/prefix".
+func MakeObjectArchive(url string, opts ObjectArchiveOptions) (Archive, error) {
+	var base string
+	region := opts.Region
+	switch {
+	case strings.HasPrefix(url, "s3://"):
+		bucket, prefix := splitBucketURL(strings.TrimPrefix(url, "s3://"))
+		if region == "" {
+			region = "us-east-1"
+			base = fmt.Sprintf("https://%s.s3.amazonaws.com/%s", bucket, prefix)
+		} else {
+			base = fmt.Sprintf("https://%s.s3.%s.amazonaws.com/%s", bucket, region, prefix)
+		}
+	case strings.HasPrefix(url, "gs://"):
+		bucket, prefix := splitBucketURL(strings.TrimPrefix(url, "gs://"))
+		base = fmt.Sprintf("https://storage.googleapis.com/%s/%s", bucket, prefix)
+		region = "auto"
+	default:
+		return nil, fmt.Errorf("MakeObjectArchive() %s is neither an s3:// nor a gs:// URL", url)
+	}
+
+	archive := makeObjectArchive(url, base)
+	if opts.AccessKeyID != "" {
+		if opts.SecretAccessKey == "" {
+			return nil, fmt.Errorf("MakeObjectArchive() %s has an access key id but no secret", url)
+		}
+		archive.signer = &sigV4Signer{
+			accessKeyID:     opts.AccessKeyID,
+			secretAccessKey: opts.SecretAccessKey,
+			sessionToken:    opts.SessionToken,
+			region:          region,
+			service:         "s3",
+		}
+	}
+	return archive, nil
+}
+
+// splitBucketURL splits "bucket/prefix" into the bucket and the prefix, which
+// is empty or ends in a slash.
+func splitBucketURL(url string) (string, string) {
+	parts := strings.SplitN(url, "/", 2)
+	if len(parts) == 1 || strings.Trim(parts[1], "/") == "" {
+		return parts[0], ""
+	}
+	return parts[0], strings.Trim(parts[1], "/") + "/"
+}
+
+func makeObjectArchive(name string, base string) *objectArchive {
+	return &objectArchive{
+		name:   name,
+		base:   base,
+		client: &http.Client{Timeout: 5 * time.Minute, Transport: archiveTransport},
+	}
+}
+
+// MakeArchive returns an object storage archive for s3:// and gs:// URLs and an
+// HTTP archive otherwise, see MakeObjectArchive() and MakeHTTPArchive().
+func MakeArchive(url string, opts ObjectArchiveOptions) (Archive, error) {
+	if strings.HasPrefix(url, "s3://") || strings.HasPrefix(url, "gs://") {
+		return MakeObjectArchive(url, opts)
+	}
+	return MakeHTTPArchive(url)
+}
+
+func (a *objectArchive) get(ctx context.Context, path string) (io.ReadCloser, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, a.base+path, nil)
+	if err != nil {
+		return nil, fmt.Errorf("get() err: %w", err)
+	}
+	if a.signer != nil {
+		a.signer.sign(req, time.Now())
+	}
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("get() err: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("get() %s status %s", path, resp.Status)
+	}
+	return resp.Body, nil
+}
+
+// readCatalog reads the catalog if it was not read within
+// catalogRefreshInterval.
+func (a *objectArchive) readCatalog(ctx context.Context) error {
+	if time.Since(a.catalogRead) < catalogRefreshInterval {
+		return nil
+	}
+
+	body, err := a.get(ctx, catalogName)
+	if err != nil {
+		return fmt.Errorf("readCatalog() err: %w", err)
+	}
+	defer body.Close()
+
+	var catalog archiveCatalog
+	err = json.NewDecoder(body).Decode(&catalog)
+	if err != nil {
+		return fmt.Errorf("readCatalog() decode err: %w", err)
+	}
+	for _, file := range catalog.Files {
+		if file.Last < file.First {
+			return fmt.Errorf(
+				"readCatalog() file %s ends at %d before it starts at %d",
+				file.Path, file.Last, file.First)
+		}
+	}
+	sort.Slice(catalog.Files, func(i, j int) bool {
+		return catalog.Files[i].First < catalog.Files[j].First
+	})
+
+	a.files = catalog.Files
+	a.catalogRead = time.Now()
+	return nil
+}
+
+// findFile returns the file of the catalog which has `round`.
+func (a *objectArchive) findFile(round uint64) (archiveFile, bool) {
+	i := sort.Search(len(a.files), func(i int) bool {
+		return a.files[i].Last >= round
+	})
+	if i == len(a.files) || a.files[i].First > round {
+		return archiveFile{}, false
+	}
+	return a.files[i], true
+}
+
+// readArchiveFile splits the content of an archive file into its blocks.
+func readArchiveFile(r io.Reader) ([][]byte, error) {
+	br := bufio.NewReader(r)
+	var blocks [][]byte
+	for {
+		var length uint32
+		err := binary.Read(br, binary.BigEndian, &length)
+		if err == io.EOF {
+			return blocks, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("readArchiveFile() read length err: %w", err)
+		}
+		if length > maxArchivedBlockSize {
+			return nil, fmt.Errorf("readArchiveFile() block of %d bytes is too large", length)
+		}
+
+		block := make([]byte, length)
+		_, err = io.ReadFull(br, block)
+		if err != nil {
+			return nil, fmt.Errorf("readArchiveFile() read block err: %w", err)
+		}
+		blocks = append(blocks, block)
+	}
+}
+
+// readFile downloads `file` and makes it the current file.
+func (a *objectArchive) readFile(ctx context.Context, file archiveFile) error {
+	body, err := a.get(ctx, file.Path)
+	if err != nil {
+		return fmt.Errorf("readFile() err: %w", err)
+	}
+	defer body.Close()
+
+	var r io.Reader = body
+	if strings.HasSuffix(file.Path, ".gz") {
+		gz, err := gzip.NewReader(body)
+		if err != nil {
+			return fmt.Errorf("readFile() %s gzip err: %w", file.Path, err)
+		}
+		defer gz.Close()
+		r = gz
+	}
+
+	blocks, err := readArchiveFile(r)
+	if err != nil {
+		return fmt.Errorf("readFile() %s err: %w", file.Path, err)
+	}
+	if uint64(len(blocks)) != file.Last-file.First+1 {
+		return fmt.Errorf(
+			"readFile() %s has %d blocks instead of rounds %d to %d",
+			file.Path, len(blocks), file.First, file.Last)
+	}
+
+	a.file = file
+	a.blocks = blocks
+	return nil
+}
+
+// BlockRaw is part of the Archive interface.
+func (a *objectArchive) BlockRaw(ctx context.Context, round uint64) ([]byte, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.blocks != nil && a.file.First <= round && round <= a.file.Last {
+		return a.blocks[round-a.file.First], nil
+	}
+
+	err := a.readCatalog(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("BlockRaw() err: %w", err)
+	}
+	file, ok := a.findFile(round)
+	if !ok {
+		return nil, fmt.Errorf("BlockRaw() round %d is not in the catalog", round)
+	}
+	// Drop the previous file before reading the next one.
+	a.blocks = nil
+	err = a.readFile(ctx, file)
+	if err != nil {
+		return nil, fmt.Errorf("BlockRaw() err: %w", err)
+	}
+	return a.blocks[round-file.First], nil
+}
+
+// String is part of the Archive interface.
+func (a *objectArchive) String() string {
+	return a.name
+}
@@ -0,0 +1,136 @@
+package fetcher
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func makeArchiveFile(t *testing.T, first uint64, last uint64, compress bool) []byte {
+	var buf bytes.Buffer
+	var w io.Writer = &buf
+	var gz *gzip.Writer
+	if compress {
+		gz = gzip.NewWriter(&buf)
+		w = gz
+	}
+
+	for round := first; round <= last; round++ {
+		block := []byte(fmt.Sprintf("block %d", round))
+		err := binary.Write(w, binary.BigEndian, uint32(len(block)))
+		require.NoError(t, err)
+		_, err = w.Write(block)
+		require.NoError(t, err)
+	}
+	if gz != nil {
+		require.NoError(t, gz.Close())
+	}
+	return buf.Bytes()
+}
+
+func TestMakeObjectArchive(t *testing.T) {
+	archive, err := MakeObjectArchive("s3://blocks/mainnet/", ObjectArchiveOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, "https://blocks.s3.amazonaws.com/mainnet/", archive.(*objectArchive).base)
+	assert.Nil(t, archive.(*objectArchive).signer)
+
+	archive, err = MakeObjectArchive("gs://blocks", ObjectArchiveOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, "https://storage.googleapis.com/blocks/", archive.(*objectArchive).base)
+
+	_, err = MakeObjectArchive("https://blocks.example.com", ObjectArchiveOptions{})
+	assert.Error(t, err)
+
+	archive, err = MakeArchive("https://blocks.example.com/{round}", ObjectArchiveOptions{})
+	require.NoError(t, err)
+	assert.IsType(t, &httpArchive{}, archive)
+}
+
+func TestMakeObjectArchiveCredentials(t *testing.T) {
+	opts := ObjectArchiveOptions{
+		AccessKeyID:     "id",
+		SecretAccessKey: "secret",
+		Region:          "eu-west-1",
+	}
+	archive, err := MakeObjectArchive("s3://blocks/mainnet", opts)
+	require.NoError(t, err)
+	assert.Equal(t, "https://blocks.s3.eu-west-1.amazonaws.com/mainnet/", archive.(*objectArchive).base)
+	require.NotNil(t, archive.(*objectArchive).signer)
+	assert.Equal(t, "eu-west-1", archive.(*objectArchive).signer.region)
+
+	archive, err = MakeObjectArchive("gs://blocks", opts)
+	require.NoError(t, err)
+	assert.Equal(t, "auto", archive.(*objectArchive).signer.region)
+
+	_, err = MakeObjectArchive("s3://blocks", ObjectArchiveOptions{AccessKeyID: "id"})
+	assert.Error(t, err)
+}
+
+func TestObjectArchiveSignsRequests(t *testing.T) {
+	var authorization string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorization = r.Header.Get("Authorization")
+		w.Write([]byte(`{"files": [{"first": 0, "last": 0, "path": "0.blocks"}]}`))
+	}))
+	defer server.Close()
+
+	archive := makeObjectArchive("s3://mainnet", server.URL+"/mainnet/")
+	archive.signer = &sigV4Signer{
+		accessKeyID: "id", secretAccessKey: "secret", region: "us-east-1", service: "s3"}
+	err := archive.readCatalog(context.Background())
+	require.NoError(t, err)
+	assert.Contains(t, authorization, "AWS4-HMAC-SHA256 Credential=id/")
+}
+
+func TestObjectArchiveBlockRaw(t *testing.T) {
+	requests := make(map[string]int)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests[r.URL.Path]++
+		switch r.URL.Path {
+		case "/mainnet/catalog.json":
+			w.Write([]byte(`{"files": [
+				{"first": 10, "last": 19, "path": "10.blocks.gz"},
+				{"first": 0, "last": 9, "path": "0.blocks"},
+				{"first": 20, "last": 29, "path": "20.blocks"}]}`))
+		case "/mainnet/0.blocks":
+			w.Write(makeArchiveFile(t, 0, 9, false))
+		case "/mainnet/10.blocks.gz":
+			w.Write(makeArchiveFile(t, 10, 19, true))
+		case "/mainnet/20.blocks":
+			// Missing a block.
+			w.Write(makeArchiveFile(t, 20, 28, false))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	archive := makeObjectArchive("s3://mainnet", server.URL+"/mainnet/")
+
+	for round := uint64(0); round < 20; round++ {
+		blockbytes, err := archive.BlockRaw(context.Background(), round)
+		require.NoError(t, err)
+		assert.Equal(t, []byte(fmt.Sprintf("block %d", round)), blockbytes)
+	}
+	assert.Equal(t, 1, requests["/mainnet/catalog.json"])
+	assert.Equal(t, 1, requests["/mainnet/0.blocks"])
+	assert.Equal(t, 1, requests["/mainnet/10.blocks.gz"])
+
+	_, err := archive.BlockRaw(context.Background(), 25)
+	assert.Error(t, err)
+	_, err = archive.BlockRaw(context.Background(), 30)
+	assert.Error(t, err)
+
+	blockbytes, err := archive.BlockRaw(context.Background(), 5)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("block 5"), blockbytes)
+}
@@ -0,0 +1,63 @@
+package fetcher
+
+import (
+	"sync"
+	"time"
+)
+
+// rateLimiter is a token bucket which limits the requests to algod. The bucket
+// fills at `rate` tokens per second up to `burst` tokens, so that after algod
+// notified the fetcher of a new round, e.g. at the tip, the next requests don't
+// wait.
+type rateLimiter struct {
+	mu    sync.Mutex
+	rate  float64
+	burst float64
+	// tokens is negative when requests wait for tokens which are not there yet.
+	tokens float64
+	last   time.Time
+
+	now func() time.Time
+}
+
+// makeRateLimiter returns a limiter of `rate` requests per second, nil if rate
+// is not positive.
+func makeRateLimiter(rate float64, burst int) *rateLimiter {
+	if rate <= 0 {
+		return nil
+	}
+	if burst < 1 {
+		burst = 1
+	}
+	return &rateLimiter{
+		rate:   rate,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		now:    time.Now,
+	}
+}
+
+// reserve takes a token and returns how long the caller has to wait before it
+// may send its request.
+func (l *rateLimiter) reserve() time.Duration {
+	if l == nil {
+		return 0
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	if !l.last.IsZero() {
+		l.tokens += now.Sub(l.last).Seconds() * l.rate
+		if l.tokens > l.burst {
+			l.tokens = l.burst
+		}
+	}
+	l.last = now
+
+	l.tokens--
+	if l.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-l.tokens / l.rate * float64(time.Second))
+}
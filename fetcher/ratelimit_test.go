@@ -0,0 +1,35 @@
+package fetcher
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRateLimiter(t *testing.T) {
+	now := time.Unix(1000, 0)
+	limiter := makeRateLimiter(2, 3)
+	limiter.now = func() time.Time { return now }
+
+	// The burst does not wait.
+	for i := 0; i < 3; i++ {
+		assert.Equal(t, time.Duration(0), limiter.reserve())
+	}
+	// The following requests are spaced by half a second.
+	assert.Equal(t, 500*time.Millisecond, limiter.reserve())
+	assert.Equal(t, time.Second, limiter.reserve())
+
+	// After a long wait, e.g. for a new round, the bucket is full again.
+	now = now.Add(time.Minute)
+	for i := 0; i < 3; i++ {
+		assert.Equal(t, time.Duration(0), limiter.reserve())
+	}
+	assert.Equal(t, 500*time.Millisecond, limiter.reserve())
+}
+
+func TestRateLimiterDisabled(t *testing.T) {
+	limiter := makeRateLimiter(0, 10)
+	assert.Nil(t, limiter)
+	assert.Equal(t, time.Duration(0), limiter.reserve())
+}
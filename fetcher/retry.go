@@ -0,0 +1,54 @@
+package fetcher
+
+import (
+	"math/rand"
+	"time"
+)
+
+// RetryPolicy configures how the fetcher waits when fetching a block from algod
+// fails. Waiting for a block which algod does not have yet is not a failure,
+// the fetcher asks algod to notify it instead.
+type RetryPolicy struct {
+	// Retries is the number of times a failed fetch is retried before the
+	// fetcher reconnects to algod.
+	Retries int
+
+	// BackoffBase is the wait after the first failure. It doubles with every
+	// consecutive failure up to BackoffMax, if that is larger.
+	BackoffBase time.Duration
+	BackoffMax  time.Duration
+
+	// Jitter randomizes the waits by up to this fraction, e.g. 0.2 for 20% more
+	// or less, so that several indexers of one algod don't retry at once.
+	Jitter float64
+}
+
+// DefaultRetryPolicy returns the policy used unless SetRetryPolicy() is called.
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		Retries:     3,
+		BackoffBase: time.Second,
+		BackoffMax:  time.Minute,
+		Jitter:      0.2,
+	}
+}
+
+// Backoff returns the wait after `failures` consecutive failures.
+func (p RetryPolicy) Backoff(failures int) time.Duration {
+	if failures <= 0 || p.BackoffBase <= 0 {
+		return 0
+	}
+
+	wait := p.BackoffBase
+	for i := 1; i < failures && wait < p.BackoffMax; i++ {
+		wait *= 2
+	}
+	if wait > p.BackoffMax && p.BackoffMax > p.BackoffBase {
+		wait = p.BackoffMax
+	}
+
+	if p.Jitter > 0 {
+		wait += time.Duration(float64(wait) * p.Jitter * (2*rand.Float64() - 1))
+	}
+	return wait
+}
@@ -0,0 +1,29 @@
+package fetcher
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRetryPolicyBackoff(t *testing.T) {
+	policy := RetryPolicy{BackoffBase: time.Second, BackoffMax: 10 * time.Second}
+
+	assert.Equal(t, time.Duration(0), policy.Backoff(0))
+	assert.Equal(t, time.Second, policy.Backoff(1))
+	assert.Equal(t, 2*time.Second, policy.Backoff(2))
+	assert.Equal(t, 8*time.Second, policy.Backoff(4))
+	assert.Equal(t, 10*time.Second, policy.Backoff(5))
+	assert.Equal(t, 10*time.Second, policy.Backoff(1000))
+}
+
+func TestRetryPolicyBackoffJitter(t *testing.T) {
+	policy := RetryPolicy{BackoffBase: time.Second, BackoffMax: 10 * time.Second, Jitter: 0.5}
+
+	for i := 0; i < 100; i++ {
+		wait := policy.Backoff(2)
+		assert.True(t, wait >= time.Second, wait)
+		assert.True(t, wait <= 3*time.Second, wait)
+	}
+}
@@ -0,0 +1,119 @@
+package idb
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/algorand/go-algorand/data/basics"
+	"github.com/algorand/go-algorand/data/transactions"
+	"github.com/algorand/go-algorand/ledger/ledgercore"
+)
+
+// AddressScope is a set of addresses, e.g. the wallets of an exchange, and the
+// assets and applications they created. An import with a scope only stores the
+// transactions which involve it.
+type AddressScope struct {
+	addresses map[basics.Address]bool
+
+	// mu guards the creatables, which grow as the import finds new ones.
+	mu     sync.RWMutex
+	assets map[uint64]bool
+	apps   map[uint64]bool
+}
+
+// ParseAddressScope makes a scope of the addresses in `addresses`, with no
+// creatables yet.
+func ParseAddressScope(addresses []string) (*AddressScope, error) {
+	if len(addresses) == 0 {
+		return nil, fmt.Errorf("ParseAddressScope() no addresses")
+	}
+	res := &AddressScope{
+		addresses: make(map[basics.Address]bool, len(addresses)),
+		assets:    make(map[uint64]bool),
+		apps:      make(map[uint64]bool),
+	}
+	for _, s := range addresses {
+		address, err := basics.UnmarshalChecksumAddress(s)
+		if err != nil {
+			return nil, fmt.Errorf("ParseAddressScope() address %s err: %w", s, err)
+		}
+		res.addresses[address] = true
+	}
+	return res, nil
+}
+
+// Addresses returns the addresses of the scope.
+func (s *AddressScope) Addresses() []basics.Address {
+	res := make([]basics.Address, 0, len(s.addresses))
+	for address := range s.addresses {
+		res = append(res, address)
+	}
+	return res
+}
+
+// AddAsset adds an asset to the scope.
+func (s *AddressScope) AddAsset(index uint64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.assets[index] = true
+}
+
+// AddApp adds an application to the scope.
+func (s *AddressScope) AddApp(index uint64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.apps[index] = true
+}
+
+// AddCreatables adds the assets and applications which the addresses of the
+// scope create in `delta`.
+func (s *AddressScope) AddCreatables(delta ledgercore.StateDelta) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for index, creatable := range delta.Creatables {
+		if !creatable.Created || !s.addresses[creatable.Creator] {
+			continue
+		}
+		if creatable.Ctype == basics.AssetCreatable {
+			s.assets[uint64(index)] = true
+		} else {
+			s.apps[uint64(index)] = true
+		}
+	}
+}
+
+// Involves returns whether the transaction `txn` involves the scope, i.e. one
+// of its participants is in the scope or it refers to an asset or application
+// of the scope. `assetid` is the asset or app id of the transaction, as stored
+// in the asset column of the txn table. A nil AddressScope involves every
+// transaction.
+func (s *AddressScope) Involves(txn *transactions.Transaction, typeenum TxnTypeEnum, assetid uint64) bool {
+	if s == nil {
+		return true
+	}
+	for _, address := range []basics.Address{
+		txn.Sender, txn.Receiver, txn.CloseRemainderTo, txn.AssetSender,
+		txn.AssetReceiver, txn.AssetCloseTo, txn.FreezeAccount} {
+		if s.addresses[address] {
+			return true
+		}
+	}
+	if typeenum == TypeEnumApplication {
+		for _, address := range txn.Accounts {
+			if s.addresses[address] {
+				return true
+			}
+		}
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	switch typeenum {
+	case TypeEnumAssetConfig, TypeEnumAssetTransfer, TypeEnumAssetFreeze:
+		return s.assets[assetid]
+	case TypeEnumApplication:
+		return s.apps[assetid]
+	}
+	return false
+}
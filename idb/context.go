@@ -0,0 +1,34 @@
+package idb
+
+import "context"
+
+type endpointKey struct{}
+
+// ContextWithEndpoint returns a copy of `ctx` which carries the API endpoint,
+// e.g. "GET /v2/accounts/:account-id", whose request runs the queries of the
+// context. The database reports it with its slow queries.
+func ContextWithEndpoint(ctx context.Context, endpoint string) context.Context {
+	return context.WithValue(ctx, endpointKey{}, endpoint)
+}
+
+// EndpointFromContext returns the API endpoint of `ctx`, or "" if the queries
+// are not run by an API request.
+func EndpointFromContext(ctx context.Context) string {
+	endpoint, _ := ctx.Value(endpointKey{}).(string)
+	return endpoint
+}
+
+type requestIDKey struct{}
+
+// ContextWithRequestID returns a copy of `ctx` which carries the ID of the API
+// request whose queries run with the context. The database logs it and sets it
+// as the application_name of the request's transactions.
+func ContextWithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// RequestIDFromContext returns the request ID of `ctx`, or "".
+func RequestIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
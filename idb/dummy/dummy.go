@@ -2,6 +2,7 @@ package dummy
 
 import (
 	"context"
+	"io"
 
 	"github.com/algorand/go-algorand/data/bookkeeping"
 	"github.com/algorand/go-algorand/data/transactions"
@@ -25,6 +26,12 @@ func (db *dummyIndexerDb) AddBlock(block *bookkeeping.Block) error {
 	return nil
 }
 
+// AddBlocks is part of idb.IndexerDB
+func (db *dummyIndexerDb) AddBlocks(blocks []*bookkeeping.Block) error {
+	db.log.Printf("AddBlocks")
+	return nil
+}
+
 // LoadGenesis is part of idb.IndexerDB
 func (db *dummyIndexerDb) LoadGenesis(genesis bookkeeping.Genesis) (err error) {
 	return nil
@@ -35,6 +42,11 @@ func (db *dummyIndexerDb) GetNextRoundToAccount() (uint64, error) {
 	return 0, nil
 }
 
+// GetGenesisHash is part of idb.IndexerDB
+func (db *dummyIndexerDb) GetGenesisHash() (string, error) {
+	return "", nil
+}
+
 // GetNextRoundToLoad is part of idb.IndexerDB
 func (db *dummyIndexerDb) GetNextRoundToLoad() (uint64, error) {
 	return 0, nil
@@ -50,6 +62,11 @@ func (db *dummyIndexerDb) GetBlock(ctx context.Context, round uint64, options id
 	return bookkeeping.BlockHeader{}, nil, nil
 }
 
+// GetBlockWithTransactions is part of idb.IndexerDB
+func (db *dummyIndexerDb) GetBlockWithTransactions(ctx context.Context, round uint64) (bookkeeping.BlockHeader, []idb.TxnRow, error) {
+	return bookkeeping.BlockHeader{}, nil, nil
+}
+
 // Transactions is part of idb.IndexerDB
 func (db *dummyIndexerDb) Transactions(ctx context.Context, tf idb.TransactionFilter) (<-chan idb.TxnRow, uint64) {
 	return nil, 0
@@ -79,3 +96,139 @@ func (db *dummyIndexerDb) Applications(ctx context.Context, filter *models.Searc
 func (db *dummyIndexerDb) Health() (state idb.Health, err error) {
 	return idb.Health{}, nil
 }
+
+// DeleteOrphanedRows is part of idb.IndexerDB
+func (db *dummyIndexerDb) DeleteOrphanedRows(ctx context.Context, dryRun bool) ([]idb.OrphanedRows, error) {
+	return nil, nil
+}
+
+// Backfill is part of idb.IndexerDB
+func (db *dummyIndexerDb) Backfill(ctx context.Context, opts idb.BackfillOptions) error {
+	return nil
+}
+
+// GetServerSettings is part of idb.IndexerDB
+func (db *dummyIndexerDb) GetServerSettings(ctx context.Context) (idb.ServerSettings, error) {
+	return idb.ServerSettings{}, nil
+}
+
+// EstimateTransactionsCount is part of idb.IndexerDB
+func (db *dummyIndexerDb) EstimateTransactionsCount(ctx context.Context, tf idb.TransactionFilter) (uint64, error) {
+	return 0, nil
+}
+
+// EstimateAccountsCount is part of idb.IndexerDB
+func (db *dummyIndexerDb) EstimateAccountsCount(ctx context.Context, opts idb.AccountQueryOptions) (uint64, error) {
+	return 0, nil
+}
+
+// ExplainTransactions is part of idb.IndexerDB
+func (db *dummyIndexerDb) ExplainTransactions(ctx context.Context, tf idb.TransactionFilter) (idb.QueryPlan, error) {
+	return idb.QueryPlan{}, nil
+}
+
+// RunPendingMigrations is part of idb.IndexerDB
+func (db *dummyIndexerDb) RunPendingMigrations() error {
+	return nil
+}
+
+// RollbackMigrations is part of idb.IndexerDB
+func (db *dummyIndexerDb) RollbackMigrations(ctx context.Context, count int) ([]string, error) {
+	return nil, nil
+}
+
+// Migrations is part of idb.IndexerDB
+func (db *dummyIndexerDb) Migrations(ctx context.Context) ([]idb.MigrationInfo, error) {
+	return nil, nil
+}
+
+// FlushCaches is part of idb.IndexerDB
+func (db *dummyIndexerDb) FlushCaches(ctx context.Context) error {
+	return nil
+}
+
+// PruneTransactions is part of idb.IndexerDB
+func (db *dummyIndexerDb) PruneTransactions(ctx context.Context, round uint64, maxRounds uint64) (uint64, error) {
+	return 0, nil
+}
+
+// PruneParticipation is part of idb.IndexerDB
+func (db *dummyIndexerDb) PruneParticipation(ctx context.Context, round uint64, maxRounds uint64, archive io.Writer) (uint64, error) {
+	return 0, nil
+}
+
+// ListenRounds is part of idb.IndexerDB
+func (db *dummyIndexerDb) ListenRounds(ctx context.Context, f func(round uint64)) error {
+	<-ctx.Done()
+	return nil
+}
+
+// RunMaintenance is part of idb.IndexerDB
+func (db *dummyIndexerDb) RunMaintenance(ctx context.Context, opts idb.MaintenanceOptions) error {
+	<-ctx.Done()
+	return nil
+}
+
+// ExportSnapshot is part of idb.IndexerDB
+func (db *dummyIndexerDb) ExportSnapshot(ctx context.Context, w io.Writer) (uint64, error) {
+	return 0, nil
+}
+
+// ImportSnapshot is part of idb.IndexerDB
+func (db *dummyIndexerDb) ImportSnapshot(ctx context.Context, r io.Reader) (uint64, error) {
+	return 0, nil
+}
+
+// DumpCSV is part of idb.IndexerDB
+func (db *dummyIndexerDb) DumpCSV(ctx context.Context, table string, w io.Writer) (uint64, error) {
+	return 0, nil
+}
+
+// Rederive is part of idb.IndexerDB
+func (db *dummyIndexerDb) Rederive(ctx context.Context, genesis bookkeeping.Genesis, repair bool) ([]idb.RederivedTable, error) {
+	return nil, nil
+}
+
+// Reprocess is part of idb.IndexerDB
+func (db *dummyIndexerDb) Reprocess(ctx context.Context, genesis bookkeeping.Genesis, blocks []*bookkeeping.Block) ([]idb.RederivedTable, error) {
+	return nil, nil
+}
+
+// GetExportRound is part of idb.IndexerDB
+func (db *dummyIndexerDb) GetExportRound(ctx context.Context, name string) (uint64, error) {
+	return 0, nil
+}
+
+// SetExportRound is part of idb.IndexerDB
+func (db *dummyIndexerDb) SetExportRound(ctx context.Context, name string, round uint64) error {
+	return nil
+}
+
+// Webhooks is part of idb.IndexerDB
+func (db *dummyIndexerDb) Webhooks(ctx context.Context) ([]idb.Webhook, error) {
+	return nil, nil
+}
+
+// AddWebhook is part of idb.IndexerDB
+func (db *dummyIndexerDb) AddWebhook(ctx context.Context, hook idb.Webhook) error {
+	return nil
+}
+
+// DeleteWebhook is part of idb.IndexerDB
+func (db *dummyIndexerDb) DeleteWebhook(ctx context.Context, id string) (bool, error) {
+	return false, nil
+}
+
+// PaginationSecret is part of idb.IndexerDB
+func (db *dummyIndexerDb) PaginationSecret(ctx context.Context) ([]byte, error) {
+	return nil, nil
+}
+
+// AddBlockHook is part of idb.IndexerDB
+func (db *dummyIndexerDb) AddBlockHook(hook idb.BlockHook) {
+}
+
+// GetRetention is part of idb.IndexerDB
+func (db *dummyIndexerDb) GetRetention(ctx context.Context) (idb.Retention, error) {
+	return idb.Retention{}, nil
+}
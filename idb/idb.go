@@ -5,11 +5,13 @@ import (
 	"encoding/base64"
 	"encoding/binary"
 	"errors"
+	"io"
 	"time"
 
 	"github.com/algorand/go-algorand/data/basics"
 	"github.com/algorand/go-algorand/data/bookkeeping"
 	"github.com/algorand/go-algorand/data/transactions"
+	"github.com/algorand/go-algorand/ledger/ledgercore"
 
 	models "github.com/algorand/indexer/api/generated/v2"
 )
@@ -34,6 +36,10 @@ type TxnRow struct {
 	// Extra are some additional fields which might be related to to the transaction.
 	Extra TxnExtra
 
+	// Truncated is set when the transaction was too large to be returned in full
+	// and TxnBytes does not include the eval delta (global and local state deltas).
+	Truncated bool
+
 	// Error indicates that there was an internal problem processing the expected transaction.
 	Error error
 }
@@ -69,23 +75,39 @@ type TxnExtra struct {
 // because initialization has not been completed.
 var ErrorNotInitialized error = errors.New("accounting not initialized")
 
+// ErrorGenesisMismatch is returned when adding a block of another network than
+// the genesis which the database was initialized with, e.g. because the indexer
+// was pointed to the algod of another network.
+var ErrorGenesisMismatch error = errors.New("block is of another network than the database")
+
 // IndexerDb is the interface used to define alternative Indexer backends.
 // TODO: sqlite3 impl
 // TODO: cockroachdb impl
 type IndexerDb interface {
 	// Import a block and do the accounting.
 	AddBlock(block *bookkeeping.Block) error
+	// Import consecutive blocks in one database transaction, used to catch up with
+	// the network faster.
+	AddBlocks(blocks []*bookkeeping.Block) error
 
 	LoadGenesis(genesis bookkeeping.Genesis) (err error)
 
 	// GetNextRoundToAccount returns ErrorNotInitialized if genesis is not loaded.
 	GetNextRoundToAccount() (uint64, error)
+	// GetGenesisHash returns the genesis hash of the network of the database,
+	// which is recorded by the first imported block, or "" before that. Returns
+	// ErrorNotInitialized if genesis is not loaded.
+	GetGenesisHash() (string, error)
 	GetSpecialAccounts() (transactions.SpecialAddresses, error)
 
 	GetBlock(ctx context.Context, round uint64, options GetBlockOptions) (blockHeader bookkeeping.BlockHeader, transactions []TxnRow, err error)
+	// GetBlockWithTransactions returns the block header and the transactions of
+	// the block ordered by their position in it, with a single query.
+	GetBlockWithTransactions(ctx context.Context, round uint64) (bookkeeping.BlockHeader, []TxnRow, error)
 
 	// The next multiple functions return a channel with results as well as the latest round
-	// accounted.
+	// accounted. The query stops and releases its connection when `ctx` is done, the
+	// consumer may stop reading then, see SendTxnRow().
 	Transactions(ctx context.Context, tf TransactionFilter) (<-chan TxnRow, uint64)
 	GetAccounts(ctx context.Context, opts AccountQueryOptions) (<-chan AccountRow, uint64)
 	Assets(ctx context.Context, filter AssetsQuery) (<-chan AssetRow, uint64)
@@ -93,6 +115,137 @@ type IndexerDb interface {
 	Applications(ctx context.Context, filter *models.SearchForApplicationsParams) (<-chan ApplicationRow, uint64)
 
 	Health() (status Health, err error)
+
+	// DeleteOrphanedRows finds rows which were left behind by interrupted migrations
+	// or repairs and deletes them. When `dryRun` is set the rows are only counted.
+	DeleteOrphanedRows(ctx context.Context, dryRun bool) ([]OrphanedRows, error)
+
+	// Backfill recomputes derived data for a range of rounds from the stored
+	// transactions. An interrupted backfill of the same range resumes where it stopped.
+	Backfill(ctx context.Context, opts BackfillOptions) error
+
+	// GetServerSettings returns the database server settings which limit how the
+	// indexer can be configured.
+	GetServerSettings(ctx context.Context) (ServerSettings, error)
+
+	// ExplainTransactions returns how the database would execute the query for
+	// `tf` without running it.
+	ExplainTransactions(ctx context.Context, tf TransactionFilter) (QueryPlan, error)
+
+	// EstimateTransactionsCount and EstimateAccountsCount return an estimate of
+	// the number of results of a search over all pages, which is cheap to get
+	// unlike an exact count. The limit and next token are ignored.
+	EstimateTransactionsCount(ctx context.Context, tf TransactionFilter) (uint64, error)
+	EstimateAccountsCount(ctx context.Context, opts AccountQueryOptions) (uint64, error)
+
+	// RunPendingMigrations starts the migrations which have not completed, e.g.
+	// after a failure. It returns once they are started. Pending blocking
+	// migrations require a restart and are not started.
+	RunPendingMigrations() error
+
+	// RollbackMigrations undoes the last `count` completed migrations, newest
+	// first, so that an older release can use the database. Nothing is rolled
+	// back if one of them can't be. It returns the descriptions of the migrations
+	// which were rolled back.
+	RollbackMigrations(ctx context.Context, count int) ([]string, error)
+
+	// Migrations returns the migrations of this release in the order they run,
+	// and whether they were applied to the database.
+	Migrations(ctx context.Context) ([]MigrationInfo, error)
+
+	// FlushCaches drops cached data, e.g. prepared statements which may be stale
+	// after a schema change and the accounts cached by the import, which are
+	// stale after the account rows were changed by something else.
+	FlushCaches(ctx context.Context) error
+
+	// PruneTransactions deletes the transactions and participation rows of the
+	// rounds before `round`, at most `maxRounds` rounds per call. Accounts and
+	// block headers are kept. It returns the new earliest round whose
+	// transactions are available.
+	PruneTransactions(ctx context.Context, round uint64, maxRounds uint64) (uint64, error)
+
+	// PruneParticipation deletes the participation rows, which make the
+	// transactions searchable by address, of the rounds before `round`, at most
+	// `maxRounds` rounds per call. Unless `archive` is nil the rows are written to
+	// it as CSV before they are deleted. It returns the new earliest round whose
+	// transactions can be searched by address.
+	PruneParticipation(ctx context.Context, round uint64, maxRounds uint64, archive io.Writer) (uint64, error)
+
+	// GetRetention returns the earliest rounds which were not pruned.
+	GetRetention(ctx context.Context) (Retention, error)
+
+	// ListenRounds calls `f` with each round imported by any indexer writing to
+	// the database, starting with the latest imported round, until the context is
+	// done or the connection fails.
+	ListenRounds(ctx context.Context, f func(round uint64)) error
+
+	// RunMaintenance analyzes, and optionally vacuums, the tables which the
+	// import changes as often as `opts` asks until the context is done.
+	RunMaintenance(ctx context.Context, opts MaintenanceOptions) error
+
+	// ExportSnapshot writes all the tables, consistent as of one imported round,
+	// to `w`. It returns the next round to import after the snapshot.
+	ExportSnapshot(ctx context.Context, w io.Writer) (uint64, error)
+
+	// ImportSnapshot loads a snapshot written by ExportSnapshot() into an empty
+	// database. It returns the next round to import.
+	ImportSnapshot(ctx context.Context, r io.Reader) (uint64, error)
+
+	// DumpCSV writes the current rows of `table`, one of DumpAccounts,
+	// DumpAssets or DumpBalances, to `w` as CSV with a header, read in one
+	// consistent snapshot. It returns the round of the rows.
+	DumpCSV(ctx context.Context, table string, w io.Writer) (uint64, error)
+
+	// Rederive rebuilds the account, asset and app tables by evaluating the
+	// stored blocks from `genesis` on, and compares them with the stored tables.
+	// The rebuilt tables are only kept if `repair` is set.
+	Rederive(ctx context.Context, genesis bookkeeping.Genesis, repair bool) ([]RederivedTable, error)
+
+	// Reprocess replaces the stored blocks of the rounds of `blocks`, which
+	// must be consecutive and already imported, and rebuilds the state like
+	// Rederive() with `repair` set. The indexer only keeps the latest state, so
+	// all the imported rounds are evaluated again, not only the replaced ones.
+	Reprocess(ctx context.Context, genesis bookkeeping.Genesis, blocks []*bookkeeping.Block) ([]RederivedTable, error)
+
+	// AddBlockHook registers a hook which is called by AddBlock() and
+	// AddBlocks() with every imported block.
+	AddBlockHook(hook BlockHook)
+
+	// GetExportRound returns the next round which the exporter `name` publishes,
+	// 0 if it did not publish anything yet.
+	GetExportRound(ctx context.Context, name string) (uint64, error)
+
+	// SetExportRound records that the exporter `name` published the rounds
+	// before `round`.
+	SetExportRound(ctx context.Context, name string, round uint64) error
+
+	// Webhooks returns the registered webhooks.
+	Webhooks(ctx context.Context) ([]Webhook, error)
+
+	// AddWebhook registers a webhook. Its id must not be registered yet.
+	AddWebhook(ctx context.Context, hook Webhook) error
+
+	// DeleteWebhook removes the webhook `id`. It returns false if there is none.
+	DeleteWebhook(ctx context.Context, id string) (bool, error)
+
+	// PaginationSecret returns a random secret which is generated by the first
+	// call and kept in the database, so that every indexer which uses the
+	// database signs the next tokens with the same key.
+	PaginationSecret(ctx context.Context) ([]byte, error)
+}
+
+// BlockHook receives the imported blocks with the state delta computed by the
+// evaluator, e.g. to maintain derived tables outside of the indexer schema.
+type BlockHook interface {
+	// BeforeBlock is called after the block is evaluated and before it is
+	// written. An error aborts the import and is returned by AddBlock(). It may
+	// be called more than once for a block when the database transaction is
+	// retried.
+	BeforeBlock(block *bookkeeping.Block, delta ledgercore.StateDelta) error
+
+	// AfterBlock is called once the block is committed. When several blocks are
+	// added in one transaction it is called for each of them after the commit.
+	AfterBlock(block *bookkeeping.Block, delta ledgercore.StateDelta)
 }
 
 // GetBlockOptions contains the options when requesting to load a block from the database.
@@ -248,6 +401,117 @@ type ApplicationRow struct {
 // IndexerDbOptions are the options common to all indexer backends.
 type IndexerDbOptions struct {
 	ReadOnly bool
+
+	// SkipMigrations opens an existing database without running the pending
+	// migrations, e.g. to inspect or roll them back.
+	SkipMigrations bool
+
+	// MaxConn overrides the maximum size of the connection pool when it is not 0.
+	MaxConn uint32
+
+	// MinConn is the number of connections the pool keeps open when idle.
+	MinConn uint32
+
+	// MaxConnLifetime overrides how long a connection is used before it is
+	// closed and replaced when it is not 0.
+	MaxConnLifetime time.Duration
+
+	// HealthCheckPeriod overrides how often idle connections are checked when
+	// it is not 0.
+	HealthCheckPeriod time.Duration
+
+	// StatementTimeout aborts statements which run longer when it is not 0.
+	StatementTimeout time.Duration
+
+	// SlowQueryThreshold logs the statements which run longer, with their SQL,
+	// parameters and API endpoint, when it is not 0.
+	SlowQueryThreshold time.Duration
+
+	// Schema is the postgres schema of the indexer tables, which is created if
+	// it does not exist. Several indexers, e.g. of different networks, can share a
+	// database in different schemas. Empty uses the default search_path.
+	Schema string
+
+	// ReadConnection is the connection string of a read-only replica which
+	// serves the queries of the API while the import writes to the primary.
+	ReadConnection string
+
+	// ReplicaMaxLag is the number of rounds the replica may be behind the
+	// primary before queries fall back to the primary.
+	ReplicaMaxLag uint64
+
+	// PgBouncerCompat avoids the session state which a PgBouncer in transaction
+	// pooling mode does not keep: the statements are not prepared and rounds
+	// are not notified to listeners, which poll instead. Schema and
+	// StatementTimeout can't be set, they are startup parameters.
+	PgBouncerCompat bool
+
+	// CockroachCompat runs the indexer on CockroachDB: the tables are created
+	// for it, rounds are not notified to listeners, which poll instead, and the
+	// postgres specific maintenance and server settings are not available.
+	// Transactions are retried when CockroachDB asks the client to.
+	CockroachCompat bool
+
+	// CompressTxnBytes compresses large encoded transactions with zstd when they
+	// are written. Transactions are decompressed when read either way.
+	CompressTxnBytes bool
+
+	// MigrationWorkers is the number of parts of a table which data migrations
+	// process in parallel, 0 for the backend's default.
+	MigrationWorkers int
+
+	// MigrationDiskSpace is the free disk space of the database in bytes, 0 if
+	// it is not known. Migrations whose tables would not fit don't start.
+	MigrationDiskSpace uint64
+
+	// SQLMigrationsDir is a directory of SQL scripts supplied by the operator,
+	// e.g. for site specific indexes, which run as migrations after the
+	// built-in ones. Empty for none.
+	SQLMigrationsDir string
+
+	// AccountCacheSize is the number of accounts and creators which the import
+	// keeps in memory across rounds, 0 disables the cache.
+	AccountCacheSize int
+
+	// LookupBatchSize is the maximum number of accounts which the import reads
+	// from the database in one batch, 0 for no limit.
+	LookupBatchSize int
+
+	// LookupPipeline reads the assets and applications of the accounts in the
+	// same batch as the accounts, which saves a round trip per batch.
+	LookupPipeline bool
+
+	// TxnRules decides which transactions the import stores, nil stores all of
+	// them. The account state is updated with all the transactions either way.
+	TxnRules *TxnRules
+
+	// AddressScope makes the import only store the transactions which involve
+	// it, nil stores all of them. The account state is updated with all the
+	// transactions either way, evaluating the blocks requires it.
+	AddressScope *AddressScope
+}
+
+// MigrationInfo describes a migration returned by Migrations().
+type MigrationInfo struct {
+	ID          int
+	Description string
+	// Blocking migrations make the database unavailable until they finish.
+	Blocking bool
+	// Applied is true if the migration completed.
+	Applied bool
+	// Reversible is true if RollbackMigrations() can undo the migration.
+	Reversible bool
+}
+
+// Retention is the history which is available after pruning.
+type Retention struct {
+	// EarliestRound is the earliest round whose transactions are available, 0
+	// unless transactions were pruned.
+	EarliestRound uint64
+
+	// EarliestParticipationRound is the earliest round whose transactions can
+	// be searched by address. It is at least EarliestRound.
+	EarliestParticipationRound uint64
 }
 
 // Health is the response object that IndexerDb objects need to return from the Health method.
@@ -258,3 +522,119 @@ type Health struct {
 	DBAvailable bool                    `json:"db-available"`
 	Error       string                  `json:"error"`
 }
+
+// BackfillOptions are the parameters of a Backfill run.
+type BackfillOptions struct {
+	// Feature selects the derived data which is recomputed, for example "sig-type".
+	Feature string
+
+	// MinRound and MaxRound are the inclusive range of rounds to process.
+	MinRound uint64
+	MaxRound uint64
+
+	// ChunkSize is the number of rounds processed in each database transaction.
+	ChunkSize uint64
+}
+
+// MaintenanceOptions are when RunMaintenance() maintains a table. A table is
+// maintained when either condition is met, 0 disables a condition.
+type MaintenanceOptions struct {
+	// Interval is the longest time since a table was last analyzed, by the
+	// indexer or by autovacuum.
+	Interval time.Duration
+
+	// ChangeThreshold is the number of rows changed since a table was last
+	// analyzed.
+	ChangeThreshold uint64
+
+	// Vacuum vacuums the tables in addition to analyzing them.
+	Vacuum bool
+}
+
+// Tables written by DumpCSV().
+const (
+	DumpAccounts = "accounts"
+	DumpAssets   = "assets"
+	DumpBalances = "balances"
+)
+
+// RederivedTable compares a table rebuilt by Rederive() with the stored one. A
+// row which differs is counted both as missing and as extra.
+type RederivedTable struct {
+	// Table is the table which was rebuilt.
+	Table string `json:"table"`
+
+	// Rows is the number of rows of the rebuilt table.
+	Rows int64 `json:"rows"`
+
+	// Missing is the number of stored rows which were not rebuilt.
+	Missing int64 `json:"missing"`
+
+	// Extra is the number of rebuilt rows which were not stored.
+	Extra int64 `json:"extra"`
+}
+
+// Webhook is a URL to which the imported transactions which match a filter are
+// posted.
+type Webhook struct {
+	// ID identifies the webhook.
+	ID string `json:"id"`
+
+	// URL receives the transactions.
+	URL string `json:"url"`
+
+	// Filter selects the transactions which are posted.
+	Filter TxnRule `json:"filter"`
+
+	// Secret is the key of the HMAC-SHA256 signature of the requests.
+	Secret string `json:"secret,omitempty"`
+}
+
+// ErrorWebhookExists is returned by AddWebhook() when the id is registered.
+var ErrorWebhookExists = errors.New("webhook exists")
+
+// OrphanedRows is the result of one orphaned row check performed by DeleteOrphanedRows.
+type OrphanedRows struct {
+	// Table is the table which was checked.
+	Table string `json:"table"`
+
+	// Description explains what makes these rows orphaned.
+	Description string `json:"description"`
+
+	// Count is the number of rows found (or deleted).
+	Count int64 `json:"count"`
+}
+
+// ServerSettings are the database server settings relevant to the indexer and the
+// connection pool size the indexer was configured with.
+type ServerSettings struct {
+	MaxConnections               int64  `json:"max-connections"`
+	SuperuserReservedConnections int64  `json:"superuser-reserved-connections"`
+	ConnectionsInUse             int64  `json:"connections-in-use"`
+	WorkMem                      string `json:"work-mem"`
+	SharedBuffers                string `json:"shared-buffers"`
+
+	// PoolMaxConnections is the maximum number of connections the indexer opens.
+	PoolMaxConnections int32 `json:"pool-max-connections"`
+}
+
+// QueryPlan describes how the database would execute a query.
+type QueryPlan struct {
+	// Tables are the tables which would be read.
+	Tables []string `json:"tables"`
+
+	// Indexes are the indexes which would be used.
+	Indexes []string `json:"indexes"`
+
+	// SequentialScans are the tables which would be read in full.
+	SequentialScans []string `json:"sequential-scans"`
+
+	// EstimatedCost is the planner's cost estimate, in arbitrary units.
+	EstimatedCost float64 `json:"estimated-cost"`
+
+	// EstimatedRows is the planner's estimate of the number of rows returned.
+	EstimatedRows float64 `json:"estimated-rows"`
+
+	// Suggestions are hints on how to change the query to make it cheaper.
+	Suggestions []string `json:"suggestions"`
+}
@@ -0,0 +1,862 @@
+// Package memory is an idb.IndexerDb which keeps everything in memory. Blocks
+// are evaluated and queried like in postgres, so projects which embed the API
+// handlers can test them with imported blocks without a database server. The
+// state is lost when the process exits.
+package memory
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/csv"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/algorand/go-algorand/config"
+	"github.com/algorand/go-algorand/crypto"
+	"github.com/algorand/go-algorand/data/basics"
+	"github.com/algorand/go-algorand/data/bookkeeping"
+	"github.com/algorand/go-algorand/data/transactions"
+	"github.com/algorand/go-algorand/ledger"
+	"github.com/algorand/go-algorand/ledger/ledgercore"
+	"github.com/algorand/go-algorand/protocol"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/algorand/indexer/idb"
+)
+
+// The rows mirror the postgres tables, including the rounds when they were
+// created and closed and whether they are deleted.
+
+type accountRow struct {
+	data      basics.AccountData
+	createdAt uint64
+	closedAt  *uint64
+	deleted   bool
+	keytype   *string
+}
+
+type assetRow struct {
+	creator   basics.Address
+	params    basics.AssetParams
+	createdAt uint64
+	closedAt  *uint64
+	deleted   bool
+}
+
+type holdingRow struct {
+	amount    uint64
+	frozen    bool
+	createdAt uint64
+	closedAt  *uint64
+	deleted   bool
+}
+
+type appRow struct {
+	creator   basics.Address
+	params    basics.AppParams
+	createdAt uint64
+	closedAt  *uint64
+	deleted   bool
+}
+
+type localStateRow struct {
+	state     basics.AppLocalState
+	createdAt uint64
+	closedAt  *uint64
+	deleted   bool
+}
+
+type txnRow struct {
+	round     uint64
+	intra     int
+	typeenum  idb.TxnTypeEnum
+	asset     uint64
+	txid      string
+	stxnad    transactions.SignedTxnWithAD
+	txnbytes  []byte
+	extra     idb.TxnExtra
+	roundtime time.Time
+}
+
+// IndexerDb is an idb.IndexerDb implementation which keeps the imported blocks
+// and the account state in memory.
+type IndexerDb struct {
+	log *log.Logger
+
+	mu sync.RWMutex
+
+	// nextRound is nil until the genesis is loaded.
+	nextRound *uint64
+	// genesisHash is recorded by the first block, every other block must have
+	// it.
+	genesisHash      *crypto.Digest
+	specialAddresses *transactions.SpecialAddresses
+	headers          map[uint64]bookkeeping.BlockHeader
+	// txns are ordered by round and intra.
+	txns        []txnRow
+	accounts    map[basics.Address]*accountRow
+	assets      map[basics.AssetIndex]*assetRow
+	holdings    map[ledgercore.AccountAsset]*holdingRow
+	apps        map[basics.AppIndex]*appRow
+	localStates map[ledgercore.AccountApp]*localStateRow
+	retention   idb.Retention
+	blockHooks  []idb.BlockHook
+	// exportRounds are the next rounds of the exporters.
+	exportRounds map[string]uint64
+	webhooks     []idb.Webhook
+	// paginationSecret is generated by the first PaginationSecret() call.
+	paginationSecret []byte
+
+	// roundAdded is closed and replaced when rounds are added, to wake up
+	// ListenRounds().
+	roundAdded chan struct{}
+}
+
+// MakeIndexerDb creates an empty IndexerDb. A nil `logger` logs to stdout.
+func MakeIndexerDb(logger *log.Logger) *IndexerDb {
+	if logger == nil {
+		logger = log.New()
+	}
+	return &IndexerDb{
+		log:          logger,
+		headers:      make(map[uint64]bookkeeping.BlockHeader),
+		accounts:     make(map[basics.Address]*accountRow),
+		assets:       make(map[basics.AssetIndex]*assetRow),
+		holdings:     make(map[ledgercore.AccountAsset]*holdingRow),
+		apps:         make(map[basics.AppIndex]*appRow),
+		localStates:  make(map[ledgercore.AccountApp]*localStateRow),
+		roundAdded:   make(chan struct{}),
+		exportRounds: make(map[string]uint64),
+	}
+}
+
+// notifyRounds wakes up the listeners of ListenRounds(). The caller holds the
+// write lock.
+func (db *IndexerDb) notifyRounds() {
+	close(db.roundAdded)
+	db.roundAdded = make(chan struct{})
+}
+
+// AddBlock is part of idb.IndexerDb.
+func (db *IndexerDb) AddBlock(block *bookkeeping.Block) error {
+	db.log.Printf("adding block %d", block.Round())
+
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	delta, err := db.addBlock(block)
+	if err != nil {
+		return fmt.Errorf("AddBlock() err: %w", err)
+	}
+	db.notifyRounds()
+
+	for _, hook := range db.blockHooks {
+		hook.AfterBlock(block, delta)
+	}
+	return nil
+}
+
+// AddBlocks is part of idb.IndexerDb. Unlike in postgres the blocks are not
+// added atomically, a block which fails leaves the blocks before it added.
+func (db *IndexerDb) AddBlocks(blocks []*bookkeeping.Block) error {
+	if len(blocks) == 0 {
+		return nil
+	}
+	db.log.Printf(
+		"adding blocks %d-%d", blocks[0].Round(), blocks[len(blocks)-1].Round())
+
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	defer db.notifyRounds()
+
+	for _, block := range blocks {
+		delta, err := db.addBlock(block)
+		if err != nil {
+			return fmt.Errorf("AddBlocks() err: %w", err)
+		}
+		for _, hook := range db.blockHooks {
+			hook.AfterBlock(block, delta)
+		}
+	}
+	return nil
+}
+
+// AddBlockHook is part of idb.IndexerDb. The hooks are called with the write
+// lock held, so they must not query `db`.
+func (db *IndexerDb) AddBlockHook(hook idb.BlockHook) {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	db.blockHooks = append(db.blockHooks, hook)
+}
+
+// callBeforeBlock calls BeforeBlock() of each block hook.
+func (db *IndexerDb) callBeforeBlock(block *bookkeeping.Block, delta ledgercore.StateDelta) error {
+	for _, hook := range db.blockHooks {
+		err := hook.BeforeBlock(block, delta)
+		if err != nil {
+			return fmt.Errorf("callBeforeBlock() round %d err: %w", block.Round(), err)
+		}
+	}
+	return nil
+}
+
+// addBlock evaluates `block` and writes it. Nothing is written if it fails. The
+// caller holds the write lock. It returns the state delta of the block.
+func (db *IndexerDb) addBlock(block *bookkeeping.Block) (ledgercore.StateDelta, error) {
+	if db.nextRound == nil {
+		return ledgercore.StateDelta{}, fmt.Errorf("addBlock() import state not initialized")
+	}
+	if block.Round() != basics.Round(*db.nextRound) {
+		return ledgercore.StateDelta{}, fmt.Errorf(
+			"addBlock() adding block round %d but next round to account is %d",
+			block.Round(), *db.nextRound)
+	}
+	if db.genesisHash != nil && block.GenesisHash() != *db.genesisHash {
+		return ledgercore.StateDelta{}, fmt.Errorf(
+			"addBlock() block round %d has genesis hash %s but the database has %s: %w",
+			block.Round(), block.GenesisHash(), *db.genesisHash, idb.ErrorGenesisMismatch)
+	}
+
+	if block.Round() == basics.Round(0) {
+		// Block 0 is special, we cannot run the evaluator on it.
+		// It contains no transactions, so just write the header.
+		err := db.callBeforeBlock(block, ledgercore.StateDelta{})
+		if err != nil {
+			return ledgercore.StateDelta{}, fmt.Errorf("addBlock() err: %w", err)
+		}
+		err = db.writeBlock(block, nil, ledgercore.StateDelta{})
+		if err != nil {
+			return ledgercore.StateDelta{}, fmt.Errorf("addBlock() err: %w", err)
+		}
+		*db.nextRound++
+		return ledgercore.StateDelta{}, nil
+	}
+
+	proto, ok := config.Consensus[block.BlockHeader.CurrentProtocol]
+	if !ok {
+		return ledgercore.StateDelta{}, fmt.Errorf(
+			"addBlock() cannot find proto version %s", block.BlockHeader.CurrentProtocol)
+	}
+	proto.EnableAssetCloseAmount = true
+
+	l := makeLedgerForEvaluator(db, block)
+	delta, modifiedTxns, err := ledger.Eval(l, block, proto)
+	if err != nil {
+		return ledgercore.StateDelta{}, fmt.Errorf("addBlock() eval err: %w", err)
+	}
+
+	err = db.callBeforeBlock(block, delta)
+	if err != nil {
+		return ledgercore.StateDelta{}, fmt.Errorf("addBlock() err: %w", err)
+	}
+	err = db.writeBlock(block, modifiedTxns, delta)
+	if err != nil {
+		return ledgercore.StateDelta{}, fmt.Errorf("addBlock() err: %w", err)
+	}
+	*db.nextRound++
+	return delta, nil
+}
+
+// transactionAssetID returns the ID of the creatable referenced in the given
+// transaction (0 if not an asset or app transaction).
+func transactionAssetID(block *bookkeeping.Block, intra uint64, typeenum idb.TxnTypeEnum) uint64 {
+	assetid := uint64(0)
+	txn := block.Payset[intra].Txn
+
+	switch typeenum {
+	case idb.TypeEnumAssetConfig:
+		assetid = uint64(txn.ConfigAsset)
+		if assetid == 0 {
+			assetid = block.TxnCounter - uint64(len(block.Payset)) + intra + 1
+		}
+	case idb.TypeEnumAssetTransfer:
+		assetid = uint64(txn.XferAsset)
+	case idb.TypeEnumAssetFreeze:
+		assetid = uint64(txn.FreezeAsset)
+	case idb.TypeEnumApplication:
+		assetid = uint64(txn.ApplicationID)
+		if assetid == 0 {
+			assetid = block.TxnCounter - uint64(len(block.Payset)) + intra + 1
+		}
+	}
+
+	return assetid
+}
+
+// makeTxnRows decodes the transactions of `block`. `modifiedTxns` contains the
+// enhanced apply data generated by the evaluator.
+func makeTxnRows(block *bookkeeping.Block, modifiedTxns []transactions.SignedTxnInBlock) ([]txnRow, error) {
+	rows := make([]txnRow, 0, len(block.Payset))
+	for i, stib := range block.Payset {
+		var stxnad transactions.SignedTxnWithAD
+		var err error
+		// This function makes sure to set correct genesis information so we can get the
+		// correct transaction hash.
+		stxnad.SignedTxn, stxnad.ApplyData, err = block.BlockHeader.DecodeSignedTxn(stib)
+		if err != nil {
+			return nil, fmt.Errorf("makeTxnRows() decode signed txn err: %w", err)
+		}
+
+		typeenum, ok := idb.GetTypeEnum(stxnad.Txn.Type)
+		if !ok {
+			return nil, fmt.Errorf("makeTxnRows() get type enum")
+		}
+		rows = append(rows, txnRow{
+			round:    uint64(block.Round()),
+			intra:    i,
+			typeenum: typeenum,
+			asset:    transactionAssetID(block, uint64(i), typeenum),
+			txid:     stxnad.Txn.ID().String(),
+			stxnad:   stxnad,
+			txnbytes: protocol.Encode(&stxnad),
+			extra: idb.TxnExtra{
+				AssetCloseAmount: modifiedTxns[i].ApplyData.AssetClosingAmount,
+			},
+			roundtime: time.Unix(block.TimeStamp, 0).UTC(),
+		})
+	}
+	return rows, nil
+}
+
+// writeBlock writes the block and the accounting state delta like the postgres
+// writer does. The caller holds the write lock.
+func (db *IndexerDb) writeBlock(block *bookkeeping.Block, modifiedTxns []transactions.SignedTxnInBlock, delta ledgercore.StateDelta) error {
+	// Everything which can fail comes first, so that nothing is written then.
+	rows, err := makeTxnRows(block, modifiedTxns)
+	if err != nil {
+		return fmt.Errorf("writeBlock() err: %w", err)
+	}
+	sigtypes := make([]*string, len(block.Payset))
+	for i := range block.Payset {
+		if block.Payset[i].Txn.RekeyTo == (basics.Address{}) {
+			sigtype, err := idb.SignatureType(&block.Payset[i].SignedTxn)
+			if err != nil {
+				return fmt.Errorf("writeBlock() err: %w", err)
+			}
+			s := string(sigtype)
+			sigtypes[i] = &s
+		}
+	}
+
+	round := uint64(block.Round())
+	specialAddresses := transactions.SpecialAddresses{
+		FeeSink:     block.FeeSink,
+		RewardsPool: block.RewardsPool,
+	}
+	if db.genesisHash == nil {
+		genesisHash := block.GenesisHash()
+		db.genesisHash = &genesisHash
+	}
+	db.headers[round] = block.BlockHeader
+	db.specialAddresses = &specialAddresses
+	db.txns = append(db.txns, rows...)
+
+	for i := 0; i < delta.Accts.Len(); i++ {
+		address, accountData := delta.Accts.GetByIdx(i)
+		// Indexer currently doesn't support special accounts.
+		if (address != specialAddresses.FeeSink) &&
+			(address != specialAddresses.RewardsPool) {
+			db.writeAccountData(round, address, accountData)
+		}
+	}
+	for index, creatable := range delta.Creatables {
+		// If deleted.
+		if !creatable.Created {
+			if creatable.Ctype == basics.AssetCreatable {
+				db.deleteAsset(round, basics.AssetIndex(index), creatable.Creator)
+			} else {
+				db.deleteApp(round, basics.AppIndex(index), creatable.Creator)
+			}
+		}
+	}
+	for aa, created := range delta.ModifiedAssetHoldings {
+		if !created {
+			db.deleteHolding(round, aa)
+		}
+	}
+	for aa, created := range delta.ModifiedAppLocalStates {
+		if !created {
+			db.deleteLocalState(round, aa)
+		}
+	}
+
+	for i := range block.Payset {
+		if row, ok := db.accounts[block.Payset[i].Txn.Sender]; ok {
+			row.keytype = sigtypes[i]
+		}
+	}
+	return nil
+}
+
+// writeAccountData upserts the account and its resources. Like in postgres, the
+// round when a row was created is kept when it is updated.
+func (db *IndexerDb) writeAccountData(round uint64, address basics.Address, accountData basics.AccountData) {
+	for assetid, params := range accountData.AssetParams {
+		row, ok := db.assets[assetid]
+		if !ok {
+			row = &assetRow{createdAt: round}
+			db.assets[assetid] = row
+		}
+		row.creator = address
+		row.params = params
+		row.deleted = false
+	}
+	for assetid, holding := range accountData.Assets {
+		key := ledgercore.AccountAsset{Address: address, Asset: assetid}
+		row, ok := db.holdings[key]
+		if !ok {
+			row = &holdingRow{createdAt: round}
+			db.holdings[key] = row
+		}
+		row.amount = holding.Amount
+		row.frozen = holding.Frozen
+		row.deleted = false
+	}
+	for appid, params := range accountData.AppParams {
+		row, ok := db.apps[appid]
+		if !ok {
+			row = &appRow{createdAt: round}
+			db.apps[appid] = row
+		}
+		row.creator = address
+		row.params = cloneAppParams(params)
+		row.deleted = false
+	}
+	for appid, state := range accountData.AppLocalStates {
+		key := ledgercore.AccountApp{Address: address, App: appid}
+		row, ok := db.localStates[key]
+		if !ok {
+			row = &localStateRow{createdAt: round}
+			db.localStates[key] = row
+		}
+		row.state = cloneAppLocalState(state)
+		row.deleted = false
+	}
+
+	row, ok := db.accounts[address]
+	if !ok {
+		row = &accountRow{createdAt: round}
+		db.accounts[address] = row
+	}
+	if accountData.IsZero() {
+		row.data = basics.AccountData{}
+		row.deleted = true
+		row.closedAt = &round
+	} else {
+		row.data = cloneAccountData(accountData)
+		row.deleted = false
+	}
+}
+
+func (db *IndexerDb) deleteAsset(round uint64, index basics.AssetIndex, creator basics.Address) {
+	row, ok := db.assets[index]
+	if !ok {
+		row = &assetRow{createdAt: round}
+		db.assets[index] = row
+	}
+	row.creator = creator
+	row.params = basics.AssetParams{}
+	row.deleted = true
+	row.closedAt = &round
+}
+
+func (db *IndexerDb) deleteApp(round uint64, index basics.AppIndex, creator basics.Address) {
+	row, ok := db.apps[index]
+	if !ok {
+		row = &appRow{createdAt: round}
+		db.apps[index] = row
+	}
+	row.creator = creator
+	row.params = basics.AppParams{}
+	row.deleted = true
+	row.closedAt = &round
+}
+
+func (db *IndexerDb) deleteHolding(round uint64, key ledgercore.AccountAsset) {
+	row, ok := db.holdings[key]
+	if !ok {
+		row = &holdingRow{createdAt: round}
+		db.holdings[key] = row
+	}
+	row.amount = 0
+	row.deleted = true
+	row.closedAt = &round
+}
+
+func (db *IndexerDb) deleteLocalState(round uint64, key ledgercore.AccountApp) {
+	row, ok := db.localStates[key]
+	if !ok {
+		row = &localStateRow{createdAt: round}
+		db.localStates[key] = row
+	}
+	row.state = basics.AppLocalState{}
+	row.deleted = true
+	row.closedAt = &round
+}
+
+// LoadGenesis is part of idb.IndexerDB
+func (db *IndexerDb) LoadGenesis(genesis bookkeeping.Genesis) error {
+	accounts := make(map[basics.Address]*accountRow, len(genesis.Allocation))
+	for ai, alloc := range genesis.Allocation {
+		addr, err := basics.UnmarshalChecksumAddress(alloc.Address)
+		if err != nil {
+			return fmt.Errorf("genesis account[%d] has invalid address, %v", ai, err)
+		}
+		if len(alloc.State.AssetParams) > 0 || len(alloc.State.Assets) > 0 {
+			return fmt.Errorf("genesis account[%d] has unhandled asset", ai)
+		}
+		accounts[addr] = &accountRow{data: cloneAccountData(alloc.State)}
+	}
+
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	for addr, row := range accounts {
+		db.accounts[addr] = row
+	}
+	nextRound := uint64(0)
+	db.nextRound = &nextRound
+	return nil
+}
+
+// GetNextRoundToAccount is part of idb.IndexerDB
+// Returns ErrorNotInitialized if genesis is not loaded.
+func (db *IndexerDb) GetNextRoundToAccount() (uint64, error) {
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+
+	if db.nextRound == nil {
+		return 0, idb.ErrorNotInitialized
+	}
+	return *db.nextRound, nil
+}
+
+// GetGenesisHash is part of idb.IndexerDB
+// Returns ErrorNotInitialized if genesis is not loaded.
+func (db *IndexerDb) GetGenesisHash() (string, error) {
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+
+	if db.nextRound == nil {
+		return "", idb.ErrorNotInitialized
+	}
+	if db.genesisHash == nil {
+		return "", nil
+	}
+	return db.genesisHash.String(), nil
+}
+
+// getMaxRoundAccounted returns the latest imported round. The caller holds the
+// lock. Returns `idb.ErrorNotInitialized` if uninitialized.
+func (db *IndexerDb) getMaxRoundAccounted() (uint64, error) {
+	if db.nextRound == nil {
+		return 0, idb.ErrorNotInitialized
+	}
+	round := *db.nextRound
+	if round > 0 {
+		round--
+	}
+	return round, nil
+}
+
+// GetSpecialAccounts is part of idb.IndexerDb
+func (db *IndexerDb) GetSpecialAccounts() (transactions.SpecialAddresses, error) {
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+
+	if db.specialAddresses == nil {
+		return transactions.SpecialAddresses{},
+			fmt.Errorf("GetSpecialAccounts() no block has been imported")
+	}
+	return *db.specialAddresses, nil
+}
+
+// Health is part of idb.IndexerDB
+func (db *IndexerDb) Health() (idb.Health, error) {
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+
+	round, err := db.getMaxRoundAccounted()
+	if err == idb.ErrorNotInitialized {
+		err = nil
+	}
+	data := map[string]interface{}{
+		"earliest-round": db.retention.EarliestRound,
+	}
+	return idb.Health{Data: &data, Round: round, DBAvailable: true}, err
+}
+
+// DeleteOrphanedRows is part of idb.IndexerDb. Rows can't be orphaned in memory,
+// every row is written with the block which changes it.
+func (db *IndexerDb) DeleteOrphanedRows(ctx context.Context, dryRun bool) ([]idb.OrphanedRows, error) {
+	return nil, nil
+}
+
+// Backfill is part of idb.IndexerDb. The derived data is always complete in
+// memory, there is nothing to backfill.
+func (db *IndexerDb) Backfill(ctx context.Context, opts idb.BackfillOptions) error {
+	return nil
+}
+
+// GetServerSettings is part of idb.IndexerDb
+func (db *IndexerDb) GetServerSettings(ctx context.Context) (idb.ServerSettings, error) {
+	return idb.ServerSettings{}, fmt.Errorf("GetServerSettings() there is no database server")
+}
+
+// ExplainTransactions is part of idb.IndexerDb
+func (db *IndexerDb) ExplainTransactions(ctx context.Context, tf idb.TransactionFilter) (idb.QueryPlan, error) {
+	return idb.QueryPlan{}, fmt.Errorf("ExplainTransactions() there is no query planner")
+}
+
+// RunPendingMigrations is part of idb.IndexerDb. There are no migrations.
+func (db *IndexerDb) RunPendingMigrations() error {
+	return nil
+}
+
+// RollbackMigrations is part of idb.IndexerDb. There are no migrations.
+func (db *IndexerDb) RollbackMigrations(ctx context.Context, count int) ([]string, error) {
+	if count > 0 {
+		return nil, fmt.Errorf("RollbackMigrations() there are no migrations")
+	}
+	return nil, nil
+}
+
+// Migrations is part of idb.IndexerDb. There are no migrations.
+func (db *IndexerDb) Migrations(ctx context.Context) ([]idb.MigrationInfo, error) {
+	return nil, nil
+}
+
+// FlushCaches is part of idb.IndexerDb. There are no caches.
+func (db *IndexerDb) FlushCaches(ctx context.Context) error {
+	return nil
+}
+
+// pruneRange returns the rounds [start, end) to prune from `earliest` towards
+// `round` in a batch of at most `maxRounds`, never including rounds which have
+// not been imported. The caller holds the lock.
+func (db *IndexerDb) pruneRange(earliest, round, maxRounds uint64) (uint64, uint64, error) {
+	if db.nextRound == nil {
+		return 0, 0, idb.ErrorNotInitialized
+	}
+	if round > *db.nextRound {
+		round = *db.nextRound
+	}
+	if round <= earliest {
+		return earliest, earliest, nil
+	}
+	end := round
+	if maxRounds != 0 && end-earliest > maxRounds {
+		end = earliest + maxRounds
+	}
+	return earliest, end, nil
+}
+
+// PruneTransactions is part of idb.IndexerDb
+func (db *IndexerDb) PruneTransactions(ctx context.Context, round uint64, maxRounds uint64) (uint64, error) {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	start, end, err := db.pruneRange(db.retention.EarliestRound, round, maxRounds)
+	if err != nil {
+		return 0, fmt.Errorf("PruneTransactions() err: %w", err)
+	}
+	if end <= start {
+		return start, nil
+	}
+
+	i := 0
+	for i < len(db.txns) && db.txns[i].round < end {
+		i++
+	}
+	db.txns = append([]txnRow(nil), db.txns[i:]...)
+
+	db.retention.EarliestRound = end
+	if db.retention.EarliestParticipationRound < end {
+		db.retention.EarliestParticipationRound = end
+	}
+	return end, nil
+}
+
+// PruneParticipation is part of idb.IndexerDb. The archive has the same format
+// as the one written by postgres.
+func (db *IndexerDb) PruneParticipation(ctx context.Context, round uint64, maxRounds uint64, archive io.Writer) (uint64, error) {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	start, end, err := db.pruneRange(
+		db.retention.EarliestParticipationRound, round, maxRounds)
+	if err != nil {
+		return 0, fmt.Errorf("PruneParticipation() err: %w", err)
+	}
+	if end <= start {
+		return start, nil
+	}
+
+	if archive != nil {
+		w := csv.NewWriter(archive)
+		for i := range db.txns {
+			row := &db.txns[i]
+			if row.round < start || row.round >= end {
+				continue
+			}
+			for _, addr := range transactionParticipants(&row.stxnad.Txn) {
+				// bytea columns are written in hex by COPY.
+				err = w.Write([]string{
+					`\x` + hex.EncodeToString(addr[:]),
+					strconv.FormatUint(row.round, 10),
+					strconv.Itoa(row.intra),
+				})
+				if err != nil {
+					return 0, fmt.Errorf("PruneParticipation() archive err: %w", err)
+				}
+			}
+		}
+		w.Flush()
+		if err := w.Error(); err != nil {
+			return 0, fmt.Errorf("PruneParticipation() archive err: %w", err)
+		}
+	}
+
+	db.retention.EarliestParticipationRound = end
+	return end, nil
+}
+
+// GetRetention is part of idb.IndexerDb
+func (db *IndexerDb) GetRetention(ctx context.Context) (idb.Retention, error) {
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+
+	return db.retention, nil
+}
+
+// ListenRounds is part of idb.IndexerDb
+func (db *IndexerDb) ListenRounds(ctx context.Context, f func(round uint64)) error {
+	db.mu.RLock()
+	nextRound := uint64(0)
+	if db.nextRound != nil {
+		nextRound = *db.nextRound
+	}
+	roundAdded := db.roundAdded
+	db.mu.RUnlock()
+
+	if nextRound > 0 {
+		f(nextRound - 1)
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-roundAdded:
+		}
+
+		db.mu.RLock()
+		latest := *db.nextRound
+		roundAdded = db.roundAdded
+		db.mu.RUnlock()
+
+		for ; nextRound < latest; nextRound++ {
+			f(nextRound)
+		}
+	}
+}
+
+// RunMaintenance is part of idb.IndexerDb. There are no tables to maintain, it
+// returns when the context is done.
+func (db *IndexerDb) RunMaintenance(ctx context.Context, opts idb.MaintenanceOptions) error {
+	<-ctx.Done()
+	return nil
+}
+
+// ExportSnapshot is part of idb.IndexerDb
+func (db *IndexerDb) ExportSnapshot(ctx context.Context, w io.Writer) (uint64, error) {
+	return 0, fmt.Errorf("ExportSnapshot() not supported in memory")
+}
+
+// ImportSnapshot is part of idb.IndexerDb
+func (db *IndexerDb) ImportSnapshot(ctx context.Context, r io.Reader) (uint64, error) {
+	return 0, fmt.Errorf("ImportSnapshot() not supported in memory")
+}
+
+// DumpCSV is part of idb.IndexerDb
+func (db *IndexerDb) DumpCSV(ctx context.Context, table string, w io.Writer) (uint64, error) {
+	return 0, fmt.Errorf("DumpCSV() not supported in memory")
+}
+
+// Rederive is part of idb.IndexerDb
+func (db *IndexerDb) Rederive(ctx context.Context, genesis bookkeeping.Genesis, repair bool) ([]idb.RederivedTable, error) {
+	return nil, fmt.Errorf("Rederive() not supported in memory")
+}
+
+// Reprocess is part of idb.IndexerDb
+func (db *IndexerDb) Reprocess(ctx context.Context, genesis bookkeeping.Genesis, blocks []*bookkeeping.Block) ([]idb.RederivedTable, error) {
+	return nil, fmt.Errorf("Reprocess() not supported in memory")
+}
+
+// GetExportRound is part of idb.IndexerDb
+func (db *IndexerDb) GetExportRound(ctx context.Context, name string) (uint64, error) {
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+	return db.exportRounds[name], nil
+}
+
+// SetExportRound is part of idb.IndexerDb
+func (db *IndexerDb) SetExportRound(ctx context.Context, name string, round uint64) error {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	db.exportRounds[name] = round
+	return nil
+}
+
+// Webhooks is part of idb.IndexerDb
+func (db *IndexerDb) Webhooks(ctx context.Context) ([]idb.Webhook, error) {
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+	return append([]idb.Webhook(nil), db.webhooks...), nil
+}
+
+// AddWebhook is part of idb.IndexerDb
+func (db *IndexerDb) AddWebhook(ctx context.Context, hook idb.Webhook) error {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	for _, h := range db.webhooks {
+		if h.ID == hook.ID {
+			return fmt.Errorf("AddWebhook() err: %w", idb.ErrorWebhookExists)
+		}
+	}
+	db.webhooks = append(db.webhooks, hook)
+	return nil
+}
+
+// DeleteWebhook is part of idb.IndexerDb
+func (db *IndexerDb) DeleteWebhook(ctx context.Context, id string) (bool, error) {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	for i, h := range db.webhooks {
+		if h.ID == id {
+			db.webhooks = append(db.webhooks[:i:i], db.webhooks[i+1:]...)
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// PaginationSecret is part of idb.IndexerDb
+func (db *IndexerDb) PaginationSecret(ctx context.Context) ([]byte, error) {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	if db.paginationSecret == nil {
+		secret := make([]byte, 32)
+		_, err := rand.Read(secret)
+		if err != nil {
+			return nil, fmt.Errorf("PaginationSecret() err: %w", err)
+		}
+		db.paginationSecret = secret
+	}
+	return db.paginationSecret, nil
+}
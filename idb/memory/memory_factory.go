@@ -0,0 +1,27 @@
+package memory
+
+import (
+	log "github.com/sirupsen/logrus"
+
+	"github.com/algorand/indexer/idb"
+)
+
+type memoryFactory struct {
+}
+
+// Name is part of the IndexerFactory interface.
+func (mf memoryFactory) Name() string {
+	return "memory"
+}
+
+// Build is part of the IndexerFactory interface. The database is empty and
+// available right away, `arg` and `opts` are ignored.
+func (mf memoryFactory) Build(arg string, opts idb.IndexerDbOptions, log *log.Logger) (idb.IndexerDb, chan struct{}, error) {
+	ch := make(chan struct{})
+	close(ch)
+	return MakeIndexerDb(log), ch, nil
+}
+
+func init() {
+	idb.RegisterFactory("memory", &memoryFactory{})
+}
@@ -0,0 +1,167 @@
+package memory
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/algorand/go-algorand/config"
+	"github.com/algorand/go-algorand/crypto"
+	"github.com/algorand/go-algorand/data/basics"
+	"github.com/algorand/go-algorand/data/bookkeeping"
+	"github.com/algorand/go-algorand/data/transactions"
+	"github.com/algorand/go-algorand/ledger"
+	"github.com/algorand/go-algorand/ledger/ledgercore"
+)
+
+// ledgerForEvaluator implements the ledgerForEvaluator interface from
+// go-algorand ledger/eval.go on top of the in-memory tables. It is used while
+// the write lock is held.
+type ledgerForEvaluator struct {
+	db          *IndexerDb
+	genesisHash crypto.Digest
+	// Indexer currently does not store the balances of special account, but
+	// go-algorand's eval checks that they satisfy the minimum balance. We thus return
+	// a fake amount.
+	specialAddresses transactions.SpecialAddresses
+}
+
+func makeLedgerForEvaluator(db *IndexerDb, block *bookkeeping.Block) ledgerForEvaluator {
+	return ledgerForEvaluator{
+		db:          db,
+		genesisHash: block.GenesisHash(),
+		specialAddresses: transactions.SpecialAddresses{
+			FeeSink:     block.FeeSink,
+			RewardsPool: block.RewardsPool,
+		},
+	}
+}
+
+// BlockHdr is part of go-algorand's ledgerForEvaluator interface.
+func (l ledgerForEvaluator) BlockHdr(round basics.Round) (bookkeeping.BlockHeader, error) {
+	header, ok := l.db.headers[uint64(round)]
+	if !ok {
+		return bookkeeping.BlockHeader{}, fmt.Errorf("BlockHdr() round %d not found", round)
+	}
+	return header, nil
+}
+
+// CheckDup is part of go-algorand's ledgerForEvaluator interface.
+func (l ledgerForEvaluator) CheckDup(config.ConsensusParams, basics.Round, basics.Round, basics.Round, transactions.Txid, ledger.TxLease) error {
+	// This function is not used by evaluator.
+	return errors.New("CheckDup() not implemented")
+}
+
+// LookupWithoutRewards is part of go-algorand's ledgerForEvaluator interface.
+// The account data is copied, the evaluator must not modify the stored maps.
+func (l ledgerForEvaluator) LookupWithoutRewards(round basics.Round, address basics.Address) (basics.AccountData, basics.Round, error) {
+	// The balance of a special address must pass the minimum balance check in
+	// go-algorand's evaluator, so return a sufficiently large balance.
+	if (address == l.specialAddresses.FeeSink) ||
+		(address == l.specialAddresses.RewardsPool) {
+		var balance uint64 = 1000 * 1000 * 1000 * 1000 * 1000
+		accountData := basics.AccountData{
+			MicroAlgos: basics.MicroAlgos{Raw: balance},
+		}
+		return accountData, round, nil
+	}
+
+	row, ok := l.db.accounts[address]
+	if !ok || row.deleted {
+		return basics.AccountData{}, round, nil
+	}
+	return cloneAccountData(row.data), round, nil
+}
+
+// GetCreatorForRound is part of go-algorand's ledgerForEvaluator interface.
+func (l ledgerForEvaluator) GetCreatorForRound(_ basics.Round, cindex basics.CreatableIndex, ctype basics.CreatableType) (basics.Address, bool, error) {
+	switch ctype {
+	case basics.AssetCreatable:
+		row, ok := l.db.assets[basics.AssetIndex(cindex)]
+		if !ok || row.deleted {
+			return basics.Address{}, false, nil
+		}
+		return row.creator, true, nil
+	case basics.AppCreatable:
+		row, ok := l.db.apps[basics.AppIndex(cindex)]
+		if !ok || row.deleted {
+			return basics.Address{}, false, nil
+		}
+		return row.creator, true, nil
+	default:
+		panic("unknown creatable type")
+	}
+}
+
+// GenesisHash is part of go-algorand's ledgerForEvaluator interface.
+func (l ledgerForEvaluator) GenesisHash() crypto.Digest {
+	return l.genesisHash
+}
+
+// Totals is part of go-algorand's ledgerForEvaluator interface.
+func (l ledgerForEvaluator) Totals(round basics.Round) (ledgercore.AccountTotals, error) {
+	// The evaluator uses totals only for recomputing the rewards pool balance. Indexer
+	// does not currently compute this balance, and we can return an empty struct
+	// here.
+	return ledgercore.AccountTotals{}, nil
+}
+
+// CompactCertVoters is part of go-algorand's ledgerForEvaluator interface.
+func (l ledgerForEvaluator) CompactCertVoters(basics.Round) (*ledger.VotersForRound, error) {
+	// This function is not used by evaluator.
+	return nil, errors.New("CompactCertVoters() not implemented")
+}
+
+func cloneTealKeyValue(tkv basics.TealKeyValue) basics.TealKeyValue {
+	if tkv == nil {
+		return nil
+	}
+	res := make(basics.TealKeyValue, len(tkv))
+	for k, v := range tkv {
+		res[k] = v
+	}
+	return res
+}
+
+func cloneAppParams(params basics.AppParams) basics.AppParams {
+	params.GlobalState = cloneTealKeyValue(params.GlobalState)
+	return params
+}
+
+func cloneAppLocalState(state basics.AppLocalState) basics.AppLocalState {
+	state.KeyValue = cloneTealKeyValue(state.KeyValue)
+	return state
+}
+
+// cloneAccountData copies the maps of `ad` so that the copy can be modified
+// independently.
+func cloneAccountData(ad basics.AccountData) basics.AccountData {
+	if ad.Assets != nil {
+		assets := make(map[basics.AssetIndex]basics.AssetHolding, len(ad.Assets))
+		for k, v := range ad.Assets {
+			assets[k] = v
+		}
+		ad.Assets = assets
+	}
+	if ad.AssetParams != nil {
+		assetParams := make(map[basics.AssetIndex]basics.AssetParams, len(ad.AssetParams))
+		for k, v := range ad.AssetParams {
+			assetParams[k] = v
+		}
+		ad.AssetParams = assetParams
+	}
+	if ad.AppLocalStates != nil {
+		localStates := make(map[basics.AppIndex]basics.AppLocalState, len(ad.AppLocalStates))
+		for k, v := range ad.AppLocalStates {
+			localStates[k] = cloneAppLocalState(v)
+		}
+		ad.AppLocalStates = localStates
+	}
+	if ad.AppParams != nil {
+		appParams := make(map[basics.AppIndex]basics.AppParams, len(ad.AppParams))
+		for k, v := range ad.AppParams {
+			appParams[k] = cloneAppParams(v)
+		}
+		ad.AppParams = appParams
+	}
+	return ad
+}
@@ -0,0 +1,1035 @@
+package memory
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/algorand/go-algorand/config"
+	"github.com/algorand/go-algorand/data/basics"
+	"github.com/algorand/go-algorand/data/bookkeeping"
+	"github.com/algorand/go-algorand/data/transactions"
+	"github.com/algorand/go-algorand/ledger/ledgercore"
+
+	models "github.com/algorand/indexer/api/generated/v2"
+	"github.com/algorand/indexer/idb"
+	"github.com/algorand/indexer/util"
+)
+
+// The queries select their rows while holding the read lock, so that they see
+// the state of one round like the postgres queries which run in a repeatable
+// read transaction. The rows are sent to the channel after the lock is released.
+
+// transactionParticipants returns the addresses by which a transaction can be
+// searched, the same as the rows of the postgres txn_participation table.
+func transactionParticipants(txn *transactions.Transaction) []basics.Address {
+	res := make([]basics.Address, 0, 7)
+
+	add := func(address basics.Address) {
+		if address.IsZero() {
+			return
+		}
+		for _, p := range res {
+			if address == p {
+				return
+			}
+		}
+		res = append(res, address)
+	}
+
+	add(txn.Sender)
+	add(txn.Receiver)
+	add(txn.CloseRemainderTo)
+	add(txn.AssetSender)
+	add(txn.AssetReceiver)
+	add(txn.AssetCloseTo)
+	add(txn.FreezeAccount)
+
+	return res
+}
+
+func isParticipant(txn *transactions.Transaction, address basics.Address) bool {
+	for _, p := range transactionParticipants(txn) {
+		if p == address {
+			return true
+		}
+	}
+	return false
+}
+
+func matchAddressRole(role idb.AddressRole, txn *transactions.Transaction, address basics.Address) bool {
+	return (role&idb.AddressRoleSender != 0 && txn.Sender == address) ||
+		(role&idb.AddressRoleReceiver != 0 && txn.Receiver == address) ||
+		(role&idb.AddressRoleCloseRemainderTo != 0 && txn.CloseRemainderTo == address) ||
+		(role&idb.AddressRoleAssetSender != 0 && txn.AssetSender == address) ||
+		(role&idb.AddressRoleAssetReceiver != 0 && txn.AssetReceiver == address) ||
+		(role&idb.AddressRoleAssetCloseTo != 0 && txn.AssetCloseTo == address) ||
+		(role&idb.AddressRoleFreeze != 0 && txn.FreezeAccount == address)
+}
+
+func matchSigType(sigtype idb.SigType, stxn *transactions.SignedTxn) bool {
+	switch sigtype {
+	case idb.Sig:
+		return !stxn.Sig.Blank()
+	case idb.Msig:
+		return !stxn.Msig.Blank()
+	case idb.Lsig:
+		return !stxn.Lsig.Blank()
+	}
+	return false
+}
+
+// matchTransaction returns whether `row` passes the filters of `tf` other than
+// the next token and the limit. The caller holds the lock.
+//
+// As in postgres, where a zero amount is left out of the stored json and
+// compares as NULL, transactions with a zero amount never match the amount
+// filters.
+func (db *IndexerDb) matchTransaction(tf *idb.TransactionFilter, row *txnRow) bool {
+	stxn := &row.stxnad
+	txn := &stxn.Txn
+
+	if tf.Address != nil {
+		var address basics.Address
+		if len(tf.Address) != len(address) {
+			return false
+		}
+		copy(address[:], tf.Address)
+		// The participation of pruned rounds is not searchable.
+		if row.round < db.retention.EarliestParticipationRound ||
+			!isParticipant(txn, address) {
+			return false
+		}
+		if tf.AddressRole != 0 && !matchAddressRole(tf.AddressRole, txn, address) {
+			return false
+		}
+	}
+	if tf.MinRound != 0 && row.round < tf.MinRound {
+		return false
+	}
+	if tf.MaxRound != 0 && row.round > tf.MaxRound {
+		return false
+	}
+	if !tf.BeforeTime.IsZero() && !row.roundtime.Before(tf.BeforeTime) {
+		return false
+	}
+	if !tf.AfterTime.IsZero() && !row.roundtime.After(tf.AfterTime) {
+		return false
+	}
+	if tf.AssetID != 0 && row.asset != tf.AssetID {
+		return false
+	}
+	if tf.ApplicationID != 0 && row.asset != tf.ApplicationID {
+		return false
+	}
+	amount := txn.AssetAmount
+	if tf.AssetAmountGT != nil && (amount == 0 || amount <= *tf.AssetAmountGT) {
+		return false
+	}
+	if tf.AssetAmountLT != nil && (amount == 0 || amount >= *tf.AssetAmountLT) {
+		return false
+	}
+	if tf.TypeEnum != 0 && row.typeenum != tf.TypeEnum {
+		return false
+	}
+	if len(tf.Txid) != 0 && row.txid != tf.Txid {
+		return false
+	}
+	if tf.Round != nil && row.round != *tf.Round {
+		return false
+	}
+	intra := uint64(row.intra)
+	if tf.Offset != nil && intra != *tf.Offset {
+		return false
+	}
+	if tf.OffsetLT != nil && intra >= *tf.OffsetLT {
+		return false
+	}
+	if tf.OffsetGT != nil && intra <= *tf.OffsetGT {
+		return false
+	}
+	if len(tf.SigType) != 0 && !matchSigType(tf.SigType, &stxn.SignedTxn) {
+		return false
+	}
+	if len(tf.NotePrefix) > 0 && !bytes.HasPrefix(txn.Note, tf.NotePrefix) {
+		return false
+	}
+	algos := txn.Amount.Raw
+	if tf.AlgosGT != nil && (algos == 0 || algos <= *tf.AlgosGT) {
+		return false
+	}
+	if tf.AlgosLT != nil && (algos == 0 || algos >= *tf.AlgosLT) {
+		return false
+	}
+	closeAmount := stxn.ClosingAmount.Raw
+	effective := closeAmount + algos
+	if tf.EffectiveAmountGT != nil &&
+		(closeAmount == 0 || algos == 0 || effective <= *tf.EffectiveAmountGT) {
+		return false
+	}
+	if tf.EffectiveAmountLT != nil &&
+		(closeAmount == 0 || algos == 0 || effective >= *tf.EffectiveAmountLT) {
+		return false
+	}
+	if tf.RekeyTo != nil && (*tf.RekeyTo) && txn.RekeyTo.IsZero() {
+		return false
+	}
+	return true
+}
+
+func makeTxnRow(row *txnRow) idb.TxnRow {
+	return idb.TxnRow{
+		Round:     row.round,
+		RoundTime: row.roundtime,
+		Intra:     row.intra,
+		TxnBytes:  row.txnbytes,
+		AssetID:   row.asset,
+		Extra:     row.extra,
+	}
+}
+
+// selectTransactions returns the transactions which match `tf`. Searches by
+// address return the newest transactions first, others the oldest first. The
+// caller holds the lock.
+func (db *IndexerDb) selectTransactions(tf idb.TransactionFilter) ([]idb.TxnRow, error) {
+	if tf.AssetID != 0 && tf.ApplicationID != 0 && tf.AssetID != tf.ApplicationID {
+		return nil, fmt.Errorf("txn query err cannot search both assetid and appid")
+	}
+
+	descending := tf.Address != nil
+	var nextRound uint64
+	var nextIntra uint64
+	if len(tf.NextToken) > 0 {
+		round, intra, err := idb.DecodeTxnRowNext(tf.NextToken)
+		if err != nil {
+			return nil, err
+		}
+		nextRound = round
+		nextIntra = uint64(intra)
+	}
+	// afterNext returns whether a row comes after the next token in the order of
+	// the results.
+	afterNext := func(row *txnRow) bool {
+		if len(tf.NextToken) == 0 {
+			return true
+		}
+		if descending {
+			return row.round < nextRound ||
+				(row.round == nextRound && uint64(row.intra) < nextIntra)
+		}
+		return row.round > nextRound ||
+			(row.round == nextRound && uint64(row.intra) > nextIntra)
+	}
+
+	var results []idb.TxnRow
+	for i := range db.txns {
+		row := &db.txns[i]
+		if descending {
+			row = &db.txns[len(db.txns)-1-i]
+		}
+		if !afterNext(row) || !db.matchTransaction(&tf, row) {
+			continue
+		}
+		results = append(results, makeTxnRow(row))
+		if tf.Limit != 0 && uint64(len(results)) >= tf.Limit {
+			break
+		}
+	}
+	return results, nil
+}
+
+// GetBlock is part of idb.IndexerDB
+func (db *IndexerDb) GetBlock(ctx context.Context, round uint64, options idb.GetBlockOptions) (bookkeeping.BlockHeader, []idb.TxnRow, error) {
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+
+	blockHeader, ok := db.headers[round]
+	if !ok {
+		return bookkeeping.BlockHeader{}, nil, fmt.Errorf("GetBlock() round %d not found", round)
+	}
+	if !options.Transactions {
+		return blockHeader, nil, nil
+	}
+
+	transactions, err := db.selectTransactions(idb.TransactionFilter{Round: &round})
+	if err != nil {
+		return bookkeeping.BlockHeader{}, nil, err
+	}
+	if transactions == nil {
+		transactions = make([]idb.TxnRow, 0)
+	}
+	return blockHeader, transactions, nil
+}
+
+// GetBlockWithTransactions is part of idb.IndexerDB
+func (db *IndexerDb) GetBlockWithTransactions(ctx context.Context, round uint64) (bookkeeping.BlockHeader, []idb.TxnRow, error) {
+	return db.GetBlock(ctx, round, idb.GetBlockOptions{Transactions: true})
+}
+
+// Transactions is part of idb.IndexerDB
+func (db *IndexerDb) Transactions(ctx context.Context, tf idb.TransactionFilter) (<-chan idb.TxnRow, uint64) {
+	out := make(chan idb.TxnRow, 1)
+
+	db.mu.RLock()
+	round, err := db.getMaxRoundAccounted()
+	var rows []idb.TxnRow
+	if err == nil {
+		rows, err = db.selectTransactions(tf)
+	}
+	db.mu.RUnlock()
+
+	if err != nil {
+		out <- idb.TxnRow{Error: err}
+		close(out)
+		return out, round
+	}
+
+	go func() {
+		defer close(out)
+		for _, row := range rows {
+			if !idb.SendTxnRow(ctx, out, row) {
+				return
+			}
+		}
+	}()
+	return out, round
+}
+
+// likeRegexp compiles the pattern of a postgres ILIKE comparison.
+func likeRegexp(pattern string) *regexp.Regexp {
+	var expr strings.Builder
+	expr.WriteString("(?is)^")
+	escaped := false
+	for _, r := range pattern {
+		switch {
+		case escaped:
+			expr.WriteString(regexp.QuoteMeta(string(r)))
+			escaped = false
+		case r == '\\':
+			escaped = true
+		case r == '%':
+			expr.WriteString(".*")
+		case r == '_':
+			expr.WriteString(".")
+		default:
+			expr.WriteString(regexp.QuoteMeta(string(r)))
+		}
+	}
+	expr.WriteString("$")
+	return regexp.MustCompile(expr.String())
+}
+
+// matchLike returns whether the asset name or unit `value` contains `s` in
+// the way of `ILIKE '%s%'`. Names which are empty or not printable are not
+// stored and never match.
+func matchLike(value string, s string) bool {
+	value = util.PrintableUTF8OrEmpty(value)
+	if value == "" {
+		return false
+	}
+	return likeRegexp("%" + s + "%").MatchString(value)
+}
+
+func uint64Ptr(x uint64) *uint64 {
+	out := new(uint64)
+	*out = x
+	return out
+}
+
+func boolPtr(x bool) *bool {
+	out := new(bool)
+	*out = x
+	return out
+}
+
+func stringPtr(x string) *string {
+	if len(x) == 0 {
+		return nil
+	}
+	out := new(string)
+	*out = x
+	return out
+}
+
+// copyUint64Ptr copies a pointer to a stored round so that the stored value
+// can't be changed through the results.
+func copyUint64Ptr(x *uint64) *uint64 {
+	if x == nil {
+		return nil
+	}
+	return uint64Ptr(*x)
+}
+
+func baPtr(x []byte) *[]byte {
+	if len(x) == 0 || allZero(x) {
+		return nil
+	}
+
+	xx := make([]byte, len(x))
+	copy(xx, x)
+	return &xx
+}
+
+func allZero(x []byte) bool {
+	for _, v := range x {
+		if v != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func addrStr(addr basics.Address) *string {
+	if addr.IsZero() {
+		return nil
+	}
+	out := new(string)
+	*out = addr.String()
+	return out
+}
+
+func tealValueToModel(tv basics.TealValue) models.TealValue {
+	switch tv.Type {
+	case basics.TealUintType:
+		return models.TealValue{
+			Uint: tv.Uint,
+			Type: uint64(tv.Type),
+		}
+	case basics.TealBytesType:
+		return models.TealValue{
+			Bytes: base64.StdEncoding.EncodeToString([]byte(tv.Bytes)),
+			Type:  uint64(tv.Type),
+		}
+	}
+	return models.TealValue{}
+}
+
+// tealKeyValueToModel converts a key value store, sorted by key so that the
+// results are the same on every call.
+func tealKeyValueToModel(tkv basics.TealKeyValue) *models.TealKeyValueStore {
+	if len(tkv) == 0 {
+		return nil
+	}
+	keys := make([]string, 0, len(tkv))
+	for key := range tkv {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	var out models.TealKeyValueStore = make([]models.TealKeyValue, len(tkv))
+	for i, key := range keys {
+		out[i].Key = base64.StdEncoding.EncodeToString([]byte(key))
+		out[i].Value = tealValueToModel(tkv[key])
+	}
+	return &out
+}
+
+var statusStrings = []string{"Offline", "Online", "NotParticipating"}
+
+const offlineStatusIdx = 0
+
+// accountResources are the rows of the resource tables which belong to one
+// account, sorted by ID.
+type accountResources struct {
+	holdings    []basics.AssetIndex
+	assets      []basics.AssetIndex
+	apps        []basics.AppIndex
+	localStates []basics.AppIndex
+}
+
+// selectAccountResources collects the resources of `addresses` in one pass over
+// each table. The caller holds the lock.
+func (db *IndexerDb) selectAccountResources(addresses []basics.Address, opts idb.AccountQueryOptions) map[basics.Address]*accountResources {
+	res := make(map[basics.Address]*accountResources, len(addresses))
+	for _, address := range addresses {
+		res[address] = &accountResources{}
+	}
+
+	if opts.IncludeAssetHoldings {
+		for key, row := range db.holdings {
+			if r, ok := res[key.Address]; ok && (opts.IncludeDeleted || !row.deleted) {
+				r.holdings = append(r.holdings, key.Asset)
+			}
+		}
+	}
+	if opts.IncludeAssetParams {
+		for index, row := range db.assets {
+			if r, ok := res[row.creator]; ok && (opts.IncludeDeleted || !row.deleted) {
+				r.assets = append(r.assets, index)
+			}
+		}
+	}
+	for index, row := range db.apps {
+		if r, ok := res[row.creator]; ok && (opts.IncludeDeleted || !row.deleted) {
+			r.apps = append(r.apps, index)
+		}
+	}
+	for key, row := range db.localStates {
+		if r, ok := res[key.Address]; ok && (opts.IncludeDeleted || !row.deleted) {
+			r.localStates = append(r.localStates, key.App)
+		}
+	}
+
+	for _, r := range res {
+		sort.Slice(r.holdings, func(i, j int) bool { return r.holdings[i] < r.holdings[j] })
+		sort.Slice(r.assets, func(i, j int) bool { return r.assets[i] < r.assets[j] })
+		sort.Slice(r.apps, func(i, j int) bool { return r.apps[i] < r.apps[j] })
+		sort.Slice(r.localStates, func(i, j int) bool { return r.localStates[i] < r.localStates[j] })
+	}
+	return res
+}
+
+// matchAccount returns whether the account passes the filters of `opts` other
+// than the address paging and the limit. The caller holds the lock.
+func (db *IndexerDb) matchAccount(opts *idb.AccountQueryOptions, address basics.Address, row *accountRow) bool {
+	// The has-asset filter matches closed holdings as well.
+	if opts.HasAssetID != 0 {
+		holding, ok := db.holdings[ledgercore.AccountAsset{
+			Address: address, Asset: basics.AssetIndex(opts.HasAssetID)}]
+		if !ok {
+			return false
+		}
+		if opts.AssetGT != nil && holding.amount <= *opts.AssetGT {
+			return false
+		}
+		if opts.AssetLT != nil && holding.amount >= *opts.AssetLT {
+			return false
+		}
+	}
+	if opts.HasAppID != 0 {
+		_, ok := db.localStates[ledgercore.AccountApp{
+			Address: address, App: basics.AppIndex(opts.HasAppID)}]
+		if !ok {
+			return false
+		}
+	}
+	if len(opts.EqualToAddress) > 0 && !bytes.Equal(address[:], opts.EqualToAddress) {
+		return false
+	}
+	if opts.AlgosGreaterThan != nil && row.data.MicroAlgos.Raw <= *opts.AlgosGreaterThan {
+		return false
+	}
+	if opts.AlgosLessThan != nil && row.data.MicroAlgos.Raw >= *opts.AlgosLessThan {
+		return false
+	}
+	if !opts.IncludeDeleted && row.deleted {
+		return false
+	}
+	if len(opts.EqualToAuthAddr) > 0 &&
+		(row.deleted || row.data.AuthAddr.IsZero() ||
+			!bytes.Equal(row.data.AuthAddr[:], opts.EqualToAuthAddr)) {
+		return false
+	}
+	return true
+}
+
+// accountToModel converts an account and its resources like the postgres
+// account query does. The caller holds the lock.
+func (db *IndexerDb) accountToModel(address basics.Address, row *accountRow, resources *accountResources, header *bookkeeping.BlockHeader) (models.Account, error) {
+	var account models.Account
+	account.Address = address.String()
+	account.Round = uint64(header.Round)
+	microalgos := row.data.MicroAlgos.Raw
+	account.AmountWithoutPendingRewards = microalgos
+	account.Rewards = row.data.RewardedMicroAlgos.Raw
+	account.CreatedAtRound = uint64Ptr(row.createdAt)
+	account.ClosedAtRound = copyUint64Ptr(row.closedAt)
+	account.Deleted = boolPtr(row.deleted)
+	account.RewardBase = uint64Ptr(row.data.RewardsBase)
+	account.TotalAssetsOptedIn = uint64Ptr(uint64(len(row.data.Assets)))
+	account.TotalCreatedAssets = uint64Ptr(uint64(len(row.data.AssetParams)))
+	account.TotalAppsOptedIn = uint64Ptr(uint64(len(row.data.AppLocalStates)))
+	account.TotalCreatedApps = uint64Ptr(uint64(len(row.data.AppParams)))
+	// default to Offline in there have been no keyreg transactions.
+	account.Status = statusStrings[offlineStatusIdx]
+	if row.keytype != nil && *row.keytype != "" {
+		account.SigType = stringPtr(*row.keytype)
+	}
+
+	// The account data of a closed account is not stored.
+	if !row.deleted {
+		ad := &row.data
+		account.Status = statusStrings[ad.Status]
+		hasSel := !allZero(ad.SelectionID[:])
+		hasVote := !allZero(ad.VoteID[:])
+		if hasSel || hasVote {
+			part := new(models.AccountParticipation)
+			if hasSel {
+				part.SelectionParticipationKey = append([]byte(nil), ad.SelectionID[:]...)
+			}
+			if hasVote {
+				part.VoteParticipationKey = append([]byte(nil), ad.VoteID[:]...)
+			}
+			part.VoteFirstValid = uint64(ad.VoteFirstValid)
+			part.VoteLastValid = uint64(ad.VoteLastValid)
+			part.VoteKeyDilution = ad.VoteKeyDilution
+			account.Participation = part
+		}
+
+		if !ad.AuthAddr.IsZero() {
+			account.AuthAddr = stringPtr(ad.AuthAddr.String())
+		}
+	}
+
+	if account.Status == "NotParticipating" {
+		account.PendingRewards = 0
+	} else {
+		proto, ok := config.Consensus[header.CurrentProtocol]
+		if !ok {
+			return models.Account{}, fmt.Errorf("get protocol err (%s)", header.CurrentProtocol)
+		}
+		rewardsUnits := uint64(0)
+		if proto.RewardUnit != 0 {
+			rewardsUnits = microalgos / proto.RewardUnit
+		}
+		rewardsDelta := header.RewardsLevel - row.data.RewardsBase
+		account.PendingRewards = rewardsUnits * rewardsDelta
+	}
+	account.Amount = microalgos + account.PendingRewards
+
+	if len(resources.holdings) > 0 {
+		av := make([]models.AssetHolding, 0, len(resources.holdings))
+		for _, assetid := range resources.holdings {
+			holding := db.holdings[ledgercore.AccountAsset{Address: address, Asset: assetid}]
+			av = append(av, models.AssetHolding{
+				Amount:          holding.amount,
+				IsFrozen:        holding.frozen,
+				AssetId:         uint64(assetid),
+				OptedOutAtRound: copyUint64Ptr(holding.closedAt),
+				OptedInAtRound:  uint64Ptr(holding.createdAt),
+				Deleted:         boolPtr(holding.deleted),
+			})
+		}
+		account.Assets = &av
+	}
+
+	if len(resources.assets) > 0 {
+		cal := make([]models.Asset, 0, len(resources.assets))
+		for _, assetid := range resources.assets {
+			asset := db.assets[assetid]
+			ap := &asset.params
+			cal = append(cal, models.Asset{
+				Index:            uint64(assetid),
+				CreatedAtRound:   uint64Ptr(asset.createdAt),
+				DestroyedAtRound: copyUint64Ptr(asset.closedAt),
+				Deleted:          boolPtr(asset.deleted),
+				Params: models.AssetParams{
+					Creator:       account.Address,
+					Total:         ap.Total,
+					Decimals:      uint64(ap.Decimals),
+					DefaultFrozen: boolPtr(ap.DefaultFrozen),
+					UnitName:      stringPtr(util.PrintableUTF8OrEmpty(ap.UnitName)),
+					UnitNameB64:   baPtr([]byte(ap.UnitName)),
+					Name:          stringPtr(util.PrintableUTF8OrEmpty(ap.AssetName)),
+					NameB64:       baPtr([]byte(ap.AssetName)),
+					Url:           stringPtr(util.PrintableUTF8OrEmpty(ap.URL)),
+					UrlB64:        baPtr([]byte(ap.URL)),
+					MetadataHash:  baPtr(ap.MetadataHash[:]),
+					Manager:       addrStr(ap.Manager),
+					Reserve:       addrStr(ap.Reserve),
+					Freeze:        addrStr(ap.Freeze),
+					Clawback:      addrStr(ap.Clawback),
+				},
+			})
+		}
+		account.CreatedAssets = &cal
+	}
+
+	var totalSchema models.ApplicationStateSchema
+
+	if len(resources.apps) > 0 {
+		var totalExtraPages uint64
+		aout := make([]models.Application, len(resources.apps))
+		for i, appid := range resources.apps {
+			app := db.apps[appid]
+			aout[i].Id = uint64(appid)
+			aout[i].CreatedAtRound = uint64Ptr(app.createdAt)
+			aout[i].DeletedAtRound = copyUint64Ptr(app.closedAt)
+			aout[i].Deleted = boolPtr(app.deleted)
+			aout[i].Params.Creator = &account.Address
+
+			// The params of a deleted app are not stored.
+			params := &app.params
+			if params.ApprovalProgram != nil || params.ClearStateProgram != nil {
+				aout[i].Params.ApprovalProgram = params.ApprovalProgram
+				aout[i].Params.ClearStateProgram = params.ClearStateProgram
+				aout[i].Params.GlobalState = tealKeyValueToModel(params.GlobalState)
+				aout[i].Params.GlobalStateSchema = &models.ApplicationStateSchema{
+					NumByteSlice: params.GlobalStateSchema.NumByteSlice,
+					NumUint:      params.GlobalStateSchema.NumUint,
+				}
+				aout[i].Params.LocalStateSchema = &models.ApplicationStateSchema{
+					NumByteSlice: params.LocalStateSchema.NumByteSlice,
+					NumUint:      params.LocalStateSchema.NumUint,
+				}
+			}
+			if !app.deleted {
+				totalSchema.NumByteSlice += params.GlobalStateSchema.NumByteSlice
+				totalSchema.NumUint += params.GlobalStateSchema.NumUint
+				totalExtraPages += uint64(params.ExtraProgramPages)
+			}
+		}
+		account.CreatedApps = &aout
+
+		if totalExtraPages != 0 {
+			account.AppsTotalExtraPages = &totalExtraPages
+		}
+	}
+
+	if len(resources.localStates) > 0 {
+		aout := make([]models.ApplicationLocalState, len(resources.localStates))
+		for i, appid := range resources.localStates {
+			ls := db.localStates[ledgercore.AccountApp{Address: address, App: appid}]
+			aout[i].Id = uint64(appid)
+			aout[i].OptedInAtRound = uint64Ptr(ls.createdAt)
+			aout[i].ClosedOutAtRound = copyUint64Ptr(ls.closedAt)
+			aout[i].Deleted = boolPtr(ls.deleted)
+			aout[i].Schema = models.ApplicationStateSchema{
+				NumByteSlice: ls.state.Schema.NumByteSlice,
+				NumUint:      ls.state.Schema.NumUint,
+			}
+			aout[i].KeyValue = tealKeyValueToModel(ls.state.KeyValue)
+			if !ls.deleted {
+				totalSchema.NumByteSlice += ls.state.Schema.NumByteSlice
+				totalSchema.NumUint += ls.state.Schema.NumUint
+			}
+		}
+		account.AppsLocalState = &aout
+	}
+
+	if totalSchema != (models.ApplicationStateSchema{}) {
+		account.AppsTotalSchema = &totalSchema
+	}
+
+	return account, nil
+}
+
+// selectAccounts returns the accounts which match `opts` in address order. The
+// caller holds the lock.
+func (db *IndexerDb) selectAccounts(opts idb.AccountQueryOptions) ([]idb.AccountRow, error) {
+	round, err := db.getMaxRoundAccounted()
+	if err != nil {
+		return nil, fmt.Errorf("account round err %v", err)
+	}
+	// Get block header for that round so we know protocol and rewards info
+	header, ok := db.headers[round]
+	if !ok {
+		return nil, fmt.Errorf("account round header %d err not found", round)
+	}
+
+	addresses := make([]basics.Address, 0, len(db.accounts))
+	for address := range db.accounts {
+		if len(opts.GreaterThanAddress) > 0 &&
+			bytes.Compare(address[:], opts.GreaterThanAddress) <= 0 {
+			continue
+		}
+		if db.matchAccount(&opts, address, db.accounts[address]) {
+			addresses = append(addresses, address)
+		}
+	}
+	sort.Slice(addresses, func(i, j int) bool {
+		return bytes.Compare(addresses[i][:], addresses[j][:]) < 0
+	})
+	if opts.Limit != 0 && uint64(len(addresses)) > opts.Limit {
+		addresses = addresses[:opts.Limit]
+	}
+
+	resources := db.selectAccountResources(addresses, opts)
+	results := make([]idb.AccountRow, 0, len(addresses))
+	for _, address := range addresses {
+		account, err := db.accountToModel(
+			address, db.accounts[address], resources[address], &header)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, idb.AccountRow{Account: account})
+	}
+	return results, nil
+}
+
+// GetAccounts is part of idb.IndexerDB
+func (db *IndexerDb) GetAccounts(ctx context.Context, opts idb.AccountQueryOptions) (<-chan idb.AccountRow, uint64) {
+	out := make(chan idb.AccountRow, 1)
+
+	if opts.HasAssetID != 0 {
+		opts.IncludeAssetHoldings = true
+	} else if (opts.AssetGT != nil) || (opts.AssetLT != nil) {
+		var gt, lt uint64
+		if opts.AssetGT != nil {
+			gt = *opts.AssetGT
+		}
+		if opts.AssetLT != nil {
+			lt = *opts.AssetLT
+		}
+		err := fmt.Errorf("AssetGT=%d, AssetLT=%d, but HasAssetID=%d", gt, lt, opts.HasAssetID)
+		out <- idb.AccountRow{Error: err}
+		close(out)
+		return out, 0
+	}
+
+	db.mu.RLock()
+	round, _ := db.getMaxRoundAccounted()
+	rows, err := db.selectAccounts(opts)
+	db.mu.RUnlock()
+
+	if err != nil {
+		out <- idb.AccountRow{Error: err}
+		close(out)
+		return out, round
+	}
+
+	go func() {
+		defer close(out)
+		for _, row := range rows {
+			if !idb.SendAccountRow(ctx, out, row) {
+				return
+			}
+		}
+	}()
+	return out, round
+}
+
+// Assets is part of idb.IndexerDB
+func (db *IndexerDb) Assets(ctx context.Context, filter idb.AssetsQuery) (<-chan idb.AssetRow, uint64) {
+	out := make(chan idb.AssetRow, 1)
+
+	db.mu.RLock()
+	round, err := db.getMaxRoundAccounted()
+	if err != nil {
+		db.mu.RUnlock()
+		out <- idb.AssetRow{Error: err}
+		close(out)
+		return out, round
+	}
+
+	var rows []idb.AssetRow
+	for index, asset := range db.assets {
+		if filter.AssetID != 0 && uint64(index) != filter.AssetID {
+			continue
+		}
+		if filter.AssetIDGreaterThan != 0 && uint64(index) <= filter.AssetIDGreaterThan {
+			continue
+		}
+		if filter.Creator != nil && !bytes.Equal(asset.creator[:], filter.Creator) {
+			continue
+		}
+		if filter.Name != "" && !matchLike(asset.params.AssetName, filter.Name) {
+			continue
+		}
+		if filter.Unit != "" && !matchLike(asset.params.UnitName, filter.Unit) {
+			continue
+		}
+		if filter.Query != "" &&
+			!matchLike(asset.params.UnitName, filter.Query) &&
+			!matchLike(asset.params.AssetName, filter.Query) {
+			continue
+		}
+		if !filter.IncludeDeleted && asset.deleted {
+			continue
+		}
+		creator := asset.creator
+		rows = append(rows, idb.AssetRow{
+			AssetID:      uint64(index),
+			Creator:      creator[:],
+			Params:       asset.params,
+			CreatedRound: uint64Ptr(asset.createdAt),
+			ClosedRound:  copyUint64Ptr(asset.closedAt),
+			Deleted:      boolPtr(asset.deleted),
+		})
+	}
+	db.mu.RUnlock()
+
+	sort.Slice(rows, func(i, j int) bool { return rows[i].AssetID < rows[j].AssetID })
+	if filter.Limit != 0 && uint64(len(rows)) > filter.Limit {
+		rows = rows[:filter.Limit]
+	}
+
+	go func() {
+		defer close(out)
+		for _, row := range rows {
+			if !idb.SendAssetRow(ctx, out, row) {
+				return
+			}
+		}
+	}()
+	return out, round
+}
+
+// AssetBalances is part of idb.IndexerDB
+func (db *IndexerDb) AssetBalances(ctx context.Context, abq idb.AssetBalanceQuery) (<-chan idb.AssetBalanceRow, uint64) {
+	out := make(chan idb.AssetBalanceRow, 1)
+
+	db.mu.RLock()
+	round, err := db.getMaxRoundAccounted()
+	if err != nil {
+		db.mu.RUnlock()
+		out <- idb.AssetBalanceRow{Error: err}
+		close(out)
+		return out, round
+	}
+
+	var rows []idb.AssetBalanceRow
+	for key, holding := range db.holdings {
+		address := key.Address
+		if abq.AssetID != 0 && uint64(key.Asset) != abq.AssetID {
+			continue
+		}
+		if abq.AmountGT != nil && holding.amount <= *abq.AmountGT {
+			continue
+		}
+		if abq.AmountLT != nil && holding.amount >= *abq.AmountLT {
+			continue
+		}
+		if len(abq.PrevAddress) != 0 && bytes.Compare(address[:], abq.PrevAddress) <= 0 {
+			continue
+		}
+		if !abq.IncludeDeleted && holding.deleted {
+			continue
+		}
+		rows = append(rows, idb.AssetBalanceRow{
+			Address:      address[:],
+			AssetID:      uint64(key.Asset),
+			Amount:       holding.amount,
+			Frozen:       holding.frozen,
+			CreatedRound: uint64Ptr(holding.createdAt),
+			ClosedRound:  copyUint64Ptr(holding.closedAt),
+			Deleted:      boolPtr(holding.deleted),
+		})
+	}
+	db.mu.RUnlock()
+
+	sort.Slice(rows, func(i, j int) bool {
+		c := bytes.Compare(rows[i].Address, rows[j].Address)
+		return c < 0 || (c == 0 && rows[i].AssetID < rows[j].AssetID)
+	})
+	if abq.Limit > 0 && uint64(len(rows)) > abq.Limit {
+		rows = rows[:abq.Limit]
+	}
+
+	go func() {
+		defer close(out)
+		for _, row := range rows {
+			if !idb.SendAssetBalanceRow(ctx, out, row) {
+				return
+			}
+		}
+	}()
+	return out, round
+}
+
+// Applications is part of idb.IndexerDB
+func (db *IndexerDb) Applications(ctx context.Context, filter *models.SearchForApplicationsParams) (<-chan idb.ApplicationRow, uint64) {
+	out := make(chan idb.ApplicationRow, 1)
+	if filter == nil {
+		out <- idb.ApplicationRow{Error: fmt.Errorf("no arguments provided to application search")}
+		close(out)
+		return out, 0
+	}
+	var next uint64
+	if filter.Next != nil {
+		var err error
+		next, err = strconv.ParseUint(*filter.Next, 10, 64)
+		if err != nil {
+			out <- idb.ApplicationRow{Error: fmt.Errorf("invalid next token %s, %v", *filter.Next, err)}
+			close(out)
+			return out, 0
+		}
+	}
+
+	db.mu.RLock()
+	round, err := db.getMaxRoundAccounted()
+	if err != nil {
+		db.mu.RUnlock()
+		out <- idb.ApplicationRow{Error: err}
+		close(out)
+		return out, round
+	}
+
+	var rows []idb.ApplicationRow
+	for index, app := range db.apps {
+		if filter.ApplicationId != nil && uint64(index) != *filter.ApplicationId {
+			continue
+		}
+		if filter.Next != nil && uint64(index) <= next {
+			continue
+		}
+		if (filter.IncludeAll == nil || !(*filter.IncludeAll)) && app.deleted {
+			continue
+		}
+
+		var rec idb.ApplicationRow
+		rec.Application.Id = uint64(index)
+		rec.Application.CreatedAtRound = uint64Ptr(app.createdAt)
+		rec.Application.DeletedAtRound = copyUint64Ptr(app.closedAt)
+		rec.Application.Deleted = boolPtr(app.deleted)
+		ap := &app.params
+		rec.Application.Params.ApprovalProgram = ap.ApprovalProgram
+		rec.Application.Params.ClearStateProgram = ap.ClearStateProgram
+		rec.Application.Params.Creator = new(string)
+		*(rec.Application.Params.Creator) = app.creator.String()
+		rec.Application.Params.GlobalState = tealKeyValueToModel(ap.GlobalState)
+		rec.Application.Params.GlobalStateSchema = &models.ApplicationStateSchema{
+			NumByteSlice: ap.GlobalStateSchema.NumByteSlice,
+			NumUint:      ap.GlobalStateSchema.NumUint,
+		}
+		rec.Application.Params.LocalStateSchema = &models.ApplicationStateSchema{
+			NumByteSlice: ap.LocalStateSchema.NumByteSlice,
+			NumUint:      ap.LocalStateSchema.NumUint,
+		}
+		if ap.ExtraProgramPages != 0 {
+			rec.Application.Params.ExtraProgramPages = new(uint64)
+			*rec.Application.Params.ExtraProgramPages = uint64(ap.ExtraProgramPages)
+		}
+		rows = append(rows, rec)
+	}
+	db.mu.RUnlock()
+
+	sort.Slice(rows, func(i, j int) bool {
+		return rows[i].Application.Id < rows[j].Application.Id
+	})
+	if filter.Limit != nil && uint64(len(rows)) > *filter.Limit {
+		rows = rows[:*filter.Limit]
+	}
+
+	go func() {
+		defer close(out)
+		for _, row := range rows {
+			if !idb.SendApplicationRow(ctx, out, row) {
+				return
+			}
+		}
+	}()
+	return out, round
+}
+
+// EstimateTransactionsCount is part of idb.IndexerDB. The count is exact.
+func (db *IndexerDb) EstimateTransactionsCount(ctx context.Context, tf idb.TransactionFilter) (uint64, error) {
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+
+	tf.Limit = 0
+	tf.NextToken = nil
+	rows, err := db.selectTransactions(tf)
+	if err != nil {
+		return 0, fmt.Errorf("EstimateTransactionsCount() err: %w", err)
+	}
+	return uint64(len(rows)), nil
+}
+
+// EstimateAccountsCount is part of idb.IndexerDB. The count is exact.
+func (db *IndexerDb) EstimateAccountsCount(ctx context.Context, opts idb.AccountQueryOptions) (uint64, error) {
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+
+	var count uint64
+	for address, row := range db.accounts {
+		if db.matchAccount(&opts, address, row) {
+			count++
+		}
+	}
+	return count, nil
+}
@@ -0,0 +1,294 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/algorand/go-algorand/data/basics"
+	"github.com/algorand/go-algorand/data/bookkeeping"
+	"github.com/algorand/go-algorand/ledger/ledgercore"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	models "github.com/algorand/indexer/api/generated/v2"
+	"github.com/algorand/indexer/idb"
+	"github.com/algorand/indexer/util/test"
+)
+
+func setupFixture(t *testing.T) *IndexerDb {
+	db := MakeIndexerDb(nil)
+	err := test.ImportFixture(db)
+	require.NoError(t, err)
+	return db
+}
+
+func txnRows(t *testing.T, db *IndexerDb, tf idb.TransactionFilter) []idb.TxnRow {
+	rowsCh, _ := db.Transactions(context.Background(), tf)
+	var rows []idb.TxnRow
+	for row := range rowsCh {
+		require.NoError(t, row.Error)
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+func TestTransactionsFilters(t *testing.T) {
+	db := setupFixture(t)
+
+	rows := txnRows(t, db, idb.TransactionFilter{})
+	require.Len(t, rows, 5)
+	assert.Equal(t, uint64(1), rows[0].Round)
+	assert.Equal(t, uint64(2), rows[4].Round)
+
+	// Searches by address are newest first and page backwards.
+	rows = txnRows(t, db, idb.TransactionFilter{Address: test.AccountB[:], Limit: 2})
+	require.Len(t, rows, 2)
+	assert.Equal(t, uint64(2), rows[0].Round)
+	assert.Equal(t, 1, rows[0].Intra)
+	assert.Equal(t, 0, rows[1].Intra)
+	rows = txnRows(t, db, idb.TransactionFilter{
+		Address: test.AccountB[:], NextToken: rows[1].Next()})
+	require.Len(t, rows, 1)
+	assert.Equal(t, uint64(1), rows[0].Round)
+
+	rows = txnRows(t, db, idb.TransactionFilter{
+		Address: test.AccountB[:], AddressRole: idb.AddressRoleSender})
+	require.Len(t, rows, 1)
+	assert.Equal(t, uint64(2), rows[0].Round)
+
+	algos := uint64(1000)
+	rows = txnRows(t, db, idb.TransactionFilter{AlgosGT: &algos})
+	require.Len(t, rows, 1)
+	assert.Equal(t, uint64(1), rows[0].Round)
+
+	rows = txnRows(t, db, idb.TransactionFilter{TypeEnum: idb.TypeEnumAssetTransfer})
+	require.Len(t, rows, 2)
+
+	_, txns, err := db.GetBlock(context.Background(), 1, idb.GetBlockOptions{Transactions: true})
+	require.NoError(t, err)
+	assert.Len(t, txns, 3)
+}
+
+func TestAccountsAndAssets(t *testing.T) {
+	db := setupFixture(t)
+
+	assetsCh, _ := db.Assets(context.Background(), idb.AssetsQuery{Query: "exm"})
+	var assets []idb.AssetRow
+	for row := range assetsCh {
+		require.NoError(t, row.Error)
+		assets = append(assets, row)
+	}
+	require.Len(t, assets, 1)
+	assetID := assets[0].AssetID
+
+	balancesCh, _ := db.AssetBalances(context.Background(), idb.AssetBalanceQuery{AssetID: assetID})
+	balances := make(map[string]uint64)
+	for row := range balancesCh {
+		require.NoError(t, row.Error)
+		var address basics.Address
+		copy(address[:], row.Address)
+		balances[address.String()] = row.Amount
+	}
+	assert.Equal(t, map[string]uint64{
+		test.AccountA.String(): 999500,
+		test.AccountB.String(): 500,
+	}, balances)
+
+	accountsCh, round := db.GetAccounts(context.Background(), idb.AccountQueryOptions{
+		HasAssetID: assetID, IncludeAssetParams: true})
+	assert.Equal(t, uint64(2), round)
+	var accounts []models.Account
+	for row := range accountsCh {
+		require.NoError(t, row.Error)
+		accounts = append(accounts, row.Account)
+	}
+	require.Len(t, accounts, 2)
+	for _, account := range accounts {
+		require.NotNil(t, account.Assets)
+		assert.Equal(t, balances[account.Address], (*account.Assets)[0].Amount)
+		require.NotNil(t, account.TotalAssetsOptedIn)
+		assert.Equal(t, uint64(1), *account.TotalAssetsOptedIn)
+		if account.Address == test.AccountA.String() {
+			require.NotNil(t, account.CreatedAssets)
+			assert.Equal(t, assetID, (*account.CreatedAssets)[0].Index)
+			require.NotNil(t, account.CreatedApps)
+			require.NotNil(t, account.TotalCreatedAssets)
+			assert.Equal(t, uint64(1), *account.TotalCreatedAssets)
+			require.NotNil(t, account.TotalCreatedApps)
+			assert.Equal(t, uint64(1), *account.TotalCreatedApps)
+		}
+	}
+
+	appsCh, _ := db.Applications(context.Background(), &models.SearchForApplicationsParams{})
+	var apps []idb.ApplicationRow
+	for row := range appsCh {
+		require.NoError(t, row.Error)
+		apps = append(apps, row)
+	}
+	require.Len(t, apps, 1)
+	assert.Equal(t, test.AccountA.String(), *apps[0].Application.Params.Creator)
+}
+
+// TestCloseAccount checks that a closed account is only returned with
+// IncludeDeleted, like in postgres.
+func TestCloseAccount(t *testing.T) {
+	db := setupFixture(t)
+
+	block2, _, err := db.GetBlock(context.Background(), 2, idb.GetBlockOptions{})
+	require.NoError(t, err)
+	closeTxn := test.MakePaymentTxn(
+		1000, 1000, 1000*1000*1000*1000-2000, 0, 0, 0, test.AccountC, test.AccountD,
+		test.AccountA, basics.Address{})
+	block3, err := test.MakeBlockForTxns(block2, &closeTxn)
+	require.NoError(t, err)
+	err = db.AddBlock(&block3)
+	require.NoError(t, err)
+
+	accountsCh, _ := db.GetAccounts(
+		context.Background(), idb.AccountQueryOptions{EqualToAddress: test.AccountC[:]})
+	for row := range accountsCh {
+		assert.Fail(t, "closed account returned", "%v", row)
+	}
+
+	accountsCh, _ = db.GetAccounts(context.Background(), idb.AccountQueryOptions{
+		EqualToAddress: test.AccountC[:], IncludeDeleted: true})
+	var accounts []models.Account
+	for row := range accountsCh {
+		require.NoError(t, row.Error)
+		accounts = append(accounts, row.Account)
+	}
+	require.Len(t, accounts, 1)
+	assert.Equal(t, uint64(0), accounts[0].Amount)
+	require.NotNil(t, accounts[0].Deleted)
+	assert.True(t, *accounts[0].Deleted)
+	require.NotNil(t, accounts[0].ClosedAtRound)
+	assert.Equal(t, uint64(3), *accounts[0].ClosedAtRound)
+}
+
+type recordingHook struct {
+	before    []basics.Round
+	after     []basics.Round
+	deltas    []ledgercore.StateDelta
+	beforeErr error
+}
+
+func (h *recordingHook) BeforeBlock(block *bookkeeping.Block, delta ledgercore.StateDelta) error {
+	h.before = append(h.before, block.Round())
+	return h.beforeErr
+}
+
+func (h *recordingHook) AfterBlock(block *bookkeeping.Block, delta ledgercore.StateDelta) {
+	h.after = append(h.after, block.Round())
+	h.deltas = append(h.deltas, delta)
+}
+
+func TestBlockHooks(t *testing.T) {
+	db := setupFixture(t)
+	hook := &recordingHook{}
+	db.AddBlockHook(hook)
+
+	block2, _, err := db.GetBlock(context.Background(), 2, idb.GetBlockOptions{})
+	require.NoError(t, err)
+	payTxn := test.MakePaymentTxn(
+		1000, 1000, 0, 0, 0, 0, test.AccountC, test.AccountD, basics.Address{},
+		basics.Address{})
+	block3, err := test.MakeBlockForTxns(block2, &payTxn)
+	require.NoError(t, err)
+	err = db.AddBlocks([]*bookkeeping.Block{&block3})
+	require.NoError(t, err)
+
+	assert.Equal(t, []basics.Round{3}, hook.before)
+	assert.Equal(t, []basics.Round{3}, hook.after)
+	_, ok := hook.deltas[0].Accts.Get(test.AccountD)
+	assert.True(t, ok)
+
+	// An error of BeforeBlock aborts the import.
+	hook.beforeErr = errors.New("hook failed")
+	payTxn = test.MakePaymentTxn(
+		1000, 2000, 0, 0, 0, 0, test.AccountC, test.AccountD, basics.Address{},
+		basics.Address{})
+	block4, err := test.MakeBlockForTxns(block3.BlockHeader, &payTxn)
+	require.NoError(t, err)
+	err = db.AddBlock(&block4)
+	assert.True(t, errors.Is(err, hook.beforeErr))
+	assert.Equal(t, []basics.Round{3}, hook.after)
+
+	next, err := db.GetNextRoundToAccount()
+	require.NoError(t, err)
+	assert.Equal(t, uint64(4), next)
+}
+
+func TestGenesisHashMismatch(t *testing.T) {
+	db := setupFixture(t)
+
+	block2, _, err := db.GetBlock(context.Background(), 2, idb.GetBlockOptions{})
+	require.NoError(t, err)
+	block3, err := test.MakeBlockForTxns(block2)
+	require.NoError(t, err)
+	block3.BlockHeader.GenesisHash[0]++
+	err = db.AddBlock(&block3)
+	assert.True(t, errors.Is(err, idb.ErrorGenesisMismatch))
+
+	next, err := db.GetNextRoundToAccount()
+	require.NoError(t, err)
+	assert.Equal(t, uint64(3), next)
+}
+
+func TestPruneTransactions(t *testing.T) {
+	db := setupFixture(t)
+
+	earliest, err := db.PruneTransactions(context.Background(), 2, 0)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(2), earliest)
+
+	rows := txnRows(t, db, idb.TransactionFilter{})
+	require.Len(t, rows, 2)
+	assert.Equal(t, uint64(2), rows[0].Round)
+
+	retention, err := db.GetRetention(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, idb.Retention{EarliestRound: 2, EarliestParticipationRound: 2}, retention)
+}
+
+// TestCanceledQuery checks that the stream of a canceled query ends, with the
+// error of the context if it is reported.
+func TestCanceledQuery(t *testing.T) {
+	db := setupFixture(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	rowsCh, _ := db.Transactions(ctx, idb.TransactionFilter{})
+	<-rowsCh
+	cancel()
+
+	count := 1
+	var err error
+	for row := range rowsCh {
+		if row.Error != nil {
+			err = row.Error
+			continue
+		}
+		count++
+	}
+	assert.LessOrEqual(t, count, 5)
+	if err != nil {
+		assert.Equal(t, context.Canceled, err)
+	}
+}
+
+func TestEstimateCounts(t *testing.T) {
+	db := setupFixture(t)
+
+	count, err := db.EstimateTransactionsCount(
+		context.Background(), idb.TransactionFilter{Limit: 1, TypeEnum: idb.TypeEnumAssetTransfer})
+	require.NoError(t, err)
+	assert.Equal(t, uint64(2), count)
+
+	// Only the accounts of the genesis have more than 1 Algo, the fee sink
+	// has the fees.
+	algos := uint64(1000000)
+	count, err = db.EstimateAccountsCount(
+		context.Background(), idb.AccountQueryOptions{Limit: 1, AlgosGreaterThan: &algos})
+	require.NoError(t, err)
+	assert.Equal(t, uint64(4), count)
+}
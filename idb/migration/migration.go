@@ -8,6 +8,8 @@ import (
 	"time"
 
 	log "github.com/sirupsen/logrus"
+
+	"github.com/algorand/indexer/util/reporting"
 )
 
 // ErrDuplicateID is the error returned when the migration is given duplicate migration IDs.
@@ -48,6 +50,49 @@ type Task struct {
 	Description string
 }
 
+// Progress is how far the running task is. Tasks which process a known amount
+// of work, e.g. rows or indexes, report it with Migration.SetProgress.
+type Progress struct {
+	// Processed is the amount of work done, in units of the task.
+	Processed uint64 `json:"processed"`
+
+	// Total is the amount of work of the task, or 0 if it is not known.
+	Total uint64 `json:"total"`
+
+	// Started is when the task started processing.
+	Started time.Time `json:"started"`
+
+	// Updated is when the progress was last reported.
+	Updated time.Time `json:"updated"`
+
+	// Resumed is the part of Processed which was done before Started, by an
+	// interrupted earlier run of the task.
+	Resumed uint64 `json:"resumed,omitempty"`
+}
+
+// Percent returns the percentage of the work which is done, or 0 if the total
+// is not known.
+func (p Progress) Percent() float64 {
+	if p.Total == 0 {
+		return 0
+	}
+	return 100 * float64(p.Processed) / float64(p.Total)
+}
+
+// ETA estimates the time until the task finishes from the rate since it
+// started. It returns false if there is not enough progress to estimate it.
+func (p Progress) ETA() (time.Duration, bool) {
+	if p.Processed >= p.Total && p.Total != 0 {
+		return 0, true
+	}
+	if p.Total == 0 || p.Processed <= p.Resumed || !p.Updated.After(p.Started) {
+		return 0, false
+	}
+	elapsed := p.Updated.Sub(p.Started)
+	remaining := float64(p.Total-p.Processed) / float64(p.Processed-p.Resumed)
+	return time.Duration(remaining * float64(elapsed)), true
+}
+
 // State is the current status of the migration.
 type State struct {
 	// Time is when this state was captured.
@@ -67,6 +112,9 @@ type State struct {
 
 	// Blocking indicates that one or more tasks have requested that the DB remain unavailable until they complete.
 	Blocking bool
+
+	// Progress is the progress of the running task, nil if it was not reported.
+	Progress *Progress
 }
 
 // IsZero returns true if the object has not been initialized.
@@ -155,9 +203,23 @@ func (m *Migration) GetStatus() State {
 		Running:  m.state.Running,
 		Blocking: m.state.Blocking,
 		TaskID:   m.state.TaskID,
+		Progress: m.state.Progress,
 	}
 }
 
+// SetProgress reports the progress of the running task. It is cleared when the
+// next task starts. This function is thread safe.
+func (m *Migration) SetProgress(progress Progress) {
+	if m == nil {
+		return
+	}
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.state.Progress = &progress
+}
+
 // update is a helper to set values in a thread safe way.
 func (m *Migration) update(err error, status string, running bool, blocking bool, id int) {
 	m.mutex.Lock()
@@ -183,12 +245,14 @@ func (m *Migration) update(err error, status string, running bool, blocking bool
 
 	if id != m.state.TaskID {
 		m.state.TaskID = id
+		m.state.Progress = nil
 	}
 }
 
 // This function always blocks. Closes `ch` when blocking migrations finish
 // running successfully.
 func (m *Migration) runMigrations(ch chan struct{}) {
+	defer reporting.ReportPanic()
 	m.log.Printf("Running %d migrations.", len(m.tasks))
 
 	blocking := true
@@ -203,7 +267,7 @@ func (m *Migration) runMigrations(ch chan struct{}) {
 		err := task.Handler()
 		if err != nil {
 			err := fmt.Errorf("%s%d (%s): %w", StatusErrorPrefix, task.MigrationID, task.Description, err)
-			m.log.WithError(err).Errorf("Migration failed")
+			m.log.WithError(err).WithField("migration", task.MigrationID).Errorf("Migration failed")
 			// If a migration failed, mark that the migration is blocking and terminate.
 			blocking = true
 			m.update(err, err.Error(), false, blocking, task.MigrationID)
@@ -428,3 +428,69 @@ func TestAvailabilityChannelDoesNotCloseEarly(t *testing.T) {
 	case <-time.After(5 * time.Millisecond):
 	}
 }
+
+func TestProgress(t *testing.T) {
+	started := time.Now()
+	progress := Progress{Processed: 25, Total: 100, Started: started, Updated: started.Add(time.Minute)}
+	assert.Equal(t, 25.0, progress.Percent())
+	eta, ok := progress.ETA()
+	require.True(t, ok)
+	assert.Equal(t, 3*time.Minute, eta)
+
+	_, ok = Progress{Total: 100, Started: started, Updated: started}.ETA()
+	assert.False(t, ok)
+	_, ok = Progress{Processed: 5, Started: started, Updated: started.Add(time.Second)}.ETA()
+	assert.False(t, ok)
+	assert.Equal(t, 0.0, Progress{Processed: 5}.Percent())
+}
+
+// TestProgressClearedByNextTask checks that the progress of a task is not
+// reported for the following task.
+func TestProgressClearedByNextTask(t *testing.T) {
+	var m *Migration
+	var recorder []State
+	tasks := []Task{
+		{
+			MigrationID: 1,
+			Handler: func() error {
+				m.SetProgress(Progress{Processed: 1, Total: 1})
+				recorder = append(recorder, m.GetStatus())
+				return nil
+			},
+		},
+		{
+			MigrationID: 2,
+			Handler: func() error {
+				recorder = append(recorder, m.GetStatus())
+				return nil
+			},
+		},
+	}
+	m, err := MakeMigration(tasks, nil)
+	require.NoError(t, err)
+	<-m.RunMigrations()
+	for m.GetStatus().Running || m.GetStatus().Status == StatusPending {
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	require.Len(t, recorder, 2)
+	require.NotNil(t, recorder[0].Progress)
+	assert.Equal(t, uint64(1), recorder[0].Progress.Processed)
+	assert.Nil(t, recorder[1].Progress)
+}
+
+// TestProgressResumed checks that the work of an earlier run is not used for
+// the ETA.
+func TestProgressResumed(t *testing.T) {
+	started := time.Now()
+	progress := Progress{
+		Processed: 60, Total: 100, Resumed: 50, Started: started, Updated: started.Add(time.Minute)}
+	assert.Equal(t, 60.0, progress.Percent())
+	eta, ok := progress.ETA()
+	require.True(t, ok)
+	assert.Equal(t, 4*time.Minute, eta)
+
+	progress.Processed = 50
+	_, ok = progress.ETA()
+	assert.False(t, ok)
+}
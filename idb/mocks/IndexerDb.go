@@ -5,6 +5,8 @@ package mocks
 import (
 	context "context"
 
+	io "io"
+
 	bookkeeping "github.com/algorand/go-algorand/data/bookkeeping"
 
 	generated "github.com/algorand/indexer/api/generated/v2"
@@ -35,6 +37,39 @@ func (_m *IndexerDb) AddBlock(block *bookkeeping.Block) error {
 	return r0
 }
 
+// AddBlockHook provides a mock function with given fields: hook
+func (_m *IndexerDb) AddBlockHook(hook idb.BlockHook) {
+	_m.Called(hook)
+}
+
+// AddBlocks provides a mock function with given fields: blocks
+func (_m *IndexerDb) AddBlocks(blocks []*bookkeeping.Block) error {
+	ret := _m.Called(blocks)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func([]*bookkeeping.Block) error); ok {
+		r0 = rf(blocks)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// AddWebhook provides a mock function with given fields: ctx, hook
+func (_m *IndexerDb) AddWebhook(ctx context.Context, hook idb.Webhook) error {
+	ret := _m.Called(ctx, hook)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, idb.Webhook) error); ok {
+		r0 = rf(ctx, hook)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // Applications provides a mock function with given fields: ctx, filter
 func (_m *IndexerDb) Applications(ctx context.Context, filter *generated.SearchForApplicationsParams) (<-chan idb.ApplicationRow, uint64) {
 	ret := _m.Called(ctx, filter)
@@ -104,6 +139,183 @@ func (_m *IndexerDb) Assets(ctx context.Context, filter idb.AssetsQuery) (<-chan
 	return r0, r1
 }
 
+// Backfill provides a mock function with given fields: ctx, opts
+func (_m *IndexerDb) Backfill(ctx context.Context, opts idb.BackfillOptions) error {
+	ret := _m.Called(ctx, opts)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, idb.BackfillOptions) error); ok {
+		r0 = rf(ctx, opts)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteOrphanedRows provides a mock function with given fields: ctx, dryRun
+func (_m *IndexerDb) DeleteOrphanedRows(ctx context.Context, dryRun bool) ([]idb.OrphanedRows, error) {
+	ret := _m.Called(ctx, dryRun)
+
+	var r0 []idb.OrphanedRows
+	if rf, ok := ret.Get(0).(func(context.Context, bool) []idb.OrphanedRows); ok {
+		r0 = rf(ctx, dryRun)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]idb.OrphanedRows)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, bool) error); ok {
+		r1 = rf(ctx, dryRun)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DeleteWebhook provides a mock function with given fields: ctx, id
+func (_m *IndexerDb) DeleteWebhook(ctx context.Context, id string) (bool, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func(context.Context, string) bool); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DumpCSV provides a mock function with given fields: ctx, table, w
+func (_m *IndexerDb) DumpCSV(ctx context.Context, table string, w io.Writer) (uint64, error) {
+	ret := _m.Called(ctx, table, w)
+
+	var r0 uint64
+	if rf, ok := ret.Get(0).(func(context.Context, string, io.Writer) uint64); ok {
+		r0 = rf(ctx, table, w)
+	} else {
+		r0 = ret.Get(0).(uint64)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, io.Writer) error); ok {
+		r1 = rf(ctx, table, w)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// EstimateAccountsCount provides a mock function with given fields: ctx, opts
+func (_m *IndexerDb) EstimateAccountsCount(ctx context.Context, opts idb.AccountQueryOptions) (uint64, error) {
+	ret := _m.Called(ctx, opts)
+
+	var r0 uint64
+	if rf, ok := ret.Get(0).(func(context.Context, idb.AccountQueryOptions) uint64); ok {
+		r0 = rf(ctx, opts)
+	} else {
+		r0 = ret.Get(0).(uint64)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, idb.AccountQueryOptions) error); ok {
+		r1 = rf(ctx, opts)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// EstimateTransactionsCount provides a mock function with given fields: ctx, tf
+func (_m *IndexerDb) EstimateTransactionsCount(ctx context.Context, tf idb.TransactionFilter) (uint64, error) {
+	ret := _m.Called(ctx, tf)
+
+	var r0 uint64
+	if rf, ok := ret.Get(0).(func(context.Context, idb.TransactionFilter) uint64); ok {
+		r0 = rf(ctx, tf)
+	} else {
+		r0 = ret.Get(0).(uint64)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, idb.TransactionFilter) error); ok {
+		r1 = rf(ctx, tf)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ExplainTransactions provides a mock function with given fields: ctx, tf
+func (_m *IndexerDb) ExplainTransactions(ctx context.Context, tf idb.TransactionFilter) (idb.QueryPlan, error) {
+	ret := _m.Called(ctx, tf)
+
+	var r0 idb.QueryPlan
+	if rf, ok := ret.Get(0).(func(context.Context, idb.TransactionFilter) idb.QueryPlan); ok {
+		r0 = rf(ctx, tf)
+	} else {
+		r0 = ret.Get(0).(idb.QueryPlan)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, idb.TransactionFilter) error); ok {
+		r1 = rf(ctx, tf)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ExportSnapshot provides a mock function with given fields: ctx, w
+func (_m *IndexerDb) ExportSnapshot(ctx context.Context, w io.Writer) (uint64, error) {
+	ret := _m.Called(ctx, w)
+
+	var r0 uint64
+	if rf, ok := ret.Get(0).(func(context.Context, io.Writer) uint64); ok {
+		r0 = rf(ctx, w)
+	} else {
+		r0 = ret.Get(0).(uint64)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, io.Writer) error); ok {
+		r1 = rf(ctx, w)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FlushCaches provides a mock function with given fields: ctx
+func (_m *IndexerDb) FlushCaches(ctx context.Context) error {
+	ret := _m.Called(ctx)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context) error); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // GetAccounts provides a mock function with given fields: ctx, opts
 func (_m *IndexerDb) GetAccounts(ctx context.Context, opts idb.AccountQueryOptions) (<-chan idb.AccountRow, uint64) {
 	ret := _m.Called(ctx, opts)
@@ -157,6 +369,78 @@ func (_m *IndexerDb) GetBlock(ctx context.Context, round uint64, options idb.Get
 	return r0, r1, r2
 }
 
+// GetBlockWithTransactions provides a mock function with given fields: ctx, round
+func (_m *IndexerDb) GetBlockWithTransactions(ctx context.Context, round uint64) (bookkeeping.BlockHeader, []idb.TxnRow, error) {
+	ret := _m.Called(ctx, round)
+
+	var r0 bookkeeping.BlockHeader
+	if rf, ok := ret.Get(0).(func(context.Context, uint64) bookkeeping.BlockHeader); ok {
+		r0 = rf(ctx, round)
+	} else {
+		r0 = ret.Get(0).(bookkeeping.BlockHeader)
+	}
+
+	var r1 []idb.TxnRow
+	if rf, ok := ret.Get(1).(func(context.Context, uint64) []idb.TxnRow); ok {
+		r1 = rf(ctx, round)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).([]idb.TxnRow)
+		}
+	}
+
+	var r2 error
+	if rf, ok := ret.Get(2).(func(context.Context, uint64) error); ok {
+		r2 = rf(ctx, round)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// GetExportRound provides a mock function with given fields: ctx, name
+func (_m *IndexerDb) GetExportRound(ctx context.Context, name string) (uint64, error) {
+	ret := _m.Called(ctx, name)
+
+	var r0 uint64
+	if rf, ok := ret.Get(0).(func(context.Context, string) uint64); ok {
+		r0 = rf(ctx, name)
+	} else {
+		r0 = ret.Get(0).(uint64)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, name)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetGenesisHash provides a mock function with given fields:
+func (_m *IndexerDb) GetGenesisHash() (string, error) {
+	ret := _m.Called()
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func() string); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetNextRoundToAccount provides a mock function with given fields:
 func (_m *IndexerDb) GetNextRoundToAccount() (uint64, error) {
 	ret := _m.Called()
@@ -178,6 +462,48 @@ func (_m *IndexerDb) GetNextRoundToAccount() (uint64, error) {
 	return r0, r1
 }
 
+// GetRetention provides a mock function with given fields: ctx
+func (_m *IndexerDb) GetRetention(ctx context.Context) (idb.Retention, error) {
+	ret := _m.Called(ctx)
+
+	var r0 idb.Retention
+	if rf, ok := ret.Get(0).(func(context.Context) idb.Retention); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Get(0).(idb.Retention)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetServerSettings provides a mock function with given fields: ctx
+func (_m *IndexerDb) GetServerSettings(ctx context.Context) (idb.ServerSettings, error) {
+	ret := _m.Called(ctx)
+
+	var r0 idb.ServerSettings
+	if rf, ok := ret.Get(0).(func(context.Context) idb.ServerSettings); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Get(0).(idb.ServerSettings)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetSpecialAccounts provides a mock function with given fields:
 func (_m *IndexerDb) GetSpecialAccounts() (transactions.SpecialAddresses, error) {
 	ret := _m.Called()
@@ -220,6 +546,41 @@ func (_m *IndexerDb) Health() (idb.Health, error) {
 	return r0, r1
 }
 
+// ImportSnapshot provides a mock function with given fields: ctx, r
+func (_m *IndexerDb) ImportSnapshot(ctx context.Context, r io.Reader) (uint64, error) {
+	ret := _m.Called(ctx, r)
+
+	var r0 uint64
+	if rf, ok := ret.Get(0).(func(context.Context, io.Reader) uint64); ok {
+		r0 = rf(ctx, r)
+	} else {
+		r0 = ret.Get(0).(uint64)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, io.Reader) error); ok {
+		r1 = rf(ctx, r)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListenRounds provides a mock function with given fields: ctx, f
+func (_m *IndexerDb) ListenRounds(ctx context.Context, f func(uint64)) error {
+	ret := _m.Called(ctx, f)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, func(uint64)) error); ok {
+		r0 = rf(ctx, f)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // LoadGenesis provides a mock function with given fields: genesis
 func (_m *IndexerDb) LoadGenesis(genesis bookkeeping.Genesis) error {
 	ret := _m.Called(genesis)
@@ -234,6 +595,205 @@ func (_m *IndexerDb) LoadGenesis(genesis bookkeeping.Genesis) error {
 	return r0
 }
 
+// Migrations provides a mock function with given fields: ctx
+func (_m *IndexerDb) Migrations(ctx context.Context) ([]idb.MigrationInfo, error) {
+	ret := _m.Called(ctx)
+
+	var r0 []idb.MigrationInfo
+	if rf, ok := ret.Get(0).(func(context.Context) []idb.MigrationInfo); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]idb.MigrationInfo)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// PaginationSecret provides a mock function with given fields: ctx
+func (_m *IndexerDb) PaginationSecret(ctx context.Context) ([]byte, error) {
+	ret := _m.Called(ctx)
+
+	var r0 []byte
+	if rf, ok := ret.Get(0).(func(context.Context) []byte); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]byte)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// PruneParticipation provides a mock function with given fields: ctx, round, maxRounds, archive
+func (_m *IndexerDb) PruneParticipation(ctx context.Context, round uint64, maxRounds uint64, archive io.Writer) (uint64, error) {
+	ret := _m.Called(ctx, round, maxRounds, archive)
+
+	var r0 uint64
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, uint64, io.Writer) uint64); ok {
+		r0 = rf(ctx, round, maxRounds, archive)
+	} else {
+		r0 = ret.Get(0).(uint64)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, uint64, uint64, io.Writer) error); ok {
+		r1 = rf(ctx, round, maxRounds, archive)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// PruneTransactions provides a mock function with given fields: ctx, round, maxRounds
+func (_m *IndexerDb) PruneTransactions(ctx context.Context, round uint64, maxRounds uint64) (uint64, error) {
+	ret := _m.Called(ctx, round, maxRounds)
+
+	var r0 uint64
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, uint64) uint64); ok {
+		r0 = rf(ctx, round, maxRounds)
+	} else {
+		r0 = ret.Get(0).(uint64)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, uint64, uint64) error); ok {
+		r1 = rf(ctx, round, maxRounds)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Rederive provides a mock function with given fields: ctx, genesis, repair
+func (_m *IndexerDb) Rederive(ctx context.Context, genesis bookkeeping.Genesis, repair bool) ([]idb.RederivedTable, error) {
+	ret := _m.Called(ctx, genesis, repair)
+
+	var r0 []idb.RederivedTable
+	if rf, ok := ret.Get(0).(func(context.Context, bookkeeping.Genesis, bool) []idb.RederivedTable); ok {
+		r0 = rf(ctx, genesis, repair)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]idb.RederivedTable)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, bookkeeping.Genesis, bool) error); ok {
+		r1 = rf(ctx, genesis, repair)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Reprocess provides a mock function with given fields: ctx, genesis, blocks
+func (_m *IndexerDb) Reprocess(ctx context.Context, genesis bookkeeping.Genesis, blocks []*bookkeeping.Block) ([]idb.RederivedTable, error) {
+	ret := _m.Called(ctx, genesis, blocks)
+
+	var r0 []idb.RederivedTable
+	if rf, ok := ret.Get(0).(func(context.Context, bookkeeping.Genesis, []*bookkeeping.Block) []idb.RederivedTable); ok {
+		r0 = rf(ctx, genesis, blocks)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]idb.RederivedTable)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, bookkeeping.Genesis, []*bookkeeping.Block) error); ok {
+		r1 = rf(ctx, genesis, blocks)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RollbackMigrations provides a mock function with given fields: ctx, count
+func (_m *IndexerDb) RollbackMigrations(ctx context.Context, count int) ([]string, error) {
+	ret := _m.Called(ctx, count)
+
+	var r0 []string
+	if rf, ok := ret.Get(0).(func(context.Context, int) []string); ok {
+		r0 = rf(ctx, count)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, count)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RunMaintenance provides a mock function with given fields: ctx, opts
+func (_m *IndexerDb) RunMaintenance(ctx context.Context, opts idb.MaintenanceOptions) error {
+	ret := _m.Called(ctx, opts)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, idb.MaintenanceOptions) error); ok {
+		r0 = rf(ctx, opts)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RunPendingMigrations provides a mock function with given fields:
+func (_m *IndexerDb) RunPendingMigrations() error {
+	ret := _m.Called()
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func() error); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SetExportRound provides a mock function with given fields: ctx, name, round
+func (_m *IndexerDb) SetExportRound(ctx context.Context, name string, round uint64) error {
+	ret := _m.Called(ctx, name, round)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, uint64) error); ok {
+		r0 = rf(ctx, name, round)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // Transactions provides a mock function with given fields: ctx, tf
 func (_m *IndexerDb) Transactions(ctx context.Context, tf idb.TransactionFilter) (<-chan idb.TxnRow, uint64) {
 	ret := _m.Called(ctx, tf)
@@ -256,3 +816,26 @@ func (_m *IndexerDb) Transactions(ctx context.Context, tf idb.TransactionFilter)
 
 	return r0, r1
 }
+
+// Webhooks provides a mock function with given fields: ctx
+func (_m *IndexerDb) Webhooks(ctx context.Context) ([]idb.Webhook, error) {
+	ret := _m.Called(ctx)
+
+	var r0 []idb.Webhook
+	if rf, ok := ret.Get(0).(func(context.Context) []idb.Webhook); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]idb.Webhook)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
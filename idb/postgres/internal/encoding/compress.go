@@ -0,0 +1,64 @@
+package encoding
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// compressMinBytes is the size from which encoded transactions are compressed.
+// Smaller transactions barely compress and postgres already compresses large
+// values with its own algorithm.
+const compressMinBytes = 512
+
+// zstdMagic begins every zstd frame. Transactions are encoded as msgpack maps,
+// which never start with it, so compressed and uncompressed transactions can be
+// stored in the same column.
+var zstdMagic = []byte{0x28, 0xb5, 0x2f, 0xfd}
+
+// The encoder and decoder are safe for concurrent use with EncodeAll() and
+// DecodeAll().
+var (
+	zstdEncoder *zstd.Encoder
+	zstdDecoder *zstd.Decoder
+)
+
+func init() {
+	var err error
+	zstdEncoder, err = zstd.NewWriter(nil)
+	if err != nil {
+		panic(err)
+	}
+	zstdDecoder, err = zstd.NewReader(nil)
+	if err != nil {
+		panic(err)
+	}
+}
+
+// CompressTxnBytes compresses a msgpack encoded transaction with zstd. Small
+// transactions, and those which zstd does not make smaller, are returned
+// unchanged.
+func CompressTxnBytes(txnbytes []byte) []byte {
+	if len(txnbytes) < compressMinBytes {
+		return txnbytes
+	}
+	compressed := zstdEncoder.EncodeAll(txnbytes, make([]byte, 0, len(txnbytes)))
+	if len(compressed) >= len(txnbytes) {
+		return txnbytes
+	}
+	return compressed
+}
+
+// DecompressTxnBytes returns the msgpack encoding of a transaction which was
+// written with or without compression.
+func DecompressTxnBytes(txnbytes []byte) ([]byte, error) {
+	if !bytes.HasPrefix(txnbytes, zstdMagic) {
+		return txnbytes, nil
+	}
+	res, err := zstdDecoder.DecodeAll(txnbytes, nil)
+	if err != nil {
+		return nil, fmt.Errorf("DecompressTxnBytes() err: %w", err)
+	}
+	return res, nil
+}
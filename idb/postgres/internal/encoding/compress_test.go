@@ -0,0 +1,32 @@
+package encoding
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompressTxnBytes(t *testing.T) {
+	// A map with a large repetitive value, like an approval program.
+	txnbytes := append([]byte{0x81, 0xa4, 'a', 'p', 'a', 'p', 0xc5, 0x10, 0x00},
+		bytes.Repeat([]byte{0x01, 0x02}, 2048)...)
+
+	compressed := CompressTxnBytes(txnbytes)
+	assert.Less(t, len(compressed), len(txnbytes))
+	assert.True(t, bytes.HasPrefix(compressed, zstdMagic))
+
+	decompressed, err := DecompressTxnBytes(compressed)
+	require.NoError(t, err)
+	assert.Equal(t, txnbytes, decompressed)
+}
+
+func TestCompressTxnBytesSmall(t *testing.T) {
+	txnbytes := []byte{0x81, 0xa3, 't', 'x', 'n', 0x80}
+	assert.Equal(t, txnbytes, CompressTxnBytes(txnbytes))
+
+	decompressed, err := DecompressTxnBytes(txnbytes)
+	require.NoError(t, err)
+	assert.Equal(t, txnbytes, decompressed)
+}
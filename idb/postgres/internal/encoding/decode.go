@@ -7,6 +7,7 @@ import (
 	"github.com/algorand/go-algorand/data/basics"
 	"github.com/algorand/go-algorand/data/bookkeeping"
 	"github.com/algorand/go-algorand/data/transactions"
+	"github.com/algorand/go-algorand/ledger/ledgercore"
 	"github.com/algorand/go-algorand/protocol"
 )
 
@@ -304,3 +305,14 @@ func DecodeSpecialAddresses(data []byte) (transactions.SpecialAddresses, error)
 
 	return unconvertSpecialAddresses(special), nil
 }
+
+// DecodeAccountTotals decodes account totals from json.
+func DecodeAccountTotals(data []byte) (ledgercore.AccountTotals, error) {
+	var totals ledgercore.AccountTotals
+	err := DecodeJSON(data, &totals)
+	if err != nil {
+		return ledgercore.AccountTotals{}, err
+	}
+
+	return totals, nil
+}
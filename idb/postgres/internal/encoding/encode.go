@@ -7,6 +7,7 @@ import (
 	"github.com/algorand/go-algorand/data/basics"
 	"github.com/algorand/go-algorand/data/bookkeeping"
 	"github.com/algorand/go-algorand/data/transactions"
+	"github.com/algorand/go-algorand/ledger/ledgercore"
 	"github.com/algorand/go-codec/codec"
 
 	"github.com/algorand/indexer/util"
@@ -246,6 +247,11 @@ func EncodeSpecialAddresses(special transactions.SpecialAddresses) []byte {
 	return EncodeJSON(convertSpecialAddresses(special))
 }
 
+// EncodeAccountTotals encodes account totals into json.
+func EncodeAccountTotals(totals ledgercore.AccountTotals) []byte {
+	return EncodeJSON(totals)
+}
+
 func init() {
 	jsonCodecHandle = new(codec.JsonHandle)
 	jsonCodecHandle.ErrorIfNoField = true
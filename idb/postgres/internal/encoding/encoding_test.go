@@ -7,6 +7,7 @@ import (
 	"github.com/algorand/go-algorand/data/basics"
 	"github.com/algorand/go-algorand/data/bookkeeping"
 	"github.com/algorand/go-algorand/data/transactions"
+	"github.com/algorand/go-algorand/ledger/ledgercore"
 	"github.com/algorand/go-algorand/protocol"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -422,3 +423,28 @@ func TestSpecialAddressesEncoding(t *testing.T) {
 	require.NoError(t, err)
 	assert.Equal(t, special, specialNew)
 }
+
+// Test that decoding the encoded account totals results in the same object.
+func TestAccountTotalsEncoding(t *testing.T) {
+	totals := ledgercore.AccountTotals{
+		Online: ledgercore.AlgoCount{
+			Money:       basics.MicroAlgos{Raw: 1},
+			RewardUnits: 2,
+		},
+		Offline: ledgercore.AlgoCount{
+			Money:       basics.MicroAlgos{Raw: 3},
+			RewardUnits: 4,
+		},
+		NotParticipating: ledgercore.AlgoCount{
+			Money:       basics.MicroAlgos{Raw: 5},
+			RewardUnits: 6,
+		},
+		RewardsLevel: 7,
+	}
+
+	buf := EncodeAccountTotals(totals)
+
+	totalsNew, err := DecodeAccountTotals(buf)
+	require.NoError(t, err)
+	assert.Equal(t, totals, totalsNew)
+}
@@ -0,0 +1,281 @@
+package ledgerforevaluator
+
+import (
+	"container/list"
+	"sync"
+
+	"github.com/algorand/go-algorand/data/basics"
+	"github.com/algorand/go-algorand/data/bookkeeping"
+	"github.com/algorand/go-algorand/ledger/ledgercore"
+)
+
+// Creatable identifies an asset or an application.
+type Creatable struct {
+	Index basics.CreatableIndex
+	Type  basics.CreatableType
+}
+
+// creator is the result of looking up the creator of a Creatable.
+type creator struct {
+	address basics.Address
+	exists  bool
+}
+
+type cacheEntry struct {
+	// basics.Address, Creatable or basics.Round.
+	key interface{}
+	// *basics.AccountData, nil if the account does not exist, creator or
+	// bookkeeping.BlockHeader.
+	value interface{}
+}
+
+// lru is a map which evicts the least recently used entries when it is full.
+type lru struct {
+	capacity int
+	entries  map[interface{}]*list.Element
+	// Most recently used first.
+	order *list.List
+}
+
+func makeLRU(capacity int) lru {
+	return lru{
+		capacity: capacity,
+		entries:  make(map[interface{}]*list.Element),
+		order:    list.New(),
+	}
+}
+
+func (l *lru) get(key interface{}) (interface{}, bool) {
+	elem, ok := l.entries[key]
+	if !ok {
+		return nil, false
+	}
+	l.order.MoveToFront(elem)
+	return elem.Value.(*cacheEntry).value, true
+}
+
+func (l *lru) put(key interface{}, value interface{}) {
+	if l.capacity <= 0 {
+		return
+	}
+	if elem, ok := l.entries[key]; ok {
+		elem.Value.(*cacheEntry).value = value
+		l.order.MoveToFront(elem)
+		return
+	}
+	l.entries[key] = l.order.PushFront(&cacheEntry{key: key, value: value})
+	for l.order.Len() > l.capacity {
+		oldest := l.order.Back()
+		l.order.Remove(oldest)
+		delete(l.entries, oldest.Value.(*cacheEntry).key)
+	}
+}
+
+func (l *lru) clear() {
+	l.entries = make(map[interface{}]*list.Element)
+	l.order.Init()
+}
+
+// headerCacheSize is the number of block headers kept by Cache. The evaluator
+// mostly asks for the headers of the last few rounds.
+const headerCacheSize = 64
+
+// Cache keeps the accounts and creators which were loaded for evaluating a
+// block for the following blocks, so that accounts which are used in every
+// round aren't read from the database again. The least recently used entries
+// are evicted when it is full. It also keeps a small number of block headers,
+// which never change once the block is written.
+//
+// The cache is only correct if every change of the account state goes through
+// ApplyDelta(), or the cache is cleared. Entries which were loaded in a
+// transaction which is rolled back must be cleared as well. The account data is
+// shared with the evaluator, which copies it before modifying it.
+type Cache struct {
+	mu      sync.Mutex
+	entries lru
+	headers lru
+	// round is the next round to account which the entries are valid for.
+	round basics.Round
+	// suspended counts the callers of Suspend() which did not resume.
+	suspended int
+}
+
+// MakeCache creates a cache of at most `capacity` accounts and creators.
+func MakeCache(capacity int) *Cache {
+	return &Cache{
+		entries: makeLRU(capacity),
+		headers: makeLRU(headerCacheSize),
+	}
+}
+
+func (c *Cache) get(key interface{}) (interface{}, bool) {
+	if c == nil {
+		return nil, false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.suspended > 0 {
+		return nil, false
+	}
+	return c.entries.get(key)
+}
+
+func (c *Cache) put(key interface{}, value interface{}) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.suspended > 0 {
+		return
+	}
+	c.entries.put(key, value)
+}
+
+func (c *Cache) getAccount(address basics.Address) (*basics.AccountData, bool) {
+	value, ok := c.get(address)
+	if !ok {
+		return nil, false
+	}
+	return value.(*basics.AccountData), true
+}
+
+func (c *Cache) putAccount(address basics.Address, accountData *basics.AccountData) {
+	c.put(address, accountData)
+}
+
+func (c *Cache) getCreator(key Creatable) (creator, bool) {
+	value, ok := c.get(key)
+	if !ok {
+		return creator{}, false
+	}
+	return value.(creator), true
+}
+
+func (c *Cache) putCreator(key Creatable, value creator) {
+	c.put(key, value)
+}
+
+func (c *Cache) getHeader(round basics.Round) (bookkeeping.BlockHeader, bool) {
+	if c == nil {
+		return bookkeeping.BlockHeader{}, false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.suspended > 0 {
+		return bookkeeping.BlockHeader{}, false
+	}
+	value, ok := c.headers.get(round)
+	if !ok {
+		return bookkeeping.BlockHeader{}, false
+	}
+	return value.(bookkeeping.BlockHeader), true
+}
+
+// AddHeader adds the header of a block which is written to the database.
+func (c *Cache) AddHeader(header bookkeeping.BlockHeader) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.suspended > 0 {
+		return
+	}
+	c.headers.put(header.Round, header)
+}
+
+// Len returns the number of cached accounts and creators.
+func (c *Cache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.entries.order.Len()
+}
+
+// Clear removes all the entries, including the block headers.
+func (c *Cache) Clear() {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.entries.clear()
+	c.headers.clear()
+}
+
+// Suspend clears the cache and stops using it until the returned function is
+// called, e.g. while a migration changes the account state between the blocks.
+func (c *Cache) Suspend() func() {
+	if c == nil {
+		return func() {}
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.suspended++
+	c.entries.clear()
+	c.headers.clear()
+	return func() {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+
+		c.suspended--
+	}
+}
+
+// StartRound prepares the cache for evaluating `round`. The cache is cleared if
+// the previous block which used it was not the one before `round`, e.g. because
+// the database was changed by something other than the import.
+func (c *Cache) StartRound(round basics.Round) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.round != round {
+		c.entries.clear()
+		c.headers.clear()
+	}
+	c.round = round
+}
+
+// ApplyDelta stores the accounts and creators which were modified by the block
+// of `delta` as they are written to the database, and moves on to the next
+// round. Blocks which use the same accounts as the previous block, e.g. calls of
+// a busy application, then don't read them again.
+func (c *Cache) ApplyDelta(delta ledgercore.StateDelta) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.round++
+	if c.suspended > 0 {
+		return
+	}
+
+	for i := 0; i < delta.Accts.Len(); i++ {
+		address, accountData := delta.Accts.GetByIdx(i)
+		// The writer deletes empty accounts.
+		if accountData.IsZero() {
+			c.entries.put(address, (*basics.AccountData)(nil))
+		} else {
+			c.entries.put(address, &accountData)
+		}
+	}
+	for index, modified := range delta.Creatables {
+		value := creator{}
+		if modified.Created {
+			value = creator{address: modified.Creator, exists: true}
+		}
+		c.entries.put(Creatable{Index: index, Type: modified.Ctype}, value)
+	}
+}
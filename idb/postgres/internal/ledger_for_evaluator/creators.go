@@ -0,0 +1,102 @@
+package ledgerforevaluator
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/algorand/go-algorand/data/basics"
+
+	pgutil "github.com/algorand/indexer/idb/postgres/internal/util"
+)
+
+// loadCreators returns the creators of the given creatables, with exists unset
+// for those that were not found. Cached creators are not read from the database,
+// the assets and applications of the rest are read in one query.
+func (l *LedgerForEvaluator) loadCreators(creatables map[Creatable]struct{}) (map[Creatable]creator, error) {
+	res := make(map[Creatable]creator, len(creatables))
+
+	var assets []int64
+	var apps []int64
+	for creatable := range creatables {
+		if cached, ok := l.cache.getCreator(creatable); ok {
+			res[creatable] = cached
+			continue
+		}
+		switch creatable.Type {
+		case basics.AssetCreatable:
+			assets = append(assets, int64(creatable.Index))
+		case basics.AppCreatable:
+			apps = append(apps, int64(creatable.Index))
+		default:
+			return nil, fmt.Errorf("loadCreators() unknown creatable type %d", creatable.Type)
+		}
+		// Not found unless the query returns it.
+		res[creatable] = creator{}
+	}
+	countLookups(creatorsLookupName, cacheSourceName, len(creatables)-len(assets)-len(apps))
+	if len(assets) == 0 && len(apps) == 0 {
+		return res, nil
+	}
+
+	start := time.Now()
+	rows, err := l.tx.Query(
+		context.Background(), pgutil.Query(l.tx, statements, creatorsStmtName),
+		assets, apps)
+	if err != nil {
+		return nil, fmt.Errorf("loadCreators() query err: %w", err)
+	}
+	defer rows.Close()
+
+	var ctype int
+	var index uint64
+	var buf []byte
+	for rows.Next() {
+		err = rows.Scan(&ctype, &index, &buf)
+		if err != nil {
+			return nil, fmt.Errorf("loadCreators() scan row err: %w", err)
+		}
+
+		value := creator{exists: true}
+		copy(value.address[:], buf)
+		creatable := Creatable{
+			Index: basics.CreatableIndex(index),
+			Type:  basics.CreatableType(ctype),
+		}
+		res[creatable] = value
+	}
+	err = rows.Err()
+	if err != nil {
+		return nil, fmt.Errorf("loadCreators() scan end err: %w", err)
+	}
+	observeLookup(creatorsLookupName, start, len(assets)+len(apps))
+
+	for _, index := range assets {
+		creatable := Creatable{Index: basics.CreatableIndex(index), Type: basics.AssetCreatable}
+		l.cache.putCreator(creatable, res[creatable])
+	}
+	for _, index := range apps {
+		creatable := Creatable{Index: basics.CreatableIndex(index), Type: basics.AppCreatable}
+		l.cache.putCreator(creatable, res[creatable])
+	}
+
+	return res, nil
+}
+
+// GetCreators returns the creators of the given assets and applications which
+// exist, read with a single query. It generalizes GetCreatorForRound() to any
+// number of creatables of both types.
+func (l *LedgerForEvaluator) GetCreators(creatables map[Creatable]struct{}) (map[Creatable]basics.Address, error) {
+	creators, err := l.loadCreators(creatables)
+	if err != nil {
+		return nil, fmt.Errorf("GetCreators() err: %w", err)
+	}
+
+	res := make(map[Creatable]basics.Address, len(creators))
+	for creatable, value := range creators {
+		if value.exists {
+			res[creatable] = value.address
+		}
+	}
+	return res, nil
+}
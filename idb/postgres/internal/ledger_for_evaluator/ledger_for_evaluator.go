@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/algorand/go-algorand/config"
 	"github.com/algorand/go-algorand/crypto"
@@ -11,28 +12,31 @@ import (
 	"github.com/algorand/go-algorand/data/bookkeeping"
 	"github.com/algorand/go-algorand/data/transactions"
 	"github.com/algorand/go-algorand/ledger"
-	"github.com/algorand/go-algorand/ledger/ledgercore"
 	"github.com/jackc/pgx/v4"
 
 	"github.com/algorand/indexer/idb/postgres/internal/encoding"
+	pgutil "github.com/algorand/indexer/idb/postgres/internal/util"
 )
 
 const (
 	blockHeaderStmtName    = "block_header"
-	assetCreatorStmtName   = "asset_creator"
-	appCreatorStmtName     = "app_creator"
+	creatorsStmtName       = "creators"
 	accountStmtName        = "account"
 	assetHoldingsStmtName  = "asset_holdings"
 	assetParamsStmtName    = "asset_params"
 	appParamsStmtName      = "app_params"
 	appLocalStatesStmtName = "app_local_states"
+	totalsStmtName         = "totals"
+	sumAccountsStmtName    = "sum_accounts"
 )
 
 var statements = map[string]string{
 	blockHeaderStmtName: "SELECT header FROM block_header WHERE round = $1",
-	assetCreatorStmtName: "SELECT creator_addr FROM asset " +
-		"WHERE index = $1 AND NOT deleted",
-	appCreatorStmtName: "SELECT creator FROM app WHERE index = $1 AND NOT deleted",
+	// The first column is the basics.CreatableType.
+	creatorsStmtName: "SELECT 0, index, creator_addr FROM asset " +
+		"WHERE index = ANY($1) AND NOT deleted " +
+		"UNION ALL SELECT 1, index, creator FROM app " +
+		"WHERE index = ANY($2) AND NOT deleted",
 	accountStmtName: "SELECT microalgos, rewardsbase, rewards_total, account_data " +
 		"FROM account WHERE addr = $1 AND NOT deleted",
 	assetHoldingsStmtName: "SELECT assetid, amount, frozen FROM account_asset " +
@@ -42,6 +46,13 @@ var statements = map[string]string{
 	appParamsStmtName: "SELECT index, params FROM app WHERE creator = $1 AND NOT deleted",
 	appLocalStatesStmtName: "SELECT app, localstate FROM account_app " +
 		"WHERE addr = $1 AND NOT deleted",
+	totalsStmtName: "SELECT totals FROM account_totals WHERE round = $1",
+	// Sums the accounts other than the special accounts $3 and $4 by status. $1
+	// is the reward unit and $2 the rewards level.
+	sumAccountsStmtName: "SELECT COALESCE((account_data->>'onl')::int, 0), " +
+		"sum(microalgos)::bigint, sum(microalgos / $1)::bigint, " +
+		"sum((microalgos / $1) * ($2 - rewardsbase))::bigint " +
+		"FROM account WHERE NOT deleted AND addr <> $3 AND addr <> $4 GROUP BY 1",
 }
 
 // LedgerForEvaluator implements the ledgerForEvaluator interface from
@@ -56,48 +67,75 @@ type LedgerForEvaluator struct {
 	specialAddresses transactions.SpecialAddresses
 	// Value is nil if account was looked up but not found.
 	preloadedAccountData map[basics.Address]*basics.AccountData
+	// Creators loaded by PreloadFromPayset().
+	preloadedCreators map[Creatable]creator
+	// cache keeps the loaded accounts and creators across rounds, may be nil.
+	cache *Cache
+	// lookupBatchSize is the maximum number of accounts read in one batch, 0
+	// for no limit.
+	lookupBatchSize int
+	// pipelineLookups queries the resources of the accounts in the same batch
+	// as the accounts.
+	pipelineLookups bool
 }
 
-// MakeLedgerForEvaluator creates a LedgerForEvaluator object.
-func MakeLedgerForEvaluator(tx pgx.Tx, genesisHash crypto.Digest, specialAddresses transactions.SpecialAddresses) (LedgerForEvaluator, error) {
+// MakeLedgerForEvaluator creates a LedgerForEvaluator object. `cache` may be nil.
+func MakeLedgerForEvaluator(tx pgx.Tx, genesisHash crypto.Digest, specialAddresses transactions.SpecialAddresses, cache *Cache) (LedgerForEvaluator, error) {
 	l := LedgerForEvaluator{
 		tx:               tx,
 		genesisHash:      genesisHash,
 		specialAddresses: specialAddresses,
+		cache:            cache,
 	}
 
-	for name, query := range statements {
-		_, err := tx.Prepare(context.Background(), name, query)
-		if err != nil {
-			return LedgerForEvaluator{},
-				fmt.Errorf("MakeLedgerForEvaluator() prepare statement err: %w", err)
-		}
+	err := pgutil.PrepareStatements(tx, statements)
+	if err != nil {
+		return LedgerForEvaluator{}, fmt.Errorf("MakeLedgerForEvaluator() err: %w", err)
 	}
 
 	return l, nil
 }
 
-// Close shuts down LedgerForEvaluator.
+// SetLookupBatching limits the number of accounts which are read from the
+// database in one batch to `size`, 0 for no limit. By default the accounts of
+// a batch are read first and then the assets and applications of those which
+// exist, which takes two round trips. `pipeline` queries them together in one
+// round trip, but also for the accounts which don't exist.
+func (l *LedgerForEvaluator) SetLookupBatching(size int, pipeline bool) {
+	l.lookupBatchSize = size
+	l.pipelineLookups = pipeline
+}
+
+// Close shuts down LedgerForEvaluator. The statements stay prepared on the
+// connection for the next block.
 func (l *LedgerForEvaluator) Close() {
-	for name := range statements {
-		l.tx.Conn().Deallocate(context.Background(), name)
-	}
 }
 
 // BlockHdr is part of go-algorand's ledgerForEvaluator interface.
+// The headers of recent rounds are served from the cache.
 func (l LedgerForEvaluator) BlockHdr(round basics.Round) (bookkeeping.BlockHeader, error) {
-	row := l.tx.QueryRow(context.Background(), blockHeaderStmtName, uint64(round))
+	if header, ok := l.cache.getHeader(round); ok {
+		countLookups(blockHeaderLookupName, cacheSourceName, 1)
+		return header, nil
+	}
+
+	start := time.Now()
+	row := l.tx.QueryRow(
+		context.Background(), pgutil.Query(l.tx, statements, blockHeaderStmtName),
+		uint64(round))
 
 	var header []byte
 	err := row.Scan(&header)
 	if err != nil {
 		return bookkeeping.BlockHeader{}, fmt.Errorf("BlockHdr() scan row err: %w", err)
 	}
+	observeLookup(blockHeaderLookupName, start, 1)
 
 	res, err := encoding.DecodeBlockHeader(header)
 	if err != nil {
 		return bookkeeping.BlockHeader{}, fmt.Errorf("BlockHdr() decode header err: %w", err)
 	}
+	l.cache.AddHeader(res)
 
 	return res, nil
 }
@@ -250,22 +288,15 @@ func (l *LedgerForEvaluator) parseAccountAppTable(rows pgx.Rows) (map[basics.App
 
 // Load rows from the account table for the given addresses. nil is stored for those
 // accounts that were not found. Uses batching.
-func (l *LedgerForEvaluator) loadAccountTable(addresses map[basics.Address]struct{}) (map[basics.Address]*basics.AccountData, error) {
-	addressesArr := make([]basics.Address, 0, len(addresses))
-	for address := range addresses {
-		if !l.isSpecialAddress(address) {
-			addressesArr = append(addressesArr, address)
-		}
+func (l *LedgerForEvaluator) loadAccountTable(addresses []basics.Address) (map[basics.Address]*basics.AccountData, error) {
+	batch := pgutil.MakeBatch(l.tx, statements)
+	for i := range addresses {
+		batch.Queue(accountStmtName, addresses[i][:])
 	}
 
-	var batch pgx.Batch
-	for i := range addressesArr {
-		batch.Queue(accountStmtName, addressesArr[i][:])
-	}
-
-	results := l.tx.SendBatch(context.Background(), &batch)
+	results := l.tx.SendBatch(context.Background(), &batch.Batch)
 	res := make(map[basics.Address]*basics.AccountData, len(addresses))
-	for _, address := range addressesArr {
+	for _, address := range addresses {
 		row := results.QueryRow()
 
 		accountData := new(basics.AccountData)
@@ -295,7 +326,7 @@ func (l *LedgerForEvaluator) loadAccountTable(addresses map[basics.Address]struc
 // Load all creatables for the non-nil account data from the provided map into that
 // account data. Uses batching.
 func (l *LedgerForEvaluator) loadCreatables(accountDataMap *map[basics.Address]*basics.AccountData) error {
-	var batch pgx.Batch
+	batch := pgutil.MakeBatch(l.tx, statements)
 
 	existingAddresses := make([]basics.Address, 0, len(*accountDataMap))
 	for address, accountData := range *accountDataMap {
@@ -317,7 +348,7 @@ func (l *LedgerForEvaluator) loadCreatables(accountDataMap *map[basics.Address]*
 		batch.Queue(appLocalStatesStmtName, existingAddresses[i][:])
 	}
 
-	results := l.tx.SendBatch(context.Background(), &batch)
+	results := l.tx.SendBatch(context.Background(), &batch.Batch)
 
 	for _, address := range existingAddresses {
 		rows, err := results.Query()
@@ -368,18 +399,164 @@ func (l *LedgerForEvaluator) loadCreatables(accountDataMap *map[basics.Address]*
 	return nil
 }
 
+// Load the accounts with the given addresses and their creatables in a single
+// batch. nil is stored for those accounts that were not found.
+func (l *LedgerForEvaluator) loadAccountsPipelined(addresses []basics.Address) (map[basics.Address]*basics.AccountData, error) {
+	batch := pgutil.MakeBatch(l.tx, statements)
+	for i := range addresses {
+		batch.Queue(accountStmtName, addresses[i][:])
+		batch.Queue(assetHoldingsStmtName, addresses[i][:])
+		batch.Queue(assetParamsStmtName, addresses[i][:])
+		batch.Queue(appParamsStmtName, addresses[i][:])
+		batch.Queue(appLocalStatesStmtName, addresses[i][:])
+	}
+
+	results := l.tx.SendBatch(context.Background(), &batch.Batch)
+	res := make(map[basics.Address]*basics.AccountData, len(addresses))
+	for _, address := range addresses {
+		accountData := new(basics.AccountData)
+		var exists bool
+		var err error
+
+		*accountData, exists, err = l.parseAccountTable(results.QueryRow())
+		if err != nil {
+			return nil, fmt.Errorf("loadAccountsPipelined() err: %w", err)
+		}
+
+		rows, err := results.Query()
+		if err != nil {
+			return nil, fmt.Errorf("loadAccountsPipelined() query asset holdings err: %w", err)
+		}
+		accountData.Assets, err = l.parseAccountAssetTable(rows)
+		if err != nil {
+			return nil, fmt.Errorf("loadAccountsPipelined() err: %w", err)
+		}
+
+		rows, err = results.Query()
+		if err != nil {
+			return nil, fmt.Errorf("loadAccountsPipelined() query asset params err: %w", err)
+		}
+		accountData.AssetParams, err = l.parseAssetTable(rows)
+		if err != nil {
+			return nil, fmt.Errorf("loadAccountsPipelined() err: %w", err)
+		}
+
+		rows, err = results.Query()
+		if err != nil {
+			return nil, fmt.Errorf("loadAccountsPipelined() query app params err: %w", err)
+		}
+		accountData.AppParams, err = l.parseAppTable(rows)
+		if err != nil {
+			return nil, fmt.Errorf("loadAccountsPipelined() err: %w", err)
+		}
+
+		rows, err = results.Query()
+		if err != nil {
+			return nil, fmt.Errorf("loadAccountsPipelined() query app local states err: %w", err)
+		}
+		accountData.AppLocalStates, err = l.parseAccountAppTable(rows)
+		if err != nil {
+			return nil, fmt.Errorf("loadAccountsPipelined() err: %w", err)
+		}
+
+		if exists {
+			res[address] = accountData
+		} else {
+			res[address] = nil
+		}
+	}
+
+	err := results.Close()
+	if err != nil {
+		return nil, fmt.Errorf("loadAccountsPipelined() close results err: %w", err)
+	}
+
+	return res, nil
+}
+
+// Load the accounts with the given addresses from the database, in batches of
+// at most `lookupBatchSize` accounts. nil is stored for those accounts that
+// were not found.
+func (l *LedgerForEvaluator) loadAccountsFromDatabase(addresses map[basics.Address]struct{}) (map[basics.Address]*basics.AccountData, error) {
+	addressesArr := make([]basics.Address, 0, len(addresses))
+	for address := range addresses {
+		if !l.isSpecialAddress(address) {
+			addressesArr = append(addressesArr, address)
+		}
+	}
+
+	size := l.lookupBatchSize
+	if size <= 0 {
+		size = len(addressesArr)
+	}
+
+	res := make(map[basics.Address]*basics.AccountData, len(addresses))
+	for len(addressesArr) > 0 {
+		if size > len(addressesArr) {
+			size = len(addressesArr)
+		}
+		chunk := addressesArr[:size]
+		addressesArr = addressesArr[size:]
+
+		var accountDataMap map[basics.Address]*basics.AccountData
+		var err error
+		if l.pipelineLookups {
+			accountDataMap, err = l.loadAccountsPipelined(chunk)
+			if err != nil {
+				return nil, fmt.Errorf("loadAccountsFromDatabase() err: %w", err)
+			}
+		} else {
+			accountDataMap, err = l.loadAccountTable(chunk)
+			if err != nil {
+				return nil, fmt.Errorf("loadAccountsFromDatabase() err: %w", err)
+			}
+			err = l.loadCreatables(&accountDataMap)
+			if err != nil {
+				return nil, fmt.Errorf("loadAccountsFromDatabase() err: %w", err)
+			}
+		}
+
+		for address, accountData := range accountDataMap {
+			res[address] = accountData
+		}
+	}
+
+	return res, nil
+}
+
 // Return a map with all accounts for the given addresses, with nil for those accounts
-// that do not exist.
+// that do not exist. Cached accounts are not read from the database and the loaded
+// ones are added to the cache.
 func (l *LedgerForEvaluator) loadAccounts(addresses map[basics.Address]struct{}) (map[basics.Address]*basics.AccountData, error) {
-	res, err := l.loadAccountTable(addresses)
-	if err != nil {
-		return nil, fmt.Errorf("loadAccounts() err: %w", err)
+	cached := make(map[basics.Address]*basics.AccountData)
+	missing := make(map[basics.Address]struct{}, len(addresses))
+	for address := range addresses {
+		if accountData, ok := l.cache.getAccount(address); ok {
+			cached[address] = accountData
+		} else {
+			missing[address] = struct{}{}
+		}
 	}
 
-	err = l.loadCreatables(&res)
+	countLookups(accountsLookupName, cacheSourceName, len(cached))
+
+	if len(missing) == 0 {
+		return cached, nil
+	}
+
+	start := time.Now()
+	res, err := l.loadAccountsFromDatabase(missing)
 	if err != nil {
 		return nil, fmt.Errorf("loadAccounts() err: %w", err)
 	}
+	observeLookup(accountsLookupName, start, len(missing))
+
+	for address, accountData := range res {
+		l.cache.putAccount(address, accountData)
+	}
+	for address, accountData := range cached {
+		res[address] = accountData
+	}
 
 	return res, nil
 }
@@ -409,6 +586,7 @@ func (l LedgerForEvaluator) LookupWithoutRewards(round basics.Round, address bas
 	}
 
 	if accountData, ok := l.preloadedAccountData[address]; ok {
+		countLookups(accountsLookupName, preloadedSourceName, 1)
 		if accountData == nil {
 			return basics.AccountData{}, round, nil
 		}
@@ -430,30 +608,17 @@ func (l LedgerForEvaluator) LookupWithoutRewards(round basics.Round, address bas
 
 // GetCreatorForRound is part of go-algorand's ledgerForEvaluator interface.
 func (l LedgerForEvaluator) GetCreatorForRound(_ basics.Round, cindex basics.CreatableIndex, ctype basics.CreatableType) (basics.Address, bool, error) {
-	var row pgx.Row
-
-	switch ctype {
-	case basics.AssetCreatable:
-		row = l.tx.QueryRow(context.Background(), assetCreatorStmtName, uint64(cindex))
-	case basics.AppCreatable:
-		row = l.tx.QueryRow(context.Background(), appCreatorStmtName, uint64(cindex))
-	default:
-		panic("unknown creatable type")
+	key := Creatable{Index: cindex, Type: ctype}
+	if preloaded, ok := l.preloadedCreators[key]; ok {
+		countLookups(creatorsLookupName, preloadedSourceName, 1)
+		return preloaded.address, preloaded.exists, nil
 	}
 
-	var buf []byte
-	err := row.Scan(&buf)
-	if err == pgx.ErrNoRows {
-		return basics.Address{}, false, nil
-	}
+	creators, err := l.loadCreators(map[Creatable]struct{}{key: {}})
 	if err != nil {
 		return basics.Address{}, false, fmt.Errorf("GetCreatorForRound() err: %w", err)
 	}
-
-	var address basics.Address
-	copy(address[:], buf)
-
-	return address, true, nil
+	return creators[key].address, creators[key].exists, nil
 }
 
 // GenesisHash is part of go-algorand's ledgerForEvaluator interface.
@@ -461,14 +626,6 @@ func (l LedgerForEvaluator) GenesisHash() crypto.Digest {
 	return l.genesisHash
 }
 
-// Totals is part of go-algorand's ledgerForEvaluator interface.
-func (l LedgerForEvaluator) Totals(round basics.Round) (ledgercore.AccountTotals, error) {
-	// The evaluator uses totals only for recomputing the rewards pool balance. Indexer
-	// does not currently compute this balance, and we can return an empty struct
-	// here.
-	return ledgercore.AccountTotals{}, nil
-}
-
 // CompactCertVoters is part of go-algorand's ledgerForEvaluator interface.
 func (l LedgerForEvaluator) CompactCertVoters(basics.Round) (*ledger.VotersForRound, error) {
 	// This function is not used by evaluator.
@@ -8,6 +8,8 @@ import (
 	"github.com/algorand/go-algorand/data/basics"
 	"github.com/algorand/go-algorand/data/bookkeeping"
 	"github.com/algorand/go-algorand/data/transactions"
+	"github.com/algorand/go-algorand/ledger/ledgercore"
+	"github.com/algorand/go-algorand/protocol"
 	"github.com/jackc/pgx/v4"
 	"github.com/jackc/pgx/v4/pgxpool"
 	"github.com/stretchr/testify/assert"
@@ -51,7 +53,7 @@ func TestLedgerForEvaluatorBlockHdr(t *testing.T) {
 	defer tx.Rollback(context.Background())
 
 	l, err := ledger_for_evaluator.MakeLedgerForEvaluator(
-		tx, header.GenesisHash, transactions.SpecialAddresses{})
+		tx, header.GenesisHash, transactions.SpecialAddresses{}, nil)
 	require.NoError(t, err)
 	defer l.Close()
 
@@ -106,7 +108,7 @@ func TestLedgerForEvaluatorAccountTableBasic(t *testing.T) {
 
 	checkFunc := func(preload bool) {
 		l, err := ledger_for_evaluator.MakeLedgerForEvaluator(
-			tx, crypto.Digest{}, transactions.SpecialAddresses{})
+			tx, crypto.Digest{}, transactions.SpecialAddresses{}, nil)
 		require.NoError(t, err)
 
 		if preload {
@@ -148,7 +150,7 @@ func TestLedgerForEvaluatorAccountTableDeleted(t *testing.T) {
 
 	checkFunc := func(preload bool) {
 		l, err := ledger_for_evaluator.MakeLedgerForEvaluator(
-			tx, crypto.Digest{}, transactions.SpecialAddresses{})
+			tx, crypto.Digest{}, transactions.SpecialAddresses{}, nil)
 		require.NoError(t, err)
 
 		if preload {
@@ -177,7 +179,7 @@ func TestLedgerForEvaluatorAccountTableMissingAccount(t *testing.T) {
 
 	checkFunc := func(preload bool) {
 		l, err := ledger_for_evaluator.MakeLedgerForEvaluator(
-			tx, crypto.Digest{}, transactions.SpecialAddresses{})
+			tx, crypto.Digest{}, transactions.SpecialAddresses{}, nil)
 		require.NoError(t, err)
 
 		if preload {
@@ -221,7 +223,7 @@ func TestLedgerForEvaluatorAccountTableNullAccountData(t *testing.T) {
 	defer tx.Rollback(context.Background())
 
 	l, err := ledger_for_evaluator.MakeLedgerForEvaluator(
-		tx, crypto.Digest{}, transactions.SpecialAddresses{})
+		tx, crypto.Digest{}, transactions.SpecialAddresses{}, nil)
 	require.NoError(t, err)
 	defer l.Close()
 
@@ -259,7 +261,7 @@ func TestLedgerForEvaluatorAccountAssetTable(t *testing.T) {
 	defer tx.Rollback(context.Background())
 
 	l, err := ledger_for_evaluator.MakeLedgerForEvaluator(
-		tx, crypto.Digest{}, transactions.SpecialAddresses{})
+		tx, crypto.Digest{}, transactions.SpecialAddresses{}, nil)
 	require.NoError(t, err)
 	defer l.Close()
 
@@ -322,7 +324,7 @@ func TestLedgerForEvaluatorAssetTable(t *testing.T) {
 	defer tx.Rollback(context.Background())
 
 	l, err := ledger_for_evaluator.MakeLedgerForEvaluator(
-		tx, crypto.Digest{}, transactions.SpecialAddresses{})
+		tx, crypto.Digest{}, transactions.SpecialAddresses{}, nil)
 	require.NoError(t, err)
 	defer l.Close()
 
@@ -391,7 +393,7 @@ func TestLedgerForEvaluatorAppTable(t *testing.T) {
 	defer tx.Rollback(context.Background())
 
 	l, err := ledger_for_evaluator.MakeLedgerForEvaluator(
-		tx, crypto.Digest{}, transactions.SpecialAddresses{})
+		tx, crypto.Digest{}, transactions.SpecialAddresses{}, nil)
 	require.NoError(t, err)
 	defer l.Close()
 
@@ -458,7 +460,7 @@ func TestLedgerForEvaluatorAccountAppTable(t *testing.T) {
 	defer tx.Rollback(context.Background())
 
 	l, err := ledger_for_evaluator.MakeLedgerForEvaluator(
-		tx, crypto.Digest{}, transactions.SpecialAddresses{})
+		tx, crypto.Digest{}, transactions.SpecialAddresses{}, nil)
 	require.NoError(t, err)
 	defer l.Close()
 
@@ -528,47 +530,63 @@ func TestLedgerForEvaluatorLookupMultipleAccounts(t *testing.T) {
 		}
 	}
 
-	tx, err := db.BeginTx(context.Background(), readonlyRepeatableRead)
-	require.NoError(t, err)
-	defer tx.Rollback(context.Background())
-
-	l, err := ledger_for_evaluator.MakeLedgerForEvaluator(
-		tx, crypto.Digest{}, transactions.SpecialAddresses{})
-	require.NoError(t, err)
-	defer l.Close()
-
-	// Preload accounts so that batching is actually used.
-	{
-		addressesMap := make(map[basics.Address]struct{})
-		for _, address := range addresses {
-			addressesMap[address] = struct{}{}
-		}
-		err := l.PreloadAccounts(addressesMap)
-		require.NoError(t, err)
+	batchings := []struct {
+		name     string
+		size     int
+		pipeline bool
+	}{
+		{"one batch", 0, false},
+		{"small batches", 2, false},
+		{"pipelined", 0, true},
+		{"small pipelined batches", 2, true},
 	}
+	for _, batching := range batchings {
+		batching := batching
+		t.Run(batching.name, func(t *testing.T) {
+			tx, err := db.BeginTx(context.Background(), readonlyRepeatableRead)
+			require.NoError(t, err)
+			defer tx.Rollback(context.Background())
 
-	for i, address := range addresses {
-		accountData, _, err := l.LookupWithoutRewards(0, address)
-		require.NoError(t, err)
-
-		assert.Equal(t, len(seq), len(accountData.Assets))
-		assert.Equal(t, len(seq), len(accountData.AssetParams))
-		assert.Equal(t, len(seq), len(accountData.AppParams))
-		assert.Equal(t, len(seq), len(accountData.AppLocalStates))
-
-		for j := range seq {
-			_, ok := accountData.Assets[basics.AssetIndex(i+10*j+100)]
-			assert.True(t, ok)
-
-			_, ok = accountData.AssetParams[basics.AssetIndex(i+10*j+200)]
-			assert.True(t, ok)
-
-			_, ok = accountData.AppParams[basics.AppIndex(i+10*j+300)]
-			assert.True(t, ok)
-
-			_, ok = accountData.AppLocalStates[basics.AppIndex(i+10*j+400)]
-			assert.True(t, ok)
-		}
+			l, err := ledger_for_evaluator.MakeLedgerForEvaluator(
+				tx, crypto.Digest{}, transactions.SpecialAddresses{}, nil)
+			require.NoError(t, err)
+			defer l.Close()
+			l.SetLookupBatching(batching.size, batching.pipeline)
+
+			// Preload accounts so that batching is actually used.
+			{
+				addressesMap := make(map[basics.Address]struct{})
+				for _, address := range addresses {
+					addressesMap[address] = struct{}{}
+				}
+				err := l.PreloadAccounts(addressesMap)
+				require.NoError(t, err)
+			}
+
+			for i, address := range addresses {
+				accountData, _, err := l.LookupWithoutRewards(0, address)
+				require.NoError(t, err)
+
+				assert.Equal(t, len(seq), len(accountData.Assets))
+				assert.Equal(t, len(seq), len(accountData.AssetParams))
+				assert.Equal(t, len(seq), len(accountData.AppParams))
+				assert.Equal(t, len(seq), len(accountData.AppLocalStates))
+
+				for j := range seq {
+					_, ok := accountData.Assets[basics.AssetIndex(i+10*j+100)]
+					assert.True(t, ok)
+
+					_, ok = accountData.AssetParams[basics.AssetIndex(i+10*j+200)]
+					assert.True(t, ok)
+
+					_, ok = accountData.AppParams[basics.AppIndex(i+10*j+300)]
+					assert.True(t, ok)
+
+					_, ok = accountData.AppLocalStates[basics.AppIndex(i+10*j+400)]
+					assert.True(t, ok)
+				}
+			}
+		})
 	}
 }
 
@@ -587,7 +605,7 @@ func TestLedgerForEvaluatorAssetCreatorBasic(t *testing.T) {
 	defer tx.Rollback(context.Background())
 
 	l, err := ledger_for_evaluator.MakeLedgerForEvaluator(
-		tx, crypto.Digest{}, transactions.SpecialAddresses{})
+		tx, crypto.Digest{}, transactions.SpecialAddresses{}, nil)
 	require.NoError(t, err)
 	defer l.Close()
 
@@ -614,7 +632,7 @@ func TestLedgerForEvaluatorAssetCreatorDeleted(t *testing.T) {
 	defer tx.Rollback(context.Background())
 
 	l, err := ledger_for_evaluator.MakeLedgerForEvaluator(
-		tx, crypto.Digest{}, transactions.SpecialAddresses{})
+		tx, crypto.Digest{}, transactions.SpecialAddresses{}, nil)
 	require.NoError(t, err)
 	defer l.Close()
 
@@ -640,7 +658,7 @@ func TestLedgerForEvaluatorAppCreatorBasic(t *testing.T) {
 	defer tx.Rollback(context.Background())
 
 	l, err := ledger_for_evaluator.MakeLedgerForEvaluator(
-		tx, crypto.Digest{}, transactions.SpecialAddresses{})
+		tx, crypto.Digest{}, transactions.SpecialAddresses{}, nil)
 	require.NoError(t, err)
 	defer l.Close()
 
@@ -667,7 +685,7 @@ func TestLedgerForEvaluatorAppCreatorDeleted(t *testing.T) {
 	defer tx.Rollback(context.Background())
 
 	l, err := ledger_for_evaluator.MakeLedgerForEvaluator(
-		tx, crypto.Digest{}, transactions.SpecialAddresses{})
+		tx, crypto.Digest{}, transactions.SpecialAddresses{}, nil)
 	require.NoError(t, err)
 	defer l.Close()
 
@@ -691,7 +709,7 @@ func TestLedgerForEvaluatorSpecialAddresses(t *testing.T) {
 		RewardsPool: test.RewardAddr,
 	}
 	l, err := ledger_for_evaluator.MakeLedgerForEvaluator(
-		tx, test.GenesisHash, specialAddresses)
+		tx, test.GenesisHash, specialAddresses, nil)
 	require.NoError(t, err)
 	defer l.Close()
 
@@ -717,10 +735,335 @@ func TestLedgerForEvaluatorGenesisHash(t *testing.T) {
 	defer tx.Rollback(context.Background())
 
 	l, err := ledger_for_evaluator.MakeLedgerForEvaluator(
-		tx, test.GenesisHash, transactions.SpecialAddresses{})
+		tx, test.GenesisHash, transactions.SpecialAddresses{}, nil)
 	require.NoError(t, err)
 	defer l.Close()
 
 	genesisHash := l.GenesisHash()
 	assert.Equal(t, test.GenesisHash, genesisHash)
 }
+
+// lookupWithCache looks up `address` in a new transaction with `cache`.
+func lookupWithCache(t *testing.T, db *pgxpool.Pool, cache *ledger_for_evaluator.Cache, address basics.Address) basics.AccountData {
+	tx, err := db.BeginTx(context.Background(), readonlyRepeatableRead)
+	require.NoError(t, err)
+	defer tx.Rollback(context.Background())
+
+	l, err := ledger_for_evaluator.MakeLedgerForEvaluator(
+		tx, crypto.Digest{}, transactions.SpecialAddresses{}, cache)
+	require.NoError(t, err)
+	defer l.Close()
+
+	err = l.PreloadAccounts(map[basics.Address]struct{}{address: {}})
+	require.NoError(t, err)
+	accountData, _, err := l.LookupWithoutRewards(0, address)
+	require.NoError(t, err)
+	return accountData
+}
+
+func TestLedgerForEvaluatorCache(t *testing.T) {
+	db, shutdownFunc := setupPostgres(t)
+	defer shutdownFunc()
+
+	query :=
+		"INSERT INTO account " +
+			"(addr, microalgos, rewardsbase, rewards_total, deleted, created_at) " +
+			"VALUES ($1, 2, 0, 0, false, 0)"
+	_, err := db.Exec(context.Background(), query, test.AccountA[:])
+	require.NoError(t, err)
+
+	cache := ledger_for_evaluator.MakeCache(10)
+	cache.StartRound(1)
+	accountData := lookupWithCache(t, db, cache, test.AccountA)
+	assert.Equal(t, uint64(2), accountData.MicroAlgos.Raw)
+	assert.Equal(t, 1, cache.Len())
+
+	// The cached account is returned for the next round.
+	_, err = db.Exec(
+		context.Background(), "UPDATE account SET microalgos = 3 WHERE addr = $1",
+		test.AccountA[:])
+	require.NoError(t, err)
+	cache.ApplyDelta(ledgercore.StateDelta{})
+	cache.StartRound(2)
+	accountData = lookupWithCache(t, db, cache, test.AccountA)
+	assert.Equal(t, uint64(2), accountData.MicroAlgos.Raw)
+
+	// The block of round 2 modified the account, the new account data is used
+	// without reading the database.
+	var delta ledgercore.StateDelta
+	delta.Accts.Upsert(test.AccountA, basics.AccountData{MicroAlgos: basics.MicroAlgos{Raw: 4}})
+	cache.ApplyDelta(delta)
+	cache.StartRound(3)
+	accountData = lookupWithCache(t, db, cache, test.AccountA)
+	assert.Equal(t, uint64(4), accountData.MicroAlgos.Raw)
+
+	// The block of round 3 closed the account.
+	delta = ledgercore.StateDelta{}
+	delta.Accts.Upsert(test.AccountA, basics.AccountData{})
+	cache.ApplyDelta(delta)
+	cache.StartRound(4)
+	accountData = lookupWithCache(t, db, cache, test.AccountA)
+	assert.Equal(t, basics.AccountData{}, accountData)
+
+	// Other rounds read the database.
+	cache.StartRound(10)
+	accountData = lookupWithCache(t, db, cache, test.AccountA)
+	assert.Equal(t, uint64(3), accountData.MicroAlgos.Raw)
+}
+
+func TestLedgerForEvaluatorCacheCreator(t *testing.T) {
+	db, shutdownFunc := setupPostgres(t)
+	defer shutdownFunc()
+
+	cache := ledger_for_evaluator.MakeCache(10)
+	cache.StartRound(1)
+
+	getCreator := func() bool {
+		tx, err := db.BeginTx(context.Background(), readonlyRepeatableRead)
+		require.NoError(t, err)
+		defer tx.Rollback(context.Background())
+
+		l, err := ledger_for_evaluator.MakeLedgerForEvaluator(
+			tx, crypto.Digest{}, transactions.SpecialAddresses{}, cache)
+		require.NoError(t, err)
+		defer l.Close()
+
+		_, ok, err := l.GetCreatorForRound(
+			basics.Round(0), basics.CreatableIndex(2), basics.AssetCreatable)
+		require.NoError(t, err)
+		return ok
+	}
+	assert.False(t, getCreator())
+
+	delta := ledgercore.StateDelta{
+		Creatables: map[basics.CreatableIndex]ledgercore.ModifiedCreatable{
+			2: {
+				Ctype:   basics.AssetCreatable,
+				Created: true,
+				Creator: test.AccountA,
+			},
+		},
+	}
+	// The asset created by the block is not in the database yet.
+	cache.ApplyDelta(delta)
+	cache.StartRound(2)
+	assert.True(t, getCreator())
+
+	delta.Creatables[2] = ledgercore.ModifiedCreatable{
+		Ctype:   basics.AssetCreatable,
+		Created: false,
+		Creator: test.AccountA,
+	}
+	cache.ApplyDelta(delta)
+	cache.StartRound(3)
+	assert.False(t, getCreator())
+}
+
+func TestLedgerForEvaluatorCacheEvicts(t *testing.T) {
+	db, shutdownFunc := setupPostgres(t)
+	defer shutdownFunc()
+
+	cache := ledger_for_evaluator.MakeCache(1)
+	cache.StartRound(1)
+	lookupWithCache(t, db, cache, test.AccountA)
+	lookupWithCache(t, db, cache, test.AccountB)
+	assert.Equal(t, 1, cache.Len())
+}
+
+func TestLedgerForEvaluatorCacheStartRound(t *testing.T) {
+	db, shutdownFunc := setupPostgres(t)
+	defer shutdownFunc()
+
+	cache := ledger_for_evaluator.MakeCache(10)
+	cache.StartRound(1)
+	lookupWithCache(t, db, cache, test.AccountA)
+	assert.Equal(t, 1, cache.Len())
+
+	// Round 1 was not evaluated, e.g. because its transaction was retried.
+	cache.StartRound(1)
+	assert.Equal(t, 1, cache.Len())
+	cache.StartRound(5)
+	assert.Equal(t, 0, cache.Len())
+
+	lookupWithCache(t, db, cache, test.AccountA)
+	resume := cache.Suspend()
+	assert.Equal(t, 0, cache.Len())
+	lookupWithCache(t, db, cache, test.AccountA)
+	assert.Equal(t, 0, cache.Len())
+	resume()
+	lookupWithCache(t, db, cache, test.AccountA)
+	assert.Equal(t, 1, cache.Len())
+}
+
+func TestLedgerForEvaluatorCacheBlockHdr(t *testing.T) {
+	db, shutdownFunc := setupPostgres(t)
+	defer shutdownFunc()
+
+	cache := ledger_for_evaluator.MakeCache(10)
+	cache.StartRound(3)
+
+	blockHdr := func(round basics.Round) (bookkeeping.BlockHeader, error) {
+		tx, err := db.BeginTx(context.Background(), readonlyRepeatableRead)
+		require.NoError(t, err)
+		defer tx.Rollback(context.Background())
+
+		l, err := ledger_for_evaluator.MakeLedgerForEvaluator(
+			tx, crypto.Digest{}, transactions.SpecialAddresses{}, cache)
+		require.NoError(t, err)
+		defer l.Close()
+
+		return l.BlockHdr(round)
+	}
+
+	query :=
+		"INSERT INTO block_header (round, realtime, rewardslevel, header) " +
+			"VALUES (1, 'epoch', 0, $1)"
+	header1 := bookkeeping.BlockHeader{Round: 1, TimeStamp: 11}
+	_, err := db.Exec(context.Background(), query, encoding.EncodeBlockHeader(header1))
+	require.NoError(t, err)
+
+	// An older header is loaded and cached.
+	ret, err := blockHdr(1)
+	require.NoError(t, err)
+	assert.Equal(t, header1, ret)
+
+	// A header added by the import does not need to be in the database yet.
+	header2 := bookkeeping.BlockHeader{Round: 2, TimeStamp: 12}
+	cache.AddHeader(header2)
+
+	_, err = db.Exec(context.Background(), "DELETE FROM block_header")
+	require.NoError(t, err)
+
+	ret, err = blockHdr(1)
+	require.NoError(t, err)
+	assert.Equal(t, header1, ret)
+	ret, err = blockHdr(2)
+	require.NoError(t, err)
+	assert.Equal(t, header2, ret)
+
+	cache.Clear()
+	_, err = blockHdr(1)
+	assert.Error(t, err)
+}
+
+func TestLedgerForEvaluatorPreloadFromPayset(t *testing.T) {
+	db, shutdownFunc := setupPostgres(t)
+	defer shutdownFunc()
+
+	addAccountQuery :=
+		"INSERT INTO account " +
+			"(addr, microalgos, rewardsbase, rewards_total, deleted, created_at) " +
+			"VALUES ($1, 5, 0, 0, false, 0)"
+	for _, address := range []basics.Address{test.AccountA, test.AccountB, test.AccountC} {
+		_, err := db.Exec(context.Background(), addAccountQuery, address[:])
+		require.NoError(t, err)
+	}
+	_, err := db.Exec(
+		context.Background(),
+		"INSERT INTO asset (index, creator_addr, params, deleted, created_at) "+
+			"VALUES (2, $1, '{}', false, 0)",
+		test.AccountA[:])
+	require.NoError(t, err)
+	_, err = db.Exec(
+		context.Background(),
+		"INSERT INTO app (index, creator, params, deleted, created_at) "+
+			"VALUES (3, $1, '{}', false, 0)",
+		test.AccountB[:])
+	require.NoError(t, err)
+
+	var axfer transactions.SignedTxnInBlock
+	axfer.Txn.Type = protocol.AssetTransferTx
+	axfer.Txn.Sender = test.AccountC
+	axfer.Txn.AssetReceiver = test.AccountD
+	axfer.Txn.XferAsset = 2
+	var appl transactions.SignedTxnInBlock
+	appl.Txn.Type = protocol.ApplicationCallTx
+	appl.Txn.Sender = test.AccountC
+	appl.Txn.ApplicationID = 3
+	appl.Txn.Accounts = []basics.Address{test.AccountE}
+	appl.Txn.ForeignAssets = []basics.AssetIndex{4}
+
+	tx, err := db.BeginTx(context.Background(), readonlyRepeatableRead)
+	require.NoError(t, err)
+	defer tx.Rollback(context.Background())
+
+	l, err := ledger_for_evaluator.MakeLedgerForEvaluator(
+		tx, crypto.Digest{}, transactions.SpecialAddresses{}, nil)
+	require.NoError(t, err)
+	defer l.Close()
+
+	err = l.PreloadFromPayset(transactions.Payset{axfer, appl})
+	require.NoError(t, err)
+
+	// Everything is preloaded, the lookups don't need the transaction anymore.
+	err = tx.Rollback(context.Background())
+	require.NoError(t, err)
+
+	address, ok, err := l.GetCreatorForRound(0, 2, basics.AssetCreatable)
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, test.AccountA, address)
+
+	address, ok, err = l.GetCreatorForRound(0, 3, basics.AppCreatable)
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, test.AccountB, address)
+
+	_, ok, err = l.GetCreatorForRound(0, 4, basics.AssetCreatable)
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	// The creators are preloaded as well.
+	for _, address := range []basics.Address{test.AccountA, test.AccountB, test.AccountC} {
+		accountData, _, err := l.LookupWithoutRewards(0, address)
+		require.NoError(t, err)
+		assert.Equal(t, uint64(5), accountData.MicroAlgos.Raw)
+	}
+	for _, address := range []basics.Address{test.AccountD, test.AccountE} {
+		accountData, _, err := l.LookupWithoutRewards(0, address)
+		require.NoError(t, err)
+		assert.Equal(t, basics.AccountData{}, accountData)
+	}
+}
+
+func TestLedgerForEvaluatorGetCreators(t *testing.T) {
+	db, shutdownFunc := setupPostgres(t)
+	defer shutdownFunc()
+
+	query :=
+		"INSERT INTO asset (index, creator_addr, params, deleted, created_at) " +
+			"VALUES (2, $1, '{}', false, 0), (3, $1, '{}', true, 0)"
+	_, err := db.Exec(context.Background(), query, test.AccountA[:])
+	require.NoError(t, err)
+	query =
+		"INSERT INTO app (index, creator, params, deleted, created_at) " +
+			"VALUES (4, $1, '{}', false, 0)"
+	_, err = db.Exec(context.Background(), query, test.AccountB[:])
+	require.NoError(t, err)
+
+	tx, err := db.BeginTx(context.Background(), readonlyRepeatableRead)
+	require.NoError(t, err)
+	defer tx.Rollback(context.Background())
+
+	l, err := ledger_for_evaluator.MakeLedgerForEvaluator(
+		tx, crypto.Digest{}, transactions.SpecialAddresses{}, nil)
+	require.NoError(t, err)
+	defer l.Close()
+
+	asset2 := ledger_for_evaluator.Creatable{Index: 2, Type: basics.AssetCreatable}
+	asset3 := ledger_for_evaluator.Creatable{Index: 3, Type: basics.AssetCreatable}
+	// An application with the index of an asset.
+	app2 := ledger_for_evaluator.Creatable{Index: 2, Type: basics.AppCreatable}
+	app4 := ledger_for_evaluator.Creatable{Index: 4, Type: basics.AppCreatable}
+
+	creators, err := l.GetCreators(map[ledger_for_evaluator.Creatable]struct{}{
+		asset2: {}, asset3: {}, app2: {}, app4: {},
+	})
+	require.NoError(t, err)
+
+	expected := map[ledger_for_evaluator.Creatable]basics.Address{
+		asset2: test.AccountA,
+		app4:   test.AccountB,
+	}
+	assert.Equal(t, expected, creators)
+}
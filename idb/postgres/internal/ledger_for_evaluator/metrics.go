@@ -0,0 +1,55 @@
+package ledgerforevaluator
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/algorand/indexer/util/metrics"
+)
+
+// Names of the lookups in the metrics.
+const (
+	accountsLookupName    = "accounts"
+	creatorsLookupName    = "creators"
+	blockHeaderLookupName = "block_header"
+)
+
+// Where a looked up value was found.
+const (
+	preloadedSourceName = "preloaded"
+	cacheSourceName     = "cache"
+	databaseSourceName  = "database"
+)
+
+var lookupDuration = metrics.DefaultRegistry.NewHistogramVec(
+	"evaluator_lookup_duration_seconds",
+	"Time spent reading the evaluator's accounts, creators and block headers "+
+		"from the database, by lookup.",
+	nil, "lookup")
+
+var lookupBatchSize = metrics.DefaultRegistry.NewHistogramVec(
+	"evaluator_lookup_batch_size",
+	"Accounts, creators or block headers read from the database at once, by lookup.",
+	prometheus.ExponentialBuckets(1, 4, 8), "lookup")
+
+var lookups = metrics.DefaultRegistry.NewCounterVec(
+	"evaluator_lookups_total",
+	"Accounts, creators and block headers requested by the evaluator or the "+
+		"preloading, by lookup and where they were found.",
+	"lookup", "source")
+
+// observeLookup records a database read of `size` values which started at
+// `start`.
+func observeLookup(name string, start time.Time, size int) {
+	lookupDuration.WithLabelValues(name).Observe(time.Since(start).Seconds())
+	lookupBatchSize.WithLabelValues(name).Observe(float64(size))
+	lookups.WithLabelValues(name, databaseSourceName).Add(float64(size))
+}
+
+// countLookups records `n` values which were found without reading the database.
+func countLookups(name string, source string, n int) {
+	if n > 0 {
+		lookups.WithLabelValues(name, source).Add(float64(n))
+	}
+}
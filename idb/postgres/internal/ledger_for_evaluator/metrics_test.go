@@ -0,0 +1,102 @@
+package ledgerforevaluator
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/algorand/go-algorand/crypto"
+	"github.com/algorand/go-algorand/data/basics"
+	"github.com/algorand/go-algorand/data/transactions"
+	"github.com/jackc/pgx/v4"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/algorand/indexer/idb/postgres/internal/schema"
+	pgtest "github.com/algorand/indexer/idb/postgres/internal/testing"
+	"github.com/algorand/indexer/util/test"
+)
+
+// sampleCount returns the number of observations of a histogram.
+func sampleCount(t *testing.T, histogram *prometheus.HistogramVec, name string) uint64 {
+	var m dto.Metric
+	err := histogram.WithLabelValues(name).(prometheus.Metric).Write(&m)
+	require.NoError(t, err)
+	return m.GetHistogram().GetSampleCount()
+}
+
+// lookupCount returns the number of values of the lookup `name` found in `source`.
+func lookupCount(name string, source string) float64 {
+	return testutil.ToFloat64(lookups.WithLabelValues(name, source))
+}
+
+func TestObserveLookup(t *testing.T) {
+	durations := sampleCount(t, lookupDuration, blockHeaderLookupName)
+	sizes := sampleCount(t, lookupBatchSize, blockHeaderLookupName)
+	database := lookupCount(blockHeaderLookupName, databaseSourceName)
+
+	observeLookup(blockHeaderLookupName, time.Now(), 3)
+	assert.Equal(t, durations+1, sampleCount(t, lookupDuration, blockHeaderLookupName))
+	assert.Equal(t, sizes+1, sampleCount(t, lookupBatchSize, blockHeaderLookupName))
+	assert.Equal(t, database+3, lookupCount(blockHeaderLookupName, databaseSourceName))
+
+	// Lookups without values are not counted.
+	cache := lookupCount(blockHeaderLookupName, cacheSourceName)
+	countLookups(blockHeaderLookupName, cacheSourceName, 0)
+	assert.Equal(t, cache, lookupCount(blockHeaderLookupName, cacheSourceName))
+	countLookups(blockHeaderLookupName, cacheSourceName, 2)
+	assert.Equal(t, cache+2, lookupCount(blockHeaderLookupName, cacheSourceName))
+}
+
+func TestLookupMetricsAccounts(t *testing.T) {
+	db, _, shutdownFunc := pgtest.SetupPostgres(t)
+	defer shutdownFunc()
+
+	_, err := db.Exec(context.Background(), schema.SetupPostgresSql)
+	require.NoError(t, err)
+	query :=
+		"INSERT INTO account " +
+			"(addr, microalgos, rewardsbase, rewards_total, deleted, created_at) " +
+			"VALUES ($1, 2, 0, 0, false, 0)"
+	_, err = db.Exec(context.Background(), query, test.AccountA[:])
+	require.NoError(t, err)
+
+	cache := MakeCache(10)
+	cache.StartRound(1)
+	preload := func() {
+		tx, err := db.BeginTx(context.Background(), pgx.TxOptions{})
+		require.NoError(t, err)
+		defer tx.Rollback(context.Background())
+
+		l, err := MakeLedgerForEvaluator(tx, crypto.Digest{}, transactions.SpecialAddresses{}, cache)
+		require.NoError(t, err)
+		defer l.Close()
+
+		err = l.PreloadAccounts(map[basics.Address]struct{}{test.AccountA: {}})
+		require.NoError(t, err)
+		_, _, err = l.LookupWithoutRewards(0, test.AccountA)
+		require.NoError(t, err)
+	}
+
+	batches := sampleCount(t, lookupBatchSize, accountsLookupName)
+	database := lookupCount(accountsLookupName, databaseSourceName)
+	cached := lookupCount(accountsLookupName, cacheSourceName)
+	preloaded := lookupCount(accountsLookupName, preloadedSourceName)
+
+	// The first preload reads the database, the lookup uses the preloaded account.
+	preload()
+	assert.Equal(t, batches+1, sampleCount(t, lookupBatchSize, accountsLookupName))
+	assert.Equal(t, database+1, lookupCount(accountsLookupName, databaseSourceName))
+	assert.Equal(t, cached, lookupCount(accountsLookupName, cacheSourceName))
+	assert.Equal(t, preloaded+1, lookupCount(accountsLookupName, preloadedSourceName))
+
+	// The next one is served from the cache.
+	preload()
+	assert.Equal(t, batches+1, sampleCount(t, lookupBatchSize, accountsLookupName))
+	assert.Equal(t, database+1, lookupCount(accountsLookupName, databaseSourceName))
+	assert.Equal(t, cached+1, lookupCount(accountsLookupName, cacheSourceName))
+	assert.Equal(t, preloaded+2, lookupCount(accountsLookupName, preloadedSourceName))
+}
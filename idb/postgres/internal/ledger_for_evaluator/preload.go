@@ -0,0 +1,97 @@
+package ledgerforevaluator
+
+import (
+	"fmt"
+
+	"github.com/algorand/go-algorand/data/basics"
+	"github.com/algorand/go-algorand/data/transactions"
+	"github.com/algorand/go-algorand/protocol"
+)
+
+// paysetReferences returns the accounts and creatables which the transactions of
+// `payset` reference, i.e. which the evaluator will look up.
+func paysetReferences(payset transactions.Payset) (map[basics.Address]struct{}, map[Creatable]struct{}) {
+	addresses := make(map[basics.Address]struct{})
+	creatables := make(map[Creatable]struct{})
+
+	addAddress := func(address basics.Address) {
+		if !address.IsZero() {
+			addresses[address] = struct{}{}
+		}
+	}
+	addAsset := func(index basics.AssetIndex) {
+		if index != 0 {
+			creatables[Creatable{Index: basics.CreatableIndex(index), Type: basics.AssetCreatable}] =
+				struct{}{}
+		}
+	}
+	addApp := func(index basics.AppIndex) {
+		if index != 0 {
+			creatables[Creatable{Index: basics.CreatableIndex(index), Type: basics.AppCreatable}] =
+				struct{}{}
+		}
+	}
+
+	for i := range payset {
+		txn := &payset[i].Txn
+		addresses[txn.Sender] = struct{}{}
+
+		switch txn.Type {
+		case protocol.PaymentTx:
+			// Payments to the zero address are valid.
+			addresses[txn.Receiver] = struct{}{}
+			addAddress(txn.CloseRemainderTo)
+		case protocol.AssetTransferTx:
+			addAddress(txn.AssetSender)
+			addresses[txn.AssetReceiver] = struct{}{}
+			addAddress(txn.AssetCloseTo)
+			addAsset(txn.XferAsset)
+		case protocol.AssetFreezeTx:
+			addresses[txn.FreezeAccount] = struct{}{}
+			addAsset(txn.FreezeAsset)
+		case protocol.AssetConfigTx:
+			addAsset(txn.ConfigAsset)
+		case protocol.ApplicationCallTx:
+			for _, address := range txn.Accounts {
+				addresses[address] = struct{}{}
+			}
+			addApp(txn.ApplicationID)
+			for _, index := range txn.ForeignApps {
+				addApp(index)
+			}
+			for _, index := range txn.ForeignAssets {
+				addAsset(index)
+			}
+		}
+	}
+
+	return addresses, creatables
+}
+
+// PreloadFromPayset loads the accounts and the creators of the assets and
+// applications which the transactions of `payset` reference, including the
+// accounts of the creators, and stores them in the internal cache. The
+// evaluator then does not need to query the database while it evaluates the
+// block, except for accounts created by the block's own transactions.
+func (l *LedgerForEvaluator) PreloadFromPayset(payset transactions.Payset) error {
+	addresses, creatables := paysetReferences(payset)
+
+	creators, err := l.loadCreators(creatables)
+	if err != nil {
+		return fmt.Errorf("PreloadFromPayset() err: %w", err)
+	}
+	for _, value := range creators {
+		if value.exists {
+			addresses[value.address] = struct{}{}
+		}
+	}
+
+	accountData, err := l.loadAccounts(addresses)
+	if err != nil {
+		return fmt.Errorf("PreloadFromPayset() err: %w", err)
+	}
+
+	l.preloadedAccountData = accountData
+	l.preloadedCreators = creators
+	return nil
+}
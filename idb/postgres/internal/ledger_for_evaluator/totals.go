@@ -0,0 +1,140 @@
+package ledgerforevaluator
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/algorand/go-algorand/config"
+	"github.com/algorand/go-algorand/data/basics"
+	"github.com/algorand/go-algorand/ledger/ledgercore"
+	"github.com/jackc/pgx/v4"
+
+	"github.com/algorand/indexer/idb/postgres/internal/encoding"
+	pgutil "github.com/algorand/indexer/idb/postgres/internal/util"
+)
+
+// Totals is part of go-algorand's ledgerForEvaluator interface. The totals
+// don't count the special accounts, indexer does not track their balances.
+//
+// When no totals were stored for `round`, e.g. because it was imported by an
+// older version, they are summed from the account table. This is only correct
+// for the latest imported round, which is the one the evaluator asks for.
+func (l LedgerForEvaluator) Totals(round basics.Round) (ledgercore.AccountTotals, error) {
+	var buf []byte
+	err := l.tx.QueryRow(
+		context.Background(), pgutil.Query(l.tx, statements, totalsStmtName),
+		uint64(round)).Scan(&buf)
+	if err == pgx.ErrNoRows {
+		totals, err := l.sumAccounts(round)
+		if err != nil {
+			return ledgercore.AccountTotals{}, fmt.Errorf("Totals() err: %w", err)
+		}
+		return totals, nil
+	}
+	if err != nil {
+		return ledgercore.AccountTotals{}, fmt.Errorf("Totals() scan row err: %w", err)
+	}
+
+	totals, err := encoding.DecodeAccountTotals(buf)
+	if err != nil {
+		return ledgercore.AccountTotals{}, fmt.Errorf("Totals() decode err: %w", err)
+	}
+	return totals, nil
+}
+
+// OnlineTotals returns the money of the online accounts after `round`.
+func (l LedgerForEvaluator) OnlineTotals(round basics.Round) (basics.MicroAlgos, error) {
+	totals, err := l.Totals(round)
+	if err != nil {
+		return basics.MicroAlgos{}, fmt.Errorf("OnlineTotals() err: %w", err)
+	}
+	return totals.Online.Money, nil
+}
+
+// sumAccounts computes the totals of the accounts as of `round` from the
+// account table.
+func (l LedgerForEvaluator) sumAccounts(round basics.Round) (ledgercore.AccountTotals, error) {
+	header, err := l.BlockHdr(round)
+	if err != nil {
+		return ledgercore.AccountTotals{}, fmt.Errorf("sumAccounts() err: %w", err)
+	}
+	proto, ok := config.Consensus[header.CurrentProtocol]
+	if !ok {
+		return ledgercore.AccountTotals{},
+			fmt.Errorf("sumAccounts() cannot find proto version %s", header.CurrentProtocol)
+	}
+
+	rows, err := l.tx.Query(
+		context.Background(), pgutil.Query(l.tx, statements, sumAccountsStmtName),
+		proto.RewardUnit, header.RewardsLevel, l.specialAddresses.FeeSink[:],
+		l.specialAddresses.RewardsPool[:])
+	if err != nil {
+		return ledgercore.AccountTotals{}, fmt.Errorf("sumAccounts() query err: %w", err)
+	}
+	defer rows.Close()
+
+	totals := ledgercore.AccountTotals{RewardsLevel: header.RewardsLevel}
+	var status int
+	var microalgos, rewardUnits, pendingRewards uint64
+	for rows.Next() {
+		err = rows.Scan(&status, &microalgos, &rewardUnits, &pendingRewards)
+		if err != nil {
+			return ledgercore.AccountTotals{}, fmt.Errorf("sumAccounts() scan row err: %w", err)
+		}
+
+		count := ledgercore.AlgoCount{
+			Money:       basics.MicroAlgos{Raw: microalgos + pendingRewards},
+			RewardUnits: rewardUnits,
+		}
+		switch basics.Status(status) {
+		case basics.Online:
+			totals.Online = count
+		case basics.Offline:
+			totals.Offline = count
+		case basics.NotParticipating:
+			// Non-participating accounts don't earn rewards.
+			count.Money.Raw = microalgos
+			totals.NotParticipating = count
+		default:
+			return ledgercore.AccountTotals{}, fmt.Errorf("sumAccounts() unknown status %d", status)
+		}
+	}
+	err = rows.Err()
+	if err != nil {
+		return ledgercore.AccountTotals{}, fmt.Errorf("sumAccounts() scan end err: %w", err)
+	}
+
+	return totals, nil
+}
+
+// NextTotals returns the totals after the block of `round` with rewards level
+// `rewardsLevel`, from the totals of the previous round and the accounts
+// modified by the block's `delta`. It must be called before the delta is
+// written.
+func (l LedgerForEvaluator) NextTotals(round basics.Round, rewardsLevel uint64, proto config.ConsensusParams, delta ledgercore.StateDelta) (ledgercore.AccountTotals, error) {
+	totals, err := l.Totals(round - 1)
+	if err != nil {
+		return ledgercore.AccountTotals{}, fmt.Errorf("NextTotals() err: %w", err)
+	}
+
+	var ot basics.OverflowTracker
+	totals.ApplyRewards(rewardsLevel, &ot)
+	for i := 0; i < delta.Accts.Len(); i++ {
+		address, accountData := delta.Accts.GetByIdx(i)
+		if l.isSpecialAddress(address) {
+			continue
+		}
+
+		previous, _, err := l.LookupWithoutRewards(round, address)
+		if err != nil {
+			return ledgercore.AccountTotals{}, fmt.Errorf("NextTotals() err: %w", err)
+		}
+		totals.DelAccount(proto, previous, &ot)
+		totals.AddAccount(proto, accountData, &ot)
+	}
+	if ot.Overflowed {
+		return ledgercore.AccountTotals{}, fmt.Errorf("NextTotals() overflow in round %d", round)
+	}
+
+	return totals, nil
+}
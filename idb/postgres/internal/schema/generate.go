@@ -1,3 +1,4 @@
 package schema
 
 //go:generate go run ../../../../cmd/texttosource/main.go schema SetupPostgresSql setup_postgres.sql setup_postgres_sql.go
+//go:generate go run ../../../../cmd/texttosource/main.go schema SetupCockroachSql setup_cockroach.sql setup_cockroach_sql.go
@@ -5,4 +5,24 @@ const (
 	StateMetastateKey           = "state"
 	MigrationMetastateKey       = "migration"
 	SpecialAccountsMetastateKey = "accounts"
+	RetentionMetastateKey       = "retention"
+	// OperatorMigrationsMetastateKey is the state of the SQL scripts supplied
+	// by the operator.
+	OperatorMigrationsMetastateKey = "operator_migrations"
+
+	// BackfillMetastateKeyPrefix is followed by the feature name.
+	BackfillMetastateKeyPrefix = "backfill_"
+
+	// WebhooksMetastateKey is the list of registered webhooks.
+	WebhooksMetastateKey = "webhooks"
+
+	// PaginationMetastateKey is the secret which signs the next tokens.
+	PaginationMetastateKey = "pagination"
+
+	// ExportMetastateKeyPrefix is followed by the name of the exporter.
+	ExportMetastateKeyPrefix = "export_"
+
+	// MigrationShardMetastateKeyPrefix is followed by the shard number of the
+	// running parallel migration.
+	MigrationShardMetastateKeyPrefix = "migration_shard_"
 )
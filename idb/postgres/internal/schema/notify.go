@@ -0,0 +1,9 @@
+package schema
+
+// RoundNotifyChannel is the SQL expression of the channel on which the writer
+// notifies listeners of each imported round, the payload is the round in
+// decimal. It is "indexer_round" in the public schema and
+// "indexer_round_<schema>" in others, so that indexers sharing a database don't
+// receive each other's rounds.
+const RoundNotifyChannel = `'indexer_round' ||
+	CASE current_schema() WHEN 'public' THEN '' ELSE '_' || current_schema() END`
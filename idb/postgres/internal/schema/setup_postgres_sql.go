@@ -24,7 +24,8 @@ typeenum smallint NOT NULL,
 asset bigint NOT NULL, -- 0=Algos, otherwise AssetIndex
 txid bytea NOT NULL, -- base32 of [32]byte hash
 txnbytes bytea NOT NULL, -- msgpack encoding of signed txn with apply data
-txn jsonb NOT NULL, -- json encoding of signed txn with apply data
+txnbytes_len integer, -- length of txnbytes before it was compressed, NULL if it is not compressed
+txn jsonb NOT NULL, -- json encoding of signed txn with apply data, without the eval delta if txnbytes is compressed
 extra jsonb,
 PRIMARY KEY ( round, intra )
 );
@@ -54,7 +55,12 @@ CREATE TABLE IF NOT EXISTS account (
   created_at bigint NOT NULL DEFAULT 0, -- round that the account is first used
   closed_at bigint, -- round that the account was last closed
   keytype varchar(8), -- sig,msig,lsig
-  account_data jsonb -- trimmed AccountData that only contains auth addr and keyreg info
+  account_data jsonb, -- trimmed AccountData that only contains auth addr and keyreg info
+  -- number of rows of the account which are not deleted in account_asset, asset, account_app and app
+  total_assets_opted_in bigint NOT NULL DEFAULT 0,
+  total_created_assets bigint NOT NULL DEFAULT 0,
+  total_apps_opted_in bigint NOT NULL DEFAULT 0,
+  total_created_apps bigint NOT NULL DEFAULT 0
 );
 
 -- data.basics.AccountData Assets[asset id] AssetHolding{}
@@ -122,4 +128,11 @@ CREATE TABLE IF NOT EXISTS account_app (
 
 -- For account lookup
 CREATE INDEX IF NOT EXISTS account_app_by_addr ON account_app ( addr );
+
+-- totals of the accounts after each round, roughly go-algorand/ledger/ledgercore/totals.go AccountTotals
+-- without the special accounts
+CREATE TABLE IF NOT EXISTS account_totals (
+  round bigint PRIMARY KEY,
+  totals jsonb NOT NULL
+);
 `
@@ -0,0 +1,159 @@
+package util
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/jackc/pgx/v4"
+
+	"github.com/algorand/indexer/util/metrics"
+)
+
+// preparedStatements counts the statements prepared by PrepareStatements() and
+// those which were already prepared on the connection, which saves a round trip
+// to the database each.
+var preparedStatements = metrics.DefaultRegistry.NewCounterVec(
+	"postgres_prepared_statements_total",
+	"Statements of the block import which were prepared, or reused without a round trip.",
+	"result")
+
+// prepared records the statements prepared on each connection, and the
+// connections on which statements are not prepared.
+var prepared = struct {
+	sync.Mutex
+	conns      map[*pgx.Conn]map[string]string
+	unprepared map[*pgx.Conn]bool
+}{
+	conns:      make(map[*pgx.Conn]map[string]string),
+	unprepared: make(map[*pgx.Conn]bool),
+}
+
+// DisablePreparedStatements makes PrepareStatements() a no-op on `conn`, the
+// statements are sent as sql instead, see Batch and Query(). Use it when the
+// connection goes through a pooler which hands each transaction a different
+// server connection, which does not have the statements.
+func DisablePreparedStatements(conn *pgx.Conn) {
+	prepared.Lock()
+	defer prepared.Unlock()
+
+	prepared.unprepared[conn] = true
+}
+
+func preparedStatementsDisabled(conn *pgx.Conn) bool {
+	prepared.Lock()
+	defer prepared.Unlock()
+
+	return prepared.unprepared[conn]
+}
+
+// PrepareStatements prepares named statements on the connection of `tx`. They
+// stay prepared when the transaction ends, so the statements of the block import
+// are only prepared once per connection instead of once per block.
+func PrepareStatements(tx pgx.Tx, statements map[string]string) error {
+	conn := tx.Conn()
+
+	prepared.Lock()
+	defer prepared.Unlock()
+
+	// Forget connections which were closed by the pool.
+	for c := range prepared.conns {
+		if c.IsClosed() {
+			delete(prepared.conns, c)
+		}
+	}
+	for c := range prepared.unprepared {
+		if c.IsClosed() {
+			delete(prepared.unprepared, c)
+		}
+	}
+	if prepared.unprepared[conn] {
+		return nil
+	}
+
+	names, ok := prepared.conns[conn]
+	if !ok {
+		names = make(map[string]string)
+		prepared.conns[conn] = names
+	}
+	for name, query := range statements {
+		if names[name] == query {
+			preparedStatements.WithLabelValues("reused").Inc()
+			continue
+		}
+		// pgx returns statements it already prepared under the same name and sql
+		// without a round trip as well.
+		_, err := conn.Prepare(context.Background(), name, query)
+		if err != nil {
+			return fmt.Errorf("PrepareStatements() prepare %s err: %w", name, err)
+		}
+		names[name] = query
+		preparedStatements.WithLabelValues("prepared").Inc()
+	}
+	return nil
+}
+
+// Query returns what to pass to pgx to run the statement `name` of `statements`
+// in `tx`, its name if PrepareStatements() prepared it, else its sql.
+func Query(tx pgx.Tx, statements map[string]string, name string) string {
+	if preparedStatementsDisabled(tx.Conn()) {
+		return statements[name]
+	}
+	return name
+}
+
+// Batch is a pgx.Batch which queues the statements of a transaction by name, see
+// Query().
+type Batch struct {
+	pgx.Batch
+
+	// Set if the statements are not prepared.
+	statements map[string]string
+
+	// names are the names of the statements queued by Queue().
+	names []string
+}
+
+// MakeBatch creates a Batch for the statements which PrepareStatements()
+// prepared in `tx`.
+func MakeBatch(tx pgx.Tx, statements map[string]string) Batch {
+	if preparedStatementsDisabled(tx.Conn()) {
+		return Batch{statements: statements}
+	}
+	return Batch{}
+}
+
+// Queue queues the statement `name`.
+func (b *Batch) Queue(name string, arguments ...interface{}) {
+	b.names = append(b.names, name)
+	if b.statements != nil {
+		b.Batch.Queue(b.statements[name], arguments...)
+		return
+	}
+	b.Batch.Queue(name, arguments...)
+}
+
+// Name returns the name of the i-th statement queued by Queue(), or "" if it
+// was queued on the pgx.Batch directly.
+func (b *Batch) Name(i int) string {
+	if i < len(b.names) {
+		return b.names[i]
+	}
+	return ""
+}
+
+// DeallocateStatements deallocates the statements prepared on `conn` by
+// PrepareStatements(), e.g. after the schema changed.
+func DeallocateStatements(ctx context.Context, conn *pgx.Conn) error {
+	prepared.Lock()
+	defer prepared.Unlock()
+
+	for name := range prepared.conns[conn] {
+		err := conn.Deallocate(ctx, name)
+		if err != nil {
+			return fmt.Errorf("DeallocateStatements() deallocate %s err: %w", name, err)
+		}
+		delete(prepared.conns[conn], name)
+	}
+	return nil
+}
@@ -0,0 +1,80 @@
+package util
+
+import (
+	"context"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	pgtest "github.com/algorand/indexer/idb/postgres/internal/testing"
+)
+
+func TestPrepareStatementsReused(t *testing.T) {
+	db, _, shutdownFunc := pgtest.SetupPostgres(t)
+	defer shutdownFunc()
+
+	conn, err := db.Acquire(context.Background())
+	require.NoError(t, err)
+	defer conn.Release()
+
+	statements := map[string]string{"one": "SELECT 1", "two": "SELECT 2"}
+	prepare := func() {
+		tx, err := conn.Begin(context.Background())
+		require.NoError(t, err)
+		defer tx.Rollback(context.Background())
+
+		err = PrepareStatements(tx, statements)
+		require.NoError(t, err)
+
+		var n int
+		require.NoError(t, tx.QueryRow(context.Background(), "two").Scan(&n))
+		assert.Equal(t, 2, n)
+	}
+
+	reused := testutil.ToFloat64(preparedStatements.WithLabelValues("reused"))
+	prepare()
+	prepare()
+	assert.Equal(t, reused+2, testutil.ToFloat64(preparedStatements.WithLabelValues("reused")))
+
+	// Deallocated statements are prepared again.
+	err = DeallocateStatements(context.Background(), conn.Conn())
+	require.NoError(t, err)
+	prepared := testutil.ToFloat64(preparedStatements.WithLabelValues("prepared"))
+	prepare()
+	assert.Equal(t, prepared+2, testutil.ToFloat64(preparedStatements.WithLabelValues("prepared")))
+}
+
+func TestPrepareStatementsDisabled(t *testing.T) {
+	db, _, shutdownFunc := pgtest.SetupPostgres(t)
+	defer shutdownFunc()
+
+	conn, err := db.Acquire(context.Background())
+	require.NoError(t, err)
+	defer conn.Release()
+	DisablePreparedStatements(conn.Conn())
+
+	tx, err := conn.Begin(context.Background())
+	require.NoError(t, err)
+	defer tx.Rollback(context.Background())
+
+	statements := map[string]string{"one": "SELECT 1", "two": "SELECT 2"}
+	err = PrepareStatements(tx, statements)
+	require.NoError(t, err)
+
+	var n int
+	err = tx.QueryRow(context.Background(), Query(tx, statements, "two")).Scan(&n)
+	require.NoError(t, err)
+	assert.Equal(t, 2, n)
+
+	batch := MakeBatch(tx, statements)
+	batch.Queue("one")
+	batch.Queue("two")
+	results := tx.SendBatch(context.Background(), &batch.Batch)
+	for _, expected := range []int{1, 2} {
+		require.NoError(t, results.QueryRow().Scan(&n))
+		assert.Equal(t, expected, n)
+	}
+	require.NoError(t, results.Close())
+}
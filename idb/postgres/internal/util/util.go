@@ -20,6 +20,8 @@ import (
 // transaction and must either call sql.Tx.Rollback() or sql.Tx.Commit(). In the second
 // case, `f` must return an error which contains the error returned by sql.Tx.Commit().
 // The easiest way is to just return the result of sql.Tx.Commit().
+// CockroachDB reports the transactions which the client must retry with the
+// same error code.
 func TxWithRetry(db *pgxpool.Pool, opts pgx.TxOptions, f func(pgx.Tx) error, log *log.Logger) error {
 	count := 0
 	for {
@@ -0,0 +1,48 @@
+package writer
+
+import (
+	"time"
+
+	"github.com/algorand/indexer/util/metrics"
+)
+
+// statementTables are the tables written by the statements, for the metrics.
+var statementTables = map[string]string{
+	addBlockHeaderStmtName:       "block_header",
+	setSpecialAccountsStmtName:   "metastate",
+	addTxnStmtName:               "txn",
+	addTxnParticipantStmtName:    "txn_participation",
+	upsertAssetStmtName:          "asset",
+	upsertAccountAssetStmtName:   "account_asset",
+	upsertAppStmtName:            "app",
+	upsertAccountAppStmtName:     "account_app",
+	deleteAccountStmtName:        "account",
+	upsertAccountStmtName:        "account",
+	deleteAssetStmtName:          "asset",
+	deleteAccountAssetStmtName:   "account_asset",
+	deleteAppStmtName:            "app",
+	deleteAccountAppStmtName:     "account_app",
+	updateAccountKeyTypeStmtName: "account",
+	upsertAccountTotalsStmtName:  "account_totals",
+}
+
+var (
+	writeSeconds = metrics.DefaultRegistry.NewCounterVec(
+		"postgres_write_seconds_total",
+		"Time spent by the block import writing rows, by table.",
+		"table")
+	writtenRows = metrics.DefaultRegistry.NewCounterVec(
+		"postgres_written_rows_total",
+		"Rows written by the block import, by table.",
+		"table")
+)
+
+// observeWrite counts `rows` rows written to `table` in `d`. The statements of
+// other tables, e.g. the notification of the round, are counted as "other".
+func observeWrite(table string, rows int, d time.Duration) {
+	if table == "" {
+		table = "other"
+	}
+	writeSeconds.WithLabelValues(table).Add(d.Seconds())
+	writtenRows.WithLabelValues(table).Add(float64(rows))
+}
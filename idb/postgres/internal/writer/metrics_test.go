@@ -0,0 +1,14 @@
+package writer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Every statement must be counted under its table.
+func TestStatementTables(t *testing.T) {
+	for name := range statements {
+		assert.NotEmpty(t, statementTables[name], name)
+	}
+}
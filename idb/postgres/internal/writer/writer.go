@@ -16,6 +16,7 @@ import (
 	"github.com/algorand/indexer/idb"
 	"github.com/algorand/indexer/idb/postgres/internal/encoding"
 	"github.com/algorand/indexer/idb/postgres/internal/schema"
+	pgutil "github.com/algorand/indexer/idb/postgres/internal/util"
 )
 
 const (
@@ -34,8 +35,14 @@ const (
 	deleteAppStmtName            = "delete_app"
 	deleteAccountAppStmtName     = "delete_account_app"
 	updateAccountKeyTypeStmtName = "update_account_key_type"
+	upsertAccountTotalsStmtName  = "upsert_account_totals"
 )
 
+// notifyRoundQuery notifies the listeners of the imported round, the
+// notification is delivered when the transaction commits. It is not prepared
+// with the other statements since CockroachDB has no pg_notify().
+const notifyRoundQuery = `SELECT pg_notify(` + schema.RoundNotifyChannel + `, $1)`
+
 var statements = map[string]string{
 	addBlockHeaderStmtName: `INSERT INTO block_header
 		(round, realtime, rewardslevel, header)
@@ -44,8 +51,8 @@ var statements = map[string]string{
 		schema.SpecialAccountsMetastateKey +
 		`', $1) ON CONFLICT (k) DO UPDATE SET v = EXCLUDED.v`,
 	addTxnStmtName: `INSERT INTO txn
-		(round, intra, typeenum, asset, txid, txnbytes, txn, extra)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8) ON CONFLICT DO NOTHING`,
+		(round, intra, typeenum, asset, txid, txnbytes, txnbytes_len, txn, extra)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9) ON CONFLICT DO NOTHING`,
 	addTxnParticipantStmtName: `INSERT INTO txn_participation
 		(addr, round, intra) VALUES ($1, $2, $3) ON CONFLICT DO NOTHING`,
 	upsertAssetStmtName: `INSERT INTO asset
@@ -69,13 +76,22 @@ var statements = map[string]string{
 		VALUES($1, 0, 0, 0, TRUE, $2, $2) ON CONFLICT (addr) DO UPDATE SET
 		microalgos = EXCLUDED.microalgos, rewardsbase = EXCLUDED.rewardsbase,
 		rewards_total = EXCLUDED.rewards_total, deleted = TRUE,
-		closed_at = EXCLUDED.closed_at, account_data = EXCLUDED.account_data`,
+		closed_at = EXCLUDED.closed_at, account_data = EXCLUDED.account_data,
+		total_assets_opted_in = 0, total_created_assets = 0,
+		total_apps_opted_in = 0, total_created_apps = 0`,
 	upsertAccountStmtName: `INSERT INTO account
-		(addr, microalgos, rewardsbase, rewards_total, deleted, created_at, account_data)
-		VALUES($1, $2, $3, $4, FALSE, $5, $6) ON CONFLICT (addr) DO UPDATE SET
+		(addr, microalgos, rewardsbase, rewards_total, deleted, created_at, account_data,
+		total_assets_opted_in, total_created_assets, total_apps_opted_in,
+		total_created_apps)
+		VALUES($1, $2, $3, $4, FALSE, $5, $6, $7, $8, $9, $10)
+		ON CONFLICT (addr) DO UPDATE SET
 		microalgos = EXCLUDED.microalgos, rewardsbase = EXCLUDED.rewardsbase,
 		rewards_total = EXCLUDED.rewards_total, deleted = FALSE,
-		account_data = EXCLUDED.account_data`,
+		account_data = EXCLUDED.account_data,
+		total_assets_opted_in = EXCLUDED.total_assets_opted_in,
+		total_created_assets = EXCLUDED.total_created_assets,
+		total_apps_opted_in = EXCLUDED.total_apps_opted_in,
+		total_created_apps = EXCLUDED.total_created_apps`,
 	deleteAssetStmtName: `INSERT INTO asset
 		(index, creator_addr, params, deleted, created_at, closed_at)
 		VALUES($1, $2, 'null'::jsonb, TRUE, $3, $3) ON CONFLICT (index) DO UPDATE SET
@@ -95,44 +111,129 @@ var statements = map[string]string{
 		VALUES($1, $2, 'null'::jsonb, TRUE, $3, $3) ON CONFLICT (addr, app) DO UPDATE SET
 		localstate = EXCLUDED.localstate, deleted = TRUE, closed_at = EXCLUDED.closed_at`,
 	updateAccountKeyTypeStmtName: `UPDATE account SET keytype = $1 WHERE addr = $2`,
+	upsertAccountTotalsStmtName: `INSERT INTO account_totals (round, totals)
+		VALUES ($1, $2) ON CONFLICT (round) DO UPDATE SET totals = EXCLUDED.totals`,
 }
 
 // Writer is responsible for writing blocks and accounting state deltas to the database.
 type Writer struct {
 	tx pgx.Tx
+
+	// In copy mode the txn and txn_participation rows are buffered and written by
+	// Flush().
+	copy              bool
+	txnRows           [][]interface{}
+	participationRows [][]interface{}
+
+	compress bool
+
+	stateOnly bool
+
+	noNotify bool
+
+	txnRules *idb.TxnRules
+	scope    *idb.AddressScope
 }
 
+var (
+	txnColumns = []string{
+		"round", "intra", "typeenum", "asset", "txid", "txnbytes", "txnbytes_len", "txn",
+		"extra"}
+	participationColumns = []string{"addr", "round", "intra"}
+)
+
 // MakeWriter creates a Writer object.
 func MakeWriter(tx pgx.Tx) (Writer, error) {
 	w := Writer{
 		tx: tx,
 	}
 
-	for name, query := range statements {
-		_, err := tx.Prepare(context.Background(), name, query)
-		if err != nil {
-			return Writer{}, fmt.Errorf("MakeWriter() prepare statement err: %w", err)
-		}
+	err := pgutil.PrepareStatements(tx, statements)
+	if err != nil {
+		return Writer{}, fmt.Errorf("MakeWriter() err: %w", err)
 	}
 
 	return w, nil
 }
 
-// Close shuts down Writer.
-func (w *Writer) Close() {
-	for name := range statements {
-		w.tx.Conn().Deallocate(context.Background(), name)
+// CopyTransactions switches the writer to copy mode. Instead of inserting the
+// transactions and their participants of every block, AddBlock() buffers them and
+// Flush() writes them with the COPY protocol. Unlike the inserts, COPY fails if a
+// row already exists.
+func (w *Writer) CopyTransactions() {
+	w.copy = true
+}
+
+// EnableCompression compresses large encoded transactions with zstd before they
+// are written, see encoding.CompressTxnBytes().
+func (w *Writer) EnableCompression() {
+	w.compress = true
+}
+
+// StateOnly makes AddBlock() only write the state deltas, when the block and
+// its transactions are already stored, e.g. when the state is rederived.
+func (w *Writer) StateOnly() {
+	w.stateOnly = true
+}
+
+// DisableNotify makes AddBlock() skip the notification of the round, for
+// databases without pg_notify() such as CockroachDB.
+func (w *Writer) DisableNotify() {
+	w.noNotify = true
+}
+
+// SetTxnRules makes AddBlock() only write the transactions, and their
+// participants, which `rules` stores. The state deltas are written in full.
+func (w *Writer) SetTxnRules(rules *idb.TxnRules) {
+	w.txnRules = rules
+}
+
+// SetAddressScope makes AddBlock() only write the transactions, and their
+// participants, which involve `scope`. The state deltas are written in full.
+func (w *Writer) SetAddressScope(scope *idb.AddressScope) {
+	w.scope = scope
+}
+
+// Flush writes the rows buffered in copy mode.
+func (w *Writer) Flush() error {
+	if len(w.txnRows) > 0 {
+		start := time.Now()
+		_, err := w.tx.CopyFrom(
+			context.Background(), pgx.Identifier{"txn"}, txnColumns,
+			pgx.CopyFromRows(w.txnRows))
+		if err != nil {
+			return fmt.Errorf("Flush() copy txn err: %w", err)
+		}
+		observeWrite("txn", len(w.txnRows), time.Since(start))
+		w.txnRows = nil
 	}
+	if len(w.participationRows) > 0 {
+		start := time.Now()
+		_, err := w.tx.CopyFrom(
+			context.Background(), pgx.Identifier{"txn_participation"},
+			participationColumns, pgx.CopyFromRows(w.participationRows))
+		if err != nil {
+			return fmt.Errorf("Flush() copy txn_participation err: %w", err)
+		}
+		observeWrite("txn_participation", len(w.participationRows), time.Since(start))
+		w.participationRows = nil
+	}
+	return nil
 }
 
-func addBlockHeader(blockHeader *bookkeeping.BlockHeader, batch *pgx.Batch) {
+// Close shuts down Writer. The statements stay prepared on the connection for
+// the next block.
+func (w *Writer) Close() {
+}
+
+func addBlockHeader(blockHeader *bookkeeping.BlockHeader, batch *pgutil.Batch) {
 	batch.Queue(
 		addBlockHeaderStmtName,
 		uint64(blockHeader.Round), time.Unix(blockHeader.TimeStamp, 0).UTC(),
 		blockHeader.RewardsLevel, encoding.EncodeBlockHeader(*blockHeader))
 }
 
-func setSpecialAccounts(addresses transactions.SpecialAddresses, batch *pgx.Batch) {
+func setSpecialAccounts(addresses transactions.SpecialAddresses, batch *pgutil.Batch) {
 	j := encoding.EncodeSpecialAddresses(addresses)
 	batch.Queue(setSpecialAccountsStmtName, j)
 }
@@ -163,10 +264,33 @@ func transactionAssetID(block *bookkeeping.Block, intra uint64, typeenum idb.Txn
 	return assetid
 }
 
+// storedTransactions returns which transactions of `block` are stored according
+// to `rules` and `scope`, nil if all of them are.
+func storedTransactions(block *bookkeeping.Block, rules *idb.TxnRules, scope *idb.AddressScope) ([]bool, error) {
+	if rules == nil && scope == nil {
+		return nil, nil
+	}
+	res := make([]bool, len(block.Payset))
+	for i := range block.Payset {
+		txn := &block.Payset[i].Txn
+		typeenum, ok := idb.GetTypeEnum(txn.Type)
+		if !ok {
+			return nil, fmt.Errorf("storedTransactions() get type enum")
+		}
+		assetid := transactionAssetID(block, uint64(i), typeenum)
+		res[i] = rules.Store(txn, typeenum, assetid) && scope.Involves(txn, typeenum, assetid)
+	}
+	return res, nil
+}
+
 // Add transactions from `block` to the database. `modifiedTxns` contains enhanced
-// apply data generated by evaluator.
-func addTransactions(block *bookkeeping.Block, modifiedTxns []transactions.SignedTxnInBlock, batch *pgx.Batch) error {
+// apply data generated by evaluator. Only the transactions set in `stored` are
+// added, unless it is nil.
+func addTransactions(block *bookkeeping.Block, modifiedTxns []transactions.SignedTxnInBlock, stored []bool, compress bool, add func(row ...interface{})) error {
 	for i, stib := range block.Payset {
+		if stored != nil && !stored[i] {
+			continue
+		}
 		var stxnad transactions.SignedTxnWithAD
 		var err error
 		// This function makes sure to set correct genesis information so we can get the
@@ -186,18 +310,32 @@ func addTransactions(block *bookkeeping.Block, modifiedTxns []transactions.Signe
 		extra := idb.TxnExtra{
 			AssetCloseAmount: modifiedTxns[i].ApplyData.AssetClosingAmount,
 		}
-		batch.Queue(
-			addTxnStmtName,
-			uint64(block.Round()), i, int(typeenum), assetid, id,
-			protocol.Encode(&stxnad),
-			encoding.EncodeSignedTxnWithAD(stxnad),
+		txnbytes := protocol.Encode(&stxnad)
+		// The length before compression, NULL when the bytes are not compressed.
+		var txnbytesLen interface{}
+		txnJSON := stxnad
+		if compress {
+			compressed := encoding.CompressTxnBytes(txnbytes)
+			if len(compressed) < len(txnbytes) {
+				txnbytesLen = len(txnbytes)
+				// The eval delta is only read from the transaction bytes, it is
+				// usually the largest part of the transaction.
+				txnJSON.EvalDelta = transactions.EvalDelta{}
+			}
+			txnbytes = compressed
+		}
+		add(
+			uint64(block.Round()), i, int(typeenum), assetid, id, txnbytes, txnbytesLen,
+			encoding.EncodeSignedTxnWithAD(txnJSON),
 			encoding.EncodeJSON(extra))
 	}
 
 	return nil
 }
 
-func getTransactionParticipants(txn transactions.Transaction) []basics.Address {
+// GetTransactionParticipants returns the addresses which are stored in the
+// txn_participation table for the given transaction.
+func GetTransactionParticipants(txn transactions.Transaction) []basics.Address {
 	res := make([]basics.Address, 0, 7)
 
 	add := func(address basics.Address) {
@@ -223,20 +361,23 @@ func getTransactionParticipants(txn transactions.Transaction) []basics.Address {
 	return res
 }
 
-func addTransactionParticipation(block *bookkeeping.Block, batch *pgx.Batch) error {
+func addTransactionParticipation(block *bookkeeping.Block, stored []bool, add func(row ...interface{})) error {
 	for i, stxnad := range block.Payset {
+		if stored != nil && !stored[i] {
+			continue
+		}
 		// TODO: replace with a function from go-algorand.
-		participants := getTransactionParticipants(stxnad.Txn)
+		participants := GetTransactionParticipants(stxnad.Txn)
 
 		for j := range participants {
-			batch.Queue(addTxnParticipantStmtName, participants[j][:], uint64(block.Round()), i)
+			add(participants[j][:], uint64(block.Round()), i)
 		}
 	}
 
 	return nil
 }
 
-func writeAccountData(round basics.Round, address basics.Address, accountData basics.AccountData, batch *pgx.Batch) {
+func writeAccountData(round basics.Round, address basics.Address, accountData basics.AccountData, batch *pgutil.Batch) {
 	// Update `asset` table.
 	for assetid, params := range accountData.AssetParams {
 		batch.Queue(
@@ -271,17 +412,20 @@ func writeAccountData(round basics.Round, address basics.Address, accountData ba
 		// Delete account.
 		batch.Queue(deleteAccountStmtName, address[:], uint64(round))
 	} else {
-		// Update account.
+		// Update account. The account data contains all the resources of the
+		// account, so the counts are the sizes of its maps.
 		accountDataJSON :=
 			encoding.EncodeTrimmedAccountData(encoding.TrimAccountData(accountData))
 		batch.Queue(
 			upsertAccountStmtName,
 			address[:], accountData.MicroAlgos.Raw, accountData.RewardsBase,
-			accountData.RewardedMicroAlgos.Raw, uint64(round), accountDataJSON)
+			accountData.RewardedMicroAlgos.Raw, uint64(round), accountDataJSON,
+			len(accountData.Assets), len(accountData.AssetParams),
+			len(accountData.AppLocalStates), len(accountData.AppParams))
 	}
 }
 
-func writeAccountDeltas(round basics.Round, deltas ledgercore.AccountDeltas, specialAddresses transactions.SpecialAddresses, batch *pgx.Batch) {
+func writeAccountDeltas(round basics.Round, deltas ledgercore.AccountDeltas, specialAddresses transactions.SpecialAddresses, batch *pgutil.Batch) {
 	// Update `account` table.
 	for i := 0; i < deltas.Len(); i++ {
 		address, accountData := deltas.GetByIdx(i)
@@ -295,7 +439,7 @@ func writeAccountDeltas(round basics.Round, deltas ledgercore.AccountDeltas, spe
 	}
 }
 
-func writeDeletedCreatables(round basics.Round, creatables map[basics.CreatableIndex]ledgercore.ModifiedCreatable, batch *pgx.Batch) {
+func writeDeletedCreatables(round basics.Round, creatables map[basics.CreatableIndex]ledgercore.ModifiedCreatable, batch *pgutil.Batch) {
 	for index, creatable := range creatables {
 		// If deleted.
 		if !creatable.Created {
@@ -311,7 +455,7 @@ func writeDeletedCreatables(round basics.Round, creatables map[basics.CreatableI
 	}
 }
 
-func writeDeletedAssetHoldings(round basics.Round, modifiedAssetHoldings map[ledgercore.AccountAsset]bool, batch *pgx.Batch) {
+func writeDeletedAssetHoldings(round basics.Round, modifiedAssetHoldings map[ledgercore.AccountAsset]bool, batch *pgutil.Batch) {
 	for aa, created := range modifiedAssetHoldings {
 		if !created {
 			address := new(basics.Address)
@@ -323,7 +467,7 @@ func writeDeletedAssetHoldings(round basics.Round, modifiedAssetHoldings map[led
 	}
 }
 
-func writeDeletedAppLocalStates(round basics.Round, modifiedAppLocalStates map[ledgercore.AccountApp]bool, batch *pgx.Batch) {
+func writeDeletedAppLocalStates(round basics.Round, modifiedAppLocalStates map[ledgercore.AccountApp]bool, batch *pgutil.Batch) {
 	for aa, created := range modifiedAppLocalStates {
 		if !created {
 			address := new(basics.Address)
@@ -334,14 +478,14 @@ func writeDeletedAppLocalStates(round basics.Round, modifiedAppLocalStates map[l
 	}
 }
 
-func writeStateDelta(round basics.Round, delta ledgercore.StateDelta, specialAddresses transactions.SpecialAddresses, batch *pgx.Batch) {
+func writeStateDelta(round basics.Round, delta ledgercore.StateDelta, specialAddresses transactions.SpecialAddresses, batch *pgutil.Batch) {
 	writeAccountDeltas(round, delta.Accts, specialAddresses, batch)
 	writeDeletedCreatables(round, delta.Creatables, batch)
 	writeDeletedAssetHoldings(round, delta.ModifiedAssetHoldings, batch)
 	writeDeletedAppLocalStates(round, delta.ModifiedAppLocalStates, batch)
 }
 
-func updateAccountSigType(payset []transactions.SignedTxnInBlock, batch *pgx.Batch) error {
+func updateAccountSigType(payset []transactions.SignedTxnInBlock, batch *pgutil.Batch) error {
 	for i := range payset {
 		if payset[i].Txn.RekeyTo == (basics.Address{}) {
 			sigtype, err := idb.SignatureType(&payset[i].SignedTxn)
@@ -359,35 +503,68 @@ func updateAccountSigType(payset []transactions.SignedTxnInBlock, batch *pgx.Bat
 
 // AddBlock writes the block and accounting state deltas to the database.
 func (w *Writer) AddBlock(block *bookkeeping.Block, modifiedTxns []transactions.SignedTxnInBlock, delta ledgercore.StateDelta) error {
-	var batch pgx.Batch
+	batch := pgutil.MakeBatch(w.tx, statements)
 
 	specialAddresses := transactions.SpecialAddresses{
 		FeeSink:     block.FeeSink,
 		RewardsPool: block.RewardsPool,
 	}
 
-	addBlockHeader(&block.BlockHeader, &batch)
+	if !w.stateOnly {
+		addBlockHeader(&block.BlockHeader, &batch)
+	}
 	setSpecialAccounts(specialAddresses, &batch)
-	err := addTransactions(block, modifiedTxns, &batch)
-	if err != nil {
-		return fmt.Errorf("AddBlock() err: %w", err)
+	addTxn := func(row ...interface{}) {
+		batch.Queue(addTxnStmtName, row...)
 	}
-	err = addTransactionParticipation(block, &batch)
-	if err != nil {
-		return fmt.Errorf("AddBlock() err: %w", err)
+	addParticipant := func(row ...interface{}) {
+		batch.Queue(addTxnParticipantStmtName, row...)
+	}
+	if w.copy {
+		addTxn = func(row ...interface{}) {
+			w.txnRows = append(w.txnRows, row)
+		}
+		addParticipant = func(row ...interface{}) {
+			w.participationRows = append(w.participationRows, row)
+		}
+	}
+
+	if !w.stateOnly {
+		stored, err := storedTransactions(block, w.txnRules, w.scope)
+		if err != nil {
+			return fmt.Errorf("AddBlock() err: %w", err)
+		}
+		err = addTransactions(block, modifiedTxns, stored, w.compress, addTxn)
+		if err != nil {
+			return fmt.Errorf("AddBlock() err: %w", err)
+		}
+		err = addTransactionParticipation(block, stored, addParticipant)
+		if err != nil {
+			return fmt.Errorf("AddBlock() err: %w", err)
+		}
 	}
 	writeStateDelta(block.Round(), delta, specialAddresses, &batch)
-	err = updateAccountSigType(block.Payset, &batch)
+	err := updateAccountSigType(block.Payset, &batch)
 	if err != nil {
 		return fmt.Errorf("AddBlock() err: %w", err)
 	}
+	if !w.stateOnly && !w.noNotify {
+		batch.Batch.Queue(notifyRoundQuery, strconv.FormatUint(uint64(block.Round()), 10))
+	}
 
-	results := w.tx.SendBatch(context.Background(), &batch)
+	// The statements are pipelined and run in order, so the time until the
+	// result of a statement arrives after the previous one is the time the
+	// database spent on it. The first one also includes sending the batch.
+	start := time.Now()
+	results := w.tx.SendBatch(context.Background(), &batch.Batch)
 	for i := 0; i < batch.Len(); i++ {
 		_, err := results.Exec()
 		if err != nil {
 			return fmt.Errorf("AddBlock() exec err: %w", err)
 		}
+		now := time.Now()
+		observeWrite(statementTables[batch.Name(i)], 1, now.Sub(start))
+		start = now
 	}
 	err = results.Close()
 	if err != nil {
@@ -396,3 +573,16 @@ func (w *Writer) AddBlock(block *bookkeeping.Block, modifiedTxns []transactions.
 
 	return nil
 }
+
+// AddAccountTotals writes the account totals after `round`.
+func (w *Writer) AddAccountTotals(round basics.Round, totals ledgercore.AccountTotals) error {
+	start := time.Now()
+	_, err := w.tx.Exec(
+		context.Background(), pgutil.Query(w.tx, statements, upsertAccountTotalsStmtName),
+		uint64(round), encoding.EncodeAccountTotals(totals))
+	if err != nil {
+		return fmt.Errorf("AddAccountTotals() err: %w", err)
+	}
+	observeWrite(statementTables[upsertAccountTotalsStmtName], 1, time.Since(start))
+	return nil
+}
@@ -158,7 +158,10 @@ func TestWriterTxnTableBasic(t *testing.T) {
 	err = pgutil.TxWithRetry(db, serializable, f, nil)
 	require.NoError(t, err)
 
-	rows, err := db.Query(context.Background(), "SELECT * FROM txn ORDER BY intra")
+	rows, err := db.Query(
+		context.Background(),
+		"SELECT round, intra, typeenum, asset, txid, txnbytes, txnbytes_len, txn, extra "+
+			"FROM txn ORDER BY intra")
 	require.NoError(t, err)
 
 	var round uint64
@@ -167,11 +170,12 @@ func TestWriterTxnTableBasic(t *testing.T) {
 	var asset uint64
 	var txid []byte
 	var txnbytes []byte
+	var txnbytesLen *int
 	var txn []byte
 	var extra []byte
 
 	require.True(t, rows.Next())
-	err = rows.Scan(&round, &intra, &typeenum, &asset, &txid, &txnbytes, &txn, &extra)
+	err = rows.Scan(&round, &intra, &typeenum, &asset, &txid, &txnbytes, &txnbytesLen, &txn, &extra)
 	require.NoError(t, err)
 	assert.Equal(t, block.Round(), basics.Round(round))
 	assert.Equal(t, uint64(0), intra)
@@ -179,6 +183,7 @@ func TestWriterTxnTableBasic(t *testing.T) {
 	assert.Equal(t, uint64(0), asset)
 	assert.Equal(t, stxnad0.ID().String(), string(txid))
 	assert.Equal(t, protocol.Encode(&stxnad0), txnbytes)
+	assert.Nil(t, txnbytesLen)
 	{
 		stxn, err := encoding.DecodeSignedTxnWithAD(txn)
 		require.NoError(t, err)
@@ -187,7 +192,7 @@ func TestWriterTxnTableBasic(t *testing.T) {
 	assert.Equal(t, "{}", string(extra))
 
 	require.True(t, rows.Next())
-	err = rows.Scan(&round, &intra, &typeenum, &asset, &txid, &txnbytes, &txn, &extra)
+	err = rows.Scan(&round, &intra, &typeenum, &asset, &txid, &txnbytes, &txnbytesLen, &txn, &extra)
 	require.NoError(t, err)
 	assert.Equal(t, block.Round(), basics.Round(round))
 	assert.Equal(t, uint64(1), intra)
@@ -195,6 +200,7 @@ func TestWriterTxnTableBasic(t *testing.T) {
 	assert.Equal(t, uint64(9), asset)
 	assert.Equal(t, stxnad1.ID().String(), string(txid))
 	assert.Equal(t, protocol.Encode(&stxnad1), txnbytes)
+	assert.Nil(t, txnbytesLen)
 	{
 		stxn, err := encoding.DecodeSignedTxnWithAD(txn)
 		require.NoError(t, err)
@@ -346,6 +352,222 @@ func TestWriterTxnParticipationTableBasic(t *testing.T) {
 	assert.NoError(t, rows.Err())
 }
 
+// Transactions which the rules skip are neither in txn nor in txn_participation.
+func TestWriterTxnRules(t *testing.T) {
+	db, shutdownFunc := setupPostgres(t)
+	defer shutdownFunc()
+
+	block := bookkeeping.Block{
+		BlockHeader: bookkeeping.BlockHeader{
+			Round:       basics.Round(2),
+			GenesisID:   test.MakeGenesis().ID(),
+			GenesisHash: test.GenesisHash,
+			UpgradeState: bookkeeping.UpgradeState{
+				CurrentProtocol: test.Proto,
+			},
+		},
+		Payset: make(transactions.Payset, 2),
+	}
+
+	stxnad0 := test.MakePaymentTxn(
+		1000, 1, 0, 0, 0, 0, test.AccountA, test.AccountB, basics.Address{},
+		basics.Address{})
+	var err error
+	block.Payset[0], err = block.EncodeSignedTxn(stxnad0.SignedTxn, stxnad0.ApplyData)
+	require.NoError(t, err)
+
+	stxnad1 := test.MakeConfigAssetTxn(
+		0, 100, 1, false, "ma", "myasset", "myasset.com", test.AccountC)
+	block.Payset[1], err = block.EncodeSignedTxn(stxnad1.SignedTxn, stxnad1.ApplyData)
+	require.NoError(t, err)
+
+	rules, err := idb.MakeTxnRules(idb.TxnRulesConfig{
+		Rules: []idb.TxnRule{{
+			Types:   []string{"acfg"},
+			Senders: []string{test.AccountC.String()},
+			Exclude: true,
+		}},
+	})
+	require.NoError(t, err)
+
+	f := func(tx pgx.Tx) error {
+		w, err := writer.MakeWriter(tx)
+		require.NoError(t, err)
+		defer w.Close()
+		w.SetTxnRules(rules)
+
+		err = w.AddBlock(&block, block.Payset, ledgercore.StateDelta{})
+		require.NoError(t, err)
+
+		return tx.Commit(context.Background())
+	}
+	err = pgutil.TxWithRetry(db, serializable, f, nil)
+	require.NoError(t, err)
+
+	var intras []uint64
+	rows, err := db.Query(context.Background(), "SELECT intra FROM txn ORDER BY intra")
+	require.NoError(t, err)
+	for rows.Next() {
+		var intra uint64
+		require.NoError(t, rows.Scan(&intra))
+		intras = append(intras, intra)
+	}
+	require.NoError(t, rows.Err())
+	assert.Equal(t, []uint64{0}, intras)
+
+	var count int
+	err = db.QueryRow(
+		context.Background(),
+		"SELECT count(*) FROM txn_participation WHERE intra = 1").Scan(&count)
+	require.NoError(t, err)
+	assert.Equal(t, 0, count)
+	err = db.QueryRow(
+		context.Background(), "SELECT count(*) FROM txn_participation").Scan(&count)
+	require.NoError(t, err)
+	assert.Equal(t, 2, count)
+}
+
+// Only the transactions which involve the address scope are written.
+func TestWriterAddressScope(t *testing.T) {
+	db, shutdownFunc := setupPostgres(t)
+	defer shutdownFunc()
+
+	block := bookkeeping.Block{
+		BlockHeader: bookkeeping.BlockHeader{
+			Round:       basics.Round(2),
+			GenesisID:   test.MakeGenesis().ID(),
+			GenesisHash: test.GenesisHash,
+			TxnCounter:  9,
+			UpgradeState: bookkeeping.UpgradeState{
+				CurrentProtocol: test.Proto,
+			},
+		},
+		Payset: make(transactions.Payset, 3),
+	}
+
+	stxnad0 := test.MakePaymentTxn(
+		1000, 1, 0, 0, 0, 0, test.AccountA, test.AccountB, basics.Address{},
+		basics.Address{})
+	var err error
+	block.Payset[0], err = block.EncodeSignedTxn(stxnad0.SignedTxn, stxnad0.ApplyData)
+	require.NoError(t, err)
+
+	stxnad1 := test.MakePaymentTxn(
+		1000, 2, 0, 0, 0, 0, test.AccountC, test.AccountD, basics.Address{},
+		basics.Address{})
+	block.Payset[1], err = block.EncodeSignedTxn(stxnad1.SignedTxn, stxnad1.ApplyData)
+	require.NoError(t, err)
+
+	// Creates asset 9.
+	stxnad2 := test.MakeConfigAssetTxn(
+		0, 100, 1, false, "ma", "myasset", "myasset.com", test.AccountC)
+	block.Payset[2], err = block.EncodeSignedTxn(stxnad2.SignedTxn, stxnad2.ApplyData)
+	require.NoError(t, err)
+
+	scope, err := idb.ParseAddressScope([]string{test.AccountB.String()})
+	require.NoError(t, err)
+	scope.AddAsset(9)
+
+	f := func(tx pgx.Tx) error {
+		w, err := writer.MakeWriter(tx)
+		require.NoError(t, err)
+		defer w.Close()
+		w.SetAddressScope(scope)
+
+		err = w.AddBlock(&block, block.Payset, ledgercore.StateDelta{})
+		require.NoError(t, err)
+
+		return tx.Commit(context.Background())
+	}
+	err = pgutil.TxWithRetry(db, serializable, f, nil)
+	require.NoError(t, err)
+
+	var intras []uint64
+	rows, err := db.Query(context.Background(), "SELECT intra FROM txn ORDER BY intra")
+	require.NoError(t, err)
+	for rows.Next() {
+		var intra uint64
+		require.NoError(t, rows.Scan(&intra))
+		intras = append(intras, intra)
+	}
+	require.NoError(t, rows.Err())
+	assert.Equal(t, []uint64{0, 2}, intras)
+}
+
+// In copy mode the transactions of several blocks are written by Flush().
+func TestWriterCopyTransactions(t *testing.T) {
+	db, shutdownFunc := setupPostgres(t)
+	defer shutdownFunc()
+
+	makeBlock := func(round basics.Round) bookkeeping.Block {
+		block := bookkeeping.Block{
+			BlockHeader: bookkeeping.BlockHeader{
+				Round:       round,
+				GenesisID:   test.MakeGenesis().ID(),
+				GenesisHash: test.GenesisHash,
+				UpgradeState: bookkeeping.UpgradeState{
+					CurrentProtocol: test.Proto,
+				},
+			},
+			Payset: make(transactions.Payset, 1),
+		}
+		stxnad := test.MakePaymentTxn(
+			1000, uint64(round), 0, 0, 0, 0, test.AccountA, test.AccountB,
+			basics.Address{}, basics.Address{})
+		var err error
+		block.Payset[0], err = block.EncodeSignedTxn(stxnad.SignedTxn, stxnad.ApplyData)
+		require.NoError(t, err)
+		return block
+	}
+	blocks := []bookkeeping.Block{makeBlock(2), makeBlock(3)}
+
+	count := func(tx pgx.Tx, table string) int {
+		var n int
+		row := tx.QueryRow(context.Background(), "SELECT count(*) FROM "+table)
+		require.NoError(t, row.Scan(&n))
+		return n
+	}
+
+	f := func(tx pgx.Tx) error {
+		w, err := writer.MakeWriter(tx)
+		require.NoError(t, err)
+		defer w.Close()
+		w.CopyTransactions()
+
+		for i := range blocks {
+			err = w.AddBlock(&blocks[i], blocks[i].Payset, ledgercore.StateDelta{})
+			require.NoError(t, err)
+		}
+		assert.Equal(t, 2, count(tx, "block_header"))
+		assert.Equal(t, 0, count(tx, "txn"))
+		assert.Equal(t, 0, count(tx, "txn_participation"))
+
+		err = w.Flush()
+		require.NoError(t, err)
+
+		return tx.Commit(context.Background())
+	}
+	err := pgutil.TxWithRetry(db, serializable, f, nil)
+	require.NoError(t, err)
+
+	rows, err := db.Query(context.Background(), "SELECT round, intra FROM txn ORDER BY round")
+	require.NoError(t, err)
+	var round, intra uint64
+	for _, expected := range []uint64{2, 3} {
+		require.True(t, rows.Next())
+		require.NoError(t, rows.Scan(&round, &intra))
+		assert.Equal(t, expected, round)
+		assert.Equal(t, uint64(0), intra)
+	}
+	assert.False(t, rows.Next())
+	assert.NoError(t, rows.Err())
+
+	var n int
+	row := db.QueryRow(context.Background(), "SELECT count(*) FROM txn_participation")
+	require.NoError(t, row.Scan(&n))
+	assert.Equal(t, 4, n)
+}
+
 // Create a new account and then delete it.
 func TestWriterAccountTableBasic(t *testing.T) {
 	db, shutdownFunc := setupPostgres(t)
@@ -390,7 +612,10 @@ func TestWriterAccountTableBasic(t *testing.T) {
 	err := pgutil.TxWithRetry(db, serializable, f, nil)
 	require.NoError(t, err)
 
-	rows, err := db.Query(context.Background(), "SELECT * FROM account")
+	rows, err := db.Query(
+		context.Background(),
+		"SELECT addr, microalgos, rewardsbase, rewards_total, deleted, created_at, "+
+			"closed_at, keytype, account_data FROM account")
 	require.NoError(t, err)
 
 	var addr []byte
@@ -444,7 +669,10 @@ func TestWriterAccountTableBasic(t *testing.T) {
 	err = pgutil.TxWithRetry(db, serializable, f, nil)
 	require.NoError(t, err)
 
-	rows, err = db.Query(context.Background(), "SELECT * FROM account")
+	rows, err = db.Query(
+		context.Background(),
+		"SELECT addr, microalgos, rewardsbase, rewards_total, deleted, created_at, "+
+			"closed_at, keytype, account_data FROM account")
 	require.NoError(t, err)
 
 	require.True(t, rows.Next())
@@ -492,7 +720,10 @@ func TestWriterAccountTableCreateDeleteSameRound(t *testing.T) {
 	err := pgutil.TxWithRetry(db, serializable, f, nil)
 	require.NoError(t, err)
 
-	rows, err := db.Query(context.Background(), "SELECT * FROM account")
+	rows, err := db.Query(
+		context.Background(),
+		"SELECT addr, microalgos, rewardsbase, rewards_total, deleted, created_at, "+
+			"closed_at, keytype, account_data FROM account")
 	require.NoError(t, err)
 
 	var addr []byte
@@ -588,6 +819,72 @@ func TestWriterDeleteAccountDoesNotDeleteKeytype(t *testing.T) {
 	assert.Equal(t, "sig", keytype)
 }
 
+func TestWriterAccountResourceCounts(t *testing.T) {
+	db, shutdownFunc := setupPostgres(t)
+	defer shutdownFunc()
+
+	var block bookkeeping.Block
+	block.BlockHeader.Round = basics.Round(1)
+
+	accountData := basics.AccountData{
+		MicroAlgos: basics.MicroAlgos{Raw: 5},
+		Assets: map[basics.AssetIndex]basics.AssetHolding{
+			3: {Amount: 4},
+			4: {},
+		},
+		AssetParams: map[basics.AssetIndex]basics.AssetParams{
+			3: {Total: 4},
+		},
+		AppLocalStates: map[basics.AppIndex]basics.AppLocalState{
+			5: {},
+			6: {},
+			7: {},
+		},
+		AppParams: map[basics.AppIndex]basics.AppParams{
+			5: {},
+			6: {},
+			7: {},
+			8: {},
+		},
+	}
+	var delta ledgercore.StateDelta
+	delta.Accts.Upsert(test.AccountA, accountData)
+
+	f := func(tx pgx.Tx) error {
+		w, err := writer.MakeWriter(tx)
+		require.NoError(t, err)
+		defer w.Close()
+
+		err = w.AddBlock(&block, block.Payset, delta)
+		require.NoError(t, err)
+
+		return tx.Commit(context.Background())
+	}
+	err := pgutil.TxWithRetry(db, serializable, f, nil)
+	require.NoError(t, err)
+
+	query := "SELECT total_assets_opted_in, total_created_assets, total_apps_opted_in, " +
+		"total_created_apps FROM account WHERE addr = $1"
+	var counts [4]uint64
+	row := db.QueryRow(context.Background(), query, test.AccountA[:])
+	err = row.Scan(&counts[0], &counts[1], &counts[2], &counts[3])
+	require.NoError(t, err)
+	assert.Equal(t, [4]uint64{2, 1, 3, 4}, counts)
+
+	// Closing the account resets the counts.
+	block.BlockHeader.Round++
+	delta.Accts = ledgercore.AccountDeltas{}
+	delta.Accts.Upsert(test.AccountA, basics.AccountData{})
+
+	err = pgutil.TxWithRetry(db, serializable, f, nil)
+	require.NoError(t, err)
+
+	row = db.QueryRow(context.Background(), query, test.AccountA[:])
+	err = row.Scan(&counts[0], &counts[1], &counts[2], &counts[3])
+	require.NoError(t, err)
+	assert.Equal(t, [4]uint64{}, counts)
+}
+
 func TestWriterAccountAssetTableBasic(t *testing.T) {
 	db, shutdownFunc := setupPostgres(t)
 	defer shutdownFunc()
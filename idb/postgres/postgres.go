@@ -9,6 +9,9 @@ import (
 	"database/sql"
 	"fmt"
 	"os"
+	"regexp"
+	"sort"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -19,10 +22,13 @@ import (
 	"github.com/algorand/go-algorand/data/transactions"
 	"github.com/algorand/go-algorand/ledger"
 	"github.com/algorand/go-algorand/ledger/ledgercore"
+	"github.com/algorand/go-algorand/protocol"
 
 	"github.com/jackc/pgx/v4"
 	"github.com/jackc/pgx/v4/pgxpool"
 	log "github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 
 	models "github.com/algorand/indexer/api/generated/v2"
 	"github.com/algorand/indexer/idb"
@@ -34,21 +40,44 @@ import (
 	"github.com/algorand/indexer/idb/postgres/internal/writer"
 	"github.com/algorand/indexer/util"
 	"github.com/algorand/indexer/util/metrics"
+	"github.com/algorand/indexer/util/tracing"
 )
 
 type importState struct {
 	// Next round to account.
 	NextRoundToAccount *uint64 `codec:"next_account_round"`
+	// GenesisHash is the genesis hash of the network, which every added block
+	// must have. It is recorded by the first added block.
+	GenesisHash string `codec:"genesis_hash,omitempty"`
+	// TxnRules is set once a block was added with transaction rules. The stored
+	// blocks then miss the skipped transactions and cannot be evaluated again.
+	TxnRules bool `codec:"txn_rules,omitempty"`
+	// AddressScope are the addresses of every address scope which blocks were
+	// added with, sorted. The stored blocks then only have the transactions of
+	// these addresses.
+	AddressScope []string `codec:"address_scope,omitempty"`
 }
 
 var serializable = pgx.TxOptions{IsoLevel: pgx.Serializable} // be a real ACID database
 var readonlyRepeatableRead = pgx.TxOptions{IsoLevel: pgx.RepeatableRead, AccessMode: pgx.ReadOnly}
 
+// setApplicationNameQuery sets the application_name until the end of the
+// transaction.
+const setApplicationNameQuery = "SELECT set_config('application_name', $1, true)"
+
 // OpenPostgres is available for creating test instances of postgres.IndexerDb
 // Returns an error object and a channel that gets closed when blocking migrations
 // finish running successfully.
 func OpenPostgres(connection string, opts idb.IndexerDbOptions, log *log.Logger) (*IndexerDb, chan struct{}, error) {
-	db, err := pgxpool.Connect(context.Background(), connection)
+	postgresConfig, err := makePoolConfig(connection, opts)
+	if err != nil {
+		return nil, nil, err
+	}
+	if opts.SlowQueryThreshold != 0 {
+		logSlowQueries(postgresConfig, opts.SlowQueryThreshold, log)
+	}
+
+	db, err := pgxpool.ConnectConfig(context.Background(), postgresConfig)
 
 	if err != nil {
 		return nil, nil, fmt.Errorf("connecting to postgres: %v", err)
@@ -58,15 +87,113 @@ func OpenPostgres(connection string, opts idb.IndexerDbOptions, log *log.Logger)
 		opts.ReadOnly = true
 	}
 
-	return openPostgres(db, opts, log)
+	idb, ch, err := openPostgres(db, opts, log)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if opts.ReadConnection != "" {
+		replicaConfig, err := makePoolConfig(opts.ReadConnection, opts)
+		if err != nil {
+			return nil, nil, fmt.Errorf("replica: %w", err)
+		}
+		if opts.SlowQueryThreshold != 0 {
+			logSlowQueries(replicaConfig, opts.SlowQueryThreshold, log)
+		}
+		pool, err := pgxpool.ConnectConfig(context.Background(), replicaConfig)
+		if err != nil {
+			return nil, nil, fmt.Errorf("connecting to postgres replica: %v", err)
+		}
+		idb.replica = makeReplica(pool, opts.ReplicaMaxLag)
+		// The pools are used until the process exits, and so is the replica.
+		go idb.replica.monitor(context.Background(), db, idb.log)
+	}
+	setPoolMetricsDB(idb)
+
+	return idb, ch, nil
+}
+
+// schemaNameRegexp matches the schema names which don't need to be quoted.
+var schemaNameRegexp = regexp.MustCompile(`^[a-z_][a-z0-9_]*$`)
+
+// makePoolConfig parses `connection` and applies the pool settings of `opts`.
+func makePoolConfig(connection string, opts idb.IndexerDbOptions) (*pgxpool.Config, error) {
+	postgresConfig, err := pgxpool.ParseConfig(connection)
+	if err != nil {
+		return nil, fmt.Errorf("parsing postgres connection string: %v", err)
+	}
+	if opts.MaxConn != 0 {
+		postgresConfig.MaxConns = int32(opts.MaxConn)
+	}
+	if opts.MinConn > uint32(postgresConfig.MaxConns) {
+		return nil, fmt.Errorf(
+			"minimum pool size %d is larger than the maximum %d",
+			opts.MinConn, postgresConfig.MaxConns)
+	}
+	if opts.MinConn != 0 {
+		postgresConfig.MinConns = int32(opts.MinConn)
+	}
+	if opts.MaxConnLifetime != 0 {
+		postgresConfig.MaxConnLifetime = opts.MaxConnLifetime
+	}
+	if opts.HealthCheckPeriod != 0 {
+		postgresConfig.HealthCheckPeriod = opts.HealthCheckPeriod
+	}
+	if opts.PgBouncerCompat {
+		// PgBouncer rejects the startup parameters it does not know.
+		if opts.StatementTimeout != 0 || opts.Schema != "" {
+			return nil, fmt.Errorf(
+				"the statement timeout and schema can't be set with PgBouncer compatibility, " +
+					"set them on the database role with ALTER ROLE ... SET instead")
+		}
+		// A transaction pooler may run each transaction on a different server
+		// connection, which does not have the statements prepared on another.
+		// Without the cache pgx sends each query as an unnamed statement.
+		postgresConfig.ConnConfig.BuildStatementCache = nil
+		postgresConfig.AfterConnect = func(ctx context.Context, conn *pgx.Conn) error {
+			pgutil.DisablePreparedStatements(conn)
+			return nil
+		}
+	}
+	if opts.StatementTimeout != 0 {
+		postgresConfig.ConnConfig.RuntimeParams["statement_timeout"] =
+			strconv.FormatInt(opts.StatementTimeout.Milliseconds(), 10)
+	}
+	if opts.Schema != "" {
+		if !schemaNameRegexp.MatchString(opts.Schema) {
+			return nil, fmt.Errorf(
+				"invalid schema name \"%s\", it may only contain lower case letters, digits and underscores",
+				opts.Schema)
+		}
+		// All queries use unqualified table names.
+		postgresConfig.ConnConfig.RuntimeParams["search_path"] = opts.Schema
+	}
+	return postgresConfig, nil
 }
 
 // Allow tests to inject a DB
 func openPostgres(db *pgxpool.Pool, opts idb.IndexerDbOptions, logger *log.Logger) (*IndexerDb, chan struct{}, error) {
 	idb := &IndexerDb{
-		readonly: opts.ReadOnly,
-		log:      logger,
-		db:       db,
+		readonly:           opts.ReadOnly,
+		log:                logger,
+		db:                 db,
+		compressTxnBytes:   opts.CompressTxnBytes,
+		schema:             opts.Schema,
+		pgbouncerCompat:    opts.PgBouncerCompat,
+		cockroachCompat:    opts.CockroachCompat,
+		migrationWorkers:   opts.MigrationWorkers,
+		migrationDiskSpace: opts.MigrationDiskSpace,
+		accountCache:       ledger_for_evaluator.MakeCache(opts.AccountCacheSize),
+		lookupBatchSize:    opts.LookupBatchSize,
+		lookupPipeline:     opts.LookupPipeline,
+		txnRules:           opts.TxnRules,
+		addressScope:       opts.AddressScope,
+	}
+
+	var err error
+	idb.operatorScripts, err = loadOperatorScripts(opts.SQLMigrationsDir)
+	if err != nil {
+		return nil, nil, fmt.Errorf("openPostgres() err: %w", err)
 	}
 
 	if idb.log == nil {
@@ -76,6 +203,19 @@ func openPostgres(db *pgxpool.Pool, opts idb.IndexerDbOptions, logger *log.Logge
 		idb.log.SetLevel(log.TraceLevel)
 	}
 
+	// CockroachDB has no max_connections, it is limited by the resources of the
+	// nodes instead.
+	if !opts.CockroachCompat {
+		settings, err := loadServerSettings(context.Background(), db)
+		if err != nil {
+			return nil, nil, fmt.Errorf("openPostgres() err: %w", err)
+		}
+		err = idb.checkServerSettings(settings)
+		if err != nil {
+			return nil, nil, fmt.Errorf("openPostgres() err: %w", err)
+		}
+	}
+
 	var ch chan struct{}
 	// e.g. a user named "readonly" is in the connection string
 	if opts.ReadOnly {
@@ -88,8 +228,10 @@ func openPostgres(db *pgxpool.Pool, opts idb.IndexerDbOptions, logger *log.Logge
 		if !migrationStateBlocked(migrationState) {
 			close(ch)
 		}
+	} else if opts.SkipMigrations {
+		ch = make(chan struct{})
+		close(ch)
 	} else {
-		var err error
 		ch, err = idb.init(opts)
 		if err != nil {
 			return nil, nil, fmt.Errorf("initializing postgres: %v", err)
@@ -104,9 +246,62 @@ type IndexerDb struct {
 	readonly bool
 	log      *log.Logger
 
-	db             *pgxpool.Pool
-	migration      *migration.Migration
-	accountingLock sync.Mutex
+	db        *pgxpool.Pool
+	migration *migration.Migration
+	// accountingLock is held by the writers of the account state. The workers
+	// of parallelMigration() share it, the others hold it exclusively.
+	accountingLock sync.RWMutex
+
+	// migrationWorkers is the number of workers of parallelMigration(), 0 for
+	// the default.
+	migrationWorkers int
+
+	// migrationDiskSpace is the free disk space of the database in bytes, 0 if
+	// it is not known. See checkMigration().
+	migrationDiskSpace uint64
+
+	// operatorScripts are the SQL migrations supplied by the operator.
+	operatorScripts []operatorScript
+
+	// accountCache keeps the accounts loaded by the import across rounds. It
+	// must be cleared when the account state is changed by anything else.
+	accountCache *ledger_for_evaluator.Cache
+
+	// lookupBatchSize and lookupPipeline configure how the import reads the
+	// accounts, see LedgerForEvaluator.SetLookupBatching().
+	lookupBatchSize int
+	lookupPipeline  bool
+
+	// blockHooks are called with the blocks added by AddBlock() and
+	// AddBlocks(). They are guarded by accountingLock.
+	blockHooks []idb.BlockHook
+
+	// compressTxnBytes compresses the encoded transactions written by AddBlock().
+	compressTxnBytes bool
+
+	// txnRules decides which transactions AddBlock() writes, nil for all.
+	txnRules *idb.TxnRules
+
+	// addressScope limits the transactions which AddBlock() writes to those
+	// involving it, nil for all. Its creatables are loaded from the database by
+	// the first import, see loadScopeCreatables().
+	addressScope       *idb.AddressScope
+	addressScopeLoaded bool
+
+	// schema is the postgres schema of the tables, empty for the default.
+	schema string
+
+	// pgbouncerCompat is set if the connections go through a transaction
+	// pooler, which does not support LISTEN.
+	pgbouncerCompat bool
+
+	// cockroachCompat is set if the database is CockroachDB, which does not
+	// support LISTEN/NOTIFY and the postgres statistics views.
+	cockroachCompat bool
+
+	// replica serves the API queries when it is configured and not too far
+	// behind, may be nil.
+	replica *replica
 }
 
 // txWithRetry is a helper function that retries the function `f` in case the database
@@ -119,8 +314,11 @@ func (db *IndexerDb) txWithRetry(opts pgx.TxOptions, f func(pgx.Tx) error) error
 	return pgutil.TxWithRetry(db.db, opts, f, db.log)
 }
 
+// isSetup returns true if the tables exist in the schema of the indexer. The
+// tables of other schemas which share the database are ignored.
 func (db *IndexerDb) isSetup() (bool, error) {
-	query := `SELECT 0 FROM INFORMATION_SCHEMA.TABLES WHERE TABLE_NAME = 'metastate'`
+	query := `SELECT 0 FROM INFORMATION_SCHEMA.TABLES
+		WHERE TABLE_NAME = 'metastate' AND TABLE_SCHEMA = current_schema()`
 	row := db.db.QueryRow(context.Background(), query)
 
 	var tmp int
@@ -144,7 +342,17 @@ func (db *IndexerDb) init(opts idb.IndexerDbOptions) (chan struct{}, error) {
 
 	if !setup {
 		// new database, run setup
-		_, err = db.db.Exec(context.Background(), schema.SetupPostgresSql)
+		if db.schema != "" {
+			_, err = db.db.Exec(context.Background(), "CREATE SCHEMA IF NOT EXISTS "+db.schema)
+			if err != nil {
+				return nil, fmt.Errorf("unable to create schema %s: %v", db.schema, err)
+			}
+		}
+		setupSql := schema.SetupPostgresSql
+		if db.cockroachCompat {
+			setupSql = schema.SetupCockroachSql
+		}
+		_, err = db.db.Exec(context.Background(), setupSql)
 		if err != nil {
 			return nil, fmt.Errorf("unable to setup postgres: %v", err)
 		}
@@ -154,6 +362,11 @@ func (db *IndexerDb) init(opts idb.IndexerDbOptions) (chan struct{}, error) {
 			return nil, fmt.Errorf("unable to confirm migration: %v", err)
 		}
 
+		// The operator's scripts run on new databases as well.
+		if len(db.operatorScripts) > 0 {
+			return db.runAvailableMigrations()
+		}
+
 		ch := make(chan struct{})
 		close(ch)
 		return ch, nil
@@ -165,119 +378,373 @@ func (db *IndexerDb) init(opts idb.IndexerDbOptions) (chan struct{}, error) {
 
 // AddBlock is part of idb.IndexerDb.
 func (db *IndexerDb) AddBlock(block *bookkeeping.Block) error {
-	db.log.Printf("adding block %d", block.Round())
+	db.log.WithField("round", uint64(block.Round())).Info("adding block")
+	ctx, span := tracing.Start(context.Background(), "AddBlock", trace.SpanKindInternal,
+		attribute.Int64("indexer.round", int64(block.Round())))
+	defer span.End()
 
 	db.accountingLock.Lock()
 	defer db.accountingLock.Unlock()
 
+	var delta ledgercore.StateDelta
 	f := func(tx pgx.Tx) error {
 		defer tx.Rollback(context.Background())
 
-		// Check and increment next round counter.
-		importstate, err := db.getImportState(context.Background(), tx)
+		w, err := writer.MakeWriter(tx)
 		if err != nil {
 			return fmt.Errorf("AddBlock() err: %w", err)
 		}
-		if importstate.NextRoundToAccount == nil {
-			return fmt.Errorf("AddBlock() import state not initialized")
+		defer w.Close()
+		if db.compressTxnBytes {
+			w.EnableCompression()
 		}
-		if block.Round() != basics.Round(*importstate.NextRoundToAccount) {
-			return fmt.Errorf(
-				"AddBlock() adding block round %d but next round to account is %d",
-				block.Round(), *importstate.NextRoundToAccount)
+		w.SetTxnRules(db.txnRules)
+		w.SetAddressScope(db.addressScope)
+		if db.cockroachCompat {
+			w.DisableNotify()
 		}
-		*importstate.NextRoundToAccount++
-		err = db.setImportState(tx, importstate)
+
+		delta, err = db.addBlock(ctx, tx, &w, block, db.blockHooks)
 		if err != nil {
 			return fmt.Errorf("AddBlock() err: %w", err)
 		}
 
-		writer, err := writer.MakeWriter(tx)
+		err = tx.Commit(context.Background())
 		if err != nil {
-			return fmt.Errorf("AddBlock() err: %w", err)
+			return fmt.Errorf("AddBlock() tx commit err: %w", err)
 		}
-		defer writer.Close()
 
-		if block.Round() == basics.Round(0) {
-			// Block 0 is special, we cannot run the evaluator on it.
-			// It contains no transactions, so just write the header.
-			err := writer.AddBlock(block, nil, ledgercore.StateDelta{})
-			if err != nil {
-				return fmt.Errorf("AddBlock() err: %w", err)
-			}
-		} else {
-			specialAddresses := transactions.SpecialAddresses{
-				FeeSink:     block.FeeSink,
-				RewardsPool: block.RewardsPool,
-			}
-			ledgerForEval, err := ledger_for_evaluator.MakeLedgerForEvaluator(
-				tx, block.GenesisHash(), specialAddresses)
-			if err != nil {
-				return fmt.Errorf("AddBlock() err: %w", err)
-			}
+		return nil
+	}
+	err := db.txWithRetry(serializable, f)
+	if err != nil {
+		// The cache may hold accounts written by the rolled back transaction.
+		db.accountCache.Clear()
+		tracing.SetError(span, err)
+		return err
+	}
 
-			err = ledgerForEval.PreloadAccounts(ledger.GetBlockAddresses(block))
-			if err != nil {
-				return fmt.Errorf("AddBlock() err: %w", err)
-			}
+	for _, hook := range db.blockHooks {
+		hook.AfterBlock(block, delta)
+	}
+	return nil
+}
 
-			proto, ok := config.Consensus[block.BlockHeader.CurrentProtocol]
-			if !ok {
-				return fmt.Errorf(
-					"AddBlock() cannot find proto version %s", block.BlockHeader.CurrentProtocol)
-			}
-			proto.EnableAssetCloseAmount = true
+// AddBlocks is part of idb.IndexerDb. The transactions of all the blocks are
+// written at the end with the COPY protocol, which is much faster than inserting
+// them one by one.
+func (db *IndexerDb) AddBlocks(blocks []*bookkeeping.Block) error {
+	if len(blocks) == 0 {
+		return nil
+	}
+	db.log.WithFields(log.Fields{
+		"round":      uint64(blocks[0].Round()),
+		"last_round": uint64(blocks[len(blocks)-1].Round()),
+	}).Info("adding blocks")
+	ctx, span := tracing.Start(context.Background(), "AddBlocks", trace.SpanKindInternal,
+		attribute.Int64("indexer.round", int64(blocks[0].Round())),
+		attribute.Int64("indexer.blocks", int64(len(blocks))))
+	defer span.End()
 
-			start := time.Now()
-			delta, modifiedTxns, err := ledger.Eval(ledgerForEval, block, proto)
-			if err != nil {
-				return fmt.Errorf("AddBlock() eval err: %w", err)
-			}
-			metrics.PostgresEvalTimeSeconds.Observe(time.Since(start).Seconds())
-			ledgerForEval.Close()
+	db.accountingLock.Lock()
+	defer db.accountingLock.Unlock()
 
-			err = writer.AddBlock(block, modifiedTxns, delta)
+	var deltas []ledgercore.StateDelta
+	f := func(tx pgx.Tx) error {
+		defer tx.Rollback(context.Background())
+		deltas = deltas[:0]
+
+		w, err := writer.MakeWriter(tx)
+		if err != nil {
+			return fmt.Errorf("AddBlocks() err: %w", err)
+		}
+		defer w.Close()
+		w.CopyTransactions()
+		if db.compressTxnBytes {
+			w.EnableCompression()
+		}
+		w.SetTxnRules(db.txnRules)
+		w.SetAddressScope(db.addressScope)
+		if db.cockroachCompat {
+			w.DisableNotify()
+		}
+
+		for _, block := range blocks {
+			delta, err := db.addBlock(ctx, tx, &w, block, db.blockHooks)
 			if err != nil {
-				return fmt.Errorf("AddBlock() err: %w", err)
+				return fmt.Errorf("AddBlocks() err: %w", err)
 			}
+			deltas = append(deltas, delta)
+		}
+
+		_, flushSpan := tracing.Start(ctx, "write transactions", trace.SpanKindInternal)
+		err = w.Flush()
+		tracing.SetError(flushSpan, err)
+		flushSpan.End()
+		if err != nil {
+			return fmt.Errorf("AddBlocks() err: %w", err)
 		}
 
 		err = tx.Commit(context.Background())
 		if err != nil {
-			return fmt.Errorf("AddBlock() tx commit err: %w", err)
+			return fmt.Errorf("AddBlocks() tx commit err: %w", err)
 		}
 
 		return nil
 	}
-	return db.txWithRetry(serializable, f)
+	err := db.txWithRetry(serializable, f)
+	if err != nil {
+		// The cache may hold accounts written by the rolled back transaction.
+		db.accountCache.Clear()
+		tracing.SetError(span, err)
+		return err
+	}
+
+	for i, block := range blocks {
+		for _, hook := range db.blockHooks {
+			hook.AfterBlock(block, deltas[i])
+		}
+	}
+	return nil
 }
 
-// LoadGenesis is part of idb.IndexerDB
-func (db *IndexerDb) LoadGenesis(genesis bookkeeping.Genesis) (err error) {
-	tx, err := db.db.BeginTx(context.Background(), serializable)
+// AddBlockHook is part of idb.IndexerDb.
+func (db *IndexerDb) AddBlockHook(hook idb.BlockHook) {
+	db.accountingLock.Lock()
+	defer db.accountingLock.Unlock()
+
+	db.blockHooks = append(db.blockHooks, hook)
+}
+
+// loadScopeCreatables adds the assets and applications which the addresses of
+// the address scope created before to the scope, once.
+func (db *IndexerDb) loadScopeCreatables(tx pgx.Tx) error {
+	if db.addressScopeLoaded {
+		return nil
+	}
+
+	var addresses [][]byte
+	for _, address := range db.addressScope.Addresses() {
+		addresses = append(addresses, address[:])
+	}
+	queries := []struct {
+		query string
+		add   func(uint64)
+	}{
+		{"SELECT index FROM asset WHERE creator_addr = ANY($1)", db.addressScope.AddAsset},
+		{"SELECT index FROM app WHERE creator = ANY($1)", db.addressScope.AddApp},
+	}
+	for _, q := range queries {
+		rows, err := tx.Query(context.Background(), q.query, addresses)
+		if err != nil {
+			return fmt.Errorf("loadScopeCreatables() query err: %w", err)
+		}
+		for rows.Next() {
+			var index uint64
+			err = rows.Scan(&index)
+			if err != nil {
+				rows.Close()
+				return fmt.Errorf("loadScopeCreatables() scan err: %w", err)
+			}
+			q.add(index)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return fmt.Errorf("loadScopeCreatables() err: %w", err)
+		}
+	}
+
+	db.addressScopeLoaded = true
+	return nil
+}
+
+// mergeScopeAddresses returns the sorted union of `addresses` and `scope`.
+func mergeScopeAddresses(addresses []string, scope []basics.Address) []string {
+	set := make(map[string]bool, len(addresses)+len(scope))
+	for _, address := range addresses {
+		set[address] = true
+	}
+	for _, address := range scope {
+		set[address.String()] = true
+	}
+	res := make([]string, 0, len(set))
+	for address := range set {
+		res = append(res, address)
+	}
+	sort.Strings(res)
+	return res
+}
+
+// callBeforeBlock calls BeforeBlock() of each hook in `hooks`.
+func callBeforeBlock(hooks []idb.BlockHook, block *bookkeeping.Block, delta ledgercore.StateDelta) error {
+	for _, hook := range hooks {
+		err := hook.BeforeBlock(block, delta)
+		if err != nil {
+			return fmt.Errorf("callBeforeBlock() round %d err: %w", block.Round(), err)
+		}
+	}
+	return nil
+}
+
+// addBlock evaluates `block` and writes it with `w`. Blocks are evaluated against
+// the state written by `tx` so several blocks can be added in one transaction.
+// `hooks` are called before the block is written. It returns the state delta of
+// the block.
+func (db *IndexerDb) addBlock(ctx context.Context, tx pgx.Tx, w *writer.Writer, block *bookkeeping.Block, hooks []idb.BlockHook) (ledgercore.StateDelta, error) {
+	// Check and increment next round counter.
+	importstate, err := db.getImportState(context.Background(), tx)
 	if err != nil {
-		return
+		return ledgercore.StateDelta{}, fmt.Errorf("addBlock() err: %w", err)
+	}
+	if importstate.NextRoundToAccount == nil {
+		return ledgercore.StateDelta{}, fmt.Errorf("addBlock() import state not initialized")
+	}
+	if block.Round() != basics.Round(*importstate.NextRoundToAccount) {
+		return ledgercore.StateDelta{}, fmt.Errorf(
+			"addBlock() adding block round %d but next round to account is %d",
+			block.Round(), *importstate.NextRoundToAccount)
+	}
+	genesisHash := block.GenesisHash().String()
+	if importstate.GenesisHash == "" {
+		importstate.GenesisHash = genesisHash
+	} else if importstate.GenesisHash != genesisHash {
+		return ledgercore.StateDelta{}, fmt.Errorf(
+			"addBlock() block round %d has genesis hash %s but the database has %s: %w",
+			block.Round(), genesisHash, importstate.GenesisHash, idb.ErrorGenesisMismatch)
+	}
+	*importstate.NextRoundToAccount++
+	if db.txnRules != nil {
+		importstate.TxnRules = true
+	}
+	if db.addressScope != nil {
+		importstate.AddressScope = mergeScopeAddresses(
+			importstate.AddressScope, db.addressScope.Addresses())
+	}
+	err = db.setImportState(tx, importstate)
+	if err != nil {
+		return ledgercore.StateDelta{}, fmt.Errorf("addBlock() err: %w", err)
 	}
-	defer tx.Rollback(context.Background()) // ignored if .Commit() first
 
-	setAccountStatementName := "set_account"
-	query := `INSERT INTO account (addr, microalgos, rewardsbase, account_data, rewards_total, created_at, deleted) VALUES ($1, $2, 0, $3, $4, 0, false)`
-	_, err = tx.Prepare(context.Background(), setAccountStatementName, query)
+	if block.Round() == basics.Round(0) {
+		// Block 0 is special, we cannot run the evaluator on it.
+		// It contains no transactions, so just write the header.
+		err := callBeforeBlock(hooks, block, ledgercore.StateDelta{})
+		if err != nil {
+			return ledgercore.StateDelta{}, fmt.Errorf("addBlock() err: %w", err)
+		}
+		err = w.AddBlock(block, nil, ledgercore.StateDelta{})
+		if err != nil {
+			return ledgercore.StateDelta{}, fmt.Errorf("addBlock() err: %w", err)
+		}
+		return ledgercore.StateDelta{}, nil
+	}
+
+	specialAddresses := transactions.SpecialAddresses{
+		FeeSink:     block.FeeSink,
+		RewardsPool: block.RewardsPool,
+	}
+	// Also clears the cache when a failed transaction is retried, after it
+	// evaluated a block.
+	db.accountCache.StartRound(block.Round())
+	ledgerForEval, err := ledger_for_evaluator.MakeLedgerForEvaluator(
+		tx, block.GenesisHash(), specialAddresses, db.accountCache)
 	if err != nil {
-		return
+		return ledgercore.StateDelta{}, fmt.Errorf("addBlock() err: %w", err)
+	}
+	ledgerForEval.SetLookupBatching(db.lookupBatchSize, db.lookupPipeline)
+
+	err = ledgerForEval.PreloadFromPayset(block.Payset)
+	if err != nil {
+		return ledgercore.StateDelta{}, fmt.Errorf("addBlock() err: %w", err)
+	}
+
+	proto, ok := config.Consensus[block.BlockHeader.CurrentProtocol]
+	if !ok {
+		return ledgercore.StateDelta{}, fmt.Errorf(
+			"addBlock() cannot find proto version %s", block.BlockHeader.CurrentProtocol)
+	}
+	proto.EnableAssetCloseAmount = true
+
+	_, evalSpan := tracing.Start(ctx, "evaluate block", trace.SpanKindInternal,
+		attribute.Int64("indexer.round", int64(block.Round())))
+	start := time.Now()
+	delta, modifiedTxns, err := ledger.Eval(ledgerForEval, block, proto)
+	tracing.SetError(evalSpan, err)
+	evalSpan.End()
+	if err != nil {
+		return ledgercore.StateDelta{}, fmt.Errorf("addBlock() eval err: %w", err)
 	}
-	defer tx.Conn().Deallocate(context.Background(), setAccountStatementName)
+	metrics.PostgresEvalTimeSeconds.Observe(time.Since(start).Seconds())
+
+	// Needs the accounts before the block, so before the delta is written.
+	totals, err := ledgerForEval.NextTotals(
+		block.Round(), block.RewardsLevel, proto, delta)
+	if err != nil {
+		return ledgercore.StateDelta{}, fmt.Errorf("addBlock() err: %w", err)
+	}
+	ledgerForEval.Close()
+	db.accountCache.ApplyDelta(delta)
+	if db.addressScope != nil {
+		err = db.loadScopeCreatables(tx)
+		if err != nil {
+			return ledgercore.StateDelta{}, fmt.Errorf("addBlock() err: %w", err)
+		}
+		db.addressScope.AddCreatables(delta)
+	}
+	// The next round's evaluator asks for this header.
+	db.accountCache.AddHeader(block.BlockHeader)
+
+	err = callBeforeBlock(hooks, block, delta)
+	if err != nil {
+		return ledgercore.StateDelta{}, fmt.Errorf("addBlock() err: %w", err)
+	}
+	_, writeSpan := tracing.Start(ctx, "write block", trace.SpanKindInternal,
+		attribute.Int64("indexer.round", int64(block.Round())),
+		attribute.Int64("indexer.transactions", int64(len(block.Payset))))
+	defer writeSpan.End()
+	err = w.AddBlock(block, modifiedTxns, delta)
+	if err != nil {
+		tracing.SetError(writeSpan, err)
+		return ledgercore.StateDelta{}, fmt.Errorf("addBlock() err: %w", err)
+	}
+	err = w.AddAccountTotals(block.Round(), totals)
+	if err != nil {
+		tracing.SetError(writeSpan, err)
+		return ledgercore.StateDelta{}, fmt.Errorf("addBlock() err: %w", err)
+	}
+	return delta, nil
+}
+
+// LoadGenesis is part of idb.IndexerDB
+func (db *IndexerDb) LoadGenesis(genesis bookkeeping.Genesis) error {
+	f := func(tx pgx.Tx) error {
+		defer tx.Rollback(context.Background()) // ignored if .Commit() first
+
+		err := db.loadGenesisAccounts(tx, genesis)
+		if err != nil {
+			return err
+		}
+
+		return tx.Commit(context.Background())
+	}
+	return db.txWithRetry(serializable, f)
+}
+
+// loadGenesisAccounts writes the accounts of `genesis` and sets the next round to
+// account to 0.
+func (db *IndexerDb) loadGenesisAccounts(tx pgx.Tx, genesis bookkeeping.Genesis) error {
+	query := `INSERT INTO account (addr, microalgos, rewardsbase, account_data, rewards_total, created_at, deleted) VALUES ($1, $2, 0, $3, $4, 0, false)`
 
 	for ai, alloc := range genesis.Allocation {
 		addr, err := basics.UnmarshalChecksumAddress(alloc.Address)
 		if err != nil {
-			return nil
+			return fmt.Errorf("genesis account[%d] has invalid address, %v", ai, err)
 		}
 		if len(alloc.State.AssetParams) > 0 || len(alloc.State.Assets) > 0 {
 			return fmt.Errorf("genesis account[%d] has unhandled asset", ai)
 		}
 		_, err = tx.Exec(
-			context.Background(), setAccountStatementName,
+			context.Background(), query,
 			addr[:], alloc.State.MicroAlgos.Raw,
 			encoding.EncodeTrimmedAccountData(encoding.TrimAccountData(alloc.State)), 0)
 		if err != nil {
@@ -289,13 +756,7 @@ func (db *IndexerDb) LoadGenesis(genesis bookkeeping.Genesis) (err error) {
 	importstate := importState{
 		NextRoundToAccount: &nextRound,
 	}
-	err = db.setImportState(tx, importstate)
-	if err != nil {
-		return
-	}
-
-	err = tx.Commit(context.Background())
-	return err
+	return db.setImportState(tx, importstate)
 }
 
 // Returns `idb.ErrorNotInitialized` if uninitialized.
@@ -370,6 +831,19 @@ func (db *IndexerDb) GetNextRoundToAccount() (uint64, error) {
 	return db.getNextRoundToAccount(context.Background(), nil)
 }
 
+// GetGenesisHash is part of idb.IndexerDB
+// Returns ErrorNotInitialized if genesis is not loaded.
+func (db *IndexerDb) GetGenesisHash() (string, error) {
+	state, err := db.getImportState(context.Background(), nil)
+	if err == idb.ErrorNotInitialized {
+		return "", err
+	}
+	if err != nil {
+		return "", fmt.Errorf("GetGenesisHash() err: %w", err)
+	}
+	return state.GenesisHash, nil
+}
+
 // Returns ErrorNotInitialized if genesis is not loaded.
 // If `tx` is nil, use a normal query.
 func (db *IndexerDb) getMaxRoundAccounted(ctx context.Context, tx pgx.Tx) (uint64, error) {
@@ -384,14 +858,34 @@ func (db *IndexerDb) getMaxRoundAccounted(ctx context.Context, tx pgx.Tx) (uint6
 	return round, nil
 }
 
+// beginRead begins the read-only transaction of an API query. The application_name
+// of the transaction is "indexer <request id>" when `ctx` has the ID of a request,
+// so that its statements can be found in pg_stat_activity and the server logs.
+func (db *IndexerDb) beginRead(ctx context.Context) (pgx.Tx, error) {
+	tx, err := db.readDB().BeginTx(ctx, readonlyRepeatableRead)
+	if err != nil {
+		return nil, err
+	}
+	// The application_name is only set on postgres, not on CockroachDB.
+	if id := idb.RequestIDFromContext(ctx); id != "" && !db.cockroachCompat {
+		_, err = tx.Exec(ctx, setApplicationNameQuery, "indexer "+id)
+		if err != nil {
+			tx.Rollback(ctx)
+			return nil, fmt.Errorf("beginRead() err: %w", err)
+		}
+	}
+	return tx, nil
+}
+
 // GetBlock is part of idb.IndexerDB
 func (db *IndexerDb) GetBlock(ctx context.Context, round uint64, options idb.GetBlockOptions) (blockHeader bookkeeping.BlockHeader, transactions []idb.TxnRow, err error) {
-	tx, err := db.db.BeginTx(ctx, readonlyRepeatableRead)
+	tx, err := db.beginRead(ctx)
 	if err != nil {
 		return
 	}
 	defer tx.Rollback(ctx)
-	row := tx.QueryRow(ctx, `SELECT header FROM block_header WHERE round = $1`, round)
+	row := queryRowObserved(
+		ctx, tx, blockHeaderQueryName, `SELECT header FROM block_header WHERE round = $1`, round)
 	var blockheaderjson []byte
 	err = row.Scan(&blockheaderjson)
 	if err != nil {
@@ -411,7 +905,7 @@ func (db *IndexerDb) GetBlock(ctx context.Context, round uint64, options idb.Get
 			close(out)
 			return bookkeeping.BlockHeader{}, nil, err
 		}
-		rows, err := tx.Query(ctx, query, whereArgs...)
+		rows, err := queryObserved(ctx, tx, blockTransactionsQueryName, query, whereArgs...)
 		if err != nil {
 			err = fmt.Errorf("txn query %#v err %v", query, err)
 			return bookkeeping.BlockHeader{}, nil, err
@@ -433,6 +927,74 @@ func (db *IndexerDb) GetBlock(ctx context.Context, round uint64, options idb.Get
 	return blockHeader, transactions, nil
 }
 
+// GetBlockWithTransactions is part of idb.IndexerDB. The header is joined with
+// the transactions, only the first row carries it.
+func (db *IndexerDb) GetBlockWithTransactions(ctx context.Context, round uint64) (bookkeeping.BlockHeader, []idb.TxnRow, error) {
+	query := fmt.Sprintf(
+		"SELECT CASE WHEN row_number() OVER (ORDER BY t.intra) = 1 THEN h.header END, "+
+			"h.realtime, t.intra, "+
+			"CASE WHEN "+txnBytesLength+" <= %d THEN t.txnbytes END, "+
+			"CASE WHEN "+txnBytesLength+" > %d THEN t.txn - 'dt' END, "+
+			"t.extra, t.asset FROM block_header h LEFT JOIN txn t ON t.round = h.round "+
+			"WHERE h.round = $1 ORDER BY t.intra",
+		maxTxnBytes, maxTxnBytes)
+	rows, err := queryObserved(ctx, db.readDB(), blockQueryName, query, round)
+	if err != nil {
+		return bookkeeping.BlockHeader{}, nil, fmt.Errorf("GetBlockWithTransactions() err: %w", err)
+	}
+	defer rows.Close()
+
+	var blockHeader bookkeeping.BlockHeader
+	found := false
+	transactions := make([]idb.TxnRow, 0)
+	for rows.Next() {
+		var headerJSON []byte
+		var roundtime time.Time
+		var intra *int
+		var txnbytes []byte
+		var trimmedTxnJSON []byte
+		var extraJSON []byte
+		var asset *uint64
+		err = rows.Scan(
+			&headerJSON, &roundtime, &intra, &txnbytes, &trimmedTxnJSON, &extraJSON, &asset)
+		if err != nil {
+			return bookkeeping.BlockHeader{}, nil, fmt.Errorf("GetBlockWithTransactions() err: %w", err)
+		}
+		if !found {
+			found = true
+			blockHeader, err = encoding.DecodeBlockHeader(headerJSON)
+			if err != nil {
+				return bookkeeping.BlockHeader{}, nil, fmt.Errorf("GetBlockWithTransactions() err: %w", err)
+			}
+		}
+		// A block without transactions is a single row without a txn.
+		if intra == nil {
+			continue
+		}
+
+		row := idb.TxnRow{
+			Round:     round,
+			Intra:     *intra,
+			RoundTime: roundtime,
+			AssetID:   *asset,
+		}
+		err = decodeTxnRow(&row, txnbytes, trimmedTxnJSON, extraJSON)
+		if err != nil {
+			return bookkeeping.BlockHeader{}, nil, fmt.Errorf("GetBlockWithTransactions() err: %w", err)
+		}
+		transactions = append(transactions, row)
+	}
+	err = rows.Err()
+	if err != nil {
+		return bookkeeping.BlockHeader{}, nil, fmt.Errorf("GetBlockWithTransactions() err: %w", err)
+	}
+	if !found {
+		return bookkeeping.BlockHeader{}, nil, fmt.Errorf("GetBlockWithTransactions() err: %w", pgx.ErrNoRows)
+	}
+
+	return blockHeader, transactions, nil
+}
+
 func buildTransactionQuery(tf idb.TransactionFilter) (query string, whereArgs []interface{}, err error) {
 	// TODO? There are some combinations of tf params that will
 	// yield no results and we could catch that before asking the
@@ -600,7 +1162,14 @@ func buildTransactionQuery(tf idb.TransactionFilter) (query string, whereArgs []
 	if tf.RekeyTo != nil && (*tf.RekeyTo) {
 		whereParts = append(whereParts, "(t.txn -> 'txn' -> 'rekey') IS NOT NULL")
 	}
-	query = "SELECT t.round, t.intra, t.txnbytes, t.extra, t.asset, h.realtime FROM txn t JOIN block_header h ON t.round = h.round"
+	// Transactions larger than maxTxnBytes are returned as json without the eval
+	// delta instead of their full encoding, see yieldTxnsThreadSimple().
+	query = fmt.Sprintf(
+		"SELECT t.round, t.intra, "+
+			"CASE WHEN "+txnBytesLength+" <= %d THEN t.txnbytes END, "+
+			"CASE WHEN "+txnBytesLength+" > %d THEN t.txn - 'dt' END, "+
+			"t.extra, t.asset, h.realtime FROM txn t JOIN block_header h ON t.round = h.round",
+		maxTxnBytes, maxTxnBytes)
 	if joinParticipation {
 		query += " JOIN txn_participation p ON t.round = p.round AND t.intra = p.intra"
 	}
@@ -631,14 +1200,14 @@ func (db *IndexerDb) yieldTxns(ctx context.Context, tx pgx.Tx, tf idb.Transactio
 	query, whereArgs, err := buildTransactionQuery(tf)
 	if err != nil {
 		err = fmt.Errorf("txn query err %v", err)
-		out <- idb.TxnRow{Error: err}
+		idb.SendTxnRow(ctx, out, idb.TxnRow{Error: err})
 		return
 	}
 
-	rows, err := tx.Query(ctx, query, whereArgs...)
+	rows, err := queryObserved(ctx, tx, transactionsQueryName, query, whereArgs...)
 	if err != nil {
 		err = fmt.Errorf("txn query %#v err %v", query, err)
-		out <- idb.TxnRow{Error: err}
+		idb.SendTxnRow(ctx, out, idb.TxnRow{Error: err})
 		return
 	}
 
@@ -649,7 +1218,7 @@ func (db *IndexerDb) yieldTxns(ctx context.Context, tx pgx.Tx, tf idb.Transactio
 func (db *IndexerDb) Transactions(ctx context.Context, tf idb.TransactionFilter) (<-chan idb.TxnRow, uint64) {
 	out := make(chan idb.TxnRow, 1)
 
-	tx, err := db.db.BeginTx(ctx, readonlyRepeatableRead)
+	tx, err := db.beginRead(ctx)
 	if err != nil {
 		out <- idb.TxnRow{Error: err}
 		close(out)
@@ -666,7 +1235,9 @@ func (db *IndexerDb) Transactions(ctx context.Context, tf idb.TransactionFilter)
 
 	go func() {
 		db.yieldTxns(ctx, tx, tf, out)
-		tx.Rollback(ctx)
+		// Not `ctx`, pgx closes the connection when a rollback fails, e.g. because
+		// the request was abandoned, instead of returning it to the pool.
+		tx.Rollback(context.Background())
 		close(out)
 	}()
 
@@ -678,7 +1249,7 @@ func (db *IndexerDb) txnsWithNext(ctx context.Context, tx pgx.Tx, tf idb.Transac
 	nextround, nextintra32, err := idb.DecodeTxnRowNext(tf.NextToken)
 	nextintra := uint64(nextintra32)
 	if err != nil {
-		out <- idb.TxnRow{Error: err}
+		idb.SendTxnRow(ctx, out, idb.TxnRow{Error: err})
 		return
 	}
 	origRound := tf.Round
@@ -699,13 +1270,13 @@ func (db *IndexerDb) txnsWithNext(ctx context.Context, tx pgx.Tx, tf idb.Transac
 	query, whereArgs, err := buildTransactionQuery(tf)
 	if err != nil {
 		err = fmt.Errorf("txn query err %v", err)
-		out <- idb.TxnRow{Error: err}
+		idb.SendTxnRow(ctx, out, idb.TxnRow{Error: err})
 		return
 	}
-	rows, err := tx.Query(ctx, query, whereArgs...)
+	rows, err := queryObserved(ctx, tx, transactionsQueryName, query, whereArgs...)
 	if err != nil {
 		err = fmt.Errorf("txn query %#v err %v", query, err)
-		out <- idb.TxnRow{Error: err}
+		idb.SendTxnRow(ctx, out, idb.TxnRow{Error: err})
 		return
 	}
 	count := int(0)
@@ -739,13 +1310,13 @@ func (db *IndexerDb) txnsWithNext(ctx context.Context, tx pgx.Tx, tf idb.Transac
 	query, whereArgs, err = buildTransactionQuery(tf)
 	if err != nil {
 		err = fmt.Errorf("txn query err %v", err)
-		out <- idb.TxnRow{Error: err}
+		idb.SendTxnRow(ctx, out, idb.TxnRow{Error: err})
 		return
 	}
-	rows, err = tx.Query(ctx, query, whereArgs...)
+	rows, err = queryObserved(ctx, tx, transactionsQueryName, query, whereArgs...)
 	if err != nil {
 		err = fmt.Errorf("txn query %#v err %v", query, err)
-		out <- idb.TxnRow{Error: err}
+		idb.SendTxnRow(ctx, out, idb.TxnRow{Error: err})
 		return
 	}
 	db.yieldTxnsThreadSimple(ctx, rows, out, nil, nil)
@@ -760,40 +1331,36 @@ func (db *IndexerDb) yieldTxnsThreadSimple(ctx context.Context, rows pgx.Rows, r
 		var asset uint64
 		var intra int
 		var txnbytes []byte
+		var trimmedTxnJSON []byte
 		var extraJSON []byte
 		var roundtime time.Time
-		err := rows.Scan(&round, &intra, &txnbytes, &extraJSON, &asset, &roundtime)
+		err := rows.Scan(&round, &intra, &txnbytes, &trimmedTxnJSON, &extraJSON, &asset, &roundtime)
 		var row idb.TxnRow
 		if err != nil {
 			row.Error = err
 		} else {
 			row.Round = round
 			row.Intra = intra
-			row.TxnBytes = txnbytes
 			row.RoundTime = roundtime
 			row.AssetID = asset
-			if len(extraJSON) > 0 {
-				err = encoding.DecodeJSON(extraJSON, &row.Extra)
-				if err != nil {
-					row.Error = fmt.Errorf("%d:%d decode txn extra, %v", row.Round, row.Intra, err)
-				}
+			err = decodeTxnRow(&row, txnbytes, trimmedTxnJSON, extraJSON)
+			if err != nil {
+				row.Error = err
 			}
 		}
-		select {
-		case <-ctx.Done():
+		if !idb.SendTxnRow(ctx, results, row) {
 			goto finish
-		case results <- row:
-			if err != nil {
-				if errp != nil {
-					*errp = err
-				}
-				goto finish
+		}
+		if err != nil {
+			if errp != nil {
+				*errp = err
 			}
-			count++
+			goto finish
 		}
+		count++
 	}
 	if err := rows.Err(); err != nil {
-		results <- idb.TxnRow{Error: err}
+		idb.SendTxnRow(ctx, results, idb.TxnRow{Error: err})
 		if errp != nil {
 			*errp = err
 		}
@@ -804,6 +1371,51 @@ finish:
 	}
 }
 
+// decodeTxnRow sets the transaction of `row` from the txnbytes column, or the
+// trimmed json when the transaction is too large, and decodes the extra column.
+func decodeTxnRow(row *idb.TxnRow, txnbytes []byte, trimmedTxnJSON []byte, extraJSON []byte) error {
+	var err error
+	if txnbytes != nil {
+		row.TxnBytes, err = encoding.DecompressTxnBytes(txnbytes)
+		if err != nil {
+			return fmt.Errorf("%d:%d decompress txn, %v", row.Round, row.Intra, err)
+		}
+	} else if trimmedTxnJSON != nil {
+		row.TxnBytes, err = reencodeTrimmedTxn(trimmedTxnJSON)
+		if err != nil {
+			return fmt.Errorf("%d:%d decode trimmed txn, %v", row.Round, row.Intra, err)
+		}
+		row.Truncated = true
+	}
+	if len(extraJSON) > 0 {
+		err = encoding.DecodeJSON(extraJSON, &row.Extra)
+		if err != nil {
+			return fmt.Errorf("%d:%d decode txn extra, %v", row.Round, row.Intra, err)
+		}
+	}
+	return nil
+}
+
+// maxTxnBytes is the largest encoded transaction returned by transaction searches.
+// Some application calls have multi-megabyte state deltas, loading them for every
+// row of a page would let a single query use a lot of memory. It is a variable so
+// that tests can lower it.
+var maxTxnBytes = 1024 * 1024
+
+// txnBytesLength is the length of the encoded transaction before compression,
+// it is compared with maxTxnBytes.
+const txnBytesLength = "COALESCE(t.txnbytes_len, octet_length(t.txnbytes))"
+
+// reencodeTrimmedTxn converts the json of a transaction without its eval delta to
+// the msgpack encoding used by idb.TxnRow.
+func reencodeTrimmedTxn(txnJSON []byte) ([]byte, error) {
+	stxn, err := encoding.DecodeSignedTxnWithAD(txnJSON)
+	if err != nil {
+		return nil, err
+	}
+	return protocol.Encode(&stxn), nil
+}
+
 var statusStrings = []string{"Offline", "Online", "NotParticipating"}
 
 const offlineStatusIdx = 0
@@ -840,15 +1452,7 @@ func tealKeyValueToModel(tkv basics.TealKeyValue) *models.TealKeyValueStore {
 
 func (db *IndexerDb) yieldAccountsThread(req *getAccountsRequest) {
 	count := uint64(0)
-	defer func() {
-		req.rows.Close()
-
-		end := time.Now()
-		dt := end.Sub(req.start)
-		if dt > (1 * time.Second) {
-			db.log.Warnf("long query %fs: %s", dt.Seconds(), req.query)
-		}
-	}()
+	defer req.rows.Close()
 	for req.rows.Next() {
 		var addr []byte
 		var microalgos uint64
@@ -859,6 +1463,11 @@ func (db *IndexerDb) yieldAccountsThread(req *getAccountsRequest) {
 		var rewardsbase uint64
 		var keytype *string
 		var accountDataJSONStr []byte
+		// The resource counts are maintained by the writer.
+		var totalAssetsOptedIn uint64
+		var totalCreatedAssets uint64
+		var totalAppsOptedIn uint64
+		var totalCreatedApps uint64
 
 		// below are bytes of json serialization
 
@@ -896,6 +1505,7 @@ func (db *IndexerDb) yieldAccountsThread(req *getAccountsRequest) {
 		if req.opts.IncludeAssetHoldings && req.opts.IncludeAssetParams {
 			err = req.rows.Scan(
 				&addr, &microalgos, &rewardstotal, &createdat, &closedat, &deleted, &rewardsbase, &keytype, &accountDataJSONStr,
+				&totalAssetsOptedIn, &totalCreatedAssets, &totalAppsOptedIn, &totalCreatedApps,
 				&holdingAssetids, &holdingAmount, &holdingFrozen, &holdingCreatedBytes, &holdingClosedBytes, &holdingDeletedBytes,
 				&assetParamsIds, &assetParamsStr, &assetParamsCreatedBytes, &assetParamsClosedBytes, &assetParamsDeletedBytes,
 				&appParamIndexes, &appParams, &appCreatedBytes, &appClosedBytes, &appDeletedBytes, &localStateAppIds, &localStates,
@@ -904,6 +1514,7 @@ func (db *IndexerDb) yieldAccountsThread(req *getAccountsRequest) {
 		} else if req.opts.IncludeAssetHoldings {
 			err = req.rows.Scan(
 				&addr, &microalgos, &rewardstotal, &createdat, &closedat, &deleted, &rewardsbase, &keytype, &accountDataJSONStr,
+				&totalAssetsOptedIn, &totalCreatedAssets, &totalAppsOptedIn, &totalCreatedApps,
 				&holdingAssetids, &holdingAmount, &holdingFrozen, &holdingCreatedBytes, &holdingClosedBytes, &holdingDeletedBytes,
 				&appParamIndexes, &appParams, &appCreatedBytes, &appClosedBytes, &appDeletedBytes, &localStateAppIds, &localStates,
 				&localStateCreatedBytes, &localStateClosedBytes, &localStateDeletedBytes,
@@ -911,6 +1522,7 @@ func (db *IndexerDb) yieldAccountsThread(req *getAccountsRequest) {
 		} else if req.opts.IncludeAssetParams {
 			err = req.rows.Scan(
 				&addr, &microalgos, &rewardstotal, &createdat, &closedat, &deleted, &rewardsbase, &keytype, &accountDataJSONStr,
+				&totalAssetsOptedIn, &totalCreatedAssets, &totalAppsOptedIn, &totalCreatedApps,
 				&assetParamsIds, &assetParamsStr, &assetParamsCreatedBytes, &assetParamsClosedBytes, &assetParamsDeletedBytes,
 				&appParamIndexes, &appParams, &appCreatedBytes, &appClosedBytes, &appDeletedBytes, &localStateAppIds, &localStates,
 				&localStateCreatedBytes, &localStateClosedBytes, &localStateDeletedBytes,
@@ -918,13 +1530,14 @@ func (db *IndexerDb) yieldAccountsThread(req *getAccountsRequest) {
 		} else {
 			err = req.rows.Scan(
 				&addr, &microalgos, &rewardstotal, &createdat, &closedat, &deleted, &rewardsbase, &keytype, &accountDataJSONStr,
+				&totalAssetsOptedIn, &totalCreatedAssets, &totalAppsOptedIn, &totalCreatedApps,
 				&appParamIndexes, &appParams, &appCreatedBytes, &appClosedBytes, &appDeletedBytes, &localStateAppIds, &localStates,
 				&localStateCreatedBytes, &localStateClosedBytes, &localStateDeletedBytes,
 			)
 		}
 		if err != nil {
 			err = fmt.Errorf("account scan err %v", err)
-			req.out <- idb.AccountRow{Error: err}
+			idb.SendAccountRow(req.ctx, req.out, idb.AccountRow{Error: err})
 			break
 		}
 
@@ -940,6 +1553,10 @@ func (db *IndexerDb) yieldAccountsThread(req *getAccountsRequest) {
 		account.Deleted = nullableBoolPtr(deleted)
 		account.RewardBase = new(uint64)
 		*account.RewardBase = rewardsbase
+		account.TotalAssetsOptedIn = uint64Ptr(totalAssetsOptedIn)
+		account.TotalCreatedAssets = uint64Ptr(totalCreatedAssets)
+		account.TotalAppsOptedIn = uint64Ptr(totalAppsOptedIn)
+		account.TotalCreatedApps = uint64Ptr(totalCreatedApps)
 		// default to Offline in there have been no keyreg transactions.
 		account.Status = statusStrings[offlineStatusIdx]
 		if keytype != nil && *keytype != "" {
@@ -951,7 +1568,7 @@ func (db *IndexerDb) yieldAccountsThread(req *getAccountsRequest) {
 			ad, err = encoding.DecodeTrimmedAccountData(accountDataJSONStr)
 			if err != nil {
 				err = fmt.Errorf("account decode err (%s) %v", accountDataJSONStr, err)
-				req.out <- idb.AccountRow{Error: err}
+				idb.SendAccountRow(req.ctx, req.out, idb.AccountRow{Error: err})
 				break
 			}
 			account.Status = statusStrings[ad.Status]
@@ -985,7 +1602,7 @@ func (db *IndexerDb) yieldAccountsThread(req *getAccountsRequest) {
 			proto, ok := config.Consensus[req.blockheader.CurrentProtocol]
 			if !ok {
 				err = fmt.Errorf("get protocol err (%s)", req.blockheader.CurrentProtocol)
-				req.out <- idb.AccountRow{Error: err}
+				idb.SendAccountRow(req.ctx, req.out, idb.AccountRow{Error: err})
 				break
 			}
 			rewardsUnits := uint64(0)
@@ -1005,49 +1622,49 @@ func (db *IndexerDb) yieldAccountsThread(req *getAccountsRequest) {
 			err = encoding.DecodeJSON(holdingAssetids, &haids)
 			if err != nil {
 				err = fmt.Errorf("parsing json holding asset ids err %v", err)
-				req.out <- idb.AccountRow{Error: err}
+				idb.SendAccountRow(req.ctx, req.out, idb.AccountRow{Error: err})
 				break
 			}
 			var hamounts []uint64
 			err = encoding.DecodeJSON(holdingAmount, &hamounts)
 			if err != nil {
 				err = fmt.Errorf("parsing json holding amounts err %v", err)
-				req.out <- idb.AccountRow{Error: err}
+				idb.SendAccountRow(req.ctx, req.out, idb.AccountRow{Error: err})
 				break
 			}
 			var hfrozen []bool
 			err = encoding.DecodeJSON(holdingFrozen, &hfrozen)
 			if err != nil {
 				err = fmt.Errorf("parsing json holding frozen err %v", err)
-				req.out <- idb.AccountRow{Error: err}
+				idb.SendAccountRow(req.ctx, req.out, idb.AccountRow{Error: err})
 				break
 			}
 			var holdingCreated []*uint64
 			err = encoding.DecodeJSON(holdingCreatedBytes, &holdingCreated)
 			if err != nil {
 				err = fmt.Errorf("parsing json holding created ids, %v", err)
-				req.out <- idb.AccountRow{Error: err}
+				idb.SendAccountRow(req.ctx, req.out, idb.AccountRow{Error: err})
 				break
 			}
 			var holdingClosed []*uint64
 			err = encoding.DecodeJSON(holdingClosedBytes, &holdingClosed)
 			if err != nil {
 				err = fmt.Errorf("parsing json holding closed ids, %v", err)
-				req.out <- idb.AccountRow{Error: err}
+				idb.SendAccountRow(req.ctx, req.out, idb.AccountRow{Error: err})
 				break
 			}
 			var holdingDeleted []*bool
 			err = encoding.DecodeJSON(holdingDeletedBytes, &holdingDeleted)
 			if err != nil {
 				err = fmt.Errorf("parsing json holding deleted ids, %v", err)
-				req.out <- idb.AccountRow{Error: err}
+				idb.SendAccountRow(req.ctx, req.out, idb.AccountRow{Error: err})
 				break
 			}
 
 			if len(hamounts) != len(haids) || len(hfrozen) != len(haids) || len(holdingCreated) != len(haids) || len(holdingClosed) != len(haids) || len(holdingDeleted) != len(haids) {
 				err = fmt.Errorf("account asset holding unpacking, all should be %d:  %d amounts, %d frozen, %d created, %d closed, %d deleted",
 					len(haids), len(hamounts), len(hfrozen), len(holdingCreated), len(holdingClosed), len(holdingDeleted))
-				req.out <- idb.AccountRow{Error: err}
+				idb.SendAccountRow(req.ctx, req.out, idb.AccountRow{Error: err})
 				break
 			}
 
@@ -1082,41 +1699,41 @@ func (db *IndexerDb) yieldAccountsThread(req *getAccountsRequest) {
 			err = encoding.DecodeJSON(assetParamsIds, &assetids)
 			if err != nil {
 				err = fmt.Errorf("parsing json asset param ids, %v", err)
-				req.out <- idb.AccountRow{Error: err}
+				idb.SendAccountRow(req.ctx, req.out, idb.AccountRow{Error: err})
 				break
 			}
 			assetParams, err := encoding.DecodeAssetParamsArray(assetParamsStr)
 			if err != nil {
 				err = fmt.Errorf("parsing json asset param string, %v", err)
-				req.out <- idb.AccountRow{Error: err}
+				idb.SendAccountRow(req.ctx, req.out, idb.AccountRow{Error: err})
 				break
 			}
 			var assetCreated []*uint64
 			err = encoding.DecodeJSON(assetParamsCreatedBytes, &assetCreated)
 			if err != nil {
 				err = fmt.Errorf("parsing json asset created ids, %v", err)
-				req.out <- idb.AccountRow{Error: err}
+				idb.SendAccountRow(req.ctx, req.out, idb.AccountRow{Error: err})
 				break
 			}
 			var assetClosed []*uint64
 			err = encoding.DecodeJSON(assetParamsClosedBytes, &assetClosed)
 			if err != nil {
 				err = fmt.Errorf("parsing json asset closed ids, %v", err)
-				req.out <- idb.AccountRow{Error: err}
+				idb.SendAccountRow(req.ctx, req.out, idb.AccountRow{Error: err})
 				break
 			}
 			var assetDeleted []*bool
 			err = encoding.DecodeJSON(assetParamsDeletedBytes, &assetDeleted)
 			if err != nil {
 				err = fmt.Errorf("parsing json asset deleted ids, %v", err)
-				req.out <- idb.AccountRow{Error: err}
+				idb.SendAccountRow(req.ctx, req.out, idb.AccountRow{Error: err})
 				break
 			}
 
 			if len(assetParams) != len(assetids) || len(assetCreated) != len(assetids) || len(assetClosed) != len(assetids) || len(assetDeleted) != len(assetids) {
 				err = fmt.Errorf("account asset unpacking, all should be %d:  %d assetids, %d created, %d closed, %d deleted",
 					len(assetParams), len(assetids), len(assetCreated), len(assetClosed), len(assetDeleted))
-				req.out <- idb.AccountRow{Error: err}
+				idb.SendAccountRow(req.ctx, req.out, idb.AccountRow{Error: err})
 				break
 			}
 
@@ -1172,40 +1789,40 @@ func (db *IndexerDb) yieldAccountsThread(req *getAccountsRequest) {
 			err = encoding.DecodeJSON(appParamIndexes, &appIds)
 			if err != nil {
 				err = fmt.Errorf("parsing json appids, %v", err)
-				req.out <- idb.AccountRow{Error: err}
+				idb.SendAccountRow(req.ctx, req.out, idb.AccountRow{Error: err})
 				break
 			}
 			var appCreated []*uint64
 			err = encoding.DecodeJSON(appCreatedBytes, &appCreated)
 			if err != nil {
 				err = fmt.Errorf("parsing json app created ids, %v", err)
-				req.out <- idb.AccountRow{Error: err}
+				idb.SendAccountRow(req.ctx, req.out, idb.AccountRow{Error: err})
 				break
 			}
 			var appClosed []*uint64
 			err = encoding.DecodeJSON(appClosedBytes, &appClosed)
 			if err != nil {
 				err = fmt.Errorf("parsing json app closed ids, %v", err)
-				req.out <- idb.AccountRow{Error: err}
+				idb.SendAccountRow(req.ctx, req.out, idb.AccountRow{Error: err})
 				break
 			}
 			var appDeleted []*bool
 			err = encoding.DecodeJSON(appDeletedBytes, &appDeleted)
 			if err != nil {
 				err = fmt.Errorf("parsing json app deleted flags, %v", err)
-				req.out <- idb.AccountRow{Error: err}
+				idb.SendAccountRow(req.ctx, req.out, idb.AccountRow{Error: err})
 				break
 			}
 
 			apps, err := encoding.DecodeAppParamsArray(appParams)
 			if err != nil {
 				err = fmt.Errorf("parsing json appparams, %v", err)
-				req.out <- idb.AccountRow{Error: err}
+				idb.SendAccountRow(req.ctx, req.out, idb.AccountRow{Error: err})
 				break
 			}
 			if len(appIds) != len(apps) || len(appClosed) != len(apps) || len(appCreated) != len(apps) || len(appDeleted) != len(apps) {
 				err = fmt.Errorf("account app unpacking, all should be %d:  %d appids, %d appClosed, %d appCreated, %d appDeleted", len(apps), len(appIds), len(appClosed), len(appCreated), len(appDeleted))
-				req.out <- idb.AccountRow{Error: err}
+				idb.SendAccountRow(req.ctx, req.out, idb.AccountRow{Error: err})
 				break
 			}
 
@@ -1257,39 +1874,39 @@ func (db *IndexerDb) yieldAccountsThread(req *getAccountsRequest) {
 			err = encoding.DecodeJSON(localStateAppIds, &appIds)
 			if err != nil {
 				err = fmt.Errorf("parsing json local appids, %v", err)
-				req.out <- idb.AccountRow{Error: err}
+				idb.SendAccountRow(req.ctx, req.out, idb.AccountRow{Error: err})
 				break
 			}
 			var appCreated []*uint64
 			err = encoding.DecodeJSON(localStateCreatedBytes, &appCreated)
 			if err != nil {
 				err = fmt.Errorf("parsing json ls created ids, %v", err)
-				req.out <- idb.AccountRow{Error: err}
+				idb.SendAccountRow(req.ctx, req.out, idb.AccountRow{Error: err})
 				break
 			}
 			var appClosed []*uint64
 			err = encoding.DecodeJSON(localStateClosedBytes, &appClosed)
 			if err != nil {
 				err = fmt.Errorf("parsing json ls closed ids, %v", err)
-				req.out <- idb.AccountRow{Error: err}
+				idb.SendAccountRow(req.ctx, req.out, idb.AccountRow{Error: err})
 				break
 			}
 			var appDeleted []*bool
 			err = encoding.DecodeJSON(localStateDeletedBytes, &appDeleted)
 			if err != nil {
 				err = fmt.Errorf("parsing json ls closed ids, %v", err)
-				req.out <- idb.AccountRow{Error: err}
+				idb.SendAccountRow(req.ctx, req.out, idb.AccountRow{Error: err})
 				break
 			}
 			ls, err := encoding.DecodeAppLocalStateArray(localStates)
 			if err != nil {
 				err = fmt.Errorf("parsing json local states, %v", err)
-				req.out <- idb.AccountRow{Error: err}
+				idb.SendAccountRow(req.ctx, req.out, idb.AccountRow{Error: err})
 				break
 			}
 			if len(appIds) != len(ls) || len(appClosed) != len(ls) || len(appCreated) != len(ls) || len(appDeleted) != len(ls) {
 				err = fmt.Errorf("account app unpacking, all should be %d:  %d appids, %d appClosed, %d appCreated, %d appDeleted", len(ls), len(appIds), len(appClosed), len(appCreated), len(appDeleted))
-				req.out <- idb.AccountRow{Error: err}
+				idb.SendAccountRow(req.ctx, req.out, idb.AccountRow{Error: err})
 				break
 			}
 
@@ -1316,19 +1933,17 @@ func (db *IndexerDb) yieldAccountsThread(req *getAccountsRequest) {
 			account.AppsTotalSchema = &totalSchema
 		}
 
-		select {
-		case req.out <- idb.AccountRow{Account: account}:
-			count++
-			if req.opts.Limit != 0 && count >= req.opts.Limit {
-				return
-			}
-		case <-req.ctx.Done():
+		if !idb.SendAccountRow(req.ctx, req.out, idb.AccountRow{Account: account}) {
+			return
+		}
+		count++
+		if req.opts.Limit != 0 && count >= req.opts.Limit {
 			return
 		}
 	}
 	if err := req.rows.Err(); err != nil {
 		err = fmt.Errorf("error reading rows: %v", err)
-		req.out <- idb.AccountRow{Error: err}
+		idb.SendAccountRow(req.ctx, req.out, idb.AccountRow{Error: err})
 	}
 }
 
@@ -1416,10 +2031,8 @@ type getAccountsRequest struct {
 	ctx         context.Context
 	opts        idb.AccountQueryOptions
 	blockheader bookkeeping.BlockHeader
-	query       string
 	rows        pgx.Rows
 	out         chan idb.AccountRow
-	start       time.Time
 }
 
 // GetAccounts is part of idb.IndexerDB
@@ -1436,7 +2049,7 @@ func (db *IndexerDb) GetAccounts(ctx context.Context, opts idb.AccountQueryOptio
 	}
 
 	// Begin transaction so we get everything at one consistent point in time and round of accounting.
-	tx, err := db.db.BeginTx(ctx, readonlyRepeatableRead)
+	tx, err := db.beginRead(ctx)
 	if err != nil {
 		err = fmt.Errorf("account tx err %v", err)
 		out <- idb.AccountRow{Error: err}
@@ -1455,7 +2068,8 @@ func (db *IndexerDb) GetAccounts(ctx context.Context, opts idb.AccountQueryOptio
 	}
 
 	// Get block header for that round so we know protocol and rewards info
-	row := tx.QueryRow(ctx, `SELECT header FROM block_header WHERE round = $1`, round)
+	row := queryRowObserved(
+		ctx, tx, blockHeaderQueryName, `SELECT header FROM block_header WHERE round = $1`, round)
 	var headerjson []byte
 	err = row.Scan(&headerjson)
 	if err != nil {
@@ -1480,11 +2094,9 @@ func (db *IndexerDb) GetAccounts(ctx context.Context, opts idb.AccountQueryOptio
 		ctx:         ctx,
 		opts:        opts,
 		blockheader: blockheader,
-		query:       query,
 		out:         out,
-		start:       time.Now(),
 	}
-	req.rows, err = tx.Query(ctx, query, whereArgs...)
+	req.rows, err = queryObserved(ctx, tx, accountsQueryName, query, whereArgs...)
 	if err != nil {
 		err = fmt.Errorf("account query %#v err %v", query, err)
 		out <- idb.AccountRow{Error: err}
@@ -1495,7 +2107,7 @@ func (db *IndexerDb) GetAccounts(ctx context.Context, opts idb.AccountQueryOptio
 	go func() {
 		db.yieldAccountsThread(req)
 		close(req.out)
-		tx.Rollback(ctx)
+		tx.Rollback(context.Background())
 	}()
 	return out, round
 }
@@ -1506,33 +2118,43 @@ func (db *IndexerDb) buildAccountQuery(opts idb.AccountQueryOptions) (query stri
 	whereParts := make([]string, 0, maxWhereParts)
 	whereArgs = make([]interface{}, 0, maxWhereParts)
 	partNumber := 1
-	withClauses := make([]string, 0, maxWhereParts)
-	// filter by has-asset or has-app
+	// Pages are selected with a.addr > next-token, the accounts are read in
+	// address order from that address on and only until the page is full. The
+	// has-asset and has-app filters join the holdings in the same order, the
+	// condition on the address is repeated on them so that deep pages start
+	// reading them at the address as well instead of reading all of them.
+	joins := make([]string, 0, 2)
 	if opts.HasAssetID != 0 {
-		aq := fmt.Sprintf("SELECT addr FROM account_asset WHERE assetid = $%d", partNumber)
+		joins = append(joins, "JOIN account_asset qasf ON a.addr = qasf.addr")
+		whereParts = append(whereParts, fmt.Sprintf("qasf.assetid = $%d", partNumber))
 		whereArgs = append(whereArgs, opts.HasAssetID)
 		partNumber++
 		if opts.AssetGT != nil {
-			aq += fmt.Sprintf(" AND amount > $%d", partNumber)
+			whereParts = append(whereParts, fmt.Sprintf("qasf.amount > $%d", partNumber))
 			whereArgs = append(whereArgs, *opts.AssetGT)
 			partNumber++
 		}
 		if opts.AssetLT != nil {
-			aq += fmt.Sprintf(" AND amount < $%d", partNumber)
+			whereParts = append(whereParts, fmt.Sprintf("qasf.amount < $%d", partNumber))
 			whereArgs = append(whereArgs, *opts.AssetLT)
 			partNumber++
 		}
-		aq = "qasf AS (" + aq + ")"
-		withClauses = append(withClauses, aq)
 	}
 	if opts.HasAppID != 0 {
-		withClauses = append(withClauses, fmt.Sprintf("qapf AS (SELECT addr FROM account_app WHERE app = $%d)", partNumber))
+		joins = append(joins, "JOIN account_app qapf ON a.addr = qapf.addr")
+		whereParts = append(whereParts, fmt.Sprintf("qapf.app = $%d", partNumber))
 		whereArgs = append(whereArgs, opts.HasAppID)
 		partNumber++
 	}
 	// filters against main account table
 	if len(opts.GreaterThanAddress) > 0 {
 		whereParts = append(whereParts, fmt.Sprintf("a.addr > $%d", partNumber))
+		if opts.HasAssetID != 0 {
+			whereParts = append(whereParts, fmt.Sprintf("qasf.addr > $%d", partNumber))
+		}
+		if opts.HasAppID != 0 {
+			whereParts = append(whereParts, fmt.Sprintf("qapf.addr > $%d", partNumber))
+		}
 		whereArgs = append(whereArgs, opts.GreaterThanAddress)
 		partNumber++
 	}
@@ -1559,14 +2181,10 @@ func (db *IndexerDb) buildAccountQuery(opts idb.AccountQueryOptions) (query stri
 		whereArgs = append(whereArgs, encoding.Base64(opts.EqualToAuthAddr))
 		partNumber++
 	}
-	query = `SELECT a.addr, a.microalgos, a.rewards_total, a.created_at, a.closed_at, a.deleted, a.rewardsbase, a.keytype, a.account_data FROM account a`
-	if opts.HasAssetID != 0 {
-		// inner join requires match, filtering on presence of asset
-		query += " JOIN qasf ON a.addr = qasf.addr"
-	}
-	if opts.HasAppID != 0 {
-		// inner join requires match, filtering on presence of app
-		query += " JOIN qapf ON a.addr = qapf.addr"
+	query = `SELECT a.addr, a.microalgos, a.rewards_total, a.created_at, a.closed_at, a.deleted, a.rewardsbase, a.keytype, a.account_data, a.total_assets_opted_in, a.total_created_assets, a.total_apps_opted_in, a.total_created_apps FROM account a`
+	for _, join := range joins {
+		// inner join requires match, filtering on presence of asset or app
+		query += " " + join
 	}
 	if len(whereParts) > 0 {
 		whereStr := strings.Join(whereParts, " AND ")
@@ -1577,8 +2195,7 @@ func (db *IndexerDb) buildAccountQuery(opts idb.AccountQueryOptions) (query stri
 		query += fmt.Sprintf(" LIMIT %d", opts.Limit)
 	}
 	// TODO: asset holdings and asset params are optional, but practically always used. Either make them actually always on, or make app-global and app-local clauses also optional (they are currently always on).
-	withClauses = append(withClauses, "qaccounts AS ("+query+")")
-	query = "WITH " + strings.Join(withClauses, ", ")
+	query = "WITH qaccounts AS (" + query + ")"
 	if opts.IncludeDeleted {
 		if opts.IncludeAssetHoldings {
 			query += `, qaa AS (SELECT xa.addr, json_agg(aa.assetid) as haid, json_agg(aa.amount) as hamt, json_agg(aa.frozen) as hf, json_agg(aa.created_at) as holding_created_at, json_agg(aa.closed_at) as holding_closed_at, json_agg(coalesce(aa.deleted, false)) as holding_deleted FROM account_asset aa JOIN qaccounts xa ON aa.addr = xa.addr GROUP BY 1)`
@@ -1604,7 +2221,7 @@ func (db *IndexerDb) buildAccountQuery(opts idb.AccountQueryOptions) (query stri
 	}
 
 	// query results
-	query += ` SELECT za.addr, za.microalgos, za.rewards_total, za.created_at, za.closed_at, za.deleted, za.rewardsbase, za.keytype, za.account_data`
+	query += ` SELECT za.addr, za.microalgos, za.rewards_total, za.created_at, za.closed_at, za.deleted, za.rewardsbase, za.keytype, za.account_data, za.total_assets_opted_in, za.total_created_assets, za.total_apps_opted_in, za.total_created_apps`
 	if opts.IncludeAssetHoldings {
 		query += `, qaa.haid, qaa.hamt, qaa.hf, qaa.holding_created_at, qaa.holding_closed_at, qaa.holding_deleted`
 	}
@@ -1676,7 +2293,7 @@ func (db *IndexerDb) Assets(ctx context.Context, filter idb.AssetsQuery) (<-chan
 
 	out := make(chan idb.AssetRow, 1)
 
-	tx, err := db.db.BeginTx(ctx, readonlyRepeatableRead)
+	tx, err := db.beginRead(ctx)
 	if err != nil {
 		out <- idb.AssetRow{Error: err}
 		close(out)
@@ -1691,7 +2308,7 @@ func (db *IndexerDb) Assets(ctx context.Context, filter idb.AssetsQuery) (<-chan
 		return out, round
 	}
 
-	rows, err := tx.Query(ctx, query, whereArgs...)
+	rows, err := queryObserved(ctx, tx, assetsQueryName, query, whereArgs...)
 	if err != nil {
 		err = fmt.Errorf("asset query %#v err %v", query, err)
 		out <- idb.AssetRow{Error: err}
@@ -1702,7 +2319,7 @@ func (db *IndexerDb) Assets(ctx context.Context, filter idb.AssetsQuery) (<-chan
 	go func() {
 		db.yieldAssetsThread(ctx, filter, rows, out)
 		close(out)
-		tx.Rollback(ctx)
+		tx.Rollback(context.Background())
 	}()
 	return out, round
 }
@@ -1721,12 +2338,12 @@ func (db *IndexerDb) yieldAssetsThread(ctx context.Context, filter idb.AssetsQue
 
 		err = rows.Scan(&index, &creatorAddr, &paramsJSONStr, &created, &closed, &deleted)
 		if err != nil {
-			out <- idb.AssetRow{Error: err}
+			idb.SendAssetRow(ctx, out, idb.AssetRow{Error: err})
 			break
 		}
 		params, err := encoding.DecodeAssetParams(paramsJSONStr)
 		if err != nil {
-			out <- idb.AssetRow{Error: err}
+			idb.SendAssetRow(ctx, out, idb.AssetRow{Error: err})
 			break
 		}
 		var creator basics.Address
@@ -1739,14 +2356,12 @@ func (db *IndexerDb) yieldAssetsThread(ctx context.Context, filter idb.AssetsQue
 			ClosedRound:  closed,
 			Deleted:      deleted,
 		}
-		select {
-		case <-ctx.Done():
+		if !idb.SendAssetRow(ctx, out, rec) {
 			return
-		case out <- rec:
 		}
 	}
 	if err := rows.Err(); err != nil {
-		out <- idb.AssetRow{Error: err}
+		idb.SendAssetRow(ctx, out, idb.AssetRow{Error: err})
 	}
 }
 
@@ -1790,7 +2405,7 @@ func (db *IndexerDb) AssetBalances(ctx context.Context, abq idb.AssetBalanceQuer
 
 	out := make(chan idb.AssetBalanceRow, 1)
 
-	tx, err := db.db.BeginTx(ctx, readonlyRepeatableRead)
+	tx, err := db.beginRead(ctx)
 	if err != nil {
 		out <- idb.AssetBalanceRow{Error: err}
 		close(out)
@@ -1805,7 +2420,7 @@ func (db *IndexerDb) AssetBalances(ctx context.Context, abq idb.AssetBalanceQuer
 		return out, round
 	}
 
-	rows, err := tx.Query(ctx, query, whereArgs...)
+	rows, err := queryObserved(ctx, tx, assetBalancesQueryName, query, whereArgs...)
 	if err != nil {
 		out <- idb.AssetBalanceRow{Error: err}
 		close(out)
@@ -1815,7 +2430,7 @@ func (db *IndexerDb) AssetBalances(ctx context.Context, abq idb.AssetBalanceQuer
 	go func() {
 		db.yieldAssetBalanceThread(ctx, rows, out)
 		close(out)
-		tx.Rollback(ctx)
+		tx.Rollback(context.Background())
 	}()
 	return out, round
 }
@@ -1833,7 +2448,7 @@ func (db *IndexerDb) yieldAssetBalanceThread(ctx context.Context, rows pgx.Rows,
 		var deleted *bool
 		err := rows.Scan(&addr, &assetID, &amount, &frozen, &created, &closed, &deleted)
 		if err != nil {
-			out <- idb.AssetBalanceRow{Error: err}
+			idb.SendAssetBalanceRow(ctx, out, idb.AssetBalanceRow{Error: err})
 			break
 		}
 		rec := idb.AssetBalanceRow{
@@ -1845,14 +2460,12 @@ func (db *IndexerDb) yieldAssetBalanceThread(ctx context.Context, rows pgx.Rows,
 			CreatedRound: created,
 			Deleted:      deleted,
 		}
-		select {
-		case <-ctx.Done():
+		if !idb.SendAssetBalanceRow(ctx, out, rec) {
 			return
-		case out <- rec:
 		}
 	}
 	if err := rows.Err(); err != nil {
-		out <- idb.AssetBalanceRow{Error: err}
+		idb.SendAssetBalanceRow(ctx, out, idb.AssetBalanceRow{Error: err})
 	}
 }
 
@@ -1893,7 +2506,7 @@ func (db *IndexerDb) Applications(ctx context.Context, filter *models.SearchForA
 		query += fmt.Sprintf(" LIMIT %d", *filter.Limit)
 	}
 
-	tx, err := db.db.BeginTx(ctx, readonlyRepeatableRead)
+	tx, err := db.beginRead(ctx)
 	if err != nil {
 		out <- idb.ApplicationRow{Error: err}
 		close(out)
@@ -1908,7 +2521,7 @@ func (db *IndexerDb) Applications(ctx context.Context, filter *models.SearchForA
 		return out, round
 	}
 
-	rows, err := tx.Query(ctx, query, whereArgs...)
+	rows, err := queryObserved(ctx, tx, applicationsQueryName, query, whereArgs...)
 	if err != nil {
 		out <- idb.ApplicationRow{Error: err}
 		close(out)
@@ -1919,7 +2532,7 @@ func (db *IndexerDb) Applications(ctx context.Context, filter *models.SearchForA
 	go func() {
 		db.yieldApplicationsThread(ctx, rows, out)
 		close(out)
-		tx.Rollback(ctx)
+		tx.Rollback(context.Background())
 	}()
 	return out, round
 }
@@ -1936,7 +2549,7 @@ func (db *IndexerDb) yieldApplicationsThread(ctx context.Context, rows pgx.Rows,
 		var deleted *bool
 		err := rows.Scan(&index, &creator, &paramsjson, &created, &closed, &deleted)
 		if err != nil {
-			out <- idb.ApplicationRow{Error: err}
+			idb.SendApplicationRow(ctx, out, idb.ApplicationRow{Error: err})
 			break
 		}
 		var rec idb.ApplicationRow
@@ -1947,7 +2560,7 @@ func (db *IndexerDb) yieldApplicationsThread(ctx context.Context, rows pgx.Rows,
 		ap, err := encoding.DecodeAppParams(paramsjson)
 		if err != nil {
 			rec.Error = fmt.Errorf("app=%d json err, %v", index, err)
-			out <- rec
+			idb.SendApplicationRow(ctx, out, rec)
 			break
 		}
 		rec.Application.Params.ApprovalProgram = ap.ApprovalProgram
@@ -1973,10 +2586,12 @@ func (db *IndexerDb) yieldApplicationsThread(ctx context.Context, rows pgx.Rows,
 			*rec.Application.Params.ExtraProgramPages = uint64(ap.ExtraProgramPages)
 		}
 
-		out <- rec
+		if !idb.SendApplicationRow(ctx, out, rec) {
+			return
+		}
 	}
 	if err := rows.Err(); err != nil {
-		out <- idb.ApplicationRow{Error: err}
+		idb.SendApplicationRow(ctx, out, idb.ApplicationRow{Error: err})
 	}
 }
 
@@ -1991,6 +2606,11 @@ func (db *IndexerDb) Health() (idb.Health, error) {
 	if db.readonly {
 		data["read-only-mode"] = true
 	}
+	if db.replica != nil {
+		data["replica-in-use"] = db.readDB() == db.replica.pool
+		data["replica-lag-rounds"] = db.replica.lastLag()
+	}
+	db.addHealthDiagnostics(context.Background(), data)
 
 	if db.migration != nil {
 		state := db.migration.GetStatus()
@@ -2001,6 +2621,9 @@ func (db *IndexerDb) Health() (idb.Health, error) {
 		if state.Status != "" {
 			data["migration-status"] = state.Status
 		}
+		if state.Progress != nil {
+			data["migration-progress"] = migrationProgressData(*state.Progress)
+		}
 
 		migrationRequired = state.Running
 		migrating = state.Running
@@ -2013,6 +2636,10 @@ func (db *IndexerDb) Health() (idb.Health, error) {
 
 		blocking = migrationStateBlocked(state)
 		migrationRequired = needsMigration(state)
+		// Reported by another indexer which runs the migrations.
+		if migrationRequired && state.Progress != nil {
+			data["migration-progress"] = migrationProgressData(*state.Progress)
+		}
 	}
 
 	data["migration-required"] = migrationRequired
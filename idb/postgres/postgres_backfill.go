@@ -0,0 +1,272 @@
+// You can build without postgres by `go build --tags nopostgres` but it's on by default
+//go:build !nopostgres
+// +build !nopostgres
+
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/algorand/go-algorand/data/basics"
+	"github.com/algorand/go-algorand/data/transactions"
+	"github.com/algorand/go-algorand/protocol"
+	"github.com/jackc/pgx/v4"
+
+	"github.com/algorand/indexer/idb"
+	"github.com/algorand/indexer/idb/postgres/internal/encoding"
+	"github.com/algorand/indexer/idb/postgres/internal/schema"
+	"github.com/algorand/indexer/idb/postgres/internal/writer"
+)
+
+const defaultBackfillChunkSize = 1000
+
+// backfillTxn is a stored transaction handed to a backfill feature.
+type backfillTxn struct {
+	round uint64
+	intra int
+	stxn  transactions.SignedTxnWithAD
+}
+
+// backfillFeature recomputes one kind of derived data. `process` is given the
+// transactions of one chunk of rounds in (round, intra) order and queues the
+// updates in `batch`.
+type backfillFeature struct {
+	description string
+	process     func(txns []backfillTxn, batch *pgx.Batch) error
+}
+
+var backfillFeatures = map[string]backfillFeature{
+	"participation": {
+		description: "txn_participation rows",
+		process:     backfillParticipation,
+	},
+	"sig-type": {
+		description: "account signature types",
+		process:     backfillSigType,
+	},
+}
+
+// BackfillFeatureNames returns the names of the features supported by Backfill.
+func BackfillFeatureNames() []string {
+	names := make([]string, 0, len(backfillFeatures))
+	for name := range backfillFeatures {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// backfillState is stored in the metastate table so an interrupted backfill can be
+// resumed.
+type backfillState struct {
+	MinRound  uint64 `codec:"min"`
+	MaxRound  uint64 `codec:"max"`
+	NextRound uint64 `codec:"next"`
+}
+
+// Backfill is part of idb.IndexerDb.
+func (db *IndexerDb) Backfill(ctx context.Context, opts idb.BackfillOptions) error {
+	if db.readonly {
+		return fmt.Errorf("Backfill() cannot backfill in read only mode")
+	}
+	feature, ok := backfillFeatures[opts.Feature]
+	if !ok {
+		return fmt.Errorf(
+			"Backfill() unknown feature \"%s\" (available: %s)",
+			opts.Feature, strings.Join(BackfillFeatureNames(), ", "))
+	}
+	if opts.MinRound > opts.MaxRound {
+		return fmt.Errorf(
+			"Backfill() min round %d is greater than max round %d",
+			opts.MinRound, opts.MaxRound)
+	}
+	if opts.ChunkSize == 0 {
+		opts.ChunkSize = defaultBackfillChunkSize
+	}
+
+	key := schema.BackfillMetastateKeyPrefix + opts.Feature
+	state := backfillState{
+		MinRound:  opts.MinRound,
+		MaxRound:  opts.MaxRound,
+		NextRound: opts.MinRound,
+	}
+	stateJSON, err := db.getMetastate(ctx, nil, key)
+	if err == nil {
+		var previous backfillState
+		err = encoding.DecodeJSON([]byte(stateJSON), &previous)
+		if err != nil {
+			return fmt.Errorf("Backfill() decode state err: %w", err)
+		}
+		if previous.MinRound == opts.MinRound && previous.MaxRound == opts.MaxRound {
+			state = previous
+			db.log.Infof("resuming %s backfill at round %d", opts.Feature, state.NextRound)
+		}
+	} else if err != idb.ErrorNotInitialized {
+		return fmt.Errorf("Backfill() err: %w", err)
+	}
+
+	for state.NextRound <= state.MaxRound {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		default:
+		}
+
+		end := state.NextRound + opts.ChunkSize - 1
+		if end > state.MaxRound || end < state.NextRound {
+			end = state.MaxRound
+		}
+		count, err := db.backfillChunk(ctx, feature, key, state, end)
+		if err != nil {
+			return fmt.Errorf("Backfill() err: %w", err)
+		}
+		db.log.Infof(
+			"backfilled %s for rounds %d-%d (%d txns)",
+			feature.description, state.NextRound, end, count)
+
+		if end == state.MaxRound {
+			break
+		}
+		state.NextRound = end + 1
+	}
+
+	_, err = db.db.Exec(ctx, `DELETE FROM metastate WHERE k = $1`, key)
+	if err != nil {
+		return fmt.Errorf("Backfill() clear state err: %w", err)
+	}
+	return nil
+}
+
+// backfillChunk processes rounds [state.NextRound, end] in one database transaction
+// and records the progress. Returns the number of transactions processed.
+func (db *IndexerDb) backfillChunk(ctx context.Context, feature backfillFeature, key string, state backfillState, end uint64) (int, error) {
+	db.accountingLock.Lock()
+	defer db.accountingLock.Unlock()
+
+	nextState := state
+	nextState.NextRound = end + 1
+
+	var count int
+	f := func(tx pgx.Tx) error {
+		defer tx.Rollback(ctx)
+
+		txns, err := loadBackfillTxns(ctx, tx, state.NextRound, end)
+		if err != nil {
+			return err
+		}
+		count = len(txns)
+
+		var batch pgx.Batch
+		err = feature.process(txns, &batch)
+		if err != nil {
+			return fmt.Errorf("backfillChunk() process err: %w", err)
+		}
+		batch.Queue(setMetastateUpsert, key, string(encoding.EncodeJSON(nextState)))
+
+		results := tx.SendBatch(ctx, &batch)
+		for i := 0; i < batch.Len(); i++ {
+			_, err := results.Exec()
+			if err != nil {
+				results.Close()
+				return fmt.Errorf("backfillChunk() exec err: %w", err)
+			}
+		}
+		err = results.Close()
+		if err != nil {
+			return fmt.Errorf("backfillChunk() close results err: %w", err)
+		}
+
+		return tx.Commit(ctx)
+	}
+	err := db.txWithRetry(serializable, f)
+	return count, err
+}
+
+func loadBackfillTxns(ctx context.Context, tx pgx.Tx, minRound, maxRound uint64) ([]backfillTxn, error) {
+	rows, err := tx.Query(
+		ctx,
+		`SELECT round, intra, txnbytes FROM txn WHERE round >= $1 AND round <= $2 ORDER BY round, intra`,
+		minRound, maxRound)
+	if err != nil {
+		return nil, fmt.Errorf("loadBackfillTxns() query err: %w", err)
+	}
+	defer rows.Close()
+
+	var txns []backfillTxn
+	for rows.Next() {
+		var txn backfillTxn
+		var txnbytes []byte
+		err = rows.Scan(&txn.round, &txn.intra, &txnbytes)
+		if err != nil {
+			return nil, fmt.Errorf("loadBackfillTxns() scan err: %w", err)
+		}
+		txnbytes, err = encoding.DecompressTxnBytes(txnbytes)
+		if err != nil {
+			return nil, fmt.Errorf(
+				"loadBackfillTxns() %d:%d err: %w", txn.round, txn.intra, err)
+		}
+		err = protocol.Decode(txnbytes, &txn.stxn)
+		if err != nil {
+			return nil, fmt.Errorf(
+				"loadBackfillTxns() %d:%d decode err: %w", txn.round, txn.intra, err)
+		}
+		txns = append(txns, txn)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("loadBackfillTxns() rows err: %w", err)
+	}
+	return txns, nil
+}
+
+func backfillParticipation(txns []backfillTxn, batch *pgx.Batch) error {
+	for _, txn := range txns {
+		for _, addr := range writer.GetTransactionParticipants(txn.stxn.Txn) {
+			addr := addr
+			batch.Queue(
+				`INSERT INTO txn_participation (addr, round, intra) VALUES ($1, $2, $3) ON CONFLICT DO NOTHING`,
+				addr[:], txn.round, txn.intra)
+		}
+	}
+	return nil
+}
+
+// Only update the key type if the sender has not sent a later transaction, which
+// makes it safe to backfill a range that does not end at the latest round.
+const backfillSigTypeUpdate = `UPDATE account a SET keytype = $1 WHERE a.addr = $2 AND NOT EXISTS (
+	SELECT 1 FROM txn_participation p JOIN txn t ON t.round = p.round AND t.intra = p.intra
+	WHERE p.addr = $2 AND (p.round > $3 OR (p.round = $3 AND p.intra > $4))
+	AND t.txn -> 'txn' ->> 'snd' = $5)`
+
+func backfillSigType(txns []backfillTxn, batch *pgx.Batch) error {
+	// Only the last transaction of each sender in the chunk matters.
+	last := make(map[basics.Address]int)
+	senders := make([]basics.Address, 0)
+	for i, txn := range txns {
+		if _, ok := last[txn.stxn.Txn.Sender]; !ok {
+			senders = append(senders, txn.stxn.Txn.Sender)
+		}
+		last[txn.stxn.Txn.Sender] = i
+	}
+
+	for _, sender := range senders {
+		sender := sender
+		txn := txns[last[sender]]
+
+		var keytype interface{}
+		if txn.stxn.Txn.RekeyTo.IsZero() {
+			sigtype, err := idb.SignatureType(&txn.stxn.SignedTxn)
+			if err != nil {
+				return fmt.Errorf(
+					"backfillSigType() %d:%d err: %w", txn.round, txn.intra, err)
+			}
+			keytype = sigtype
+		}
+		batch.Queue(
+			backfillSigTypeUpdate,
+			keytype, sender[:], txn.round, txn.intra, encoding.Base64(sender[:]))
+	}
+	return nil
+}
@@ -0,0 +1,207 @@
+// You can build without postgres by `go build --tags nopostgres` but it's on by default
+//go:build !nopostgres
+// +build !nopostgres
+
+package postgres
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/algorand/go-algorand/data/basics"
+	"github.com/jackc/pgx/v4"
+
+	"github.com/algorand/indexer/idb"
+	"github.com/algorand/indexer/idb/postgres/internal/encoding"
+)
+
+// dumpQuery is a query of DumpCSV() and the function which turns a row of it
+// into the CSV columns.
+type dumpQuery struct {
+	header []string
+	query  string
+	record func(rows pgx.Rows) ([]string, error)
+}
+
+var dumpQueries = map[string]dumpQuery{
+	idb.DumpAccounts: {
+		header: []string{
+			"address", "microalgos", "rewards_base", "rewards_total", "status",
+			"auth_address", "key_type", "created_at", "assets_opted_in",
+			"created_assets", "apps_opted_in", "created_apps",
+		},
+		query: "SELECT addr, microalgos, rewardsbase, rewards_total, account_data, keytype, " +
+			"created_at, total_assets_opted_in, total_created_assets, total_apps_opted_in, " +
+			"total_created_apps FROM account WHERE NOT deleted ORDER BY addr",
+		record: dumpAccount,
+	},
+	idb.DumpAssets: {
+		header: []string{
+			"asset_id", "creator", "name", "unit_name", "url", "total", "decimals",
+			"default_frozen", "manager", "reserve", "freeze", "clawback", "created_at",
+		},
+		query:  "SELECT index, creator_addr, params, created_at FROM asset WHERE NOT deleted ORDER BY index",
+		record: dumpAsset,
+	},
+	idb.DumpBalances: {
+		header: []string{"address", "asset_id", "amount", "frozen", "created_at"},
+		query: "SELECT addr, assetid, amount, frozen, created_at FROM account_asset " +
+			"WHERE NOT deleted ORDER BY addr, assetid",
+		record: dumpBalance,
+	},
+}
+
+// dumpAddress returns the address in `addr`, or "" for the zero address.
+func dumpAddress(addr basics.Address) string {
+	if addr.IsZero() {
+		return ""
+	}
+	return addr.String()
+}
+
+func dumpAccount(rows pgx.Rows) ([]string, error) {
+	var addr []byte
+	var microalgos, rewardsBase, rewardsTotal, createdAt uint64
+	var accountData []byte
+	var keyType *string
+	var assetsOptedIn, createdAssets, appsOptedIn, createdApps uint64
+	err := rows.Scan(
+		&addr, &microalgos, &rewardsBase, &rewardsTotal, &accountData, &keyType,
+		&createdAt, &assetsOptedIn, &createdAssets, &appsOptedIn, &createdApps)
+	if err != nil {
+		return nil, fmt.Errorf("dumpAccount() scan err: %w", err)
+	}
+
+	var ad basics.AccountData
+	if accountData != nil {
+		ad, err = encoding.DecodeTrimmedAccountData(accountData)
+		if err != nil {
+			return nil, fmt.Errorf("dumpAccount() decode err: %w", err)
+		}
+	}
+	var address basics.Address
+	copy(address[:], addr)
+	kt := ""
+	if keyType != nil {
+		kt = *keyType
+	}
+	return []string{
+		address.String(),
+		strconv.FormatUint(microalgos, 10),
+		strconv.FormatUint(rewardsBase, 10),
+		strconv.FormatUint(rewardsTotal, 10),
+		ad.Status.String(),
+		dumpAddress(ad.AuthAddr),
+		kt,
+		strconv.FormatUint(createdAt, 10),
+		strconv.FormatUint(assetsOptedIn, 10),
+		strconv.FormatUint(createdAssets, 10),
+		strconv.FormatUint(appsOptedIn, 10),
+		strconv.FormatUint(createdApps, 10),
+	}, nil
+}
+
+func dumpAsset(rows pgx.Rows) ([]string, error) {
+	var index, createdAt uint64
+	var creator []byte
+	var paramsJSON []byte
+	err := rows.Scan(&index, &creator, &paramsJSON, &createdAt)
+	if err != nil {
+		return nil, fmt.Errorf("dumpAsset() scan err: %w", err)
+	}
+	params, err := encoding.DecodeAssetParams(paramsJSON)
+	if err != nil {
+		return nil, fmt.Errorf("dumpAsset() decode err: %w", err)
+	}
+	var creatorAddr basics.Address
+	copy(creatorAddr[:], creator)
+	return []string{
+		strconv.FormatUint(index, 10),
+		creatorAddr.String(),
+		params.AssetName,
+		params.UnitName,
+		params.URL,
+		strconv.FormatUint(params.Total, 10),
+		strconv.FormatUint(uint64(params.Decimals), 10),
+		strconv.FormatBool(params.DefaultFrozen),
+		dumpAddress(params.Manager),
+		dumpAddress(params.Reserve),
+		dumpAddress(params.Freeze),
+		dumpAddress(params.Clawback),
+		strconv.FormatUint(createdAt, 10),
+	}, nil
+}
+
+func dumpBalance(rows pgx.Rows) ([]string, error) {
+	var addr []byte
+	var assetID, amount, createdAt uint64
+	var frozen bool
+	err := rows.Scan(&addr, &assetID, &amount, &frozen, &createdAt)
+	if err != nil {
+		return nil, fmt.Errorf("dumpBalance() scan err: %w", err)
+	}
+	var address basics.Address
+	copy(address[:], addr)
+	return []string{
+		address.String(),
+		strconv.FormatUint(assetID, 10),
+		strconv.FormatUint(amount, 10),
+		strconv.FormatBool(frozen),
+		strconv.FormatUint(createdAt, 10),
+	}, nil
+}
+
+// DumpCSV is part of idb.IndexerDb. The rows are read in one repeatable read
+// transaction, so they are all of the same round while the import continues.
+func (db *IndexerDb) DumpCSV(ctx context.Context, table string, w io.Writer) (uint64, error) {
+	dq, ok := dumpQueries[table]
+	if !ok {
+		return 0, fmt.Errorf("DumpCSV() unknown table %s", table)
+	}
+
+	tx, err := db.readDB().BeginTx(ctx, readonlyRepeatableRead)
+	if err != nil {
+		return 0, fmt.Errorf("DumpCSV() begin err: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	nextRound, err := db.getNextRoundToAccount(ctx, tx)
+	if err != nil {
+		return 0, fmt.Errorf("DumpCSV() err: %w", err)
+	}
+	if nextRound == 0 {
+		return 0, fmt.Errorf("DumpCSV() no round was imported")
+	}
+
+	cw := csv.NewWriter(w)
+	err = cw.Write(dq.header)
+	if err != nil {
+		return 0, fmt.Errorf("DumpCSV() write err: %w", err)
+	}
+	rows, err := tx.Query(ctx, dq.query)
+	if err != nil {
+		return 0, fmt.Errorf("DumpCSV() query err: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		record, err := dq.record(rows)
+		if err != nil {
+			return 0, fmt.Errorf("DumpCSV() err: %w", err)
+		}
+		err = cw.Write(record)
+		if err != nil {
+			return 0, fmt.Errorf("DumpCSV() write err: %w", err)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return 0, fmt.Errorf("DumpCSV() rows err: %w", err)
+	}
+	cw.Flush()
+	if err := cw.Error(); err != nil {
+		return 0, fmt.Errorf("DumpCSV() write err: %w", err)
+	}
+	return nextRound - 1, nil
+}
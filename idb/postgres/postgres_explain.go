@@ -0,0 +1,190 @@
+// You can build without postgres by `go build --tags nopostgres` but it's on by default
+//go:build !nopostgres
+// +build !nopostgres
+
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/algorand/indexer/idb"
+)
+
+// explainNode is one node of the output of EXPLAIN (FORMAT JSON).
+type explainNode struct {
+	NodeType     string        `json:"Node Type"`
+	RelationName string        `json:"Relation Name"`
+	IndexName    string        `json:"Index Name"`
+	TotalCost    float64       `json:"Total Cost"`
+	PlanRows     float64       `json:"Plan Rows"`
+	Plans        []explainNode `json:"Plans"`
+}
+
+// ExplainTransactions is part of idb.IndexerDb. Only the query of the first page
+// is explained, the next token is ignored.
+func (db *IndexerDb) ExplainTransactions(ctx context.Context, tf idb.TransactionFilter) (idb.QueryPlan, error) {
+	query, whereArgs, err := buildTransactionQuery(tf)
+	if err != nil {
+		return idb.QueryPlan{}, fmt.Errorf("ExplainTransactions() err: %w", err)
+	}
+
+	root, err := db.explain(ctx, query, whereArgs...)
+	if err != nil {
+		return idb.QueryPlan{}, fmt.Errorf("ExplainTransactions() err: %w", err)
+	}
+
+	plan := summarizePlan(root)
+	plan.Suggestions = transactionQuerySuggestions(tf, plan)
+	return plan, nil
+}
+
+// EstimateTransactionsCount is part of idb.IndexerDb. It is the planner's
+// estimate of the rows of the search without a limit, from the statistics of
+// the tables, which may be far off for combined filters.
+func (db *IndexerDb) EstimateTransactionsCount(ctx context.Context, tf idb.TransactionFilter) (uint64, error) {
+	tf.Limit = 0
+	tf.NextToken = nil
+	query, whereArgs, err := buildTransactionQuery(tf)
+	if err != nil {
+		return 0, fmt.Errorf("EstimateTransactionsCount() err: %w", err)
+	}
+
+	root, err := db.explain(ctx, query, whereArgs...)
+	if err != nil {
+		return 0, fmt.Errorf("EstimateTransactionsCount() err: %w", err)
+	}
+	return uint64(root.PlanRows), nil
+}
+
+// EstimateAccountsCount is part of idb.IndexerDb, see
+// EstimateTransactionsCount().
+func (db *IndexerDb) EstimateAccountsCount(ctx context.Context, opts idb.AccountQueryOptions) (uint64, error) {
+	opts.Limit = 0
+	opts.GreaterThanAddress = nil
+	query, whereArgs := db.buildAccountQuery(opts)
+
+	root, err := db.explain(ctx, query, whereArgs...)
+	if err != nil {
+		return 0, fmt.Errorf("EstimateAccountsCount() err: %w", err)
+	}
+	return uint64(root.PlanRows), nil
+}
+
+// explain returns the root of the plan of `query` without running it.
+func (db *IndexerDb) explain(ctx context.Context, query string, args ...interface{}) (explainNode, error) {
+	tx, err := db.readDB().BeginTx(ctx, readonlyRepeatableRead)
+	if err != nil {
+		return explainNode{}, fmt.Errorf("explain() begin err: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var planJSON []byte
+	err = tx.QueryRow(ctx, "EXPLAIN (FORMAT JSON) "+query, args...).Scan(&planJSON)
+	if err != nil {
+		return explainNode{}, fmt.Errorf("explain() err: %w", err)
+	}
+
+	var plans []struct {
+		Plan explainNode `json:"Plan"`
+	}
+	err = json.Unmarshal(planJSON, &plans)
+	if err != nil {
+		return explainNode{}, fmt.Errorf("explain() decode err: %w", err)
+	}
+	if len(plans) == 0 {
+		return explainNode{}, fmt.Errorf("explain() empty plan")
+	}
+	return plans[0].Plan, nil
+}
+
+// summarizePlan collects the tables and indexes used anywhere in the plan tree.
+func summarizePlan(root explainNode) idb.QueryPlan {
+	tables := make(map[string]struct{})
+	indexes := make(map[string]struct{})
+	seqScans := make(map[string]struct{})
+
+	var walk func(node explainNode)
+	walk = func(node explainNode) {
+		if node.RelationName != "" {
+			tables[node.RelationName] = struct{}{}
+			if node.NodeType == "Seq Scan" {
+				seqScans[node.RelationName] = struct{}{}
+			}
+		}
+		if node.IndexName != "" {
+			indexes[node.IndexName] = struct{}{}
+		}
+		for _, child := range node.Plans {
+			walk(child)
+		}
+	}
+	walk(root)
+
+	return idb.QueryPlan{
+		Tables:          sortedKeys(tables),
+		Indexes:         sortedKeys(indexes),
+		SequentialScans: sortedKeys(seqScans),
+		EstimatedCost:   root.TotalCost,
+		EstimatedRows:   root.PlanRows,
+	}
+}
+
+func sortedKeys(m map[string]struct{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// transactionQuerySuggestions returns hints for making a transaction search use
+// the indexes of the txn and txn_participation tables.
+func transactionQuerySuggestions(tf idb.TransactionFilter, plan idb.QueryPlan) []string {
+	suggestions := make([]string, 0)
+
+	hasRounds := tf.Round != nil || tf.MinRound != 0 || tf.MaxRound != 0
+	hasIndexedFilter := tf.Address != nil || tf.AssetID != 0 || tf.ApplicationID != 0 ||
+		tf.Txid != "" || hasRounds
+
+	for _, table := range plan.SequentialScans {
+		if table == "txn" {
+			suggestions = append(suggestions,
+				"the txn table is read in full, add address, asset-id, application-id, "+
+					"txid or a round range to use an index")
+		}
+	}
+	if tf.Address != nil && tf.AddressRole != 0 && !hasRounds {
+		suggestions = append(suggestions,
+			"address-role is checked after loading every transaction of the address, "+
+				"add min-round/max-round to limit the transactions examined")
+	}
+	if !hasIndexedFilter && len(tf.NotePrefix) > 0 {
+		suggestions = append(suggestions,
+			"note-prefix cannot use an index, combine it with address, asset-id "+
+				"or a round range")
+	}
+	if !hasIndexedFilter && (tf.AlgosGT != nil || tf.AlgosLT != nil ||
+		tf.EffectiveAmountGT != nil || tf.EffectiveAmountLT != nil) {
+		suggestions = append(suggestions,
+			"currency filters cannot use an index, combine them with address, "+
+				"asset-id or a round range")
+	}
+	if tf.AssetID == 0 && (tf.AssetAmountGT != nil || tf.AssetAmountLT != nil) {
+		suggestions = append(suggestions,
+			"add asset-id to use the txn_asset index for asset amount filters")
+	}
+	if !hasRounds && (!tf.BeforeTime.IsZero() || !tf.AfterTime.IsZero()) {
+		suggestions = append(suggestions,
+			"time filters are converted to rounds through block_header, a round range "+
+				"is cheaper if it is known")
+	}
+	if tf.Limit == 0 {
+		suggestions = append(suggestions, "add a limit")
+	}
+
+	return suggestions
+}
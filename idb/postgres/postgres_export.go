@@ -0,0 +1,53 @@
+// You can build without postgres by `go build --tags nopostgres` but it's on by default
+//go:build !nopostgres
+// +build !nopostgres
+
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/algorand/indexer/idb"
+	"github.com/algorand/indexer/idb/postgres/internal/encoding"
+	"github.com/algorand/indexer/idb/postgres/internal/schema"
+)
+
+// exportState is the progress of an exporter.
+type exportState struct {
+	// Next round to publish.
+	NextRound uint64 `codec:"next_round"`
+}
+
+// GetExportRound is part of idb.IndexerDb.
+func (db *IndexerDb) GetExportRound(ctx context.Context, name string) (uint64, error) {
+	stateJSON, err := db.getMetastate(ctx, nil, schema.ExportMetastateKeyPrefix+name)
+	if err == idb.ErrorNotInitialized {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("GetExportRound() err: %w", err)
+	}
+
+	var state exportState
+	err = encoding.DecodeJSON([]byte(stateJSON), &state)
+	if err != nil {
+		return 0, fmt.Errorf("GetExportRound() decode err: %w", err)
+	}
+	return state.NextRound, nil
+}
+
+// SetExportRound is part of idb.IndexerDb.
+func (db *IndexerDb) SetExportRound(ctx context.Context, name string, round uint64) error {
+	if db.readonly {
+		return fmt.Errorf("SetExportRound() cannot record progress in read only mode")
+	}
+	state := exportState{NextRound: round}
+	_, err := db.db.Exec(
+		ctx, setMetastateUpsert, schema.ExportMetastateKeyPrefix+name,
+		string(encoding.EncodeJSON(state)))
+	if err != nil {
+		return fmt.Errorf("SetExportRound() err: %w", err)
+	}
+	return nil
+}
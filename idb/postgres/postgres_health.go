@@ -0,0 +1,87 @@
+// You can build without postgres by `go build --tags nopostgres` but it's on by default
+//go:build !nopostgres
+// +build !nopostgres
+
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/algorand/indexer/idb/migration"
+)
+
+// oldestQueryAgeQuery returns the age in seconds of the oldest query running in
+// the indexer database, other than itself, or 0.
+const oldestQueryAgeQuery = `SELECT COALESCE(EXTRACT(EPOCH FROM max(now() - query_start)), 0)::float8
+	FROM pg_stat_activity
+	WHERE datname = current_database() AND state = 'active' AND pid <> pg_backend_pid()`
+
+// How long collecting the diagnostics may take, Health() is called by
+// monitoring and must answer quickly when the database is overloaded.
+const healthDiagnosticsTimeout = 2 * time.Second
+
+// addHealthDiagnostics adds details about the database for operators to `data`.
+// They are best effort, a diagnostic which can't be read is logged and left
+// out instead of failing the health check.
+func (db *IndexerDb) addHealthDiagnostics(ctx context.Context, data map[string]interface{}) {
+	ctx, cancel := context.WithTimeout(ctx, healthDiagnosticsTimeout)
+	defer cancel()
+
+	stat := db.db.Stat()
+	data["pool"] = map[string]interface{}{
+		"acquired-conns": stat.AcquiredConns(),
+		"idle-conns":     stat.IdleConns(),
+		"total-conns":    stat.TotalConns(),
+		"max-conns":      stat.MaxConns(),
+		"utilization":    float64(stat.AcquiredConns()) / float64(stat.MaxConns()),
+	}
+
+	// CockroachDB does not have the postgres statistics views.
+	if !db.cockroachCompat {
+		age, err := db.oldestQueryAge(ctx)
+		if err != nil {
+			db.log.WithError(err).Warn("addHealthDiagnostics() oldest query age")
+		} else {
+			data["oldest-query-seconds"] = age
+		}
+	}
+
+	if state, err := db.getMigrationState(); err == nil {
+		data["migration-next"] = state.NextMigration
+		data["migration-count"] = len(migrations)
+	}
+
+	retention, err := db.getRetentionState(ctx, nil)
+	if err != nil {
+		db.log.WithError(err).Warn("addHealthDiagnostics() retention state")
+	} else {
+		data["earliest-round"] = retention.EarliestRound
+	}
+}
+
+// oldestQueryAge returns the age of the oldest running query in seconds.
+func (db *IndexerDb) oldestQueryAge(ctx context.Context) (float64, error) {
+	var age float64
+	err := db.db.QueryRow(ctx, oldestQueryAgeQuery).Scan(&age)
+	if err != nil {
+		return 0, fmt.Errorf("oldestQueryAge() err: %w", err)
+	}
+	return age, nil
+}
+
+// migrationProgressData formats the progress of the running migration for the
+// health check.
+func migrationProgressData(progress migration.Progress) map[string]interface{} {
+	data := map[string]interface{}{
+		"processed": progress.Processed,
+		"total":     progress.Total,
+		"percent":   progress.Percent(),
+		"updated":   progress.Updated,
+	}
+	if eta, ok := progress.ETA(); ok {
+		data["eta-seconds"] = uint64(eta.Seconds())
+	}
+	return data
+}
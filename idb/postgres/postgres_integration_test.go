@@ -1,24 +1,41 @@
 package postgres
 
 import (
+	"bytes"
 	"context"
 	"database/sql"
+	"encoding/csv"
+	"errors"
+	"io/ioutil"
 	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/algorand/go-algorand/crypto"
 	"github.com/algorand/go-algorand/data/basics"
+	"github.com/algorand/go-algorand/data/bookkeeping"
 	"github.com/algorand/go-algorand/data/transactions"
 	"github.com/algorand/go-algorand/protocol"
 	"github.com/jackc/pgx/v4"
 	"github.com/jackc/pgx/v4/pgxpool"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	log "github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
 	"github.com/algorand/indexer/api/generated/v2"
 	"github.com/algorand/indexer/idb"
+	"github.com/algorand/indexer/idb/migration"
 	"github.com/algorand/indexer/idb/postgres/internal/encoding"
+	ledger_for_evaluator "github.com/algorand/indexer/idb/postgres/internal/ledger_for_evaluator"
 	pgtest "github.com/algorand/indexer/idb/postgres/internal/testing"
 	"github.com/algorand/indexer/util/test"
 )
@@ -928,6 +945,37 @@ func TestOpenDbAgain(t *testing.T) {
 	require.NoError(t, err)
 }
 
+// Test that the pool settings of IndexerDbOptions are applied.
+func TestOpenPostgresPoolOptions(t *testing.T) {
+	_, connStr, shutdownFunc := pgtest.SetupPostgres(t)
+	defer shutdownFunc()
+
+	opts := idb.IndexerDbOptions{
+		MaxConn:           4,
+		MinConn:           2,
+		MaxConnLifetime:   time.Hour,
+		HealthCheckPeriod: time.Second,
+		StatementTimeout:  1500 * time.Millisecond,
+	}
+	db, _, err := OpenPostgres(connStr, opts, nil)
+	require.NoError(t, err)
+
+	config := db.db.Config()
+	assert.Equal(t, int32(4), config.MaxConns)
+	assert.Equal(t, int32(2), config.MinConns)
+	assert.Equal(t, time.Hour, config.MaxConnLifetime)
+	assert.Equal(t, time.Second, config.HealthCheckPeriod)
+
+	var timeout string
+	err = db.db.QueryRow(context.Background(), "SHOW statement_timeout").Scan(&timeout)
+	require.NoError(t, err)
+	assert.Equal(t, "1500ms", timeout)
+
+	opts.MinConn = 5
+	_, _, err = OpenPostgres(connStr, opts, nil)
+	assert.Error(t, err)
+}
+
 func requireNilOrEqual(t *testing.T, expected string, actual *string) {
 	if expected == "" {
 		require.Nil(t, actual)
@@ -1434,3 +1482,1654 @@ func TestAddBlockAppOptInOutSameRound(t *testing.T) {
 	require.NotNil(t, localState.ClosedOutAtRound)
 	assert.Equal(t, uint64(1), *localState.ClosedOutAtRound)
 }
+
+// Test that DeleteOrphanedRows reports and removes participation rows whose
+// transaction no longer exists, and leaves everything else alone.
+func TestDeleteOrphanedRows(t *testing.T) {
+	db, shutdownFunc := setupIdb(t, test.MakeGenesis(), test.MakeGenesisBlock())
+	defer shutdownFunc()
+
+	payTxn := test.MakePaymentTxn(
+		1000, 10, 0, 0, 0, 0, test.AccountA, test.AccountB, basics.Address{},
+		basics.Address{})
+	block, err := test.MakeBlockForTxns(test.MakeGenesisBlock().BlockHeader, &payTxn)
+	require.NoError(t, err)
+
+	err = db.AddBlock(&block)
+	require.NoError(t, err)
+
+	// Simulate an interrupted repair which deleted the transaction but not its
+	// participation rows.
+	_, err = db.db.Exec(context.Background(), "DELETE FROM txn WHERE round = 1")
+	require.NoError(t, err)
+
+	countOrphans := func(results []idb.OrphanedRows) int64 {
+		var total int64
+		for _, result := range results {
+			total += result.Count
+		}
+		return total
+	}
+
+	results, err := db.DeleteOrphanedRows(context.Background(), true)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), countOrphans(results))
+	assert.Equal(t, 2, queryInt(db.db, "SELECT count(*) FROM txn_participation"))
+
+	results, err = db.DeleteOrphanedRows(context.Background(), false)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), countOrphans(results))
+	assert.Equal(t, 0, queryInt(db.db, "SELECT count(*) FROM txn_participation"))
+
+	results, err = db.DeleteOrphanedRows(context.Background(), true)
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), countOrphans(results))
+}
+
+// Test that the participation backfill restores deleted txn_participation rows and
+// clears its resume state when done.
+func TestBackfillParticipation(t *testing.T) {
+	db, shutdownFunc := setupIdb(t, test.MakeGenesis(), test.MakeGenesisBlock())
+	defer shutdownFunc()
+
+	payTxn := test.MakePaymentTxn(
+		1000, 10, 0, 0, 0, 0, test.AccountA, test.AccountB, basics.Address{},
+		basics.Address{})
+	block, err := test.MakeBlockForTxns(test.MakeGenesisBlock().BlockHeader, &payTxn)
+	require.NoError(t, err)
+
+	err = db.AddBlock(&block)
+	require.NoError(t, err)
+
+	_, err = db.db.Exec(context.Background(), "DELETE FROM txn_participation")
+	require.NoError(t, err)
+
+	opts := idb.BackfillOptions{
+		Feature:   "participation",
+		MinRound:  0,
+		MaxRound:  1,
+		ChunkSize: 1,
+	}
+	err = db.Backfill(context.Background(), opts)
+	require.NoError(t, err)
+
+	assert.Equal(t, 2, queryInt(db.db, "SELECT count(*) FROM txn_participation"))
+	assert.Equal(
+		t, 0, queryInt(db.db, "SELECT count(*) FROM metastate WHERE k = 'backfill_participation'"))
+
+	opts.Feature = "no-such-feature"
+	err = db.Backfill(context.Background(), opts)
+	assert.Error(t, err)
+}
+
+// Test that ExplainTransactions reports the tables of the transaction search.
+func TestExplainTransactions(t *testing.T) {
+	db, shutdownFunc := setupIdb(t, test.MakeGenesis(), test.MakeGenesisBlock())
+	defer shutdownFunc()
+
+	tf := idb.TransactionFilter{
+		Address:     test.AccountA[:],
+		AddressRole: idb.AddressRoleSender,
+	}
+	plan, err := db.ExplainTransactions(context.Background(), tf)
+	require.NoError(t, err)
+
+	assert.Contains(t, plan.Tables, "txn")
+	assert.Contains(t, plan.Tables, "txn_participation")
+	assert.Contains(t, plan.Tables, "block_header")
+	assert.Greater(t, plan.EstimatedCost, 0.0)
+	assert.Contains(t, plan.Suggestions, "add a limit")
+}
+
+// Test that transactions larger than maxTxnBytes are returned without their eval
+// delta and marked as truncated.
+func TestTransactionsTruncateLargeTxn(t *testing.T) {
+	db, shutdownFunc := setupIdb(t, test.MakeGenesis(), test.MakeGenesisBlock())
+	defer shutdownFunc()
+
+	appCall := test.MakeCreateAppTxn(test.AccountA)
+	appCall.ApplyData.EvalDelta = transactions.EvalDelta{
+		GlobalDelta: map[string]basics.ValueDelta{
+			"key": {Action: basics.SetBytesAction, Bytes: strings.Repeat("x", 1000)},
+		},
+	}
+	payTxn := test.MakePaymentTxn(
+		1000, 10, 0, 0, 0, 0, test.AccountA, test.AccountB, basics.Address{},
+		basics.Address{})
+	block, err := test.MakeBlockForTxns(
+		test.MakeGenesisBlock().BlockHeader, &appCall, &payTxn)
+	require.NoError(t, err)
+
+	err = db.AddBlock(&block)
+	require.NoError(t, err)
+
+	defer func(orig int) { maxTxnBytes = orig }(maxTxnBytes)
+	maxTxnBytes = 500
+
+	rowsCh, _ := db.Transactions(context.Background(), idb.TransactionFilter{})
+	var rows []idb.TxnRow
+	for row := range rowsCh {
+		require.NoError(t, row.Error)
+		rows = append(rows, row)
+	}
+	require.Len(t, rows, 2)
+
+	assert.True(t, rows[0].Truncated)
+	var stxn transactions.SignedTxnWithAD
+	err = protocol.Decode(rows[0].TxnBytes, &stxn)
+	require.NoError(t, err)
+	assert.Equal(t, appCall.Txn.ID(), stxn.Txn.ID())
+	assert.Empty(t, stxn.EvalDelta.GlobalDelta)
+
+	assert.False(t, rows[1].Truncated)
+	var payStxn transactions.SignedTxnWithAD
+	err = protocol.Decode(rows[1].TxnBytes, &payStxn)
+	require.NoError(t, err)
+	assert.Equal(t, payTxn.Txn.ID(), payStxn.Txn.ID())
+}
+
+// Test that compressed transactions are decompressed when they are read.
+func TestTransactionsCompressed(t *testing.T) {
+	db, shutdownFunc := setupIdb(t, test.MakeGenesis(), test.MakeGenesisBlock())
+	defer shutdownFunc()
+	db.compressTxnBytes = true
+
+	payTxn := test.MakePaymentTxn(
+		1000, 10, 0, 0, 0, 0, test.AccountA, test.AccountB, basics.Address{},
+		basics.Address{})
+	payTxn.Txn.Note = []byte(strings.Repeat("note ", 200))
+	block, err := test.MakeBlockForTxns(test.MakeGenesisBlock().BlockHeader, &payTxn)
+	require.NoError(t, err)
+
+	err = db.AddBlock(&block)
+	require.NoError(t, err)
+
+	var stored []byte
+	row := db.db.QueryRow(context.Background(), "SELECT txnbytes FROM txn")
+	require.NoError(t, row.Scan(&stored))
+	assert.Less(t, len(stored), len(payTxn.Txn.Note))
+
+	rowsCh, _ := db.Transactions(context.Background(), idb.TransactionFilter{})
+	var rows []idb.TxnRow
+	for row := range rowsCh {
+		require.NoError(t, row.Error)
+		rows = append(rows, row)
+	}
+	require.Len(t, rows, 1)
+
+	var stxn transactions.SignedTxnWithAD
+	err = protocol.Decode(rows[0].TxnBytes, &stxn)
+	require.NoError(t, err)
+	assert.Equal(t, payTxn.Txn.ID(), stxn.Txn.ID())
+	assert.Equal(t, payTxn.Txn.Note, stxn.Txn.Note)
+}
+
+// Test that the size of a compressed transaction is checked against maxTxnBytes
+// before compression, and that only the compressed bytes have the eval delta.
+func TestTransactionsCompressedTruncateLargeTxn(t *testing.T) {
+	db, shutdownFunc := setupIdb(t, test.MakeGenesis(), test.MakeGenesisBlock())
+	defer shutdownFunc()
+	db.compressTxnBytes = true
+
+	appCall := test.MakeCreateAppTxn(test.AccountA)
+	appCall.ApplyData.EvalDelta = transactions.EvalDelta{
+		GlobalDelta: map[string]basics.ValueDelta{
+			"key": {Action: basics.SetBytesAction, Bytes: strings.Repeat("x", 1000)},
+		},
+	}
+	block, err := test.MakeBlockForTxns(test.MakeGenesisBlock().BlockHeader, &appCall)
+	require.NoError(t, err)
+
+	err = db.AddBlock(&block)
+	require.NoError(t, err)
+
+	var stored []byte
+	var length int
+	var hasDelta bool
+	row := db.db.QueryRow(
+		context.Background(), "SELECT txnbytes, txnbytes_len, txn ? 'dt' FROM txn")
+	require.NoError(t, row.Scan(&stored, &length, &hasDelta))
+	assert.Less(t, len(stored), 500)
+	assert.Greater(t, length, 1000)
+	assert.False(t, hasDelta)
+
+	defer func(orig int) { maxTxnBytes = orig }(maxTxnBytes)
+	maxTxnBytes = 500
+
+	rowsCh, _ := db.Transactions(context.Background(), idb.TransactionFilter{})
+	var rows []idb.TxnRow
+	for row := range rowsCh {
+		require.NoError(t, row.Error)
+		rows = append(rows, row)
+	}
+	require.Len(t, rows, 1)
+
+	assert.True(t, rows[0].Truncated)
+	var stxn transactions.SignedTxnWithAD
+	err = protocol.Decode(rows[0].TxnBytes, &stxn)
+	require.NoError(t, err)
+	assert.Equal(t, appCall.Txn.ID(), stxn.Txn.ID())
+	assert.Empty(t, stxn.EvalDelta.GlobalDelta)
+
+	// The eval delta is read from the compressed bytes.
+	maxTxnBytes = 1024 * 1024
+	rowsCh, _ = db.Transactions(context.Background(), idb.TransactionFilter{})
+	rows = rows[:0]
+	for row := range rowsCh {
+		require.NoError(t, row.Error)
+		rows = append(rows, row)
+	}
+	require.Len(t, rows, 1)
+
+	assert.False(t, rows[0].Truncated)
+	err = protocol.Decode(rows[0].TxnBytes, &stxn)
+	require.NoError(t, err)
+	assert.Equal(t, appCall.ApplyData.EvalDelta, stxn.EvalDelta)
+}
+
+// Test that AddBlocks() evaluates every block against the state written by the
+// previous ones and copies all their transactions.
+func TestAddBlocks(t *testing.T) {
+	db, shutdownFunc := setupIdb(t, test.MakeGenesis(), test.MakeGenesisBlock())
+	defer shutdownFunc()
+
+	assetid := uint64(1)
+	createAsset := test.MakeConfigAssetTxn(
+		0, 1000, 0, false, "mcn", "my coin", "http://antarctica.com", test.AccountA)
+	block1, err := test.MakeBlockForTxns(test.MakeGenesisBlock().BlockHeader, &createAsset)
+	require.NoError(t, err)
+
+	optIn := test.MakeAssetOptInTxn(assetid, test.AccountB)
+	transfer := test.MakeAssetTransferTxn(
+		assetid, 100, test.AccountA, test.AccountB, basics.Address{})
+	block2, err := test.MakeBlockForTxns(block1.BlockHeader, &optIn, &transfer)
+	require.NoError(t, err)
+
+	err = db.AddBlocks([]*bookkeeping.Block{&block1, &block2})
+	require.NoError(t, err)
+
+	assertAccountAsset(t, db.db, test.AccountA, assetid, false, 900)
+	assertAccountAsset(t, db.db, test.AccountB, assetid, false, 100)
+	assert.Equal(t, 3, queryInt(db.db, "SELECT count(*) FROM txn"))
+	assert.Equal(t, 4, queryInt(db.db, "SELECT count(*) FROM txn_participation"))
+
+	nextRound, err := db.GetNextRoundToAccount()
+	require.NoError(t, err)
+	assert.Equal(t, uint64(3), nextRound)
+}
+
+// Test that the accounts holding an asset are paged in address order.
+func TestGetAccountsHasAssetPaging(t *testing.T) {
+	db, shutdownFunc := setupIdb(t, test.MakeGenesis(), test.MakeGenesisBlock())
+	defer shutdownFunc()
+
+	assetid := uint64(1)
+	createAsset := test.MakeConfigAssetTxn(
+		0, 1000, 0, false, "mcn", "my coin", "http://antarctica.com", test.AccountD)
+	optInA := test.MakeAssetOptInTxn(assetid, test.AccountA)
+	optInB := test.MakeAssetOptInTxn(assetid, test.AccountB)
+	optInC := test.MakeAssetOptInTxn(assetid, test.AccountC)
+	block, err := test.MakeBlockForTxns(
+		test.MakeGenesisBlock().BlockHeader, &createAsset, &optInA, &optInB, &optInC)
+	require.NoError(t, err)
+	err = db.AddBlock(&block)
+	require.NoError(t, err)
+
+	getPage := func(after []byte) [][]byte {
+		opts := idb.AccountQueryOptions{
+			HasAssetID:         assetid,
+			GreaterThanAddress: after,
+			Limit:              3,
+		}
+		rowsCh, _ := db.GetAccounts(context.Background(), opts)
+		var addrs [][]byte
+		for row := range rowsCh {
+			require.NoError(t, row.Error)
+			addr, err := basics.UnmarshalChecksumAddress(row.Account.Address)
+			require.NoError(t, err)
+			addrs = append(addrs, append([]byte{}, addr[:]...))
+		}
+		return addrs
+	}
+
+	holders := [][]byte{test.AccountA[:], test.AccountB[:], test.AccountC[:], test.AccountD[:]}
+	sort.Slice(holders, func(i, j int) bool { return bytes.Compare(holders[i], holders[j]) < 0 })
+
+	page1 := getPage(nil)
+	assert.Equal(t, holders[:3], page1)
+	page2 := getPage(page1[2])
+	assert.Equal(t, holders[3:], page2)
+}
+
+// Test that indexMigration() replaces an invalid index left by a failed build.
+func TestIndexMigration(t *testing.T) {
+	db, shutdownFunc := setupIdb(t, test.MakeGenesis(), test.MakeGenesisBlock())
+	defer shutdownFunc()
+
+	_, err := db.db.Exec(context.Background(), "CREATE TABLE t (x int); INSERT INTO t VALUES (1), (1)")
+	require.NoError(t, err)
+	// The duplicates make the build fail and leave an invalid index.
+	_, err = db.db.Exec(context.Background(), "CREATE UNIQUE INDEX CONCURRENTLY t_x ON t (x)")
+	require.Error(t, err)
+	assert.Equal(t, 0, queryInt(db.db,
+		"SELECT count(*) FROM pg_index WHERE indexrelid = 't_x'::regclass AND indisvalid"))
+
+	state := MigrationState{NextMigration: 3}
+	indexes := []concurrentIndex{{name: "t_x", definition: "ON t (x)"}}
+	err = indexMigration(db, &state, indexes)
+	require.NoError(t, err)
+	assert.Equal(t, 4, state.NextMigration)
+	assert.Equal(t, 1, queryInt(db.db,
+		"SELECT count(*) FROM pg_index WHERE indexrelid = 't_x'::regclass AND indisvalid"))
+
+	// A valid index is kept.
+	err = indexMigration(db, &state, indexes)
+	require.NoError(t, err)
+	assert.Equal(t, 5, state.NextMigration)
+
+	migrationState, err := db.getMigrationState()
+	require.NoError(t, err)
+	assert.Equal(t, 5, migrationState.NextMigration)
+	assert.Nil(t, migrationState.Progress)
+}
+
+// Test that the progress of a migration is stored with the migration state and
+// reported by the health check.
+func TestMigrationProgress(t *testing.T) {
+	db, shutdownFunc := setupIdb(t, test.MakeGenesis(), test.MakeGenesisBlock())
+	defer shutdownFunc()
+
+	state := MigrationState{NextMigration: len(migrations) - 1}
+	err := db.setMigrationProgress(&state, 0, 4)
+	require.NoError(t, err)
+	err = db.setMigrationProgress(&state, 1, 4)
+	require.NoError(t, err)
+
+	migrationState, err := db.getMigrationState()
+	require.NoError(t, err)
+	require.NotNil(t, migrationState.Progress)
+	assert.Equal(t, uint64(1), migrationState.Progress.Processed)
+	assert.Equal(t, uint64(4), migrationState.Progress.Total)
+
+	// Reported from the metastate when this indexer does not run the migrations.
+	db.migration = nil
+	health, err := db.Health()
+	require.NoError(t, err)
+	require.Contains(t, *health.Data, "migration-progress")
+	progress := (*health.Data)["migration-progress"].(map[string]interface{})
+	assert.Equal(t, 25.0, progress["percent"])
+}
+
+// Test that an interrupted chunkedMigration() resumes after the last committed
+// batch.
+func TestChunkedMigrationResumes(t *testing.T) {
+	db, shutdownFunc := setupIdb(t, test.MakeGenesis(), test.MakeGenesisBlock())
+	defer shutdownFunc()
+
+	_, err := db.db.Exec(
+		context.Background(),
+		"CREATE TABLE t (x int PRIMARY KEY, n int NOT NULL DEFAULT 0); "+
+			"INSERT INTO t (x) SELECT generate_series(1, 10)")
+	require.NoError(t, err)
+
+	failAfter := 2
+	calls := 0
+	batch := func(tx pgx.Tx, cursor string) (string, uint64, bool, error) {
+		calls++
+		if calls > failAfter {
+			return "", 0, false, errors.New("interrupted")
+		}
+		after := 0
+		if cursor != "" {
+			var err error
+			after, err = strconv.Atoi(cursor)
+			if err != nil {
+				return "", 0, false, err
+			}
+		}
+		var last *int
+		err := tx.QueryRow(
+			context.Background(),
+			"WITH b AS (UPDATE t SET n = n + 1 WHERE x IN "+
+				"(SELECT x FROM t WHERE x > $1 ORDER BY x LIMIT 3) RETURNING x) "+
+				"SELECT max(x) FROM b",
+			after).Scan(&last)
+		if err != nil {
+			return "", 0, false, err
+		}
+		if last == nil {
+			return "", 0, true, nil
+		}
+		return strconv.Itoa(*last), 3, false, nil
+	}
+
+	processedBefore := testutil.ToFloat64(migrationProcessed.WithLabelValues("3"))
+
+	state := MigrationState{NextMigration: 3}
+	err = chunkedMigration(db, &state, 10, batch)
+	require.Error(t, err)
+
+	migrationState, err := db.getMigrationState()
+	require.NoError(t, err)
+	assert.Equal(t, 3, migrationState.NextMigration)
+	assert.Equal(t, "6", migrationState.Data)
+	require.NotNil(t, migrationState.Progress)
+	assert.Equal(t, uint64(6), migrationState.Progress.Processed)
+
+	failAfter = 100
+	err = chunkedMigration(db, &migrationState, 10, batch)
+	require.NoError(t, err)
+	assert.Equal(t, 4, migrationState.NextMigration)
+	assert.Equal(t, "", migrationState.Data)
+	assert.Nil(t, migrationState.Progress)
+
+	// Every row was processed exactly once.
+	assert.Equal(t, 10, queryInt(db.db, "SELECT count(*) FROM t WHERE n = 1"))
+	// The metric counts what the batches reported, 3 for each of the 4 batches.
+	assert.Equal(
+		t, float64(12),
+		testutil.ToFloat64(migrationProcessed.WithLabelValues("3"))-processedBefore)
+	migrationState, err = db.getMigrationState()
+	require.NoError(t, err)
+	assert.Equal(t, 4, migrationState.NextMigration)
+	assert.Equal(t, "", migrationState.Data)
+}
+
+// Test that queries use the replica until it falls behind the primary.
+func TestReadDBReplicaLag(t *testing.T) {
+	db, shutdownFunc := setupIdb(t, test.MakeGenesis(), test.MakeGenesisBlock())
+	defer shutdownFunc()
+
+	// The replica has the genesis but not the genesis block.
+	_, replicaConnStr, replicaShutdownFunc := pgtest.SetupPostgres(t)
+	defer replicaShutdownFunc()
+	replicaDb, _, err := OpenPostgres(replicaConnStr, idb.IndexerDbOptions{}, nil)
+	require.NoError(t, err)
+	err = replicaDb.LoadGenesis(test.MakeGenesis())
+	require.NoError(t, err)
+
+	// The primary is used until the replica was checked.
+	db.replica = makeReplica(replicaDb.db, 1)
+	assert.Equal(t, db.db, db.readDB())
+	db.replica.update(context.Background(), db.db, db.log)
+	assert.Equal(t, replicaDb.db, db.readDB())
+	assert.Equal(t, uint64(1), db.replica.lastLag())
+
+	db.replica.maxLag = 0
+	db.replica.update(context.Background(), db.db, db.log)
+	assert.Equal(t, db.db, db.readDB())
+
+	// A replica which can't be checked is not used.
+	db.replica.maxLag = 1
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+	db.replica.update(canceled, db.db, db.log)
+	assert.Equal(t, db.db, db.readDB())
+}
+
+// Test that monitor() checks the replica in the background.
+func TestReplicaMonitor(t *testing.T) {
+	db, shutdownFunc := setupIdb(t, test.MakeGenesis(), test.MakeGenesisBlock())
+	defer shutdownFunc()
+
+	db.replica = makeReplica(db.db, 0)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		db.replica.monitor(ctx, db.db, db.log)
+		close(done)
+	}()
+
+	for i := 0; atomic.LoadUint32(&db.replica.usable) == 0; i++ {
+		require.Less(t, i, 100, "the replica was not checked")
+		time.Sleep(10 * time.Millisecond)
+	}
+	cancel()
+	<-done
+}
+
+// poolMetric returns the value of the pool metric `name` of `pool`, and false if
+// it was not collected.
+func poolMetric(t *testing.T, name string, pool string) (float64, bool) {
+	families, err := prometheus.DefaultGatherer.Gather()
+	require.NoError(t, err)
+	for _, family := range families {
+		if family.GetName() != name {
+			continue
+		}
+		for _, metric := range family.GetMetric() {
+			if metric.GetLabel()[0].GetValue() != pool {
+				continue
+			}
+			if metric.GetCounter() != nil {
+				return metric.GetCounter().GetValue(), true
+			}
+			return metric.GetGauge().GetValue(), true
+		}
+	}
+	return 0, false
+}
+
+// Test that the statistics of the connection pools and the lag of the replica
+// are collected.
+func TestPoolMetrics(t *testing.T) {
+	db, shutdownFunc := setupIdb(t, test.MakeGenesis(), test.MakeGenesisBlock())
+	defer shutdownFunc()
+
+	maxConns, ok := poolMetric(t, "indexer_daemon_postgres_pool_max_conns", primaryPoolLabel)
+	require.True(t, ok)
+	assert.Equal(t, float64(db.db.Stat().MaxConns()), maxConns)
+	acquires, ok := poolMetric(t, "indexer_daemon_postgres_pool_acquires_total", primaryPoolLabel)
+	require.True(t, ok)
+	assert.Greater(t, acquires, float64(0))
+	_, ok = poolMetric(t, "indexer_daemon_postgres_pool_idle_conns", replicaPoolLabel)
+	assert.False(t, ok)
+
+	_, replicaConnStr, replicaShutdownFunc := pgtest.SetupPostgres(t)
+	defer replicaShutdownFunc()
+	replicaDb, _, err := OpenPostgres(replicaConnStr, idb.IndexerDbOptions{}, nil)
+	require.NoError(t, err)
+	err = replicaDb.LoadGenesis(test.MakeGenesis())
+	require.NoError(t, err)
+	db.replica = makeReplica(replicaDb.db, 1)
+	db.replica.update(context.Background(), db.db, db.log)
+	setPoolMetricsDB(db)
+
+	_, ok = poolMetric(t, "indexer_daemon_postgres_pool_idle_conns", replicaPoolLabel)
+	assert.True(t, ok)
+	// The test replica is not a standby.
+	assert.Equal(t, 0.0, testutil.ToFloat64(replicaLagSeconds.WithLabelValues()))
+	assert.Equal(t, 1.0, testutil.ToFloat64(replicaLag.WithLabelValues()))
+}
+
+// Test that PruneTransactions() deletes transactions in batches and keeps the
+// block headers.
+func TestPruneTransactions(t *testing.T) {
+	db, shutdownFunc := setupIdb(t, test.MakeGenesis(), test.MakeGenesisBlock())
+	defer shutdownFunc()
+
+	header := test.MakeGenesisBlock().BlockHeader
+	for i := 0; i < 3; i++ {
+		payment := test.MakePaymentTxn(
+			1000, uint64(i+1), 0, 0, 0, 0, test.AccountA, test.AccountB, basics.Address{},
+			basics.Address{})
+		block, err := test.MakeBlockForTxns(header, &payment)
+		require.NoError(t, err)
+		err = db.AddBlock(&block)
+		require.NoError(t, err)
+		header = block.BlockHeader
+	}
+
+	retention, err := db.GetRetention(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, idb.Retention{}, retention)
+
+	earliest, err := db.PruneTransactions(context.Background(), 3, 2)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(2), earliest)
+	assert.Equal(t, 2, queryInt(db.db, "SELECT count(*) FROM txn"))
+
+	earliest, err = db.PruneTransactions(context.Background(), 3, 2)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(3), earliest)
+	assert.Equal(t, 1, queryInt(db.db, "SELECT count(*) FROM txn WHERE round = 3"))
+	assert.Equal(t, 1, queryInt(db.db, "SELECT count(*) FROM txn"))
+	assert.Equal(t, 0, queryInt(db.db, "SELECT count(*) FROM txn_participation WHERE round < 3"))
+	assert.Equal(t, 4, queryInt(db.db, "SELECT count(*) FROM block_header"))
+
+	// Rounds which have not been imported are not pruned.
+	earliest, err = db.PruneTransactions(context.Background(), 100, 0)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(4), earliest)
+
+	retention, err = db.GetRetention(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, idb.Retention{EarliestRound: 4, EarliestParticipationRound: 4}, retention)
+}
+
+// Test that PruneParticipation() archives and deletes only the participation rows.
+func TestPruneParticipation(t *testing.T) {
+	db, shutdownFunc := setupIdb(t, test.MakeGenesis(), test.MakeGenesisBlock())
+	defer shutdownFunc()
+
+	header := test.MakeGenesisBlock().BlockHeader
+	for i := 0; i < 3; i++ {
+		payment := test.MakePaymentTxn(
+			1000, uint64(i+1), 0, 0, 0, 0, test.AccountA, test.AccountB, basics.Address{},
+			basics.Address{})
+		block, err := test.MakeBlockForTxns(header, &payment)
+		require.NoError(t, err)
+		err = db.AddBlock(&block)
+		require.NoError(t, err)
+		header = block.BlockHeader
+	}
+
+	var archive bytes.Buffer
+	earliest, err := db.PruneParticipation(context.Background(), 3, 0, &archive)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(3), earliest)
+
+	// Two rows, sender and receiver, for each of rounds 1 and 2.
+	lines := strings.Split(strings.TrimSpace(archive.String()), "\n")
+	require.Len(t, lines, 4)
+	assert.True(t, strings.HasPrefix(lines[0], `\x`), lines[0])
+	assert.Contains(t, lines[0], ",1,0")
+
+	assert.Equal(t, 0, queryInt(db.db, "SELECT count(*) FROM txn_participation WHERE round < 3"))
+	assert.Equal(t, 2, queryInt(db.db, "SELECT count(*) FROM txn_participation"))
+	assert.Equal(t, 3, queryInt(db.db, "SELECT count(*) FROM txn"))
+
+	retention, err := db.GetRetention(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, idb.Retention{EarliestParticipationRound: 3}, retention)
+
+	// Nothing is left to prune.
+	archive.Reset()
+	earliest, err = db.PruneParticipation(context.Background(), 3, 0, &archive)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(3), earliest)
+	assert.Equal(t, 0, archive.Len())
+}
+
+// Test that ListenRounds() reports the latest round and then each imported round.
+func TestListenRounds(t *testing.T) {
+	db, shutdownFunc := setupIdb(t, test.MakeGenesis(), test.MakeGenesisBlock())
+	defer shutdownFunc()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	rounds := make(chan uint64, 10)
+	done := make(chan error)
+	go func() {
+		done <- db.ListenRounds(ctx, func(round uint64) { rounds <- round })
+	}()
+	assert.Equal(t, uint64(0), <-rounds)
+
+	block, err := test.MakeBlockForTxns(test.MakeGenesisBlock().BlockHeader)
+	require.NoError(t, err)
+	err = db.AddBlock(&block)
+	require.NoError(t, err)
+
+	select {
+	case round := <-rounds:
+		assert.Equal(t, uint64(1), round)
+	case <-time.After(5 * time.Second):
+		t.Fatal("no notification")
+	}
+
+	cancel()
+	assert.NoError(t, <-done)
+}
+
+// Test that indexers in different schemas of a database are independent.
+func TestSchemas(t *testing.T) {
+	_, connStr, shutdownFunc := pgtest.SetupPostgres(t)
+	defer shutdownFunc()
+
+	open := func(schema string) *IndexerDb {
+		db, _, err := OpenPostgres(connStr, idb.IndexerDbOptions{Schema: schema}, nil)
+		require.NoError(t, err)
+		err = db.LoadGenesis(test.MakeGenesis())
+		require.NoError(t, err)
+		return db
+	}
+	db1 := open("network_one")
+	db2 := open("network_two")
+
+	block := test.MakeGenesisBlock()
+	err := db1.AddBlock(&block)
+	require.NoError(t, err)
+
+	nextRound, err := db1.GetNextRoundToAccount()
+	require.NoError(t, err)
+	assert.Equal(t, uint64(1), nextRound)
+	nextRound, err = db2.GetNextRoundToAccount()
+	require.NoError(t, err)
+	assert.Equal(t, uint64(0), nextRound)
+
+	assert.Equal(t, 2, queryInt(db1.db,
+		"SELECT count(*) FROM information_schema.tables WHERE table_name = 'txn'"))
+
+	// Opening a schema again does not set it up again.
+	db1, _, err = OpenPostgres(connStr, idb.IndexerDbOptions{Schema: "network_one"}, nil)
+	require.NoError(t, err)
+	nextRound, err = db1.GetNextRoundToAccount()
+	require.NoError(t, err)
+	assert.Equal(t, uint64(1), nextRound)
+
+	_, _, err = OpenPostgres(connStr, idb.IndexerDbOptions{Schema: "a; DROP"}, nil)
+	assert.Error(t, err)
+}
+
+// Test that PgBouncer compatibility imports blocks without prepared statements.
+func TestPgBouncerCompat(t *testing.T) {
+	_, connStr, shutdownFunc := pgtest.SetupPostgres(t)
+	defer shutdownFunc()
+
+	opts := idb.IndexerDbOptions{MaxConn: 1, PgBouncerCompat: true}
+	db, _, err := OpenPostgres(connStr, opts, nil)
+	require.NoError(t, err)
+	err = db.LoadGenesis(test.MakeGenesis())
+	require.NoError(t, err)
+
+	block := test.MakeGenesisBlock()
+	err = db.AddBlock(&block)
+	require.NoError(t, err)
+	txn := test.MakePaymentTxn(
+		1000, 10000, 0, 0, 0, 0, test.AccountA, test.AccountB, basics.Address{},
+		basics.Address{})
+	block, err = test.MakeBlockForTxns(block.BlockHeader, &txn)
+	require.NoError(t, err)
+	err = db.AddBlock(&block)
+	require.NoError(t, err)
+
+	nextRound, err := db.GetNextRoundToAccount()
+	require.NoError(t, err)
+	assert.Equal(t, uint64(2), nextRound)
+	assert.Equal(t, 0, queryInt(db.db, "SELECT count(*) FROM pg_prepared_statements"))
+
+	err = db.ListenRounds(context.Background(), func(uint64) {})
+	assert.Error(t, err)
+
+	opts.StatementTimeout = time.Second
+	_, _, err = OpenPostgres(connStr, opts, nil)
+	assert.Error(t, err)
+}
+
+// querySamples returns the number of queries named `name` and their total rows.
+func querySamples(t *testing.T, name string) (uint64, float64) {
+	families, err := prometheus.DefaultGatherer.Gather()
+	require.NoError(t, err)
+	for _, family := range families {
+		if family.GetName() != "indexer_daemon_postgres_query_rows" {
+			continue
+		}
+		for _, metric := range family.GetMetric() {
+			for _, label := range metric.GetLabel() {
+				if label.GetName() == "query" && label.GetValue() == name {
+					return metric.GetHistogram().GetSampleCount(), metric.GetHistogram().GetSampleSum()
+				}
+			}
+		}
+	}
+	return 0, 0
+}
+
+// Test that the API queries record their metrics when their rows are read.
+func TestQueryMetrics(t *testing.T) {
+	db, shutdownFunc := setupIdb(t, test.MakeGenesis(), test.MakeGenesisBlock())
+	defer shutdownFunc()
+
+	txnA := test.MakePaymentTxn(
+		1000, 10000, 0, 0, 0, 0, test.AccountA, test.AccountB, basics.Address{},
+		basics.Address{})
+	txnB := test.MakePaymentTxn(
+		1000, 20000, 0, 0, 0, 0, test.AccountB, test.AccountA, basics.Address{},
+		basics.Address{})
+	block, err := test.MakeBlockForTxns(test.MakeGenesisBlock().BlockHeader, &txnA, &txnB)
+	require.NoError(t, err)
+	err = db.AddBlock(&block)
+	require.NoError(t, err)
+
+	count, sum := querySamples(t, transactionsQueryName)
+	rowsCh, _ := db.Transactions(context.Background(), idb.TransactionFilter{})
+	for row := range rowsCh {
+		require.NoError(t, row.Error)
+	}
+	newCount, newSum := querySamples(t, transactionsQueryName)
+	assert.Equal(t, count+1, newCount)
+	assert.Equal(t, sum+2, newSum)
+
+	count, _ = querySamples(t, blockHeaderQueryName)
+	_, _, err = db.GetBlock(context.Background(), 1, idb.GetBlockOptions{})
+	require.NoError(t, err)
+	newCount, _ = querySamples(t, blockHeaderQueryName)
+	assert.Equal(t, count+1, newCount)
+}
+
+// Test that the tables are maintained when they are due.
+func TestMaintainTables(t *testing.T) {
+	db, shutdownFunc := setupIdb(t, test.MakeGenesis(), test.MakeGenesisBlock())
+	defer shutdownFunc()
+
+	// Nothing changed this much.
+	tables, err := db.maintainTables(
+		context.Background(), idb.MaintenanceOptions{ChangeThreshold: 1 << 40})
+	require.NoError(t, err)
+	assert.Empty(t, tables)
+
+	// The new tables were never analyzed.
+	tables, err = db.maintainTables(
+		context.Background(), idb.MaintenanceOptions{Interval: time.Hour, Vacuum: true})
+	require.NoError(t, err)
+	assert.ElementsMatch(t, maintainedTables, tables)
+
+	err = db.RunMaintenance(context.Background(), idb.MaintenanceOptions{})
+	assert.Error(t, err)
+}
+
+// Test that a snapshot imported into an empty database has the same rows.
+func TestSnapshot(t *testing.T) {
+	_, connStr, shutdownFunc := pgtest.SetupPostgres(t)
+	defer shutdownFunc()
+
+	db, _, err := OpenPostgres(connStr, idb.IndexerDbOptions{}, nil)
+	require.NoError(t, err)
+	err = db.LoadGenesis(test.MakeGenesis())
+	require.NoError(t, err)
+	block := test.MakeGenesisBlock()
+	err = db.AddBlock(&block)
+	require.NoError(t, err)
+	txn := test.MakePaymentTxn(
+		1000, 10000, 0, 0, 0, 0, test.AccountA, test.AccountB, basics.Address{},
+		basics.Address{})
+	block, err = test.MakeBlockForTxns(block.BlockHeader, &txn)
+	require.NoError(t, err)
+	err = db.AddBlock(&block)
+	require.NoError(t, err)
+
+	var snapshot bytes.Buffer
+	nextRound, err := db.ExportSnapshot(context.Background(), &snapshot)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(2), nextRound)
+
+	restored, _, err := OpenPostgres(connStr, idb.IndexerDbOptions{Schema: "restored"}, nil)
+	require.NoError(t, err)
+	nextRound, err = restored.ImportSnapshot(
+		context.Background(), bytes.NewReader(snapshot.Bytes()))
+	require.NoError(t, err)
+	assert.Equal(t, uint64(2), nextRound)
+
+	nextRound, err = restored.GetNextRoundToAccount()
+	require.NoError(t, err)
+	assert.Equal(t, uint64(2), nextRound)
+	for _, table := range snapshotTables {
+		query := "SELECT count(*) FROM " + table
+		assert.Equal(t, queryInt(db.db, query), queryInt(restored.db, query), table)
+	}
+
+	// Only an empty database can be restored.
+	_, err = restored.ImportSnapshot(context.Background(), bytes.NewReader(snapshot.Bytes()))
+	assert.Error(t, err)
+}
+
+// Test that DumpCSV() writes the current rows of each table.
+func TestDumpCSV(t *testing.T) {
+	db, shutdownFunc := setupIdb(t, test.MakeGenesis(), test.MakeGenesisBlock())
+	defer shutdownFunc()
+
+	create := test.MakeConfigAssetTxn(0, 100, 0, false, "UNIT", "Asset", "https://example.com", test.AccountA)
+	block, err := test.MakeBlockForTxns(test.MakeGenesisBlock().BlockHeader, &create)
+	require.NoError(t, err)
+	require.NoError(t, db.AddBlock(&block))
+
+	dump := func(table string) [][]string {
+		var buf bytes.Buffer
+		round, err := db.DumpCSV(context.Background(), table, &buf)
+		require.NoError(t, err)
+		assert.Equal(t, uint64(1), round)
+		records, err := csv.NewReader(&buf).ReadAll()
+		require.NoError(t, err)
+		return records
+	}
+
+	accounts := dump(idb.DumpAccounts)
+	assert.Equal(t, "address", accounts[0][0])
+	addresses := make([]string, 0, len(accounts)-1)
+	for _, record := range accounts[1:] {
+		addresses = append(addresses, record[0])
+	}
+	assert.Contains(t, addresses, test.AccountA.String())
+	assert.Contains(t, addresses, test.AccountD.String())
+
+	assets := dump(idb.DumpAssets)
+	require.Len(t, assets, 2)
+	assert.Equal(t, test.AccountA.String(), assets[1][1])
+	assert.Equal(t, []string{"Asset", "UNIT", "https://example.com", "100", "0", "false"}, assets[1][2:8])
+	a := test.AccountA.String()
+	assert.Equal(t, []string{a, a, a, a, "1"}, assets[1][8:])
+
+	balances := dump(idb.DumpBalances)
+	require.Len(t, balances, 2)
+	assert.Equal(t, []string{test.AccountA.String(), assets[1][0], "100", "false", "1"}, balances[1])
+
+	_, err = db.DumpCSV(context.Background(), "txn", ioutil.Discard)
+	assert.Error(t, err)
+}
+
+// Test that Rederive() finds and repairs a corrupted account.
+func TestRederive(t *testing.T) {
+	db, shutdownFunc := setupIdb(t, test.MakeGenesis(), test.MakeGenesisBlock())
+	defer shutdownFunc()
+
+	txn := test.MakePaymentTxn(
+		1000, 10000, 0, 0, 0, 0, test.AccountA, test.AccountB, basics.Address{},
+		basics.Address{})
+	block, err := test.MakeBlockForTxns(test.MakeGenesisBlock().BlockHeader, &txn)
+	require.NoError(t, err)
+	err = db.AddBlock(&block)
+	require.NoError(t, err)
+
+	differences := func(results []idb.RederivedTable) int64 {
+		var n int64
+		for _, result := range results {
+			n += result.Missing + result.Extra
+		}
+		return n
+	}
+	balanceA := func() int {
+		return queryInt(
+			db.db, "SELECT microalgos FROM account WHERE addr = $1", test.AccountA[:])
+	}
+
+	results, err := db.Rederive(context.Background(), test.MakeGenesis(), false)
+	require.NoError(t, err)
+	assert.Len(t, results, len(rederivedTables))
+	assert.Equal(t, int64(0), differences(results))
+
+	expected := balanceA()
+	_, err = db.db.Exec(
+		context.Background(), "UPDATE account SET microalgos = 1 WHERE addr = $1",
+		test.AccountA[:])
+	require.NoError(t, err)
+
+	// Without repairing, the tables are kept.
+	results, err = db.Rederive(context.Background(), test.MakeGenesis(), false)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), differences(results))
+	assert.Equal(t, 1, balanceA())
+
+	results, err = db.Rederive(context.Background(), test.MakeGenesis(), true)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), differences(results))
+	assert.Equal(t, expected, balanceA())
+
+	nextRound, err := db.GetNextRoundToAccount()
+	require.NoError(t, err)
+	assert.Equal(t, uint64(2), nextRound)
+}
+
+// Test that Reprocess() replaces the transactions of a round and repairs the
+// accounts which they changed.
+func TestReprocess(t *testing.T) {
+	db, shutdownFunc := setupIdb(t, test.MakeGenesis(), test.MakeGenesisBlock())
+	defer shutdownFunc()
+
+	txn := test.MakePaymentTxn(
+		1000, 10000, 0, 0, 0, 0, test.AccountA, test.AccountB, basics.Address{},
+		basics.Address{})
+	block, err := test.MakeBlockForTxns(test.MakeGenesisBlock().BlockHeader, &txn)
+	require.NoError(t, err)
+	err = db.AddBlock(&block)
+	require.NoError(t, err)
+
+	balance := func(address basics.Address) int {
+		return queryInt(
+			db.db, "SELECT microalgos FROM account WHERE addr = $1", address[:])
+	}
+	balanceB := balance(test.AccountB)
+	balanceC := balance(test.AccountC)
+
+	replacementTxn := test.MakePaymentTxn(
+		1000, 20000, 0, 0, 0, 0, test.AccountA, test.AccountC, basics.Address{},
+		basics.Address{})
+	replacement, err := test.MakeBlockForTxns(
+		test.MakeGenesisBlock().BlockHeader, &replacementTxn)
+	require.NoError(t, err)
+
+	results, err := db.Reprocess(
+		context.Background(), test.MakeGenesis(), []*bookkeeping.Block{&replacement})
+	require.NoError(t, err)
+	var differences int64
+	for _, result := range results {
+		differences += result.Missing + result.Extra
+	}
+	assert.Greater(t, differences, int64(0))
+
+	assert.Equal(t, balanceB-10000, balance(test.AccountB))
+	assert.Equal(t, balanceC+20000, balance(test.AccountC))
+	var txid string
+	row := db.db.QueryRow(context.Background(), "SELECT txid FROM txn WHERE round = 1")
+	require.NoError(t, row.Scan(&txid))
+	assert.Equal(t, replacementTxn.Txn.ID().String(), txid)
+	assert.Equal(t, 0, queryInt(
+		db.db, "SELECT count(*) FROM txn_participation WHERE addr = $1", test.AccountB[:]))
+
+	nextRound, err := db.GetNextRoundToAccount()
+	require.NoError(t, err)
+	assert.Equal(t, uint64(2), nextRound)
+}
+
+// Test that Rederive() refuses to rebuild the state from blocks which the
+// transaction rules filtered, also after the rules were removed.
+func TestRederiveTxnRules(t *testing.T) {
+	db, shutdownFunc := setupIdb(t, test.MakeGenesis(), test.MakeGenesisBlock())
+	defer shutdownFunc()
+
+	rules, err := idb.MakeTxnRules(idb.TxnRulesConfig{ExcludeUnmatched: true})
+	require.NoError(t, err)
+	db.txnRules = rules
+
+	txn := test.MakePaymentTxn(
+		1000, 10000, 0, 0, 0, 0, test.AccountA, test.AccountB, basics.Address{},
+		basics.Address{})
+	block, err := test.MakeBlockForTxns(test.MakeGenesisBlock().BlockHeader, &txn)
+	require.NoError(t, err)
+	err = db.AddBlock(&block)
+	require.NoError(t, err)
+	assert.Equal(t, 0, queryInt(db.db, "SELECT count(*) FROM txn"))
+
+	_, err = db.Rederive(context.Background(), test.MakeGenesis(), true)
+	assert.Error(t, err)
+
+	db.txnRules = nil
+	_, err = db.Rederive(context.Background(), test.MakeGenesis(), true)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "transaction rules")
+	_, err = db.Reprocess(context.Background(), test.MakeGenesis(), []*bookkeeping.Block{&block})
+	assert.Error(t, err)
+}
+
+// Test that the address scope is recorded, and that Rederive() and Reprocess()
+// refuse to rebuild the state of a scoped database.
+func TestRederiveAddressScope(t *testing.T) {
+	db, shutdownFunc := setupIdb(t, test.MakeGenesis(), test.MakeGenesisBlock())
+	defer shutdownFunc()
+
+	scope, err := idb.ParseAddressScope([]string{test.AccountC.String()})
+	require.NoError(t, err)
+	db.addressScope = scope
+
+	txn := test.MakePaymentTxn(
+		1000, 10000, 0, 0, 0, 0, test.AccountA, test.AccountB, basics.Address{},
+		basics.Address{})
+	block, err := test.MakeBlockForTxns(test.MakeGenesisBlock().BlockHeader, &txn)
+	require.NoError(t, err)
+	err = db.AddBlock(&block)
+	require.NoError(t, err)
+
+	importstate, err := db.getImportState(context.Background(), nil)
+	require.NoError(t, err)
+	assert.Equal(t, []string{test.AccountC.String()}, importstate.AddressScope)
+
+	_, err = db.Rederive(context.Background(), test.MakeGenesis(), false)
+	assert.Error(t, err)
+
+	db.addressScope = nil
+	_, err = db.Rederive(context.Background(), test.MakeGenesis(), true)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), test.AccountC.String())
+	_, err = db.Reprocess(context.Background(), test.MakeGenesis(), []*bookkeeping.Block{&block})
+	assert.Error(t, err)
+}
+
+// Test that CockroachDB compatibility imports blocks without notifying the
+// round, the tables of postgres are used since CockroachDB is not available.
+func TestCockroachCompat(t *testing.T) {
+	db, shutdownFunc := setupIdb(t, test.MakeGenesis(), test.MakeGenesisBlock())
+	defer shutdownFunc()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	rounds := make(chan uint64, 10)
+	done := make(chan error)
+	go func() {
+		done <- db.ListenRounds(ctx, func(round uint64) { rounds <- round })
+	}()
+	assert.Equal(t, uint64(0), <-rounds)
+
+	db.cockroachCompat = true
+	block, err := test.MakeBlockForTxns(test.MakeGenesisBlock().BlockHeader)
+	require.NoError(t, err)
+	err = db.AddBlock(&block)
+	require.NoError(t, err)
+
+	select {
+	case round := <-rounds:
+		t.Fatalf("round %d notified", round)
+	case <-time.After(time.Second):
+	}
+	cancel()
+	assert.NoError(t, <-done)
+
+	nextRound, err := db.GetNextRoundToAccount()
+	require.NoError(t, err)
+	assert.Equal(t, uint64(2), nextRound)
+
+	err = db.ListenRounds(context.Background(), func(uint64) {})
+	assert.Error(t, err)
+	_, err = db.GetServerSettings(context.Background())
+	assert.Error(t, err)
+	err = db.RunMaintenance(
+		context.Background(), idb.MaintenanceOptions{Interval: time.Hour})
+	assert.Error(t, err)
+}
+
+// Test that GetBlockWithTransactions() returns the same as GetBlock(), also for
+// a block without transactions.
+func TestGetBlockWithTransactions(t *testing.T) {
+	_, connStr, shutdownFunc := pgtest.SetupPostgres(t)
+	defer shutdownFunc()
+	db, _, err := OpenPostgres(connStr, idb.IndexerDbOptions{}, nil)
+	require.NoError(t, err)
+	err = test.ImportFixture(db)
+	require.NoError(t, err)
+
+	for round := uint64(0); round <= 2; round++ {
+		expectedHeader, expectedTxns, err :=
+			db.GetBlock(context.Background(), round, idb.GetBlockOptions{Transactions: true})
+		require.NoError(t, err)
+
+		header, txns, err := db.GetBlockWithTransactions(context.Background(), round)
+		require.NoError(t, err)
+		assert.Equal(t, expectedHeader, header)
+		require.Len(t, txns, len(expectedTxns))
+		for i := range txns {
+			assert.Equal(t, expectedTxns[i].Intra, txns[i].Intra)
+			assert.Equal(t, expectedTxns[i].TxnBytes, txns[i].TxnBytes)
+			assert.Equal(t, expectedTxns[i].Extra, txns[i].Extra)
+			assert.Equal(t, expectedTxns[i].AssetID, txns[i].AssetID)
+			assert.True(t, expectedTxns[i].RoundTime.Equal(txns[i].RoundTime))
+		}
+	}
+
+	_, _, err = db.GetBlockWithTransactions(context.Background(), 3)
+	assert.Error(t, err)
+}
+
+// Test that the connection of a query is returned to the pool when the consumer
+// stops reading and the context is canceled.
+func TestAbandonedQueryReleasesConnection(t *testing.T) {
+	_, connStr, shutdownFunc := pgtest.SetupPostgres(t)
+	defer shutdownFunc()
+	db, _, err := OpenPostgres(connStr, idb.IndexerDbOptions{}, nil)
+	require.NoError(t, err)
+	err = test.ImportFixture(db)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	txnCh, _ := db.Transactions(ctx, idb.TransactionFilter{})
+	accountCh, _ := db.GetAccounts(ctx, idb.AccountQueryOptions{})
+	<-txnCh
+	<-accountCh
+	cancel()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for db.db.Stat().AcquiredConns() > 0 {
+		if time.Now().After(deadline) {
+			t.Fatalf("%d connections still acquired", db.db.Stat().AcquiredConns())
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestHealthDiagnostics(t *testing.T) {
+	_, connStr, shutdownFunc := pgtest.SetupPostgres(t)
+	defer shutdownFunc()
+	db, _, err := OpenPostgres(connStr, idb.IndexerDbOptions{}, nil)
+	require.NoError(t, err)
+	err = test.ImportFixture(db)
+	require.NoError(t, err)
+	_, err = db.PruneTransactions(context.Background(), 2, 0)
+	require.NoError(t, err)
+
+	health, err := db.Health()
+	require.NoError(t, err)
+	require.NotNil(t, health.Data)
+	data := *health.Data
+
+	assert.Equal(t, uint64(2), health.Round)
+	assert.Equal(t, uint64(2), data["earliest-round"])
+	assert.Equal(t, len(migrations), data["migration-next"])
+	assert.Equal(t, len(migrations), data["migration-count"])
+	assert.Contains(t, data, "oldest-query-seconds")
+	require.Contains(t, data, "pool")
+	pool := data["pool"].(map[string]interface{})
+	assert.Equal(t, db.db.Stat().MaxConns(), pool["max-conns"])
+}
+
+// Test that the last migration is rolled back and runs again on the next start.
+func TestRollbackMigrations(t *testing.T) {
+	_, connStr, shutdownFunc := pgtest.SetupPostgres(t)
+	defer shutdownFunc()
+	db, availableCh, err := OpenPostgres(connStr, idb.IndexerDbOptions{}, nil)
+	require.NoError(t, err)
+	<-availableCh
+
+	// The oldest migrations can't be rolled back.
+	_, err = db.RollbackMigrations(context.Background(), len(migrations))
+	require.Error(t, err)
+	state, err := db.getMigrationState()
+	require.NoError(t, err)
+	assert.Equal(t, len(migrations), state.NextMigration)
+
+	rolledBack, err := db.RollbackMigrations(context.Background(), 1)
+	require.NoError(t, err)
+	assert.Equal(t, []string{migrations[len(migrations)-1].description}, rolledBack)
+	state, err = db.getMigrationState()
+	require.NoError(t, err)
+	assert.Equal(t, len(migrations)-1, state.NextMigration)
+	assert.Equal(t, 0, queryInt(db.db,
+		"SELECT count(*) FROM information_schema.columns "+
+			"WHERE table_name = 'txn' AND column_name = 'txnbytes_len'"))
+	db.db.Close()
+
+	// The migration is pending again and runs on the next start.
+	db, _, err = OpenPostgres(connStr, idb.IndexerDbOptions{SkipMigrations: true}, nil)
+	require.NoError(t, err)
+	state, err = db.getMigrationState()
+	require.NoError(t, err)
+	assert.Equal(t, len(migrations)-1, state.NextMigration)
+	db.db.Close()
+
+	db, availableCh, err = OpenPostgres(connStr, idb.IndexerDbOptions{}, nil)
+	require.NoError(t, err)
+	defer db.db.Close()
+	<-availableCh
+	for db.migration.GetStatus().Running {
+		time.Sleep(10 * time.Millisecond)
+	}
+	state, err = db.getMigrationState()
+	require.NoError(t, err)
+	assert.Equal(t, len(migrations), state.NextMigration)
+	assert.Equal(t, 1, queryInt(db.db,
+		"SELECT count(*) FROM information_schema.columns "+
+			"WHERE table_name = 'txn' AND column_name = 'txnbytes_len'"))
+}
+
+// Test that Migrations() reports the rolled back migration as pending.
+func TestMigrationsList(t *testing.T) {
+	db, shutdownFunc := setupIdb(t, test.MakeGenesis(), test.MakeGenesisBlock())
+	defer shutdownFunc()
+
+	infos, err := db.Migrations(context.Background())
+	require.NoError(t, err)
+	require.Len(t, infos, len(migrations))
+	for i, info := range infos {
+		assert.Equal(t, i, info.ID)
+		assert.True(t, info.Applied)
+		assert.Equal(t, migrations[i].blocking, info.Blocking)
+	}
+	last := len(migrations) - 1
+	assert.True(t, infos[last].Reversible)
+	assert.False(t, infos[0].Reversible)
+
+	_, err = db.RollbackMigrations(context.Background(), 1)
+	require.NoError(t, err)
+	infos, err = db.Migrations(context.Background())
+	require.NoError(t, err)
+	assert.False(t, infos[last].Applied)
+	assert.True(t, infos[last-1].Applied)
+}
+
+// Test that the shards of an interrupted parallelMigration() resume after
+// their last committed batch.
+func TestParallelMigrationResumes(t *testing.T) {
+	db, shutdownFunc := setupIdb(t, test.MakeGenesis(), test.MakeGenesisBlock())
+	defer shutdownFunc()
+	db.migrationWorkers = 3
+
+	_, err := db.db.Exec(
+		context.Background(),
+		"CREATE TABLE t (x int PRIMARY KEY, n int NOT NULL DEFAULT 0); "+
+			"INSERT INTO t (x) SELECT generate_series(0, 99)")
+	require.NoError(t, err)
+
+	const shards = 4
+	var failShard int64 = 2
+	batch := func(tx pgx.Tx, shard int, cursor string) (string, uint64, bool, error) {
+		after := -1
+		if cursor != "" {
+			var err error
+			after, err = strconv.Atoi(cursor)
+			if err != nil {
+				return "", 0, false, err
+			}
+		}
+		if int64(shard) == atomic.LoadInt64(&failShard) && after >= 0 {
+			return "", 0, false, errors.New("interrupted")
+		}
+		var last *int
+		err := tx.QueryRow(
+			context.Background(),
+			"WITH b AS (UPDATE t SET n = n + 1 WHERE x IN "+
+				"(SELECT x FROM t WHERE x % $1 = $2 AND x > $3 ORDER BY x LIMIT 5) RETURNING x) "+
+				"SELECT max(x) FROM b",
+			shards, shard, after).Scan(&last)
+		if err != nil {
+			return "", 0, false, err
+		}
+		if last == nil {
+			return "", 0, true, nil
+		}
+		return strconv.Itoa(*last), 5, false, nil
+	}
+
+	state := MigrationState{NextMigration: 3}
+	err = parallelMigration(db, &state, shards, 100, batch)
+	require.Error(t, err)
+	migrationState, err := db.getMigrationState()
+	require.NoError(t, err)
+	assert.Equal(t, 3, migrationState.NextMigration)
+	assert.Equal(t, "shards:4", migrationState.Data)
+	// The first batch of the failing shard was committed.
+	checkpoint, err := db.getShardState(int(failShard))
+	require.NoError(t, err)
+	assert.Equal(t, uint64(5), checkpoint.Processed)
+
+	// A different sharding can't resume the checkpoints.
+	err = parallelMigration(db, &migrationState, shards+1, 100, batch)
+	require.Error(t, err)
+
+	atomic.StoreInt64(&failShard, -1)
+	err = parallelMigration(db, &migrationState, shards, 100, batch)
+	require.NoError(t, err)
+	assert.Equal(t, 4, migrationState.NextMigration)
+	assert.Equal(t, "", migrationState.Data)
+
+	// Every row was processed exactly once and the checkpoints are gone.
+	assert.Equal(t, 100, queryInt(db.db, "SELECT count(*) FROM t WHERE n = 1"))
+	assert.Equal(t, 0, queryInt(db.db, "SELECT count(*) FROM metastate WHERE k LIKE 'migration_shard_%'"))
+}
+
+// Test that checkMigration() refuses to start a migration whose tables are
+// locked by an old transaction or don't fit into the free disk space.
+func TestCheckMigration(t *testing.T) {
+	db, shutdownFunc := setupIdb(t, test.MakeGenesis(), test.MakeGenesisBlock())
+	defer shutdownFunc()
+	// The last migration which rewrites the account table.
+	id := -1
+	for i, m := range migrations {
+		for _, table := range m.tables {
+			if table == "account" {
+				id = i
+			}
+		}
+	}
+	require.NotEqual(t, -1, id)
+
+	require.NoError(t, db.checkMigration(context.Background(), id))
+
+	db.migrationDiskSpace = 1
+	err := db.checkMigration(context.Background(), id)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "free disk space")
+	db.migrationDiskSpace = 0
+
+	defer func(maxAge time.Duration) { migrationLockMaxAge = maxAge }(migrationLockMaxAge)
+	migrationLockMaxAge = 0
+	tx, err := db.db.Begin(context.Background())
+	require.NoError(t, err)
+	_, err = tx.Exec(context.Background(), "LOCK TABLE account IN ACCESS SHARE MODE")
+	require.NoError(t, err)
+	err = db.checkMigration(context.Background(), id)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "LOCK TABLE account")
+
+	require.NoError(t, tx.Rollback(context.Background()))
+	require.NoError(t, db.checkMigration(context.Background(), id))
+}
+
+// waitForMigrations waits until the migrations started by OpenPostgres()
+// finished and returns their final status.
+func waitForMigrations(t *testing.T, db *IndexerDb) migration.State {
+	deadline := time.Now().Add(10 * time.Second)
+	for {
+		state := db.migration.GetStatus()
+		if !state.Running && state.Status != migration.StatusPending {
+			return state
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("migrations still running: %s", state.Status)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// Test that the operator's scripts run once each, on new and existing
+// databases.
+func TestOperatorMigrations(t *testing.T) {
+	_, connStr, shutdownFunc := pgtest.SetupPostgres(t)
+	defer shutdownFunc()
+	dir, err := ioutil.TempDir("", "sql-migrations")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	writeScript := func(name, sql string) {
+		err := ioutil.WriteFile(filepath.Join(dir, name), []byte(sql), 0644)
+		require.NoError(t, err)
+	}
+	writeScript("002_counter.sql", "CREATE TABLE counter (n int); INSERT INTO counter VALUES (1)")
+	writeScript("001_index.sql",
+		"-- indexer:no-transaction\nCREATE INDEX CONCURRENTLY IF NOT EXISTS account_rewards ON account (rewardsbase)")
+	writeScript("README", "not a script")
+
+	opts := idb.IndexerDbOptions{SQLMigrationsDir: dir}
+	db, _, err := OpenPostgres(connStr, opts, nil)
+	require.NoError(t, err)
+	status := waitForMigrations(t, db)
+	require.NoError(t, status.Err)
+	assert.Equal(t, 1, queryInt(db.db, "SELECT count(*) FROM counter"))
+	assert.Equal(t, 1, queryInt(db.db,
+		"SELECT count(*) FROM pg_index WHERE indexrelid = to_regclass('account_rewards')"))
+	state, err := db.getOperatorMigrationState(context.Background(), nil)
+	require.NoError(t, err)
+	assert.Equal(t, []uint64{1, 2}, state.Applied)
+	db.db.Close()
+
+	// Only the new script runs on the next start.
+	writeScript("003_counter.sql", "INSERT INTO counter VALUES (3)")
+	db, _, err = OpenPostgres(connStr, opts, nil)
+	require.NoError(t, err)
+	defer db.db.Close()
+	status = waitForMigrations(t, db)
+	require.NoError(t, status.Err)
+	assert.Equal(t, 2, queryInt(db.db, "SELECT count(*) FROM counter"))
+
+	infos, err := db.Migrations(context.Background())
+	require.NoError(t, err)
+	require.Len(t, infos, len(migrations)+3)
+	last := infos[len(infos)-1]
+	assert.Equal(t, operatorMigrationIDBase+3, last.ID)
+	assert.True(t, last.Applied)
+
+	// Scripts with the same version are rejected.
+	writeScript("3_duplicate.sql", "SELECT 1")
+	_, _, err = OpenPostgres(connStr, opts, nil)
+	require.Error(t, err)
+}
+
+// Test that the account totals are written with each round and match the sum of
+// the accounts.
+func TestAccountTotals(t *testing.T) {
+	db, shutdownFunc := setupIdb(t, test.MakeGenesis(), test.MakeGenesisBlock())
+	defer shutdownFunc()
+
+	txn := test.MakePaymentTxn(
+		1000, 10000, 0, 0, 0, 0, test.AccountA, test.AccountB, basics.Address{},
+		basics.Address{})
+	block, err := test.MakeBlockForTxns(test.MakeGenesisBlock().BlockHeader, &txn)
+	require.NoError(t, err)
+	err = db.AddBlock(&block)
+	require.NoError(t, err)
+
+	var buf []byte
+	err = db.db.QueryRow(
+		context.Background(), "SELECT totals FROM account_totals WHERE round = 1").Scan(&buf)
+	require.NoError(t, err)
+	stored, err := encoding.DecodeAccountTotals(buf)
+	require.NoError(t, err)
+
+	// The fee went to the fee sink, which is not counted.
+	assert.Equal(t, uint64(4*1000*1000*1000*1000-1000), stored.Offline.Money.Raw)
+	assert.Equal(t, uint64(0), stored.Online.Money.Raw)
+
+	// Summing the account table gives the same totals.
+	tx, err := db.db.Begin(context.Background())
+	require.NoError(t, err)
+	defer tx.Rollback(context.Background())
+	_, err = tx.Exec(context.Background(), "DELETE FROM account_totals")
+	require.NoError(t, err)
+	specialAddresses := transactions.SpecialAddresses{
+		FeeSink:     test.FeeAddr,
+		RewardsPool: test.RewardAddr,
+	}
+	l, err := ledger_for_evaluator.MakeLedgerForEvaluator(
+		tx, test.GenesisHash, specialAddresses, nil)
+	require.NoError(t, err)
+	summed, err := l.Totals(1)
+	require.NoError(t, err)
+	assert.Equal(t, stored, summed)
+}
+
+func TestNormalizeSQL(t *testing.T) {
+	query := `SELECT t.round, t.txn FROM txn t
+		WHERE t.round >= $1 AND t.typeenum = 2 AND t.note = 'it''s 5'
+		ORDER BY t.round LIMIT 1000`
+	assert.Equal(t,
+		"SELECT t.round, t.txn FROM txn t WHERE t.round >= $1 AND t.typeenum = ? AND t.note = ? ORDER BY t.round LIMIT ?",
+		normalizeSQL(query))
+	assert.Equal(t, "SELECT * FROM txn_participation2 WHERE x = ?", normalizeSQL(" SELECT * FROM txn_participation2 WHERE x = 1.5 "))
+	assert.Equal(t, "$1=10 $2=abcdefghijklmnopqrstuvwxyz012345...", summarizeArgs([]interface{}{10, "abcdefghijklmnopqrstuvwxyz0123456789"}))
+}
+
+// Test that the queries which take longer than the threshold are logged with
+// the endpoint of their context.
+func TestSlowQueryLog(t *testing.T) {
+	_, connStr, shutdownFunc := pgtest.SetupPostgres(t)
+	defer shutdownFunc()
+	var buf bytes.Buffer
+	logger := log.New()
+	logger.SetOutput(&buf)
+	db, _, err := OpenPostgres(
+		connStr, idb.IndexerDbOptions{SlowQueryThreshold: time.Nanosecond}, logger)
+	require.NoError(t, err)
+	err = db.LoadGenesis(test.MakeGenesis())
+	require.NoError(t, err)
+	genesisBlock := test.MakeGenesisBlock()
+	err = db.AddBlock(&genesisBlock)
+	require.NoError(t, err)
+
+	buf.Reset()
+	ctx := idb.ContextWithEndpoint(context.Background(), "GET /v2/transactions")
+	rowsCh, _ := db.Transactions(ctx, idb.TransactionFilter{Limit: 10})
+	for row := range rowsCh {
+		require.NoError(t, row.Error)
+	}
+	assert.Contains(t, buf.String(), "slow query")
+	assert.Contains(t, buf.String(), `endpoint="GET /v2/transactions"`)
+	assert.Contains(t, buf.String(), "operation=Query")
+}
+
+// Test that the read transactions of a request have its ID in the
+// application_name.
+func TestBeginReadApplicationName(t *testing.T) {
+	db, shutdownFunc := setupIdb(t, test.MakeGenesis(), test.MakeGenesisBlock())
+	defer shutdownFunc()
+
+	ctx := idb.ContextWithRequestID(context.Background(), "4bf92f35")
+	tx, err := db.beginRead(ctx)
+	require.NoError(t, err)
+	var name string
+	err = tx.QueryRow(ctx, "SELECT current_setting('application_name')").Scan(&name)
+	require.NoError(t, err)
+	assert.Equal(t, "indexer 4bf92f35", name)
+	require.NoError(t, tx.Rollback(ctx))
+
+	// The name is reset with the transaction.
+	err = db.db.QueryRow(ctx, "SELECT current_setting('application_name')").Scan(&name)
+	require.NoError(t, err)
+	assert.NotEqual(t, "indexer 4bf92f35", name)
+}
+
+// Test that a block of another network is refused.
+func TestAddBlockGenesisMismatch(t *testing.T) {
+	db, shutdownFunc := setupIdb(t, test.MakeGenesis(), test.MakeGenesisBlock())
+	defer shutdownFunc()
+
+	genesisHash, err := db.GetGenesisHash()
+	require.NoError(t, err)
+	assert.Equal(t, test.MakeGenesisBlock().GenesisHash().String(), genesisHash)
+
+	block, err := test.MakeBlockForTxns(test.MakeGenesisBlock().BlockHeader)
+	require.NoError(t, err)
+	block.BlockHeader.GenesisHash = crypto.Hash([]byte("other network"))
+
+	err = db.AddBlock(&block)
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, idb.ErrorGenesisMismatch), err.Error())
+
+	nextRound, err := db.GetNextRoundToAccount()
+	require.NoError(t, err)
+	assert.Equal(t, uint64(1), nextRound)
+}
+
+// Test that every indexer which uses the database gets the same pagination
+// secret.
+func TestPaginationSecret(t *testing.T) {
+	db, shutdownFunc := setupIdb(t, test.MakeGenesis(), test.MakeGenesisBlock())
+	defer shutdownFunc()
+
+	secret, err := db.PaginationSecret(context.Background())
+	require.NoError(t, err)
+	assert.Len(t, secret, 32)
+
+	again, err := db.PaginationSecret(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, secret, again)
+}
+
+// Test that accounts are returned with their resource counts.
+func TestAccountResourceCounts(t *testing.T) {
+	db, shutdownFunc := setupIdb(t, test.MakeGenesis(), test.MakeGenesisBlock())
+	defer shutdownFunc()
+
+	createAsset := test.MakeConfigAssetTxn(
+		0, 100, 0, false, "UNIT", "Asset", "https://example.com", test.AccountD)
+	optInAsset := test.MakeAssetOptInTxn(1, test.AccountB)
+	createApp := test.MakeCreateAppTxn(test.AccountD)
+	optInApp := test.MakeAppOptInTxn(3, test.AccountB)
+	block, err := test.MakeBlockForTxns(
+		test.MakeGenesisBlock().BlockHeader, &createAsset, &optInAsset, &createApp,
+		&optInApp)
+	require.NoError(t, err)
+	err = db.AddBlock(&block)
+	require.NoError(t, err)
+
+	counts := func(address basics.Address) [4]uint64 {
+		rowsCh, _ := db.GetAccounts(
+			context.Background(), idb.AccountQueryOptions{EqualToAddress: address[:]})
+		row, ok := <-rowsCh
+		require.True(t, ok)
+		require.NoError(t, row.Error)
+		account := row.Account
+		require.NotNil(t, account.TotalAssetsOptedIn)
+		require.NotNil(t, account.TotalCreatedAssets)
+		require.NotNil(t, account.TotalAppsOptedIn)
+		require.NotNil(t, account.TotalCreatedApps)
+		return [4]uint64{
+			*account.TotalAssetsOptedIn, *account.TotalCreatedAssets,
+			*account.TotalAppsOptedIn, *account.TotalCreatedApps}
+	}
+	assert.Equal(t, [4]uint64{1, 1, 0, 1}, counts(test.AccountD))
+	assert.Equal(t, [4]uint64{1, 0, 1, 0}, counts(test.AccountB))
+	assert.Equal(t, [4]uint64{0, 0, 0, 0}, counts(test.AccountC))
+}
+
+// Test that FlushCaches() clears the accounts cached by the import.
+func TestFlushCachesAccountCache(t *testing.T) {
+	db, shutdownFunc := setupIdb(t, test.MakeGenesis(), test.MakeGenesisBlock())
+	defer shutdownFunc()
+	db.accountCache = ledger_for_evaluator.MakeCache(10)
+
+	payment := test.MakePaymentTxn(
+		1000, 1, 0, 0, 0, 0, test.AccountA, test.AccountB, basics.Address{},
+		basics.Address{})
+	block, err := test.MakeBlockForTxns(test.MakeGenesisBlock().BlockHeader, &payment)
+	require.NoError(t, err)
+	err = db.AddBlock(&block)
+	require.NoError(t, err)
+	require.Greater(t, db.accountCache.Len(), 0)
+
+	err = db.FlushCaches(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 0, db.accountCache.Len())
+}
@@ -0,0 +1,291 @@
+// You can build without postgres by `go build --tags nopostgres` but it's on by default
+//go:build !nopostgres
+// +build !nopostgres
+
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v4"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/algorand/indexer/idb"
+	pgutil "github.com/algorand/indexer/idb/postgres/internal/util"
+	"github.com/algorand/indexer/util/metrics"
+)
+
+// orphanCheck describes one kind of row that may be left behind by an interrupted
+// migration or repair. `where` selects the orphaned rows from `table`, which is
+// aliased as `o`. If `useRound` is set, `where` takes the next round to account
+// as its only parameter.
+type orphanCheck struct {
+	table       string
+	description string
+	where       string
+	useRound    bool
+}
+
+var orphanChecks = []orphanCheck{
+	{
+		table:       "txn_participation",
+		description: "participation rows for transactions which no longer exist",
+		where:       "NOT EXISTS (SELECT 1 FROM txn t WHERE t.round = o.round AND t.intra = o.intra)",
+	},
+	{
+		table:       "txn_participation",
+		description: "participation rows for rounds which have not been accounted",
+		where:       "o.round >= $1",
+		useRound:    true,
+	},
+	{
+		table:       "txn",
+		description: "transactions for rounds which have not been accounted",
+		where:       "o.round >= $1",
+		useRound:    true,
+	},
+	{
+		table:       "block_header",
+		description: "block headers for rounds which have not been accounted",
+		where:       "o.round >= $1",
+		useRound:    true,
+	},
+	{
+		table:       "account_asset",
+		description: "asset holdings for assets which were never created",
+		where:       "NOT EXISTS (SELECT 1 FROM asset a WHERE a.index = o.assetid)",
+	},
+	{
+		table:       "account_app",
+		description: "app local states for apps which were never created",
+		where:       "NOT EXISTS (SELECT 1 FROM app a WHERE a.index = o.app)",
+	},
+}
+
+// DeleteOrphanedRows is part of idb.IndexerDb.
+func (db *IndexerDb) DeleteOrphanedRows(ctx context.Context, dryRun bool) ([]idb.OrphanedRows, error) {
+	if db.readonly && !dryRun {
+		return nil, fmt.Errorf("DeleteOrphanedRows() cannot delete rows in read only mode")
+	}
+
+	// Hold the accounting lock so that a block being imported is not mistaken for
+	// an orphan.
+	db.accountingLock.Lock()
+	defer db.accountingLock.Unlock()
+	defer db.accountCache.Clear()
+
+	var results []idb.OrphanedRows
+	f := func(tx pgx.Tx) error {
+		defer tx.Rollback(ctx)
+
+		results = make([]idb.OrphanedRows, 0, len(orphanChecks))
+
+		nextRound, err := db.getNextRoundToAccount(ctx, tx)
+		roundKnown := true
+		if errors.Is(err, idb.ErrorNotInitialized) {
+			roundKnown = false
+		} else if err != nil {
+			return fmt.Errorf("DeleteOrphanedRows() err: %w", err)
+		}
+
+		for _, check := range orphanChecks {
+			args := make([]interface{}, 0, 1)
+			if check.useRound {
+				if !roundKnown {
+					continue
+				}
+				args = append(args, nextRound)
+			}
+
+			count, err := runOrphanCheck(ctx, tx, check, dryRun, args)
+			if err != nil {
+				return fmt.Errorf("DeleteOrphanedRows() err: %w", err)
+			}
+
+			results = append(results, idb.OrphanedRows{
+				Table:       check.table,
+				Description: check.description,
+				Count:       count,
+			})
+		}
+
+		if dryRun {
+			return nil
+		}
+		return tx.Commit(ctx)
+	}
+	err := db.txWithRetry(serializable, f)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, result := range results {
+		if result.Count > 0 {
+			db.log.Infof(
+				"%s: found %d %s", result.Table, result.Count, result.Description)
+		}
+	}
+
+	return results, nil
+}
+
+func runOrphanCheck(ctx context.Context, tx pgx.Tx, check orphanCheck, dryRun bool, args []interface{}) (int64, error) {
+	if dryRun {
+		query := fmt.Sprintf("SELECT count(*) FROM %s o WHERE %s", check.table, check.where)
+		var count int64
+		err := tx.QueryRow(ctx, query, args...).Scan(&count)
+		if err != nil {
+			return 0, fmt.Errorf("runOrphanCheck() count %s err: %w", check.table, err)
+		}
+		return count, nil
+	}
+
+	query := fmt.Sprintf("DELETE FROM %s o WHERE %s", check.table, check.where)
+	tag, err := tx.Exec(ctx, query, args...)
+	if err != nil {
+		return 0, fmt.Errorf("runOrphanCheck() delete %s err: %w", check.table, err)
+	}
+	return tag.RowsAffected(), nil
+}
+
+// FlushCaches is part of idb.IndexerDb. It clears the accounts cached by the
+// import, and the prepared statement caches and the statements of the block
+// import of the idle connections in the pool. Connections which are in use keep
+// their statements.
+func (db *IndexerDb) FlushCaches(ctx context.Context) error {
+	db.accountCache.Clear()
+
+	conns := db.db.AcquireAllIdle(ctx)
+	defer func() {
+		for _, conn := range conns {
+			conn.Release()
+		}
+	}()
+
+	for _, conn := range conns {
+		if cache := conn.Conn().StatementCache(); cache != nil {
+			err := cache.Clear(ctx)
+			if err != nil {
+				return fmt.Errorf("FlushCaches() err: %w", err)
+			}
+		}
+		err := pgutil.DeallocateStatements(ctx, conn.Conn())
+		if err != nil {
+			return fmt.Errorf("FlushCaches() err: %w", err)
+		}
+	}
+	return nil
+}
+
+// maintainedTables are the tables which the import changes, and whose planner
+// statistics autovacuum may not keep up with.
+var maintainedTables = []string{
+	"txn", "txn_participation", "account", "account_asset", "account_app", "asset",
+	"app", "block_header",
+}
+
+// maintenanceCheckInterval is how often RunMaintenance() checks the tables.
+const maintenanceCheckInterval = time.Minute
+
+var maintenanceRuns = metrics.DefaultRegistry.NewCounterVec(
+	"postgres_maintenance_total",
+	"Tables maintained by the indexer, by table and operation.",
+	"table", "operation")
+
+// RunMaintenance is part of idb.IndexerDb. Failures are logged and retried at
+// the next check.
+func (db *IndexerDb) RunMaintenance(ctx context.Context, opts idb.MaintenanceOptions) error {
+	if db.readonly {
+		return fmt.Errorf("RunMaintenance() cannot maintain tables in read only mode")
+	}
+	if opts.Interval == 0 && opts.ChangeThreshold == 0 {
+		return fmt.Errorf("RunMaintenance() neither an interval nor a change threshold is set")
+	}
+	if db.cockroachCompat {
+		// CockroachDB collects the table statistics by itself and has no
+		// pg_stat_user_tables.
+		return fmt.Errorf("RunMaintenance() not supported with CockroachDB compatibility")
+	}
+
+	interval := maintenanceCheckInterval
+	if opts.Interval != 0 && opts.Interval < interval {
+		interval = opts.Interval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		_, err := db.maintainTables(ctx, opts)
+		if err != nil && ctx.Err() == nil {
+			db.log.WithError(err).Warn("RunMaintenance() failed")
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// maintainTables maintains the tables which are due according to `opts` and
+// returns their names.
+func (db *IndexerDb) maintainTables(ctx context.Context, opts idb.MaintenanceOptions) ([]string, error) {
+	// The statistics of the other schemas sharing the database are ignored.
+	query := `SELECT relname, n_mod_since_analyze, GREATEST(last_analyze, last_autoanalyze)
+		FROM pg_stat_user_tables WHERE schemaname = current_schema() AND relname = ANY($1)`
+	rows, err := db.db.Query(ctx, query, maintainedTables)
+	if err != nil {
+		return nil, fmt.Errorf("maintainTables() query err: %w", err)
+	}
+	defer rows.Close()
+
+	var due []string
+	for rows.Next() {
+		var table string
+		var changed int64
+		var lastAnalyzed *time.Time
+		err = rows.Scan(&table, &changed, &lastAnalyzed)
+		if err != nil {
+			return nil, fmt.Errorf("maintainTables() scan err: %w", err)
+		}
+		if opts.Interval != 0 &&
+			(lastAnalyzed == nil || time.Since(*lastAnalyzed) >= opts.Interval) {
+			due = append(due, table)
+		} else if opts.ChangeThreshold != 0 && changed >= int64(opts.ChangeThreshold) {
+			due = append(due, table)
+		}
+	}
+	err = rows.Err()
+	if err != nil {
+		return nil, fmt.Errorf("maintainTables() rows err: %w", err)
+	}
+	rows.Close()
+
+	operation := "analyze"
+	if opts.Vacuum {
+		operation = "vacuum"
+	}
+	for i, table := range due {
+		// VACUUM can't run in a transaction, it is sent on its own.
+		statement := "ANALYZE " + table
+		if opts.Vacuum {
+			statement = "VACUUM (ANALYZE) " + table
+		}
+		start := time.Now()
+		_, err = db.db.Exec(ctx, statement)
+		if err != nil {
+			return due[:i], fmt.Errorf("maintainTables() %s err: %w", operation, err)
+		}
+		maintenanceRuns.WithLabelValues(table, operation).Inc()
+		db.log.WithFields(log.Fields{
+			"operation": operation,
+			"table":     table,
+			"duration":  time.Since(start).Seconds(),
+		}).Info("maintainTables() done")
+	}
+	return due, nil
+}
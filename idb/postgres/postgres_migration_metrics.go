@@ -0,0 +1,54 @@
+// You can build without postgres by `go build --tags nopostgres` but it's on by default
+//go:build !nopostgres
+// +build !nopostgres
+
+package postgres
+
+import (
+	"strconv"
+
+	"github.com/algorand/indexer/util/metrics"
+)
+
+var migrationCurrent = metrics.DefaultRegistry.NewGaugeVec(
+	"postgres_migration_current",
+	"Id of the running migration, -1 when none is running.")
+
+var migrationBatches = metrics.DefaultRegistry.NewCounterVec(
+	"postgres_migration_batches_total",
+	"Batches committed by the migrations, by migration.",
+	"migration")
+
+var migrationProcessed = metrics.DefaultRegistry.NewCounterVec(
+	"postgres_migration_processed_total",
+	"Units of work, e.g. rows, processed by the migrations, by migration.",
+	"migration")
+
+var migrationRetries = metrics.DefaultRegistry.NewCounterVec(
+	"postgres_migration_retries_total",
+	"Migration transactions retried after a serialization failure, by migration.",
+	"migration")
+
+func init() {
+	migrationCurrent.WithLabelValues().Set(-1)
+}
+
+// startMigrationMetrics reports migration `id` as the running one until the
+// returned function is called.
+func startMigrationMetrics(id int) func() {
+	migrationCurrent.WithLabelValues().Set(float64(id))
+	return func() {
+		migrationCurrent.WithLabelValues().Set(-1)
+	}
+}
+
+// observeMigrationBatch records a batch of migration `id` which was committed
+// after `attempts` attempts.
+func observeMigrationBatch(id int, processed uint64, attempts int) {
+	label := strconv.Itoa(id)
+	migrationBatches.WithLabelValues(label).Inc()
+	migrationProcessed.WithLabelValues(label).Add(float64(processed))
+	if attempts > 1 {
+		migrationRetries.WithLabelValues(label).Add(float64(attempts - 1))
+	}
+}
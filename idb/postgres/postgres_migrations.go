@@ -7,6 +7,7 @@ package postgres
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/jackc/pgx/v4"
 
@@ -20,30 +21,33 @@ func init() {
 	// To deprecate old migrations change the functions to return a `unsupportedMigrationErrorMsg` error.
 	// Make sure you set the blocking flag to true to avoid possible consistency issues during startup.
 	migrations = []migrationStruct{
-		// function, blocking, description
-		{m0fixupTxid, false, "Recompute the txid with corrected algorithm."},
-		{m1fixupBlockTime, true, "Adjust block time to UTC timezone."},
-		{m2apps, true, "Update DB Schema for Algorand application support."},
-		{m3acfgFix, false, "Recompute asset configurations with corrected merge function."},
+		// function, blocking, description, rollback, rewritten tables
+		{m0fixupTxid, false, "Recompute the txid with corrected algorithm.", nil, nil},
+		{m1fixupBlockTime, true, "Adjust block time to UTC timezone.", nil, nil},
+		{m2apps, true, "Update DB Schema for Algorand application support.", nil, nil},
+		{m3acfgFix, false, "Recompute asset configurations with corrected merge function.", nil, nil},
 
 		// 2.2.2 hotfix
-		{m4accountIndices, true, "Add indices to make sure account lookups remain fast when there are a lot of apps or assets."},
+		{m4accountIndices, true, "Add indices to make sure account lookups remain fast when there are a lot of apps or assets.", nil, nil},
 
 		// Migrations for 2.3.1 release
-		{m5MarkTxnJSONSplit, true, "record round at which txn json recording changes, for future migration to fixup prior records"},
-		{m6RewardsAndDatesPart1, true, "Update DB Schema for cumulative account reward support and creation dates."},
-		{m7RewardsAndDatesPart2, false, "Compute cumulative account rewards for all accounts."},
+		{m5MarkTxnJSONSplit, true, "record round at which txn json recording changes, for future migration to fixup prior records", nil, nil},
+		{m6RewardsAndDatesPart1, true, "Update DB Schema for cumulative account reward support and creation dates.", nil, nil},
+		{m7RewardsAndDatesPart2, false, "Compute cumulative account rewards for all accounts.", nil, nil},
 
 		// Migrations for 2.3.2 release
-		{m8StaleClosedAccounts, false, "clear some stale data from closed accounts"},
-		{m9TxnJSONEncoding, false, "some txn JSON encodings need app keys base64 encoded"},
-		{m10SpecialAccountCleanup, false, "The initial m7 implementation would miss special accounts."},
-		{m11AssetHoldingFrozen, true, "Fix asset holding freeze states."},
-
-		{FixFreezeLookupMigration, false, "Fix search by asset freeze address."},
-		{ClearAccountDataMigration, false, "clear account data for accounts that have been closed"},
-		{MakeDeletedNotNullMigration, false, "make all \"deleted\" columns NOT NULL"},
-		{MaxRoundAccountedMigration, true, "change import state format"},
+		{m8StaleClosedAccounts, false, "clear some stale data from closed accounts", nil, nil},
+		{m9TxnJSONEncoding, false, "some txn JSON encodings need app keys base64 encoded", nil, nil},
+		{m10SpecialAccountCleanup, false, "The initial m7 implementation would miss special accounts.", nil, nil},
+		{m11AssetHoldingFrozen, true, "Fix asset holding freeze states.", nil, nil},
+
+		{FixFreezeLookupMigration, false, "Fix search by asset freeze address.", nil, nil},
+		{ClearAccountDataMigration, false, "clear account data for accounts that have been closed", nil, nil},
+		{MakeDeletedNotNullMigration, false, "make all \"deleted\" columns NOT NULL", nil, nil},
+		{MaxRoundAccountedMigration, true, "change import state format", nil, nil},
+		{AccountResourceCountsMigration, true, "add resource counts to the account table", rollbackAccountResourceCounts, []string{"account"}},
+		{AccountTotalsMigration, true, "add the account totals table", rollbackAccountTotals, nil},
+		{TxnBytesLengthMigration, true, "add the uncompressed length of the transaction bytes", rollbackTxnBytesLength, nil},
 	}
 }
 
@@ -57,9 +61,30 @@ type MigrationState struct {
 	PointerRound *int64 `json:"pointerRound,omitempty"`
 	PointerIntra *int64 `json:"pointerIntra,omitempty"`
 
-	// Note: a generic "data" field here could be a good way to deal with this growing over time.
-	//       It would require a mechanism to clear the data field between migrations to avoid using migration data
-	//       from the previous migration.
+	// Progress of the next migration, it is reset when the migration finishes.
+	Progress *migration.Progress `json:"progress,omitempty"`
+
+	// Data is the checkpoint of the next migration, in a format of its choice,
+	// e.g. the cursor of chunkedMigration(). It is reset when the migration
+	// finishes, so a migration never sees the data of the previous one.
+	Data string `json:"data,omitempty"`
+}
+
+// next returns the state after the next migration finished.
+func (state MigrationState) next() MigrationState {
+	state.NextMigration++
+	state.Progress = nil
+	state.Data = ""
+	return state
+}
+
+// previous returns the state after the last completed migration was rolled
+// back.
+func (state MigrationState) previous() MigrationState {
+	state.NextMigration--
+	state.Progress = nil
+	state.Data = ""
+	return state
 }
 
 // A migration function should take care of writing back to metastate migration row
@@ -72,12 +97,29 @@ type migrationStruct struct {
 
 	// Description of the migration
 	description string
+
+	// rollback undoes the migration when it was the last one to complete, nil
+	// if it can't be undone. Like migrate, it writes back the migration state,
+	// with the previous state, e.g. with sqlRollback().
+	rollback postgresMigrationFunc
+
+	// tables are the tables which the migration rewrites, they are checked by
+	// checkMigration() before it starts.
+	tables []string
 }
 
 var migrations []migrationStruct
 
 func wrapPostgresHandler(handler postgresMigrationFunc, db *IndexerDb, state *MigrationState) migration.Handler {
 	return func() error {
+		// The tasks share `state`, the next migration is the one of this task.
+		defer startMigrationMetrics(state.NextMigration)()
+		// Migrations may change the accounts between the blocks.
+		defer db.accountCache.Suspend()()
+		err := db.checkMigration(context.Background(), state.NextMigration)
+		if err != nil {
+			return err
+		}
 		return handler(db, state)
 	}
 }
@@ -100,7 +142,6 @@ func needsMigration(state MigrationState) bool {
 // upsertMigrationState updates the migration state, and optionally increments
 // the next counter with an existing transaction.
 // If `tx` is nil, use a normal query.
-//lint:ignore U1000 this function might be used in a future migration
 func upsertMigrationState(db *IndexerDb, tx pgx.Tx, state *MigrationState) error {
 	migrationStateJSON := encoding.EncodeJSON(state)
 	return db.setMetastate(tx, schema.MigrationMetastateKey, string(migrationStateJSON))
@@ -116,6 +157,17 @@ func (db *IndexerDb) runAvailableMigrations() (chan struct{}, error) {
 		return nil, fmt.Errorf("runAvailableMigrations() err: %w", err)
 	}
 
+	// The rate of an interrupted run does not carry over to this one, the work
+	// it did does when the migration resumes from its checkpoint.
+	if state.Progress != nil {
+		if state.Data == "" {
+			state.Progress = nil
+		} else {
+			progress := resumeProgress(*state.Progress, time.Now())
+			state.Progress = &progress
+		}
+	}
+
 	// Make migration tasks
 	nextMigration := state.NextMigration
 	tasks := make([]migration.Task, 0)
@@ -129,7 +181,14 @@ func (db *IndexerDb) runAvailableMigrations() (chan struct{}, error) {
 		nextMigration++
 	}
 
-	if len(tasks) > 0 {
+	builtinTasks := len(tasks)
+	operatorTasks, err := db.operatorMigrationTasks()
+	if err != nil {
+		return nil, fmt.Errorf("runAvailableMigrations() err: %w", err)
+	}
+	tasks = append(tasks, operatorTasks...)
+
+	if builtinTasks > 0 {
 		// Add a task to mark migrations as done instead of using a channel.
 		tasks = append(tasks, migration.Task{
 			MigrationID: 9999999,
@@ -149,6 +208,223 @@ func (db *IndexerDb) runAvailableMigrations() (chan struct{}, error) {
 	return ch, nil
 }
 
+// RunPendingMigrations is part of idb.IndexerDb.
+func (db *IndexerDb) RunPendingMigrations() error {
+	if db.readonly {
+		return fmt.Errorf("RunPendingMigrations() cannot run migrations in read only mode")
+	}
+	if db.migration != nil && db.migration.GetStatus().Running {
+		return fmt.Errorf("RunPendingMigrations() migrations are already running")
+	}
+
+	state, err := db.getMigrationState()
+	if err != nil {
+		return fmt.Errorf("RunPendingMigrations() err: %w", err)
+	}
+	if !needsMigration(state) {
+		return nil
+	}
+	if migrationStateBlocked(state) {
+		return fmt.Errorf("RunPendingMigrations() pending blocking migrations require a restart")
+	}
+
+	_, err = db.runAvailableMigrations()
+	if err != nil {
+		return fmt.Errorf("RunPendingMigrations() err: %w", err)
+	}
+	return nil
+}
+
+// RollbackMigrations is part of idb.IndexerDb.
+func (db *IndexerDb) RollbackMigrations(ctx context.Context, count int) ([]string, error) {
+	if db.readonly {
+		return nil, fmt.Errorf("RollbackMigrations() cannot roll back migrations in read only mode")
+	}
+	if db.migration != nil && db.migration.GetStatus().Running {
+		return nil, fmt.Errorf("RollbackMigrations() migrations are running")
+	}
+
+	state, err := db.getMigrationState()
+	if err != nil {
+		return nil, fmt.Errorf("RollbackMigrations() err: %w", err)
+	}
+	if count > state.NextMigration {
+		return nil, fmt.Errorf(
+			"RollbackMigrations() only %d migrations have completed", state.NextMigration)
+	}
+	if state.Data != "" {
+		return nil, fmt.Errorf(
+			"RollbackMigrations() migration %d is partially done, let it finish first",
+			state.NextMigration)
+	}
+	// Check first so that nothing is rolled back if the downgrade is not
+	// possible.
+	for i := state.NextMigration - count; i < state.NextMigration; i++ {
+		if migrations[i].rollback == nil {
+			return nil, fmt.Errorf(
+				"RollbackMigrations() migration %d (%s) can't be rolled back",
+				i, migrations[i].description)
+		}
+	}
+
+	var rolledBack []string
+	for n := 0; n < count; n++ {
+		if err := ctx.Err(); err != nil {
+			return rolledBack, fmt.Errorf("RollbackMigrations() err: %w", err)
+		}
+		m := migrations[state.NextMigration-1]
+		err := m.rollback(db, &state)
+		if err != nil {
+			return rolledBack, fmt.Errorf("RollbackMigrations() err: %w", err)
+		}
+		db.log.Infof("rolled back migration %d: %s", state.NextMigration, m.description)
+		rolledBack = append(rolledBack, m.description)
+	}
+	return rolledBack, nil
+}
+
+// Migrations is part of idb.IndexerDb.
+func (db *IndexerDb) Migrations(ctx context.Context) ([]idb.MigrationInfo, error) {
+	state, err := db.getMigrationState()
+	if err != nil {
+		return nil, fmt.Errorf("Migrations() err: %w", err)
+	}
+
+	res := make([]idb.MigrationInfo, 0, len(migrations))
+	for i, m := range migrations {
+		res = append(res, idb.MigrationInfo{
+			ID:          i,
+			Description: m.description,
+			Blocking:    m.blocking,
+			Applied:     i < state.NextMigration,
+			Reversible:  m.rollback != nil,
+		})
+	}
+	operatorInfos, err := db.operatorMigrationInfos(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("Migrations() err: %w", err)
+	}
+	return append(res, operatorInfos...), nil
+}
+
+// setMigrationProgress records that `processed` of `total` units of work of the
+// running migration are done, in the migration status and in the metastate so
+// that other indexers on the database report it as well.
+func (db *IndexerDb) setMigrationProgress(state *MigrationState, processed, total uint64) error {
+	progress := updateProgress(state.Progress, processed, total, time.Now())
+
+	nextState := *state
+	nextState.Progress = &progress
+	err := upsertMigrationState(db, nil, &nextState)
+	if err != nil {
+		return fmt.Errorf("setMigrationProgress() err: %w", err)
+	}
+
+	*state = nextState
+	db.migration.SetProgress(progress)
+	return nil
+}
+
+// updateProgress returns `progress` with the given amounts of work. A new
+// progress starts at `now` if `progress` is nil.
+func updateProgress(progress *migration.Progress, processed, total uint64, now time.Time) migration.Progress {
+	res := migration.Progress{Started: now}
+	if progress != nil {
+		res = *progress
+	}
+	res.Processed = processed
+	res.Total = total
+	res.Updated = now
+	return res
+}
+
+// resumeProgress returns the progress of an interrupted migration which
+// resumes at `now`. The work done so far is kept but not used for the ETA.
+func resumeProgress(progress migration.Progress, now time.Time) migration.Progress {
+	progress.Resumed = progress.Processed
+	progress.Started = now
+	progress.Updated = now
+	return progress
+}
+
+// migrationBatch processes the batch of a chunked migration after `cursor`,
+// which is empty for the first batch. It returns the cursor of the next batch
+// and the amount of work in this batch, or done if there was nothing left.
+type migrationBatch func(tx pgx.Tx, cursor string) (next string, processed uint64, done bool, err error)
+
+// chunkedMigration runs a data migration in batches. Each batch runs in a
+// transaction of its own which also records the cursor of the next batch in
+// the migration state, so an interrupted migration resumes after the last
+// committed batch instead of starting over. The import is paused during a
+// batch, not between batches, so batches must be small. `total` is the amount
+// of work for the progress, 0 if it is not known.
+//
+//lint:ignore U1000 this function might be used in a future migration
+func chunkedMigration(db *IndexerDb, state *MigrationState, total uint64, batch migrationBatch) error {
+	for {
+		done, err := db.runMigrationBatch(state, total, batch)
+		if err != nil {
+			return fmt.Errorf("migration %d err: %w", state.NextMigration, err)
+		}
+		if done {
+			return nil
+		}
+	}
+}
+
+// runMigrationBatch runs the next batch of a chunked migration and commits it
+// with the new migration state. It returns true when the migration finished.
+func (db *IndexerDb) runMigrationBatch(state *MigrationState, total uint64, batch migrationBatch) (bool, error) {
+	db.accountingLock.Lock()
+	defer db.accountingLock.Unlock()
+
+	var nextState MigrationState
+	var done bool
+	var processed uint64
+	attempts := 0
+	f := func(tx pgx.Tx) error {
+		defer tx.Rollback(context.Background())
+		attempts++
+
+		next, batchProcessed, batchDone, err := batch(tx, state.Data)
+		if err != nil {
+			return fmt.Errorf("runMigrationBatch() batch err: %w", err)
+		}
+		if batchDone {
+			nextState = state.next()
+		} else {
+			nextState = *state
+			nextState.Data = next
+			var processedBefore uint64
+			if state.Progress != nil {
+				processedBefore = state.Progress.Processed
+			}
+			progress := updateProgress(
+				state.Progress, processedBefore+batchProcessed, total, time.Now())
+			nextState.Progress = &progress
+		}
+		done = batchDone
+		processed = batchProcessed
+
+		err = upsertMigrationState(db, tx, &nextState)
+		if err != nil {
+			return fmt.Errorf("runMigrationBatch() metastate err: %w", err)
+		}
+		return tx.Commit(context.Background())
+	}
+	err := db.txWithRetry(serializable, f)
+	if err != nil {
+		return false, err
+	}
+	observeMigrationBatch(state.NextMigration, processed, attempts)
+
+	*state = nextState
+	if nextState.Progress != nil {
+		db.migration.SetProgress(*nextState.Progress)
+	}
+	return done, nil
+}
+
 // after setting up a new database, mark state as if all migrations had been done
 func (db *IndexerDb) markMigrationsAsDone() (err error) {
 	state := MigrationState{
@@ -177,22 +453,31 @@ func (db *IndexerDb) getMigrationState() (MigrationState, error) {
 }
 
 // sqlMigration executes a sql statements as the entire migration.
-//lint:ignore U1000 this function might be used in a future migration
 func sqlMigration(db *IndexerDb, state *MigrationState, sqlLines []string) error {
+	return execMigrationSQL(db, state, state.NextMigration, state.next(), sqlLines)
+}
+
+// sqlRollback executes sql statements as the entire rollback of the last
+// migration.
+func sqlRollback(db *IndexerDb, state *MigrationState, sqlLines []string) error {
+	return execMigrationSQL(db, state, state.NextMigration-1, state.previous(), sqlLines)
+}
+
+// execMigrationSQL executes the statements of migration `id` and sets the
+// migration state to `nextState` in one transaction.
+func execMigrationSQL(db *IndexerDb, state *MigrationState, id int, nextState MigrationState, sqlLines []string) error {
 	db.accountingLock.Lock()
 	defer db.accountingLock.Unlock()
 
-	nextState := *state
-	nextState.NextMigration++
-
+	attempts := 0
 	f := func(tx pgx.Tx) error {
 		defer tx.Rollback(context.Background())
+		attempts++
 
 		for _, cmd := range sqlLines {
 			_, err := tx.Exec(context.Background(), cmd)
 			if err != nil {
-				return fmt.Errorf(
-					"migration %d exec cmd: \"%s\" err: %w", state.NextMigration, cmd, err)
+				return fmt.Errorf("migration %d exec cmd: \"%s\" err: %w", id, cmd, err)
 			}
 		}
 		migrationStateJSON := encoding.EncodeJSON(nextState)
@@ -200,19 +485,104 @@ func sqlMigration(db *IndexerDb, state *MigrationState, sqlLines []string) error
 			context.Background(), setMetastateUpsert, schema.MigrationMetastateKey,
 			migrationStateJSON)
 		if err != nil {
-			return fmt.Errorf("migration %d exec metastate err: %w", state.NextMigration, err)
+			return fmt.Errorf("migration %d exec metastate err: %w", id, err)
 		}
 		return tx.Commit(context.Background())
 	}
 	err := db.txWithRetry(serializable, f)
 	if err != nil {
-		return fmt.Errorf("migration %d commit err: %w", state.NextMigration, err)
+		return fmt.Errorf("migration %d commit err: %w", id, err)
+	}
+	observeMigrationBatch(id, 0, attempts)
+
+	*state = nextState
+	return nil
+}
+
+// concurrentIndex is an index created by indexMigration().
+type concurrentIndex struct {
+	name string
+	// The rest of the statement after the name, e.g. "ON txn (round, intra)".
+	definition string
+}
+
+// Number of times indexMigration() tries to create an index.
+const indexMigrationAttempts = 3
+
+// indexMigration creates indexes with CREATE INDEX CONCURRENTLY as the entire
+// migration. Unlike sqlMigration() it does not lock the tables against writes
+// and does not hold the accounting lock, so the import continues while large
+// tables are indexed. The statements can't run in a transaction, so a failed
+// build leaves an invalid index behind. It is dropped and the build is retried,
+// also when the migration is restarted.
+//lint:ignore U1000 this function might be used in a future migration
+func indexMigration(db *IndexerDb, state *MigrationState, indexes []concurrentIndex) error {
+	err := db.setMigrationProgress(state, 0, uint64(len(indexes)))
+	if err != nil {
+		return fmt.Errorf("migration %d err: %w", state.NextMigration, err)
+	}
+
+	for i, index := range indexes {
+		for attempt := 1; attempt <= indexMigrationAttempts; attempt++ {
+			err = db.createIndexConcurrently(index)
+			if err == nil {
+				break
+			}
+			db.log.WithError(err).Warnf(
+				"migration %d create index %s attempt %d failed",
+				state.NextMigration, index.name, attempt)
+		}
+		if err != nil {
+			return fmt.Errorf("migration %d err: %w", state.NextMigration, err)
+		}
+		observeMigrationBatch(state.NextMigration, 1, 1)
+		err = db.setMigrationProgress(state, uint64(i+1), uint64(len(indexes)))
+		if err != nil {
+			return fmt.Errorf("migration %d err: %w", state.NextMigration, err)
+		}
+	}
+
+	nextState := state.next()
+	err = upsertMigrationState(db, nil, &nextState)
+	if err != nil {
+		return fmt.Errorf("migration %d exec metastate err: %w", state.NextMigration, err)
 	}
 
 	*state = nextState
 	return nil
 }
 
+// createIndexConcurrently creates `index` unless a valid index of that name
+// exists. An invalid index left by a failed build is dropped first.
+func (db *IndexerDb) createIndexConcurrently(index concurrentIndex) error {
+	ctx := context.Background()
+
+	var valid bool
+	err := db.db.QueryRow(
+		ctx, "SELECT indisvalid FROM pg_index WHERE indexrelid = to_regclass($1)",
+		index.name).Scan(&valid)
+	switch {
+	case err == pgx.ErrNoRows:
+	case err != nil:
+		return fmt.Errorf("createIndexConcurrently() check %s err: %w", index.name, err)
+	case valid:
+		return nil
+	default:
+		_, err = db.db.Exec(ctx, "DROP INDEX CONCURRENTLY IF EXISTS "+index.name)
+		if err != nil {
+			return fmt.Errorf(
+				"createIndexConcurrently() drop invalid %s err: %w", index.name, err)
+		}
+	}
+
+	_, err = db.db.Exec(
+		ctx, fmt.Sprintf("CREATE INDEX CONCURRENTLY %s %s", index.name, index.definition))
+	if err != nil {
+		return fmt.Errorf("createIndexConcurrently() create %s err: %w", index.name, err)
+	}
+	return nil
+}
+
 const unsupportedMigrationErrorMsg = "unsupported migration: please downgrade to %s to run this migration"
 
 func m0fixupTxid(db *IndexerDb, state *MigrationState) error {
@@ -283,3 +653,70 @@ func MakeDeletedNotNullMigration(db *IndexerDb, state *MigrationState) error {
 func MaxRoundAccountedMigration(db *IndexerDb, migrationState *MigrationState) error {
 	return fmt.Errorf(unsupportedMigrationErrorMsg, "2.6.1")
 }
+
+// AccountResourceCountsMigration adds the number of assets and apps that each
+// account holds and created to the account table.
+func AccountResourceCountsMigration(db *IndexerDb, state *MigrationState) error {
+	return sqlMigration(db, state, []string{
+		`ALTER TABLE account
+			ADD COLUMN total_assets_opted_in bigint NOT NULL DEFAULT 0,
+			ADD COLUMN total_created_assets bigint NOT NULL DEFAULT 0,
+			ADD COLUMN total_apps_opted_in bigint NOT NULL DEFAULT 0,
+			ADD COLUMN total_created_apps bigint NOT NULL DEFAULT 0`,
+		`UPDATE account a SET total_assets_opted_in = c.n FROM
+			(SELECT addr, count(*) AS n FROM account_asset WHERE NOT deleted GROUP BY addr) c
+			WHERE a.addr = c.addr`,
+		`UPDATE account a SET total_created_assets = c.n FROM
+			(SELECT creator_addr, count(*) AS n FROM asset WHERE NOT deleted GROUP BY creator_addr) c
+			WHERE a.addr = c.creator_addr`,
+		`UPDATE account a SET total_apps_opted_in = c.n FROM
+			(SELECT addr, count(*) AS n FROM account_app WHERE NOT deleted GROUP BY addr) c
+			WHERE a.addr = c.addr`,
+		`UPDATE account a SET total_created_apps = c.n FROM
+			(SELECT creator, count(*) AS n FROM app WHERE NOT deleted GROUP BY creator) c
+			WHERE a.addr = c.creator`,
+	})
+}
+
+// rollbackAccountResourceCounts drops the columns added by
+// AccountResourceCountsMigration.
+func rollbackAccountResourceCounts(db *IndexerDb, state *MigrationState) error {
+	return sqlRollback(db, state, []string{
+		`ALTER TABLE account
+			DROP COLUMN total_assets_opted_in,
+			DROP COLUMN total_created_assets,
+			DROP COLUMN total_apps_opted_in,
+			DROP COLUMN total_created_apps`,
+	})
+}
+
+// AccountTotalsMigration adds the table of the account totals after each round.
+// The totals of the rounds imported before are not computed, the evaluator sums
+// the accounts for the latest round when it needs them.
+func AccountTotalsMigration(db *IndexerDb, state *MigrationState) error {
+	return sqlMigration(db, state, []string{
+		`CREATE TABLE IF NOT EXISTS account_totals (
+			round bigint PRIMARY KEY,
+			totals jsonb NOT NULL
+		)`,
+	})
+}
+
+// rollbackAccountTotals drops the table added by AccountTotalsMigration.
+func rollbackAccountTotals(db *IndexerDb, state *MigrationState) error {
+	return sqlRollback(db, state, []string{`DROP TABLE account_totals`})
+}
+
+// TxnBytesLengthMigration adds the length of the transaction bytes before they
+// are compressed. The column is NULL for the transactions stored before, the
+// transaction bytes are not compressed unless --compress-transactions was set.
+func TxnBytesLengthMigration(db *IndexerDb, state *MigrationState) error {
+	return sqlMigration(db, state, []string{
+		`ALTER TABLE txn ADD COLUMN IF NOT EXISTS txnbytes_len integer`,
+	})
+}
+
+// rollbackTxnBytesLength drops the column added by TxnBytesLengthMigration.
+func rollbackTxnBytesLength(db *IndexerDb, state *MigrationState) error {
+	return sqlRollback(db, state, []string{`ALTER TABLE txn DROP COLUMN txnbytes_len`})
+}
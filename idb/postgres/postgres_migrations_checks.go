@@ -0,0 +1,100 @@
+// You can build without postgres by `go build --tags nopostgres` but it's on by default
+//go:build !nopostgres
+// +build !nopostgres
+
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// migrationLockMaxAge is how long a transaction may have been running while it
+// holds a lock on a table of a migration. An older one makes the migration
+// refuse to start, it would wait for the lock while the queries of the table
+// queue behind it.
+var migrationLockMaxAge = time.Minute
+
+// rewriteSizeQuery returns the size of the given tables with their indexes and
+// TOAST tables, tables which don't exist are ignored.
+const rewriteSizeQuery = `SELECT COALESCE(sum(pg_total_relation_size(to_regclass(t))), 0)::bigint
+	FROM unnest($1::text[]) t`
+
+// lockHoldersQuery returns the other sessions holding a lock on the given
+// tables whose transaction started more than $2 seconds ago.
+const lockHoldersQuery = `SELECT DISTINCT a.pid, c.relname,
+		EXTRACT(EPOCH FROM now() - a.xact_start)::float8, COALESCE(a.state, ''), left(a.query, 200)
+	FROM pg_locks l
+	JOIN pg_stat_activity a ON a.pid = l.pid
+	JOIN pg_class c ON c.oid = l.relation
+	WHERE l.relation IN (SELECT to_regclass(t) FROM unnest($1::text[]) t)
+		AND l.pid <> pg_backend_pid()
+		AND a.xact_start < now() - make_interval(secs => $2)
+	ORDER BY a.pid`
+
+// checkMigration checks that migration `id` can run to completion before it
+// starts: the tables it rewrites must fit into the free disk space of the
+// database, when it is configured, and no long running transaction may hold
+// a lock on them.
+func (db *IndexerDb) checkMigration(ctx context.Context, id int) error {
+	if id < 0 || id >= len(migrations) || len(migrations[id].tables) == 0 {
+		return nil
+	}
+	// CockroachDB has neither the size functions nor pg_locks.
+	if db.cockroachCompat {
+		return nil
+	}
+	tables := migrations[id].tables
+
+	if db.migrationDiskSpace != 0 {
+		var required uint64
+		err := db.db.QueryRow(ctx, rewriteSizeQuery, tables).Scan(&required)
+		if err != nil {
+			return fmt.Errorf("checkMigration() size err: %w", err)
+		}
+		if required > db.migrationDiskSpace {
+			return fmt.Errorf(
+				"migration %d rewrites %s, which needs about %d MB of free disk space but "+
+					"only %d MB are free, free up space or raise the free space setting",
+				id, strings.Join(tables, ", "), required>>20, db.migrationDiskSpace>>20)
+		}
+	}
+
+	holders, err := db.lockHolders(ctx, tables, migrationLockMaxAge)
+	if err != nil {
+		return fmt.Errorf("checkMigration() locks err: %w", err)
+	}
+	if len(holders) > 0 {
+		return fmt.Errorf(
+			"migration %d can't lock %s, end these transactions first: %s",
+			id, strings.Join(tables, ", "), strings.Join(holders, "; "))
+	}
+	return nil
+}
+
+// lockHolders describes the sessions which hold a lock on `tables` in a
+// transaction older than `maxAge`.
+func (db *IndexerDb) lockHolders(ctx context.Context, tables []string, maxAge time.Duration) ([]string, error) {
+	rows, err := db.db.Query(ctx, lockHoldersQuery, tables, maxAge.Seconds())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var res []string
+	for rows.Next() {
+		var pid int
+		var table, state, query string
+		var age float64
+		err = rows.Scan(&pid, &table, &age, &state, &query)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, fmt.Sprintf(
+			"process %d holds a lock on %s in a transaction running for %s (%s): %s",
+			pid, table, time.Duration(age*float64(time.Second)).Round(time.Second), state, query))
+	}
+	return res, rows.Err()
+}
@@ -0,0 +1,236 @@
+// You can build without postgres by `go build --tags nopostgres` but it's on by default
+//go:build !nopostgres
+// +build !nopostgres
+
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/jackc/pgx/v4"
+
+	"github.com/algorand/indexer/idb"
+	"github.com/algorand/indexer/idb/migration"
+	"github.com/algorand/indexer/idb/postgres/internal/encoding"
+	"github.com/algorand/indexer/idb/postgres/internal/schema"
+)
+
+// operatorMigrationIDBase is added to the versions of the operator's scripts
+// to get their migration ids, which follow those of the built-in migrations.
+const operatorMigrationIDBase = 1000000
+
+// maxOperatorScriptVersion keeps the ids of the scripts below the id of the
+// task which marks the built-in migrations as done.
+const maxOperatorScriptVersion = 999999
+
+// operatorScriptNameRegexp matches the script names, the version is the
+// leading number, e.g. 001 in 001_account_index.sql.
+var operatorScriptNameRegexp = regexp.MustCompile(`^([0-9]+)_.*\.sql$`)
+
+// noTransactionDirective as the first line of a script runs it outside of a
+// transaction, e.g. for CREATE INDEX CONCURRENTLY.
+const noTransactionDirective = "-- indexer:no-transaction"
+
+// operatorScript is a SQL migration supplied by the operator.
+type operatorScript struct {
+	version uint64
+	name    string
+	sql     string
+}
+
+func (script operatorScript) inTransaction() bool {
+	return !strings.HasPrefix(script.sql, noTransactionDirective)
+}
+
+// operatorMigrationState is the metastate of the operator's scripts.
+type operatorMigrationState struct {
+	// Applied are the versions of the scripts which completed.
+	Applied []uint64 `json:"applied"`
+}
+
+// loadOperatorScripts reads the scripts in `dir`, ordered by version. Files
+// which don't end in .sql are ignored.
+func loadOperatorScripts(dir string) ([]operatorScript, error) {
+	if dir == "" {
+		return nil, nil
+	}
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("loadOperatorScripts() err: %w", err)
+	}
+
+	var res []operatorScript
+	versions := make(map[uint64]string)
+	for _, file := range files {
+		if file.IsDir() || filepath.Ext(file.Name()) != ".sql" {
+			continue
+		}
+		match := operatorScriptNameRegexp.FindStringSubmatch(file.Name())
+		if match == nil {
+			return nil, fmt.Errorf(
+				"loadOperatorScripts() %s does not start with a version, e.g. 001_name.sql",
+				file.Name())
+		}
+		version, err := strconv.ParseUint(match[1], 10, 64)
+		if err != nil || version > maxOperatorScriptVersion {
+			return nil, fmt.Errorf(
+				"loadOperatorScripts() the version of %s must be at most %d",
+				file.Name(), maxOperatorScriptVersion)
+		}
+		if other, ok := versions[version]; ok {
+			return nil, fmt.Errorf(
+				"loadOperatorScripts() %s and %s have the same version", other, file.Name())
+		}
+		versions[version] = file.Name()
+
+		sql, err := ioutil.ReadFile(filepath.Join(dir, file.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("loadOperatorScripts() err: %w", err)
+		}
+		res = append(res, operatorScript{version: version, name: file.Name(), sql: string(sql)})
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i].version < res[j].version })
+	return res, nil
+}
+
+// If `tx` is nil, use a normal query.
+func (db *IndexerDb) getOperatorMigrationState(ctx context.Context, tx pgx.Tx) (operatorMigrationState, error) {
+	stateJSON, err := db.getMetastate(ctx, tx, schema.OperatorMigrationsMetastateKey)
+	if err == idb.ErrorNotInitialized {
+		return operatorMigrationState{}, nil
+	}
+	if err != nil {
+		return operatorMigrationState{}, fmt.Errorf("getOperatorMigrationState() err: %w", err)
+	}
+
+	var state operatorMigrationState
+	err = encoding.DecodeJSON([]byte(stateJSON), &state)
+	if err != nil {
+		return operatorMigrationState{}, fmt.Errorf("getOperatorMigrationState() decode err: %w", err)
+	}
+	return state, nil
+}
+
+func (state operatorMigrationState) applied(version uint64) bool {
+	for _, v := range state.Applied {
+		if v == version {
+			return true
+		}
+	}
+	return false
+}
+
+// operatorMigrationTasks returns the tasks of the scripts which were not
+// applied. They don't block the database.
+func (db *IndexerDb) operatorMigrationTasks() ([]migration.Task, error) {
+	if len(db.operatorScripts) == 0 {
+		return nil, nil
+	}
+	state, err := db.getOperatorMigrationState(context.Background(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("operatorMigrationTasks() err: %w", err)
+	}
+
+	var tasks []migration.Task
+	for _, script := range db.operatorScripts {
+		if state.applied(script.version) {
+			continue
+		}
+		script := script
+		tasks = append(tasks, migration.Task{
+			MigrationID: operatorMigrationIDBase + int(script.version),
+			Handler: func() error {
+				defer startMigrationMetrics(operatorMigrationIDBase + int(script.version))()
+				defer db.accountCache.Suspend()()
+				return db.runOperatorScript(script)
+			},
+			Description: "operator script " + script.name,
+		})
+	}
+	return tasks, nil
+}
+
+// runOperatorScript runs `script` and records it as applied, in one
+// transaction unless the script asks to run outside of one. Such a script is
+// run again if the indexer stops before it is recorded, so it should be
+// idempotent, e.g. use IF NOT EXISTS.
+func (db *IndexerDb) runOperatorScript(script operatorScript) error {
+	ctx := context.Background()
+	markApplied := func(tx pgx.Tx) error {
+		state, err := db.getOperatorMigrationState(ctx, tx)
+		if err != nil {
+			return err
+		}
+		if !state.applied(script.version) {
+			state.Applied = append(state.Applied, script.version)
+		}
+		return db.setMetastate(
+			tx, schema.OperatorMigrationsMetastateKey, string(encoding.EncodeJSON(state)))
+	}
+
+	if !script.inTransaction() {
+		_, err := db.db.Exec(ctx, script.sql)
+		if err != nil {
+			return fmt.Errorf("runOperatorScript() %s err: %w", script.name, err)
+		}
+		err = markApplied(nil)
+		if err != nil {
+			return fmt.Errorf("runOperatorScript() %s metastate err: %w", script.name, err)
+		}
+		observeMigrationBatch(operatorMigrationIDBase+int(script.version), 0, 1)
+		return nil
+	}
+
+	db.accountingLock.Lock()
+	defer db.accountingLock.Unlock()
+
+	attempts := 0
+	f := func(tx pgx.Tx) error {
+		defer tx.Rollback(ctx)
+		attempts++
+
+		_, err := tx.Exec(ctx, script.sql)
+		if err != nil {
+			return fmt.Errorf("runOperatorScript() %s err: %w", script.name, err)
+		}
+		err = markApplied(tx)
+		if err != nil {
+			return fmt.Errorf("runOperatorScript() %s metastate err: %w", script.name, err)
+		}
+		return tx.Commit(ctx)
+	}
+	err := db.txWithRetry(serializable, f)
+	if err != nil {
+		return err
+	}
+	observeMigrationBatch(operatorMigrationIDBase+int(script.version), 0, attempts)
+	return nil
+}
+
+// operatorMigrationInfos describes the operator's scripts for Migrations().
+func (db *IndexerDb) operatorMigrationInfos(ctx context.Context) ([]idb.MigrationInfo, error) {
+	if len(db.operatorScripts) == 0 {
+		return nil, nil
+	}
+	state, err := db.getOperatorMigrationState(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]idb.MigrationInfo, 0, len(db.operatorScripts))
+	for _, script := range db.operatorScripts {
+		res = append(res, idb.MigrationInfo{
+			ID:          operatorMigrationIDBase + int(script.version),
+			Description: "operator script " + script.name,
+			Applied:     state.applied(script.version),
+		})
+	}
+	return res, nil
+}
@@ -0,0 +1,239 @@
+// You can build without postgres by `go build --tags nopostgres` but it's on by default
+//go:build !nopostgres
+// +build !nopostgres
+
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+
+	"github.com/jackc/pgx/v4"
+
+	"github.com/algorand/indexer/idb"
+	"github.com/algorand/indexer/idb/postgres/internal/encoding"
+	"github.com/algorand/indexer/idb/postgres/internal/schema"
+)
+
+// defaultMigrationWorkers is the number of workers of parallelMigration() when
+// it is not configured.
+const defaultMigrationWorkers = 4
+
+// shardState is the checkpoint of a shard of parallelMigration().
+type shardState struct {
+	Cursor    string `json:"cursor,omitempty"`
+	Processed uint64 `json:"processed,omitempty"`
+	Done      bool   `json:"done,omitempty"`
+}
+
+// shardBatch processes the batch of `shard` after `cursor`, which is empty for
+// the first batch of the shard. It returns the cursor of the next batch and
+// the amount of work in this batch, or done if the shard has nothing left.
+type shardBatch func(tx pgx.Tx, shard int, cursor string) (next string, processed uint64, done bool, err error)
+
+func shardMetastateKey(shard int) string {
+	return schema.MigrationShardMetastateKeyPrefix + strconv.Itoa(shard)
+}
+
+// parallelMigration runs a data migration over `shards` disjoint parts of the
+// data, e.g. ranges of rounds or addresses, with the configured number of
+// workers. Each shard is processed in batches like chunkedMigration(). The
+// cursor of each shard is kept in a metastate row of its own, so the workers
+// don't contend on the migration state, and an interrupted migration resumes
+// every shard after its last committed batch. The import is paused while any
+// batch runs. `total` is the amount of work for the progress, 0 if it is not
+// known.
+//
+//lint:ignore U1000 this function might be used in a future migration
+func parallelMigration(db *IndexerDb, state *MigrationState, shards int, total uint64, batch shardBatch) error {
+	// The checkpoint of the migration is the number of shards, the cursors of a
+	// different sharding can't be resumed.
+	data := fmt.Sprintf("shards:%d", shards)
+	if state.Data != "" && state.Data != data {
+		return fmt.Errorf(
+			"migration %d checkpoint \"%s\" does not match %d shards",
+			state.NextMigration, state.Data, shards)
+	}
+	if state.Data == "" {
+		nextState := *state
+		nextState.Data = data
+		err := upsertMigrationState(db, nil, &nextState)
+		if err != nil {
+			return fmt.Errorf("migration %d exec metastate err: %w", state.NextMigration, err)
+		}
+		*state = nextState
+	}
+
+	checkpoints := make([]shardState, shards)
+	var processed uint64
+	for i := range checkpoints {
+		checkpoint, err := db.getShardState(i)
+		if err != nil {
+			return fmt.Errorf("migration %d err: %w", state.NextMigration, err)
+		}
+		checkpoints[i] = checkpoint
+		processed += checkpoint.Processed
+	}
+
+	// Guards `state` and `processed`.
+	var mu sync.Mutex
+	report := func(n uint64) error {
+		mu.Lock()
+		defer mu.Unlock()
+		processed += n
+		return db.setMigrationProgress(state, processed, total)
+	}
+
+	workers := db.migrationWorkers
+	if workers <= 0 {
+		workers = defaultMigrationWorkers
+	}
+
+	// A failed shard stops the other workers after their current batch.
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	id := state.NextMigration
+	shardCh := make(chan int)
+	errCh := make(chan error, workers)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for shard := range shardCh {
+				err := db.runShard(ctx, id, shard, checkpoints[shard], batch, report)
+				if err != nil {
+					errCh <- err
+					cancel()
+					return
+				}
+			}
+		}()
+	}
+
+feed:
+	for i, checkpoint := range checkpoints {
+		if checkpoint.Done {
+			continue
+		}
+		select {
+		case shardCh <- i:
+		case <-ctx.Done():
+			break feed
+		}
+	}
+	close(shardCh)
+	wg.Wait()
+	close(errCh)
+	// The first error is the one which stopped the other workers.
+	if err := <-errCh; err != nil {
+		return fmt.Errorf("migration %d err: %w", state.NextMigration, err)
+	}
+
+	return db.finishParallelMigration(state)
+}
+
+// runShard processes the batches of `shard` from `checkpoint` until it is done.
+func (db *IndexerDb) runShard(ctx context.Context, id int, shard int, checkpoint shardState, batch shardBatch, report func(uint64) error) error {
+	for !checkpoint.Done {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		next, err := db.runShardBatch(id, shard, checkpoint, batch)
+		if err != nil {
+			return fmt.Errorf("runShard() shard %d err: %w", shard, err)
+		}
+		err = report(next.Processed - checkpoint.Processed)
+		if err != nil {
+			return fmt.Errorf("runShard() shard %d err: %w", shard, err)
+		}
+		checkpoint = next
+	}
+	return nil
+}
+
+// runShardBatch runs the next batch of `shard` and commits it with the new
+// checkpoint of the shard.
+func (db *IndexerDb) runShardBatch(id int, shard int, checkpoint shardState, batch shardBatch) (shardState, error) {
+	// Shared by the workers, exclusive with the import.
+	db.accountingLock.RLock()
+	defer db.accountingLock.RUnlock()
+
+	var next shardState
+	attempts := 0
+	f := func(tx pgx.Tx) error {
+		defer tx.Rollback(context.Background())
+		attempts++
+
+		cursor, processed, done, err := batch(tx, shard, checkpoint.Cursor)
+		if err != nil {
+			return fmt.Errorf("runShardBatch() batch err: %w", err)
+		}
+		next = shardState{
+			Cursor:    cursor,
+			Processed: checkpoint.Processed + processed,
+			Done:      done,
+		}
+
+		err = db.setMetastate(tx, shardMetastateKey(shard), string(encoding.EncodeJSON(next)))
+		if err != nil {
+			return fmt.Errorf("runShardBatch() metastate err: %w", err)
+		}
+		return tx.Commit(context.Background())
+	}
+	err := db.txWithRetry(serializable, f)
+	if err != nil {
+		return shardState{}, err
+	}
+	observeMigrationBatch(id, next.Processed-checkpoint.Processed, attempts)
+	return next, nil
+}
+
+// finishParallelMigration deletes the checkpoints of the shards and marks the
+// migration as done.
+func (db *IndexerDb) finishParallelMigration(state *MigrationState) error {
+	nextState := state.next()
+	f := func(tx pgx.Tx) error {
+		defer tx.Rollback(context.Background())
+
+		_, err := tx.Exec(
+			context.Background(), "DELETE FROM metastate WHERE k LIKE $1",
+			schema.MigrationShardMetastateKeyPrefix+"%")
+		if err != nil {
+			return fmt.Errorf("finishParallelMigration() delete err: %w", err)
+		}
+		err = upsertMigrationState(db, tx, &nextState)
+		if err != nil {
+			return fmt.Errorf("finishParallelMigration() metastate err: %w", err)
+		}
+		return tx.Commit(context.Background())
+	}
+	err := db.txWithRetry(serializable, f)
+	if err != nil {
+		return fmt.Errorf("migration %d err: %w", state.NextMigration, err)
+	}
+
+	*state = nextState
+	return nil
+}
+
+// getShardState returns the checkpoint of `shard`, empty if the shard was not
+// started.
+func (db *IndexerDb) getShardState(shard int) (shardState, error) {
+	checkpointJSON, err := db.getMetastate(context.Background(), nil, shardMetastateKey(shard))
+	if err == idb.ErrorNotInitialized {
+		return shardState{}, nil
+	}
+	if err != nil {
+		return shardState{}, fmt.Errorf("getShardState() err: %w", err)
+	}
+
+	var checkpoint shardState
+	err = encoding.DecodeJSON([]byte(checkpointJSON), &checkpoint)
+	if err != nil {
+		return shardState{}, fmt.Errorf("getShardState() decode err: %w", err)
+	}
+	return checkpoint, nil
+}
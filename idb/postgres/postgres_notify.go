@@ -0,0 +1,72 @@
+// You can build without postgres by `go build --tags nopostgres` but it's on by default
+//go:build !nopostgres
+// +build !nopostgres
+
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/jackc/pgx/v4"
+
+	"github.com/algorand/indexer/idb"
+	"github.com/algorand/indexer/idb/postgres/internal/schema"
+)
+
+// ListenRounds is part of idb.IndexerDb. It uses a connection of its own, a
+// listening connection can't be shared with queries.
+func (db *IndexerDb) ListenRounds(ctx context.Context, f func(round uint64)) error {
+	if db.pgbouncerCompat {
+		// A transaction pooler hands the connection to other clients between
+		// transactions, the notifications would be lost.
+		return fmt.Errorf("ListenRounds() not supported with PgBouncer compatibility")
+	}
+	if db.cockroachCompat {
+		return fmt.Errorf("ListenRounds() not supported with CockroachDB compatibility")
+	}
+
+	conn, err := pgx.ConnectConfig(ctx, db.db.Config().ConnConfig)
+	if err != nil {
+		return fmt.Errorf("ListenRounds() connect err: %w", err)
+	}
+	defer conn.Close(context.Background())
+
+	var channel string
+	err = conn.QueryRow(ctx, "SELECT "+schema.RoundNotifyChannel).Scan(&channel)
+	if err != nil {
+		return fmt.Errorf("ListenRounds() channel err: %w", err)
+	}
+	_, err = conn.Exec(ctx, "LISTEN "+pgx.Identifier{channel}.Sanitize())
+	if err != nil {
+		return fmt.Errorf("ListenRounds() listen err: %w", err)
+	}
+
+	// Rounds imported before listening, e.g. while reconnecting, are covered by
+	// the latest round.
+	nextRound, err := db.GetNextRoundToAccount()
+	if err != nil && err != idb.ErrorNotInitialized {
+		return fmt.Errorf("ListenRounds() err: %w", err)
+	}
+	if nextRound > 0 {
+		f(nextRound - 1)
+	}
+
+	for {
+		notification, err := conn.WaitForNotification(ctx)
+		if ctx.Err() != nil {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("ListenRounds() wait err: %w", err)
+		}
+		round, err := strconv.ParseUint(notification.Payload, 10, 64)
+		if err != nil {
+			db.log.WithError(err).Warnf(
+				"ListenRounds() ignoring notification \"%s\"", notification.Payload)
+			continue
+		}
+		f(round)
+	}
+}
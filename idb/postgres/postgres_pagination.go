@@ -0,0 +1,49 @@
+// You can build without postgres by `go build --tags nopostgres` but it's on by default
+//go:build !nopostgres
+// +build !nopostgres
+
+package postgres
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+
+	"github.com/algorand/indexer/idb/postgres/internal/encoding"
+	"github.com/algorand/indexer/idb/postgres/internal/schema"
+)
+
+// paginationState is the secret from which the next tokens of every indexer
+// which uses the database are signed.
+type paginationState struct {
+	Secret []byte `codec:"secret"`
+}
+
+// Only the first indexer stores its secret, the others read it.
+const insertMetastateIfMissing = `INSERT INTO metastate (k, v) VALUES ($1, $2) ON CONFLICT (k) DO NOTHING`
+
+// PaginationSecret is part of idb.IndexerDb.
+func (db *IndexerDb) PaginationSecret(ctx context.Context) ([]byte, error) {
+	state := paginationState{Secret: make([]byte, 32)}
+	_, err := rand.Read(state.Secret)
+	if err != nil {
+		return nil, fmt.Errorf("PaginationSecret() generate err: %w", err)
+	}
+	_, err = db.db.Exec(
+		ctx, insertMetastateIfMissing, schema.PaginationMetastateKey,
+		string(encoding.EncodeJSON(state)))
+	if err != nil {
+		return nil, fmt.Errorf("PaginationSecret() insert err: %w", err)
+	}
+
+	stateJSON, err := db.getMetastate(ctx, nil, schema.PaginationMetastateKey)
+	if err != nil {
+		return nil, fmt.Errorf("PaginationSecret() err: %w", err)
+	}
+	state = paginationState{}
+	err = encoding.DecodeJSON([]byte(stateJSON), &state)
+	if err != nil {
+		return nil, fmt.Errorf("PaginationSecret() decode err: %w", err)
+	}
+	return state.Secret, nil
+}
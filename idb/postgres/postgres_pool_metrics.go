@@ -0,0 +1,106 @@
+// You can build without postgres by `go build --tags nopostgres` but it's on by default
+//go:build !nopostgres
+// +build !nopostgres
+
+package postgres
+
+import (
+	"sync/atomic"
+
+	"github.com/jackc/pgx/v4/pgxpool"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/algorand/indexer/util/metrics"
+)
+
+// Labels of the connection pools in the metrics.
+const (
+	primaryPoolLabel = "primary"
+	replicaPoolLabel = "replica"
+)
+
+func newPoolDesc(name, help string) *prometheus.Desc {
+	return prometheus.NewDesc(
+		prometheus.BuildFQName("", "indexer_daemon", name), help, []string{"pool"}, nil)
+}
+
+var (
+	poolAcquiredConnsDesc = newPoolDesc(
+		"postgres_pool_acquired_conns", "Connections in use by a query or the import, by pool.")
+	poolIdleConnsDesc = newPoolDesc(
+		"postgres_pool_idle_conns", "Open connections which are not in use, by pool.")
+	poolTotalConnsDesc = newPoolDesc(
+		"postgres_pool_total_conns", "Open connections, including those being opened, by pool.")
+	poolMaxConnsDesc = newPoolDesc(
+		"postgres_pool_max_conns", "Maximum size of the connection pool, by pool.")
+	poolAcquiresDesc = newPoolDesc(
+		"postgres_pool_acquires_total", "Connections acquired from the pool, by pool.")
+	poolEmptyAcquiresDesc = newPoolDesc(
+		"postgres_pool_empty_acquires_total",
+		"Acquires which waited because no connection was idle, by pool.")
+	poolCanceledAcquiresDesc = newPoolDesc(
+		"postgres_pool_canceled_acquires_total",
+		"Acquires which were canceled while waiting for a connection, by pool.")
+	poolAcquireSecondsDesc = newPoolDesc(
+		"postgres_pool_acquire_wait_seconds_total",
+		"Time spent waiting to acquire a connection, by pool.")
+)
+
+// poolCollector reads the statistics of the connection pools of the database
+// set by setPoolMetricsDB when the metrics are scraped.
+type poolCollector struct {
+	db atomic.Value
+}
+
+var poolMetrics = &poolCollector{}
+
+func init() {
+	metrics.DefaultRegistry.Register(poolMetrics)
+}
+
+// setPoolMetricsDB sets the database whose pools are reported.
+func setPoolMetricsDB(db *IndexerDb) {
+	poolMetrics.db.Store(db)
+}
+
+// Describe is part of prometheus.Collector.
+func (c *poolCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- poolAcquiredConnsDesc
+	ch <- poolIdleConnsDesc
+	ch <- poolTotalConnsDesc
+	ch <- poolMaxConnsDesc
+	ch <- poolAcquiresDesc
+	ch <- poolEmptyAcquiresDesc
+	ch <- poolCanceledAcquiresDesc
+	ch <- poolAcquireSecondsDesc
+}
+
+// Collect is part of prometheus.Collector.
+func (c *poolCollector) Collect(ch chan<- prometheus.Metric) {
+	db, _ := c.db.Load().(*IndexerDb)
+	if db == nil {
+		return
+	}
+	collectPoolStat(ch, primaryPoolLabel, db.db)
+	if db.replica != nil {
+		collectPoolStat(ch, replicaPoolLabel, db.replica.pool)
+	}
+}
+
+func collectPoolStat(ch chan<- prometheus.Metric, label string, pool *pgxpool.Pool) {
+	stat := pool.Stat()
+	gauge := func(desc *prometheus.Desc, value float64) {
+		ch <- prometheus.MustNewConstMetric(desc, prometheus.GaugeValue, value, label)
+	}
+	counter := func(desc *prometheus.Desc, value float64) {
+		ch <- prometheus.MustNewConstMetric(desc, prometheus.CounterValue, value, label)
+	}
+	gauge(poolAcquiredConnsDesc, float64(stat.AcquiredConns()))
+	gauge(poolIdleConnsDesc, float64(stat.IdleConns()))
+	gauge(poolTotalConnsDesc, float64(stat.TotalConns()))
+	gauge(poolMaxConnsDesc, float64(stat.MaxConns()))
+	counter(poolAcquiresDesc, float64(stat.AcquireCount()))
+	counter(poolEmptyAcquiresDesc, float64(stat.EmptyAcquireCount()))
+	counter(poolCanceledAcquiresDesc, float64(stat.CanceledAcquireCount()))
+	counter(poolAcquireSecondsDesc, stat.AcquireDuration().Seconds())
+}
@@ -0,0 +1,161 @@
+// You can build without postgres by `go build --tags nopostgres` but it's on by default
+//go:build !nopostgres
+// +build !nopostgres
+
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/algorand/indexer/idb"
+	"github.com/algorand/indexer/util/metrics"
+	"github.com/algorand/indexer/util/reporting"
+	"github.com/algorand/indexer/util/tracing"
+)
+
+// Names of the queries in the metrics, one per class of API query.
+const (
+	blockQueryName             = "block"
+	blockHeaderQueryName       = "block_header"
+	blockTransactionsQueryName = "block_transactions"
+	transactionsQueryName      = "transactions"
+	accountsQueryName          = "accounts"
+	assetsQueryName            = "assets"
+	assetBalancesQueryName     = "asset_balances"
+	applicationsQueryName      = "applications"
+)
+
+var queryDuration = metrics.DefaultRegistry.NewHistogramVec(
+	"postgres_query_duration_seconds",
+	"Time from sending a query until its rows are read and closed, by query.",
+	nil, "query")
+
+var queryRows = metrics.DefaultRegistry.NewHistogramVec(
+	"postgres_query_rows",
+	"Rows read from a query before it was closed, by query.",
+	prometheus.ExponentialBuckets(1, 4, 8), "query")
+
+func observeQuery(name string, start time.Time, rows int) {
+	queryDuration.WithLabelValues(name).Observe(time.Since(start).Seconds())
+	queryRows.WithLabelValues(name).Observe(float64(rows))
+}
+
+// startQuerySpan starts the span of the query `name` with its statement, so
+// that a slow request can be traced down to its SQL.
+func startQuerySpan(ctx context.Context, name string, query string) (context.Context, trace.Span) {
+	return tracing.Start(ctx, "postgres "+name, trace.SpanKindClient,
+		attribute.String("db.system", "postgresql"),
+		attribute.String("db.statement", query))
+}
+
+func endQuerySpan(span trace.Span, rows int, err error) {
+	span.SetAttributes(attribute.Int64("db.rows", int64(rows)))
+	tracing.SetError(span, err)
+	span.End()
+}
+
+// reportQueryError reports the failure of the query `name`, with the ID of the
+// request, unless its context is done, i.e. the request was canceled or timed
+// out.
+func reportQueryError(ctx context.Context, name string, err error) {
+	if err == nil || ctx.Err() != nil {
+		return
+	}
+	tags := reporting.Tags{"query": name}
+	if id := idb.RequestIDFromContext(ctx); id != "" {
+		tags["request_id"] = id
+	}
+	reporting.Report(err, tags)
+}
+
+// observedRows records the metrics and the span of a query when it is closed. The rows are
+// streamed to the API, so the duration includes the time the consumer takes.
+type observedRows struct {
+	pgx.Rows
+
+	ctx    context.Context
+	name   string
+	start  time.Time
+	span   trace.Span
+	rows   int
+	closed bool
+}
+
+func (r *observedRows) Next() bool {
+	if r.Rows.Next() {
+		r.rows++
+		return true
+	}
+	return false
+}
+
+func (r *observedRows) Close() {
+	r.Rows.Close()
+	if !r.closed {
+		r.closed = true
+		observeQuery(r.name, r.start, r.rows)
+		endQuerySpan(r.span, r.rows, r.Rows.Err())
+		reportQueryError(r.ctx, r.name, r.Rows.Err())
+	}
+}
+
+// querier is a transaction or a connection pool.
+type querier interface {
+	Query(ctx context.Context, sql string, args ...interface{}) (pgx.Rows, error)
+}
+
+// queryObserved is tx.Query() which records the duration and row count of the
+// query under `name`, and its span, when the rows are closed. A failure is
+// reported with the name.
+func queryObserved(ctx context.Context, tx querier, name string, query string, args ...interface{}) (pgx.Rows, error) {
+	ctx, span := startQuerySpan(ctx, name, query)
+	start := time.Now()
+	rows, err := tx.Query(ctx, query, args...)
+	if err != nil {
+		observeQuery(name, start, 0)
+		endQuerySpan(span, 0, err)
+		reportQueryError(ctx, name, err)
+		return nil, err
+	}
+	return &observedRows{Rows: rows, ctx: ctx, name: name, start: start, span: span}, nil
+}
+
+// observedRow records the metrics and the span of a query when it is scanned.
+type observedRow struct {
+	pgx.Row
+
+	ctx   context.Context
+	name  string
+	start time.Time
+	span  trace.Span
+}
+
+func (r observedRow) Scan(dest ...interface{}) error {
+	err := r.Row.Scan(dest...)
+	rows := 1
+	if err != nil {
+		rows = 0
+	}
+	observeQuery(r.name, r.start, rows)
+	if err == pgx.ErrNoRows {
+		endQuerySpan(r.span, rows, nil)
+	} else {
+		endQuerySpan(r.span, rows, err)
+		reportQueryError(r.ctx, r.name, err)
+	}
+	return err
+}
+
+// queryRowObserved is tx.QueryRow() which records the duration and row count of
+// the query under `name`, and its span, when the row is scanned.
+func queryRowObserved(ctx context.Context, tx pgx.Tx, name string, query string, args ...interface{}) pgx.Row {
+	ctx, span := startQuerySpan(ctx, name, query)
+	start := time.Now()
+	return observedRow{Row: tx.QueryRow(ctx, query, args...), ctx: ctx, name: name, start: start, span: span}
+}
@@ -0,0 +1,294 @@
+// You can build without postgres by `go build --tags nopostgres` but it's on by default
+//go:build !nopostgres
+// +build !nopostgres
+
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/algorand/go-algorand/data/bookkeeping"
+	"github.com/algorand/go-algorand/data/transactions"
+	"github.com/algorand/go-algorand/protocol"
+	"github.com/jackc/pgx/v4"
+
+	"github.com/algorand/indexer/idb"
+	"github.com/algorand/indexer/idb/postgres/internal/encoding"
+	"github.com/algorand/indexer/idb/postgres/internal/writer"
+)
+
+// rederivedTables are the tables which Rederive() rebuilds.
+var rederivedTables = []string{
+	"account", "account_asset", "account_app", "asset", "app", "account_totals"}
+
+// loadStoredBlock reassembles block `round` from its stored header and
+// transactions.
+func loadStoredBlock(ctx context.Context, tx pgx.Tx, round uint64) (bookkeeping.Block, error) {
+	var headerJSON []byte
+	err := tx.QueryRow(
+		ctx, "SELECT header FROM block_header WHERE round = $1", round).Scan(&headerJSON)
+	if err != nil {
+		return bookkeeping.Block{}, fmt.Errorf("loadStoredBlock() header err: %w", err)
+	}
+	header, err := encoding.DecodeBlockHeader(headerJSON)
+	if err != nil {
+		return bookkeeping.Block{}, fmt.Errorf("loadStoredBlock() decode header err: %w", err)
+	}
+	block := bookkeeping.Block{BlockHeader: header}
+
+	rows, err := tx.Query(
+		ctx, "SELECT txnbytes FROM txn WHERE round = $1 ORDER BY intra", round)
+	if err != nil {
+		return bookkeeping.Block{}, fmt.Errorf("loadStoredBlock() query err: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var txnbytes []byte
+		err = rows.Scan(&txnbytes)
+		if err != nil {
+			return bookkeeping.Block{}, fmt.Errorf("loadStoredBlock() scan err: %w", err)
+		}
+		txnbytes, err = encoding.DecompressTxnBytes(txnbytes)
+		if err != nil {
+			return bookkeeping.Block{}, fmt.Errorf("loadStoredBlock() err: %w", err)
+		}
+		var stxn transactions.SignedTxnWithAD
+		err = protocol.Decode(txnbytes, &stxn)
+		if err != nil {
+			return bookkeeping.Block{}, fmt.Errorf("loadStoredBlock() decode err: %w", err)
+		}
+		// Strips the genesis information which the header implies, as in the
+		// original block, so that the block hashes the same.
+		stib, err := header.EncodeSignedTxn(stxn.SignedTxn, stxn.ApplyData)
+		if err != nil {
+			return bookkeeping.Block{}, fmt.Errorf("loadStoredBlock() encode err: %w", err)
+		}
+		block.Payset = append(block.Payset, stib)
+	}
+	if err := rows.Err(); err != nil {
+		return bookkeeping.Block{}, fmt.Errorf("loadStoredBlock() rows err: %w", err)
+	}
+	return block, nil
+}
+
+// Rederive is part of idb.IndexerDb. The tables are rebuilt in one database
+// transaction, queries keep seeing the stored tables until it commits. The import
+// has to be stopped, it conflicts with the transaction.
+func (db *IndexerDb) Rederive(ctx context.Context, genesis bookkeeping.Genesis, repair bool) ([]idb.RederivedTable, error) {
+	results, err := db.rederive(ctx, genesis, repair, nil)
+	if err != nil {
+		return nil, fmt.Errorf("Rederive() err: %w", err)
+	}
+	return results, nil
+}
+
+// Reprocess is part of idb.IndexerDb. Like Rederive() it runs in one database
+// transaction, which conflicts with the import.
+func (db *IndexerDb) Reprocess(ctx context.Context, genesis bookkeeping.Genesis, blocks []*bookkeeping.Block) ([]idb.RederivedTable, error) {
+	if len(blocks) == 0 {
+		return nil, fmt.Errorf("Reprocess() no blocks")
+	}
+	replacements := make(map[uint64]*bookkeeping.Block, len(blocks))
+	for i, block := range blocks {
+		if uint64(block.Round()) != uint64(blocks[0].Round())+uint64(i) {
+			return nil, fmt.Errorf("Reprocess() blocks are not consecutive")
+		}
+		replacements[uint64(block.Round())] = block
+	}
+
+	results, err := db.rederive(ctx, genesis, true, replacements)
+	if err != nil {
+		return nil, fmt.Errorf("Reprocess() err: %w", err)
+	}
+	return results, nil
+}
+
+// deleteStoredBlock deletes the header and the transactions of `round`.
+func deleteStoredBlock(ctx context.Context, tx pgx.Tx, round uint64) error {
+	for _, table := range []string{"block_header", "txn", "txn_participation"} {
+		_, err := tx.Exec(ctx, "DELETE FROM "+table+" WHERE round = $1", round)
+		if err != nil {
+			return fmt.Errorf("deleteStoredBlock() %s err: %w", table, err)
+		}
+	}
+	return nil
+}
+
+// rederive rebuilds the account state from the stored blocks and compares it
+// with the stored state, which is replaced if `repair` is set. The blocks in
+// `replacements` are written instead of the stored blocks of their rounds.
+func (db *IndexerDb) rederive(ctx context.Context, genesis bookkeeping.Genesis, repair bool, replacements map[uint64]*bookkeeping.Block) ([]idb.RederivedTable, error) {
+	if db.readonly {
+		return nil, fmt.Errorf("rederive() cannot rebuild tables in read only mode")
+	}
+	if db.cockroachCompat {
+		// The stored rows are kept in temporary tables.
+		return nil, fmt.Errorf("rederive() not supported with CockroachDB compatibility")
+	}
+
+	// The stored blocks must be complete to evaluate them again.
+	if db.txnRules != nil {
+		return nil, fmt.Errorf("rederive() the import stores the transactions selected by transaction rules")
+	}
+	importstate, err := db.getImportState(ctx, nil)
+	if err != nil && err != idb.ErrorNotInitialized {
+		return nil, fmt.Errorf("rederive() err: %w", err)
+	}
+	if importstate.TxnRules {
+		return nil, fmt.Errorf("rederive() the database was imported with transaction rules, the skipped transactions are not stored")
+	}
+	if db.addressScope != nil {
+		return nil, fmt.Errorf("rederive() the import stores the transactions of an address scope")
+	}
+	if len(importstate.AddressScope) > 0 {
+		return nil, fmt.Errorf(
+			"rederive() the database was imported with the address scope %s, the other transactions are not stored",
+			strings.Join(importstate.AddressScope, ","))
+	}
+
+	db.accountingLock.Lock()
+	defer db.accountingLock.Unlock()
+	// The blocks are evaluated with the account cache, which must not keep the
+	// stored state going in or the rebuilt one coming out.
+	db.accountCache.Clear()
+	defer db.accountCache.Clear()
+
+	retention, err := db.getRetentionState(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("rederive() err: %w", err)
+	}
+	if retention.EarliestRound > 0 {
+		return nil, fmt.Errorf(
+			"rederive() the transactions before round %d were pruned", retention.EarliestRound)
+	}
+
+	tx, err := db.db.BeginTx(ctx, serializable)
+	if err != nil {
+		return nil, fmt.Errorf("rederive() begin err: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	nextRound, err := db.getNextRoundToAccount(ctx, tx)
+	if err != nil {
+		return nil, fmt.Errorf("rederive() err: %w", err)
+	}
+
+	// Keep the stored rows to compare with, and start from the genesis accounts.
+	for _, table := range rederivedTables {
+		query := fmt.Sprintf(
+			"CREATE TEMP TABLE stored_%s ON COMMIT DROP AS SELECT * FROM %s", table, table)
+		_, err = tx.Exec(ctx, query)
+		if err != nil {
+			return nil, fmt.Errorf("rederive() copy %s err: %w", table, err)
+		}
+		_, err = tx.Exec(ctx, "DELETE FROM "+table)
+		if err != nil {
+			return nil, fmt.Errorf("rederive() delete %s err: %w", table, err)
+		}
+	}
+	err = db.loadGenesisAccounts(tx, genesis)
+	if err != nil {
+		return nil, fmt.Errorf("rederive() err: %w", err)
+	}
+
+	w, err := writer.MakeWriter(tx)
+	if err != nil {
+		return nil, fmt.Errorf("rederive() err: %w", err)
+	}
+	defer w.Close()
+	w.StateOnly()
+
+	// Writes the replaced blocks with their transactions. They are not new
+	// rounds, nobody is notified.
+	full, err := writer.MakeWriter(tx)
+	if err != nil {
+		return nil, fmt.Errorf("rederive() err: %w", err)
+	}
+	defer full.Close()
+	full.DisableNotify()
+	if db.compressTxnBytes {
+		full.EnableCompression()
+	}
+
+	for round := range replacements {
+		if round >= nextRound {
+			return nil, fmt.Errorf(
+				"rederive() round %d is not imported, the next round is %d", round, nextRound)
+		}
+	}
+
+	var prev bookkeeping.BlockHeader
+	for round := uint64(0); round < nextRound; round++ {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		replacement, replaced := replacements[round]
+		var block bookkeeping.Block
+		if replaced {
+			block = *replacement
+		} else {
+			block, err = loadStoredBlock(ctx, tx, round)
+			if err != nil {
+				return nil, fmt.Errorf("rederive() round %d err: %w", round, err)
+			}
+		}
+		// The replaced blocks must chain with the blocks around them.
+		if round > 0 && (replaced || replacements[round-1] != nil) &&
+			block.Branch != bookkeeping.BlockHash(prev.Hash()) {
+			return nil, fmt.Errorf(
+				"rederive() block %d does not follow the block of the previous round", round)
+		}
+		prev = block.BlockHeader
+
+		if replaced {
+			err = deleteStoredBlock(ctx, tx, round)
+			if err != nil {
+				return nil, fmt.Errorf("rederive() round %d err: %w", round, err)
+			}
+			_, err = db.addBlock(ctx, tx, &full, &block, nil)
+		} else {
+			_, err = db.addBlock(ctx, tx, &w, &block, nil)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("rederive() round %d err: %w", round, err)
+		}
+		if round%1000 == 999 {
+			db.log.Infof("rederive() evaluated rounds up to %d", round)
+		}
+	}
+
+	results := make([]idb.RederivedTable, 0, len(rederivedTables))
+	for _, table := range rederivedTables {
+		result := idb.RederivedTable{Table: table}
+		err = tx.QueryRow(ctx, "SELECT count(*) FROM "+table).Scan(&result.Rows)
+		if err != nil {
+			return nil, fmt.Errorf("rederive() count %s err: %w", table, err)
+		}
+		query := fmt.Sprintf(
+			"SELECT count(*) FROM (SELECT * FROM stored_%s EXCEPT ALL SELECT * FROM %s) d",
+			table, table)
+		err = tx.QueryRow(ctx, query).Scan(&result.Missing)
+		if err != nil {
+			return nil, fmt.Errorf("rederive() compare %s err: %w", table, err)
+		}
+		query = fmt.Sprintf(
+			"SELECT count(*) FROM (SELECT * FROM %s EXCEPT ALL SELECT * FROM stored_%s) d",
+			table, table)
+		err = tx.QueryRow(ctx, query).Scan(&result.Extra)
+		if err != nil {
+			return nil, fmt.Errorf("rederive() compare %s err: %w", table, err)
+		}
+		results = append(results, result)
+	}
+
+	if repair {
+		err = tx.Commit(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("rederive() commit err: %w", err)
+		}
+	}
+	return results, nil
+}
@@ -0,0 +1,162 @@
+// You can build without postgres by `go build --tags nopostgres` but it's on by default
+//go:build !nopostgres
+// +build !nopostgres
+
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/jackc/pgx/v4/pgxpool"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/algorand/indexer/idb"
+	"github.com/algorand/indexer/idb/postgres/internal/encoding"
+	"github.com/algorand/indexer/idb/postgres/internal/schema"
+	pgutil "github.com/algorand/indexer/idb/postgres/internal/util"
+	"github.com/algorand/indexer/util/metrics"
+)
+
+// How often the lag of the replica is checked.
+const replicaLagCheckInterval = 5 * time.Second
+
+// replayLagQuery returns the seconds since a streaming replica replayed the
+// latest transaction of the primary, or 0 on a database which is not a standby.
+// The import writes every round, so the time grows only when the replica falls
+// behind or the import stops.
+const replayLagQuery = "SELECT COALESCE(EXTRACT(EPOCH FROM now() - pg_last_xact_replay_timestamp()), 0)::float8"
+
+// replicaLag is the number of rounds the replica was behind the primary at the
+// last check.
+var replicaLag = metrics.DefaultRegistry.NewGaugeVec(
+	"postgres_replica_lag_rounds", "Rounds the read replica is behind the primary.")
+
+// replicaLagSeconds is the time since the replica replayed the latest
+// transaction of the primary at the last check.
+var replicaLagSeconds = metrics.DefaultRegistry.NewGaugeVec(
+	"postgres_replica_lag_seconds",
+	"Seconds since the read replica replayed the latest transaction of the primary.")
+
+// replica is a read-only database, e.g. a streaming replica of the primary,
+// which serves the API queries while the import writes to the primary.
+type replica struct {
+	pool *pgxpool.Pool
+
+	// maxLag is the number of rounds the replica may be behind the primary
+	// before the queries fall back to the primary.
+	maxLag uint64
+
+	// usable is 1 while the replica was at most maxLag rounds behind at the
+	// last check, accessed atomically.
+	usable uint32
+	// lag is the number of rounds the replica was behind at the last
+	// successful check, accessed atomically.
+	lag uint64
+}
+
+func makeReplica(pool *pgxpool.Pool, maxLag uint64) *replica {
+	return &replica{pool: pool, maxLag: maxLag}
+}
+
+// readDB returns the pool for read-only queries. That is the replica unless it
+// is not configured, can't be reached or is more than maxLag rounds behind the
+// primary, then it is the primary. The replica is checked in the background by
+// monitor(), so that a slow replica does not delay the queries.
+func (db *IndexerDb) readDB() *pgxpool.Pool {
+	if db.replica != nil && atomic.LoadUint32(&db.replica.usable) == 1 {
+		return db.replica.pool
+	}
+	return db.db
+}
+
+// monitor checks the lag of the replica every replicaLagCheckInterval until
+// `ctx` is done. The queries use the primary until the first check succeeds.
+func (r *replica) monitor(ctx context.Context, primary *pgxpool.Pool, l *log.Logger) {
+	ticker := time.NewTicker(replicaLagCheckInterval)
+	defer ticker.Stop()
+	for {
+		r.update(ctx, primary, l)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// update checks the lag of the replica and publishes whether it is usable.
+func (r *replica) update(ctx context.Context, primary *pgxpool.Pool, l *log.Logger) {
+	ctx, cancel := context.WithTimeout(ctx, replicaLagCheckInterval)
+	usable, err := r.check(ctx, primary)
+	cancel()
+
+	wasUsable := atomic.LoadUint32(&r.usable) == 1
+	if err != nil {
+		l.WithError(err).Warn("update() replica check failed, using the primary")
+	} else if usable != wasUsable {
+		if usable {
+			l.Info("update() replica caught up, using the replica")
+		} else {
+			l.Warnf("update() replica is more than %d rounds behind, using the primary", r.maxLag)
+		}
+	}
+	if usable {
+		atomic.StoreUint32(&r.usable, 1)
+	} else {
+		atomic.StoreUint32(&r.usable, 0)
+	}
+}
+
+// check returns true if the replica is at most maxLag rounds behind `primary`.
+func (r *replica) check(ctx context.Context, primary *pgxpool.Pool) (bool, error) {
+	primaryRound, err := nextRoundToAccount(ctx, primary)
+	if err != nil {
+		return false, fmt.Errorf("check() primary err: %w", err)
+	}
+	replicaRound, err := nextRoundToAccount(ctx, r.pool)
+	if err != nil {
+		return false, fmt.Errorf("check() replica err: %w", err)
+	}
+
+	var lag uint64
+	if replicaRound < primaryRound {
+		lag = primaryRound - replicaRound
+	}
+	replicaLag.WithLabelValues().Set(float64(lag))
+	atomic.StoreUint64(&r.lag, lag)
+
+	// Not every replica replays the transactions of the primary, e.g. a
+	// CockroachDB follower, then the time is not reported.
+	var seconds float64
+	if r.pool.QueryRow(ctx, replayLagQuery).Scan(&seconds) == nil {
+		replicaLagSeconds.WithLabelValues().Set(seconds)
+	}
+	return lag <= r.maxLag, nil
+}
+
+// lastLag returns the lag of the replica at the last successful check.
+func (r *replica) lastLag() uint64 {
+	return atomic.LoadUint64(&r.lag)
+}
+
+// nextRoundToAccount reads the next round to account from the import state in
+// `pool`.
+func nextRoundToAccount(ctx context.Context, pool *pgxpool.Pool) (uint64, error) {
+	importStateJSON, err := pgutil.GetMetastate(ctx, pool, nil, schema.StateMetastateKey)
+	if err != nil {
+		return 0, err
+	}
+
+	var state importState
+	err = encoding.DecodeJSON([]byte(importStateJSON), &state)
+	if err != nil {
+		return 0, fmt.Errorf("nextRoundToAccount() decode err: %w", err)
+	}
+	if state.NextRoundToAccount == nil {
+		return 0, idb.ErrorNotInitialized
+	}
+	return *state.NextRoundToAccount, nil
+}
@@ -0,0 +1,207 @@
+// You can build without postgres by `go build --tags nopostgres` but it's on by default
+//go:build !nopostgres
+// +build !nopostgres
+
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/jackc/pgx/v4"
+
+	"github.com/algorand/indexer/idb"
+	"github.com/algorand/indexer/idb/postgres/internal/encoding"
+	"github.com/algorand/indexer/idb/postgres/internal/schema"
+)
+
+// retentionState is the progress of PruneTransactions() and PruneParticipation().
+type retentionState struct {
+	// Earliest round whose transactions are available.
+	EarliestRound uint64 `codec:"earliest_round"`
+	// Earliest round whose participation rows are available.
+	EarliestParticipationRound uint64 `codec:"earliest_participation_round"`
+}
+
+// If `tx` is nil, use a normal query.
+func (db *IndexerDb) getRetentionState(ctx context.Context, tx pgx.Tx) (retentionState, error) {
+	stateJSON, err := db.getMetastate(ctx, tx, schema.RetentionMetastateKey)
+	if err == idb.ErrorNotInitialized {
+		return retentionState{}, nil
+	}
+	if err != nil {
+		return retentionState{}, fmt.Errorf("getRetentionState() err: %w", err)
+	}
+
+	var state retentionState
+	err = encoding.DecodeJSON([]byte(stateJSON), &state)
+	if err != nil {
+		return retentionState{}, fmt.Errorf("getRetentionState() decode err: %w", err)
+	}
+	return state, nil
+}
+
+func setRetentionState(ctx context.Context, tx pgx.Tx, state retentionState) error {
+	_, err := tx.Exec(
+		ctx, setMetastateUpsert, schema.RetentionMetastateKey,
+		string(encoding.EncodeJSON(state)))
+	if err != nil {
+		return fmt.Errorf("setRetentionState() err: %w", err)
+	}
+	return nil
+}
+
+// GetRetention is part of idb.IndexerDb.
+func (db *IndexerDb) GetRetention(ctx context.Context) (idb.Retention, error) {
+	state, err := db.getRetentionState(ctx, nil)
+	if err != nil {
+		return idb.Retention{}, err
+	}
+	return idb.Retention{
+		EarliestRound:              state.EarliestRound,
+		EarliestParticipationRound: state.EarliestParticipationRound,
+	}, nil
+}
+
+// pruneRange returns the rounds [start, end) to prune from `earliest` towards
+// `round` in a batch of at most `maxRounds`, never including rounds which have
+// not been imported. It returns end <= start if there is nothing to prune.
+func (db *IndexerDb) pruneRange(ctx context.Context, tx pgx.Tx, earliest, round, maxRounds uint64) (uint64, uint64, error) {
+	nextRound, err := db.getNextRoundToAccount(ctx, tx)
+	if err != nil {
+		return 0, 0, err
+	}
+	if round > nextRound {
+		round = nextRound
+	}
+	if round <= earliest {
+		return earliest, earliest, nil
+	}
+	end := round
+	if maxRounds != 0 && end-earliest > maxRounds {
+		end = earliest + maxRounds
+	}
+	return earliest, end, nil
+}
+
+// PruneTransactions is part of idb.IndexerDb. The rounds are deleted in order
+// and the earliest round is updated in the same database transaction, so an
+// interrupted run resumes where it stopped.
+func (db *IndexerDb) PruneTransactions(ctx context.Context, round uint64, maxRounds uint64) (uint64, error) {
+	if db.readonly {
+		return 0, fmt.Errorf("PruneTransactions() cannot delete rows in read only mode")
+	}
+
+	var earliest uint64
+	f := func(tx pgx.Tx) error {
+		defer tx.Rollback(ctx)
+
+		state, err := db.getRetentionState(ctx, tx)
+		if err != nil {
+			return fmt.Errorf("PruneTransactions() err: %w", err)
+		}
+		start, end, err := db.pruneRange(ctx, tx, state.EarliestRound, round, maxRounds)
+		if err != nil {
+			return fmt.Errorf("PruneTransactions() err: %w", err)
+		}
+		earliest = start
+		if end <= start {
+			return nil
+		}
+
+		for _, table := range []string{"txn_participation", "txn"} {
+			query := fmt.Sprintf("DELETE FROM %s WHERE round >= $1 AND round < $2", table)
+			_, err = tx.Exec(ctx, query, start, end)
+			if err != nil {
+				return fmt.Errorf("PruneTransactions() delete %s err: %w", table, err)
+			}
+		}
+
+		state.EarliestRound = end
+		if state.EarliestParticipationRound < end {
+			state.EarliestParticipationRound = end
+		}
+		err = setRetentionState(ctx, tx, state)
+		if err != nil {
+			return fmt.Errorf("PruneTransactions() err: %w", err)
+		}
+
+		err = tx.Commit(ctx)
+		if err != nil {
+			return err
+		}
+		earliest = end
+		return nil
+	}
+	err := db.txWithRetry(serializable, f)
+	if err != nil {
+		return 0, err
+	}
+	return earliest, nil
+}
+
+// PruneParticipation is part of idb.IndexerDb. The transaction is not retried,
+// the rows would be archived twice. Only old rounds are deleted, which the
+// import does not write, so it does not need to be serializable.
+func (db *IndexerDb) PruneParticipation(ctx context.Context, round uint64, maxRounds uint64, archive io.Writer) (uint64, error) {
+	if db.readonly {
+		return 0, fmt.Errorf("PruneParticipation() cannot delete rows in read only mode")
+	}
+
+	tx, err := db.db.Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("PruneParticipation() begin err: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	// Wait for a concurrent PruneTransactions() to commit its state.
+	_, err = tx.Exec(
+		ctx, "SELECT 1 FROM metastate WHERE k = $1 FOR UPDATE", schema.RetentionMetastateKey)
+	if err != nil {
+		return 0, fmt.Errorf("PruneParticipation() lock err: %w", err)
+	}
+	state, err := db.getRetentionState(ctx, tx)
+	if err != nil {
+		return 0, fmt.Errorf("PruneParticipation() err: %w", err)
+	}
+	start, end, err := db.pruneRange(
+		ctx, tx, state.EarliestParticipationRound, round, maxRounds)
+	if err != nil {
+		return 0, fmt.Errorf("PruneParticipation() err: %w", err)
+	}
+	if end <= start {
+		return start, nil
+	}
+
+	if archive != nil {
+		// COPY does not take parameters, the rounds are integers.
+		query := fmt.Sprintf(
+			"COPY (SELECT addr, round, intra FROM txn_participation "+
+				"WHERE round >= %d AND round < %d ORDER BY round, intra, addr) "+
+				"TO STDOUT WITH (FORMAT csv)",
+			start, end)
+		_, err = tx.Conn().PgConn().CopyTo(ctx, archive, query)
+		if err != nil {
+			return 0, fmt.Errorf("PruneParticipation() archive err: %w", err)
+		}
+	}
+
+	_, err = tx.Exec(
+		ctx, "DELETE FROM txn_participation WHERE round >= $1 AND round < $2", start, end)
+	if err != nil {
+		return 0, fmt.Errorf("PruneParticipation() delete err: %w", err)
+	}
+
+	state.EarliestParticipationRound = end
+	err = setRetentionState(ctx, tx, state)
+	if err != nil {
+		return 0, fmt.Errorf("PruneParticipation() err: %w", err)
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("PruneParticipation() commit err: %w", err)
+	}
+	return end, nil
+}
@@ -0,0 +1,78 @@
+// You can build without postgres by `go build --tags nopostgres` but it's on by default
+//go:build !nopostgres
+// +build !nopostgres
+
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v4/pgxpool"
+
+	"github.com/algorand/indexer/idb"
+)
+
+const serverSettingsQuery = `SELECT
+	current_setting('max_connections')::bigint,
+	current_setting('superuser_reserved_connections')::bigint,
+	(SELECT count(*) FROM pg_stat_activity),
+	current_setting('work_mem'),
+	current_setting('shared_buffers')`
+
+func loadServerSettings(ctx context.Context, pool *pgxpool.Pool) (idb.ServerSettings, error) {
+	var settings idb.ServerSettings
+	err := pool.QueryRow(ctx, serverSettingsQuery).Scan(
+		&settings.MaxConnections, &settings.SuperuserReservedConnections,
+		&settings.ConnectionsInUse, &settings.WorkMem, &settings.SharedBuffers)
+	if err != nil {
+		return idb.ServerSettings{}, fmt.Errorf("loadServerSettings() err: %w", err)
+	}
+	settings.PoolMaxConnections = pool.Config().MaxConns
+	return settings, nil
+}
+
+// checkServerSettings returns an error if the connection pool can never be filled
+// because the server does not allow that many connections. It logs a warning if
+// the pool leaves little room for other clients, e.g. additional read only
+// indexers sharing the database.
+func (db *IndexerDb) checkServerSettings(settings idb.ServerSettings) error {
+	available := settings.MaxConnections - settings.SuperuserReservedConnections
+	pool := int64(settings.PoolMaxConnections)
+
+	if pool > available {
+		return fmt.Errorf(
+			"checkServerSettings() connection pool size %d exceeds the %d connections "+
+				"available on the server (max_connections=%d, "+
+				"superuser_reserved_connections=%d), lower the pool size or raise "+
+				"max_connections",
+			pool, available, settings.MaxConnections,
+			settings.SuperuserReservedConnections)
+	}
+	if pool+settings.ConnectionsInUse > available {
+		db.log.Warnf(
+			"connection pool size %d and the %d connections already in use exceed the "+
+				"%d connections available on the server, queries may fail under load",
+			pool, settings.ConnectionsInUse, available)
+	} else if pool > available/2 {
+		db.log.Warnf(
+			"connection pool size %d uses more than half of the %d connections "+
+				"available on the server, other clients may run out of connections",
+			pool, available)
+	}
+
+	db.log.Infof(
+		"postgres server settings: max_connections=%d work_mem=%s shared_buffers=%s, "+
+			"connection pool size %d",
+		settings.MaxConnections, settings.WorkMem, settings.SharedBuffers, pool)
+	return nil
+}
+
+// GetServerSettings is part of idb.IndexerDb.
+func (db *IndexerDb) GetServerSettings(ctx context.Context) (idb.ServerSettings, error) {
+	if db.cockroachCompat {
+		return idb.ServerSettings{}, fmt.Errorf(
+			"GetServerSettings() not supported with CockroachDB compatibility")
+	}
+	return loadServerSettings(ctx, db.db)
+}
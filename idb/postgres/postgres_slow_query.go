@@ -0,0 +1,140 @@
+// You can build without postgres by `go build --tags nopostgres` but it's on by default
+//go:build !nopostgres
+// +build !nopostgres
+
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/algorand/indexer/idb"
+)
+
+const (
+	// maxSlowQueryLength is the length at which the SQL of a slow query is cut.
+	maxSlowQueryLength = 2000
+	// maxSlowQueryArgLength is the length at which each parameter is cut.
+	maxSlowQueryArgLength = 32
+)
+
+// slowQueryLogger is the pgx logger which logs the statements which take longer
+// than the threshold. pgx measures a query until its rows are closed, so the
+// time of the API queries includes streaming the rows to the response.
+type slowQueryLogger struct {
+	log       *log.Logger
+	threshold time.Duration
+}
+
+// logSlowQueries makes the pool of `config` log the statements which take
+// longer than `threshold` to `logger`.
+func logSlowQueries(config *pgxpool.Config, threshold time.Duration, logger *log.Logger) {
+	if logger == nil {
+		logger = log.StandardLogger()
+	}
+	config.ConnConfig.Logger = slowQueryLogger{log: logger, threshold: threshold}
+	config.ConnConfig.LogLevel = pgx.LogLevelInfo
+}
+
+// Log is part of pgx.Logger. The statements which pgx measures have a time,
+// the others and the errors are ignored.
+func (l slowQueryLogger) Log(ctx context.Context, level pgx.LogLevel, msg string, data map[string]interface{}) {
+	duration, ok := data["time"].(time.Duration)
+	if !ok || duration < l.threshold {
+		return
+	}
+	query, _ := data["sql"].(string)
+	if table, ok := data["tableName"].(pgx.Identifier); ok {
+		query = "COPY " + table.Sanitize()
+	}
+	fields := log.Fields{
+		"duration":  duration.Seconds(),
+		"operation": msg,
+		"query":     normalizeSQL(query),
+	}
+	if args, ok := data["args"].([]interface{}); ok && len(args) > 0 {
+		fields["args"] = summarizeArgs(args)
+	}
+	if rows, ok := data["rowCount"]; ok {
+		fields["rows"] = rows
+	}
+	if endpoint := idb.EndpointFromContext(ctx); endpoint != "" {
+		fields["endpoint"] = endpoint
+	}
+	if id := idb.RequestIDFromContext(ctx); id != "" {
+		fields["request_id"] = id
+	}
+	l.log.WithFields(fields).Warn("slow query")
+}
+
+// normalizeSQL replaces the literals of `query` with ? and its whitespace with
+// single spaces, so that the same statement with different values, e.g. rounds
+// or limits, logs the same SQL. Parameters like $1 are kept.
+func normalizeSQL(query string) string {
+	var b strings.Builder
+	space := false
+	for i := 0; i < len(query); i++ {
+		c := query[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			space = b.Len() > 0
+			continue
+		case c == '\'':
+			// A quote in a string is escaped by doubling it.
+			for i++; i < len(query); i++ {
+				if query[i] == '\'' {
+					if i+1 < len(query) && query[i+1] == '\'' {
+						i++
+						continue
+					}
+					break
+				}
+			}
+			c = '?'
+		case isDigit(c) && (i == 0 || !isIdentifierChar(query[i-1])):
+			for i+1 < len(query) && (isDigit(query[i+1]) || query[i+1] == '.') {
+				i++
+			}
+			c = '?'
+		}
+		if space {
+			b.WriteByte(' ')
+			space = false
+		}
+		b.WriteByte(c)
+		if b.Len() >= maxSlowQueryLength {
+			return b.String() + "..."
+		}
+	}
+	return b.String()
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+// isIdentifierChar returns true if a number after `c` is part of a name or a
+// parameter, e.g. txn_participation2 or $12.
+func isIdentifierChar(c byte) bool {
+	return c == '_' || c == '$' || isDigit(c) || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+// summarizeArgs formats the parameters of a query, each cut to
+// maxSlowQueryArgLength, e.g. "$1=10 $2=ab01...".
+func summarizeArgs(args []interface{}) string {
+	parts := make([]string, len(args))
+	for i, arg := range args {
+		value := fmt.Sprint(arg)
+		if len(value) > maxSlowQueryArgLength {
+			value = value[:maxSlowQueryArgLength] + "..."
+		}
+		parts[i] = fmt.Sprintf("$%d=%s", i+1, value)
+	}
+	return strings.Join(parts, " ")
+}
@@ -0,0 +1,350 @@
+// You can build without postgres by `go build --tags nopostgres` but it's on by default
+//go:build !nopostgres
+// +build !nopostgres
+
+package postgres
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v4"
+
+	"github.com/algorand/indexer/idb"
+	"github.com/algorand/indexer/idb/postgres/internal/encoding"
+)
+
+// snapshotTables are the tables of a snapshot in the order they are written.
+var snapshotTables = []string{
+	"metastate", "block_header", "txn", "txn_participation", "account",
+	"account_asset", "asset", "app", "account_app", "account_totals",
+}
+
+const (
+	// snapshotVersion is the version of the snapshot format.
+	snapshotVersion = 1
+
+	snapshotManifestName = "manifest.json"
+
+	// snapshotChunkSize is the size of the archive entries the rows of a table
+	// are split into, so that a table is neither held in memory nor spooled to
+	// disk to learn its size.
+	snapshotChunkSize = 32 << 20
+)
+
+// snapshotManifest is the first entry of a snapshot. It is followed by the
+// rows of each table in the text format of COPY, in entries named
+// "<table>/<chunk>" which are concatenated when the snapshot is imported.
+type snapshotManifest struct {
+	Version int `codec:"version"`
+	// Migration is the number of migrations applied to the exported schema,
+	// a snapshot can only be imported by the same version of the indexer.
+	Migration int `codec:"migration"`
+	// NextRound is the next round to import after the snapshot.
+	NextRound uint64              `codec:"next-round"`
+	Columns   map[string][]string `codec:"columns"`
+}
+
+// chunkWriter writes the rows of a table as tar entries of at most
+// snapshotChunkSize bytes.
+type chunkWriter struct {
+	tw     *tar.Writer
+	table  string
+	chunks int
+	buf    bytes.Buffer
+}
+
+func (w *chunkWriter) Write(p []byte) (int, error) {
+	n := len(p)
+	for len(p) > 0 {
+		room := snapshotChunkSize - w.buf.Len()
+		if room > len(p) {
+			room = len(p)
+		}
+		w.buf.Write(p[:room])
+		p = p[room:]
+		if w.buf.Len() == snapshotChunkSize {
+			err := w.flush()
+			if err != nil {
+				return 0, err
+			}
+		}
+	}
+	return n, nil
+}
+
+// flush writes the buffered rows as the next chunk.
+func (w *chunkWriter) flush() error {
+	if w.buf.Len() == 0 {
+		return nil
+	}
+	err := writeSnapshotEntry(
+		w.tw, fmt.Sprintf("%s/%06d", w.table, w.chunks), w.buf.Bytes())
+	if err != nil {
+		return err
+	}
+	w.chunks++
+	w.buf.Reset()
+	return nil
+}
+
+func writeSnapshotEntry(tw *tar.Writer, name string, data []byte) error {
+	header := tar.Header{
+		Name:    name,
+		Mode:    0644,
+		Size:    int64(len(data)),
+		ModTime: time.Now(),
+	}
+	err := tw.WriteHeader(&header)
+	if err != nil {
+		return fmt.Errorf("writeSnapshotEntry() header err: %w", err)
+	}
+	_, err = tw.Write(data)
+	if err != nil {
+		return fmt.Errorf("writeSnapshotEntry() write err: %w", err)
+	}
+	return nil
+}
+
+func getTableColumns(ctx context.Context, tx pgx.Tx, table string) ([]string, error) {
+	rows, err := tx.Query(
+		ctx,
+		"SELECT column_name FROM information_schema.columns "+
+			"WHERE table_schema = current_schema() AND table_name = $1 ORDER BY ordinal_position",
+		table)
+	if err != nil {
+		return nil, fmt.Errorf("getTableColumns() query err: %w", err)
+	}
+	defer rows.Close()
+
+	var columns []string
+	for rows.Next() {
+		var column string
+		err = rows.Scan(&column)
+		if err != nil {
+			return nil, fmt.Errorf("getTableColumns() scan err: %w", err)
+		}
+		columns = append(columns, column)
+	}
+	return columns, rows.Err()
+}
+
+func copyColumns(table string, columns []string) string {
+	quoted := make([]string, len(columns))
+	for i, column := range columns {
+		quoted[i] = pgx.Identifier{column}.Sanitize()
+	}
+	return fmt.Sprintf(
+		"%s (%s)", pgx.Identifier{table}.Sanitize(), strings.Join(quoted, ", "))
+}
+
+// ExportSnapshot is part of idb.IndexerDb. The tables are read in one repeatable
+// read transaction, the import may continue meanwhile. Columns are listed by
+// name, because the order of the columns depends on how the schema was migrated.
+func (db *IndexerDb) ExportSnapshot(ctx context.Context, w io.Writer) (uint64, error) {
+	migrationState, err := db.getMigrationState()
+	if err != nil {
+		return 0, fmt.Errorf("ExportSnapshot() err: %w", err)
+	}
+	if migrationState.NextMigration != len(migrations) {
+		return 0, fmt.Errorf("ExportSnapshot() migrations are pending, export after they finish")
+	}
+
+	tx, err := db.db.BeginTx(ctx, readonlyRepeatableRead)
+	if err != nil {
+		return 0, fmt.Errorf("ExportSnapshot() begin err: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	manifest := snapshotManifest{
+		Version:   snapshotVersion,
+		Migration: migrationState.NextMigration,
+		Columns:   make(map[string][]string, len(snapshotTables)),
+	}
+	manifest.NextRound, err = db.getNextRoundToAccount(ctx, tx)
+	if err != nil {
+		return 0, fmt.Errorf("ExportSnapshot() err: %w", err)
+	}
+	for _, table := range snapshotTables {
+		manifest.Columns[table], err = getTableColumns(ctx, tx, table)
+		if err != nil {
+			return 0, fmt.Errorf("ExportSnapshot() %s err: %w", table, err)
+		}
+	}
+
+	tw := tar.NewWriter(w)
+	err = writeSnapshotEntry(tw, snapshotManifestName, encoding.EncodeJSON(manifest))
+	if err != nil {
+		return 0, fmt.Errorf("ExportSnapshot() err: %w", err)
+	}
+	for _, table := range snapshotTables {
+		cw := chunkWriter{tw: tw, table: table}
+		query := fmt.Sprintf(
+			"COPY %s TO STDOUT", copyColumns(table, manifest.Columns[table]))
+		_, err = tx.Conn().PgConn().CopyTo(ctx, &cw, query)
+		if err != nil {
+			return 0, fmt.Errorf("ExportSnapshot() copy %s err: %w", table, err)
+		}
+		err = cw.flush()
+		if err != nil {
+			return 0, fmt.Errorf("ExportSnapshot() %s err: %w", table, err)
+		}
+		db.log.Infof("ExportSnapshot() exported %s", table)
+	}
+	err = tw.Close()
+	if err != nil {
+		return 0, fmt.Errorf("ExportSnapshot() close err: %w", err)
+	}
+	return manifest.NextRound, nil
+}
+
+// tableReader reads the consecutive chunks of one table from a snapshot. The
+// header of the entry after the last chunk is kept in `next`.
+type tableReader struct {
+	tr    *tar.Reader
+	table string
+	next  *tar.Header
+}
+
+func (r *tableReader) Read(p []byte) (int, error) {
+	for {
+		n, err := r.tr.Read(p)
+		if err != io.EOF || n > 0 {
+			if err == io.EOF {
+				err = nil
+			}
+			return n, err
+		}
+		header, err := r.tr.Next()
+		if err == io.EOF {
+			r.next = nil
+			return 0, io.EOF
+		}
+		if err != nil {
+			return 0, err
+		}
+		if snapshotEntryTable(header.Name) != r.table {
+			r.next = header
+			return 0, io.EOF
+		}
+	}
+}
+
+func isSnapshotTable(table string) bool {
+	for _, t := range snapshotTables {
+		if t == table {
+			return true
+		}
+	}
+	return false
+}
+
+func snapshotEntryTable(name string) string {
+	return strings.SplitN(name, "/", 2)[0]
+}
+
+// ImportSnapshot is part of idb.IndexerDb. The snapshot is loaded in one
+// transaction, an interrupted import leaves the database empty.
+func (db *IndexerDb) ImportSnapshot(ctx context.Context, r io.Reader) (uint64, error) {
+	if db.readonly {
+		return 0, fmt.Errorf("ImportSnapshot() cannot import in read only mode")
+	}
+
+	db.accountingLock.Lock()
+	defer db.accountingLock.Unlock()
+
+	_, err := db.GetNextRoundToAccount()
+	if err != idb.ErrorNotInitialized {
+		if err != nil {
+			return 0, fmt.Errorf("ImportSnapshot() err: %w", err)
+		}
+		return 0, fmt.Errorf("ImportSnapshot() the database is not empty")
+	}
+
+	tr := tar.NewReader(r)
+	header, err := tr.Next()
+	if err != nil {
+		return 0, fmt.Errorf("ImportSnapshot() read err: %w", err)
+	}
+	if header.Name != snapshotManifestName {
+		return 0, fmt.Errorf("ImportSnapshot() %s is not a snapshot manifest", header.Name)
+	}
+	manifestJSON, err := ioutil.ReadAll(tr)
+	if err != nil {
+		return 0, fmt.Errorf("ImportSnapshot() read manifest err: %w", err)
+	}
+	var manifest snapshotManifest
+	err = encoding.DecodeJSON(manifestJSON, &manifest)
+	if err != nil {
+		return 0, fmt.Errorf("ImportSnapshot() decode manifest err: %w", err)
+	}
+	if manifest.Version != snapshotVersion {
+		return 0, fmt.Errorf(
+			"ImportSnapshot() unsupported snapshot version %d", manifest.Version)
+	}
+	if manifest.Migration != len(migrations) {
+		return 0, fmt.Errorf(
+			"ImportSnapshot() the snapshot was exported after %d migrations, this indexer has %d",
+			manifest.Migration, len(migrations))
+	}
+
+	tx, err := db.db.BeginTx(ctx, serializable)
+	if err != nil {
+		return 0, fmt.Errorf("ImportSnapshot() begin err: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	// The migration state written when the tables were created is replaced by
+	// the one of the snapshot, which is the same.
+	_, err = tx.Exec(ctx, "DELETE FROM metastate")
+	if err != nil {
+		return 0, fmt.Errorf("ImportSnapshot() delete err: %w", err)
+	}
+
+	header, err = tr.Next()
+	for err != io.EOF {
+		if err != nil {
+			return 0, fmt.Errorf("ImportSnapshot() read err: %w", err)
+		}
+		table := snapshotEntryTable(header.Name)
+		columns, ok := manifest.Columns[table]
+		if !ok || !isSnapshotTable(table) {
+			return 0, fmt.Errorf("ImportSnapshot() unknown table %s", table)
+		}
+
+		tableReader := tableReader{tr: tr, table: table}
+		query := fmt.Sprintf("COPY %s FROM STDIN", copyColumns(table, columns))
+		_, err = tx.Conn().PgConn().CopyFrom(ctx, &tableReader, query)
+		if err != nil {
+			return 0, fmt.Errorf("ImportSnapshot() copy %s err: %w", table, err)
+		}
+		db.log.Infof("ImportSnapshot() imported %s", table)
+
+		header = tableReader.next
+		if header == nil {
+			err = io.EOF
+		}
+	}
+
+	nextRound, err := db.getNextRoundToAccount(ctx, tx)
+	if err != nil {
+		return 0, fmt.Errorf("ImportSnapshot() err: %w", err)
+	}
+	if nextRound != manifest.NextRound {
+		return 0, fmt.Errorf(
+			"ImportSnapshot() the snapshot is of round %d but contains round %d",
+			manifest.NextRound, nextRound)
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("ImportSnapshot() commit err: %w", err)
+	}
+	return nextRound, nil
+}
@@ -0,0 +1,114 @@
+// You can build without postgres by `go build --tags nopostgres` but it's on by default
+//go:build !nopostgres
+// +build !nopostgres
+
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/jackc/pgx/v4"
+
+	"github.com/algorand/indexer/idb"
+	"github.com/algorand/indexer/idb/postgres/internal/schema"
+)
+
+// If `tx` is nil, use a normal query.
+func (db *IndexerDb) getWebhooks(ctx context.Context, tx pgx.Tx) ([]idb.Webhook, error) {
+	hooksJSON, err := db.getMetastate(ctx, tx, schema.WebhooksMetastateKey)
+	if err == idb.ErrorNotInitialized {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("getWebhooks() err: %w", err)
+	}
+
+	var hooks []idb.Webhook
+	err = json.Unmarshal([]byte(hooksJSON), &hooks)
+	if err != nil {
+		return nil, fmt.Errorf("getWebhooks() decode err: %w", err)
+	}
+	return hooks, nil
+}
+
+func setWebhooks(ctx context.Context, tx pgx.Tx, hooks []idb.Webhook) error {
+	if hooks == nil {
+		hooks = []idb.Webhook{}
+	}
+	hooksJSON, err := json.Marshal(hooks)
+	if err != nil {
+		return fmt.Errorf("setWebhooks() encode err: %w", err)
+	}
+	_, err = tx.Exec(ctx, setMetastateUpsert, schema.WebhooksMetastateKey, string(hooksJSON))
+	if err != nil {
+		return fmt.Errorf("setWebhooks() err: %w", err)
+	}
+	return nil
+}
+
+// Webhooks is part of idb.IndexerDb.
+func (db *IndexerDb) Webhooks(ctx context.Context) ([]idb.Webhook, error) {
+	hooks, err := db.getWebhooks(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("Webhooks() err: %w", err)
+	}
+	return hooks, nil
+}
+
+// AddWebhook is part of idb.IndexerDb.
+func (db *IndexerDb) AddWebhook(ctx context.Context, hook idb.Webhook) error {
+	if db.readonly {
+		return fmt.Errorf("AddWebhook() cannot register webhooks in read only mode")
+	}
+	f := func(tx pgx.Tx) error {
+		hooks, err := db.getWebhooks(ctx, tx)
+		if err != nil {
+			return err
+		}
+		for _, h := range hooks {
+			if h.ID == hook.ID {
+				return idb.ErrorWebhookExists
+			}
+		}
+		return setWebhooks(ctx, tx, append(hooks, hook))
+	}
+	err := db.txWithRetry(serializable, f)
+	if err != nil {
+		return fmt.Errorf("AddWebhook() err: %w", err)
+	}
+	return nil
+}
+
+// DeleteWebhook is part of idb.IndexerDb.
+func (db *IndexerDb) DeleteWebhook(ctx context.Context, id string) (bool, error) {
+	if db.readonly {
+		return false, fmt.Errorf("DeleteWebhook() cannot remove webhooks in read only mode")
+	}
+	deleted := false
+	f := func(tx pgx.Tx) error {
+		deleted = false
+		hooks, err := db.getWebhooks(ctx, tx)
+		if err != nil {
+			return err
+		}
+		kept := make([]idb.Webhook, 0, len(hooks))
+		for _, h := range hooks {
+			if h.ID == id {
+				deleted = true
+				continue
+			}
+			kept = append(kept, h)
+		}
+		if !deleted {
+			return nil
+		}
+		return setWebhooks(ctx, tx, kept)
+	}
+	err := db.txWithRetry(serializable, f)
+	if err != nil {
+		return false, fmt.Errorf("DeleteWebhook() err: %w", err)
+	}
+	return deleted, nil
+}
@@ -0,0 +1,85 @@
+package idb
+
+import (
+	"context"
+)
+
+// The backends stream the rows of a query to a consumer which may stop reading
+// at any time, e.g. when the http request was abandoned. Each send also waits
+// for the context of the query, so that the thread streaming the rows never
+// blocks holding a database connection. When the context is done first, its
+// error is sent if the consumer is waiting for the next row, e.g. when the
+// deadline of the query passed. It can't be sent when the consumer is gone, so a
+// consumer which sets a deadline must still check ctx.Err() when the stream ends
+// without an error. The functions return false if the row was not sent, the
+// thread must stop.
+
+// SendTxnRow sends `row` to `out` unless `ctx` is done first.
+func SendTxnRow(ctx context.Context, out chan<- TxnRow, row TxnRow) bool {
+	select {
+	case out <- row:
+		return true
+	case <-ctx.Done():
+		select {
+		case out <- TxnRow{Error: ctx.Err()}:
+		default:
+		}
+		return false
+	}
+}
+
+// SendAccountRow sends `row` to `out` unless `ctx` is done first.
+func SendAccountRow(ctx context.Context, out chan<- AccountRow, row AccountRow) bool {
+	select {
+	case out <- row:
+		return true
+	case <-ctx.Done():
+		select {
+		case out <- AccountRow{Error: ctx.Err()}:
+		default:
+		}
+		return false
+	}
+}
+
+// SendAssetRow sends `row` to `out` unless `ctx` is done first.
+func SendAssetRow(ctx context.Context, out chan<- AssetRow, row AssetRow) bool {
+	select {
+	case out <- row:
+		return true
+	case <-ctx.Done():
+		select {
+		case out <- AssetRow{Error: ctx.Err()}:
+		default:
+		}
+		return false
+	}
+}
+
+// SendAssetBalanceRow sends `row` to `out` unless `ctx` is done first.
+func SendAssetBalanceRow(ctx context.Context, out chan<- AssetBalanceRow, row AssetBalanceRow) bool {
+	select {
+	case out <- row:
+		return true
+	case <-ctx.Done():
+		select {
+		case out <- AssetBalanceRow{Error: ctx.Err()}:
+		default:
+		}
+		return false
+	}
+}
+
+// SendApplicationRow sends `row` to `out` unless `ctx` is done first.
+func SendApplicationRow(ctx context.Context, out chan<- ApplicationRow, row ApplicationRow) bool {
+	select {
+	case out <- row:
+		return true
+	case <-ctx.Done():
+		select {
+		case out <- ApplicationRow{Error: ctx.Err()}:
+		default:
+		}
+		return false
+	}
+}
@@ -0,0 +1,182 @@
+package idb
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+
+	"github.com/algorand/go-algorand/data/basics"
+	"github.com/algorand/go-algorand/data/transactions"
+	"github.com/algorand/go-algorand/protocol"
+)
+
+// TxnRule matches transactions by type, sender, receiver and asset or app id.
+// A transaction matches if it matches every field which is set.
+type TxnRule struct {
+	// Types are transaction types, e.g. "pay" or "axfer".
+	Types []string `json:"types,omitempty"`
+
+	// Senders match the sender of the transaction.
+	Senders []string `json:"senders,omitempty"`
+
+	// Receivers match the receiver, asset receiver, close-to and asset close-to
+	// addresses of the transaction.
+	Receivers []string `json:"receivers,omitempty"`
+
+	// AssetIDs match the asset of asset config, transfer and freeze
+	// transactions, including the asset created by the transaction.
+	AssetIDs []uint64 `json:"asset-ids,omitempty"`
+
+	// AppIDs match the application of application call transactions, including
+	// the application created by the transaction.
+	AppIDs []uint64 `json:"app-ids,omitempty"`
+
+	// Exclude skips the matching transactions instead of storing them.
+	Exclude bool `json:"exclude,omitempty"`
+}
+
+// TxnRulesConfig is the file format of the transaction rules.
+type TxnRulesConfig struct {
+	// Rules are checked in order, the first rule which matches a transaction
+	// decides whether it is stored.
+	Rules []TxnRule `json:"rules"`
+
+	// ExcludeUnmatched skips the transactions which match no rule. By default
+	// they are stored.
+	ExcludeUnmatched bool `json:"exclude-unmatched,omitempty"`
+}
+
+type txnRule struct {
+	types     map[TxnTypeEnum]bool
+	senders   map[basics.Address]bool
+	receivers map[basics.Address]bool
+	assets    map[uint64]bool
+	apps      map[uint64]bool
+	exclude   bool
+}
+
+// TxnRules decides which transactions are stored by the import. The account
+// state is updated with all the transactions either way.
+type TxnRules struct {
+	rules            []txnRule
+	excludeUnmatched bool
+}
+
+func parseAddresses(addresses []string) (map[basics.Address]bool, error) {
+	if len(addresses) == 0 {
+		return nil, nil
+	}
+	res := make(map[basics.Address]bool, len(addresses))
+	for _, s := range addresses {
+		address, err := basics.UnmarshalChecksumAddress(s)
+		if err != nil {
+			return nil, fmt.Errorf("parseAddresses() address %s err: %w", s, err)
+		}
+		res[address] = true
+	}
+	return res, nil
+}
+
+func makeIDSet(ids []uint64) map[uint64]bool {
+	if len(ids) == 0 {
+		return nil
+	}
+	res := make(map[uint64]bool, len(ids))
+	for _, id := range ids {
+		res[id] = true
+	}
+	return res
+}
+
+// MakeTxnRules checks `config` and prepares its rules.
+func MakeTxnRules(config TxnRulesConfig) (*TxnRules, error) {
+	res := &TxnRules{excludeUnmatched: config.ExcludeUnmatched}
+	for i, rule := range config.Rules {
+		r := txnRule{
+			assets:  makeIDSet(rule.AssetIDs),
+			apps:    makeIDSet(rule.AppIDs),
+			exclude: rule.Exclude,
+		}
+		if len(rule.Types) > 0 {
+			r.types = make(map[TxnTypeEnum]bool, len(rule.Types))
+			for _, t := range rule.Types {
+				typeenum, ok := GetTypeEnum(protocol.TxType(t))
+				if !ok {
+					return nil, fmt.Errorf("MakeTxnRules() rule %d unknown type %s", i, t)
+				}
+				r.types[typeenum] = true
+			}
+		}
+		var err error
+		r.senders, err = parseAddresses(rule.Senders)
+		if err != nil {
+			return nil, fmt.Errorf("MakeTxnRules() rule %d senders err: %w", i, err)
+		}
+		r.receivers, err = parseAddresses(rule.Receivers)
+		if err != nil {
+			return nil, fmt.Errorf("MakeTxnRules() rule %d receivers err: %w", i, err)
+		}
+		res.rules = append(res.rules, r)
+	}
+	return res, nil
+}
+
+// LoadTxnRules reads a TxnRulesConfig in JSON from `path`.
+func LoadTxnRules(path string) (*TxnRules, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("LoadTxnRules() err: %w", err)
+	}
+	var config TxnRulesConfig
+	err = json.Unmarshal(data, &config)
+	if err != nil {
+		return nil, fmt.Errorf("LoadTxnRules() parse %s err: %w", path, err)
+	}
+	rules, err := MakeTxnRules(config)
+	if err != nil {
+		return nil, fmt.Errorf("LoadTxnRules() err: %w", err)
+	}
+	return rules, nil
+}
+
+func (r *txnRule) matches(txn *transactions.Transaction, typeenum TxnTypeEnum, assetid uint64) bool {
+	if r.types != nil && !r.types[typeenum] {
+		return false
+	}
+	if r.senders != nil && !r.senders[txn.Sender] {
+		return false
+	}
+	if r.receivers != nil && !r.receivers[txn.Receiver] &&
+		!r.receivers[txn.AssetReceiver] && !r.receivers[txn.CloseRemainderTo] &&
+		!r.receivers[txn.AssetCloseTo] {
+		return false
+	}
+	if r.assets != nil {
+		isAsset := (typeenum == TypeEnumAssetConfig) ||
+			(typeenum == TypeEnumAssetTransfer) || (typeenum == TypeEnumAssetFreeze)
+		if !isAsset || !r.assets[assetid] {
+			return false
+		}
+	}
+	if r.apps != nil {
+		if (typeenum != TypeEnumApplication) || !r.apps[assetid] {
+			return false
+		}
+	}
+	return true
+}
+
+// Store returns whether the transaction `txn` is stored. `assetid` is the asset
+// or app id of the transaction, as stored in the asset column of the txn table.
+// A nil TxnRules stores every transaction.
+func (rules *TxnRules) Store(txn *transactions.Transaction, typeenum TxnTypeEnum, assetid uint64) bool {
+	if rules == nil {
+		return true
+	}
+	for i := range rules.rules {
+		if rules.rules[i].matches(txn, typeenum, assetid) {
+			return !rules.rules[i].exclude
+		}
+	}
+	return !rules.excludeUnmatched
+}
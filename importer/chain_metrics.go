@@ -0,0 +1,262 @@
+package importer
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"time"
+
+	"github.com/algorand/go-algorand/data/basics"
+	"github.com/algorand/go-algorand/data/bookkeeping"
+	"github.com/algorand/go-algorand/protocol"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/push"
+
+	"github.com/algorand/indexer/idb"
+)
+
+// The transaction types which always have a series, so that a type without
+// transactions in a round reports 0 instead of no sample.
+var chainMetricsTxnTypes = []protocol.TxType{
+	protocol.PaymentTx,
+	protocol.KeyRegistrationTx,
+	protocol.AssetConfigTx,
+	protocol.AssetTransferTx,
+	protocol.AssetFreezeTx,
+	protocol.ApplicationCallTx,
+}
+
+// ChainStats are the statistics of a round.
+type ChainStats struct {
+	Round uint64
+	Time  time.Time
+
+	// Network is the genesis id of the block.
+	Network string
+
+	// Transactions counts the stored transactions by type.
+	Transactions map[protocol.TxType]uint64
+
+	// Fees is the sum of the fees of the stored transactions in microalgos.
+	Fees uint64
+
+	// NewAccounts counts the accounts which were created in the round.
+	NewAccounts uint64
+}
+
+// computeChainStats returns the statistics of a round. An account is created by
+// being paid, the receivers of the payments whose account was created in the
+// round are the new accounts.
+func computeChainStats(ctx context.Context, db idb.IndexerDb, header bookkeeping.BlockHeader, txns []idb.TxnRow) (ChainStats, error) {
+	stats := ChainStats{
+		Round:        uint64(header.Round),
+		Time:         time.Unix(header.TimeStamp, 0).UTC(),
+		Network:      header.GenesisID,
+		Transactions: make(map[protocol.TxType]uint64),
+	}
+
+	receivers := make(map[basics.Address]bool)
+	for _, row := range txns {
+		txn, err := MakeExportedTransaction(row)
+		if err != nil {
+			return ChainStats{}, fmt.Errorf("computeChainStats() err: %w", err)
+		}
+		stats.Transactions[txn.Txn.Txn.Type]++
+		stats.Fees += txn.Txn.Txn.Fee.Raw
+		if txn.Txn.Txn.Type == protocol.PaymentTx {
+			receivers[txn.Txn.Txn.Receiver] = true
+			if !txn.Txn.Txn.CloseRemainderTo.IsZero() {
+				receivers[txn.Txn.Txn.CloseRemainderTo] = true
+			}
+		}
+	}
+
+	for addr := range receivers {
+		opts := idb.AccountQueryOptions{EqualToAddress: addr[:], IncludeDeleted: true}
+		rows, _ := db.GetAccounts(ctx, opts)
+		for row := range rows {
+			if row.Error != nil {
+				// Drains the channel so that the query ends.
+				for range rows {
+				}
+				return ChainStats{}, fmt.Errorf("computeChainStats() err: %w", row.Error)
+			}
+			created := row.Account.CreatedAtRound
+			if created != nil && *created == stats.Round {
+				stats.NewAccounts++
+			}
+		}
+	}
+	return stats, nil
+}
+
+// ChainMetricsOptions configures a ChainMetricsSink, at least one of the URLs
+// must be set.
+type ChainMetricsOptions struct {
+	// RemoteWriteURL is a Prometheus remote write endpoint, e.g.
+	// http://prometheus:9090/api/v1/write. The statistics of every round are
+	// written with the time of the round.
+	RemoteWriteURL string
+
+	// PushgatewayURL is a Prometheus pushgateway, the running totals are pushed
+	// after every round.
+	PushgatewayURL string
+
+	// Job is the job label of the metrics.
+	Job string
+
+	// Timeout is the timeout of a request.
+	Timeout time.Duration
+}
+
+// ChainMetricsSink is an ExportSink which publishes the statistics of every
+// round to Prometheus, so that the history of the chain is kept for as long as
+// the Prometheus storage keeps it, independently of the scrapes of /metrics.
+type ChainMetricsSink struct {
+	db     idb.IndexerDb
+	opts   ChainMetricsOptions
+	client *http.Client
+
+	// The running totals for the pushgateway.
+	registry     *prometheus.Registry
+	transactions *prometheus.CounterVec
+	fees         prometheus.Counter
+	newAccounts  prometheus.Counter
+	round        prometheus.Gauge
+
+	// counted is set once a round was counted in the totals, countedRound is
+	// the latest one.
+	counted      bool
+	countedRound uint64
+}
+
+// MakeChainMetricsSink creates a ChainMetricsSink.
+func MakeChainMetricsSink(db idb.IndexerDb, opts ChainMetricsOptions) (*ChainMetricsSink, error) {
+	if opts.RemoteWriteURL == "" && opts.PushgatewayURL == "" {
+		return nil, fmt.Errorf("MakeChainMetricsSink() no remote write or pushgateway URL")
+	}
+	for _, u := range []string{opts.RemoteWriteURL, opts.PushgatewayURL} {
+		if u == "" {
+			continue
+		}
+		parsed, err := url.Parse(u)
+		if err != nil {
+			return nil, fmt.Errorf("MakeChainMetricsSink() err: %w", err)
+		}
+		if parsed.Scheme != "http" && parsed.Scheme != "https" {
+			return nil, fmt.Errorf("MakeChainMetricsSink() unsupported URL scheme %s", parsed.Scheme)
+		}
+	}
+	if opts.Job == "" {
+		return nil, fmt.Errorf("MakeChainMetricsSink() no job")
+	}
+
+	s := &ChainMetricsSink{
+		db:       db,
+		opts:     opts,
+		client:   &http.Client{Timeout: opts.Timeout},
+		registry: prometheus.NewRegistry(),
+		transactions: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "algorand_transactions_total",
+			Help: "Transactions by type.",
+		}, []string{"type"}),
+		fees: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "algorand_fees_microalgos_total",
+			Help: "Fees of the transactions in microalgos.",
+		}),
+		newAccounts: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "algorand_new_accounts_total",
+			Help: "Accounts created.",
+		}),
+		round: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "algorand_round",
+			Help: "The latest round.",
+		}),
+	}
+	s.registry.MustRegister(s.transactions, s.fees, s.newAccounts, s.round)
+	for _, typ := range chainMetricsTxnTypes {
+		s.transactions.WithLabelValues(string(typ))
+	}
+	return s, nil
+}
+
+// series returns the remote write series of `stats`.
+func (s *ChainMetricsSink) series(stats ChainStats) []remoteWriteSeries {
+	timeMs := stats.Time.UnixNano() / int64(time.Millisecond)
+	labels := func(extra ...string) map[string]string {
+		l := map[string]string{"job": s.opts.Job, "network": stats.Network}
+		for i := 0; i+1 < len(extra); i += 2 {
+			l[extra[i]] = extra[i+1]
+		}
+		return l
+	}
+
+	types := make(map[protocol.TxType]bool)
+	for _, typ := range chainMetricsTxnTypes {
+		types[typ] = true
+	}
+	for typ := range stats.Transactions {
+		types[typ] = true
+	}
+	sorted := make([]string, 0, len(types))
+	for typ := range types {
+		sorted = append(sorted, string(typ))
+	}
+	sort.Strings(sorted)
+
+	series := []remoteWriteSeries{
+		{name: "algorand_round", labels: labels(), value: float64(stats.Round), timeMs: timeMs},
+		{name: "algorand_round_fees_microalgos", labels: labels(), value: float64(stats.Fees), timeMs: timeMs},
+		{name: "algorand_round_new_accounts", labels: labels(), value: float64(stats.NewAccounts), timeMs: timeMs},
+	}
+	for _, typ := range sorted {
+		series = append(series, remoteWriteSeries{
+			name:   "algorand_round_transactions",
+			labels: labels("type", typ),
+			value:  float64(stats.Transactions[protocol.TxType(typ)]),
+			timeMs: timeMs,
+		})
+	}
+	return series
+}
+
+// Publish is part of ExportSink.
+func (s *ChainMetricsSink) Publish(ctx context.Context, header bookkeeping.BlockHeader, txns []idb.TxnRow) error {
+	stats, err := computeChainStats(ctx, s.db, header, txns)
+	if err != nil {
+		return fmt.Errorf("Publish() err: %w", err)
+	}
+
+	if s.opts.RemoteWriteURL != "" {
+		err = remoteWrite(ctx, s.client, s.opts.RemoteWriteURL, s.series(stats))
+		if err != nil {
+			return fmt.Errorf("Publish() round %d err: %w", stats.Round, err)
+		}
+	}
+
+	if s.opts.PushgatewayURL != "" {
+		// A round is only counted once, a failed push is retried with the same
+		// totals.
+		if !s.counted || stats.Round > s.countedRound {
+			for typ, n := range stats.Transactions {
+				s.transactions.WithLabelValues(string(typ)).Add(float64(n))
+			}
+			s.fees.Add(float64(stats.Fees))
+			s.newAccounts.Add(float64(stats.NewAccounts))
+			s.round.Set(float64(stats.Round))
+			s.counted = true
+			s.countedRound = stats.Round
+		}
+		err = push.New(s.opts.PushgatewayURL, s.opts.Job).
+			Gatherer(s.registry).
+			Grouping("network", stats.Network).
+			Client(s.client).
+			PushContext(ctx)
+		if err != nil {
+			return fmt.Errorf("Publish() round %d push err: %w", stats.Round, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,128 @@
+package importer
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/algorand/go-algorand/data/basics"
+	"github.com/algorand/go-algorand/data/bookkeeping"
+	"github.com/algorand/go-algorand/protocol"
+	"github.com/algorand/go-algorand/rpcs"
+	"github.com/golang/snappy"
+	"github.com/prometheus/prometheus/prompb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/algorand/indexer/idb"
+	"github.com/algorand/indexer/idb/memory"
+	"github.com/algorand/indexer/util/test"
+)
+
+func TestMakeWriteRequest(t *testing.T) {
+	req := makeWriteRequest([]remoteWriteSeries{
+		{name: "m", labels: map[string]string{"network": "n", "job": "j"}, value: 1, timeMs: 2},
+	})
+
+	expected := []prompb.TimeSeries{{
+		Labels: []prompb.Label{
+			{Name: "__name__", Value: "m"},
+			{Name: "job", Value: "j"},
+			{Name: "network", Value: "n"},
+		},
+		Samples: []prompb.Sample{{Value: 1, Timestamp: 2}},
+	}}
+	assert.Equal(t, expected, req.Timeseries)
+}
+
+func TestComputeChainStats(t *testing.T) {
+	db := memory.MakeIndexerDb(nil)
+	require.NoError(t, db.LoadGenesis(test.MakeGenesis()))
+
+	block0 := test.MakeGenesisBlock()
+	// AccountE is not in the genesis.
+	txnB := test.MakePaymentTxn(
+		1000, 100, 0, 0, 0, 0, test.AccountA, test.AccountB, basics.Address{}, basics.Address{})
+	txnE := test.MakePaymentTxn(
+		2000, 100000, 0, 0, 0, 0, test.AccountA, test.AccountE, basics.Address{}, basics.Address{})
+	create := test.MakeConfigAssetTxn(0, 100, 0, false, "UNIT", "Asset", "https://example.com", test.AccountA)
+	block1, err := test.MakeBlockForTxns(block0.BlockHeader, &txnB, &txnE, &create)
+	require.NoError(t, err)
+	imp := NewImporter(db)
+	require.NoError(t, imp.ImportBlocks([]*rpcs.EncodedBlockCert{{Block: block0}, {Block: block1}}))
+
+	rows := []idb.TxnRow{
+		{Round: 1, Intra: 0, TxnBytes: protocol.Encode(&txnB)},
+		{Round: 1, Intra: 1, TxnBytes: protocol.Encode(&txnE)},
+		{Round: 1, Intra: 2, TxnBytes: protocol.Encode(&create)},
+	}
+	stats, err := computeChainStats(context.Background(), db, block1.BlockHeader, rows)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(1), stats.Round)
+	assert.Equal(t, uint64(2), stats.Transactions[protocol.PaymentTx])
+	assert.Equal(t, uint64(1), stats.Transactions[protocol.AssetConfigTx])
+	assert.Equal(t, uint64(3000)+create.Txn.Fee.Raw, stats.Fees)
+	assert.Equal(t, uint64(1), stats.NewAccounts)
+}
+
+func TestChainMetricsSinkPublish(t *testing.T) {
+	var requests []*http.Request
+	var bodies [][]byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := ioutil.ReadAll(r.Body)
+		require.NoError(t, err)
+		requests = append(requests, r)
+		bodies = append(bodies, body)
+	}))
+	defer server.Close()
+
+	db := memory.MakeIndexerDb(nil)
+	sink, err := MakeChainMetricsSink(db, ChainMetricsOptions{
+		RemoteWriteURL: server.URL + "/api/v1/write",
+		PushgatewayURL: server.URL,
+		Job:            "indexer",
+		Timeout:        time.Second,
+	})
+	require.NoError(t, err)
+
+	pay := test.MakePaymentTxn(
+		1000, 100, 0, 0, 0, 0, test.AccountA, test.AccountB, basics.Address{}, basics.Address{})
+	rows := []idb.TxnRow{{Round: 5, TxnBytes: protocol.Encode(&pay)}}
+	header := bookkeeping.BlockHeader{Round: 5, TimeStamp: 1000, GenesisID: "mainnet-v1.0"}
+	require.NoError(t, sink.Publish(context.Background(), header, rows))
+	require.Len(t, requests, 2)
+
+	write := requests[0]
+	assert.Equal(t, http.MethodPost, write.Method)
+	assert.Equal(t, "/api/v1/write", write.URL.Path)
+	assert.Equal(t, "snappy", write.Header.Get("Content-Encoding"))
+	assert.Equal(t, "application/x-protobuf", write.Header.Get("Content-Type"))
+	data, err := snappy.Decode(nil, bodies[0])
+	require.NoError(t, err)
+	var req prompb.WriteRequest
+	require.NoError(t, req.Unmarshal(data))
+	series := sink.series(ChainStats{Round: 5, Time: time.Unix(1000, 0), Network: "mainnet-v1.0",
+		Transactions: map[protocol.TxType]uint64{protocol.PaymentTx: 1}, Fees: 1000})
+	assert.Equal(t, makeWriteRequest(series).Timeseries, req.Timeseries)
+
+	pushed := requests[1]
+	assert.Equal(t, http.MethodPut, pushed.Method)
+	assert.Equal(t, "/metrics/job/indexer/network/mainnet-v1.0", pushed.URL.Path)
+	assert.Contains(t, string(bodies[1]), "algorand_fees_microalgos_total")
+
+	// A retried round is not counted twice.
+	require.NoError(t, sink.Publish(context.Background(), header, rows))
+	metrics, err := sink.registry.Gather()
+	require.NoError(t, err)
+	for _, family := range metrics {
+		if family.GetName() == "algorand_fees_microalgos_total" {
+			assert.Equal(t, float64(1000), family.GetMetric()[0].GetCounter().GetValue())
+		}
+	}
+
+	_, err = MakeChainMetricsSink(db, ChainMetricsOptions{Job: "indexer"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,336 @@
+package importer
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/algorand/go-algorand/data/basics"
+	"github.com/algorand/go-algorand/data/bookkeeping"
+	"github.com/algorand/go-algorand/protocol"
+
+	"github.com/algorand/indexer/idb"
+)
+
+// Actions sent to Elasticsearch in one bulk request.
+const elasticsearchMaxActions = 1000
+
+// Kinds of the documents mirrored to Elasticsearch, the index of a kind is
+// "<prefix>-<kind>".
+const (
+	SearchNotes   = "notes"
+	SearchAssets  = "assets"
+	SearchAppKeys = "app-keys"
+)
+
+// searchFields are the fields of a kind which free-text queries search.
+var searchFields = map[string][]string{
+	SearchNotes:   {"note"},
+	SearchAssets:  {"name", "unit-name", "url"},
+	SearchAppKeys: {"key", "value"},
+}
+
+// ElasticsearchOptions configures an ElasticsearchSink.
+type ElasticsearchOptions struct {
+	// URL is the URL of the Elasticsearch or OpenSearch cluster, it may have
+	// basic auth credentials.
+	URL string
+
+	// IndexPrefix is the prefix of the indices.
+	IndexPrefix string
+
+	// Timeout is the timeout of a request to the cluster.
+	Timeout time.Duration
+}
+
+// ElasticsearchSink is an ExportSink which mirrors the notes of the
+// transactions, the names and URLs of the assets and the global state of the
+// applications to Elasticsearch for free-text search. Destroyed assets and
+// deleted keys are removed. It also runs the searches of the API.
+type ElasticsearchSink struct {
+	opts   ElasticsearchOptions
+	client *http.Client
+}
+
+// MakeElasticsearchSink creates an ElasticsearchSink.
+func MakeElasticsearchSink(opts ElasticsearchOptions) (*ElasticsearchSink, error) {
+	u, err := url.Parse(opts.URL)
+	if err != nil {
+		return nil, fmt.Errorf("MakeElasticsearchSink() err: %w", err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, fmt.Errorf("MakeElasticsearchSink() unsupported URL scheme %s", u.Scheme)
+	}
+	if opts.IndexPrefix == "" {
+		return nil, fmt.Errorf("MakeElasticsearchSink() no index prefix")
+	}
+	opts.URL = strings.TrimSuffix(opts.URL, "/")
+	return &ElasticsearchSink{
+		opts:   opts,
+		client: &http.Client{Timeout: opts.Timeout},
+	}, nil
+}
+
+func (s *ElasticsearchSink) index(kind string) string {
+	return s.opts.IndexPrefix + "-" + kind
+}
+
+// searchText returns `b` as a string when it is text, and base64 otherwise.
+func searchText(b []byte) string {
+	if utf8.Valid(b) {
+		return string(b)
+	}
+	return base64.StdEncoding.EncodeToString(b)
+}
+
+type noteDocument struct {
+	Round     uint64 `json:"round"`
+	Intra     int    `json:"intra"`
+	RoundTime int64  `json:"round-time"`
+	Txid      string `json:"txid"`
+	Type      string `json:"type"`
+	Sender    string `json:"sender"`
+	Note      string `json:"note"`
+}
+
+type assetDocument struct {
+	AssetID  uint64 `json:"asset-id"`
+	Creator  string `json:"creator"`
+	Name     string `json:"name"`
+	UnitName string `json:"unit-name"`
+	URL      string `json:"url"`
+	Round    uint64 `json:"round"`
+}
+
+type appKeyDocument struct {
+	ApplicationID uint64 `json:"application-id"`
+	Key           string `json:"key"`
+	Value         string `json:"value,omitempty"`
+	Uint          uint64 `json:"uint,omitempty"`
+	Round         uint64 `json:"round"`
+}
+
+// bulkAction is an action of the bulk API, with the document for an index
+// action.
+type bulkAction struct {
+	delete bool
+	index  string
+	id     string
+	doc    interface{}
+}
+
+// actions returns the bulk actions which mirror `txn`.
+func (s *ElasticsearchSink) actions(txn ExportedTransaction) []bulkAction {
+	var actions []bulkAction
+	stxn := &txn.Txn
+
+	if len(stxn.Txn.Note) > 0 {
+		actions = append(actions, bulkAction{
+			index: s.index(SearchNotes),
+			id:    txn.Txid,
+			doc: noteDocument{
+				Round:     txn.Round,
+				Intra:     txn.Intra,
+				RoundTime: txn.RoundTime,
+				Txid:      txn.Txid,
+				Type:      string(stxn.Txn.Type),
+				Sender:    stxn.Txn.Sender.String(),
+				Note:      searchText(stxn.Txn.Note),
+			},
+		})
+	}
+
+	switch stxn.Txn.Type {
+	case protocol.AssetConfigTx:
+		id := strconv.FormatUint(txn.AssetID, 10)
+		params := stxn.Txn.AssetParams
+		if stxn.Txn.ConfigAsset == 0 {
+			actions = append(actions, bulkAction{
+				index: s.index(SearchAssets),
+				id:    id,
+				doc: assetDocument{
+					AssetID:  txn.AssetID,
+					Creator:  stxn.Txn.Sender.String(),
+					Name:     params.AssetName,
+					UnitName: params.UnitName,
+					URL:      params.URL,
+					Round:    txn.Round,
+				},
+			})
+		} else if params == (basics.AssetParams{}) {
+			actions = append(actions, bulkAction{delete: true, index: s.index(SearchAssets), id: id})
+		}
+	case protocol.ApplicationCallTx:
+		for key, delta := range stxn.EvalDelta.GlobalDelta {
+			id := fmt.Sprintf("%d-%s", txn.AssetID, base64.URLEncoding.EncodeToString([]byte(key)))
+			if delta.Action == basics.DeleteAction {
+				actions = append(actions, bulkAction{delete: true, index: s.index(SearchAppKeys), id: id})
+				continue
+			}
+			doc := appKeyDocument{
+				ApplicationID: txn.AssetID,
+				Key:           searchText([]byte(key)),
+				Round:         txn.Round,
+			}
+			if delta.Action == basics.SetBytesAction {
+				doc.Value = searchText([]byte(delta.Bytes))
+			} else {
+				doc.Uint = delta.Uint
+			}
+			actions = append(actions, bulkAction{index: s.index(SearchAppKeys), id: id, doc: doc})
+		}
+	}
+	return actions
+}
+
+// Publish is part of ExportSink.
+func (s *ElasticsearchSink) Publish(ctx context.Context, header bookkeeping.BlockHeader, txns []idb.TxnRow) error {
+	var actions []bulkAction
+	for _, row := range txns {
+		txn, err := MakeExportedTransaction(row)
+		if err != nil {
+			return fmt.Errorf("Publish() err: %w", err)
+		}
+		actions = append(actions, s.actions(txn)...)
+	}
+
+	for len(actions) > 0 {
+		n := len(actions)
+		if n > elasticsearchMaxActions {
+			n = elasticsearchMaxActions
+		}
+		err := s.bulk(ctx, actions[:n])
+		if err != nil {
+			return fmt.Errorf("Publish() round %d err: %w", header.Round, err)
+		}
+		actions = actions[n:]
+	}
+	return nil
+}
+
+type bulkResponse struct {
+	Errors bool `json:"errors"`
+	Items  []map[string]struct {
+		Status int             `json:"status"`
+		Error  json.RawMessage `json:"error"`
+	} `json:"items"`
+}
+
+// do sends a request to the cluster and decodes the response into `response`.
+func (s *ElasticsearchSink) do(ctx context.Context, method string, path string, contentType string, body []byte, response interface{}) error {
+	req, err := http.NewRequest(method, s.opts.URL+path, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("do() err: %w", err)
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Content-Type", contentType)
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("do() err: %w", err)
+	}
+	respBody, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return fmt.Errorf("do() read err: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("do() status %d: %s", resp.StatusCode, respBody)
+	}
+	err = json.Unmarshal(respBody, response)
+	if err != nil {
+		return fmt.Errorf("do() decode err: %w", err)
+	}
+	return nil
+}
+
+// bulk sends `actions` in one bulk request.
+func (s *ElasticsearchSink) bulk(ctx context.Context, actions []bulkAction) error {
+	var body bytes.Buffer
+	enc := json.NewEncoder(&body)
+	for _, action := range actions {
+		meta := map[string]string{"_index": action.index, "_id": action.id}
+		var err error
+		if action.delete {
+			err = enc.Encode(map[string]interface{}{"delete": meta})
+		} else {
+			err = enc.Encode(map[string]interface{}{"index": meta})
+			if err == nil {
+				err = enc.Encode(action.doc)
+			}
+		}
+		if err != nil {
+			return fmt.Errorf("bulk() encode err: %w", err)
+		}
+	}
+
+	var response bulkResponse
+	err := s.do(ctx, http.MethodPost, "/_bulk", "application/x-ndjson", body.Bytes(), &response)
+	if err != nil {
+		return fmt.Errorf("bulk() err: %w", err)
+	}
+	if !response.Errors {
+		return nil
+	}
+	for i, item := range response.Items {
+		for op, result := range item {
+			// Deleting a document which was never indexed is fine.
+			if op == "delete" && result.Status == http.StatusNotFound {
+				continue
+			}
+			if result.Status >= 300 {
+				return fmt.Errorf("bulk() action %d %s status %d: %s", i, op, result.Status, result.Error)
+			}
+		}
+	}
+	return nil
+}
+
+// Search returns the documents of `kind` which match the free-text `query`,
+// best matches first.
+func (s *ElasticsearchSink) Search(ctx context.Context, kind string, query string, limit uint64) ([]json.RawMessage, error) {
+	fields, ok := searchFields[kind]
+	if !ok {
+		return nil, fmt.Errorf("Search() unknown kind %s", kind)
+	}
+	body, err := json.Marshal(map[string]interface{}{
+		"size": limit,
+		"query": map[string]interface{}{
+			"simple_query_string": map[string]interface{}{
+				"query":  query,
+				"fields": fields,
+			},
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Search() encode err: %w", err)
+	}
+
+	var response struct {
+		Hits struct {
+			Hits []struct {
+				Source json.RawMessage `json:"_source"`
+			} `json:"hits"`
+		} `json:"hits"`
+	}
+	// A missing index, before anything was mirrored, has no results.
+	path := "/" + s.index(kind) + "/_search?ignore_unavailable=true"
+	err = s.do(ctx, http.MethodPost, path, "application/json", body, &response)
+	if err != nil {
+		return nil, fmt.Errorf("Search() err: %w", err)
+	}
+	docs := make([]json.RawMessage, 0, len(response.Hits.Hits))
+	for _, hit := range response.Hits.Hits {
+		docs = append(docs, hit.Source)
+	}
+	return docs, nil
+}
@@ -0,0 +1,108 @@
+package importer
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/algorand/go-algorand/data/basics"
+	"github.com/algorand/go-algorand/data/bookkeeping"
+	"github.com/algorand/go-algorand/protocol"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/algorand/indexer/idb"
+	"github.com/algorand/indexer/util/test"
+)
+
+func TestElasticsearchSinkPublish(t *testing.T) {
+	var lines []map[string]interface{}
+	response := `{"errors": false, "items": []}`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/_bulk", r.URL.Path)
+		assert.Equal(t, "application/x-ndjson", r.Header.Get("Content-Type"))
+		body, err := ioutil.ReadAll(r.Body)
+		require.NoError(t, err)
+		scanner := bufio.NewScanner(bytes.NewReader(body))
+		for scanner.Scan() {
+			var line map[string]interface{}
+			require.NoError(t, json.Unmarshal(scanner.Bytes(), &line))
+			lines = append(lines, line)
+		}
+		w.Write([]byte(response))
+	}))
+	defer server.Close()
+
+	sink, err := MakeElasticsearchSink(ElasticsearchOptions{
+		URL:         server.URL,
+		IndexPrefix: "algorand",
+		Timeout:     time.Second,
+	})
+	require.NoError(t, err)
+
+	pay := test.MakePaymentTxn(
+		1000, 100, 0, 0, 0, 0, test.AccountA, test.AccountB, basics.Address{}, basics.Address{})
+	pay.Txn.Note = []byte("hello world")
+	create := test.MakeConfigAssetTxn(0, 100, 0, false, "UNIT", "Asset", "https://example.com", test.AccountA)
+	destroy := test.MakeAssetDestroyTxn(9, test.AccountA)
+	app := test.MakeCreateAppTxn(test.AccountA)
+	app.EvalDelta.GlobalDelta = basics.StateDelta{
+		"owner": {Action: basics.SetBytesAction, Bytes: "alice"},
+	}
+	rows := []idb.TxnRow{
+		{Round: 5, Intra: 0, TxnBytes: protocol.Encode(&pay)},
+		{Round: 5, Intra: 1, TxnBytes: protocol.Encode(&create), AssetID: 8},
+		{Round: 5, Intra: 2, TxnBytes: protocol.Encode(&destroy), AssetID: 9},
+		{Round: 5, Intra: 3, TxnBytes: protocol.Encode(&app), AssetID: 10},
+	}
+
+	err = sink.Publish(context.Background(), bookkeeping.BlockHeader{Round: 5}, rows)
+	require.NoError(t, err)
+	require.Len(t, lines, 7)
+
+	assert.Equal(t, "algorand-notes", lines[0]["index"].(map[string]interface{})["_index"])
+	assert.Equal(t, "hello world", lines[1]["note"])
+	assert.Equal(t, "8", lines[2]["index"].(map[string]interface{})["_id"])
+	assert.Equal(t, "Asset", lines[3]["name"])
+	assert.Equal(t, "9", lines[4]["delete"].(map[string]interface{})["_id"])
+	assert.Equal(t, "algorand-app-keys", lines[5]["index"].(map[string]interface{})["_index"])
+	assert.Equal(t, "owner", lines[6]["key"])
+	assert.Equal(t, "alice", lines[6]["value"])
+
+	// Deleting a missing document is not an error, a failed index is.
+	response = `{"errors": true, "items": [{"delete": {"status": 404}}]}`
+	err = sink.Publish(context.Background(), bookkeeping.BlockHeader{Round: 5}, rows[2:3])
+	require.NoError(t, err)
+	response = `{"errors": true, "items": [{"index": {"status": 429, "error": {"type": "rejected"}}}]}`
+	err = sink.Publish(context.Background(), bookkeeping.BlockHeader{Round: 5}, rows[:1])
+	assert.Error(t, err)
+}
+
+func TestElasticsearchSinkSearch(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/algorand-assets/_search", r.URL.Path)
+		assert.Equal(t, "true", r.URL.Query().Get("ignore_unavailable"))
+		var query map[string]interface{}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&query))
+		assert.Equal(t, float64(5), query["size"])
+		w.Write([]byte(`{"hits": {"hits": [{"_source": {"asset-id": 8}}]}}`))
+	}))
+	defer server.Close()
+
+	sink, err := MakeElasticsearchSink(ElasticsearchOptions{URL: server.URL, IndexPrefix: "algorand"})
+	require.NoError(t, err)
+
+	docs, err := sink.Search(context.Background(), SearchAssets, "asset", 5)
+	require.NoError(t, err)
+	require.Len(t, docs, 1)
+	assert.JSONEq(t, `{"asset-id": 8}`, string(docs[0]))
+
+	_, err = sink.Search(context.Background(), "blocks", "asset", 5)
+	assert.Error(t, err)
+}
@@ -0,0 +1,219 @@
+package importer
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/algorand/go-algorand/data/bookkeeping"
+	"github.com/algorand/go-algorand/protocol"
+
+	"github.com/algorand/indexer/idb"
+)
+
+// eventConn is a connection to a NATS server or an MQTT broker.
+type eventConn interface {
+	publish(topic string, payload []byte) error
+	// flush returns once the server received the published events, or fails
+	// after `timeout`.
+	flush(timeout time.Duration) error
+	close()
+}
+
+// EventOptions configures an EventSink.
+type EventOptions struct {
+	// URL is the NATS server, nats://[user:password@]host[:port], or the MQTT
+	// broker, mqtt://[user:password@]host[:port].
+	URL string
+
+	// BlockTopic is the topic of the block events, none are published if it is
+	// empty.
+	BlockTopic string
+
+	// TransactionTopic is the topic of the transaction events, in which "{type}"
+	// is replaced by the type of the transaction, e.g. "algorand.txn.{type}".
+	// None are published if it is empty.
+	TransactionTopic string
+
+	// Timeout is the timeout of connecting and publishing a round.
+	Timeout time.Duration
+}
+
+// BlockEvent is the event of an imported round.
+type BlockEvent struct {
+	Round        uint64 `json:"round"`
+	Timestamp    int64  `json:"timestamp"`
+	Transactions int    `json:"transactions"`
+}
+
+// TransactionEvent is the event of an imported transaction, with the fields
+// which most consumers filter by. The full transaction is in the API.
+type TransactionEvent struct {
+	Round         uint64 `json:"round"`
+	Intra         int    `json:"intra"`
+	Txid          string `json:"txid"`
+	Type          string `json:"type"`
+	Sender        string `json:"sender"`
+	Receiver      string `json:"receiver,omitempty"`
+	Amount        uint64 `json:"amount,omitempty"`
+	AssetID       uint64 `json:"asset-id,omitempty"`
+	ApplicationID uint64 `json:"application-id,omitempty"`
+	Fee           uint64 `json:"fee"`
+}
+
+// MakeTransactionEvent returns the event of `txn`.
+func MakeTransactionEvent(txn ExportedTransaction) TransactionEvent {
+	stxn := &txn.Txn.Txn
+	event := TransactionEvent{
+		Round:  txn.Round,
+		Intra:  txn.Intra,
+		Txid:   txn.Txid,
+		Type:   string(stxn.Type),
+		Sender: stxn.Sender.String(),
+		Fee:    stxn.Fee.Raw,
+	}
+	switch stxn.Type {
+	case protocol.PaymentTx:
+		event.Receiver = stxn.Receiver.String()
+		event.Amount = stxn.Amount.Raw
+	case protocol.AssetTransferTx:
+		event.AssetID = txn.AssetID
+		if !stxn.AssetReceiver.IsZero() {
+			event.Receiver = stxn.AssetReceiver.String()
+		}
+		event.Amount = stxn.AssetAmount
+	case protocol.AssetConfigTx, protocol.AssetFreezeTx:
+		event.AssetID = txn.AssetID
+	case protocol.ApplicationCallTx:
+		event.ApplicationID = txn.AssetID
+	}
+	return event
+}
+
+// EventSink is an ExportSink which publishes small JSON events of the imported
+// blocks and transactions to NATS or MQTT, for consumers which don't need all
+// of Kafka. The transaction events of a round are published before its block
+// event.
+type EventSink struct {
+	opts EventOptions
+	url  *url.URL
+	conn eventConn
+}
+
+// MakeEventSink creates an EventSink. It connects when it publishes the first
+// round.
+func MakeEventSink(opts EventOptions) (*EventSink, error) {
+	u, err := url.Parse(opts.URL)
+	if err != nil {
+		return nil, fmt.Errorf("MakeEventSink() err: %w", err)
+	}
+	if u.Scheme != "nats" && u.Scheme != "mqtt" {
+		return nil, fmt.Errorf("MakeEventSink() unsupported URL scheme %s, use nats or mqtt", u.Scheme)
+	}
+	if opts.BlockTopic == "" && opts.TransactionTopic == "" {
+		return nil, fmt.Errorf("MakeEventSink() no topics")
+	}
+	return &EventSink{opts: opts, url: u}, nil
+}
+
+func (s *EventSink) dial() (eventConn, error) {
+	if s.url.Scheme == "mqtt" {
+		c, err := dialMQTT(s.url, s.opts.Timeout)
+		if err != nil {
+			return nil, fmt.Errorf("dial() err: %w", err)
+		}
+		return c, nil
+	}
+	c, err := dialNATS(s.opts.URL, s.opts.Timeout)
+	if err != nil {
+		return nil, fmt.Errorf("dial() err: %w", err)
+	}
+	return c, nil
+}
+
+type event struct {
+	topic   string
+	payload []byte
+}
+
+// send publishes `events`. The connection is closed after an error.
+func (s *EventSink) send(ctx context.Context, events []event) error {
+	if s.conn == nil {
+		conn, err := s.dial()
+		if err != nil {
+			return fmt.Errorf("send() err: %w", err)
+		}
+		s.conn = conn
+	}
+
+	fail := func(err error) error {
+		s.conn.close()
+		s.conn = nil
+		return fmt.Errorf("send() err: %w", err)
+	}
+	timeout := s.opts.Timeout
+	if d, ok := ctx.Deadline(); ok && time.Until(d) < timeout {
+		timeout = time.Until(d)
+	}
+
+	for _, e := range events {
+		err := s.conn.publish(e.topic, e.payload)
+		if err != nil {
+			return fail(err)
+		}
+	}
+	err := s.conn.flush(timeout)
+	if err != nil {
+		return fail(err)
+	}
+	return nil
+}
+
+// Publish is part of ExportSink.
+func (s *EventSink) Publish(ctx context.Context, header bookkeeping.BlockHeader, txns []idb.TxnRow) error {
+	var events []event
+	if s.opts.TransactionTopic != "" {
+		for _, row := range txns {
+			txn, err := MakeExportedTransaction(row)
+			if err != nil {
+				return fmt.Errorf("Publish() err: %w", err)
+			}
+			payload, err := json.Marshal(MakeTransactionEvent(txn))
+			if err != nil {
+				return fmt.Errorf("Publish() encode err: %w", err)
+			}
+			topic := strings.Replace(s.opts.TransactionTopic, "{type}", string(txn.Txn.Txn.Type), -1)
+			events = append(events, event{topic: topic, payload: payload})
+		}
+	}
+	if s.opts.BlockTopic != "" {
+		payload, err := json.Marshal(BlockEvent{
+			Round:        uint64(header.Round),
+			Timestamp:    header.TimeStamp,
+			Transactions: len(txns),
+		})
+		if err != nil {
+			return fmt.Errorf("Publish() encode err: %w", err)
+		}
+		events = append(events, event{topic: s.opts.BlockTopic, payload: payload})
+	}
+
+	if len(events) == 0 {
+		return nil
+	}
+
+	// A connection which was idle may have been closed by the server, it is
+	// retried once with a new one.
+	reconnect := s.conn != nil
+	err := s.send(ctx, events)
+	if err != nil && reconnect {
+		err = s.send(ctx, events)
+	}
+	if err != nil {
+		return fmt.Errorf("Publish() round %d err: %w", header.Round, err)
+	}
+	return nil
+}
@@ -0,0 +1,162 @@
+package importer
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/algorand/go-algorand/data/basics"
+	"github.com/algorand/go-algorand/data/bookkeeping"
+	"github.com/algorand/go-algorand/protocol"
+	"github.com/eclipse/paho.mqtt.golang/packets"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/algorand/indexer/idb"
+	"github.com/algorand/indexer/util/test"
+)
+
+type publishedEvent struct {
+	topic   string
+	payload string
+}
+
+func eventRows() []idb.TxnRow {
+	pay := test.MakePaymentTxn(
+		1000, 100, 0, 0, 0, 0, test.AccountA, test.AccountB, basics.Address{}, basics.Address{})
+	app := test.MakeCreateAppTxn(test.AccountA)
+	return []idb.TxnRow{
+		{Round: 5, Intra: 0, TxnBytes: protocol.Encode(&pay)},
+		{Round: 5, Intra: 1, TxnBytes: protocol.Encode(&app), AssetID: 10},
+	}
+}
+
+// serveNATS accepts one connection and sends the published messages to `out`.
+func serveNATS(t *testing.T, l net.Listener, out chan<- publishedEvent) {
+	conn, err := l.Accept()
+	require.NoError(t, err)
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	fmt.Fprintf(conn, "INFO {\"server_id\":\"test\",\"max_payload\":1048576}\r\n")
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		fields := strings.Fields(line)
+		switch fields[0] {
+		case "CONNECT":
+			assert.Contains(t, line, `"user":"user"`)
+		case "PING":
+			fmt.Fprintf(conn, "PONG\r\n")
+		case "PUB":
+			size, err := strconv.Atoi(fields[2])
+			require.NoError(t, err)
+			payload := make([]byte, size+2)
+			_, err = io.ReadFull(r, payload)
+			require.NoError(t, err)
+			out <- publishedEvent{topic: fields[1], payload: string(payload[:size])}
+		}
+	}
+}
+
+func TestEventSinkNATS(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer l.Close()
+	events := make(chan publishedEvent, 10)
+	go serveNATS(t, l, events)
+
+	sink, err := MakeEventSink(EventOptions{
+		URL:              "nats://user:password@" + l.Addr().String(),
+		BlockTopic:       "algorand.blocks",
+		TransactionTopic: "algorand.transactions.{type}",
+		Timeout:          time.Second,
+	})
+	require.NoError(t, err)
+	header := bookkeeping.BlockHeader{Round: 5, TimeStamp: 1000}
+	require.NoError(t, sink.Publish(context.Background(), header, eventRows()))
+
+	pay := <-events
+	assert.Equal(t, "algorand.transactions.pay", pay.topic)
+	var txn TransactionEvent
+	require.NoError(t, json.Unmarshal([]byte(pay.payload), &txn))
+	assert.Equal(t, test.AccountB.String(), txn.Receiver)
+	assert.Equal(t, uint64(100), txn.Amount)
+
+	app := <-events
+	assert.Equal(t, "algorand.transactions.appl", app.topic)
+	assert.Contains(t, app.payload, `"application-id":10`)
+
+	block := <-events
+	assert.Equal(t, "algorand.blocks", block.topic)
+	assert.Equal(t, `{"round":5,"timestamp":1000,"transactions":2}`, block.payload)
+}
+
+// serveMQTT accepts one connection, acknowledges the messages published with
+// QoS 1 and sends them to `out`.
+func serveMQTT(t *testing.T, l net.Listener, out chan<- publishedEvent) {
+	conn, err := l.Accept()
+	require.NoError(t, err)
+	defer conn.Close()
+	for {
+		packet, err := packets.ReadPacket(conn)
+		if err != nil {
+			return
+		}
+		switch p := packet.(type) {
+		case *packets.ConnectPacket:
+			assert.Equal(t, "MQTT", p.ProtocolName)
+			assert.Equal(t, byte(4), p.ProtocolVersion)
+			err = packets.NewControlPacket(packets.Connack).Write(conn)
+		case *packets.PublishPacket:
+			assert.Equal(t, byte(1), p.Qos)
+			out <- publishedEvent{topic: p.TopicName, payload: string(p.Payload)}
+			ack := packets.NewControlPacket(packets.Puback).(*packets.PubackPacket)
+			ack.MessageID = p.MessageID
+			err = ack.Write(conn)
+		case *packets.PingreqPacket:
+			err = packets.NewControlPacket(packets.Pingresp).Write(conn)
+		case *packets.DisconnectPacket:
+			return
+		}
+		require.NoError(t, err)
+	}
+}
+
+func TestEventSinkMQTT(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer l.Close()
+	events := make(chan publishedEvent, 10)
+	go serveMQTT(t, l, events)
+
+	sink, err := MakeEventSink(EventOptions{
+		URL:        "mqtt://" + l.Addr().String(),
+		BlockTopic: "algorand/blocks",
+		Timeout:    time.Second,
+	})
+	require.NoError(t, err)
+	header := bookkeeping.BlockHeader{Round: 5, TimeStamp: 1000}
+	require.NoError(t, sink.Publish(context.Background(), header, eventRows()))
+
+	// Only the block events are published.
+	block := <-events
+	assert.Equal(t, "algorand/blocks", block.topic)
+	assert.Equal(t, `{"round":5,"timestamp":1000,"transactions":2}`, block.payload)
+	assert.Len(t, events, 0)
+}
+
+func TestMakeEventSink(t *testing.T) {
+	_, err := MakeEventSink(EventOptions{URL: "kafka://localhost", BlockTopic: "blocks"})
+	assert.Error(t, err)
+	_, err = MakeEventSink(EventOptions{URL: "nats://localhost"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,148 @@
+package importer
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/algorand/go-algorand/data/bookkeeping"
+	"github.com/algorand/go-algorand/data/transactions"
+	"github.com/algorand/go-algorand/protocol"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/algorand/indexer/idb"
+	"github.com/algorand/indexer/util/metrics"
+)
+
+// How long the export waits after a round failed to be published.
+const exportRetryInterval = 10 * time.Second
+
+var exportedRounds = metrics.DefaultRegistry.NewCounterVec(
+	"exported_rounds_total",
+	"Rounds published by the exporters, by exporter.",
+	"exporter")
+
+var exportFailures = metrics.DefaultRegistry.NewCounterVec(
+	"export_failures_total",
+	"Rounds which failed to be published and are retried, by exporter.",
+	"exporter")
+
+// ExportedTransaction is a transaction as it is published by the exporters.
+type ExportedTransaction struct {
+	Round     uint64 `codec:"round"`
+	Intra     int    `codec:"intra"`
+	RoundTime int64  `codec:"round-time"`
+	Txid      string `codec:"txid"`
+
+	// AssetID is the asset or application of the transaction, including the
+	// one it creates.
+	AssetID uint64 `codec:"asset-id,omitempty"`
+
+	// Truncated is set when the transaction was too large to be stored in full
+	// and the eval delta is left out.
+	Truncated bool `codec:"truncated,omitempty"`
+
+	Txn transactions.SignedTxnWithAD `codec:"txn"`
+}
+
+// MakeExportedTransaction decodes the transaction of `row`.
+func MakeExportedTransaction(row idb.TxnRow) (ExportedTransaction, error) {
+	if row.Error != nil {
+		return ExportedTransaction{}, row.Error
+	}
+	txn := ExportedTransaction{
+		Round:     row.Round,
+		Intra:     row.Intra,
+		RoundTime: row.RoundTime.Unix(),
+		AssetID:   row.AssetID,
+		Truncated: row.Truncated,
+	}
+	err := protocol.Decode(row.TxnBytes, &txn.Txn)
+	if err != nil {
+		return ExportedTransaction{}, fmt.Errorf(
+			"MakeExportedTransaction() round %d intra %d decode err: %w", row.Round, row.Intra, err)
+	}
+	txn.Txid = txn.Txn.Txn.ID().String()
+	return txn, nil
+}
+
+// ExportSink publishes the imported rounds to an external system, see
+// RunExport().
+type ExportSink interface {
+	// Publish publishes the block header and the stored transactions of a
+	// round. It returns once they are delivered.
+	Publish(ctx context.Context, header bookkeeping.BlockHeader, txns []idb.TxnRow) error
+}
+
+// ExportOptions configures RunExport().
+type ExportOptions struct {
+	// Name identifies the progress of the exporter in the database.
+	Name string
+
+	// StartRound is the first round which is published when the exporter has
+	// not published anything yet.
+	StartRound uint64
+}
+
+// RunExport publishes the imported rounds in order to `sink` until the context
+// is done. The next round to publish is kept in the database, so the export
+// continues where it stopped after a restart. A round is recorded once it was
+// published, so the round which was being published when the daemon stopped is
+// published again: rounds are delivered at least once.
+func RunExport(ctx context.Context, db idb.IndexerDb, publisher *RoundPublisher, sink ExportSink, opts ExportOptions, l *log.Logger) {
+	for ctx.Err() == nil {
+		next, err := exportRounds(ctx, db, sink, opts)
+		if err != nil {
+			exportFailures.WithLabelValues(opts.Name).Inc()
+			l.WithError(err).WithFields(log.Fields{
+				"export": opts.Name,
+				"round":  next,
+			}).Warn("export failed to publish the round")
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(exportRetryInterval):
+			}
+			continue
+		}
+		// Waits for the import of round `next`, round 0 is published with round 1.
+		var latest uint64
+		if next > 0 {
+			latest = next - 1
+		}
+		publisher.WaitForRoundAfter(ctx, latest)
+	}
+}
+
+// exportRounds publishes the imported rounds which were not published yet. It
+// returns the next round to publish.
+func exportRounds(ctx context.Context, db idb.IndexerDb, sink ExportSink, opts ExportOptions) (uint64, error) {
+	next, err := db.GetExportRound(ctx, opts.Name)
+	if err != nil {
+		return 0, fmt.Errorf("exportRounds() err: %w", err)
+	}
+	if next < opts.StartRound {
+		next = opts.StartRound
+	}
+	imported, err := db.GetNextRoundToAccount()
+	if err != nil {
+		return next, fmt.Errorf("exportRounds() err: %w", err)
+	}
+
+	for ; next < imported && ctx.Err() == nil; next++ {
+		header, txns, err := db.GetBlockWithTransactions(ctx, next)
+		if err != nil {
+			return next, fmt.Errorf("exportRounds() err: %w", err)
+		}
+		err = sink.Publish(ctx, header, txns)
+		if err != nil {
+			return next, fmt.Errorf("exportRounds() publish err: %w", err)
+		}
+		err = db.SetExportRound(ctx, opts.Name, next+1)
+		if err != nil {
+			return next, fmt.Errorf("exportRounds() err: %w", err)
+		}
+		exportedRounds.WithLabelValues(opts.Name).Inc()
+	}
+	return next, nil
+}
@@ -0,0 +1,77 @@
+package importer
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/algorand/go-algorand/data/basics"
+	"github.com/algorand/go-algorand/data/bookkeeping"
+	"github.com/algorand/go-algorand/rpcs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/algorand/indexer/idb"
+	"github.com/algorand/indexer/idb/memory"
+	"github.com/algorand/indexer/util/test"
+)
+
+type recordingSink struct {
+	rounds []uint64
+	txns   int
+	err    error
+}
+
+func (s *recordingSink) Publish(ctx context.Context, header bookkeeping.BlockHeader, txns []idb.TxnRow) error {
+	if s.err != nil {
+		return s.err
+	}
+	s.rounds = append(s.rounds, uint64(header.Round))
+	s.txns += len(txns)
+	return nil
+}
+
+func TestExportRounds(t *testing.T) {
+	db := memory.MakeIndexerDb(nil)
+	require.NoError(t, db.LoadGenesis(test.MakeGenesis()))
+
+	block0 := test.MakeGenesisBlock()
+	txn := test.MakePaymentTxn(
+		1000, 100, 0, 0, 0, 0, test.AccountA, test.AccountB, basics.Address{}, basics.Address{})
+	block1, err := test.MakeBlockForTxns(block0.BlockHeader, &txn)
+	require.NoError(t, err)
+	imp := NewImporter(db)
+	require.NoError(t, imp.ImportBlocks([]*rpcs.EncodedBlockCert{{Block: block0}, {Block: block1}}))
+
+	ctx := context.Background()
+	opts := ExportOptions{Name: "test"}
+
+	// A failed round is published again.
+	sink := &recordingSink{err: errors.New("unavailable")}
+	next, err := exportRounds(ctx, db, sink, opts)
+	assert.Error(t, err)
+	assert.Equal(t, uint64(0), next)
+
+	sink.err = nil
+	next, err = exportRounds(ctx, db, sink, opts)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(2), next)
+	assert.Equal(t, []uint64{0, 1}, sink.rounds)
+	assert.Equal(t, 1, sink.txns)
+
+	recorded, err := db.GetExportRound(ctx, "test")
+	require.NoError(t, err)
+	assert.Equal(t, uint64(2), recorded)
+
+	// Caught up.
+	next, err = exportRounds(ctx, db, sink, opts)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(2), next)
+	assert.Equal(t, []uint64{0, 1}, sink.rounds)
+
+	// Another exporter starts at its start round.
+	sink = &recordingSink{}
+	_, err = exportRounds(ctx, db, sink, ExportOptions{Name: "other", StartRound: 1})
+	require.NoError(t, err)
+	assert.Equal(t, []uint64{1}, sink.rounds)
+}
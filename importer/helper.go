@@ -4,6 +4,7 @@ import (
 	"archive/tar"
 	"compress/bzip2"
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"io/ioutil"
@@ -15,12 +16,14 @@ import (
 	"time"
 
 	"github.com/algorand/go-algorand-sdk/client/v2/algod"
+	"github.com/algorand/go-algorand/crypto"
 	"github.com/algorand/go-algorand/data/bookkeeping"
 	"github.com/algorand/go-algorand/protocol"
 	"github.com/algorand/go-algorand/rpcs"
 	log "github.com/sirupsen/logrus"
 
 	"github.com/algorand/indexer/idb"
+	"github.com/algorand/indexer/util/archive"
 )
 
 // NewImportHelper builds an ImportHelper
@@ -40,6 +43,9 @@ type ImportHelper struct {
 	// BlockFileLimit is the number of block files to process.
 	BlockFileLimit int
 
+	// StopAtRound is the last round which is imported, 0 for no limit.
+	StopAtRound uint64
+
 	Log *log.Logger
 }
 
@@ -53,6 +59,7 @@ func (h *ImportHelper) Import(db idb.IndexerDb, args []string) {
 	}
 
 	imp := NewImporter(db)
+	imp.SetStopRound(h.StopAtRound)
 
 	blocks := 0
 	txCount := 0
@@ -66,21 +73,31 @@ func (h *ImportHelper) Import(db idb.IndexerDb, args []string) {
 				pathsSorted = pathsSorted[:h.BlockFileLimit]
 			}
 			for _, gfname := range pathsSorted {
+				if imp.StopRoundReached() {
+					break
+				}
 				fb, ft := importFile(gfname, &imp, h.Log)
 				blocks += fb
 				txCount += ft
 			}
-		} else {
+		} else if !imp.StopRoundReached() {
 			// try without passing throug glob
 			fb, ft := importFile(fname, &imp, h.Log)
 			blocks += fb
 			txCount += ft
 		}
 	}
+	if imp.StopRoundReached() {
+		h.Log.WithField("round", h.StopAtRound).Info("stop round reached")
+	}
 	blockdone := time.Now()
 	if blocks > 0 {
 		dt := blockdone.Sub(start)
-		h.Log.Infof("%d blocks in %s, %.0f/s, %d txn, %.0f/s", blocks, dt.String(), float64(time.Second)*float64(blocks)/float64(dt), txCount, float64(time.Second)*float64(txCount)/float64(dt))
+		h.Log.WithFields(log.Fields{
+			"blocks":   blocks,
+			"txns":     txCount,
+			"duration": dt.Seconds(),
+		}).Infof("%.0f blocks/s, %.0f txns/s", float64(blocks)/dt.Seconds(), float64(txCount)/dt.Seconds())
 	}
 }
 
@@ -130,6 +147,10 @@ func importTar(imp *Importer, tarfile io.Reader, l *log.Logger) (blockCount, txC
 
 	for _, blockContainer := range blocks {
 		err = imp.ImportBlock(&blockContainer)
+		if errors.Is(err, ErrStopRound) {
+			err = nil
+			return
+		}
 		if err != nil {
 			return
 		}
@@ -142,6 +163,7 @@ func importFile(fname string, imp *Importer, l *log.Logger) (blocks, txCount int
 	blocks = 0
 	txCount = 0
 	l.Infof("importing %s ...", fname)
+	verifyArchive(fname, l)
 	if strings.HasSuffix(fname, ".tar") {
 		fin, err := os.Open(fname)
 		maybeFail(err, l, "%s: %v", fname, err)
@@ -167,6 +189,9 @@ func importFile(fname string, imp *Importer, l *log.Logger) (blocks, txCount int
 		err = protocol.Decode(blockbytes, &blockContainer)
 		maybeFail(err, l, "cannot decode blockbytes err: %v", err)
 		err = imp.ImportBlock(&blockContainer)
+		if errors.Is(err, ErrStopRound) {
+			return
+		}
 		maybeFail(err, l, "cannot import block err: %v", err)
 		blocks++
 		txCount += len(blockContainer.Block.Payset)
@@ -174,6 +199,23 @@ func importFile(fname string, imp *Importer, l *log.Logger) (blocks, txCount int
 	return
 }
 
+// verifyArchive checks the file against its manifest if it has one.
+func verifyArchive(fname string, l *log.Logger) {
+	problems, err := archive.Verify(fname)
+	if err == archive.ErrNoManifest {
+		return
+	}
+	maybeFail(err, l, "%s: could not verify archive, %v", fname, err)
+	if len(problems) > 0 {
+		for _, problem := range problems {
+			l.Error(problem.String())
+		}
+		l.Errorf("%s: archive is corrupt, %d problems found", fname, len(problems))
+		os.Exit(1)
+	}
+	l.Infof("%s: archive checksums verified", fname)
+}
+
 func loadGenesis(db idb.IndexerDb, in io.Reader) (err error) {
 	var genesis bookkeeping.Genesis
 	gbytes, err := ioutil.ReadAll(in)
@@ -221,6 +263,37 @@ func InitialImport(db idb.IndexerDb, genesisJSONPath string, client *algod.Clien
 	return true
 }
 
+// CheckGenesisHash returns an error wrapping idb.ErrorGenesisMismatch when algod
+// follows another network than the one of the database. Databases which have not
+// imported a block yet are not checked.
+func CheckGenesisHash(ctx context.Context, db idb.IndexerDb, client *algod.Client) error {
+	stored, err := db.GetGenesisHash()
+	if err != nil {
+		return fmt.Errorf("CheckGenesisHash() err: %w", err)
+	}
+	if stored == "" {
+		return nil
+	}
+
+	version, err := client.Versions().Do(ctx)
+	if err != nil {
+		return fmt.Errorf("CheckGenesisHash() versions err: %w", err)
+	}
+	var genesisHash crypto.Digest
+	if len(version.GenesisHashB64) != len(genesisHash) {
+		return fmt.Errorf(
+			"CheckGenesisHash() algod returned a genesis hash of %d bytes",
+			len(version.GenesisHashB64))
+	}
+	copy(genesisHash[:], version.GenesisHashB64)
+	if genesisHash.String() != stored {
+		return fmt.Errorf(
+			"CheckGenesisHash() algod has genesis hash %s (%s) but the database has %s: %w",
+			genesisHash, version.GenesisId, stored, idb.ErrorGenesisMismatch)
+	}
+	return nil
+}
+
 type blockTarPaths []string
 
 // Len is part of sort.Interface
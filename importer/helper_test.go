@@ -0,0 +1,56 @@
+package importer
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/algorand/go-algorand-sdk/client/v2/algod"
+	"github.com/algorand/go-algorand/crypto"
+	"github.com/algorand/go-algorand/rpcs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/algorand/indexer/idb"
+	"github.com/algorand/indexer/idb/memory"
+	"github.com/algorand/indexer/util/test"
+)
+
+// checkGenesisHash runs CheckGenesisHash() with an algod which has `genesisHash`.
+func checkGenesisHash(t *testing.T, db idb.IndexerDb, genesisHash crypto.Digest) error {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(
+			w, `{"genesis_hash_b64": "%s", "genesis_id": "test", "versions": ["v2"]}`,
+			base64.StdEncoding.EncodeToString(genesisHash[:]))
+	}))
+	defer server.Close()
+	client, err := algod.MakeClient(server.URL, "")
+	require.NoError(t, err)
+	return CheckGenesisHash(context.Background(), db, client)
+}
+
+func TestCheckGenesisHash(t *testing.T) {
+	db := memory.MakeIndexerDb(nil)
+	require.NoError(t, db.LoadGenesis(test.MakeGenesis()))
+	block0 := test.MakeGenesisBlock()
+	other := crypto.Hash([]byte("other network"))
+
+	// Nothing is checked before the first block.
+	assert.NoError(t, checkGenesisHash(t, db, other))
+
+	block1, err := test.MakeBlockForTxns(block0.BlockHeader)
+	require.NoError(t, err)
+	err = NewImporter(db).ImportBlocks(
+		[]*rpcs.EncodedBlockCert{{Block: block0}, {Block: block1}})
+	require.NoError(t, err)
+
+	assert.NoError(t, checkGenesisHash(t, db, block0.GenesisHash()))
+
+	err = checkGenesisHash(t, db, other)
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, idb.ErrorGenesisMismatch))
+}
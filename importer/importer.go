@@ -1,28 +1,134 @@
 package importer
 
 import (
+	"errors"
 	"fmt"
 
 	"github.com/algorand/go-algorand/config"
+	"github.com/algorand/go-algorand/data/bookkeeping"
 	"github.com/algorand/go-algorand/rpcs"
 
 	"github.com/algorand/indexer/idb"
 )
 
+// ErrStopRound is returned for blocks after the stop round, see SetStopRound().
+var ErrStopRound = errors.New("block is after the stop round")
+
+// ErrUnknownProtocol is returned for blocks of a consensus protocol which this
+// version does not know, e.g. after a network upgrade.
+var ErrUnknownProtocol = errors.New("unknown consensus protocol")
+
+// ProtocolSupported returns whether the consensus protocol of `block` is known,
+// i.e. whether the block can be imported.
+func ProtocolSupported(block *bookkeeping.Block) bool {
+	_, ok := config.Consensus[block.CurrentProtocol]
+	return ok
+}
+
 // Importer is used to import blocks into an idb.IndexerDb object.
 type Importer struct {
-	db idb.IndexerDb
+	db    idb.IndexerDb
+	hooks []PublishHook
+
+	// stopRound is the last round which is imported, 0 for no limit.
+	stopRound        uint64
+	stopRoundReached bool
+}
+
+// SetStopRound makes the importer refuse the blocks after `round`, e.g. to build
+// a dataset of a fixed range of rounds. 0 removes the limit.
+func (imp *Importer) SetStopRound(round uint64) {
+	imp.stopRound = round
+}
+
+// StopRoundReached returns true once the block of the stop round is imported.
+func (imp *Importer) StopRoundReached() bool {
+	return imp.stopRoundReached
+}
+
+// afterStopRound returns true if `round` must not be imported.
+func (imp *Importer) afterStopRound(round uint64) bool {
+	return imp.stopRound != 0 && round > imp.stopRound
+}
+
+// imported records that `round` was imported.
+func (imp *Importer) imported(round uint64) {
+	if imp.stopRound != 0 && round >= imp.stopRound {
+		imp.stopRoundReached = true
+	}
+}
+
+// AddPublishHook registers a hook which is called after each imported block.
+func (imp *Importer) AddPublishHook(hook PublishHook) {
+	imp.hooks = append(imp.hooks, hook)
+}
+
+// RegisterBlockHook registers a hook which is called before and after each
+// imported block is written, with the state delta computed for it. See
+// idb.BlockHook.
+func (imp *Importer) RegisterBlockHook(hook idb.BlockHook) {
+	imp.db.AddBlockHook(hook)
 }
 
 // ImportBlock processes a block and adds it to the IndexerDb
 func (imp *Importer) ImportBlock(blockContainer *rpcs.EncodedBlockCert) error {
 	block := &blockContainer.Block
 
-	_, ok := config.Consensus[block.CurrentProtocol]
-	if !ok {
-		return fmt.Errorf("protocol %s not found", block.CurrentProtocol)
+	if imp.afterStopRound(uint64(block.Round())) {
+		return ErrStopRound
+	}
+	if !ProtocolSupported(block) {
+		return fmt.Errorf(
+			"round %d protocol %s: %w", block.Round(), block.CurrentProtocol, ErrUnknownProtocol)
+	}
+	err := imp.db.AddBlock(&blockContainer.Block)
+	if err != nil {
+		return err
+	}
+	imp.imported(uint64(block.Round()))
+
+	for _, hook := range imp.hooks {
+		hook(uint64(block.Round()))
+	}
+	return nil
+}
+
+// ImportBlocks adds consecutive blocks to the IndexerDb in one database
+// transaction. The publish hooks are called for every block once they are all
+// added. The blocks after the stop round are not added, ErrStopRound is
+// returned after the others are.
+func (imp *Importer) ImportBlocks(blockContainers []*rpcs.EncodedBlockCert) error {
+	blocks := make([]*bookkeeping.Block, 0, len(blockContainers))
+	truncated := false
+	for _, blockContainer := range blockContainers {
+		block := &blockContainer.Block
+		if imp.afterStopRound(uint64(block.Round())) {
+			truncated = true
+			break
+		}
+		if !ProtocolSupported(block) {
+			return fmt.Errorf(
+				"round %d protocol %s: %w", block.Round(), block.CurrentProtocol, ErrUnknownProtocol)
+		}
+		blocks = append(blocks, block)
+	}
+	err := imp.db.AddBlocks(blocks)
+	if err != nil {
+		return err
+	}
+	if len(blocks) > 0 {
+		imp.imported(uint64(blocks[len(blocks)-1].Round()))
+	}
+
+	for _, block := range blocks {
+		for _, hook := range imp.hooks {
+			hook(uint64(block.Round()))
+		}
+	}
+	if truncated {
+		return ErrStopRound
 	}
-	return imp.db.AddBlock(&blockContainer.Block)
+	return nil
 }
 
 // NewImporter creates a new importer object.
@@ -0,0 +1,62 @@
+package importer
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/algorand/go-algorand/rpcs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/algorand/indexer/idb/memory"
+	"github.com/algorand/indexer/util/test"
+)
+
+func TestImportStopRound(t *testing.T) {
+	db := memory.MakeIndexerDb(nil)
+	require.NoError(t, db.LoadGenesis(test.MakeGenesis()))
+
+	block0 := test.MakeGenesisBlock()
+	block1, err := test.MakeBlockForTxns(block0.BlockHeader)
+	require.NoError(t, err)
+	block2, err := test.MakeBlockForTxns(block1.BlockHeader)
+	require.NoError(t, err)
+
+	imp := NewImporter(db)
+	imp.SetStopRound(1)
+	assert.False(t, imp.StopRoundReached())
+
+	err = imp.ImportBlocks([]*rpcs.EncodedBlockCert{{Block: block0}, {Block: block1}, {Block: block2}})
+	assert.Equal(t, ErrStopRound, err)
+	assert.True(t, imp.StopRoundReached())
+
+	err = imp.ImportBlock(&rpcs.EncodedBlockCert{Block: block2})
+	assert.Equal(t, ErrStopRound, err)
+
+	next, err := db.GetNextRoundToAccount()
+	require.NoError(t, err)
+	assert.Equal(t, uint64(2), next)
+}
+
+func TestImportUnknownProtocol(t *testing.T) {
+	db := memory.MakeIndexerDb(nil)
+	require.NoError(t, db.LoadGenesis(test.MakeGenesis()))
+
+	block0 := test.MakeGenesisBlock()
+	block1, err := test.MakeBlockForTxns(block0.BlockHeader)
+	require.NoError(t, err)
+	block1.CurrentProtocol = "future"
+	assert.False(t, ProtocolSupported(&block1))
+
+	imp := NewImporter(db)
+	err = imp.ImportBlocks([]*rpcs.EncodedBlockCert{{Block: block0}, {Block: block1}})
+	assert.True(t, errors.Is(err, ErrUnknownProtocol))
+
+	require.NoError(t, imp.ImportBlock(&rpcs.EncodedBlockCert{Block: block0}))
+	err = imp.ImportBlock(&rpcs.EncodedBlockCert{Block: block1})
+	assert.True(t, errors.Is(err, ErrUnknownProtocol))
+
+	next, err := db.GetNextRoundToAccount()
+	require.NoError(t, err)
+	assert.Equal(t, uint64(1), next)
+}
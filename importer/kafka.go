@@ -0,0 +1,182 @@
+package importer
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/algorand/go-algorand/data/bookkeeping"
+	"github.com/algorand/go-algorand/protocol"
+
+	"github.com/algorand/indexer/idb"
+)
+
+const (
+	// Records sent to the REST proxy in one request.
+	kafkaMaxRecords = 500
+
+	kafkaJSONContentType   = "application/vnd.kafka.json.v2+json"
+	kafkaBinaryContentType = "application/vnd.kafka.binary.v2+json"
+	kafkaAcceptType        = "application/vnd.kafka.v2+json"
+)
+
+// KafkaOptions configures a KafkaSink.
+type KafkaOptions struct {
+	// RESTURL is the URL of a Kafka REST proxy with the v2 API, e.g.
+	// http://localhost:8082.
+	RESTURL string
+
+	// TopicPrefix is the prefix of the topics, the block headers are published
+	// to "<prefix>.blocks" and the transactions to "<prefix>.transactions".
+	TopicPrefix string
+
+	// Msgpack publishes the values msgpack encoded instead of as JSON.
+	Msgpack bool
+
+	// Timeout is the timeout of a request to the REST proxy.
+	Timeout time.Duration
+}
+
+// KafkaSink is an ExportSink which publishes to Kafka through a REST proxy. The
+// transactions of a round are published before its block header, a consumer
+// which sees the header has been sent all the transactions of the round.
+type KafkaSink struct {
+	opts   KafkaOptions
+	client *http.Client
+}
+
+// MakeKafkaSink creates a KafkaSink.
+func MakeKafkaSink(opts KafkaOptions) (*KafkaSink, error) {
+	u, err := url.Parse(opts.RESTURL)
+	if err != nil {
+		return nil, fmt.Errorf("MakeKafkaSink() err: %w", err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, fmt.Errorf("MakeKafkaSink() unsupported URL %s", opts.RESTURL)
+	}
+	if opts.TopicPrefix == "" {
+		return nil, fmt.Errorf("MakeKafkaSink() no topic prefix")
+	}
+	opts.RESTURL = strings.TrimSuffix(opts.RESTURL, "/")
+	return &KafkaSink{
+		opts:   opts,
+		client: &http.Client{Timeout: opts.Timeout},
+	}, nil
+}
+
+type kafkaRecord struct {
+	Key   string      `json:"key"`
+	Value interface{} `json:"value"`
+}
+
+type kafkaRequest struct {
+	Records []kafkaRecord `json:"records"`
+}
+
+type kafkaResponse struct {
+	Offsets []struct {
+		ErrorCode *int    `json:"error_code"`
+		Error     *string `json:"error"`
+	} `json:"offsets"`
+	Message string `json:"message"`
+}
+
+// value encodes a record value in the format of the sink.
+func (s *KafkaSink) value(obj interface{}) interface{} {
+	if s.opts.Msgpack {
+		return base64.StdEncoding.EncodeToString(protocol.EncodeReflect(obj))
+	}
+	return json.RawMessage(protocol.EncodeJSON(obj))
+}
+
+// Publish is part of ExportSink.
+func (s *KafkaSink) Publish(ctx context.Context, header bookkeeping.BlockHeader, txns []idb.TxnRow) error {
+	records := make([]kafkaRecord, 0, len(txns))
+	for _, row := range txns {
+		txn, err := MakeExportedTransaction(row)
+		if err != nil {
+			return fmt.Errorf("Publish() err: %w", err)
+		}
+		records = append(records, kafkaRecord{Key: txn.Txid, Value: s.value(txn)})
+	}
+	err := s.send(ctx, s.opts.TopicPrefix+".transactions", records)
+	if err != nil {
+		return fmt.Errorf("Publish() round %d err: %w", header.Round, err)
+	}
+
+	record := kafkaRecord{
+		Key:   strconv.FormatUint(uint64(header.Round), 10),
+		Value: s.value(header),
+	}
+	err = s.send(ctx, s.opts.TopicPrefix+".blocks", []kafkaRecord{record})
+	if err != nil {
+		return fmt.Errorf("Publish() round %d err: %w", header.Round, err)
+	}
+	return nil
+}
+
+// send produces `records` to `topic`, at most kafkaMaxRecords per request.
+func (s *KafkaSink) send(ctx context.Context, topic string, records []kafkaRecord) error {
+	contentType := kafkaJSONContentType
+	if s.opts.Msgpack {
+		contentType = kafkaBinaryContentType
+	}
+
+	for len(records) > 0 {
+		n := len(records)
+		if n > kafkaMaxRecords {
+			n = kafkaMaxRecords
+		}
+		body, err := json.Marshal(kafkaRequest{Records: records[:n]})
+		if err != nil {
+			return fmt.Errorf("send() encode err: %w", err)
+		}
+		req, err := http.NewRequest(
+			http.MethodPost, s.opts.RESTURL+"/topics/"+url.PathEscape(topic), bytes.NewReader(body))
+		if err != nil {
+			return fmt.Errorf("send() err: %w", err)
+		}
+		req = req.WithContext(ctx)
+		req.Header.Set("Content-Type", contentType)
+		req.Header.Set("Accept", kafkaAcceptType)
+
+		resp, err := s.client.Do(req)
+		if err != nil {
+			return fmt.Errorf("send() topic %s err: %w", topic, err)
+		}
+		respBody, err := ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return fmt.Errorf("send() topic %s read err: %w", topic, err)
+		}
+		var response kafkaResponse
+		decodeErr := json.Unmarshal(respBody, &response)
+		if resp.StatusCode != http.StatusOK {
+			return fmt.Errorf(
+				"send() topic %s status %d: %s", topic, resp.StatusCode, response.Message)
+		}
+		if decodeErr != nil {
+			return fmt.Errorf("send() topic %s decode err: %w", topic, decodeErr)
+		}
+		for i, offset := range response.Offsets {
+			if offset.Error != nil || offset.ErrorCode != nil {
+				message := ""
+				if offset.Error != nil {
+					message = *offset.Error
+				}
+				return fmt.Errorf("send() topic %s record %d not delivered: %s", topic, i, message)
+			}
+		}
+
+		records = records[n:]
+	}
+	return nil
+}
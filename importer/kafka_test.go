@@ -0,0 +1,86 @@
+package importer
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/algorand/go-algorand/data/basics"
+	"github.com/algorand/go-algorand/data/bookkeeping"
+	"github.com/algorand/go-algorand/protocol"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/algorand/indexer/idb"
+	"github.com/algorand/indexer/util/test"
+)
+
+func TestKafkaSinkPublish(t *testing.T) {
+	requests := make(map[string]kafkaRequest)
+	failTopic := ""
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, kafkaJSONContentType, r.Header.Get("Content-Type"))
+		body, err := ioutil.ReadAll(r.Body)
+		require.NoError(t, err)
+		var req kafkaRequest
+		require.NoError(t, json.Unmarshal(body, &req))
+		requests[r.URL.Path] = req
+
+		if r.URL.Path == failTopic {
+			w.Write([]byte(`{"offsets": [{"error_code": 50003, "error": "timeout"}]}`))
+			return
+		}
+		w.Write([]byte(`{"offsets": [{"partition": 0, "offset": 1}]}`))
+	}))
+	defer server.Close()
+
+	sink, err := MakeKafkaSink(KafkaOptions{
+		RESTURL:     server.URL + "/",
+		TopicPrefix: "algorand",
+		Timeout:     time.Second,
+	})
+	require.NoError(t, err)
+
+	txn := test.MakePaymentTxn(
+		1000, 100, 0, 0, 0, 0, test.AccountA, test.AccountB, basics.Address{}, basics.Address{})
+	header := bookkeeping.BlockHeader{Round: 5}
+	rows := []idb.TxnRow{{
+		Round:     5,
+		Intra:     0,
+		RoundTime: time.Unix(1000, 0),
+		TxnBytes:  protocol.Encode(&txn),
+	}}
+
+	err = sink.Publish(context.Background(), header, rows)
+	require.NoError(t, err)
+
+	require.Len(t, requests["/topics/algorand.transactions"].Records, 1)
+	record := requests["/topics/algorand.transactions"].Records[0]
+	txid := txn.Txn.ID().String()
+	assert.Equal(t, txid, record.Key)
+	value := record.Value.(map[string]interface{})
+	assert.Equal(t, txid, value["txid"])
+	assert.Equal(t, float64(5), value["round"])
+	assert.Equal(t, float64(1000), value["round-time"])
+
+	require.Len(t, requests["/topics/algorand.blocks"].Records, 1)
+	assert.Equal(t, "5", requests["/topics/algorand.blocks"].Records[0].Key)
+
+	// The block header is not published when a transaction was not delivered.
+	requests = make(map[string]kafkaRequest)
+	failTopic = "/topics/algorand.transactions"
+	err = sink.Publish(context.Background(), header, rows)
+	assert.Error(t, err)
+	assert.NotContains(t, requests, "/topics/algorand.blocks")
+}
+
+func TestMakeKafkaSinkErrors(t *testing.T) {
+	_, err := MakeKafkaSink(KafkaOptions{RESTURL: "localhost:8082", TopicPrefix: "algorand"})
+	assert.Error(t, err)
+	_, err = MakeKafkaSink(KafkaOptions{RESTURL: "http://localhost:8082"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,90 @@
+package importer
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"time"
+
+	mqtt "github.com/eclipse/paho.mqtt.golang"
+)
+
+// mqttDisconnectQuiesce is how long closing a connection waits for the work in
+// flight, in milliseconds.
+const mqttDisconnectQuiesce = 250
+
+// mqttConn publishes to an MQTT 3.1.1 broker. The messages are published with
+// QoS 1, so that the broker acknowledges them.
+type mqttConn struct {
+	client mqtt.Client
+	// tokens are of the published messages which were not acknowledged.
+	tokens []mqtt.Token
+}
+
+// waitToken waits for `token` until `timeout`.
+func waitToken(token mqtt.Token, timeout time.Duration) error {
+	if !token.WaitTimeout(timeout) {
+		return fmt.Errorf("timed out after %s", timeout)
+	}
+	return token.Error()
+}
+
+// dialMQTT connects to the MQTT broker of `u`,
+// mqtt://[user[:password]@]host[:port][?client_id=<id>]. Without a client id the
+// broker assigns one. The connection is not reconnected by the client, the
+// EventSink dials again after an error.
+func dialMQTT(u *url.URL, timeout time.Duration) (*mqttConn, error) {
+	host := u.Host
+	if u.Port() == "" {
+		host = net.JoinHostPort(u.Hostname(), "1883")
+	}
+	opts := mqtt.NewClientOptions().
+		AddBroker("tcp://" + host).
+		SetClientID(u.Query().Get("client_id")).
+		SetProtocolVersion(4).
+		SetCleanSession(true).
+		SetAutoReconnect(false).
+		SetConnectRetry(false).
+		SetConnectTimeout(timeout).
+		SetWriteTimeout(timeout)
+	if u.User != nil {
+		opts.SetUsername(u.User.Username())
+		if password, ok := u.User.Password(); ok {
+			opts.SetPassword(password)
+		}
+	}
+
+	client := mqtt.NewClient(opts)
+	err := waitToken(client.Connect(), timeout)
+	if err != nil {
+		return nil, fmt.Errorf("dialMQTT() err: %w", err)
+	}
+	return &mqttConn{client: client}, nil
+}
+
+// publish is part of eventConn.
+func (c *mqttConn) publish(topic string, payload []byte) error {
+	if topic == "" || len(topic) > 0xffff {
+		return fmt.Errorf("publish() invalid topic %q", topic)
+	}
+	c.tokens = append(c.tokens, c.client.Publish(topic, 1, false, payload))
+	return nil
+}
+
+// flush is part of eventConn, it waits for the acknowledgements.
+func (c *mqttConn) flush(timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	for len(c.tokens) > 0 {
+		err := waitToken(c.tokens[0], time.Until(deadline))
+		if err != nil {
+			return fmt.Errorf("flush() err: %w", err)
+		}
+		c.tokens = c.tokens[1:]
+	}
+	return nil
+}
+
+// close is part of eventConn.
+func (c *mqttConn) close() {
+	c.client.Disconnect(mqttDisconnectQuiesce)
+}
@@ -0,0 +1,51 @@
+package importer
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/nats-io/nats.go"
+)
+
+// natsConn publishes to a NATS server.
+type natsConn struct {
+	nc *nats.Conn
+}
+
+// dialNATS connects to the NATS server of `url`, nats://[user:password@]host[:port]
+// or nats://token@host[:port]. The connection is not reconnected by the client,
+// the EventSink dials again after an error.
+func dialNATS(url string, timeout time.Duration) (*natsConn, error) {
+	nc, err := nats.Connect(url,
+		nats.Name("algorand-indexer"),
+		nats.Timeout(timeout),
+		nats.NoReconnect())
+	if err != nil {
+		return nil, fmt.Errorf("dialNATS() err: %w", err)
+	}
+	return &natsConn{nc: nc}, nil
+}
+
+// publish is part of eventConn.
+func (c *natsConn) publish(topic string, payload []byte) error {
+	err := c.nc.Publish(topic, payload)
+	if err != nil {
+		return fmt.Errorf("publish() subject %q err: %w", topic, err)
+	}
+	return nil
+}
+
+// flush is part of eventConn. The server answers a PING after it processed the
+// messages sent before it.
+func (c *natsConn) flush(timeout time.Duration) error {
+	err := c.nc.FlushTimeout(timeout)
+	if err != nil {
+		return fmt.Errorf("flush() err: %w", err)
+	}
+	return nil
+}
+
+// close is part of eventConn.
+func (c *natsConn) close() {
+	c.nc.Close()
+}
@@ -0,0 +1,76 @@
+package importer
+
+import (
+	"context"
+	"sync"
+)
+
+// Pauser lets an operator pause the import of new blocks at runtime. The block
+// handler calls Wait before importing each block, which blocks while paused.
+type Pauser struct {
+	mu sync.Mutex
+	// resumed is closed while the importer is not paused.
+	resumed chan struct{}
+}
+
+// MakePauser creates a Pauser in the running state.
+func MakePauser() *Pauser {
+	resumed := make(chan struct{})
+	close(resumed)
+	return &Pauser{resumed: resumed}
+}
+
+// Pause makes subsequent calls to Wait block until Resume is called. A block
+// which is being imported is not interrupted.
+func (p *Pauser) Pause() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	select {
+	case <-p.resumed:
+		p.resumed = make(chan struct{})
+	default:
+		// Already paused.
+	}
+}
+
+// Resume releases the importer.
+func (p *Pauser) Resume() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	select {
+	case <-p.resumed:
+		// Not paused.
+	default:
+		close(p.resumed)
+	}
+}
+
+// Paused returns whether the importer is paused.
+func (p *Pauser) Paused() bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	select {
+	case <-p.resumed:
+		return false
+	default:
+		return true
+	}
+}
+
+// Wait blocks while paused. It returns the context error if the context is
+// canceled first.
+func (p *Pauser) Wait(ctx context.Context) error {
+	p.mu.Lock()
+	resumed := p.resumed
+	p.mu.Unlock()
+
+	select {
+	case <-resumed:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,44 @@
+package importer
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPauserWait(t *testing.T) {
+	p := MakePauser()
+	assert.False(t, p.Paused())
+	assert.NoError(t, p.Wait(context.Background()))
+
+	p.Pause()
+	p.Pause()
+	assert.True(t, p.Paused())
+
+	done := make(chan error)
+	go func() {
+		done <- p.Wait(context.Background())
+	}()
+
+	select {
+	case <-done:
+		t.Fatal("Wait() returned while paused")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	p.Resume()
+	p.Resume()
+	assert.NoError(t, <-done)
+	assert.False(t, p.Paused())
+}
+
+func TestPauserWaitCanceled(t *testing.T) {
+	p := MakePauser()
+	p.Pause()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.Equal(t, context.Canceled, p.Wait(ctx))
+}
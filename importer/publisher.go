@@ -0,0 +1,68 @@
+package importer
+
+import (
+	"context"
+	"sync"
+)
+
+// PublishHook is called with the round of each block after it is imported.
+type PublishHook func(round uint64)
+
+// RoundPublisher keeps track of the latest imported round and wakes up the
+// goroutines waiting for a new round. Its Publish method is meant to be added to
+// an Importer with AddPublishHook.
+type RoundPublisher struct {
+	mu    sync.Mutex
+	round uint64
+	// next is closed and replaced when a round is published.
+	next chan struct{}
+}
+
+// MakeRoundPublisher creates a RoundPublisher. `round` is the latest round which
+// was imported before the publisher was created.
+func MakeRoundPublisher(round uint64) *RoundPublisher {
+	return &RoundPublisher{
+		round: round,
+		next:  make(chan struct{}),
+	}
+}
+
+// Publish records that `round` has been imported.
+func (p *RoundPublisher) Publish(round uint64) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if round > p.round {
+		p.round = round
+	}
+	close(p.next)
+	p.next = make(chan struct{})
+}
+
+// Round returns the latest imported round.
+func (p *RoundPublisher) Round() uint64 {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.round
+}
+
+// WaitForRoundAfter blocks until a round greater than `round` has been imported or
+// the context is done, and returns the latest imported round.
+func (p *RoundPublisher) WaitForRoundAfter(ctx context.Context, round uint64) uint64 {
+	for {
+		p.mu.Lock()
+		latest := p.round
+		next := p.next
+		p.mu.Unlock()
+
+		if latest > round {
+			return latest
+		}
+
+		select {
+		case <-next:
+		case <-ctx.Done():
+			return latest
+		}
+	}
+}
@@ -0,0 +1,43 @@
+package importer
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRoundPublisherAlreadyImported(t *testing.T) {
+	p := MakeRoundPublisher(10)
+	assert.Equal(t, uint64(10), p.WaitForRoundAfter(context.Background(), 9))
+}
+
+func TestRoundPublisherWaits(t *testing.T) {
+	p := MakeRoundPublisher(10)
+
+	done := make(chan uint64)
+	go func() {
+		done <- p.WaitForRoundAfter(context.Background(), 11)
+	}()
+
+	// Round 11 is not enough.
+	p.Publish(11)
+	select {
+	case <-done:
+		t.Fatal("WaitForRoundAfter() returned too early")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	p.Publish(12)
+	assert.Equal(t, uint64(12), <-done)
+	assert.Equal(t, uint64(12), p.Round())
+}
+
+func TestRoundPublisherTimeout(t *testing.T) {
+	p := MakeRoundPublisher(10)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	assert.Equal(t, uint64(10), p.WaitForRoundAfter(ctx, 10))
+}
@@ -0,0 +1,68 @@
+package importer
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"sort"
+
+	"github.com/golang/snappy"
+	"github.com/prometheus/prometheus/prompb"
+)
+
+// remoteWriteSeries is a time series of a Prometheus remote write request.
+type remoteWriteSeries struct {
+	name   string
+	labels map[string]string
+	value  float64
+	timeMs int64
+}
+
+// makeWriteRequest returns the remote write request of `series`, with a sample
+// per series. The labels of a series are sorted by name, with __name__ first.
+func makeWriteRequest(series []remoteWriteSeries) *prompb.WriteRequest {
+	req := &prompb.WriteRequest{Timeseries: make([]prompb.TimeSeries, 0, len(series))}
+	for _, s := range series {
+		labels := make([]prompb.Label, 0, len(s.labels)+1)
+		for name, value := range s.labels {
+			labels = append(labels, prompb.Label{Name: name, Value: value})
+		}
+		sort.Slice(labels, func(i, j int) bool { return labels[i].Name < labels[j].Name })
+		labels = append([]prompb.Label{{Name: "__name__", Value: s.name}}, labels...)
+
+		req.Timeseries = append(req.Timeseries, prompb.TimeSeries{
+			Labels:  labels,
+			Samples: []prompb.Sample{{Value: s.value, Timestamp: s.timeMs}},
+		})
+	}
+	return req
+}
+
+// remoteWrite sends `series` to a Prometheus remote write endpoint.
+func remoteWrite(ctx context.Context, client *http.Client, url string, series []remoteWriteSeries) error {
+	data, err := makeWriteRequest(series).Marshal()
+	if err != nil {
+		return fmt.Errorf("remoteWrite() err: %w", err)
+	}
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(snappy.Encode(nil, data)))
+	if err != nil {
+		return fmt.Errorf("remoteWrite() err: %w", err)
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Content-Encoding", "snappy")
+	req.Header.Set("Content-Type", "application/x-protobuf")
+	req.Header.Set("X-Prometheus-Remote-Write-Version", "0.1.0")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("remoteWrite() err: %w", err)
+	}
+	respBody, _ := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("remoteWrite() status %d: %s", resp.StatusCode, respBody)
+	}
+	return nil
+}
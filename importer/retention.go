@@ -0,0 +1,87 @@
+package importer
+
+import (
+	"context"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/algorand/indexer/idb"
+)
+
+const (
+	// How often the retention job checks whether rounds can be pruned.
+	retentionInterval = time.Minute
+
+	// Rounds deleted per database transaction, so that the job does not hold
+	// long transactions or compete with the import for long.
+	retentionBatchRounds = 1000
+)
+
+// RetentionOptions is how much history RunRetention() keeps.
+type RetentionOptions struct {
+	// KeepRounds is the number of latest rounds whose transactions are kept, 0
+	// keeps all.
+	KeepRounds uint64
+
+	// KeepParticipationRounds is the number of latest rounds whose transactions
+	// can be searched by address, 0 keeps all. The participation rows dominate
+	// the size of the database, they can be pruned sooner than the transactions.
+	KeepParticipationRounds uint64
+}
+
+// RunRetention deletes the history which is older than `opts` allows until the
+// context is done. Accounts and block headers are kept. The rounds are pruned in
+// small batches in the background of the import.
+func RunRetention(ctx context.Context, db idb.IndexerDb, opts RetentionOptions, l *log.Logger) {
+	ticker := time.NewTicker(retentionInterval)
+	defer ticker.Stop()
+
+	for {
+		pruneRounds(ctx, db, opts, l)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// pruneRounds prunes batches until the earliest rounds are as far behind the
+// next round to import as `opts` allows.
+func pruneRounds(ctx context.Context, db idb.IndexerDb, opts RetentionOptions, l *log.Logger) {
+	nextRound, err := db.GetNextRoundToAccount()
+	if err != nil {
+		l.WithError(err).Warn("retention: failed to get the next round")
+		return
+	}
+
+	prune := func(what string, keep uint64, f func(round uint64) (uint64, error)) {
+		if keep == 0 || nextRound <= keep {
+			return
+		}
+		target := nextRound - keep
+		for ctx.Err() == nil {
+			earliest, err := f(target)
+			if err != nil {
+				l.WithError(err).WithField("pruned", what).Warn("retention: failed to prune")
+				return
+			}
+			if earliest >= target {
+				return
+			}
+			l.WithFields(log.Fields{
+				"pruned": what,
+				"round":  earliest,
+			}).Info("retention: pruned the rounds before the round")
+		}
+	}
+
+	prune("transactions", opts.KeepRounds, func(round uint64) (uint64, error) {
+		return db.PruneTransactions(ctx, round, retentionBatchRounds)
+	})
+	prune("participation", opts.KeepParticipationRounds, func(round uint64) (uint64, error) {
+		return db.PruneParticipation(ctx, round, retentionBatchRounds, nil)
+	})
+}
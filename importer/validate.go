@@ -0,0 +1,214 @@
+package importer
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/algorand/go-algorand-sdk/client/v2/algod"
+	algodmodels "github.com/algorand/go-algorand-sdk/client/v2/common/models"
+	"github.com/algorand/go-algorand/data/basics"
+	"github.com/algorand/go-algorand/data/bookkeeping"
+	"github.com/algorand/go-algorand/ledger/ledgercore"
+	log "github.com/sirupsen/logrus"
+
+	models "github.com/algorand/indexer/api/generated/v2"
+	"github.com/algorand/indexer/idb"
+	"github.com/algorand/indexer/util/metrics"
+)
+
+// Results of the comparison of an account in the metrics.
+const (
+	validationOK       = "ok"
+	validationMismatch = "mismatch"
+	validationSkipped  = "skipped"
+	validationError    = "error"
+)
+
+var validatedAccounts = metrics.DefaultRegistry.NewCounterVec(
+	"validated_accounts_total",
+	"Accounts compared with algod by the import validation, by result.",
+	"result")
+
+// ValidationOptions configures a Validator.
+type ValidationOptions struct {
+	// Interval is the number of rounds between validations.
+	Interval uint64
+
+	// Accounts is the maximum number of the accounts modified by a validated
+	// round which are compared with algod.
+	Accounts int
+}
+
+type validation struct {
+	round     uint64
+	addresses []basics.Address
+}
+
+// Validator compares a sample of the accounts which the import modified with
+// algod, to catch accounting bugs early. It is an idb.BlockHook which picks the
+// accounts of every Interval-th round, Run() compares them in the background.
+//
+// algod only returns the current state of accounts, so a round can only be
+// validated while algod is still at it, i.e. near the tip. The accounts of
+// other rounds are skipped.
+type Validator struct {
+	db      idb.IndexerDb
+	client  *algod.Client
+	opts    ValidationOptions
+	log     *log.Logger
+	pending chan validation
+}
+
+// MakeValidator creates a Validator which compares the state in `db` with the
+// state of the algod of `client`.
+func MakeValidator(db idb.IndexerDb, client *algod.Client, opts ValidationOptions, l *log.Logger) *Validator {
+	return &Validator{
+		db:      db,
+		client:  client,
+		opts:    opts,
+		log:     l,
+		pending: make(chan validation, 1),
+	}
+}
+
+// BeforeBlock is part of idb.BlockHook.
+func (v *Validator) BeforeBlock(block *bookkeeping.Block, delta ledgercore.StateDelta) error {
+	return nil
+}
+
+// AfterBlock is part of idb.BlockHook. The round is skipped if the previous
+// validation is still running, the import is not slowed down.
+func (v *Validator) AfterBlock(block *bookkeeping.Block, delta ledgercore.StateDelta) {
+	round := uint64(block.Round())
+	if round == 0 || v.opts.Interval == 0 || round%v.opts.Interval != 0 {
+		return
+	}
+
+	var addresses []basics.Address
+	for i := 0; i < delta.Accts.Len() && len(addresses) < v.opts.Accounts; i++ {
+		address, _ := delta.Accts.GetByIdx(i)
+		// Indexer currently doesn't support special accounts.
+		if address == block.FeeSink || address == block.RewardsPool {
+			continue
+		}
+		addresses = append(addresses, address)
+	}
+	if len(addresses) == 0 {
+		return
+	}
+
+	select {
+	case v.pending <- validation{round: round, addresses: addresses}:
+	default:
+		validatedAccounts.WithLabelValues(validationSkipped).Add(float64(len(addresses)))
+	}
+}
+
+// Run compares the picked accounts until the context is done.
+func (v *Validator) Run(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case val := <-v.pending:
+			for _, address := range val.addresses {
+				result := v.validateAccount(ctx, val.round, address)
+				validatedAccounts.WithLabelValues(result).Inc()
+			}
+		}
+	}
+}
+
+// validateAccount compares `address` in the database and in algod, if both
+// are at `round`. It returns the result for the metrics.
+func (v *Validator) validateAccount(ctx context.Context, round uint64, address basics.Address) string {
+	info, err := v.client.AccountInformation(address.String()).Do(ctx)
+	if err != nil {
+		v.log.WithError(err).Warnf("validation: failed to get account %s from algod", address)
+		return validationError
+	}
+	if info.Round != round {
+		return validationSkipped
+	}
+
+	opts := idb.AccountQueryOptions{
+		EqualToAddress:       address[:],
+		IncludeAssetHoldings: true,
+		IncludeAssetParams:   true,
+	}
+	accountsCh, dbRound := v.db.GetAccounts(ctx, opts)
+	// A closed account is not returned, algod returns it with no algos.
+	var account models.Account
+	for row := range accountsCh {
+		if row.Error != nil {
+			v.log.WithError(row.Error).Warnf("validation: failed to get account %s", address)
+			return validationError
+		}
+		account = row.Account
+	}
+	if dbRound != round {
+		return validationSkipped
+	}
+
+	diffs := compareAccounts(info, account)
+	if len(diffs) > 0 {
+		v.log.WithFields(log.Fields{
+			"round":   round,
+			"address": address.String(),
+			"diffs":   diffs,
+		}).Error("validation: the account differs from algod")
+		return validationMismatch
+	}
+	return validationOK
+}
+
+// compareAccounts returns the differences between the account of algod and of
+// the indexer, empty if they agree.
+func compareAccounts(algodAccount algodmodels.Account, account models.Account) []string {
+	var diffs []string
+	if algodAccount.AmountWithoutPendingRewards != account.AmountWithoutPendingRewards {
+		diffs = append(diffs, fmt.Sprintf(
+			"amount without pending rewards %d != %d",
+			algodAccount.AmountWithoutPendingRewards, account.AmountWithoutPendingRewards))
+	}
+
+	holdings := make(map[uint64]models.AssetHolding)
+	if account.Assets != nil {
+		for _, holding := range *account.Assets {
+			holdings[holding.AssetId] = holding
+		}
+	}
+	for _, algodHolding := range algodAccount.Assets {
+		holding, ok := holdings[algodHolding.AssetId]
+		delete(holdings, algodHolding.AssetId)
+		if !ok {
+			diffs = append(diffs, fmt.Sprintf("asset %d missing", algodHolding.AssetId))
+			continue
+		}
+		if algodHolding.Amount != holding.Amount || algodHolding.IsFrozen != holding.IsFrozen {
+			diffs = append(diffs, fmt.Sprintf(
+				"asset %d amount %d frozen %t != amount %d frozen %t", algodHolding.AssetId,
+				algodHolding.Amount, algodHolding.IsFrozen, holding.Amount, holding.IsFrozen))
+		}
+	}
+	var extra []uint64
+	for assetID := range holdings {
+		extra = append(extra, assetID)
+	}
+	sort.Slice(extra, func(i, j int) bool { return extra[i] < extra[j] })
+	for _, assetID := range extra {
+		diffs = append(diffs, fmt.Sprintf("asset %d not held in algod", assetID))
+	}
+
+	createdAssets := 0
+	if account.CreatedAssets != nil {
+		createdAssets = len(*account.CreatedAssets)
+	}
+	if len(algodAccount.CreatedAssets) != createdAssets {
+		diffs = append(diffs, fmt.Sprintf(
+			"created assets %d != %d", len(algodAccount.CreatedAssets), createdAssets))
+	}
+
+	return diffs
+}
@@ -0,0 +1,50 @@
+package importer
+
+import (
+	"testing"
+
+	algodmodels "github.com/algorand/go-algorand-sdk/client/v2/common/models"
+	"github.com/stretchr/testify/assert"
+
+	models "github.com/algorand/indexer/api/generated/v2"
+)
+
+func TestCompareAccounts(t *testing.T) {
+	algodAccount := algodmodels.Account{
+		AmountWithoutPendingRewards: 1000,
+		Assets: []algodmodels.AssetHolding{
+			{AssetId: 1, Amount: 5},
+			{AssetId: 2, Amount: 7, IsFrozen: true},
+		},
+		CreatedAssets: []algodmodels.Asset{{Index: 1}},
+	}
+	account := models.Account{
+		AmountWithoutPendingRewards: 1000,
+		Assets: &[]models.AssetHolding{
+			{AssetId: 2, Amount: 7, IsFrozen: true},
+			{AssetId: 1, Amount: 5},
+		},
+		CreatedAssets: &[]models.Asset{{Index: 1}},
+	}
+	assert.Empty(t, compareAccounts(algodAccount, account))
+
+	account.AmountWithoutPendingRewards = 999
+	account.Assets = &[]models.AssetHolding{
+		{AssetId: 2, Amount: 7},
+		{AssetId: 3, Amount: 1},
+	}
+	account.CreatedAssets = nil
+	expected := []string{
+		"amount without pending rewards 1000 != 999",
+		"asset 1 missing",
+		"asset 2 amount 7 frozen true != amount 7 frozen false",
+		"asset 3 not held in algod",
+		"created assets 1 != 0",
+	}
+	assert.Equal(t, expected, compareAccounts(algodAccount, account))
+}
+
+func TestCompareAccountsClosed(t *testing.T) {
+	// A closed account is not returned by the indexer.
+	assert.Empty(t, compareAccounts(algodmodels.Account{}, models.Account{}))
+}
@@ -0,0 +1,205 @@
+package importer
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/algorand/go-algorand/data/bookkeeping"
+	"github.com/algorand/go-algorand/protocol"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/algorand/indexer/idb"
+	"github.com/algorand/indexer/util/metrics"
+)
+
+const (
+	// WebhookSignatureHeader is the header with the hex HMAC-SHA256 of the body,
+	// keyed by the secret of the webhook.
+	WebhookSignatureHeader = "X-Indexer-Signature"
+
+	// Requests to a webhook URL for a round, the waits between them double.
+	webhookAttempts     = 5
+	webhookFirstBackoff = time.Second
+	webhookTimeout      = 10 * time.Second
+)
+
+// Results of the deliveries in the metrics.
+const (
+	webhookDelivered = "delivered"
+	webhookFailed    = "failed"
+)
+
+var webhookDeliveries = metrics.DefaultRegistry.NewCounterVec(
+	"webhook_deliveries_total",
+	"Rounds posted to webhooks, by result. A failed delivery is not retried.",
+	"result")
+
+// WebhookPayload is the body posted to a webhook, with the matching
+// transactions of a round.
+type WebhookPayload struct {
+	WebhookID    string            `json:"webhook-id"`
+	Round        uint64            `json:"round"`
+	Transactions []json.RawMessage `json:"transactions"`
+}
+
+// WebhookSink is an ExportSink which posts the transactions which match the
+// filters of the registered webhooks to their URLs. The webhooks are read from
+// the database for every round, so webhooks registered by any daemon sharing
+// the database take effect with the next round.
+//
+// A delivery is retried a few times, then dropped, so that a webhook which is
+// down does not hold up the others.
+type WebhookSink struct {
+	db      idb.IndexerDb
+	client  *http.Client
+	log     *log.Logger
+	backoff time.Duration
+}
+
+// MakeWebhookSink creates a WebhookSink for the webhooks of `db`.
+func MakeWebhookSink(db idb.IndexerDb, l *log.Logger) *WebhookSink {
+	return &WebhookSink{
+		db:      db,
+		client:  &http.Client{Timeout: webhookTimeout},
+		log:     l,
+		backoff: webhookFirstBackoff,
+	}
+}
+
+// SignWebhookBody returns the signature of `body` with `secret`, as sent in the
+// WebhookSignatureHeader.
+func SignWebhookBody(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// Publish is part of ExportSink. It only fails if the webhooks can't be read.
+func (s *WebhookSink) Publish(ctx context.Context, header bookkeeping.BlockHeader, txns []idb.TxnRow) error {
+	hooks, err := s.db.Webhooks(ctx)
+	if err != nil {
+		return fmt.Errorf("Publish() err: %w", err)
+	}
+	if len(hooks) == 0 || len(txns) == 0 {
+		return nil
+	}
+
+	exported := make([]ExportedTransaction, 0, len(txns))
+	for _, row := range txns {
+		txn, err := MakeExportedTransaction(row)
+		if err != nil {
+			return fmt.Errorf("Publish() err: %w", err)
+		}
+		exported = append(exported, txn)
+	}
+
+	var wg sync.WaitGroup
+	for _, hook := range hooks {
+		payload, err := matchingTransactions(hook, exported, txns)
+		if err != nil {
+			s.log.WithError(err).Warnf("webhook %s: invalid filter", hook.ID)
+			continue
+		}
+		if len(payload) == 0 {
+			continue
+		}
+		body, err := json.Marshal(WebhookPayload{
+			WebhookID:    hook.ID,
+			Round:        uint64(header.Round),
+			Transactions: payload,
+		})
+		if err != nil {
+			return fmt.Errorf("Publish() encode err: %w", err)
+		}
+
+		wg.Add(1)
+		go func(hook idb.Webhook) {
+			defer wg.Done()
+			err := s.deliver(ctx, hook, body)
+			if err != nil {
+				webhookDeliveries.WithLabelValues(webhookFailed).Inc()
+				s.log.WithError(err).WithFields(log.Fields{
+					"webhook": hook.ID,
+					"round":   uint64(header.Round),
+					"url":     hook.URL,
+				}).Warn("webhook failed to post the round")
+				return
+			}
+			webhookDeliveries.WithLabelValues(webhookDelivered).Inc()
+		}(hook)
+	}
+	wg.Wait()
+	return nil
+}
+
+// matchingTransactions returns the JSON of the transactions which match the
+// filter of `hook`.
+func matchingTransactions(hook idb.Webhook, exported []ExportedTransaction, rows []idb.TxnRow) ([]json.RawMessage, error) {
+	rules, err := idb.MakeTxnRules(idb.TxnRulesConfig{
+		Rules:            []idb.TxnRule{hook.Filter},
+		ExcludeUnmatched: true,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("matchingTransactions() err: %w", err)
+	}
+
+	var res []json.RawMessage
+	for i := range exported {
+		txn := &exported[i].Txn.Txn
+		typeenum, _ := idb.GetTypeEnum(txn.Type)
+		if rules.Store(txn, typeenum, rows[i].AssetID) {
+			res = append(res, protocol.EncodeJSON(exported[i]))
+		}
+	}
+	return res, nil
+}
+
+// deliver posts `body` to the URL of `hook`, retrying failures.
+func (s *WebhookSink) deliver(ctx context.Context, hook idb.Webhook, body []byte) error {
+	signature := SignWebhookBody(hook.Secret, body)
+	backoff := s.backoff
+	var err error
+	for attempt := 1; attempt <= webhookAttempts; attempt++ {
+		if attempt > 1 {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(backoff):
+			}
+			backoff *= 2
+		}
+		err = s.post(ctx, hook.URL, signature, body)
+		if err == nil {
+			return nil
+		}
+	}
+	return fmt.Errorf("deliver() %d attempts err: %w", webhookAttempts, err)
+}
+
+func (s *WebhookSink) post(ctx context.Context, url string, signature string, body []byte) error {
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("post() err: %w", err)
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(WebhookSignatureHeader, signature)
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("post() err: %w", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("post() status %d", resp.StatusCode)
+	}
+	return nil
+}
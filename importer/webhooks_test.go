@@ -0,0 +1,80 @@
+package importer
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/algorand/go-algorand/data/basics"
+	"github.com/algorand/go-algorand/data/bookkeeping"
+	"github.com/algorand/go-algorand/protocol"
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/algorand/indexer/idb"
+	"github.com/algorand/indexer/idb/memory"
+	"github.com/algorand/indexer/util/test"
+)
+
+func TestWebhookSinkPublish(t *testing.T) {
+	var payloads []WebhookPayload
+	failures := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if failures > 0 {
+			failures--
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		body, err := ioutil.ReadAll(r.Body)
+		require.NoError(t, err)
+		assert.Equal(t, SignWebhookBody("secret", body), r.Header.Get(WebhookSignatureHeader))
+		var payload WebhookPayload
+		require.NoError(t, json.Unmarshal(body, &payload))
+		payloads = append(payloads, payload)
+	}))
+	defer server.Close()
+
+	db := memory.MakeIndexerDb(nil)
+	ctx := context.Background()
+	require.NoError(t, db.AddWebhook(ctx, idb.Webhook{
+		ID:     "b",
+		URL:    server.URL,
+		Filter: idb.TxnRule{Receivers: []string{test.AccountB.String()}},
+		Secret: "secret",
+	}))
+	require.NoError(t, db.AddWebhook(ctx, idb.Webhook{
+		ID:     "c",
+		URL:    server.URL,
+		Filter: idb.TxnRule{Receivers: []string{test.AccountC.String()}},
+		Secret: "secret",
+	}))
+
+	txnB := test.MakePaymentTxn(
+		1000, 100, 0, 0, 0, 0, test.AccountA, test.AccountB, basics.Address{}, basics.Address{})
+	txnD := test.MakePaymentTxn(
+		1000, 100, 0, 0, 0, 0, test.AccountA, test.AccountD, basics.Address{}, basics.Address{})
+	rows := []idb.TxnRow{
+		{Round: 3, Intra: 0, TxnBytes: protocol.Encode(&txnB)},
+		{Round: 3, Intra: 1, TxnBytes: protocol.Encode(&txnD)},
+	}
+
+	sink := MakeWebhookSink(db, log.New())
+	sink.backoff = time.Millisecond
+	failures = 2
+	err := sink.Publish(ctx, bookkeeping.BlockHeader{Round: 3}, rows)
+	require.NoError(t, err)
+
+	// Only the webhook of B matches, after two failed attempts.
+	require.Len(t, payloads, 1)
+	assert.Equal(t, "b", payloads[0].WebhookID)
+	assert.Equal(t, uint64(3), payloads[0].Round)
+	require.Len(t, payloads[0].Transactions, 1)
+	var txn map[string]interface{}
+	require.NoError(t, json.Unmarshal(payloads[0].Transactions[0], &txn))
+	assert.Equal(t, txnB.Txn.ID().String(), txn["txid"])
+}
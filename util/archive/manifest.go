@@ -0,0 +1,342 @@
+package archive
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// ManifestVersion is the current manifest format version.
+const ManifestVersion = 1
+
+// DefaultChunkSize is the number of bytes covered by each checksum.
+const DefaultChunkSize = 64 * 1024 * 1024
+
+// DirManifestName is the name of the manifest stored inside an archive directory.
+const DirManifestName = "MANIFEST.json"
+
+// FileManifestSuffix is appended to the name of a single file archive to get the
+// name of its manifest.
+const FileManifestSuffix = ".manifest.json"
+
+// ErrNoManifest is returned when an archive does not have a manifest.
+var ErrNoManifest = errors.New("archive has no manifest")
+
+// Chunk is the checksum of one fixed size piece of a file.
+type Chunk struct {
+	Offset int64  `json:"offset"`
+	Size   int64  `json:"size"`
+	SHA256 string `json:"sha256"`
+}
+
+// File describes one file covered by a manifest.
+type File struct {
+	// Name is the path of the file relative to the manifest.
+	Name   string  `json:"name"`
+	Size   int64   `json:"size"`
+	SHA256 string  `json:"sha256"`
+	Chunks []Chunk `json:"chunks"`
+}
+
+// Manifest lists the files which make up an archive with their checksums.
+type Manifest struct {
+	Version   int    `json:"version"`
+	ChunkSize int64  `json:"chunk-size"`
+	Files     []File `json:"files"`
+}
+
+// ChecksumWriter computes the file and chunk checksums of everything written to it.
+// It can be used to build a manifest entry while an export is being written.
+type ChecksumWriter struct {
+	chunkSize int64
+
+	file      hash.Hash
+	chunk     hash.Hash
+	chunkUsed int64
+	size      int64
+	chunks    []Chunk
+}
+
+// MakeChecksumWriter creates a ChecksumWriter. A non-positive chunk size selects
+// DefaultChunkSize.
+func MakeChecksumWriter(chunkSize int64) *ChecksumWriter {
+	if chunkSize <= 0 {
+		chunkSize = DefaultChunkSize
+	}
+	return &ChecksumWriter{
+		chunkSize: chunkSize,
+		file:      sha256.New(),
+		chunk:     sha256.New(),
+	}
+}
+
+// Write is part of io.Writer.
+func (w *ChecksumWriter) Write(p []byte) (int, error) {
+	written := 0
+	for len(p) > 0 {
+		n := int64(len(p))
+		if remaining := w.chunkSize - w.chunkUsed; n > remaining {
+			n = remaining
+		}
+		w.file.Write(p[:n])
+		w.chunk.Write(p[:n])
+		w.chunkUsed += n
+		w.size += n
+		written += int(n)
+		p = p[n:]
+
+		if w.chunkUsed == w.chunkSize {
+			w.finishChunk()
+		}
+	}
+	return written, nil
+}
+
+func (w *ChecksumWriter) finishChunk() {
+	if w.chunkUsed == 0 {
+		return
+	}
+	w.chunks = append(w.chunks, Chunk{
+		Offset: w.size - w.chunkUsed,
+		Size:   w.chunkUsed,
+		SHA256: hex.EncodeToString(w.chunk.Sum(nil)),
+	})
+	w.chunk.Reset()
+	w.chunkUsed = 0
+}
+
+// File returns the manifest entry for everything written so far.
+func (w *ChecksumWriter) File(name string) File {
+	w.finishChunk()
+	chunks := make([]Chunk, len(w.chunks))
+	copy(chunks, w.chunks)
+	return File{
+		Name:   filepath.ToSlash(name),
+		Size:   w.size,
+		SHA256: hex.EncodeToString(w.file.Sum(nil)),
+		Chunks: chunks,
+	}
+}
+
+func checksumFile(path string, name string, chunkSize int64) (File, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return File{}, fmt.Errorf("checksumFile() err: %w", err)
+	}
+	defer f.Close()
+
+	w := MakeChecksumWriter(chunkSize)
+	_, err = io.Copy(w, f)
+	if err != nil {
+		return File{}, fmt.Errorf("checksumFile() read %s err: %w", path, err)
+	}
+	return w.File(name), nil
+}
+
+// ManifestPath returns where the manifest of the archive at `path` is stored.
+// A directory keeps its manifest inside, a single file keeps it next to itself.
+func ManifestPath(path string) (string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return "", fmt.Errorf("ManifestPath() err: %w", err)
+	}
+	if info.IsDir() {
+		return filepath.Join(path, DirManifestName), nil
+	}
+	return path + FileManifestSuffix, nil
+}
+
+// BuildManifest computes a manifest for the archive at `path`, which may be a
+// single file or a directory. Existing manifests are not included.
+func BuildManifest(path string, chunkSize int64) (Manifest, error) {
+	if chunkSize <= 0 {
+		chunkSize = DefaultChunkSize
+	}
+	m := Manifest{
+		Version:   ManifestVersion,
+		ChunkSize: chunkSize,
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return Manifest{}, fmt.Errorf("BuildManifest() err: %w", err)
+	}
+	if !info.IsDir() {
+		file, err := checksumFile(path, filepath.Base(path), chunkSize)
+		if err != nil {
+			return Manifest{}, fmt.Errorf("BuildManifest() err: %w", err)
+		}
+		m.Files = []File{file}
+		return m, nil
+	}
+
+	err = filepath.Walk(path, func(p string, fi os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if fi.IsDir() || strings.HasSuffix(p, FileManifestSuffix) {
+			return nil
+		}
+		name, err := filepath.Rel(path, p)
+		if err != nil {
+			return err
+		}
+		if name == DirManifestName {
+			return nil
+		}
+		file, err := checksumFile(p, name, chunkSize)
+		if err != nil {
+			return err
+		}
+		m.Files = append(m.Files, file)
+		return nil
+	})
+	if err != nil {
+		return Manifest{}, fmt.Errorf("BuildManifest() err: %w", err)
+	}
+	sort.Slice(m.Files, func(i, j int) bool { return m.Files[i].Name < m.Files[j].Name })
+
+	return m, nil
+}
+
+// WriteManifest stores the manifest for the archive at `path`.
+func WriteManifest(path string, m Manifest) error {
+	manifestPath, err := ManifestPath(path)
+	if err != nil {
+		return fmt.Errorf("WriteManifest() err: %w", err)
+	}
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return fmt.Errorf("WriteManifest() encode err: %w", err)
+	}
+	err = ioutil.WriteFile(manifestPath, data, 0644)
+	if err != nil {
+		return fmt.Errorf("WriteManifest() err: %w", err)
+	}
+	return nil
+}
+
+// ReadManifest loads the manifest of the archive at `path`. Returns
+// ErrNoManifest if there is none.
+func ReadManifest(path string) (Manifest, error) {
+	manifestPath, err := ManifestPath(path)
+	if err != nil {
+		return Manifest{}, fmt.Errorf("ReadManifest() err: %w", err)
+	}
+	data, err := ioutil.ReadFile(manifestPath)
+	if os.IsNotExist(err) {
+		return Manifest{}, ErrNoManifest
+	}
+	if err != nil {
+		return Manifest{}, fmt.Errorf("ReadManifest() err: %w", err)
+	}
+
+	var m Manifest
+	err = json.Unmarshal(data, &m)
+	if err != nil {
+		return Manifest{}, fmt.Errorf("ReadManifest() decode %s err: %w", manifestPath, err)
+	}
+	if m.Version != ManifestVersion {
+		return Manifest{}, fmt.Errorf(
+			"ReadManifest() unsupported manifest version %d", m.Version)
+	}
+	return m, nil
+}
+
+// Problem describes one integrity failure found by Verify.
+type Problem struct {
+	File string
+	// Chunk is the index of the damaged chunk, or -1 if the problem concerns the
+	// whole file.
+	Chunk   int
+	Message string
+}
+
+func (p Problem) String() string {
+	if p.Chunk < 0 {
+		return fmt.Sprintf("%s: %s", p.File, p.Message)
+	}
+	return fmt.Sprintf("%s: chunk %d: %s", p.File, p.Chunk, p.Message)
+}
+
+// Verify checks the archive at `path` against its manifest. It returns the list
+// of problems found, which is empty if the archive is intact. An error is
+// returned if verification could not be performed at all.
+func Verify(path string) ([]Problem, error) {
+	m, err := ReadManifest(path)
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("Verify() err: %w", err)
+	}
+	base := path
+	if !info.IsDir() {
+		base = filepath.Dir(path)
+	}
+
+	problems := make([]Problem, 0)
+	for _, expected := range m.Files {
+		filePath := filepath.Join(base, filepath.FromSlash(expected.Name))
+		actual, err := checksumFile(filePath, expected.Name, m.ChunkSize)
+		if err != nil {
+			problems = append(problems, Problem{
+				File:    expected.Name,
+				Chunk:   -1,
+				Message: err.Error(),
+			})
+			continue
+		}
+		problems = append(problems, compareFiles(expected, actual)...)
+	}
+
+	return problems, nil
+}
+
+func compareFiles(expected, actual File) []Problem {
+	var problems []Problem
+	if expected.Size != actual.Size {
+		problems = append(problems, Problem{
+			File:  expected.Name,
+			Chunk: -1,
+			Message: fmt.Sprintf(
+				"size is %d but manifest says %d", actual.Size, expected.Size),
+		})
+	}
+	for i, chunk := range expected.Chunks {
+		if i >= len(actual.Chunks) {
+			problems = append(problems, Problem{
+				File:    expected.Name,
+				Chunk:   i,
+				Message: "missing",
+			})
+			continue
+		}
+		if chunk != actual.Chunks[i] {
+			problems = append(problems, Problem{
+				File:    expected.Name,
+				Chunk:   i,
+				Message: "checksum mismatch",
+			})
+		}
+	}
+	if len(problems) == 0 && expected.SHA256 != actual.SHA256 {
+		problems = append(problems, Problem{
+			File:    expected.Name,
+			Chunk:   -1,
+			Message: "checksum mismatch",
+		})
+	}
+	return problems
+}
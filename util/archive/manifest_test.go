@@ -0,0 +1,83 @@
+package archive
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChecksumWriterChunks(t *testing.T) {
+	w := MakeChecksumWriter(4)
+	_, err := w.Write([]byte("0123456789"))
+	require.NoError(t, err)
+
+	file := w.File("x")
+	assert.Equal(t, int64(10), file.Size)
+	require.Len(t, file.Chunks, 3)
+	assert.Equal(t, int64(0), file.Chunks[0].Offset)
+	assert.Equal(t, int64(8), file.Chunks[2].Offset)
+	assert.Equal(t, int64(2), file.Chunks[2].Size)
+}
+
+func TestVerifyFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "archive")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "blocks.tar")
+	require.NoError(t, ioutil.WriteFile(path, []byte("some block data"), 0644))
+
+	m, err := BuildManifest(path, 4)
+	require.NoError(t, err)
+	require.NoError(t, WriteManifest(path, m))
+
+	problems, err := Verify(path)
+	require.NoError(t, err)
+	assert.Empty(t, problems)
+
+	// Corrupt the second chunk.
+	require.NoError(t, ioutil.WriteFile(path, []byte("someXblock data"), 0644))
+	problems, err = Verify(path)
+	require.NoError(t, err)
+	require.Len(t, problems, 1)
+	assert.Equal(t, 1, problems[0].Chunk)
+}
+
+func TestVerifyDirectory(t *testing.T) {
+	dir, err := ioutil.TempDir("", "archive")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	require.NoError(t, os.Mkdir(filepath.Join(dir, "sub"), 0755))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "a"), []byte("aaaa"), 0644))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "sub", "b"), []byte("bbbb"), 0644))
+
+	m, err := BuildManifest(dir, 0)
+	require.NoError(t, err)
+	require.Len(t, m.Files, 2)
+	assert.Equal(t, "sub/b", m.Files[1].Name)
+	require.NoError(t, WriteManifest(dir, m))
+
+	problems, err := Verify(dir)
+	require.NoError(t, err)
+	assert.Empty(t, problems)
+
+	require.NoError(t, os.Remove(filepath.Join(dir, "a")))
+	problems, err = Verify(dir)
+	require.NoError(t, err)
+	require.Len(t, problems, 1)
+	assert.Equal(t, "a", problems[0].File)
+}
+
+func TestVerifyNoManifest(t *testing.T) {
+	dir, err := ioutil.TempDir("", "archive")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	_, err = Verify(dir)
+	assert.Equal(t, ErrNoManifest, err)
+}
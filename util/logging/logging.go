@@ -0,0 +1,67 @@
+// Package logging configures the format of the logs. Every entry has a module
+// field, the package which logged it, and the entries use the same fields for
+// the same things: round, txns, duration in seconds and error.
+package logging
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// Formats of the logs.
+const (
+	FormatJSON = "json"
+	FormatText = "text"
+)
+
+// ModuleField is the field of the package which logged an entry.
+const ModuleField = "module"
+
+const modulePrefix = "github.com/algorand/indexer/"
+
+// module returns the package of `function`, relative to the indexer module, e.g.
+// "idb/postgres" of "github.com/algorand/indexer/idb/postgres.(*IndexerDb).AddBlock".
+func module(function string) string {
+	slash := strings.LastIndex(function, "/")
+	dot := strings.Index(function[slash+1:], ".")
+	if dot < 0 {
+		return function
+	}
+	pkg := function[:slash+1+dot]
+	if pkg == "main" {
+		return "cmd"
+	}
+	return strings.TrimPrefix(pkg, modulePrefix)
+}
+
+// callerModule reports the caller of an entry as its module, without the file.
+func callerModule(frame *runtime.Frame) (string, string) {
+	return module(frame.Function), ""
+}
+
+// Configure sets the format of `logger`, FormatJSON or FormatText, and adds the
+// module field to its entries.
+func Configure(logger *log.Logger, format string) error {
+	fieldMap := log.FieldMap{log.FieldKeyFunc: ModuleField}
+	switch format {
+	case FormatJSON:
+		logger.SetFormatter(&log.JSONFormatter{
+			DisableHTMLEscape: true,
+			FieldMap:          fieldMap,
+			CallerPrettyfier:  callerModule,
+		})
+	case FormatText:
+		logger.SetFormatter(&log.TextFormatter{
+			FullTimestamp:    true,
+			FieldMap:         fieldMap,
+			CallerPrettyfier: callerModule,
+		})
+	default:
+		return fmt.Errorf("Configure() unknown log format %q, use %s or %s", format, FormatJSON, FormatText)
+	}
+	logger.SetReportCaller(true)
+	return nil
+}
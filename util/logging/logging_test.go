@@ -0,0 +1,48 @@
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestModule(t *testing.T) {
+	assert.Equal(t, "idb/postgres", module("github.com/algorand/indexer/idb/postgres.(*IndexerDb).AddBlock"))
+	assert.Equal(t, "fetcher", module("github.com/algorand/indexer/fetcher.(*fetcherImpl).catchupLoop.func1"))
+	assert.Equal(t, "cmd", module("main.glob..func3"))
+	assert.Equal(t, "github.com/other/pkg", module("github.com/other/pkg.Run"))
+}
+
+func TestConfigureJSON(t *testing.T) {
+	var buf bytes.Buffer
+	logger := log.New()
+	logger.SetOutput(&buf)
+	require.NoError(t, Configure(logger, FormatJSON))
+
+	logger.WithFields(log.Fields{"round": 5, "duration": time.Second.Seconds()}).Info("round imported")
+	var entry map[string]interface{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &entry))
+	assert.Equal(t, "round imported", entry["msg"])
+	assert.Equal(t, float64(5), entry["round"])
+	assert.Equal(t, float64(1), entry["duration"])
+	assert.Equal(t, "util/logging", entry[ModuleField])
+	assert.NotContains(t, entry, "file")
+}
+
+func TestConfigureText(t *testing.T) {
+	var buf bytes.Buffer
+	logger := log.New()
+	logger.SetOutput(&buf)
+	require.NoError(t, Configure(logger, FormatText))
+
+	logger.WithField("round", 5).Warn("round imported")
+	assert.Contains(t, buf.String(), "module=util/logging")
+	assert.Contains(t, buf.String(), "round=5")
+
+	assert.Error(t, Configure(logger, "xml"))
+}
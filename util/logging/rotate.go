@@ -0,0 +1,152 @@
+package logging
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// rotatedSuffixLayout is the layout of the time of rotation which is appended
+// to the names of the rotated files, it sorts in the order of rotation.
+const rotatedSuffixLayout = "2006-01-02T15-04-05.000"
+
+// RotateOptions configures a RotatingFile.
+type RotateOptions struct {
+	// MaxSize is the size in bytes at which the file is rotated, 0 for no limit.
+	MaxSize int64
+
+	// Interval is the time after which the file is rotated, 0 for never.
+	Interval time.Duration
+
+	// MaxFiles is the number of rotated files which are kept, the oldest ones are
+	// deleted. 0 keeps all of them.
+	MaxFiles int
+}
+
+// RotatingFile is a log file which is renamed to <path>.<time of rotation> when
+// it reaches the maximum size or age, and replaced by a new file.
+type RotatingFile struct {
+	path string
+	opts RotateOptions
+
+	mu     sync.Mutex
+	file   *os.File
+	size   int64
+	opened time.Time
+}
+
+// OpenRotatingFile opens the log file `path` for appending.
+func OpenRotatingFile(path string, opts RotateOptions) (*RotatingFile, error) {
+	f := &RotatingFile{path: path, opts: opts}
+	err := f.open()
+	if err != nil {
+		return nil, fmt.Errorf("OpenRotatingFile() err: %w", err)
+	}
+	return f, nil
+}
+
+func (f *RotatingFile) open() error {
+	file, err := os.OpenFile(f.path, os.O_APPEND|os.O_WRONLY|os.O_CREATE, 0644)
+	if err != nil {
+		return fmt.Errorf("open() err: %w", err)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return fmt.Errorf("open() err: %w", err)
+	}
+	f.file = file
+	f.size = info.Size()
+	f.opened = time.Now()
+	return nil
+}
+
+// Write is part of io.Writer. An entry is not split across files.
+func (f *RotatingFile) Write(p []byte) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.file == nil {
+		return 0, os.ErrClosed
+	}
+	if f.size > 0 && ((f.opts.MaxSize > 0 && f.size+int64(len(p)) > f.opts.MaxSize) ||
+		(f.opts.Interval > 0 && time.Since(f.opened) >= f.opts.Interval)) {
+		err := f.rotate()
+		if err != nil {
+			return 0, fmt.Errorf("Write() err: %w", err)
+		}
+	}
+	n, err := f.file.Write(p)
+	f.size += int64(n)
+	return n, err
+}
+
+// rotate renames the current file, opens a new one and deletes the rotated files
+// beyond MaxFiles.
+func (f *RotatingFile) rotate() error {
+	err := f.file.Close()
+	f.file = nil
+	if err != nil {
+		return fmt.Errorf("rotate() err: %w", err)
+	}
+	// The names stay in the order of rotation when a file is rotated more than
+	// once in a millisecond.
+	t := time.Now()
+	rotated := f.path + "." + t.Format(rotatedSuffixLayout)
+	for _, err := os.Stat(rotated); err == nil; _, err = os.Stat(rotated) {
+		t = t.Add(time.Millisecond)
+		rotated = f.path + "." + t.Format(rotatedSuffixLayout)
+	}
+	err = os.Rename(f.path, rotated)
+	if err != nil {
+		return fmt.Errorf("rotate() err: %w", err)
+	}
+	err = f.open()
+	if err != nil {
+		return fmt.Errorf("rotate() err: %w", err)
+	}
+	if f.opts.MaxFiles > 0 {
+		return f.prune()
+	}
+	return nil
+}
+
+// prune deletes the oldest rotated files beyond MaxFiles.
+func (f *RotatingFile) prune() error {
+	rotated, err := filepath.Glob(f.path + ".*")
+	if err != nil {
+		return fmt.Errorf("prune() err: %w", err)
+	}
+	// Other files which start with the name of the log file are not touched.
+	files := rotated[:0]
+	for _, name := range rotated {
+		suffix := name[len(f.path)+1:]
+		if _, err := time.Parse(rotatedSuffixLayout, suffix); err == nil {
+			files = append(files, name)
+		}
+	}
+	sort.Strings(files)
+	for len(files) > f.opts.MaxFiles {
+		err = os.Remove(files[0])
+		if err != nil {
+			return fmt.Errorf("prune() err: %w", err)
+		}
+		files = files[1:]
+	}
+	return nil
+}
+
+// Close is part of io.Closer.
+func (f *RotatingFile) Close() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.file == nil {
+		return nil
+	}
+	err := f.file.Close()
+	f.file = nil
+	return err
+}
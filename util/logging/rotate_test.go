@@ -0,0 +1,73 @@
+package logging
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRotatingFileSize(t *testing.T) {
+	dir, err := ioutil.TempDir("", "rotate")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "indexer.log")
+	// Not a rotated file, it is kept.
+	require.NoError(t, ioutil.WriteFile(path+".old", []byte("old"), 0644))
+
+	f, err := OpenRotatingFile(path, RotateOptions{MaxSize: 10, MaxFiles: 2})
+	require.NoError(t, err)
+	for _, entry := range []string{"first\n", "second\n", "third\n", "fourth\n"} {
+		_, err := f.Write([]byte(entry))
+		require.NoError(t, err)
+	}
+	require.NoError(t, f.Close())
+
+	data, err := ioutil.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "fourth\n", string(data))
+
+	rotated, err := filepath.Glob(path + ".*")
+	require.NoError(t, err)
+	require.Len(t, rotated, 3)
+	assert.Equal(t, path+".old", rotated[2])
+	data, err = ioutil.ReadFile(rotated[0])
+	require.NoError(t, err)
+	assert.Equal(t, "second\n", string(data))
+	data, err = ioutil.ReadFile(rotated[1])
+	require.NoError(t, err)
+	assert.Equal(t, "third\n", string(data))
+}
+
+func TestRotatingFileInterval(t *testing.T) {
+	dir, err := ioutil.TempDir("", "rotate")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "indexer.log")
+
+	f, err := OpenRotatingFile(path, RotateOptions{Interval: time.Hour})
+	require.NoError(t, err)
+	defer f.Close()
+	_, err = f.Write([]byte("first\n"))
+	require.NoError(t, err)
+	_, err = f.Write([]byte("second\n"))
+	require.NoError(t, err)
+
+	f.opened = f.opened.Add(-time.Hour)
+	_, err = f.Write([]byte("third\n"))
+	require.NoError(t, err)
+
+	data, err := ioutil.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "third\n", string(data))
+	rotated, err := filepath.Glob(path + ".2*")
+	require.NoError(t, err)
+	require.Len(t, rotated, 1)
+	data, err = ioutil.ReadFile(rotated[0])
+	require.NoError(t, err)
+	assert.Equal(t, "first\nsecond\n", string(data))
+}
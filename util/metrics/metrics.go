@@ -1,15 +1,23 @@
 package metrics
 
-import "github.com/prometheus/client_golang/prometheus"
+import (
+	"github.com/prometheus/client_golang/prometheus"
 
-// RegisterPrometheusMetrics register all prometheus metrics with the global
-// metrics handler.
+	"github.com/algorand/indexer/version"
+)
+
+// RegisterPrometheusMetrics registers the import metrics with DefaultRegistry.
+// Other subsystems create their metrics with DefaultRegistry directly.
 func RegisterPrometheusMetrics() {
-	prometheus.Register(BlockImportTimeSeconds)
-	prometheus.Register(ImportedTxnsPerBlock)
-	prometheus.Register(ImportedRoundGauge)
-	prometheus.Register(BlockUploadTimeSeconds)
-	prometheus.Register(PostgresEvalTimeSeconds)
+	DefaultRegistry.Register(BlockImportTimeSeconds)
+	DefaultRegistry.Register(ImportedTxnsPerBlock)
+	DefaultRegistry.Register(ImportedRoundGauge)
+	DefaultRegistry.Register(BlockUploadTimeSeconds)
+	DefaultRegistry.Register(PostgresEvalTimeSeconds)
+
+	info := version.Info()
+	DefaultRegistry.Register(BuildInfo)
+	BuildInfo.WithLabelValues(info.Version, info.Commit, info.GoVersion).Set(1)
 }
 
 // Prometheus metric names broken out for reuse.
@@ -19,6 +27,8 @@ const (
 	ImportedTxnsPerBlockName = "imported_tx_per_block"
 	ImportedRoundGaugeName   = "imported_round"
 	PostgresEvalName         = "postgres_eval_time_sec"
+	ImportLagName            = "import_lag_rounds"
+	BuildInfoName            = "build_info"
 )
 
 // AllMetricNames is a reference for all the custom metric names.
@@ -28,6 +38,7 @@ var AllMetricNames = []string{
 	ImportedTxnsPerBlockName,
 	ImportedRoundGaugeName,
 	PostgresEvalName,
+	ImportLagName,
 }
 
 // Initialize the prometheus objects.
@@ -66,4 +77,35 @@ var (
 			Name:      PostgresEvalName,
 			Help:      "Time spent calling Eval function in seconds.",
 		})
+
+	BuildInfo = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Subsystem: "indexer_daemon",
+			Name:      BuildInfoName,
+			Help:      "Always 1, labeled with the version, git commit and go version of the binary.",
+		}, []string{"version", "commit", "go_version"})
 )
+
+// RegisterImportLag registers a gauge of the number of rounds by which the
+// import lags the latest round of algod. It is computed when the metrics are
+// scraped, so it keeps growing while the import is stuck.
+func RegisterImportLag(latestRound func() (uint64, bool), importedRound func() uint64) error {
+	return DefaultRegistry.Register(makeImportLag(latestRound, importedRound))
+}
+
+func makeImportLag(latestRound func() (uint64, bool), importedRound func() uint64) prometheus.GaugeFunc {
+	return prometheus.NewGaugeFunc(
+		prometheus.GaugeOpts{
+			Subsystem: "indexer_daemon",
+			Name:      ImportLagName,
+			Help:      "Rounds between the latest round of algod and the latest imported round.",
+		},
+		func() float64 {
+			latest, ok := latestRound()
+			imported := importedRound()
+			if !ok || imported >= latest {
+				return 0
+			}
+			return float64(latest - imported)
+		})
+}
@@ -0,0 +1,28 @@
+package metrics
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestImportLag(t *testing.T) {
+	latest := uint64(0)
+	known := false
+	imported := uint64(0)
+	gauge := makeImportLag(
+		func() (uint64, bool) { return latest, known },
+		func() uint64 { return imported })
+
+	// There is no lag until algod reported a round.
+	assert.Equal(t, 0.0, testutil.ToFloat64(gauge))
+
+	latest, known = 10, true
+	imported = 4
+	assert.Equal(t, 6.0, testutil.ToFloat64(gauge))
+
+	// The import may be ahead of the round last reported by algod.
+	imported = 11
+	assert.Equal(t, 0.0, testutil.ToFloat64(gauge))
+}
@@ -0,0 +1,106 @@
+package metrics
+
+import (
+	"errors"
+	"sort"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Registry creates labeled metric families in a subsystem and registers them,
+// and the collectors of other subsystems, with a prometheus registerer. Metrics
+// and collectors can be added at any time, e.g. when a feature is enabled.
+type Registry struct {
+	registerer prometheus.Registerer
+	subsystem  string
+
+	mu    sync.Mutex
+	names map[string]struct{}
+}
+
+// DefaultRegistry registers with the global prometheus registerer, which is
+// served on /metrics.
+var DefaultRegistry = NewRegistry(prometheus.DefaultRegisterer, "indexer_daemon")
+
+// NewRegistry creates a registry for the metrics of `subsystem`.
+func NewRegistry(registerer prometheus.Registerer, subsystem string) *Registry {
+	return &Registry{
+		registerer: registerer,
+		subsystem:  subsystem,
+		names:      make(map[string]struct{}),
+	}
+}
+
+// register registers a metric family. When an identical family is already
+// registered, the existing one is returned so that a subsystem which is started
+// more than once keeps updating the same metric.
+func (r *Registry) register(name string, c prometheus.Collector) prometheus.Collector {
+	err := r.registerer.Register(c)
+	var are prometheus.AlreadyRegisteredError
+	if errors.As(err, &are) {
+		return are.ExistingCollector
+	}
+	if err != nil {
+		panic(err)
+	}
+
+	r.mu.Lock()
+	r.names[name] = struct{}{}
+	r.mu.Unlock()
+	return c
+}
+
+// NewCounterVec creates and registers a counter family with the given labels.
+// It panics if a different metric with the same name exists.
+func (r *Registry) NewCounterVec(name, help string, labels ...string) *prometheus.CounterVec {
+	opts := prometheus.CounterOpts{Subsystem: r.subsystem, Name: name, Help: help}
+	return r.register(name, prometheus.NewCounterVec(opts, labels)).(*prometheus.CounterVec)
+}
+
+// NewGaugeVec creates and registers a gauge family with the given labels. It
+// panics if a different metric with the same name exists.
+func (r *Registry) NewGaugeVec(name, help string, labels ...string) *prometheus.GaugeVec {
+	opts := prometheus.GaugeOpts{Subsystem: r.subsystem, Name: name, Help: help}
+	return r.register(name, prometheus.NewGaugeVec(opts, labels)).(*prometheus.GaugeVec)
+}
+
+// NewSummaryVec creates and registers a summary family with the given labels.
+// It panics if a different metric with the same name exists.
+func (r *Registry) NewSummaryVec(name, help string, labels ...string) *prometheus.SummaryVec {
+	opts := prometheus.SummaryOpts{Subsystem: r.subsystem, Name: name, Help: help}
+	return r.register(name, prometheus.NewSummaryVec(opts, labels)).(*prometheus.SummaryVec)
+}
+
+// NewHistogramVec creates and registers a histogram family with the given
+// labels, nil buckets use the prometheus default buckets. It panics if a
+// different metric with the same name exists.
+func (r *Registry) NewHistogramVec(name, help string, buckets []float64, labels ...string) *prometheus.HistogramVec {
+	opts := prometheus.HistogramOpts{Subsystem: r.subsystem, Name: name, Help: help, Buckets: buckets}
+	return r.register(name, prometheus.NewHistogramVec(opts, labels)).(*prometheus.HistogramVec)
+}
+
+// Register registers a collector of a subsystem, for example one which reads
+// its values when metrics are scraped.
+func (r *Registry) Register(c prometheus.Collector) error {
+	return r.registerer.Register(c)
+}
+
+// Unregister removes a collector registered with Register(), e.g. when its
+// subsystem stops.
+func (r *Registry) Unregister(c prometheus.Collector) bool {
+	return r.registerer.Unregister(c)
+}
+
+// Names returns the names of the metric families created by the registry,
+// without the subsystem prefix.
+func (r *Registry) Names() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	names := make([]string, 0, len(r.names))
+	for name := range r.names {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,61 @@
+package metrics
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegistryLabeledMetrics(t *testing.T) {
+	promRegistry := prometheus.NewRegistry()
+	registry := NewRegistry(promRegistry, "test")
+
+	counter := registry.NewCounterVec("requests_total", "Requests.", "endpoint")
+	counter.WithLabelValues("accounts").Inc()
+	counter.WithLabelValues("accounts").Inc()
+	counter.WithLabelValues("assets").Inc()
+	assert.Equal(t, 2.0, testutil.ToFloat64(counter.WithLabelValues("accounts")))
+
+	// Creating the same family again returns the registered one.
+	again := registry.NewCounterVec("requests_total", "Requests.", "endpoint")
+	assert.Equal(t, 2.0, testutil.ToFloat64(again.WithLabelValues("accounts")))
+
+	registry.NewGaugeVec("queue_length", "Queue length.", "queue")
+	assert.Equal(t, []string{"queue_length", "requests_total"}, registry.Names())
+
+	families, err := promRegistry.Gather()
+	require.NoError(t, err)
+	require.Len(t, families, 1, "the gauge has no labeled values yet")
+	assert.Equal(t, "test_requests_total", families[0].GetName())
+
+	// A different metric with the same name is a programming error.
+	assert.Panics(t, func() {
+		registry.NewGaugeVec("requests_total", "Other requests.", "endpoint")
+	})
+}
+
+func TestRegistryCollectors(t *testing.T) {
+	promRegistry := prometheus.NewRegistry()
+	registry := NewRegistry(promRegistry, "test")
+
+	value := 0.0
+	collector := prometheus.NewGaugeFunc(
+		prometheus.GaugeOpts{Name: "cache_entries", Help: "Cache entries."},
+		func() float64 { return value })
+	require.NoError(t, registry.Register(collector))
+	assert.Error(t, registry.Register(collector))
+
+	value = 3
+	families, err := promRegistry.Gather()
+	require.NoError(t, err)
+	require.Len(t, families, 1)
+	assert.Equal(t, 3.0, families[0].GetMetric()[0].GetGauge().GetValue())
+
+	assert.True(t, registry.Unregister(collector))
+	families, err = promRegistry.Gather()
+	require.NoError(t, err)
+	assert.Len(t, families, 0)
+}
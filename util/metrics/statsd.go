@@ -0,0 +1,226 @@
+package metrics
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	log "github.com/sirupsen/logrus"
+)
+
+// Metrics sinks.
+const (
+	// SinkPrometheus only serves the metrics on /metrics.
+	SinkPrometheus = "prometheus"
+	// SinkStatsd also pushes them to a StatsD server, the labels are appended
+	// to the names.
+	SinkStatsd = "statsd"
+	// SinkDogStatsd also pushes them to a DogStatsD server, e.g. the Datadog
+	// agent, with the labels as tags.
+	SinkDogStatsd = "dogstatsd"
+)
+
+// maxPacketSize is the size of the UDP packets, which must not be fragmented.
+const maxPacketSize = 1432
+
+// StatsdOptions configures a StatsdSink.
+type StatsdOptions struct {
+	// Address is the host:port of the StatsD server.
+	Address string
+
+	// DogStatsd sends the labels as DogStatsD tags.
+	DogStatsd bool
+
+	// Interval is the time between two pushes.
+	Interval time.Duration
+}
+
+// StatsdSink pushes the metrics of a prometheus gatherer to a StatsD server.
+// Gauges are sent as gauges and counters as counters of the increase since the
+// previous push. Histograms and summaries are sent as the counters <name>.count
+// and <name>.sum, summaries also as the gauges <name>.<quantile>.
+type StatsdSink struct {
+	opts     StatsdOptions
+	gatherer prometheus.Gatherer
+	conn     net.Conn
+	log      *log.Logger
+
+	// counters are the values of the counters at the previous push, by line
+	// without the value.
+	counters map[string]float64
+}
+
+// MakeStatsdSink creates a StatsdSink of `gatherer`, use Run to push.
+func MakeStatsdSink(opts StatsdOptions, gatherer prometheus.Gatherer, log *log.Logger) (*StatsdSink, error) {
+	if opts.Interval <= 0 {
+		return nil, fmt.Errorf("MakeStatsdSink() interval %s is not positive", opts.Interval)
+	}
+	conn, err := net.Dial("udp", opts.Address)
+	if err != nil {
+		return nil, fmt.Errorf("MakeStatsdSink() err: %w", err)
+	}
+	return &StatsdSink{
+		opts:     opts,
+		gatherer: gatherer,
+		conn:     conn,
+		log:      log,
+		counters: make(map[string]float64),
+	}, nil
+}
+
+// Run pushes the metrics every interval until `ctx` is done, and once more
+// before it returns.
+func (s *StatsdSink) Run(ctx context.Context) {
+	defer s.conn.Close()
+	ticker := time.NewTicker(s.opts.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			s.pushLogged()
+			return
+		case <-ticker.C:
+			s.pushLogged()
+		}
+	}
+}
+
+// pushLogged pushes the metrics, a failure is logged.
+func (s *StatsdSink) pushLogged() {
+	err := s.push()
+	if err != nil {
+		s.log.WithError(err).Warn("pushing the metrics to statsd failed")
+	}
+}
+
+func (s *StatsdSink) push() error {
+	families, err := s.gatherer.Gather()
+	if err != nil {
+		return fmt.Errorf("push() err: %w", err)
+	}
+	var packet []byte
+	for _, line := range s.lines(families) {
+		if len(packet)+len(line)+1 > maxPacketSize && len(packet) > 0 {
+			if _, err := s.conn.Write(packet); err != nil {
+				return fmt.Errorf("push() err: %w", err)
+			}
+			packet = packet[:0]
+		}
+		if len(packet) > 0 {
+			packet = append(packet, '\n')
+		}
+		packet = append(packet, line...)
+	}
+	if len(packet) > 0 {
+		if _, err := s.conn.Write(packet); err != nil {
+			return fmt.Errorf("push() err: %w", err)
+		}
+	}
+	return nil
+}
+
+// lines returns the StatsD lines of `families`.
+func (s *StatsdSink) lines(families []*dto.MetricFamily) []string {
+	var res []string
+	for _, family := range families {
+		for _, m := range family.GetMetric() {
+			name, tags := s.nameAndTags(family.GetName(), m.GetLabel())
+			switch family.GetType() {
+			case dto.MetricType_COUNTER:
+				res = s.appendCounter(res, name, tags, m.GetCounter().GetValue())
+			case dto.MetricType_GAUGE:
+				res = appendLine(res, name, tags, m.GetGauge().GetValue(), "g")
+			case dto.MetricType_UNTYPED:
+				res = appendLine(res, name, tags, m.GetUntyped().GetValue(), "g")
+			case dto.MetricType_SUMMARY:
+				summary := m.GetSummary()
+				res = s.appendCounter(res, name+".count", tags, float64(summary.GetSampleCount()))
+				res = s.appendCounter(res, name+".sum", tags, summary.GetSampleSum())
+				for _, q := range summary.GetQuantile() {
+					quantile := "p" + strings.Replace(strconv.FormatFloat(q.GetQuantile()*100, 'f', -1, 64), ".", "_", 1)
+					res = appendLine(res, name+"."+quantile, tags, q.GetValue(), "g")
+				}
+			case dto.MetricType_HISTOGRAM:
+				histogram := m.GetHistogram()
+				res = s.appendCounter(res, name+".count", tags, float64(histogram.GetSampleCount()))
+				res = s.appendCounter(res, name+".sum", tags, histogram.GetSampleSum())
+			}
+		}
+	}
+	return res
+}
+
+// nameAndTags returns the name of a metric with `labels`, which has the label
+// values appended for StatsD, and its DogStatsD tags.
+func (s *StatsdSink) nameAndTags(name string, labels []*dto.LabelPair) (string, string) {
+	if len(labels) == 0 {
+		return name, ""
+	}
+	if !s.opts.DogStatsd {
+		for _, label := range labels {
+			name += "." + sanitizeStatsdName(label.GetValue())
+		}
+		return name, ""
+	}
+	tags := make([]string, len(labels))
+	for i, label := range labels {
+		tags[i] = label.GetName() + ":" + sanitizeStatsdTag(label.GetValue())
+	}
+	sort.Strings(tags)
+	return name, "|#" + strings.Join(tags, ",")
+}
+
+// appendCounter appends the increase of a counter since the previous push. A
+// counter which went down was reset, e.g. a collector was registered again.
+func (s *StatsdSink) appendCounter(lines []string, name string, tags string, value float64) []string {
+	key := name + tags
+	previous, ok := s.counters[key]
+	s.counters[key] = value
+	delta := value - previous
+	if ok && delta < 0 {
+		delta = value
+	}
+	if delta == 0 {
+		return lines
+	}
+	return appendLine(lines, name, tags, delta, "c")
+}
+
+// appendLine appends a line, unless `value` is NaN, e.g. a quantile of a summary
+// without observations.
+func appendLine(lines []string, name string, tags string, value float64, kind string) []string {
+	if math.IsNaN(value) {
+		return lines
+	}
+	return append(lines, name+":"+strconv.FormatFloat(value, 'f', -1, 64)+"|"+kind+tags)
+}
+
+// sanitizeStatsdName replaces the characters of a label value which can't be
+// part of a metric name, e.g. the dots and slashes of a path.
+func sanitizeStatsdName(value string) string {
+	return strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '_' || r == '-' {
+			return r
+		}
+		return '_'
+	}, value)
+}
+
+// sanitizeStatsdTag replaces the characters of a label value which separate the
+// tags and the fields of a DogStatsD line.
+func sanitizeStatsdTag(value string) string {
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case '|', '#', ',', ' ', '\n':
+			return '_'
+		}
+		return r
+	}, value)
+}
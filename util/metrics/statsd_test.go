@@ -0,0 +1,102 @@
+package metrics
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// statsdLines pushes the metrics of `sink` to the test server and returns the
+// lines it received, sorted.
+func statsdLines(t *testing.T, sink *StatsdSink, server net.PacketConn) []string {
+	require.NoError(t, sink.push())
+	var lines []string
+	buf := make([]byte, maxPacketSize)
+	for {
+		server.SetReadDeadline(time.Now().Add(100 * time.Millisecond))
+		n, _, err := server.ReadFrom(buf)
+		if err != nil {
+			break
+		}
+		assert.LessOrEqual(t, n, maxPacketSize)
+		lines = append(lines, strings.Split(string(buf[:n]), "\n")...)
+	}
+	sort.Strings(lines)
+	return lines
+}
+
+func makeTestSink(t *testing.T, dogStatsd bool) (*prometheus.Registry, *StatsdSink, net.PacketConn) {
+	server, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	promRegistry := prometheus.NewRegistry()
+	sink, err := MakeStatsdSink(StatsdOptions{
+		Address:   server.LocalAddr().String(),
+		DogStatsd: dogStatsd,
+		Interval:  time.Second,
+	}, promRegistry, log.New())
+	require.NoError(t, err)
+	return promRegistry, sink, server
+}
+
+func TestStatsdSink(t *testing.T) {
+	promRegistry, sink, server := makeTestSink(t, false)
+	defer server.Close()
+	registry := NewRegistry(promRegistry, "test")
+	counter := registry.NewCounterVec("requests_total", "Requests.", "endpoint")
+	gauge := registry.NewGaugeVec("round", "Round.")
+	histogram := registry.NewHistogramVec("duration_seconds", "Duration.", nil)
+
+	counter.WithLabelValues("/v2/accounts").Add(3)
+	gauge.WithLabelValues().Set(42)
+	histogram.WithLabelValues().Observe(0.5)
+	assert.Equal(t, []string{
+		"test_duration_seconds.count:1|c",
+		"test_duration_seconds.sum:0.5|c",
+		"test_requests_total._v2_accounts:3|c",
+		"test_round:42|g",
+	}, statsdLines(t, sink, server))
+
+	// Counters send the increase, unchanged counters are not sent.
+	counter.WithLabelValues("/v2/accounts").Add(2)
+	assert.Equal(t, []string{
+		"test_requests_total._v2_accounts:2|c",
+		"test_round:42|g",
+	}, statsdLines(t, sink, server))
+}
+
+func TestDogStatsdSink(t *testing.T) {
+	promRegistry, sink, server := makeTestSink(t, true)
+	defer server.Close()
+	registry := NewRegistry(promRegistry, "test")
+	gauge := registry.NewGaugeVec("pool_conns", "Conns.", "pool", "state")
+	gauge.WithLabelValues("primary", "idle, open").Set(3)
+
+	assert.Equal(t, []string{
+		"test_pool_conns:3|g|#pool:primary,state:idle__open",
+	}, statsdLines(t, sink, server))
+}
+
+func TestStatsdSinkPackets(t *testing.T) {
+	promRegistry, sink, server := makeTestSink(t, false)
+	defer server.Close()
+	registry := NewRegistry(promRegistry, "test")
+	gauge := registry.NewGaugeVec("queue_length", "Queue length.", "queue")
+	for i := 0; i < 200; i++ {
+		gauge.WithLabelValues(fmt.Sprintf("queue_with_a_long_name_%d", i)).Set(1)
+	}
+	assert.Len(t, statsdLines(t, sink, server), 200)
+
+	// The last push when the context is done.
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	sink.Run(ctx)
+}
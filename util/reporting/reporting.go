@@ -0,0 +1,246 @@
+// Package reporting sends errors and panics to a Sentry compatible service,
+// e.g. Sentry or GlitchTip, with the Sentry SDK. A Reporter is a logrus hook,
+// so the entries logged at the error level are reported with their fields,
+// e.g. round, migration or query, as tags. Errors which are not logged are
+// reported with Report, and panics with ReportPanic.
+package reporting
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/getsentry/sentry-go"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// queueSize is the number of events which wait to be sent, events are dropped
+	// when the service falls behind.
+	queueSize = 100
+	// repeatInterval is the time during which an event with the same type and
+	// message as a sent one is dropped, so that a failing database does not send
+	// an event per request.
+	repeatInterval = time.Minute
+	// defaultTimeout is the default of Options.Timeout.
+	defaultTimeout = 10 * time.Second
+)
+
+// Tags are the context of an error, e.g. the round which failed to import.
+type Tags map[string]string
+
+// Options configures a Reporter.
+type Options struct {
+	// DSN is the Sentry DSN of the project, e.g.
+	// https://<key>@sentry.example.com/<project id>.
+	DSN string
+
+	// Environment is the environment of the events, e.g. mainnet or testnet.
+	Environment string
+
+	// Release is the version of the indexer.
+	Release string
+
+	// ServerName identifies the host of the indexer.
+	ServerName string
+
+	// Timeout is the timeout of a request, defaults to defaultTimeout.
+	Timeout time.Duration
+}
+
+// Reporter is a Sentry client which sends the events in the background.
+type Reporter struct {
+	client  *sentry.Client
+	timeout time.Duration
+	log     *log.Logger
+
+	// sent is when the events which were sent in the last repeatInterval were
+	// sent, by repeatKey.
+	sent   map[string]time.Time
+	sentMu sync.Mutex
+}
+
+// MakeReporter creates a Reporter and starts sending. Use SetReporter to report
+// errors with it, logger.AddHook to report the error logs and Close to send the
+// remaining events. `log` receives a warning when the remaining events could
+// not be sent in time, it may be the logger the Reporter is a hook of.
+func MakeReporter(opts Options, log *log.Logger) (*Reporter, error) {
+	if opts.DSN == "" {
+		return nil, fmt.Errorf("MakeReporter() missing DSN")
+	}
+	if opts.Timeout == 0 {
+		opts.Timeout = defaultTimeout
+	}
+	r := &Reporter{
+		timeout: opts.Timeout,
+		log:     log,
+		sent:    make(map[string]time.Time),
+	}
+
+	transport := sentry.NewHTTPTransport()
+	transport.BufferSize = queueSize
+	transport.Timeout = opts.Timeout
+	client, err := sentry.NewClient(sentry.ClientOptions{
+		Dsn:              opts.DSN,
+		Environment:      opts.Environment,
+		Release:          opts.Release,
+		ServerName:       opts.ServerName,
+		AttachStacktrace: true,
+		BeforeSend:       r.beforeSend,
+		Transport:        transport,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("MakeReporter() err: %w", err)
+	}
+	r.client = client
+	return r, nil
+}
+
+// Report sends `err` with `tags`. It does nothing on a nil Reporter.
+func (r *Reporter) Report(err error, tags Tags) {
+	if r == nil || err == nil {
+		return
+	}
+	scope := sentry.NewScope()
+	scope.SetTags(tags)
+	r.client.CaptureException(err, nil, scope)
+}
+
+// Levels is part of log.Hook, the entries logged at the error level and above
+// are reported.
+func (r *Reporter) Levels() []log.Level {
+	return []log.Level{log.PanicLevel, log.FatalLevel, log.ErrorLevel}
+}
+
+// Fire is part of log.Hook. The logger is locked, so it only queues the event.
+func (r *Reporter) Fire(entry *log.Entry) error {
+	scope := sentry.NewScope()
+	scope.SetLevel(sentry.LevelError)
+	if entry.Level < log.ErrorLevel {
+		scope.SetLevel(sentry.LevelFatal)
+	}
+	if entry.Caller != nil {
+		scope.SetTransaction(entry.Caller.Function)
+	}
+	var err error
+	for key, value := range entry.Data {
+		if e, ok := value.(error); ok && key == log.ErrorKey {
+			err = e
+			continue
+		}
+		scope.SetTag(key, fmt.Sprint(value))
+	}
+	scope.AddEventProcessor(func(event *sentry.Event, hint *sentry.EventHint) *sentry.Event {
+		event.Logger = "algorand-indexer"
+		event.Message = entry.Message
+		for i := range event.Exception {
+			trimLogrus(event.Exception[i].Stacktrace)
+		}
+		for i := range event.Threads {
+			trimLogrus(event.Threads[i].Stacktrace)
+		}
+		return event
+	})
+
+	if err != nil {
+		r.client.CaptureException(err, nil, scope)
+	} else {
+		r.client.CaptureMessage(entry.Message, nil, scope)
+	}
+	return nil
+}
+
+// trimLogrus removes the frames of logrus from `st`.
+func trimLogrus(st *sentry.Stacktrace) {
+	if st == nil {
+		return
+	}
+	frames := st.Frames[:0]
+	for _, f := range st.Frames {
+		if !strings.HasPrefix(f.Module, "github.com/sirupsen/logrus") {
+			frames = append(frames, f)
+		}
+	}
+	st.Frames = frames
+}
+
+// repeatKey identifies the events which repeat, by their message and errors.
+func repeatKey(event *sentry.Event) string {
+	var b strings.Builder
+	b.WriteString(event.Message)
+	for _, e := range event.Exception {
+		b.WriteString("\n" + e.Type + ": " + e.Value)
+	}
+	return b.String()
+}
+
+// beforeSend drops `event` if an event like it was sent in the last
+// repeatInterval.
+func (r *Reporter) beforeSend(event *sentry.Event, hint *sentry.EventHint) *sentry.Event {
+	r.sentMu.Lock()
+	defer r.sentMu.Unlock()
+	now := time.Now()
+	for key, at := range r.sent {
+		if now.Sub(at) >= repeatInterval {
+			delete(r.sent, key)
+		}
+	}
+	key := repeatKey(event)
+	if _, ok := r.sent[key]; ok {
+		return nil
+	}
+	r.sent[key] = now
+	return event
+}
+
+// Close sends the queued events. It waits at most `timeout`.
+func (r *Reporter) Close(timeout time.Duration) {
+	if r == nil {
+		return
+	}
+	if !r.client.Flush(timeout) {
+		r.log.Warnf("sending the error reports timed out after %s", timeout)
+	}
+}
+
+// The global reporter, like the tracer, so that the packages report without
+// passing it around.
+var reporter atomic.Value
+
+// SetReporter sets the reporter of Report and ReportPanic, nil stops reporting.
+func SetReporter(r *Reporter) {
+	reporter.Store(r)
+}
+
+func getReporter() *Reporter {
+	r, _ := reporter.Load().(*Reporter)
+	return r
+}
+
+// Report sends `err` with `tags` with the reporter set by SetReporter, if any.
+func Report(err error, tags Tags) {
+	getReporter().Report(err, tags)
+}
+
+// ReportPanic reports a panic of the calling goroutine and panics again, it must
+// be deferred: `defer reporting.ReportPanic()`. The panic may stop the indexer,
+// so it waits for the event to be sent.
+func ReportPanic() {
+	p := recover()
+	if p == nil {
+		return
+	}
+	if r := getReporter(); r != nil {
+		r.client.Recover(p, nil, sentry.NewScope())
+		r.Close(r.timeout)
+	}
+	panic(p)
+}
+
+// Close sends the events queued in the reporter set by SetReporter, before the
+// indexer exits. It waits at most `timeout`.
+func Close(timeout time.Duration) {
+	getReporter().Close(timeout)
+}
@@ -0,0 +1,160 @@
+package reporting
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/getsentry/sentry-go"
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMakeReporter(t *testing.T) {
+	invalid := []string{
+		"",
+		"ftp://public@sentry.example.com/42",
+		"https://sentry.example.com/42",
+		"https://public@sentry.example.com/",
+	}
+	for _, dsn := range invalid {
+		_, err := MakeReporter(Options{DSN: dsn}, log.New())
+		assert.Error(t, err, dsn)
+	}
+}
+
+// makeTestReporter returns a Reporter which sends to a test server, and the
+// events the server received.
+func makeTestReporter(t *testing.T) (*Reporter, chan sentry.Event, func()) {
+	events := make(chan sentry.Event, 10)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/1/store/", r.URL.Path)
+		assert.True(t, strings.HasPrefix(r.Header.Get("X-Sentry-Auth"), "Sentry sentry_version=7"))
+		body, err := ioutil.ReadAll(r.Body)
+		require.NoError(t, err)
+		var e sentry.Event
+		require.NoError(t, json.Unmarshal(body, &e))
+		events <- e
+	}))
+	dsn := strings.Replace(server.URL, "http://", "http://key@", 1) + "/1"
+	r, err := MakeReporter(Options{DSN: dsn, Environment: "test"}, log.New())
+	require.NoError(t, err)
+	return r, events, server.Close
+}
+
+// lastFrame returns the innermost frame of `st`.
+func lastFrame(t *testing.T, st *sentry.Stacktrace) sentry.Frame {
+	require.NotNil(t, st)
+	require.NotEmpty(t, st.Frames)
+	return st.Frames[len(st.Frames)-1]
+}
+
+func TestHook(t *testing.T) {
+	r, events, closeServer := makeTestReporter(t)
+	defer closeServer()
+	logger := log.New()
+	logger.SetOutput(ioutil.Discard)
+	logger.AddHook(r)
+
+	logger.WithField("round", 5).Info("round imported")
+	err := fmt.Errorf("AddBlock() err: %w", errors.New("conflict"))
+	logger.WithError(err).WithField("round", 6).Error("adding block to database failed")
+	r.Close(time.Second)
+
+	require.Len(t, events, 1)
+	e := <-events
+	assert.Equal(t, "adding block to database failed", e.Message)
+	assert.Equal(t, sentry.LevelError, e.Level)
+	assert.Equal(t, "test", e.Environment)
+	assert.Equal(t, map[string]string{"round": "6"}, e.Tags)
+	// The errors are from the innermost to the outermost.
+	require.Len(t, e.Exception, 2)
+	assert.Equal(t, "*errors.errorString", e.Exception[0].Type)
+	assert.Equal(t, "conflict", e.Exception[0].Value)
+	assert.Equal(t, "AddBlock() err: conflict", e.Exception[1].Value)
+	for _, f := range e.Exception[1].Stacktrace.Frames {
+		assert.NotContains(t, f.Module, "logrus")
+	}
+	f := lastFrame(t, e.Exception[1].Stacktrace)
+	assert.Equal(t, "(*Reporter).Fire", f.Function)
+}
+
+func TestHookMessage(t *testing.T) {
+	r, events, closeServer := makeTestReporter(t)
+	defer closeServer()
+	logger := log.New()
+	logger.SetOutput(ioutil.Discard)
+	logger.AddHook(r)
+
+	logger.WithField("migration", 3).Error("migration failed")
+	r.Close(time.Second)
+
+	require.Len(t, events, 1)
+	e := <-events
+	assert.Equal(t, "migration failed", e.Message)
+	assert.Equal(t, sentry.LevelError, e.Level)
+	assert.Equal(t, "3", e.Tags["migration"])
+	assert.Empty(t, e.Exception)
+	require.Len(t, e.Threads, 1)
+}
+
+func TestReportRepeats(t *testing.T) {
+	r, events, closeServer := makeTestReporter(t)
+	defer closeServer()
+
+	for i := 0; i < 3; i++ {
+		r.Report(errors.New("connection refused"), Tags{"query": "accounts"})
+	}
+	r.Report(errors.New("timeout"), Tags{"query": "accounts"})
+	r.Close(time.Second)
+
+	require.Len(t, events, 2)
+	e := <-events
+	assert.Equal(t, "connection refused", e.Exception[0].Value)
+	assert.Equal(t, "accounts", e.Tags["query"])
+	f := lastFrame(t, e.Exception[0].Stacktrace)
+	assert.Equal(t, "github.com/algorand/indexer/util/reporting", f.Module)
+	assert.Equal(t, "(*Reporter).Report", f.Function)
+	e = <-events
+	assert.Equal(t, "timeout", e.Exception[0].Value)
+}
+
+func TestReportPanic(t *testing.T) {
+	r, events, closeServer := makeTestReporter(t)
+	defer closeServer()
+	SetReporter(r)
+	defer SetReporter(nil)
+
+	// The panic continues after it is reported.
+	func() {
+		defer func() {
+			assert.Equal(t, "nil map", recover())
+		}()
+		defer ReportPanic()
+		panic("nil map")
+	}()
+
+	require.Len(t, events, 1)
+	e := <-events
+	assert.Equal(t, sentry.LevelFatal, e.Level)
+	assert.Equal(t, "nil map", e.Message)
+	require.Len(t, e.Threads, 1)
+	assert.NotEmpty(t, e.Threads[0].Stacktrace.Frames)
+}
+
+func TestWithoutReporter(t *testing.T) {
+	SetReporter(nil)
+	Report(errors.New("failed"), nil)
+	Close(time.Second)
+	assert.Panics(t, func() {
+		defer ReportPanic()
+		panic("failed")
+	})
+}
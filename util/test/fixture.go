@@ -0,0 +1,153 @@
+package test
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/algorand/go-algorand/data/basics"
+	"github.com/algorand/go-algorand/data/bookkeeping"
+	"github.com/algorand/go-algorand/data/transactions"
+
+	"github.com/algorand/indexer/api/generated/v2"
+	"github.com/algorand/indexer/idb"
+)
+
+// Block timestamps of the fixture are fixed so that responses do not change
+// between runs.
+const fixtureTime = 1600000000
+
+// fixturePaymentTxn returns the payment of the first fixture round.
+func fixturePaymentTxn() transactions.SignedTxnWithAD {
+	return MakePaymentTxn(
+		1000, 100000, 0, 0, 0, 0, AccountA, AccountB, basics.Address{},
+		basics.Address{})
+}
+
+// ImportFixture imports a few rounds with payments, an asset and an application
+// into an empty database. The fixture is the same on every run, so responses of
+// the API to FixtureRequests() can be compared between runs and implementations.
+func ImportFixture(db idb.IndexerDb) error {
+	err := db.LoadGenesis(MakeGenesis())
+	if err != nil {
+		return fmt.Errorf("ImportFixture() load genesis err: %w", err)
+	}
+	genesisBlock := MakeGenesisBlock()
+	err = db.AddBlock(&genesisBlock)
+	if err != nil {
+		return fmt.Errorf("ImportFixture() add genesis block err: %w", err)
+	}
+
+	payTxn := fixturePaymentTxn()
+	createAssetTxn := MakeConfigAssetTxn(
+		0, 1000000, 2, false, "EXMPL", "example", "https://example.com", AccountA)
+	createAppTxn := MakeCreateAppTxn(AccountA)
+	block1, err := makeFixtureBlock(
+		genesisBlock.BlockHeader, &payTxn, &createAssetTxn, &createAppTxn)
+	if err != nil {
+		return err
+	}
+	err = db.AddBlock(&block1)
+	if err != nil {
+		return fmt.Errorf("ImportFixture() add round 1 err: %w", err)
+	}
+
+	assetID, err := createdAssetID(db, AccountA)
+	if err != nil {
+		return err
+	}
+
+	optinTxn := MakeAssetOptInTxn(assetID, AccountB)
+	transferTxn := MakeAssetTransferTxn(
+		assetID, 500, AccountA, AccountB, basics.Address{})
+	block2, err := makeFixtureBlock(block1.BlockHeader, &optinTxn, &transferTxn)
+	if err != nil {
+		return err
+	}
+	err = db.AddBlock(&block2)
+	if err != nil {
+		return fmt.Errorf("ImportFixture() add round 2 err: %w", err)
+	}
+	return nil
+}
+
+// FixtureRequest is a request of one operation of the API against the fixture.
+type FixtureRequest struct {
+	OperationID string
+	Path        string
+}
+
+// FixtureRequests returns one request per operation of the API against a
+// database with the fixture imported by ImportFixture().
+func FixtureRequests(db idb.IndexerDb) ([]FixtureRequest, error) {
+	assetID, err := createdAssetID(db, AccountA)
+	if err != nil {
+		return nil, err
+	}
+	appID, err := createdAppID(db)
+	if err != nil {
+		return nil, err
+	}
+
+	payTxn := fixturePaymentTxn()
+	requests := []FixtureRequest{
+		{"searchForAccounts", "/v2/accounts?limit=2"},
+		{"lookupAccountByID", "/v2/accounts/" + AccountB.String()},
+		{"lookupAccountTransactions", "/v2/accounts/" + AccountB.String() + "/transactions?limit=2"},
+		{"searchForApplications", "/v2/applications"},
+		{"lookupApplicationByID", fmt.Sprintf("/v2/applications/%d", appID)},
+		{"searchForAssets", "/v2/assets"},
+		{"lookupAssetByID", fmt.Sprintf("/v2/assets/%d", assetID)},
+		{"lookupAssetBalances", fmt.Sprintf("/v2/assets/%d/balances", assetID)},
+		{"lookupAssetTransactions", fmt.Sprintf("/v2/assets/%d/transactions", assetID)},
+		{"lookupBlock", "/v2/blocks/1"},
+		{"searchForTransactions", "/v2/transactions?limit=2"},
+		{"lookupTransaction", "/v2/transactions/" + payTxn.Txn.ID().String()},
+	}
+	return requests, nil
+}
+
+func makeFixtureBlock(prev bookkeeping.BlockHeader, txns ...*transactions.SignedTxnWithAD) (bookkeeping.Block, error) {
+	block, err := MakeBlockForTxns(prev, txns...)
+	if err != nil {
+		return bookkeeping.Block{}, fmt.Errorf("makeFixtureBlock() round %d err: %w", prev.Round+1, err)
+	}
+	block.TimeStamp = fixtureTime + int64(block.Round())
+	return block, nil
+}
+
+// createdAssetID returns the asset created by `creator`. The rows are read to the
+// end so that the query releases its connection.
+func createdAssetID(db idb.IndexerDb, creator basics.Address) (uint64, error) {
+	rows, _ := db.Assets(context.Background(), idb.AssetsQuery{Creator: creator[:]})
+	var assetID uint64
+	var err error
+	for row := range rows {
+		if row.Error != nil {
+			err = fmt.Errorf("find asset: %w", row.Error)
+		} else if assetID == 0 {
+			assetID = row.AssetID
+		}
+	}
+	if err == nil && assetID == 0 {
+		err = fmt.Errorf("find asset: not created")
+	}
+	return assetID, err
+}
+
+// createdAppID returns the only application of the fixture.
+func createdAppID(db idb.IndexerDb) (uint64, error) {
+	rows, _ := db.Applications(context.Background(), &generated.SearchForApplicationsParams{})
+	var appID uint64
+	var err error
+	for row := range rows {
+		if row.Error != nil {
+			err = fmt.Errorf("find application: %w", row.Error)
+		} else if appID == 0 {
+			appID = row.Application.Id
+		}
+	}
+	if err == nil && appID == 0 {
+		err = fmt.Errorf("find application: not created")
+	}
+	return appID, err
+}
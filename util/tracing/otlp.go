@@ -0,0 +1,119 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.10.0"
+)
+
+const (
+	// queueSize is the number of ended spans which wait for export, spans are
+	// dropped when the collector falls behind.
+	queueSize = 4096
+	// batchSize is the maximum number of spans of an export request.
+	batchSize = 512
+	// exportInterval is the time after which the ended spans are exported.
+	exportInterval = 5 * time.Second
+	// defaultTimeout is the default of Options.Timeout.
+	defaultTimeout = 10 * time.Second
+)
+
+// Options configures a Tracer.
+type Options struct {
+	// Endpoint is the OTLP over HTTP endpoint of the collector, e.g.
+	// http://localhost:4318, the spans are posted to <Endpoint>/v1/traces.
+	Endpoint string
+
+	// Headers are added to the export requests, e.g. an API key of the vendor.
+	Headers map[string]string
+
+	// ServiceName is the service.name of the spans.
+	ServiceName string
+
+	// SampleRatio is the fraction of the traces started by the indexer which are
+	// exported, the traces continued from a caller follow the caller's decision.
+	SampleRatio float64
+
+	// Timeout is the timeout of an export request, defaults to defaultTimeout.
+	Timeout time.Duration
+}
+
+// Tracer is the OpenTelemetry SDK tracer provider which exports the ended spans
+// in batches, in the background.
+type Tracer struct {
+	provider *sdktrace.TracerProvider
+	timeout  time.Duration
+	log      *log.Logger
+}
+
+// MakeTracer creates a Tracer and starts exporting. Use SetTracer to record
+// spans with it and Close to export the remaining ones. The failed exports are
+// logged to `log`, the spans are dropped, they are not worth slowing the
+// indexer down.
+func MakeTracer(opts Options, log *log.Logger) (*Tracer, error) {
+	u, err := url.Parse(opts.Endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("MakeTracer() err: %w", err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, fmt.Errorf("MakeTracer() unsupported endpoint scheme %q, use http or https", u.Scheme)
+	}
+	if opts.SampleRatio < 0 || opts.SampleRatio > 1 {
+		return nil, fmt.Errorf("MakeTracer() sample ratio %v is not between 0 and 1", opts.SampleRatio)
+	}
+	if opts.Timeout == 0 {
+		opts.Timeout = defaultTimeout
+	}
+	path := u.Path
+	if !strings.HasSuffix(path, "/v1/traces") {
+		path = strings.TrimSuffix(path, "/") + "/v1/traces"
+	}
+
+	clientOpts := []otlptracehttp.Option{
+		otlptracehttp.WithEndpoint(u.Host),
+		otlptracehttp.WithURLPath(path),
+		otlptracehttp.WithHeaders(opts.Headers),
+		otlptracehttp.WithTimeout(opts.Timeout),
+		otlptracehttp.WithRetry(otlptracehttp.RetryConfig{Enabled: false}),
+	}
+	if u.Scheme == "http" {
+		clientOpts = append(clientOpts, otlptracehttp.WithInsecure())
+	}
+	exporter, err := otlptracehttp.New(context.Background(), clientOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("MakeTracer() err: %w", err)
+	}
+
+	otel.SetErrorHandler(otel.ErrorHandlerFunc(func(err error) {
+		log.WithError(err).Warn("exporting spans failed")
+	}))
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter,
+			sdktrace.WithMaxQueueSize(queueSize),
+			sdktrace.WithMaxExportBatchSize(batchSize),
+			sdktrace.WithBatchTimeout(exportInterval),
+			sdktrace.WithExportTimeout(opts.Timeout)),
+		sdktrace.WithResource(resource.NewWithAttributes(semconv.SchemaURL,
+			semconv.ServiceNameKey.String(opts.ServiceName))),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(opts.SampleRatio))))
+	return &Tracer{provider: provider, timeout: opts.Timeout, log: log}, nil
+}
+
+// Close exports the spans which ended and stops exporting.
+func (t *Tracer) Close() {
+	ctx, cancel := context.WithTimeout(context.Background(), t.timeout)
+	defer cancel()
+	err := t.provider.Shutdown(ctx)
+	if err != nil {
+		t.log.WithError(err).Warn("exporting the remaining spans failed")
+	}
+}
@@ -0,0 +1,50 @@
+// Package tracing records OpenTelemetry spans of the API requests, the database
+// queries, the block fetches and the block writes, and exports them to a
+// collector with OTLP over HTTP. Nothing is recorded until a Tracer is set.
+package tracing
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// instrumentationName is the name of the tracer of the indexer's spans.
+const instrumentationName = "github.com/algorand/indexer"
+
+// SetTracer sets the tracer of the spans which are started from now on, nil
+// stops recording spans.
+func SetTracer(t *Tracer) {
+	if t == nil {
+		otel.SetTracerProvider(trace.NewNoopTracerProvider())
+		return
+	}
+	otel.SetTracerProvider(t.provider)
+}
+
+// Start starts a span, which is a child of the span of `ctx` if there is one,
+// and returns a context with it. Without a tracer the span records nothing.
+func Start(ctx context.Context, name string, kind trace.SpanKind, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return otel.Tracer(instrumentationName).Start(ctx, name,
+		trace.WithSpanKind(kind), trace.WithAttributes(attrs...))
+}
+
+// SetError marks `span` as failed with `err`, unless `err` is nil.
+func SetError(span trace.Span, err error) {
+	if err == nil {
+		return
+	}
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+}
+
+// ContextWithTraceparent returns `ctx` with the caller's span of a W3C
+// traceparent header, so that the spans started with it continue the caller's
+// trace. It returns `ctx` when the header is missing or invalid.
+func ContextWithTraceparent(ctx context.Context, header string) context.Context {
+	return propagation.TraceContext{}.Extract(ctx, propagation.MapCarrier{"traceparent": header})
+}
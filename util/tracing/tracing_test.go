@@ -0,0 +1,137 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	collectortrace "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	tracepb "go.opentelemetry.io/proto/otlp/trace/v1"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestStartWithoutTracer(t *testing.T) {
+	SetTracer(nil)
+	_, span := Start(context.Background(), "test", trace.SpanKindInternal)
+	assert.False(t, span.IsRecording())
+
+	// The methods of a span which isn't recording do nothing.
+	span.SetAttributes(attribute.String("key", "value"))
+	SetError(span, errors.New("failed"))
+	span.End()
+}
+
+func TestContextWithTraceparent(t *testing.T) {
+	ctx := ContextWithTraceparent(
+		context.Background(), "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01")
+	parent := trace.SpanContextFromContext(ctx)
+	assert.Equal(t, "0af7651916cd43dd8448eb211c80319c", parent.TraceID().String())
+	assert.Equal(t, "b7ad6b7169203331", parent.SpanID().String())
+	assert.True(t, parent.IsSampled())
+	assert.True(t, parent.IsRemote())
+
+	invalid := []string{
+		"",
+		"00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331",
+		"00-00000000000000000000000000000000-b7ad6b7169203331-01",
+		"ff-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01",
+		"00-0af7651916cd43dd8448eb211c80319x-b7ad6b7169203331-01",
+	}
+	for _, header := range invalid {
+		ctx := ContextWithTraceparent(context.Background(), header)
+		assert.False(t, trace.SpanContextFromContext(ctx).IsValid(), header)
+	}
+}
+
+func TestMakeTracer(t *testing.T) {
+	_, err := MakeTracer(Options{Endpoint: "grpc://localhost:4317", SampleRatio: 1}, log.New())
+	assert.Error(t, err)
+	_, err = MakeTracer(Options{Endpoint: "http://localhost:4318", SampleRatio: 2}, log.New())
+	assert.Error(t, err)
+}
+
+// attributeValue returns the value of the attribute `key` of `attrs`.
+func attributeValue(attrs []*commonpb.KeyValue, key string) *commonpb.AnyValue {
+	for _, a := range attrs {
+		if a.Key == key {
+			return a.Value
+		}
+	}
+	return nil
+}
+
+func TestExport(t *testing.T) {
+	requests := make(chan *collectortrace.ExportTraceServiceRequest, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v1/traces", r.URL.Path)
+		assert.Equal(t, "secret", r.Header.Get("X-Api-Key"))
+		body, err := ioutil.ReadAll(r.Body)
+		require.NoError(t, err)
+		var req collectortrace.ExportTraceServiceRequest
+		require.NoError(t, proto.Unmarshal(body, &req))
+		requests <- &req
+	}))
+	defer server.Close()
+
+	tracer, err := MakeTracer(Options{
+		Endpoint:    server.URL,
+		Headers:     map[string]string{"X-Api-Key": "secret"},
+		ServiceName: "indexer",
+		SampleRatio: 1,
+	}, log.New())
+	require.NoError(t, err)
+	SetTracer(tracer)
+	defer SetTracer(nil)
+
+	ctx, parent := Start(context.Background(), "GET /v2/accounts", trace.SpanKindServer)
+	_, child := Start(ctx, "postgres accounts", trace.SpanKindClient, attribute.String("db.statement", "SELECT 1"))
+	child.SetAttributes(attribute.Int64("db.rows", 3))
+	SetError(child, errors.New("canceled"))
+	child.End()
+	parent.End()
+	// Spans which ended are exported when the tracer is closed.
+	tracer.Close()
+
+	req := <-requests
+	require.Len(t, req.ResourceSpans, 1)
+	resource := req.ResourceSpans[0].Resource
+	assert.Equal(t, "indexer", attributeValue(resource.Attributes, "service.name").GetStringValue())
+	spans := req.ResourceSpans[0].ScopeSpans[0].Spans
+	require.Len(t, spans, 2)
+	assert.Equal(t, "postgres accounts", spans[0].Name)
+	assert.Equal(t, tracepb.Span_SPAN_KIND_CLIENT, spans[0].Kind)
+	assert.Equal(t, spans[1].TraceId, spans[0].TraceId)
+	assert.Equal(t, spans[1].SpanId, spans[0].ParentSpanId)
+	assert.Empty(t, spans[1].ParentSpanId)
+	assert.Equal(t, "SELECT 1", attributeValue(spans[0].Attributes, "db.statement").GetStringValue())
+	assert.Equal(t, int64(3), attributeValue(spans[0].Attributes, "db.rows").GetIntValue())
+	assert.Equal(t, tracepb.Status_STATUS_CODE_ERROR, spans[0].Status.Code)
+	assert.Equal(t, "canceled", spans[0].Status.Message)
+	assert.Equal(t, tracepb.Status_STATUS_CODE_UNSET, spans[1].Status.Code)
+}
+
+func TestSampleRatio(t *testing.T) {
+	tracer, err := MakeTracer(Options{Endpoint: "http://localhost:4318", SampleRatio: 0}, log.New())
+	require.NoError(t, err)
+	defer tracer.Close()
+	SetTracer(tracer)
+	defer SetTracer(nil)
+
+	_, span := Start(context.Background(), "test", trace.SpanKindInternal)
+	assert.False(t, span.IsRecording())
+
+	// A trace continued from a caller follows the caller's decision.
+	ctx := ContextWithTraceparent(
+		context.Background(), "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01")
+	_, span = Start(ctx, "test", trace.SpanKindServer)
+	assert.True(t, span.IsRecording())
+}
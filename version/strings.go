@@ -4,6 +4,7 @@ import (
 	"encoding/base64"
 	"fmt"
 	"regexp"
+	"runtime"
 	"strings"
 )
 
@@ -68,3 +69,28 @@ func LongVersion() string {
 	}
 	return fmt.Sprintf("%s compiled at %s from git hash %s%s", tagVersion, CompileTime, Hash, dirtyStr)
 }
+
+// BuildInfo describes the binary, it is served on /version and is the build
+// info metric.
+type BuildInfo struct {
+	// Version is the tag of the build, or UnknownVersion.
+	Version string `json:"version"`
+	// Release is the content of /.version.
+	Release     string `json:"release-version"`
+	Commit      string `json:"commit"`
+	Dirty       bool   `json:"dirty"`
+	CompileTime string `json:"compile-time"`
+	GoVersion   string `json:"go-version"`
+}
+
+// Info returns the BuildInfo of the binary.
+func Info() BuildInfo {
+	return BuildInfo{
+		Version:     Version(),
+		Release:     ReleaseVersion,
+		Commit:      Hash,
+		Dirty:       len(Dirty) > 0 && Dirty != "false",
+		CompileTime: CompileTime,
+		GoVersion:   runtime.Version(),
+	}
+}
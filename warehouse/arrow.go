@@ -0,0 +1,171 @@
+package warehouse
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/apache/arrow/go/arrow"
+	"github.com/apache/arrow/go/arrow/array"
+	"github.com/apache/arrow/go/arrow/ipc"
+	"github.com/apache/arrow/go/arrow/memory"
+)
+
+// A record batch is written every arrowBatchRows rows, or when the variable
+// width values of a column reach arrowBatchBytes, so that the int32 offsets
+// don't overflow.
+const (
+	arrowBatchRows  = 65536
+	arrowBatchBytes = 64 << 20
+)
+
+// arrowType returns the Arrow type of the column `c`.
+func arrowType(c column) arrow.DataType {
+	switch c.kind {
+	case kindLong:
+		return arrow.PrimitiveTypes.Int64
+	case kindUint:
+		return arrow.PrimitiveTypes.Uint64
+	case kindBool:
+		return arrow.FixedWidthTypes.Boolean
+	case kindString:
+		return arrow.BinaryTypes.String
+	case kindBytes:
+		return arrow.BinaryTypes.Binary
+	case kindTimestamp:
+		return arrow.FixedWidthTypes.Timestamp_us
+	}
+	return nil
+}
+
+// arrowSchema returns the Arrow schema of the table `t`.
+func arrowSchema(t table) *arrow.Schema {
+	fields := make([]arrow.Field, len(t.columns))
+	for i, c := range t.columns {
+		fields[i] = arrow.Field{Name: c.name, Type: arrowType(c), Nullable: c.nullable}
+	}
+	return arrow.NewSchema(fields, nil)
+}
+
+// appendArrowValue appends a value of `c` to its builder, and returns the number
+// of bytes of variable width values.
+func appendArrowValue(b array.Builder, c column, v interface{}) int {
+	if v == nil {
+		b.AppendNull()
+		return 0
+	}
+	switch c.kind {
+	case kindLong:
+		b.(*array.Int64Builder).Append(v.(int64))
+	case kindUint:
+		b.(*array.Uint64Builder).Append(v.(uint64))
+	case kindBool:
+		b.(*array.BooleanBuilder).Append(v.(bool))
+	case kindString:
+		b.(*array.StringBuilder).Append(v.(string))
+		return len(v.(string))
+	case kindBytes:
+		b.(*array.BinaryBuilder).Append(v.([]byte))
+		return len(v.([]byte))
+	case kindTimestamp:
+		micros := v.(time.Time).UnixNano() / int64(time.Microsecond)
+		b.(*array.TimestampBuilder).Append(arrow.Timestamp(micros))
+	}
+	return 0
+}
+
+// positionWriter tracks the position in an io.Writer, which the Arrow file
+// writer asks for with Seek.
+type positionWriter struct {
+	w   io.Writer
+	pos int64
+}
+
+func (w *positionWriter) Write(b []byte) (int, error) {
+	n, err := w.w.Write(b)
+	w.pos += int64(n)
+	return n, err
+}
+
+func (w *positionWriter) Seek(offset int64, whence int) (int64, error) {
+	if offset != 0 || whence != io.SeekCurrent {
+		return 0, errors.New("Seek() only the current position is supported")
+	}
+	return w.pos, nil
+}
+
+// arrowWriter writes an uncompressed Arrow IPC file, also known as Feather
+// version 2. Unsigned amounts are uint64 and timestamps are microseconds in UTC.
+type arrowWriter struct {
+	fw      *ipc.FileWriter
+	builder *array.RecordBuilder
+	table   table
+	rows    int
+	// bytes are the bytes of variable width values of each column in the batch.
+	bytes []int
+}
+
+func makeArrowWriter(w io.Writer, t table) (*arrowWriter, error) {
+	mem := memory.NewGoAllocator()
+	schema := arrowSchema(t)
+	fw, err := ipc.NewFileWriter(&positionWriter{w: w}, ipc.WithSchema(schema), ipc.WithAllocator(mem))
+	if err != nil {
+		return nil, fmt.Errorf("makeArrowWriter() err: %w", err)
+	}
+	return &arrowWriter{
+		fw:      fw,
+		builder: array.NewRecordBuilder(mem, schema),
+		table:   t,
+		bytes:   make([]int, len(t.columns)),
+	}, nil
+}
+
+func (w *arrowWriter) writeRow(values []interface{}) error {
+	for i, c := range w.table.columns {
+		if values[i] == nil && !c.nullable {
+			return fmt.Errorf("writeRow() column %s null value", c.name)
+		}
+	}
+	full := false
+	for i, c := range w.table.columns {
+		w.bytes[i] += appendArrowValue(w.builder.Field(i), c, values[i])
+		full = full || w.bytes[i] >= arrowBatchBytes
+	}
+	w.rows++
+	if full || w.rows >= arrowBatchRows {
+		return w.flush()
+	}
+	return nil
+}
+
+// flush writes the buffered rows as a record batch.
+func (w *arrowWriter) flush() error {
+	if w.rows == 0 {
+		return nil
+	}
+	record := w.builder.NewRecord()
+	defer record.Release()
+	w.rows = 0
+	for i := range w.bytes {
+		w.bytes[i] = 0
+	}
+	err := w.fw.Write(record)
+	if err != nil {
+		return fmt.Errorf("flush() err: %w", err)
+	}
+	return nil
+}
+
+func (w *arrowWriter) close() error {
+	defer w.builder.Release()
+	err := w.flush()
+	if err != nil {
+		return fmt.Errorf("close() err: %w", err)
+	}
+	err = w.fw.Close()
+	if err != nil {
+		return fmt.Errorf("close() err: %w", err)
+	}
+	return nil
+}
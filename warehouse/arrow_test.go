@@ -0,0 +1,98 @@
+package warehouse
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/apache/arrow/go/arrow"
+	"github.com/apache/arrow/go/arrow/array"
+	"github.com/apache/arrow/go/arrow/ipc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestArrowWriter(t *testing.T) {
+	var buf bytes.Buffer
+	w, err := makeArrowWriter(&buf, table{
+		name: "test",
+		columns: []column{
+			{name: "amount", kind: kindUint},
+			{name: "receiver", kind: kindString, nullable: true},
+			{name: "frozen", kind: kindBool},
+			{name: "time", kind: kindTimestamp},
+			{name: "note", kind: kindBytes, nullable: true},
+		},
+	})
+	require.NoError(t, err)
+	ts := time.Unix(10, 5000)
+	require.NoError(t, w.writeRow([]interface{}{uint64(5), "b", true, ts, []byte{1}}))
+	require.NoError(t, w.writeRow([]interface{}{uint64(6), nil, false, ts, nil}))
+	assert.Error(t, w.writeRow([]interface{}{nil, nil, false, ts, nil}))
+	require.NoError(t, w.close())
+
+	r, err := ipc.NewFileReader(bytes.NewReader(buf.Bytes()))
+	require.NoError(t, err)
+	defer r.Close()
+
+	schema := r.Schema()
+	require.Len(t, schema.Fields(), 5)
+	assert.Equal(t, "receiver", schema.Field(1).Name)
+	assert.True(t, schema.Field(1).Nullable)
+	assert.Equal(t, arrow.FixedWidthTypes.Timestamp_us, schema.Field(3).Type)
+
+	require.Equal(t, 1, r.NumRecords())
+	record, err := r.Record(0)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), record.NumRows())
+
+	assert.Equal(t, []uint64{5, 6}, record.Column(0).(*array.Uint64).Uint64Values())
+	receivers := record.Column(1).(*array.String)
+	assert.Equal(t, "b", receivers.Value(0))
+	assert.True(t, receivers.IsNull(1))
+	frozen := record.Column(2).(*array.Boolean)
+	assert.True(t, frozen.Value(0))
+	assert.False(t, frozen.Value(1))
+	micros := arrow.Timestamp(10000005)
+	assert.Equal(t, []arrow.Timestamp{micros, micros}, record.Column(3).(*array.Timestamp).TimestampValues())
+	notes := record.Column(4).(*array.Binary)
+	assert.Equal(t, []byte{1}, notes.Value(0))
+	assert.True(t, notes.IsNull(1))
+}
+
+func TestArrowWriterBatches(t *testing.T) {
+	var buf bytes.Buffer
+	w, err := makeArrowWriter(&buf, table{
+		name:    "test",
+		columns: []column{{name: "round", kind: kindLong}},
+	})
+	require.NoError(t, err)
+	for i := 0; i < arrowBatchRows+1; i++ {
+		require.NoError(t, w.writeRow([]interface{}{int64(i)}))
+	}
+	require.NoError(t, w.close())
+
+	r, err := ipc.NewFileReader(bytes.NewReader(buf.Bytes()))
+	require.NoError(t, err)
+	defer r.Close()
+	require.Equal(t, 2, r.NumRecords())
+	record, err := r.Record(1)
+	require.NoError(t, err)
+	assert.Equal(t, []int64{arrowBatchRows}, record.Column(0).(*array.Int64).Int64Values())
+}
+
+func TestArrowWriterEmpty(t *testing.T) {
+	var buf bytes.Buffer
+	w, err := makeArrowWriter(&buf, table{
+		name:    "test",
+		columns: []column{{name: "round", kind: kindLong}},
+	})
+	require.NoError(t, err)
+	require.NoError(t, w.close())
+
+	r, err := ipc.NewFileReader(bytes.NewReader(buf.Bytes()))
+	require.NoError(t, err)
+	defer r.Close()
+	assert.Equal(t, 0, r.NumRecords())
+	assert.Equal(t, "round", r.Schema().Field(0).Name)
+}
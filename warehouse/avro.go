@@ -0,0 +1,161 @@
+package warehouse
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+
+	"github.com/linkedin/goavro/v2"
+)
+
+const (
+	// Rows and approximate uncompressed bytes of an Avro block.
+	avroBlockRows  = 10000
+	avroBlockBytes = 1 << 20
+
+	avroNamespace = "algorand.indexer"
+)
+
+// avroWriter writes an Avro object container file with deflate compressed
+// blocks. Unsigned amounts are decimals so that they don't overflow a long.
+type avroWriter struct {
+	ocf   *goavro.OCFWriter
+	table table
+	rows  []interface{}
+	// bytes are the bytes of the strings and bytes of the buffered rows.
+	bytes int
+}
+
+// avroType returns the Avro schema of the type of `c`.
+func avroType(c column) interface{} {
+	var t interface{}
+	switch c.kind {
+	case kindLong:
+		t = "long"
+	case kindUint:
+		t = map[string]interface{}{
+			"type": "bytes", "logicalType": "decimal", "precision": 20, "scale": 0,
+		}
+	case kindBool:
+		t = "boolean"
+	case kindString:
+		t = "string"
+	case kindBytes:
+		t = "bytes"
+	case kindTimestamp:
+		t = map[string]interface{}{"type": "long", "logicalType": "timestamp-micros"}
+	}
+	if c.nullable {
+		return []interface{}{"null", t}
+	}
+	return t
+}
+
+// avroTypeName returns the name of the type of `c` in a union, as goavro names
+// it.
+func avroTypeName(c column) string {
+	switch c.kind {
+	case kindLong:
+		return "long"
+	case kindUint:
+		return "bytes.decimal"
+	case kindBool:
+		return "boolean"
+	case kindString:
+		return "string"
+	case kindBytes:
+		return "bytes"
+	case kindTimestamp:
+		return "long.timestamp-micros"
+	}
+	return ""
+}
+
+// avroSchema returns the schema of the records of `t`.
+func avroSchema(t table) ([]byte, error) {
+	fields := make([]interface{}, 0, len(t.columns))
+	for _, c := range t.columns {
+		field := map[string]interface{}{"name": c.name, "type": avroType(c)}
+		if c.nullable {
+			field["default"] = nil
+		}
+		fields = append(fields, field)
+	}
+	return json.Marshal(map[string]interface{}{
+		"type":      "record",
+		"name":      t.name,
+		"namespace": avroNamespace,
+		"fields":    fields,
+	})
+}
+
+// avroValue converts a value of `c` to the native goavro value.
+func avroValue(c column, v interface{}) interface{} {
+	if v == nil {
+		return nil
+	}
+	if c.kind == kindUint {
+		v = new(big.Rat).SetInt(new(big.Int).SetUint64(v.(uint64)))
+	}
+	if c.nullable {
+		return goavro.Union(avroTypeName(c), v)
+	}
+	return v
+}
+
+func makeAvroWriter(w io.Writer, t table) (*avroWriter, error) {
+	schema, err := avroSchema(t)
+	if err != nil {
+		return nil, fmt.Errorf("makeAvroWriter() err: %w", err)
+	}
+	ocf, err := goavro.NewOCFWriter(goavro.OCFConfig{
+		W:               w,
+		Schema:          string(schema),
+		CompressionName: goavro.CompressionDeflateLabel,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("makeAvroWriter() err: %w", err)
+	}
+	return &avroWriter{ocf: ocf, table: t}, nil
+}
+
+func (w *avroWriter) writeRow(values []interface{}) error {
+	record := make(map[string]interface{}, len(w.table.columns))
+	for i, c := range w.table.columns {
+		if values[i] == nil && !c.nullable {
+			return fmt.Errorf("writeRow() column %s null value", c.name)
+		}
+		record[c.name] = avroValue(c, values[i])
+		switch v := values[i].(type) {
+		case string:
+			w.bytes += len(v)
+		case []byte:
+			w.bytes += len(v)
+		}
+	}
+	w.rows = append(w.rows, record)
+
+	if len(w.rows) >= avroBlockRows || w.bytes >= avroBlockBytes {
+		return w.flush()
+	}
+	return nil
+}
+
+// flush writes the buffered rows as a block.
+func (w *avroWriter) flush() error {
+	if len(w.rows) == 0 {
+		return nil
+	}
+	err := w.ocf.Append(w.rows)
+	if err != nil {
+		return fmt.Errorf("flush() err: %w", err)
+	}
+	w.rows = w.rows[:0]
+	w.bytes = 0
+	return nil
+}
+
+func (w *avroWriter) close() error {
+	return w.flush()
+}
@@ -0,0 +1,79 @@
+package warehouse
+
+import (
+	"bufio"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+)
+
+// ndjsonWriter writes a JSON object per line. Timestamps are RFC 3339 strings
+// and bytes are base64, as BigQuery and Snowflake load them.
+type ndjsonWriter struct {
+	w     *bufio.Writer
+	table table
+	line  []byte
+}
+
+func makeNDJSONWriter(w io.Writer, t table) *ndjsonWriter {
+	return &ndjsonWriter{
+		w:     bufio.NewWriter(w),
+		table: t,
+	}
+}
+
+// appendValue appends the JSON of a value of `c`.
+func appendValue(b []byte, c column, v interface{}) ([]byte, error) {
+	if v == nil {
+		return append(b, "null"...), nil
+	}
+	switch c.kind {
+	case kindLong:
+		return strconv.AppendInt(b, v.(int64), 10), nil
+	case kindUint:
+		return strconv.AppendUint(b, v.(uint64), 10), nil
+	case kindBool:
+		return strconv.AppendBool(b, v.(bool)), nil
+	case kindString:
+		s, err := json.Marshal(v.(string))
+		if err != nil {
+			return nil, err
+		}
+		return append(b, s...), nil
+	case kindBytes:
+		b = append(b, '"')
+		b = append(b, base64.StdEncoding.EncodeToString(v.([]byte))...)
+		return append(b, '"'), nil
+	case kindTimestamp:
+		return strconv.AppendQuote(b, formatTimestamp(v.(time.Time))), nil
+	}
+	return nil, fmt.Errorf("appendValue() unknown kind %d", c.kind)
+}
+
+func (w *ndjsonWriter) writeRow(values []interface{}) error {
+	line := append(w.line[:0], '{')
+	for i, c := range w.table.columns {
+		if i > 0 {
+			line = append(line, ',')
+		}
+		line = strconv.AppendQuote(line, c.name)
+		line = append(line, ':')
+		var err error
+		line, err = appendValue(line, c, values[i])
+		if err != nil {
+			return fmt.Errorf("writeRow() column %s err: %w", c.name, err)
+		}
+	}
+	line = append(line, '}', '\n')
+	w.line = line
+
+	_, err := w.w.Write(line)
+	return err
+}
+
+func (w *ndjsonWriter) close() error {
+	return w.w.Flush()
+}
@@ -0,0 +1,106 @@
+package warehouse
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/xitongsys/parquet-go/parquet"
+	"github.com/xitongsys/parquet-go/writer"
+)
+
+// Rows of a parquet row group.
+const parquetRowGroupRows = 20000
+
+// parquetTag returns the parquet-go metadata of the column `c`.
+func parquetTag(c column) string {
+	var typ string
+	switch c.kind {
+	case kindLong:
+		typ = "type=INT64"
+	case kindUint:
+		typ = "type=INT64, convertedtype=UINT_64"
+	case kindBool:
+		typ = "type=BOOLEAN"
+	case kindString:
+		typ = "type=BYTE_ARRAY, convertedtype=UTF8"
+	case kindBytes:
+		typ = "type=BYTE_ARRAY"
+	case kindTimestamp:
+		typ = "type=INT64, convertedtype=TIMESTAMP_MICROS"
+	}
+	repetition := "REQUIRED"
+	if c.nullable {
+		repetition = "OPTIONAL"
+	}
+	return fmt.Sprintf("name=%s, %s, repetitiontype=%s", c.name, typ, repetition)
+}
+
+// parquetValue converts a value of `c` to the type parquet-go writes.
+func parquetValue(c column, v interface{}) interface{} {
+	if v == nil {
+		return nil
+	}
+	switch c.kind {
+	case kindUint:
+		return int64(v.(uint64))
+	case kindBytes:
+		return string(v.([]byte))
+	case kindTimestamp:
+		return v.(time.Time).UnixNano() / int64(time.Microsecond)
+	}
+	return v
+}
+
+// parquetWriter writes a parquet file with gzip compressed pages. Unsigned
+// amounts are UINT_64 and timestamps TIMESTAMP_MICROS.
+type parquetWriter struct {
+	pw    *writer.CSVWriter
+	table table
+	rows  int
+}
+
+func makeParquetWriter(w io.Writer, t table) (*parquetWriter, error) {
+	tags := make([]string, len(t.columns))
+	for i, c := range t.columns {
+		tags[i] = parquetTag(c)
+	}
+	pw, err := writer.NewCSVWriterFromWriter(tags, w, 1)
+	if err != nil {
+		return nil, fmt.Errorf("makeParquetWriter() err: %w", err)
+	}
+	pw.CompressionType = parquet.CompressionCodec_GZIP
+	createdBy := "algorand-indexer"
+	pw.Footer.CreatedBy = &createdBy
+	return &parquetWriter{pw: pw, table: t}, nil
+}
+
+func (w *parquetWriter) writeRow(values []interface{}) error {
+	row := make([]interface{}, len(values))
+	for i, c := range w.table.columns {
+		if values[i] == nil && !c.nullable {
+			return fmt.Errorf("writeRow() column %s null value", c.name)
+		}
+		row[i] = parquetValue(c, values[i])
+	}
+	err := w.pw.Write(row)
+	if err != nil {
+		return fmt.Errorf("writeRow() err: %w", err)
+	}
+	w.rows++
+	if w.rows%parquetRowGroupRows == 0 {
+		err = w.pw.Flush(true)
+		if err != nil {
+			return fmt.Errorf("writeRow() flush err: %w", err)
+		}
+	}
+	return nil
+}
+
+func (w *parquetWriter) close() error {
+	err := w.pw.WriteStop()
+	if err != nil {
+		return fmt.Errorf("close() err: %w", err)
+	}
+	return nil
+}
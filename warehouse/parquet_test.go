@@ -0,0 +1,90 @@
+package warehouse
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xitongsys/parquet-go-source/buffer"
+	"github.com/xitongsys/parquet-go/common"
+	"github.com/xitongsys/parquet-go/parquet"
+	"github.com/xitongsys/parquet-go/reader"
+)
+
+func TestParquetWriter(t *testing.T) {
+	var buf bytes.Buffer
+	tbl := table{
+		name: "test",
+		columns: []column{
+			{name: "amount", kind: kindUint},
+			{name: "receiver", kind: kindString, nullable: true},
+			{name: "note", kind: kindBytes, nullable: true},
+			{name: "round_time", kind: kindTimestamp},
+			{name: "deleted", kind: kindBool},
+		},
+	}
+	w, err := makeParquetWriter(&buf, tbl)
+	require.NoError(t, err)
+	roundTime := time.Unix(1600000000, 123456000).UTC()
+	require.NoError(t, w.writeRow([]interface{}{uint64(5), "b", []byte{1, 2}, roundTime, true}))
+	require.NoError(t, w.writeRow([]interface{}{uint64(1) << 63, nil, nil, roundTime, false}))
+	assert.Error(t, w.writeRow([]interface{}{nil, nil, nil, roundTime, false}))
+	require.NoError(t, w.close())
+
+	file, err := buffer.NewBufferFile(buf.Bytes())
+	require.NoError(t, err)
+	r, err := reader.NewParquetColumnReader(file, 1)
+	require.NoError(t, err)
+	defer r.ReadStop()
+	assert.Equal(t, int64(2), r.GetNumRows())
+	assert.Equal(t, "algorand-indexer", r.Footer.GetCreatedBy())
+
+	read := func(name string) []interface{} {
+		path := r.SchemaHandler.GetRootExName() + common.PAR_GO_PATH_DELIMITER + name
+		values, _, _, err := r.ReadColumnByPath(path, 2)
+		require.NoError(t, err, name)
+		return values
+	}
+	micros := roundTime.UnixNano() / int64(time.Microsecond)
+	assert.Equal(t, []interface{}{int64(5), int64(-1 << 63)}, read("amount"))
+	assert.Equal(t, []interface{}{"b", nil}, read("receiver"))
+	assert.Equal(t, []interface{}{"\x01\x02", nil}, read("note"))
+	assert.Equal(t, []interface{}{micros, micros}, read("round_time"))
+	assert.Equal(t, []interface{}{true, false}, read("deleted"))
+
+	schema := r.Footer.GetSchema()
+	require.Len(t, schema, len(tbl.columns)+1)
+	assert.Equal(t, parquet.ConvertedType_UINT_64, schema[1].GetConvertedType())
+	assert.Equal(t, parquet.FieldRepetitionType_OPTIONAL, schema[2].GetRepetitionType())
+	assert.Equal(t, parquet.ConvertedType_TIMESTAMP_MICROS, schema[4].GetConvertedType())
+	for _, group := range r.Footer.GetRowGroups() {
+		for _, chunk := range group.GetColumns() {
+			assert.Equal(t, parquet.CompressionCodec_GZIP, chunk.GetMetaData().GetCodec())
+		}
+	}
+}
+
+func TestParquetWriterRowGroups(t *testing.T) {
+	var buf bytes.Buffer
+	w, err := makeParquetWriter(&buf, table{
+		name:    "test",
+		columns: []column{{name: "round", kind: kindLong}},
+	})
+	require.NoError(t, err)
+	for i := 0; i < parquetRowGroupRows+1; i++ {
+		require.NoError(t, w.writeRow([]interface{}{int64(i)}))
+	}
+	require.NoError(t, w.close())
+
+	file, err := buffer.NewBufferFile(buf.Bytes())
+	require.NoError(t, err)
+	r, err := reader.NewParquetColumnReader(file, 1)
+	require.NoError(t, err)
+	defer r.ReadStop()
+	groups := r.Footer.GetRowGroups()
+	require.Len(t, groups, 2)
+	assert.Equal(t, int64(parquetRowGroupRows), groups[0].GetNumRows())
+	assert.Equal(t, int64(1), groups[1].GetNumRows())
+}
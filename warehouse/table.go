@@ -0,0 +1,267 @@
+package warehouse
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/algorand/go-algorand/data/basics"
+	"github.com/algorand/go-algorand/protocol"
+
+	models "github.com/algorand/indexer/api/generated/v2"
+	"github.com/algorand/indexer/idb"
+	"github.com/algorand/indexer/importer"
+)
+
+// kind is the type of a column.
+type kind int
+
+const (
+	// kindLong is an int64.
+	kindLong kind = iota
+	// kindUint is a uint64, for amounts which may not fit in a signed integer.
+	kindUint
+	kindBool
+	kindString
+	kindBytes
+	// kindTimestamp is a time.Time, stored with microsecond precision.
+	kindTimestamp
+)
+
+// column is a column of a table. A nullable column may have nil values.
+type column struct {
+	name     string
+	kind     kind
+	nullable bool
+}
+
+// table is the layout of the rows of an exported table. The values of a row are
+// in the order of the columns.
+type table struct {
+	name    string
+	columns []column
+}
+
+// Names of the exported tables.
+const (
+	TransactionsTable = "transactions"
+	AccountsTable     = "accounts"
+	AssetsTable       = "assets"
+)
+
+var transactionsTable = table{
+	name: TransactionsTable,
+	columns: []column{
+		{name: "round", kind: kindLong},
+		{name: "intra", kind: kindLong},
+		{name: "round_time", kind: kindTimestamp},
+		{name: "txid", kind: kindString},
+		{name: "type", kind: kindString},
+		{name: "sender", kind: kindString},
+		{name: "receiver", kind: kindString, nullable: true},
+		{name: "close_to", kind: kindString, nullable: true},
+		// The microalgos of payments, and the asset units of asset transfers.
+		{name: "amount", kind: kindUint},
+		{name: "close_amount", kind: kindUint},
+		{name: "asset_id", kind: kindUint, nullable: true},
+		{name: "application_id", kind: kindUint, nullable: true},
+		{name: "fee", kind: kindUint},
+		{name: "first_valid", kind: kindUint},
+		{name: "last_valid", kind: kindUint},
+		{name: "group", kind: kindBytes, nullable: true},
+		{name: "note", kind: kindBytes, nullable: true},
+		{name: "rekey_to", kind: kindString, nullable: true},
+		// The whole transaction as JSON, as published by the exporters of the
+		// importer.
+		{name: "txn", kind: kindString},
+	},
+}
+
+var accountsTable = table{
+	name: AccountsTable,
+	columns: []column{
+		{name: "round", kind: kindLong},
+		{name: "address", kind: kindString},
+		{name: "amount", kind: kindUint},
+		{name: "amount_without_pending_rewards", kind: kindUint},
+		{name: "pending_rewards", kind: kindUint},
+		{name: "rewards", kind: kindUint},
+		{name: "status", kind: kindString},
+		{name: "sig_type", kind: kindString, nullable: true},
+		{name: "auth_addr", kind: kindString, nullable: true},
+		{name: "created_at_round", kind: kindLong, nullable: true},
+		{name: "closed_at_round", kind: kindLong, nullable: true},
+		{name: "deleted", kind: kindBool},
+	},
+}
+
+var assetsTable = table{
+	name: AssetsTable,
+	columns: []column{
+		{name: "round", kind: kindLong},
+		{name: "asset_id", kind: kindUint},
+		{name: "creator", kind: kindString},
+		{name: "name", kind: kindString},
+		{name: "unit_name", kind: kindString},
+		{name: "url", kind: kindString},
+		{name: "total", kind: kindUint},
+		{name: "decimals", kind: kindLong},
+		{name: "default_frozen", kind: kindBool},
+		{name: "manager", kind: kindString, nullable: true},
+		{name: "reserve", kind: kindString, nullable: true},
+		{name: "freeze", kind: kindString, nullable: true},
+		{name: "clawback", kind: kindString, nullable: true},
+		{name: "created_at_round", kind: kindLong, nullable: true},
+		{name: "closed_at_round", kind: kindLong, nullable: true},
+		{name: "deleted", kind: kindBool},
+	},
+}
+
+// addressOrNil returns nil for the zero address.
+func addressOrNil(addr basics.Address) interface{} {
+	if addr.IsZero() {
+		return nil
+	}
+	return addr.String()
+}
+
+// bytesOrNil returns nil for empty bytes.
+func bytesOrNil(b []byte) interface{} {
+	if len(b) == 0 {
+		return nil
+	}
+	return b
+}
+
+// uint64OrNil returns nil for 0.
+func uint64OrNil(v uint64) interface{} {
+	if v == 0 {
+		return nil
+	}
+	return v
+}
+
+// roundOrNil returns nil for a nil round.
+func roundOrNil(round *uint64) interface{} {
+	if round == nil {
+		return nil
+	}
+	return int64(*round)
+}
+
+// transactionValues returns the values of the row of transactionsTable.
+func transactionValues(row idb.TxnRow) ([]interface{}, error) {
+	exported, err := importer.MakeExportedTransaction(row)
+	if err != nil {
+		return nil, fmt.Errorf("transactionValues() err: %w", err)
+	}
+	stxn := &exported.Txn
+	txn := &stxn.Txn
+
+	var receiver, closeTo basics.Address
+	var amount, closeAmount, assetID, appID uint64
+	switch txn.Type {
+	case protocol.PaymentTx:
+		receiver = txn.Receiver
+		closeTo = txn.CloseRemainderTo
+		amount = txn.Amount.Raw
+		closeAmount = stxn.ClosingAmount.Raw
+	case protocol.AssetTransferTx:
+		receiver = txn.AssetReceiver
+		closeTo = txn.AssetCloseTo
+		amount = txn.AssetAmount
+		closeAmount = row.Extra.AssetCloseAmount
+		assetID = row.AssetID
+	case protocol.AssetConfigTx, protocol.AssetFreezeTx:
+		assetID = row.AssetID
+	case protocol.ApplicationCallTx:
+		appID = row.AssetID
+	}
+
+	var group interface{}
+	if !txn.Group.IsZero() {
+		group = txn.Group[:]
+	}
+
+	return []interface{}{
+		int64(row.Round),
+		int64(row.Intra),
+		row.RoundTime.UTC(),
+		exported.Txid,
+		string(txn.Type),
+		txn.Sender.String(),
+		addressOrNil(receiver),
+		addressOrNil(closeTo),
+		amount,
+		closeAmount,
+		uint64OrNil(assetID),
+		uint64OrNil(appID),
+		txn.Fee.Raw,
+		uint64(txn.FirstValid),
+		uint64(txn.LastValid),
+		group,
+		bytesOrNil(txn.Note),
+		addressOrNil(txn.RekeyTo),
+		string(protocol.EncodeJSON(exported)),
+	}, nil
+}
+
+// accountValues returns the values of the row of accountsTable.
+func accountValues(round uint64, account models.Account) []interface{} {
+	var sigType, authAddr interface{}
+	if account.SigType != nil {
+		sigType = *account.SigType
+	}
+	if account.AuthAddr != nil {
+		authAddr = *account.AuthAddr
+	}
+	deleted := account.Deleted != nil && *account.Deleted
+
+	return []interface{}{
+		int64(round),
+		account.Address,
+		account.Amount,
+		account.AmountWithoutPendingRewards,
+		account.PendingRewards,
+		account.Rewards,
+		account.Status,
+		sigType,
+		authAddr,
+		roundOrNil(account.CreatedAtRound),
+		roundOrNil(account.ClosedAtRound),
+		deleted,
+	}
+}
+
+// assetValues returns the values of the row of assetsTable.
+func assetValues(round uint64, asset idb.AssetRow) ([]interface{}, error) {
+	if asset.Error != nil {
+		return nil, asset.Error
+	}
+	var creator basics.Address
+	copy(creator[:], asset.Creator)
+	deleted := asset.Deleted != nil && *asset.Deleted
+
+	return []interface{}{
+		int64(round),
+		asset.AssetID,
+		creator.String(),
+		asset.Params.AssetName,
+		asset.Params.UnitName,
+		asset.Params.URL,
+		asset.Params.Total,
+		int64(asset.Params.Decimals),
+		asset.Params.DefaultFrozen,
+		addressOrNil(asset.Params.Manager),
+		addressOrNil(asset.Params.Reserve),
+		addressOrNil(asset.Params.Freeze),
+		addressOrNil(asset.Params.Clawback),
+		roundOrNil(asset.CreatedRound),
+		roundOrNil(asset.ClosedRound),
+		deleted,
+	}, nil
+}
+
+// formatTimestamp formats a kindTimestamp value for the text formats.
+func formatTimestamp(t time.Time) string {
+	return t.UTC().Format(time.RFC3339Nano)
+}
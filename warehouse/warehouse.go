@@ -0,0 +1,430 @@
+// Package warehouse exports the imported history to files which data
+// warehouses such as BigQuery and Snowflake, and analytics engines such as Spark
+// and DuckDB, can load.
+package warehouse
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/algorand/indexer/idb"
+)
+
+// Formats of the exported files.
+const (
+	FormatNDJSON  = "ndjson"
+	FormatAvro    = "avro"
+	FormatParquet = "parquet"
+	FormatArrow   = "arrow"
+)
+
+// ManifestName is the name of the manifest in the export directory.
+const ManifestName = "manifest.json"
+
+// DefaultPartitionRounds is the default of Options.PartitionRounds.
+const DefaultPartitionRounds = 100000
+
+// Options configures Export().
+type Options struct {
+	// Dir is the export directory, with the manifest of the previous exports.
+	Dir string
+
+	// Format is FormatNDJSON, FormatAvro, FormatParquet or FormatArrow.
+	Format string
+
+	// Tables are the exported tables, TransactionsTable, AccountsTable and
+	// AssetsTable.
+	Tables []string
+
+	// FromRound is the first round of the transactions, nil continues after the
+	// last round in the manifest.
+	FromRound *uint64
+
+	// ToRound is the last round of the transactions, nil is the latest imported
+	// round.
+	ToRound *uint64
+
+	// PartitionRounds is the number of rounds of a transactions partition.
+	PartitionRounds uint64
+}
+
+// ManifestFile is an exported file.
+type ManifestFile struct {
+	Table string `json:"table"`
+
+	// Path is relative to the export directory.
+	Path string `json:"path"`
+
+	// MinRound and MaxRound are the rounds of the transactions in the file, or
+	// the round of the accounts and assets.
+	MinRound uint64 `json:"min-round"`
+	MaxRound uint64 `json:"max-round"`
+
+	Rows uint64 `json:"rows"`
+}
+
+// Manifest lists the files of an export directory. It is updated after every
+// file, the files which are not listed are incomplete.
+type Manifest struct {
+	Format string `json:"format"`
+
+	// NextRound is the round after the last exported transactions, where the
+	// next export continues.
+	NextRound uint64 `json:"next-round"`
+
+	Files []ManifestFile `json:"files"`
+}
+
+// rowWriter writes the rows of a table in a format.
+type rowWriter interface {
+	writeRow(values []interface{}) error
+	close() error
+}
+
+func makeRowWriter(format string, w io.Writer, t table) (rowWriter, error) {
+	switch format {
+	case FormatNDJSON:
+		return makeNDJSONWriter(w, t), nil
+	case FormatAvro:
+		return makeAvroWriter(w, t)
+	case FormatParquet:
+		return makeParquetWriter(w, t)
+	case FormatArrow:
+		return makeArrowWriter(w, t)
+	}
+	return nil, fmt.Errorf("makeRowWriter() unknown format %s", format)
+}
+
+// ReadManifest reads the manifest of `dir`. A directory without a manifest has
+// an empty one.
+func ReadManifest(dir string) (Manifest, error) {
+	data, err := ioutil.ReadFile(filepath.Join(dir, ManifestName))
+	if os.IsNotExist(err) {
+		return Manifest{}, nil
+	}
+	if err != nil {
+		return Manifest{}, fmt.Errorf("ReadManifest() err: %w", err)
+	}
+	var manifest Manifest
+	err = json.Unmarshal(data, &manifest)
+	if err != nil {
+		return Manifest{}, fmt.Errorf("ReadManifest() decode err: %w", err)
+	}
+	return manifest, nil
+}
+
+// writeManifest replaces the manifest of `dir`.
+func writeManifest(dir string, manifest Manifest) error {
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return fmt.Errorf("writeManifest() err: %w", err)
+	}
+	name := filepath.Join(dir, ManifestName)
+	err = ioutil.WriteFile(name+".tmp", data, 0644)
+	if err != nil {
+		return fmt.Errorf("writeManifest() err: %w", err)
+	}
+	err = os.Rename(name+".tmp", name)
+	if err != nil {
+		return fmt.Errorf("writeManifest() err: %w", err)
+	}
+	return nil
+}
+
+// writeFile writes a file of `t` to `relPath` in `dir` with the rows of
+// `fill`. The file is renamed into place once it is complete. An empty file is
+// not kept.
+func writeFile(dir string, relPath string, format string, t table, fill func(write func([]interface{}) error) error) (uint64, error) {
+	name := filepath.Join(dir, filepath.FromSlash(relPath))
+	err := os.MkdirAll(filepath.Dir(name), 0755)
+	if err != nil {
+		return 0, fmt.Errorf("writeFile() err: %w", err)
+	}
+	f, err := os.Create(name + ".tmp")
+	if err != nil {
+		return 0, fmt.Errorf("writeFile() err: %w", err)
+	}
+	defer os.Remove(name + ".tmp")
+	defer f.Close()
+
+	w, err := makeRowWriter(format, f, t)
+	if err != nil {
+		return 0, fmt.Errorf("writeFile() err: %w", err)
+	}
+	var rows uint64
+	err = fill(func(values []interface{}) error {
+		rows++
+		return w.writeRow(values)
+	})
+	if err != nil {
+		return 0, fmt.Errorf("writeFile() %s err: %w", relPath, err)
+	}
+	if rows == 0 {
+		return 0, nil
+	}
+	err = w.close()
+	if err != nil {
+		return 0, fmt.Errorf("writeFile() %s err: %w", relPath, err)
+	}
+	err = f.Close()
+	if err != nil {
+		return 0, fmt.Errorf("writeFile() %s err: %w", relPath, err)
+	}
+	err = os.Rename(name+".tmp", name)
+	if err != nil {
+		return 0, fmt.Errorf("writeFile() err: %w", err)
+	}
+	return rows, nil
+}
+
+// Export writes the transactions of a round range, and the accounts and assets
+// as of the latest imported round, to `opts.Dir`. The transactions are
+// partitioned in directories of `opts.PartitionRounds` rounds, named
+// round_bucket=<first round> for the hive partitioning of the warehouses. The
+// indexer only keeps the current accounts and assets, every export adds a
+// round=<round> directory with all of them.
+//
+// The manifest of the directory lists the exported files. By default an export
+// continues with the round after the transactions of the previous one, a
+// scheduled export only adds the new rounds.
+func Export(ctx context.Context, db idb.IndexerDb, opts Options, l *log.Logger) (Manifest, error) {
+	switch opts.Format {
+	case FormatNDJSON, FormatAvro, FormatParquet, FormatArrow:
+	default:
+		return Manifest{}, fmt.Errorf("Export() unknown format %s", opts.Format)
+	}
+	if opts.PartitionRounds == 0 {
+		return Manifest{}, fmt.Errorf("Export() no partition rounds")
+	}
+	tables := make(map[string]bool)
+	for _, name := range opts.Tables {
+		switch name {
+		case TransactionsTable, AccountsTable, AssetsTable:
+			tables[name] = true
+		default:
+			return Manifest{}, fmt.Errorf("Export() unknown table %s", name)
+		}
+	}
+
+	err := os.MkdirAll(opts.Dir, 0755)
+	if err != nil {
+		return Manifest{}, fmt.Errorf("Export() err: %w", err)
+	}
+	manifest, err := ReadManifest(opts.Dir)
+	if err != nil {
+		return Manifest{}, fmt.Errorf("Export() err: %w", err)
+	}
+	if manifest.Format != "" && manifest.Format != opts.Format {
+		return Manifest{}, fmt.Errorf(
+			"Export() the directory has %s files, not %s", manifest.Format, opts.Format)
+	}
+	manifest.Format = opts.Format
+
+	imported, err := db.GetNextRoundToAccount()
+	if err != nil {
+		return Manifest{}, fmt.Errorf("Export() err: %w", err)
+	}
+	if imported == 0 {
+		return Manifest{}, fmt.Errorf("Export() no rounds are imported")
+	}
+	from := manifest.NextRound
+	if opts.FromRound != nil {
+		from = *opts.FromRound
+	}
+	to := imported - 1
+	if opts.ToRound != nil {
+		to = *opts.ToRound
+	}
+	if to >= imported {
+		return Manifest{}, fmt.Errorf("Export() round %d is not imported", to)
+	}
+
+	ext := "." + opts.Format
+	// add lists `file`, replacing the file it overwrote.
+	add := func(file ManifestFile) error {
+		if file.Rows > 0 {
+			replaced := false
+			for i := range manifest.Files {
+				if manifest.Files[i].Path == file.Path {
+					manifest.Files[i] = file
+					replaced = true
+				}
+			}
+			if !replaced {
+				manifest.Files = append(manifest.Files, file)
+			}
+		}
+		return writeManifest(opts.Dir, manifest)
+	}
+
+	if tables[TransactionsTable] {
+		for first := from; first <= to; {
+			bucket := first - first%opts.PartitionRounds
+			last := bucket + opts.PartitionRounds - 1
+			if last > to {
+				last = to
+			}
+			relPath := path.Join(
+				TransactionsTable, fmt.Sprintf("round_bucket=%d", bucket),
+				fmt.Sprintf("%s-%d-%d%s", TransactionsTable, first, last, ext))
+			rows, err := writeFile(opts.Dir, relPath, opts.Format, transactionsTable,
+				func(write func([]interface{}) error) error {
+					return exportTransactions(ctx, db, first, last, write)
+				})
+			if err != nil {
+				return Manifest{}, fmt.Errorf("Export() err: %w", err)
+			}
+			manifest.NextRound = last + 1
+			err = add(ManifestFile{
+				Table: TransactionsTable, Path: relPath, MinRound: first, MaxRound: last, Rows: rows,
+			})
+			if err != nil {
+				return Manifest{}, fmt.Errorf("Export() err: %w", err)
+			}
+			l.Infof("exported the transactions of rounds %d to %d, %d rows", first, last, rows)
+			first = last + 1
+		}
+	}
+
+	snapshots := []struct {
+		table  table
+		export func(ctx context.Context, db idb.IndexerDb, write func([]interface{}) error) (uint64, error)
+	}{
+		{accountsTable, exportAccounts},
+		{assetsTable, exportAssets},
+	}
+	for _, snapshot := range snapshots {
+		if !tables[snapshot.table.name] {
+			continue
+		}
+		// The round is only known once the query ran, the file is moved to its
+		// directory afterwards.
+		var round uint64
+		tmpPath := path.Join(snapshot.table.name, snapshot.table.name+ext)
+		rows, err := writeFile(opts.Dir, tmpPath, opts.Format, snapshot.table,
+			func(write func([]interface{}) error) error {
+				var err error
+				round, err = snapshot.export(ctx, db, write)
+				return err
+			})
+		if err != nil {
+			return Manifest{}, fmt.Errorf("Export() err: %w", err)
+		}
+		if rows == 0 {
+			continue
+		}
+		relPath := path.Join(
+			snapshot.table.name, fmt.Sprintf("round=%d", round),
+			fmt.Sprintf("%s-%d%s", snapshot.table.name, round, ext))
+		err = os.MkdirAll(filepath.Dir(filepath.Join(opts.Dir, filepath.FromSlash(relPath))), 0755)
+		if err != nil {
+			return Manifest{}, fmt.Errorf("Export() err: %w", err)
+		}
+		err = os.Rename(
+			filepath.Join(opts.Dir, filepath.FromSlash(tmpPath)),
+			filepath.Join(opts.Dir, filepath.FromSlash(relPath)))
+		if err != nil {
+			return Manifest{}, fmt.Errorf("Export() err: %w", err)
+		}
+		err = add(ManifestFile{
+			Table: snapshot.table.name, Path: relPath, MinRound: round, MaxRound: round, Rows: rows,
+		})
+		if err != nil {
+			return Manifest{}, fmt.Errorf("Export() err: %w", err)
+		}
+		l.Infof("exported the %s of round %d, %d rows", snapshot.table.name, round, rows)
+	}
+
+	return manifest, nil
+}
+
+// exportTransactions writes the transactions of the rounds from `first` to
+// `last`.
+func exportTransactions(ctx context.Context, db idb.IndexerDb, first uint64, last uint64, write func([]interface{}) error) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	tf := idb.TransactionFilter{MinRound: first, MaxRound: last}
+	if last == 0 {
+		// A MaxRound of 0 is no limit.
+		tf.Round = &last
+	}
+	rows, _ := db.Transactions(ctx, tf)
+	// Drains the rows after an error, so that the query ends.
+	defer func() {
+		cancel()
+		for range rows {
+		}
+	}()
+
+	for row := range rows {
+		values, err := transactionValues(row)
+		if err != nil {
+			return fmt.Errorf("exportTransactions() err: %w", err)
+		}
+		err = write(values)
+		if err != nil {
+			return fmt.Errorf("exportTransactions() err: %w", err)
+		}
+	}
+	return nil
+}
+
+// exportAccounts writes all the accounts, including the closed ones, and
+// returns their round.
+func exportAccounts(ctx context.Context, db idb.IndexerDb, write func([]interface{}) error) (uint64, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	rows, round := db.GetAccounts(ctx, idb.AccountQueryOptions{IncludeDeleted: true})
+	defer func() {
+		cancel()
+		for range rows {
+		}
+	}()
+
+	for row := range rows {
+		if row.Error != nil {
+			return 0, fmt.Errorf("exportAccounts() err: %w", row.Error)
+		}
+		err := write(accountValues(round, row.Account))
+		if err != nil {
+			return 0, fmt.Errorf("exportAccounts() err: %w", err)
+		}
+	}
+	return round, nil
+}
+
+// exportAssets writes all the assets, including the destroyed ones, and
+// returns their round.
+func exportAssets(ctx context.Context, db idb.IndexerDb, write func([]interface{}) error) (uint64, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	rows, round := db.Assets(ctx, idb.AssetsQuery{IncludeDeleted: true})
+	defer func() {
+		cancel()
+		for range rows {
+		}
+	}()
+
+	for row := range rows {
+		values, err := assetValues(round, row)
+		if err != nil {
+			return 0, fmt.Errorf("exportAssets() err: %w", err)
+		}
+		err = write(values)
+		if err != nil {
+			return 0, fmt.Errorf("exportAssets() err: %w", err)
+		}
+	}
+	return round, nil
+}
@@ -0,0 +1,166 @@
+package warehouse
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/algorand/go-algorand/data/basics"
+	"github.com/algorand/go-algorand/data/bookkeeping"
+	"github.com/algorand/go-algorand/rpcs"
+	"github.com/linkedin/goavro/v2"
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/algorand/indexer/idb/memory"
+	"github.com/algorand/indexer/importer"
+	"github.com/algorand/indexer/util/test"
+)
+
+// makeDb returns a database with rounds 0 to 3, with a payment in rounds 1 and
+// 3.
+func makeDb(t *testing.T) *memory.IndexerDb {
+	db := memory.MakeIndexerDb(nil)
+	require.NoError(t, db.LoadGenesis(test.MakeGenesis()))
+
+	block0 := test.MakeGenesisBlock()
+	blocks := []*rpcs.EncodedBlockCert{{Block: block0}}
+	prev := block0.BlockHeader
+	for round := 1; round <= 3; round++ {
+		var block bookkeeping.Block
+		var err error
+		if round%2 == 1 {
+			txn := test.MakePaymentTxn(
+				1000, uint64(round), 0, 0, 0, 0, test.AccountA, test.AccountB, basics.Address{}, basics.Address{})
+			block, err = test.MakeBlockForTxns(prev, &txn)
+		} else {
+			block, err = test.MakeBlockForTxns(prev)
+		}
+		require.NoError(t, err)
+		blocks = append(blocks, &rpcs.EncodedBlockCert{Block: block})
+		prev = block.BlockHeader
+	}
+	imp := importer.NewImporter(db)
+	require.NoError(t, imp.ImportBlocks(blocks))
+	return db
+}
+
+func readLines(t *testing.T, name string) []map[string]interface{} {
+	f, err := os.Open(name)
+	require.NoError(t, err)
+	defer f.Close()
+
+	var lines []map[string]interface{}
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(nil, 1<<20)
+	for scanner.Scan() {
+		var line map[string]interface{}
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &line))
+		lines = append(lines, line)
+	}
+	require.NoError(t, scanner.Err())
+	return lines
+}
+
+func TestExportNDJSON(t *testing.T) {
+	dir, err := ioutil.TempDir("", "warehouse")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	db := makeDb(t)
+	opts := Options{
+		Dir:             dir,
+		Format:          FormatNDJSON,
+		Tables:          []string{TransactionsTable, AccountsTable},
+		PartitionRounds: 2,
+	}
+	manifest, err := Export(context.Background(), db, opts, log.New())
+	require.NoError(t, err)
+
+	assert.Equal(t, uint64(4), manifest.NextRound)
+	require.Len(t, manifest.Files, 3)
+	assert.Equal(t, "transactions/round_bucket=0/transactions-0-1.ndjson", manifest.Files[0].Path)
+	assert.Equal(t, "transactions/round_bucket=2/transactions-2-3.ndjson", manifest.Files[1].Path)
+	assert.Equal(t, "accounts/round=3/accounts-3.ndjson", manifest.Files[2].Path)
+
+	read, err := ReadManifest(dir)
+	require.NoError(t, err)
+	assert.Equal(t, manifest, read)
+
+	lines := readLines(t, filepath.Join(dir, filepath.FromSlash(manifest.Files[1].Path)))
+	require.Len(t, lines, 1)
+	assert.Equal(t, float64(3), lines[0]["round"])
+	assert.Equal(t, "pay", lines[0]["type"])
+	assert.Equal(t, test.AccountA.String(), lines[0]["sender"])
+	assert.Equal(t, test.AccountB.String(), lines[0]["receiver"])
+	assert.Equal(t, float64(3), lines[0]["amount"])
+	assert.Nil(t, lines[0]["asset_id"])
+
+	lines = readLines(t, filepath.Join(dir, filepath.FromSlash(manifest.Files[2].Path)))
+	assert.Equal(t, manifest.Files[2].Rows, uint64(len(lines)))
+
+	// Nothing new to export, the accounts are replaced.
+	manifest, err = Export(context.Background(), db, opts, log.New())
+	require.NoError(t, err)
+	assert.Equal(t, uint64(4), manifest.NextRound)
+	assert.Len(t, manifest.Files, 3)
+
+	// A directory has one format.
+	opts.Format = FormatAvro
+	_, err = Export(context.Background(), db, opts, log.New())
+	assert.Error(t, err)
+}
+
+func TestAvroWriter(t *testing.T) {
+	var buf bytes.Buffer
+	w, err := makeAvroWriter(&buf, table{
+		name: "test",
+		columns: []column{
+			{name: "round", kind: kindLong},
+			{name: "amount", kind: kindUint},
+			{name: "note", kind: kindBytes, nullable: true},
+			{name: "receiver", kind: kindString, nullable: true},
+			{name: "time", kind: kindTimestamp},
+		},
+	})
+	require.NoError(t, err)
+	when := time.Unix(100, 5000)
+	require.NoError(t, w.writeRow([]interface{}{int64(-3), uint64(1 << 62), nil, "b", when}))
+	require.NoError(t, w.writeRow([]interface{}{int64(4), uint64(0), []byte{1}, nil, when}))
+	assert.Error(t, w.writeRow([]interface{}{nil, uint64(0), nil, nil, when}))
+	require.NoError(t, w.close())
+
+	r, err := goavro.NewOCFReader(bytes.NewReader(buf.Bytes()))
+	require.NoError(t, err)
+	assert.Equal(t, goavro.CompressionDeflateLabel, r.CompressionName())
+	var schema map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(r.Codec().Schema()), &schema))
+	assert.Equal(t, "record", schema["type"])
+	assert.Len(t, schema["fields"], 5)
+
+	var records []map[string]interface{}
+	for r.Scan() {
+		record, err := r.Read()
+		require.NoError(t, err)
+		records = append(records, record.(map[string]interface{}))
+	}
+	require.NoError(t, r.Err())
+	require.Len(t, records, 2)
+
+	assert.Equal(t, int64(-3), records[0]["round"])
+	assert.Equal(t, big.NewRat(1<<62, 1), records[0]["amount"])
+	assert.Nil(t, records[0]["note"])
+	assert.Equal(t, map[string]interface{}{"string": "b"}, records[0]["receiver"])
+	assert.Equal(t, time.Unix(100, 5000).UTC(), records[0]["time"])
+	assert.Equal(t, big.NewRat(0, 1), records[1]["amount"])
+	assert.Equal(t, map[string]interface{}{"bytes": []byte{1}}, records[1]["note"])
+	assert.Nil(t, records[1]["receiver"])
+}